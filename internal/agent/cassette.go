@@ -0,0 +1,449 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// CassetteRecordEnv makes NewCassetteRunner record against the real runner
+// instead of replaying, e.g. JUGGLE_RECORD_CASSETTES=1 go test ./...
+const CassetteRecordEnv = "JUGGLE_RECORD_CASSETTES"
+
+// cassetteProjectDir stands in for the project directory in recorded prompts
+// and output, so fixtures replay in any temp directory
+const cassetteProjectDir = "$PROJECT_DIR"
+
+// Cassette is a recorded sequence of agent runs, stored as a JSON fixture
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction is one agent run: what the loop asked for, what the
+// agent returned, and what it changed in juggle's state while running
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+	Effects  *CassetteEffects `json:"effects,omitempty"`
+}
+
+// CassetteRequest is the recorded subset of RunOptions.
+// Env is left out since it may hold secrets.
+type CassetteRequest struct {
+	Prompt       string         `json:"prompt,omitempty"`
+	SystemPrompt string         `json:"system_prompt,omitempty"`
+	Mode         RunMode        `json:"mode"`
+	Permission   PermissionMode `json:"permission"`
+	Model        string         `json:"model,omitempty"`
+	Timeout      time.Duration  `json:"timeout,omitempty"`
+}
+
+// CassetteResponse is a serializable RunResult
+type CassetteResponse struct {
+	Output            string              `json:"output"`
+	ExitCode          int                 `json:"exit_code,omitempty"`
+	Complete          bool                `json:"complete,omitempty"`
+	Continue          bool                `json:"continue,omitempty"`
+	CommitMessage     string              `json:"commit_message,omitempty"`
+	Blocked           bool                `json:"blocked,omitempty"`
+	BlockedReason     string              `json:"blocked_reason,omitempty"`
+	TimedOut          bool                `json:"timed_out,omitempty"`
+	StoppedEarly      bool                `json:"stopped_early,omitempty"`
+	RateLimited       bool                `json:"rate_limited,omitempty"`
+	RetryAfter        time.Duration       `json:"retry_after,omitempty"`
+	OverloadExhausted bool                `json:"overload_exhausted,omitempty"`
+	InputTokens       int                 `json:"input_tokens,omitempty"`
+	OutputTokens      int                 `json:"output_tokens,omitempty"`
+	CostUSD           float64             `json:"cost_usd,omitempty"`
+	ToolEvents        []CassetteToolEvent `json:"tool_events,omitempty"`
+	Error             string              `json:"error,omitempty"`
+}
+
+// CassetteToolEvent is a serializable ToolEvent
+type CassetteToolEvent struct {
+	Name    string `json:"name"`
+	Input   string `json:"input,omitempty"`
+	IsError bool   `json:"is_error,omitempty"`
+}
+
+// CassetteEffects are the changes an agent made to juggle's own state during a
+// run. The agent loop checks them (progress updates, ball states) before it
+// accepts a signal, so replay applies them again. Changes to project files are
+// not recorded.
+type CassetteEffects struct {
+	Progress map[string]string    `json:"progress,omitempty"` // Session storage ID -> text appended to progress.txt
+	Balls    []CassetteBallEffect `json:"balls,omitempty"`
+}
+
+// CassetteBallEffect is a ball state change. Balls are matched by title since
+// IDs differ between the recording and the test project.
+type CassetteBallEffect struct {
+	Title         string            `json:"title"`
+	State         session.BallState `json:"state"`
+	BlockedReason string            `json:"blocked_reason,omitempty"`
+}
+
+// CassetteRunner records agent runs to a fixture file, or replays a recorded
+// fixture, so agent loop tests can cover realistic multi-iteration transcripts.
+type CassetteRunner struct {
+	Path       string    // Fixture file
+	ProjectDir string    // Project the agent runs in, for effects and path substitution
+	Inner      Runner    // Runner to record; nil when replaying
+	Cassette   *Cassette // Interactions recorded or being replayed
+	// Strict fails a replayed run whose model, mode or permission differ from the recording
+	Strict bool
+	// Calls records all calls made to Run
+	Calls []RunOptions
+	// NextIndex tracks which interaction to replay next
+	NextIndex int
+}
+
+// NewCassetteRunner replays the fixture at path, or records a new one through
+// inner when CassetteRecordEnv is set
+func NewCassetteRunner(path, projectDir string, inner Runner) (*CassetteRunner, error) {
+	if os.Getenv(CassetteRecordEnv) != "" {
+		return RecordCassette(path, projectDir, inner), nil
+	}
+	return ReplayCassette(path, projectDir)
+}
+
+// RecordCassette returns a runner that passes runs through to inner and saves
+// each one to path, overwriting any previous recording
+func RecordCassette(path, projectDir string, inner Runner) *CassetteRunner {
+	return &CassetteRunner{
+		Path:       path,
+		ProjectDir: projectDir,
+		Inner:      inner,
+		Cassette:   &Cassette{},
+		Calls:      make([]RunOptions, 0),
+	}
+}
+
+// ReplayCassette loads the fixture at path and returns a runner that replays it
+func ReplayCassette(path, projectDir string) (*CassetteRunner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &CassetteRunner{
+		Path:       path,
+		ProjectDir: projectDir,
+		Cassette:   &cassette,
+		Calls:      make([]RunOptions, 0),
+	}, nil
+}
+
+// Recording reports whether the runner records rather than replays
+func (c *CassetteRunner) Recording() bool {
+	return c.Inner != nil
+}
+
+// Remaining returns the number of recorded interactions not yet replayed
+func (c *CassetteRunner) Remaining() int {
+	if c.Recording() {
+		return 0
+	}
+	return len(c.Cassette.Interactions) - c.NextIndex
+}
+
+// Run records or replays one agent run
+func (c *CassetteRunner) Run(opts RunOptions) (*RunResult, error) {
+	c.Calls = append(c.Calls, opts)
+	if c.Recording() {
+		return c.record(opts)
+	}
+	return c.replay(opts)
+}
+
+// record runs the inner runner, captures its effects and saves the cassette
+func (c *CassetteRunner) record(opts RunOptions) (*RunResult, error) {
+	before := snapshotCassetteState(c.ProjectDir)
+	result, err := c.Inner.Run(opts)
+	if err != nil {
+		return result, err
+	}
+	after := snapshotCassetteState(c.ProjectDir)
+
+	c.Cassette.Interactions = append(c.Cassette.Interactions, CassetteInteraction{
+		Request:  c.newRequest(opts),
+		Response: c.newResponse(result),
+		Effects:  diffCassetteState(before, after),
+	})
+	if err := c.save(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// replay applies the next interaction's effects and returns its result
+func (c *CassetteRunner) replay(opts RunOptions) (*RunResult, error) {
+	if c.NextIndex >= len(c.Cassette.Interactions) {
+		// Return a default blocked result if the recording has run out, like MockRunner
+		return &RunResult{
+			Output:        "No more cassette interactions",
+			Blocked:       true,
+			BlockedReason: "CassetteRunner exhausted",
+		}, nil
+	}
+
+	interaction := c.Cassette.Interactions[c.NextIndex]
+	c.NextIndex++
+
+	if c.Strict {
+		req := interaction.Request
+		if req.Model != opts.Model || req.Mode != opts.Mode || req.Permission != opts.Permission {
+			return nil, fmt.Errorf("cassette %s interaction %d: recorded %s/%s/%s, got %s/%s/%s",
+				c.Path, c.NextIndex, req.Model, req.Mode, req.Permission, opts.Model, opts.Mode, opts.Permission)
+		}
+	}
+
+	if interaction.Effects != nil {
+		if err := c.applyEffects(*interaction.Effects); err != nil {
+			return nil, fmt.Errorf("cassette %s interaction %d: %w", c.Path, c.NextIndex, err)
+		}
+	}
+	return c.toResult(interaction.Response), nil
+}
+
+// save writes the cassette to its fixture file
+func (c *CassetteRunner) save() error {
+	data, err := json.MarshalIndent(c.Cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(c.Path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// scrub replaces the project directory with a placeholder
+func (c *CassetteRunner) scrub(s string) string {
+	if c.ProjectDir == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.ProjectDir, cassetteProjectDir)
+}
+
+// unscrub restores the project directory placeholder
+func (c *CassetteRunner) unscrub(s string) string {
+	if c.ProjectDir == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, cassetteProjectDir, c.ProjectDir)
+}
+
+func (c *CassetteRunner) newRequest(opts RunOptions) CassetteRequest {
+	return CassetteRequest{
+		Prompt:       c.scrub(opts.Prompt),
+		SystemPrompt: c.scrub(opts.SystemPrompt),
+		Mode:         opts.Mode,
+		Permission:   opts.Permission,
+		Model:        opts.Model,
+		Timeout:      opts.Timeout,
+	}
+}
+
+func (c *CassetteRunner) newResponse(result *RunResult) CassetteResponse {
+	resp := CassetteResponse{
+		Output:            c.scrub(result.Output),
+		ExitCode:          result.ExitCode,
+		Complete:          result.Complete,
+		Continue:          result.Continue,
+		CommitMessage:     result.CommitMessage,
+		Blocked:           result.Blocked,
+		BlockedReason:     result.BlockedReason,
+		TimedOut:          result.TimedOut,
+		StoppedEarly:      result.StoppedEarly,
+		RateLimited:       result.RateLimited,
+		RetryAfter:        result.RetryAfter,
+		OverloadExhausted: result.OverloadExhausted,
+		InputTokens:       result.InputTokens,
+		OutputTokens:      result.OutputTokens,
+		CostUSD:           result.CostUSD,
+	}
+	for _, event := range result.ToolEvents {
+		resp.ToolEvents = append(resp.ToolEvents, CassetteToolEvent{
+			Name:    event.Name,
+			Input:   c.scrub(event.Input),
+			IsError: event.IsError,
+		})
+	}
+	if result.Error != nil {
+		resp.Error = c.scrub(result.Error.Error())
+	}
+	return resp
+}
+
+func (c *CassetteRunner) toResult(resp CassetteResponse) *RunResult {
+	result := &RunResult{
+		Output:            c.unscrub(resp.Output),
+		ExitCode:          resp.ExitCode,
+		Complete:          resp.Complete,
+		Continue:          resp.Continue,
+		CommitMessage:     resp.CommitMessage,
+		Blocked:           resp.Blocked,
+		BlockedReason:     resp.BlockedReason,
+		TimedOut:          resp.TimedOut,
+		StoppedEarly:      resp.StoppedEarly,
+		RateLimited:       resp.RateLimited,
+		RetryAfter:        resp.RetryAfter,
+		OverloadExhausted: resp.OverloadExhausted,
+		InputTokens:       resp.InputTokens,
+		OutputTokens:      resp.OutputTokens,
+		CostUSD:           resp.CostUSD,
+	}
+	for _, event := range resp.ToolEvents {
+		result.ToolEvents = append(result.ToolEvents, provider.ToolEvent{
+			Name:    event.Name,
+			Input:   c.unscrub(event.Input),
+			IsError: event.IsError,
+		})
+	}
+	if resp.Error != "" {
+		result.Error = errors.New(c.unscrub(resp.Error))
+	}
+	return result
+}
+
+// applyEffects replays recorded progress updates and ball state changes
+func (c *CassetteRunner) applyEffects(effects CassetteEffects) error {
+	if len(effects.Progress) > 0 {
+		sessionStore, err := session.NewSessionStore(c.ProjectDir)
+		if err != nil {
+			return err
+		}
+		for id, text := range effects.Progress {
+			if err := sessionStore.AppendProgress(id, text); err != nil {
+				return fmt.Errorf("failed to append progress for %s: %w", id, err)
+			}
+		}
+	}
+
+	if len(effects.Balls) == 0 {
+		return nil
+	}
+	store, err := session.NewStore(c.ProjectDir)
+	if err != nil {
+		return err
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return err
+	}
+	for _, effect := range effects.Balls {
+		ball := findBallByTitle(balls, effect.Title)
+		if ball == nil {
+			return fmt.Errorf("no ball titled %q", effect.Title)
+		}
+		if effect.State == session.StateComplete {
+			ball.MarkComplete("")
+		} else {
+			ball.ForceSetState(effect.State)
+			ball.BlockedReason = effect.BlockedReason
+		}
+		if err := store.UpdateBall(ball); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findBallByTitle(balls []*session.Ball, title string) *session.Ball {
+	for _, ball := range balls {
+		if ball.Title == title {
+			return ball
+		}
+	}
+	return nil
+}
+
+// cassetteState is the juggle state an agent run can change
+type cassetteState struct {
+	progress map[string]string             // Session storage ID -> progress.txt contents
+	balls    map[string]CassetteBallEffect // Title -> state
+}
+
+// snapshotCassetteState reads every session's progress and every ball's state.
+// Unreadable state is treated as empty so recording never fails a run.
+func snapshotCassetteState(projectDir string) cassetteState {
+	state := cassetteState{
+		progress: make(map[string]string),
+		balls:    make(map[string]CassetteBallEffect),
+	}
+
+	sessionsDir := filepath.Join(projectDir, ".juggle", "sessions")
+	if entries, err := os.ReadDir(sessionsDir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(sessionsDir, entry.Name(), "progress.txt"))
+			if err == nil {
+				state.progress[entry.Name()] = string(data)
+			}
+		}
+	}
+
+	if store, err := session.NewStore(projectDir); err == nil {
+		if balls, err := store.LoadBalls(); err == nil {
+			for _, ball := range balls {
+				state.balls[ball.Title] = CassetteBallEffect{
+					Title:         ball.Title,
+					State:         ball.State,
+					BlockedReason: ball.BlockedReason,
+				}
+			}
+		}
+	}
+	return state
+}
+
+// diffCassetteState returns what changed between two snapshots, or nil if
+// nothing did. Progress is only recorded when appended to; balls that
+// disappeared were archived as complete.
+func diffCassetteState(before, after cassetteState) *CassetteEffects {
+	var effects CassetteEffects
+	for id, text := range after.progress {
+		prev := before.progress[id]
+		if len(text) > len(prev) && strings.HasPrefix(text, prev) {
+			if effects.Progress == nil {
+				effects.Progress = make(map[string]string)
+			}
+			effects.Progress[id] = text[len(prev):]
+		}
+	}
+
+	for title, prev := range before.balls {
+		next, ok := after.balls[title]
+		if !ok {
+			next = CassetteBallEffect{Title: title, State: session.StateComplete}
+		}
+		if next != prev {
+			effects.Balls = append(effects.Balls, next)
+		}
+	}
+	sort.Slice(effects.Balls, func(i, j int) bool {
+		return effects.Balls[i].Title < effects.Balls[j].Title
+	})
+
+	if len(effects.Progress) == 0 && len(effects.Balls) == 0 {
+		return nil
+	}
+	return &effects
+}
@@ -0,0 +1,247 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// setupCassetteProject creates a project with a session and one in-progress ball
+func setupCassetteProject(t *testing.T) (string, *session.Ball) {
+	t.Helper()
+	projectDir := t.TempDir()
+
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.CreateSession("s1", "Cassette session"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ball, err := session.NewBall(projectDir, "Write parser", session.PriorityMedium)
+	if err != nil {
+		t.Fatalf("failed to create ball: %v", err)
+	}
+	ball.Tags = []string{"s1"}
+	ball.ForceSetState(session.StateInProgress)
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("failed to save ball: %v", err)
+	}
+	return projectDir, ball
+}
+
+// effectRunner completes the project's ball and logs progress, like a real agent would
+type effectRunner struct {
+	projectDir string
+	ballID     string
+}
+
+func (r *effectRunner) Run(opts RunOptions) (*RunResult, error) {
+	store, err := session.NewStore(r.projectDir)
+	if err != nil {
+		return nil, err
+	}
+	ball, err := store.GetBallByID(r.ballID)
+	if err != nil {
+		return nil, err
+	}
+	ball.MarkComplete("done")
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, err
+	}
+
+	sessionStore, err := session.NewSessionStore(r.projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("s1", "[ITER 1] Wrote parser\n"); err != nil {
+		return nil, err
+	}
+
+	return &RunResult{
+		Output:        "Edited " + filepath.Join(r.projectDir, "parser.go") + "\n<promise>COMPLETE: feat: add parser</promise>",
+		Complete:      true,
+		CommitMessage: "feat: add parser",
+		InputTokens:   1500,
+		OutputTokens:  200,
+		CostUSD:       0.01,
+		RetryAfter:    time.Minute,
+		ToolEvents:    []provider.ToolEvent{{Name: "Edit", Input: filepath.Join(r.projectDir, "parser.go")}},
+	}, nil
+}
+
+func TestCassetteRunner_RecordAndReplay(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "cassettes", "parser.json")
+	opts := RunOptions{Prompt: "work", Mode: ModeHeadless, Permission: PermissionAcceptEdits, Model: "sonnet"}
+
+	// Record against a runner with real side effects
+	recordDir, recordBall := setupCassetteProject(t)
+	recorder := RecordCassette(cassettePath, recordDir, &effectRunner{projectDir: recordDir, ballID: recordBall.ID})
+	if !recorder.Recording() {
+		t.Fatal("expected recorder to be recording")
+	}
+	if _, err := recorder.Run(opts); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	data, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("cassette not written: %v", err)
+	}
+	if strings.Contains(string(data), recordDir) {
+		t.Error("expected project directory to be scrubbed from cassette")
+	}
+
+	// Replay into a fresh project with a different ball ID
+	replayDir, replayBall := setupCassetteProject(t)
+	player, err := ReplayCassette(cassettePath, replayDir)
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+	if player.Remaining() != 1 {
+		t.Fatalf("expected 1 interaction, got %d", player.Remaining())
+	}
+
+	result, err := player.Run(opts)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if !result.Complete || result.CommitMessage != "feat: add parser" {
+		t.Errorf("expected COMPLETE with commit message, got %+v", result)
+	}
+	if result.InputTokens != 1500 || result.OutputTokens != 200 || result.CostUSD != 0.01 || result.RetryAfter != time.Minute {
+		t.Errorf("expected usage and retry-after replayed, got %+v", result)
+	}
+	wantPath := filepath.Join(replayDir, "parser.go")
+	if !strings.Contains(result.Output, wantPath) {
+		t.Errorf("expected output paths rewritten to %s, got %q", wantPath, result.Output)
+	}
+	if len(result.ToolEvents) != 1 || result.ToolEvents[0].Input != wantPath {
+		t.Errorf("expected tool event replayed, got %+v", result.ToolEvents)
+	}
+
+	// Effects are applied to the replay project
+	store, err := session.NewStore(replayDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ball, err := store.GetBallByID(replayBall.ID)
+	if err != nil {
+		t.Fatalf("failed to load ball: %v", err)
+	}
+	if ball.State != session.StateComplete {
+		t.Errorf("expected ball completed by replay, got %s", ball.State)
+	}
+	sessionStore, err := session.NewSessionStore(replayDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("s1")
+	if err != nil || !strings.Contains(progress, "[ITER 1] Wrote parser") {
+		t.Errorf("expected progress replayed, got %q (err: %v)", progress, err)
+	}
+
+	// Running past the end blocks like MockRunner
+	result, err = player.Run(opts)
+	if err != nil || !result.Blocked || result.BlockedReason != "CassetteRunner exhausted" {
+		t.Errorf("expected exhausted cassette to block, got %+v (err: %v)", result, err)
+	}
+	if len(player.Calls) != 2 {
+		t.Errorf("expected 2 recorded calls, got %d", len(player.Calls))
+	}
+}
+
+func TestCassetteRunner_ReplayErrorAndStrict(t *testing.T) {
+	projectDir, _ := setupCassetteProject(t)
+	player := &CassetteRunner{
+		ProjectDir: projectDir,
+		Strict:     true,
+		Cassette: &Cassette{Interactions: []CassetteInteraction{
+			{
+				Request:  CassetteRequest{Mode: ModeHeadless, Permission: PermissionAcceptEdits, Model: "opus"},
+				Response: CassetteResponse{Output: "boom", ExitCode: 1, Error: "agent crashed"},
+			},
+			{
+				Request:  CassetteRequest{Mode: ModeHeadless, Permission: PermissionAcceptEdits, Model: "opus"},
+				Response: CassetteResponse{Output: "ok"},
+			},
+		}},
+	}
+
+	result, err := player.Run(RunOptions{Mode: ModeHeadless, Permission: PermissionAcceptEdits, Model: "opus"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Error == nil || result.Error.Error() != "agent crashed" || result.ExitCode != 1 {
+		t.Errorf("expected recorded error replayed, got %+v", result)
+	}
+
+	if _, err := player.Run(RunOptions{Mode: ModeHeadless, Permission: PermissionAcceptEdits, Model: "haiku"}); err == nil {
+		t.Error("expected strict replay to reject a different model")
+	}
+}
+
+func TestNewCassetteRunner_ModeFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	t.Setenv(CassetteRecordEnv, "")
+	if _, err := NewCassetteRunner(path, t.TempDir(), NewMockRunner()); err == nil {
+		t.Error("expected replay of a missing cassette to fail")
+	}
+
+	t.Setenv(CassetteRecordEnv, "1")
+	runner, err := NewCassetteRunner(path, t.TempDir(), NewMockRunner())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !runner.Recording() {
+		t.Error("expected recording when the env var is set")
+	}
+}
+
+func TestDiffCassetteState(t *testing.T) {
+	before := cassetteState{
+		progress: map[string]string{"s1": "a\n", "s2": "x\n"},
+		balls: map[string]CassetteBallEffect{
+			"Archived": {Title: "Archived", State: session.StateInProgress},
+			"Blocked":  {Title: "Blocked", State: session.StateInProgress},
+			"Same":     {Title: "Same", State: session.StatePending},
+		},
+	}
+	after := cassetteState{
+		progress: map[string]string{"s1": "a\nb\n", "s2": "x\n", "s3": "new\n"},
+		balls: map[string]CassetteBallEffect{
+			"Blocked": {Title: "Blocked", State: session.StateBlocked, BlockedReason: "needs key"},
+			"Same":    {Title: "Same", State: session.StatePending},
+		},
+	}
+
+	effects := diffCassetteState(before, after)
+	if effects == nil {
+		t.Fatal("expected effects")
+	}
+	if len(effects.Progress) != 2 || effects.Progress["s1"] != "b\n" || effects.Progress["s3"] != "new\n" {
+		t.Errorf("unexpected progress effects: %+v", effects.Progress)
+	}
+	want := []CassetteBallEffect{
+		{Title: "Archived", State: session.StateComplete},
+		{Title: "Blocked", State: session.StateBlocked, BlockedReason: "needs key"},
+	}
+	if len(effects.Balls) != len(want) || effects.Balls[0] != want[0] || effects.Balls[1] != want[1] {
+		t.Errorf("unexpected ball effects: %+v", effects.Balls)
+	}
+
+	if diffCassetteState(after, after) != nil {
+		t.Error("expected no effects for identical state")
+	}
+}
@@ -0,0 +1,478 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ohare93/juggle/internal/process"
+	"github.com/ohare93/juggle/pkg/daemonapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// apiTokenKey is the metadata key clients send the API token in
+const apiTokenKey = "juggle-token"
+
+// ErrNoAPI is returned by DialAPI when the session's loop doesn't serve the API
+var ErrNoAPI = errors.New("the agent loop does not serve the daemon API")
+
+// apiLoop is an agent loop running in this process, as the API sees it
+type apiLoop struct {
+	projectDir string
+	sessionID  string
+	control    *ControlReceiver
+	last       *State // Last state written, nil until the first write
+	watchers   map[chan *State]struct{}
+}
+
+// liveLoops are the loops in this process with a control receiver, keyed by
+// session directory
+var liveLoops = struct {
+	sync.Mutex
+	m map[string]*apiLoop
+}{m: make(map[string]*apiLoop)}
+
+func liveLoopKey(projectDir, sessionID string) string {
+	return filepath.Clean(sessionDir(projectDir, sessionID))
+}
+
+// registerLoop makes a loop's state and control available over the API
+func registerLoop(r *ControlReceiver) {
+	liveLoops.Lock()
+	defer liveLoops.Unlock()
+	liveLoops.m[liveLoopKey(r.projectDir, r.sessionID)] = &apiLoop{
+		projectDir: r.projectDir,
+		sessionID:  r.sessionID,
+		control:    r,
+		watchers:   make(map[chan *State]struct{}),
+	}
+}
+
+// unregisterLoop removes a loop from the API and ends its state watches
+func unregisterLoop(r *ControlReceiver) {
+	liveLoops.Lock()
+	defer liveLoops.Unlock()
+	key := liveLoopKey(r.projectDir, r.sessionID)
+	l, ok := liveLoops.m[key]
+	if !ok || l.control != r {
+		return
+	}
+	for ch := range l.watchers {
+		close(ch)
+	}
+	delete(liveLoops.m, key)
+}
+
+// publishState passes a state written by a loop in this process on to its watchers
+func publishState(projectDir, sessionID string, state *State) {
+	liveLoops.Lock()
+	defer liveLoops.Unlock()
+	l, ok := liveLoops.m[liveLoopKey(projectDir, sessionID)]
+	if !ok {
+		return
+	}
+	snapshot := *state
+	l.last = &snapshot
+	for ch := range l.watchers {
+		// Watchers only need the latest state; replace one they haven't read yet
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- &snapshot
+	}
+}
+
+// loopState returns a loop's last state, from its state file if it hasn't
+// written one since it registered
+func loopState(l *apiLoop) *State {
+	if l.last != nil {
+		return l.last
+	}
+	state, err := ReadStateFile(l.projectDir, l.sessionID)
+	if err != nil {
+		return &State{Version: StateVersion, Running: true}
+	}
+	return state
+}
+
+// findLoop returns the loop running a session in this process
+func findLoop(projectDir, sessionID string) (*apiLoop, error) {
+	l, ok := liveLoops.m[liveLoopKey(projectDir, sessionID)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no agent loop is running for session %s in %s", sessionID, projectDir)
+	}
+	return l, nil
+}
+
+// apiServer serves the daemon API for the loops running in this process
+type apiServer struct {
+	daemonapi.UnimplementedDaemonServer
+}
+
+func (apiServer) ListLoops(ctx context.Context, req *daemonapi.ListLoopsRequest) (*daemonapi.ListLoopsResponse, error) {
+	liveLoops.Lock()
+	defer liveLoops.Unlock()
+	resp := &daemonapi.ListLoopsResponse{}
+	for _, l := range liveLoops.m {
+		resp.Loops = append(resp.Loops, &daemonapi.Loop{
+			ProjectDir: l.projectDir,
+			SessionId:  l.sessionID,
+			State:      stateToProto(loopState(l)),
+		})
+	}
+	sort.Slice(resp.Loops, func(i, j int) bool {
+		if resp.Loops[i].ProjectDir != resp.Loops[j].ProjectDir {
+			return resp.Loops[i].ProjectDir < resp.Loops[j].ProjectDir
+		}
+		return resp.Loops[i].SessionId < resp.Loops[j].SessionId
+	})
+	return resp, nil
+}
+
+func (apiServer) GetState(ctx context.Context, req *daemonapi.GetStateRequest) (*daemonapi.State, error) {
+	liveLoops.Lock()
+	defer liveLoops.Unlock()
+	l, err := findLoop(req.GetProjectDir(), req.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+	return stateToProto(loopState(l)), nil
+}
+
+func (apiServer) WatchState(req *daemonapi.GetStateRequest, stream daemonapi.Daemon_WatchStateServer) error {
+	liveLoops.Lock()
+	l, err := findLoop(req.GetProjectDir(), req.GetSessionId())
+	if err != nil {
+		liveLoops.Unlock()
+		return err
+	}
+	ch := make(chan *State, 1)
+	ch <- loopState(l)
+	l.watchers[ch] = struct{}{}
+	liveLoops.Unlock()
+
+	defer func() {
+		liveLoops.Lock()
+		delete(l.watchers, ch)
+		liveLoops.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case state, ok := <-ch:
+			if !ok {
+				return nil // The loop ended
+			}
+			if err := stream.Send(stateToProto(state)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (apiServer) SendControl(ctx context.Context, req *daemonapi.SendControlRequest) (*daemonapi.SendControlResponse, error) {
+	liveLoops.Lock()
+	l, err := findLoop(req.GetProjectDir(), req.GetSessionId())
+	liveLoops.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	err = l.control.deliver(&Control{
+		Command:   req.GetCommand(),
+		Args:      req.GetArgs(),
+		Timestamp: time.Now(),
+	})
+	switch {
+	case errors.Is(err, errUnknownCommand):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errTooManyCommands):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &daemonapi.SendControlResponse{}, nil
+}
+
+// api is the process's API server, started by the first loop that asks for it
+var api struct {
+	sync.Mutex
+	server   *grpc.Server
+	listener net.Listener
+	token    string
+}
+
+// APIOptions sets where the daemon API listens
+type APIOptions struct {
+	Addr  string // Loopback address to listen on; empty picks a free port
+	Token string // Token clients must send; empty generates one
+}
+
+// ErrNotLoopback is returned by StartAPIWithOptions for an address other
+// machines could reach. Remote monitors reach the API through SSH port
+// forwarding instead.
+var ErrNotLoopback = errors.New("the daemon API only listens on a loopback address")
+
+// StartAPI serves the daemon API on localhost for the loops running in this
+// process, unless it already runs. Returns the address and the token clients
+// must send; loops record both in their PID file for DialAPI.
+func StartAPI() (addr, token string, err error) {
+	return StartAPIWithOptions(APIOptions{})
+}
+
+// StartAPIWithOptions is StartAPI listening on a fixed address and with a
+// fixed token, so a monitor on another machine can reach the API through an
+// SSH tunnel. If the API already runs it is returned as is.
+func StartAPIWithOptions(opts APIOptions) (addr, token string, err error) {
+	api.Lock()
+	defer api.Unlock()
+	if api.server != nil {
+		return api.listener.Addr().String(), api.token, nil
+	}
+
+	listenAddr := "127.0.0.1:0"
+	if opts.Addr != "" {
+		if err := checkLoopback(opts.Addr); err != nil {
+			return "", "", err
+		}
+		listenAddr = opts.Addr
+	}
+	token = opts.Token
+	if token == "" {
+		if token, err = NewAPIToken(); err != nil {
+			return "", "", err
+		}
+	}
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to listen for the daemon API: %w", err)
+	}
+	api.token = token
+	api.listener = listener
+	api.server = grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := checkAPIToken(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := checkAPIToken(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	daemonapi.RegisterDaemonServer(api.server, apiServer{})
+	go api.server.Serve(listener)
+	return listener.Addr().String(), api.token, nil
+}
+
+// NewAPIToken generates a random daemon API token
+func NewAPIToken() (string, error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// LoadOrCreateAPIToken returns the token stored at path, generating and
+// storing one if there is none yet. Unlike the per-process tokens of daemons
+// on a random port it stays the same across restarts, so a remote monitor
+// can keep using it.
+func LoadOrCreateAPIToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil && strings.TrimSpace(string(data)) != "" {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read daemon API token: %w", err)
+	}
+
+	token, err := NewAPIToken()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	// Only the owner may read the token
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write daemon API token: %w", err)
+	}
+	return token, nil
+}
+
+// checkLoopback returns ErrNotLoopback unless addr is host:port with a
+// loopback host
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid daemon API address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("%w: %s", ErrNotLoopback, addr)
+	}
+	return nil
+}
+
+// stopAPI stops the API server; the next StartAPI starts a new one
+func stopAPI() {
+	api.Lock()
+	defer api.Unlock()
+	if api.server != nil {
+		api.server.Stop()
+		api.server = nil
+	}
+}
+
+// checkAPIToken rejects calls without this process's token
+func checkAPIToken(ctx context.Context) error {
+	api.Lock()
+	want := api.token
+	api.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, token := range md.Get(apiTokenKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or wrong API token")
+}
+
+// APIClient is a connection to the daemon API of the process running a
+// session's loop
+type APIClient struct {
+	daemonapi.DaemonClient
+	conn *grpc.ClientConn
+}
+
+// DialAPI connects to the daemon API of the process running a session's loop.
+// Returns ErrNoAPI if no loop is running for the session, or it doesn't serve
+// the API.
+func DialAPI(projectDir, sessionID string) (*APIClient, error) {
+	info, err := ReadPIDFile(projectDir, sessionID)
+	if err != nil || info.APIAddr == "" || !process.RunningSince(info.PID, info.StartedAt) {
+		return nil, ErrNoAPI
+	}
+	conn, err := grpc.NewClient(info.APIAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(apiToken(info.APIToken)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the daemon API: %w", err)
+	}
+	return &APIClient{DaemonClient: daemonapi.NewDaemonClient(conn), conn: conn}, nil
+}
+
+// DialRemoteAPI connects to a daemon API at addr, typically the local end
+// of an SSH tunnel to another machine, sending token with every call. Unlike
+// DialAPI no PID file is read, so the connection is only tried on the
+// first call.
+func DialRemoteAPI(addr, token string) (*APIClient, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(apiToken(token)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the daemon API at %s: %w", addr, err)
+	}
+	return &APIClient{DaemonClient: daemonapi.NewDaemonClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *APIClient) Close() error {
+	return c.conn.Close()
+}
+
+// apiToken sends the API token with every call
+type apiToken string
+
+func (t apiToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{apiTokenKey: string(t)}, nil
+}
+
+func (t apiToken) RequireTransportSecurity() bool {
+	return false // Localhost only, or through an SSH tunnel
+}
+
+// stateToProto converts a state for the API
+func stateToProto(s *State) *daemonapi.State {
+	return &daemonapi.State{
+		Running:          s.Running,
+		Paused:           s.Paused,
+		CurrentBallId:    s.CurrentBallID,
+		CurrentBallTitle: s.CurrentBallTitle,
+		Iteration:        int32(s.Iteration),
+		MaxIterations:    int32(s.MaxIterations),
+		FilesChanged:     int32(s.FilesChanged),
+		AcsComplete:      int32(s.ACsComplete),
+		AcsTotal:         int32(s.ACsTotal),
+		Model:            s.Model,
+		Provider:         s.Provider,
+		Status:           s.Status,
+		LastUpdated:      timestampOrNil(s.LastUpdated),
+		StartedAt:        timestampOrNil(s.StartedAt),
+		EstimatedEnd:     timestampOrNil(s.EstimatedEnd),
+		Version:          int32(s.Version),
+	}
+}
+
+// timestampOrNil leaves zero times unset
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timeOrZero is the inverse of timestampOrNil
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// StateFromProto converts a state received from the API
+func StateFromProto(p *daemonapi.State) *State {
+	return &State{
+		Running:          p.GetRunning(),
+		Paused:           p.GetPaused(),
+		CurrentBallID:    p.GetCurrentBallId(),
+		CurrentBallTitle: p.GetCurrentBallTitle(),
+		Iteration:        int(p.GetIteration()),
+		MaxIterations:    int(p.GetMaxIterations()),
+		FilesChanged:     int(p.GetFilesChanged()),
+		ACsComplete:      int(p.GetAcsComplete()),
+		ACsTotal:         int(p.GetAcsTotal()),
+		Model:            p.GetModel(),
+		Provider:         p.GetProvider(),
+		Status:           p.GetStatus(),
+		LastUpdated:      timeOrZero(p.GetLastUpdated()),
+		StartedAt:        timeOrZero(p.GetStartedAt()),
+		EstimatedEnd:     timeOrZero(p.GetEstimatedEnd()),
+		Version:          int(p.GetVersion()),
+	}
+}
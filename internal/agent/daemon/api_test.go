@@ -0,0 +1,215 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/pkg/daemonapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startAPILoop serves the API for a loop on the session, the way RunAgentLoop
+// does in daemon mode, and connects a client to it
+func startAPILoop(t *testing.T, dir, sessionID string) (*ControlReceiver, *APIClient) {
+	t.Helper()
+	addr, token, err := StartAPI()
+	if err != nil {
+		t.Fatalf("StartAPI failed: %v", err)
+	}
+	t.Cleanup(stopAPI)
+	info := &Info{PID: os.Getpid(), SessionID: sessionID, ProjectDir: dir, APIAddr: addr, APIToken: token}
+	if err := WritePIDFile(dir, sessionID, info); err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	control := ListenControl(dir, sessionID)
+	t.Cleanup(control.Close)
+
+	client, err := DialAPI(dir, sessionID)
+	if err != nil {
+		t.Fatalf("DialAPI failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return control, client
+}
+
+func TestAPIState(t *testing.T) {
+	dir := socketTempDir(t)
+	_, client := startAPILoop(t, dir, "s")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	started := time.Now().Truncate(time.Second)
+	if err := WriteStateFile(dir, "s", &State{Running: true, Iteration: 1, MaxIterations: 5, Model: "sonnet", StartedAt: started}); err != nil {
+		t.Fatalf("WriteStateFile failed: %v", err)
+	}
+	state, err := client.GetState(ctx, &daemonapi.GetStateRequest{ProjectDir: dir, SessionId: "s"})
+	if err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+	got := StateFromProto(state)
+	if !got.Running || got.Iteration != 1 || got.Model != "sonnet" || !got.StartedAt.Equal(started) || !got.EstimatedEnd.IsZero() || got.Version != StateVersion {
+		t.Errorf("Unexpected state: %+v", got)
+	}
+
+	loops, err := client.ListLoops(ctx, &daemonapi.ListLoopsRequest{})
+	if err != nil {
+		t.Fatalf("ListLoops failed: %v", err)
+	}
+	if len(loops.GetLoops()) != 1 || loops.GetLoops()[0].GetSessionId() != "s" {
+		t.Errorf("Expected the one loop, got %v", loops.GetLoops())
+	}
+
+	_, err = client.GetState(ctx, &daemonapi.GetStateRequest{ProjectDir: dir, SessionId: "other"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a session without a loop, got %v", err)
+	}
+}
+
+func TestAPIWatchState(t *testing.T) {
+	dir := socketTempDir(t)
+	control, client := startAPILoop(t, dir, "s")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = WriteStateFile(dir, "s", &State{Running: true, Iteration: 1})
+	stream, err := client.WatchState(ctx, &daemonapi.GetStateRequest{ProjectDir: dir, SessionId: "s"})
+	if err != nil {
+		t.Fatalf("WatchState failed: %v", err)
+	}
+	if state, err := stream.Recv(); err != nil || state.GetIteration() != 1 {
+		t.Fatalf("Expected the current state first, got %v, %v", state, err)
+	}
+
+	_ = WriteStateFile(dir, "s", &State{Running: true, Iteration: 2})
+	if state, err := stream.Recv(); err != nil || state.GetIteration() != 2 {
+		t.Fatalf("Expected the update, got %v, %v", state, err)
+	}
+
+	// The loop writes its final state, then ends
+	_ = WriteStateFile(dir, "s", &State{Running: false, Iteration: 2, Status: "Complete"})
+	control.Close()
+	if state, err := stream.Recv(); err != nil || state.GetRunning() || state.GetStatus() != "Complete" {
+		t.Fatalf("Expected the final state, got %v, %v", state, err)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("Expected the stream to end with the loop")
+	}
+}
+
+func TestAPISendControl(t *testing.T) {
+	dir := socketTempDir(t)
+	control, client := startAPILoop(t, dir, "s")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := &daemonapi.SendControlRequest{ProjectDir: dir, SessionId: "s", Command: CmdChangeModel, Args: "haiku"}
+	if _, err := client.SendControl(ctx, req); err != nil {
+		t.Fatalf("SendControl failed: %v", err)
+	}
+	if ctrl := control.Next(); ctrl == nil || ctrl.Command != CmdChangeModel || ctrl.Args != "haiku" {
+		t.Errorf("Expected change_model haiku, got %+v", ctrl)
+	}
+
+	req.Command = "explode"
+	if _, err := client.SendControl(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown command, got %v", err)
+	}
+}
+
+func TestAPIRequiresToken(t *testing.T) {
+	dir := socketTempDir(t)
+	startAPILoop(t, dir, "s")
+
+	info, err := ReadPIDFile(dir, "s")
+	if err != nil {
+		t.Fatalf("ReadPIDFile failed: %v", err)
+	}
+	info.APIToken = "wrong"
+	if err := WritePIDFile(dir, "s", info); err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	client, err := DialAPI(dir, "s")
+	if err != nil {
+		t.Fatalf("DialAPI failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.ListLoops(ctx, &daemonapi.ListLoopsRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with a wrong token, got %v", err)
+	}
+}
+
+func TestDialAPIWithoutAPI(t *testing.T) {
+	dir := socketTempDir(t)
+	if _, err := DialAPI(dir, "s"); err != ErrNoAPI {
+		t.Errorf("Expected ErrNoAPI without a running loop, got %v", err)
+	}
+
+	// A loop started by an older juggle has no API address
+	if err := WritePIDFile(dir, "s", &Info{PID: os.Getpid(), SessionID: "s"}); err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	if _, err := DialAPI(dir, "s"); err != ErrNoAPI {
+		t.Errorf("Expected ErrNoAPI without an API address, got %v", err)
+	}
+}
+
+func TestStartAPIWithOptions(t *testing.T) {
+	if _, _, err := StartAPIWithOptions(APIOptions{Addr: "0.0.0.0:0"}); !errors.Is(err, ErrNotLoopback) {
+		t.Fatalf("Expected ErrNotLoopback for a public address, got %v", err)
+	}
+
+	addr, token, err := StartAPIWithOptions(APIOptions{Addr: "127.0.0.1:0", Token: "fixed"})
+	if err != nil {
+		t.Fatalf("StartAPIWithOptions failed: %v", err)
+	}
+	t.Cleanup(stopAPI)
+	if token != "fixed" {
+		t.Errorf("Expected the given token, got %q", token)
+	}
+	dir := socketTempDir(t)
+	control := ListenControl(dir, "s")
+	t.Cleanup(control.Close)
+
+	// A remote monitor knows only the address and token, not the PID file
+	client, err := DialRemoteAPI(addr, "fixed")
+	if err != nil {
+		t.Fatalf("DialRemoteAPI failed: %v", err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	loops, err := client.ListLoops(ctx, &daemonapi.ListLoopsRequest{})
+	if err != nil || len(loops.GetLoops()) != 1 {
+		t.Fatalf("Expected the one loop, got %v, %v", loops.GetLoops(), err)
+	}
+}
+
+func TestLoadOrCreateAPIToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".juggle", "daemon-api-token")
+	token, err := LoadOrCreateAPIToken(path)
+	if err != nil || len(token) != 32 {
+		t.Fatalf("Expected a new 32-character token, got %q (err %v)", token, err)
+	}
+	again, err := LoadOrCreateAPIToken(path)
+	if err != nil || again != token {
+		t.Errorf("Expected the stored token %q, got %q (err %v)", token, again, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the token file to exist: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		t.Errorf("Expected the token file to be readable by its owner only, got %v", info.Mode().Perm())
+	}
+}
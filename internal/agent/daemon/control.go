@@ -0,0 +1,258 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	socketFileName = "agent.sock"
+
+	// socketDialTimeout bounds connecting to a daemon's control socket; a
+	// daemon that doesn't answer in time gets the control file instead
+	socketDialTimeout = time.Second
+
+	// socketReplyTimeout bounds waiting for the daemon to acknowledge a command
+	socketReplyTimeout = 5 * time.Second
+
+	// controlFilePollInterval is how often a loop without a control socket
+	// checks the control file while it waits
+	controlFilePollInterval = 500 * time.Millisecond
+
+	// controlFileFallbackInterval is how often a loop with a control socket
+	// still checks the control file while it waits, for senders that couldn't
+	// reach the socket
+	controlFileFallbackInterval = 5 * time.Second
+)
+
+// controlCommands are the commands the control socket accepts
+var controlCommands = map[string]bool{
+	CmdPause:          true,
+	CmdResume:         true,
+	CmdCancel:         true,
+	CmdSkipBall:       true,
+	CmdChangeModel:    true,
+	CmdChangeProvider: true,
+	CmdTakeover:       true,
+}
+
+// ControlResponse is the daemon's reply to a command sent over its control socket
+type ControlResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// GetSocketPath returns the path to the control socket for a session
+func GetSocketPath(projectDir, sessionID string) string {
+	return filepath.Join(sessionDir(projectDir, sessionID), socketFileName)
+}
+
+// ControlReceiver delivers control commands to a running loop. It listens on
+// the session's Unix socket, so commands arrive as soon as they are sent, and
+// also reads the control file, which senders fall back to when the socket
+// can't be reached (no socket support, or a path too long for one).
+type ControlReceiver struct {
+	projectDir string
+	sessionID  string
+	listener   net.Listener // nil when the socket couldn't be created
+	commands   chan *Control
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// ListenControl starts receiving control commands for a session, over its
+// socket and the daemon API if this process serves it. Failing to create the
+// socket isn't an error; the receiver then reads the control file only.
+func ListenControl(projectDir, sessionID string) *ControlReceiver {
+	r := &ControlReceiver{
+		projectDir: projectDir,
+		sessionID:  sessionID,
+		commands:   make(chan *Control, 16),
+		done:       make(chan struct{}),
+	}
+
+	registerLoop(r)
+
+	path := GetSocketPath(projectDir, sessionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return r
+	}
+	// The loop holds the session lock, so a socket left here belongs to a dead daemon
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return r
+	}
+	r.listener = listener
+	go r.serve()
+	return r
+}
+
+// HasSocket reports whether commands arrive over the control socket
+func (r *ControlReceiver) HasSocket() bool {
+	return r.listener != nil
+}
+
+// maxAcceptBackoff caps the wait between retries of a failing Accept
+const maxAcceptBackoff = time.Second
+
+// serve answers connections until the receiver is closed. Temporary Accept
+// errors (such as running out of file descriptors) are retried with a
+// growing wait rather than in a tight loop; any other error ends it.
+func (r *ControlReceiver) serve() {
+	var backoff time.Duration
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			var temporary interface{ Temporary() bool }
+			if errors.Is(err, net.ErrClosed) || !errors.As(err, &temporary) || !temporary.Temporary() {
+				return
+			}
+			backoff = min(max(2*backoff, 5*time.Millisecond), maxAcceptBackoff)
+			select {
+			case <-r.done:
+				return
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		backoff = 0
+		go r.handle(conn)
+	}
+}
+
+// handle reads one command from a connection and acknowledges it
+func (r *ControlReceiver) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(socketReplyTimeout))
+
+	reply := func(resp ControlResponse) {
+		data, _ := json.Marshal(resp)
+		_, _ = conn.Write(append(data, '\n'))
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		reply(ControlResponse{Error: "failed to read command"})
+		return
+	}
+	var ctrl Control
+	if err := json.Unmarshal(line, &ctrl); err != nil {
+		reply(ControlResponse{Error: "invalid command: " + err.Error()})
+		return
+	}
+	if err := r.deliver(&ctrl); err != nil {
+		reply(ControlResponse{Error: err.Error()})
+		return
+	}
+	reply(ControlResponse{OK: true})
+}
+
+var (
+	errUnknownCommand  = errors.New("unknown command")
+	errTooManyCommands = errors.New("too many pending commands")
+	errLoopEnding      = errors.New("the agent loop is ending")
+)
+
+// deliver queues a command for the loop
+func (r *ControlReceiver) deliver(ctrl *Control) error {
+	if !controlCommands[ctrl.Command] {
+		return fmt.Errorf("%w %q", errUnknownCommand, ctrl.Command)
+	}
+	select {
+	case <-r.done:
+		return errLoopEnding
+	default:
+	}
+	select {
+	case r.commands <- ctrl:
+		return nil
+	default:
+		return errTooManyCommands
+	}
+}
+
+// Next returns a pending command without waiting, or nil if there is none
+func (r *ControlReceiver) Next() *Control {
+	select {
+	case ctrl := <-r.commands:
+		return ctrl
+	default:
+	}
+	ctrl, _ := ReadControlCommand(r.projectDir, r.sessionID)
+	return ctrl
+}
+
+// Wait returns the next command, waiting for one to arrive. Returns nil once
+// ctx is cancelled.
+func (r *ControlReceiver) Wait(ctx context.Context) *Control {
+	interval := controlFilePollInterval
+	if r.HasSocket() {
+		interval = controlFileFallbackInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if ctrl := r.Next(); ctrl != nil {
+			return ctrl
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case ctrl := <-r.commands:
+			return ctrl
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops listening and removes the socket. Closing again does nothing.
+func (r *ControlReceiver) Close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+		unregisterLoop(r)
+		if r.listener != nil {
+			r.listener.Close()
+			os.Remove(GetSocketPath(r.projectDir, r.sessionID))
+		}
+	})
+}
+
+// sendOverSocket delivers a command to the session's control socket. Returns
+// an error without sending if no daemon listens there.
+func sendOverSocket(projectDir, sessionID string, ctrl Control) (delivered bool, err error) {
+	conn, err := net.DialTimeout("unix", GetSocketPath(projectDir, sessionID), socketDialTimeout)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(socketReplyTimeout))
+
+	data, err := json.Marshal(ctrl)
+	if err != nil {
+		return false, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return false, err
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return false, err
+	}
+	var resp ControlResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return false, fmt.Errorf("invalid response from daemon: %w", err)
+	}
+	if !resp.OK {
+		return true, fmt.Errorf("daemon rejected %s: %s", ctrl.Command, resp.Error)
+	}
+	return true, nil
+}
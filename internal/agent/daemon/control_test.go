@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// socketTempDir returns a temp dir short enough for a Unix socket path
+func socketTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "jctl-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestControlSocket(t *testing.T) {
+	dir := socketTempDir(t)
+	control := ListenControl(dir, "s")
+	if !control.HasSocket() {
+		control.Close()
+		t.Skip("Unix sockets are not available")
+	}
+
+	if err := SendControlCommand(dir, "s", CmdChangeModel, "haiku"); err != nil {
+		t.Fatalf("SendControlCommand failed: %v", err)
+	}
+	if _, err := os.Stat(GetControlFilePath(dir, "s")); !os.IsNotExist(err) {
+		t.Error("Expected the command to go over the socket, not the control file")
+	}
+	ctrl := control.Next()
+	if ctrl == nil || ctrl.Command != CmdChangeModel || ctrl.Args != "haiku" {
+		t.Fatalf("Expected change_model haiku, got %+v", ctrl)
+	}
+	if ctrl := control.Next(); ctrl != nil {
+		t.Errorf("Expected no more commands, got %+v", ctrl)
+	}
+
+	// Wait returns as soon as a command arrives, well before the file fallback poll
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = SendControlCommand(dir, "s", CmdResume, "")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if ctrl := control.Wait(ctx); ctrl == nil || ctrl.Command != CmdResume {
+		t.Errorf("Expected resume, got %+v", ctrl)
+	}
+
+	err := SendControlCommand(dir, "s", "explode", "")
+	if err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("Expected unknown command to be rejected, got %v", err)
+	}
+
+	control.Close()
+	if _, err := os.Stat(GetSocketPath(dir, "s")); !os.IsNotExist(err) {
+		t.Error("Expected Close to remove the socket")
+	}
+}
+
+func TestControlFileFallback(t *testing.T) {
+	dir := socketTempDir(t)
+
+	// No loop listening: the command is left in the control file
+	if err := SendControlCommand(dir, "s", CmdPause, ""); err != nil {
+		t.Fatalf("SendControlCommand failed: %v", err)
+	}
+	if _, err := os.Stat(GetControlFilePath(dir, "s")); err != nil {
+		t.Fatalf("Expected a control file, got %v", err)
+	}
+
+	// A loop started afterwards still picks it up
+	control := ListenControl(dir, "s")
+	defer control.Close()
+	if ctrl := control.Next(); ctrl == nil || ctrl.Command != CmdPause {
+		t.Errorf("Expected pause from the control file, got %+v", ctrl)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ctrl := control.Wait(ctx); ctrl != nil {
+		t.Errorf("Expected no command once cancelled, got %+v", ctrl)
+	}
+}
+
+// flakyListener fails Accept with temporary errors, then reports it is closed
+type flakyListener struct {
+	net.Listener
+	failures int
+	accepts  int
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "too many open files" }
+func (temporaryError) Temporary() bool { return true }
+
+func (l *flakyListener) Accept() (net.Conn, error) {
+	l.accepts++
+	if l.accepts <= l.failures {
+		return nil, temporaryError{}
+	}
+	return nil, net.ErrClosed
+}
+
+func TestControlServeBacksOff(t *testing.T) {
+	listener := &flakyListener{failures: 3}
+	r := &ControlReceiver{listener: listener, done: make(chan struct{})}
+
+	served := make(chan struct{})
+	start := time.Now()
+	go func() {
+		r.serve()
+		close(served)
+	}()
+	select {
+	case <-served:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected serve to return once the listener is closed")
+	}
+
+	if listener.accepts != 4 {
+		t.Errorf("Expected 3 retries then a stop, got %d accepts", listener.accepts)
+	}
+	// 5ms, 10ms and 20ms between the retries
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("Expected retries to back off, all took %v", elapsed)
+	}
+}
@@ -1,24 +1,43 @@
 // Package daemon provides infrastructure for running the agent loop as a background daemon
-// with file-based control and state communication.
+// with socket- and file-based control and file-based state communication.
 package daemon
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"syscall"
+	"strings"
 	"time"
+
+	"github.com/ohare93/juggle/internal/process"
 )
 
 const (
 	pidFileName   = "agent.pid"
 	ctrlFileName  = "agent.ctrl"
 	stateFileName = "agent.state"
+	stopFileName  = "agent.stop"
 )
 
+// StateVersion is the schema version of the agent.pid and agent.state files,
+// and the API's State, this build writes. Bump it when a field is added or
+// changes meaning, and list new fields in stateFieldVersions, so a monitor
+// can tell a field the daemon doesn't write from one that is empty.
+const StateVersion = 1
+
+// stateFieldVersions maps state fields (by JSON name) to the version from
+// which every daemon writes them. Files from before versioning (version 0)
+// came from builds with and without them.
+var stateFieldVersions = map[string]int{
+	"estimated_end": 1,
+	"crashed":       1,
+}
+
 // Info contains information about a running daemon
 type Info struct {
+	Version       int       `json:"version,omitempty"` // Schema version, see StateVersion
 	PID           int       `json:"pid"`
 	SessionID     string    `json:"session_id"`
 	ProjectDir    string    `json:"project_dir"`
@@ -26,10 +45,15 @@ type Info struct {
 	MaxIterations int       `json:"max_iterations"`
 	Model         string    `json:"model"`
 	Provider      string    `json:"provider"`
+	BallID        string    `json:"ball_id,omitempty"`   // Ball the run is limited to, if any
+	Trust         bool      `json:"trust,omitempty"`     // Whether the run has full permissions
+	APIAddr       string    `json:"api_addr,omitempty"`  // Daemon API address, see StartAPI
+	APIToken      string    `json:"api_token,omitempty"` // Token for the daemon API
 }
 
 // State represents the current state of the daemon, updated each iteration
 type State struct {
+	Version          int       `json:"version,omitempty"` // Schema version, see StateVersion
 	Running          bool      `json:"running"`
 	Paused           bool      `json:"paused"`
 	CurrentBallID    string    `json:"current_ball_id"`
@@ -43,25 +67,75 @@ type State struct {
 	Provider         string    `json:"provider"`
 	LastUpdated      time.Time `json:"last_updated"`
 	StartedAt        time.Time `json:"started_at"`
-	Status           string    `json:"status,omitempty"` // Status message (e.g., "No workable balls", "Complete", "Blocked")
+	Status           string    `json:"status,omitempty"`        // Status message (e.g., "No workable balls", "Complete", "Blocked")
+	EstimatedEnd     time.Time `json:"estimated_end,omitempty"` // Rough time the run should finish, zero when unknown
+	Crashed          *Info     `json:"crashed,omitempty"`       // PID file of a run that died mid-run, until the supervisor handles it
 }
 
-// Control represents a command sent to the daemon via the control file
+// Control represents a command sent to the daemon via the control socket or file
 type Control struct {
-	Command   string    `json:"command"`   // pause, resume, cancel, skip_ball, change_model
-	Args      string    `json:"args"`      // e.g., model name for change_model
+	Command   string    `json:"command"` // pause, resume, cancel, skip_ball, change_model, change_provider, takeover
+	Args      string    `json:"args"`    // e.g., model name for change_model, see SkipBallArgs for skip_ball
 	Timestamp time.Time `json:"timestamp"`
 }
 
 // Command constants
 const (
-	CmdPause       = "pause"
-	CmdResume      = "resume"
-	CmdCancel      = "cancel"
-	CmdSkipBall    = "skip_ball"
-	CmdChangeModel = "change_model"
+	CmdPause          = "pause"
+	CmdResume         = "resume"
+	CmdCancel         = "cancel"
+	CmdSkipBall       = "skip_ball"
+	CmdChangeModel    = "change_model"
+	CmdChangeProvider = "change_provider" // Switch the agent provider from the next iteration
+	CmdTakeover       = "takeover"        // Hold the loop between iterations for an interactive session
 )
 
+// SkipBallArgs builds the args of a skip_ball command: the ball to skip and
+// who asked, either of which may be empty
+func SkipBallArgs(ballID, requester string) string {
+	if requester == "" {
+		return ballID
+	}
+	return ballID + " " + requester
+}
+
+// ParseSkipBallArgs splits the args of a skip_ball command into the ball to
+// skip and who asked
+func ParseSkipBallArgs(args string) (ballID, requester string) {
+	ballID, requester, _ = strings.Cut(args, " ")
+	return strings.TrimSpace(ballID), strings.TrimSpace(requester)
+}
+
+// StatusStopped is the final state status of a run ended by juggle agent stop
+const StatusStopped = "Stopped by juggle agent stop"
+
+// StatusTakeover is the state status while the loop is held for an interactive
+// takeover; the daemon waits for CmdResume
+const StatusTakeover = "Paused for interactive takeover"
+
+// StatusCrashed is the state status of a run whose process died mid-run
+const StatusCrashed = "Daemon exited unexpectedly"
+
+// HasField reports whether the state's daemon writes a field (by JSON name),
+// so an unset one is really empty rather than unknown to an older daemon
+func (s *State) HasField(field string) bool {
+	return s.Version >= stateFieldVersions[field]
+}
+
+// VersionMismatch describes how the schema version of a daemon's files
+// differs from this build's, or returns "" when they match. Files written
+// before versioning have version 0.
+func VersionMismatch(version int) string {
+	switch {
+	case version < StateVersion:
+		return fmt.Sprintf("daemon was started by an older juggle (state version %d, this juggle writes %d)", version, StateVersion)
+	case version > StateVersion:
+		return fmt.Sprintf("daemon was started by a newer juggle (state version %d, this juggle reads %d)", version, StateVersion)
+	default:
+		return ""
+	}
+}
+
 // sessionDir returns the session directory path
 func sessionDir(projectDir, sessionID string) string {
 	return filepath.Join(projectDir, ".juggle", "sessions", sessionID)
@@ -82,6 +156,37 @@ func GetStateFilePath(projectDir, sessionID string) string {
 	return filepath.Join(sessionDir(projectDir, sessionID), stateFileName)
 }
 
+// GetStopFilePath returns the path to the stop request file for a session
+func GetStopFilePath(projectDir, sessionID string) string {
+	return filepath.Join(sessionDir(projectDir, sessionID), stopFileName)
+}
+
+// RequestStop asks the session's agent loop, daemon or foreground, to stop
+// safely. Unlike control commands the request isn't consumed when read, so
+// the loop can watch for it while an iteration runs.
+func RequestStop(projectDir, sessionID string) error {
+	dir := sessionDir(projectDir, sessionID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	return os.WriteFile(GetStopFilePath(projectDir, sessionID), []byte(time.Now().Format(time.RFC3339)+"\n"), 0644)
+}
+
+// StopRequested reports whether a stop has been requested for the session
+func StopRequested(projectDir, sessionID string) bool {
+	_, err := os.Stat(GetStopFilePath(projectDir, sessionID))
+	return err == nil
+}
+
+// ClearStopRequest removes the session's stop request, if any
+func ClearStopRequest(projectDir, sessionID string) error {
+	err := os.Remove(GetStopFilePath(projectDir, sessionID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
 // WritePIDFile creates a PID file for the running daemon
 func WritePIDFile(projectDir, sessionID string, info *Info) error {
 	// Ensure session directory exists
@@ -91,11 +196,13 @@ func WritePIDFile(projectDir, sessionID string, info *Info) error {
 	}
 
 	path := GetPIDFilePath(projectDir, sessionID)
+	info.Version = StateVersion
 	data, err := json.MarshalIndent(info, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal daemon info: %w", err)
 	}
-	return os.WriteFile(path, data, 0644)
+	// Only the owner may read the API token
+	return os.WriteFile(path, data, 0600)
 }
 
 // ReadPIDFile reads the PID file for a session
@@ -126,10 +233,12 @@ func RemovePIDFile(projectDir, sessionID string) error {
 func WriteStateFile(projectDir, sessionID string, state *State) error {
 	path := GetStateFilePath(projectDir, sessionID)
 	state.LastUpdated = time.Now()
+	state.Version = StateVersion
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal daemon state: %w", err)
 	}
+	publishState(projectDir, sessionID, state)
 	return os.WriteFile(path, data, 0644)
 }
 
@@ -157,8 +266,19 @@ func RemoveStateFile(projectDir, sessionID string) error {
 	return err
 }
 
-// SendControlCommand writes a control command to the control file
+// SendControlCommand delivers a control command to the session's agent loop.
+// It goes over the loop's control socket when one is listening, and is written
+// to the control file otherwise.
 func SendControlCommand(projectDir, sessionID, command, args string) error {
+	ctrl := Control{
+		Command:   command,
+		Args:      args,
+		Timestamp: time.Now(),
+	}
+	if delivered, err := sendOverSocket(projectDir, sessionID, ctrl); delivered {
+		return err
+	}
+
 	// Ensure session directory exists
 	dir := sessionDir(projectDir, sessionID)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -166,11 +286,6 @@ func SendControlCommand(projectDir, sessionID, command, args string) error {
 	}
 
 	path := GetControlFilePath(projectDir, sessionID)
-	ctrl := Control{
-		Command:   command,
-		Args:      args,
-		Timestamp: time.Now(),
-	}
 	data, err := json.MarshalIndent(ctrl, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal control command: %w", err)
@@ -212,17 +327,6 @@ func ReadControlCommand(projectDir, sessionID string) (*Control, error) {
 	return &ctrl, nil
 }
 
-// isProcessRunning checks if a process with the given PID is running
-func isProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	// On Unix, FindProcess always succeeds, so we need to send signal 0
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
-}
-
 // IsRunning checks if a daemon is running for a session
 // Returns (running, info, error)
 func IsRunning(projectDir, sessionID string) (bool, *Info, error) {
@@ -234,17 +338,37 @@ func IsRunning(projectDir, sessionID string) (bool, *Info, error) {
 		return false, nil, err
 	}
 
-	// Check if process is still running
-	if isProcessRunning(info.PID) {
+	// Check if process is still running, and is the daemon rather than a
+	// process that got its PID since
+	if process.RunningSince(info.PID, info.StartedAt) {
 		return true, info, nil
 	}
 
-	// Stale PID file - clean up
-	RemovePIDFile(projectDir, sessionID)
-	RemoveStateFile(projectDir, sessionID)
+	// Stale PID file - clean up. A run that was still going died without
+	// ending; its state is kept, marked as crashed, for the supervisor.
+	CleanupPIDAndControl(projectDir, sessionID)
+	state, err := ReadStateFile(projectDir, sessionID)
+	if err == nil && !state.Running {
+		RemoveStateFile(projectDir, sessionID)
+		return false, nil, nil
+	}
+	if state == nil {
+		state = &State{MaxIterations: info.MaxIterations, Model: info.Model, Provider: info.Provider, StartedAt: info.StartedAt}
+	}
+	state.Running = false
+	state.Status = StatusCrashed
+	state.Crashed = info
+	WriteStateFile(projectDir, sessionID, state)
 	return false, nil, nil
 }
 
+// IsAlive reports whether the session's PID file names a running daemon.
+// Unlike IsRunning it leaves stale PID and state files in place.
+func IsAlive(projectDir, sessionID string) bool {
+	info, err := ReadPIDFile(projectDir, sessionID)
+	return err == nil && process.RunningSince(info.PID, info.StartedAt)
+}
+
 // Cleanup removes all daemon-related files for a session
 func Cleanup(projectDir, sessionID string) error {
 	var lastErr error
@@ -254,14 +378,45 @@ func Cleanup(projectDir, sessionID string) error {
 	if err := RemoveStateFile(projectDir, sessionID); err != nil {
 		lastErr = err
 	}
-	// Remove control file if it exists
-	ctrlPath := GetControlFilePath(projectDir, sessionID)
-	if err := os.Remove(ctrlPath); err != nil && !os.IsNotExist(err) {
-		lastErr = err
+	// Remove control file and socket if they exist
+	for _, path := range []string{GetControlFilePath(projectDir, sessionID), GetSocketPath(projectDir, sessionID)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			lastErr = err
+		}
 	}
 	return lastErr
 }
 
+// ErrDaemonAlive is returned by CleanStale while the session's daemon runs
+var ErrDaemonAlive = errors.New("daemon is still running")
+
+// CleanStale removes the files a dead daemon left for a session: PID, state,
+// control, socket and stop request. It refuses while the daemon runs, and
+// returns the paths it removed.
+func CleanStale(projectDir, sessionID string) ([]string, error) {
+	if IsAlive(projectDir, sessionID) {
+		return nil, ErrDaemonAlive
+	}
+	var removed []string
+	var lastErr error
+	for _, path := range []string{
+		GetPIDFilePath(projectDir, sessionID),
+		GetStateFilePath(projectDir, sessionID),
+		GetControlFilePath(projectDir, sessionID),
+		GetSocketPath(projectDir, sessionID),
+		GetStopFilePath(projectDir, sessionID),
+	} {
+		err := os.Remove(path)
+		switch {
+		case err == nil:
+			removed = append(removed, path)
+		case !os.IsNotExist(err):
+			lastErr = err
+		}
+	}
+	return removed, lastErr
+}
+
 // CleanupPIDAndControl removes PID and control files but preserves the state file
 // This is used for normal daemon exit where the final state should remain readable by the TUI
 func CleanupPIDAndControl(projectDir, sessionID string) error {
@@ -269,10 +424,11 @@ func CleanupPIDAndControl(projectDir, sessionID string) error {
 	if err := RemovePIDFile(projectDir, sessionID); err != nil {
 		lastErr = err
 	}
-	// Remove control file if it exists
-	ctrlPath := GetControlFilePath(projectDir, sessionID)
-	if err := os.Remove(ctrlPath); err != nil && !os.IsNotExist(err) {
-		lastErr = err
+	// Remove control file and socket if they exist
+	for _, path := range []string{GetControlFilePath(projectDir, sessionID), GetSocketPath(projectDir, sessionID)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			lastErr = err
+		}
 	}
 	return lastErr
 }
@@ -1,8 +1,10 @@
 package daemon
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -47,6 +49,9 @@ func TestDaemonFiles(t *testing.T) {
 		if readInfo.Model != info.Model {
 			t.Errorf("Model mismatch: got %s, want %s", readInfo.Model, info.Model)
 		}
+		if readInfo.Version != StateVersion {
+			t.Errorf("Version mismatch: got %d, want %d", readInfo.Version, StateVersion)
+		}
 	})
 
 	// Test WriteStateFile and ReadStateFile
@@ -84,6 +89,9 @@ func TestDaemonFiles(t *testing.T) {
 		if readState.CurrentBallID != state.CurrentBallID {
 			t.Errorf("CurrentBallID mismatch: got %s, want %s", readState.CurrentBallID, state.CurrentBallID)
 		}
+		if readState.Version != StateVersion {
+			t.Errorf("Version mismatch: got %d, want %d", readState.Version, StateVersion)
+		}
 	})
 
 	// Test SendControlCommand and ReadControlCommand
@@ -206,6 +214,30 @@ func TestDaemonFiles(t *testing.T) {
 	})
 }
 
+func TestStateVersion(t *testing.T) {
+	if msg := VersionMismatch(StateVersion); msg != "" {
+		t.Errorf("Expected no mismatch for the current version, got %q", msg)
+	}
+	if msg := VersionMismatch(0); !strings.Contains(msg, "older juggle") {
+		t.Errorf("Expected an older daemon for version 0, got %q", msg)
+	}
+	if msg := VersionMismatch(StateVersion + 1); !strings.Contains(msg, "newer juggle") {
+		t.Errorf("Expected a newer daemon, got %q", msg)
+	}
+
+	// A state from before versioning may lack later fields; older ones are known
+	old := &State{}
+	if old.HasField("estimated_end") {
+		t.Error("Expected an unversioned state not to vouch for estimated_end")
+	}
+	if !old.HasField("current_ball_id") {
+		t.Error("Expected an unversioned state to have current_ball_id")
+	}
+	if !(&State{Version: StateVersion}).HasField("estimated_end") {
+		t.Error("Expected a current state to have estimated_end")
+	}
+}
+
 func TestIsRunning(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "daemon-isrunning-test-*")
 	if err != nil {
@@ -252,6 +284,55 @@ func TestIsRunning(t *testing.T) {
 	if _, err := os.Stat(pidPath); !os.IsNotExist(err) {
 		t.Error("Stale PID file should be cleaned up")
 	}
+
+	// The run died without ending, so its state is marked as crashed
+	state, err := ReadStateFile(tmpDir, sessionID)
+	if err != nil {
+		t.Fatalf("Expected a state file for the crashed run: %v", err)
+	}
+	if state.Running || state.Status != StatusCrashed || state.Crashed == nil || state.Crashed.PID != fakeInfo.PID {
+		t.Errorf("Expected the state to record the crash, got %+v", state)
+	}
+
+	// A run that ended is just cleaned up
+	state.Running = false
+	state.Crashed = nil
+	state.Status = "Complete"
+	WriteStateFile(tmpDir, sessionID, state)
+	WritePIDFile(tmpDir, sessionID, fakeInfo)
+	if running, _, err := IsRunning(tmpDir, sessionID); err != nil || running {
+		t.Fatalf("IsRunning = %v, %v; want false", running, err)
+	}
+	if _, err := os.Stat(GetStateFilePath(tmpDir, sessionID)); !os.IsNotExist(err) {
+		t.Error("State of an ended run should be cleaned up")
+	}
+}
+
+func TestIsRunningReusedPID(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionID := "test-session"
+
+	// This process started after the PID file was written, so it got the
+	// PID of a daemon that exited
+	info := &Info{PID: os.Getpid(), SessionID: sessionID, StartedAt: time.Now().Add(-24 * time.Hour)}
+	if err := WritePIDFile(tmpDir, sessionID, info); err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	if err := os.WriteFile(GetControlFilePath(tmpDir, sessionID), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if IsAlive(tmpDir, sessionID) {
+		t.Error("Expected a reused PID not to count as alive")
+	}
+	running, _, err := IsRunning(tmpDir, sessionID)
+	if err != nil || running {
+		t.Fatalf("IsRunning = %v, %v; want false", running, err)
+	}
+	for _, path := range []string{GetPIDFilePath(tmpDir, sessionID), GetControlFilePath(tmpDir, sessionID)} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected stale %s to be removed", filepath.Base(path))
+		}
+	}
 }
 
 func TestControlCommandAtomicity(t *testing.T) {
@@ -307,3 +388,77 @@ func TestControlCommandAtomicity(t *testing.T) {
 		t.Error("Expected nil after command was consumed")
 	}
 }
+
+func TestStopRequest(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionID := "test-session"
+
+	if StopRequested(tmpDir, sessionID) {
+		t.Fatal("Expected no stop request initially")
+	}
+	if err := RequestStop(tmpDir, sessionID); err != nil {
+		t.Fatalf("RequestStop failed: %v", err)
+	}
+
+	// Reading the request doesn't consume it
+	if !StopRequested(tmpDir, sessionID) || !StopRequested(tmpDir, sessionID) {
+		t.Error("Expected the stop request to persist until cleared")
+	}
+
+	if err := ClearStopRequest(tmpDir, sessionID); err != nil {
+		t.Fatalf("ClearStopRequest failed: %v", err)
+	}
+	if StopRequested(tmpDir, sessionID) {
+		t.Error("Expected no stop request after clearing")
+	}
+	if err := ClearStopRequest(tmpDir, sessionID); err != nil {
+		t.Errorf("Expected clearing a missing request to succeed, got %v", err)
+	}
+}
+
+func TestCleanStale(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionID := "test-session"
+
+	// A live daemon's files are left alone
+	if err := WritePIDFile(tmpDir, sessionID, &Info{PID: os.Getpid(), StartedAt: time.Now()}); err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	if _, err := CleanStale(tmpDir, sessionID); !errors.Is(err, ErrDaemonAlive) {
+		t.Fatalf("CleanStale on a live daemon = %v; want ErrDaemonAlive", err)
+	}
+
+	// A dead one's are removed, state included
+	if err := WritePIDFile(tmpDir, sessionID, &Info{PID: 999999999}); err != nil {
+		t.Fatalf("WritePIDFile failed: %v", err)
+	}
+	WriteStateFile(tmpDir, sessionID, &State{Running: true, Status: StatusCrashed})
+	RequestStop(tmpDir, sessionID)
+	removed, err := CleanStale(tmpDir, sessionID)
+	if err != nil {
+		t.Fatalf("CleanStale failed: %v", err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("Expected PID, state and stop files removed, got %v", removed)
+	}
+	if removed, _ := CleanStale(tmpDir, sessionID); len(removed) != 0 {
+		t.Errorf("Expected nothing left to clean, got %v", removed)
+	}
+}
+
+func TestSkipBallArgs(t *testing.T) {
+	tests := []struct {
+		ballID, requester string
+	}{
+		{"proj-1", "alice"},
+		{"proj-1", ""},
+		{"", "alice"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		ballID, requester := ParseSkipBallArgs(SkipBallArgs(tt.ballID, tt.requester))
+		if ballID != tt.ballID || requester != tt.requester {
+			t.Errorf("SkipBallArgs(%q, %q) parsed back as %q, %q", tt.ballID, tt.requester, ballID, requester)
+		}
+	}
+}
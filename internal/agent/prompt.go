@@ -3,7 +3,7 @@
 package agent
 
 import (
-	_ "embed"
+	"embed"
 )
 
 //go:embed prompt.md
@@ -13,3 +13,19 @@ var PromptTemplate string
 func GetPromptTemplate() string {
 	return PromptTemplate
 }
+
+//go:embed kinds/*.md
+var kindTemplates embed.FS
+
+// KindInstructions returns the extra agent instructions for balls of the given
+// kind (docs, research, ops). Code balls follow the main template and get "".
+func KindInstructions(kind string) string {
+	if kind == "" || kind == "code" {
+		return ""
+	}
+	data, err := kindTemplates.ReadFile("kinds/" + kind + ".md")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
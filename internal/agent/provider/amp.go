@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// AmpProvider implements Provider for Sourcegraph's Amp CLI
+type AmpProvider struct{}
+
+// NewAmpProvider creates a new Amp provider
+func NewAmpProvider() *AmpProvider {
+	return &AmpProvider{}
+}
+
+// Type returns TypeAmp
+func (a *AmpProvider) Type() Type {
+	return TypeAmp
+}
+
+// MapModel converts canonical model name to an Amp agent mode.
+// Amp picks the model itself; callers choose between the fast "rush" mode
+// and the default "smart" mode.
+func (a *AmpProvider) MapModel(canonical string) string {
+	switch canonical {
+	case "haiku", "small":
+		return "rush"
+	case "sonnet", "medium", "opus", "large":
+		return "smart"
+	default:
+		// Assume it's already an Amp mode name
+		return canonical
+	}
+}
+
+// MapPermission converts PermissionMode to Amp CLI flags.
+// Amp has no plan mode: in execute mode any tool not on the user's allowlist
+// is rejected, so plan runs without extra flags and is effectively read-only.
+func (a *AmpProvider) MapPermission(mode PermissionMode) (flag, value string) {
+	switch mode {
+	case PermissionPlan:
+		return "", ""
+	default:
+		return "--dangerously-allow-all", ""
+	}
+}
+
+// ListModels returns the Amp agent modes
+func (a *AmpProvider) ListModels() []string {
+	return []string{"rush", "smart"}
+}
+
+// SupportsInteractive returns false: juggle only drives Amp in execute mode
+func (a *AmpProvider) SupportsInteractive() bool {
+	return false
+}
+
+// SupportsSystemPrompt returns false: Amp has no system prompt flag
+func (a *AmpProvider) SupportsSystemPrompt() bool {
+	return false
+}
+
+// SupportsTools returns true
+func (a *AmpProvider) SupportsTools() bool {
+	return true
+}
+
+// Run executes Amp CLI with the given options
+func (a *AmpProvider) Run(opts RunOptions) (*RunResult, error) {
+	if opts.Mode == ModeInteractive {
+		return nil, fmt.Errorf("amp provider does not support interactive mode")
+	}
+	return a.runHeadless(opts)
+}
+
+// runHeadless executes Amp in execute mode (amp -x, prompt on stdin)
+func (a *AmpProvider) runHeadless(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
+
+	args := []string{"--execute"}
+
+	if opts.Model != "" {
+		args = append(args, "--mode", a.MapModel(opts.Model))
+	}
+
+	if flag, _ := a.MapPermission(opts.Permission); flag != "" {
+		args = append(args, flag)
+	}
+
+	// Amp has no system prompt flag, so prepend it if the caller hasn't already
+	prompt := opts.Prompt
+	if opts.SystemPrompt != "" {
+		prompt = opts.SystemPrompt + "\n\n" + opts.Prompt
+	}
+
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
+
+	// Separate cancel so the watcher can stop the agent early without reporting a timeout
+	runCtx, stopRun := context.WithCancel(ctx)
+	defer stopRun()
+
+	cmd := agentCommand(runCtx, opts, BinaryName(TypeAmp), args)
+
+	// Pipe prompt through stdin
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	// Start command
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start amp: %w", err)
+	}
+
+	// Write prompt to stdin
+	go func() {
+		defer stdin.Close()
+		io.WriteString(stdin, prompt)
+	}()
+
+	// Stream output to console and capture, watching for signals
+	watcher := newSignalWatcher(stopRun, stdout, stderr)
+	result.Output, err = waitWatched(cmd, stdout, stderr, watcher)
+	if finishRun(ctx, opts, result, "amp", err, watcher.release()) {
+		return result, nil
+	}
+
+	// Parse signals - same format as Claude since the prompt instructs the LLM
+	parseSignals(result)
+
+	return result, nil
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvAnthropicAPIKey holds the key the API provider authenticates with
+	EnvAnthropicAPIKey = "ANTHROPIC_API_KEY"
+	// EnvAnthropicBaseURL overrides the API endpoint (proxies, gateways, tests)
+	EnvAnthropicBaseURL = "ANTHROPIC_BASE_URL"
+
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	anthropicVersion        = "2023-06-01"
+	apiDefaultModel         = "claude-sonnet-4-5"
+	apiMaxTokens            = 8192
+)
+
+// AdviceSystemPrompt tells the model it is answering without tools, so it
+// reviews and plans instead of pretending to edit files or run commands
+const AdviceSystemPrompt = `You are running in advice mode: you have no tools and cannot read files, edit code or run commands. Work only from the prompt. Do not claim to have made changes. Reply with your analysis, any problems you see, and a concrete plan or suggested edits the user can apply.`
+
+// APIProvider implements Provider by calling the Anthropic messages API
+// directly. It needs no CLI binary but has no tools, so it only gives advice:
+// juggle uses it for refine and dry-run review, not for the agent loop.
+type APIProvider struct {
+	client *http.Client
+}
+
+// NewAPIProvider creates a new Anthropic API provider
+func NewAPIProvider() *APIProvider {
+	return &APIProvider{client: http.DefaultClient}
+}
+
+// Type returns TypeAPI
+func (a *APIProvider) Type() Type {
+	return TypeAPI
+}
+
+// MapModel converts canonical model name to an Anthropic model ID
+func (a *APIProvider) MapModel(canonical string) string {
+	switch canonical {
+	case "haiku", "small":
+		return "claude-3-5-haiku-latest"
+	case "sonnet", "medium":
+		return "claude-sonnet-4-5"
+	case "opus", "large":
+		return "claude-opus-4-5"
+	default:
+		return canonical
+	}
+}
+
+// MapPermission returns empty strings: without tools there is nothing to permit
+func (a *APIProvider) MapPermission(mode PermissionMode) (flag, value string) {
+	return "", ""
+}
+
+// ListModels returns nil: the API accepts any model ID
+func (a *APIProvider) ListModels() []string {
+	return nil
+}
+
+// SupportsInteractive returns false: each run is a single request
+func (a *APIProvider) SupportsInteractive() bool {
+	return false
+}
+
+// SupportsSystemPrompt returns true (the request's system field)
+func (a *APIProvider) SupportsSystemPrompt() bool {
+	return true
+}
+
+// SupportsTools returns false: the API provider only gives advice
+func (a *APIProvider) SupportsTools() bool {
+	return false
+}
+
+// apiRequest is the body of a messages API request
+type apiRequest struct {
+	Model     string       `json:"model"`
+	MaxTokens int          `json:"max_tokens"`
+	System    string       `json:"system,omitempty"`
+	Messages  []apiMessage `json:"messages"`
+}
+
+type apiMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// apiResponse is the subset of a messages API response juggle reads
+type apiResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Run sends the prompt to the messages API and returns the model's reply.
+// Interactive mode is not supported.
+func (a *APIProvider) Run(opts RunOptions) (*RunResult, error) {
+	if opts.Mode == ModeInteractive {
+		return nil, fmt.Errorf("the api provider does not support interactive mode")
+	}
+
+	key := os.Getenv(EnvAnthropicAPIKey)
+	if key == "" {
+		return nil, fmt.Errorf("%s is not set", EnvAnthropicAPIKey)
+	}
+
+	model := apiDefaultModel
+	if opts.Model != "" {
+		model = a.MapModel(opts.Model)
+	}
+	system := AdviceSystemPrompt
+	if opts.SystemPrompt != "" {
+		system += "\n\n" + opts.SystemPrompt
+	}
+	body, err := json.Marshal(apiRequest{
+		Model:     model,
+		MaxTokens: apiMaxTokens,
+		System:    system,
+		Messages:  []apiMessage{{Role: "user", Content: opts.Prompt}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := runContext(opts)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBaseURL()+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("x-api-key", key)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	result := &RunResult{}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		// Check if this was a timeout
+		if ctx.Err() == context.DeadlineExceeded {
+			result.TimedOut = true
+			result.Error = fmt.Errorf("request timed out after %v", opts.Timeout)
+			return result, nil
+		}
+		if ctx.Err() == context.Canceled {
+			result.Error = fmt.Errorf("run cancelled: %w", context.Cause(ctx))
+			return result, nil
+		}
+		return nil, fmt.Errorf("anthropic API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anthropic API response: %w", err)
+	}
+
+	var parsed apiResponse
+	if err := json.Unmarshal(data, &parsed); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to parse anthropic API response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		result.ExitCode = 1
+		result.Output = strings.TrimSpace(string(data))
+		if parsed.Error != nil {
+			result.Output = fmt.Sprintf("%s: %s", parsed.Error.Type, parsed.Error.Message)
+		}
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			result.RateLimited = true
+			result.RetryAfter = retryAfterHeader(resp.Header.Get("retry-after"))
+		case resp.StatusCode == 529:
+			result.RateLimited = true
+			result.OverloadExhausted = true
+		default:
+			result.Error = fmt.Errorf("anthropic API returned %s: %s", resp.Status, result.Output)
+		}
+		return result, nil
+	}
+
+	var text []string
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			text = append(text, block.Text)
+		}
+	}
+	result.Output = strings.Join(text, "\n")
+	result.InputTokens = parsed.Usage.InputTokens + parsed.Usage.CacheCreationInputTokens + parsed.Usage.CacheReadInputTokens
+	result.OutputTokens = parsed.Usage.OutputTokens
+	parsePromiseSignals(result)
+
+	return result, nil
+}
+
+// apiBaseURL returns $ANTHROPIC_BASE_URL or the public endpoint
+func apiBaseURL() string {
+	if url := os.Getenv(EnvAnthropicBaseURL); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return defaultAnthropicBaseURL
+}
+
+// retryAfterHeader parses a retry-after header given in seconds (0 if absent)
+func retryAfterHeader(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
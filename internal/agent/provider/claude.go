@@ -54,6 +54,26 @@ func (c *ClaudeProvider) MapPermission(mode PermissionMode) (flag, value string)
 	}
 }
 
+// ListModels returns nil: Claude Code accepts aliases and full model IDs
+func (c *ClaudeProvider) ListModels() []string {
+	return nil
+}
+
+// SupportsInteractive returns true
+func (c *ClaudeProvider) SupportsInteractive() bool {
+	return true
+}
+
+// SupportsSystemPrompt returns true (--append-system-prompt)
+func (c *ClaudeProvider) SupportsSystemPrompt() bool {
+	return true
+}
+
+// SupportsTools returns true
+func (c *ClaudeProvider) SupportsTools() bool {
+	return true
+}
+
 // Run executes Claude CLI with the given options
 func (c *ClaudeProvider) Run(opts RunOptions) (*RunResult, error) {
 	if opts.Mode == ModeInteractive {
@@ -89,25 +109,21 @@ func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 		args = append(args, flag)
 	}
 
+	// Structured events instead of plain text; stream-json requires --verbose with -p
+	args = append(args, "--output-format", "stream-json", "--verbose")
+
 	// Headless mode: read prompt from stdin
 	args = append(args, "-p", "-")
 
-	// Create context with timeout if specified
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if opts.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
-		defer cancel()
-	} else {
-		ctx = context.Background()
-	}
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "claude", args...)
-	if opts.WorkingDir != "" {
-		cmd.Dir = opts.WorkingDir
-	}
+	// Separate cancel so the watcher can stop the agent early without reporting a timeout
+	runCtx, stopRun := context.WithCancel(ctx)
+	defer stopRun()
 
-	var outputBuf strings.Builder
+	cmd := agentCommand(runCtx, opts, BinaryName(TypeClaude), args)
 
 	// Pipe prompt through stdin
 	stdin, err := cmd.StdinPipe()
@@ -136,40 +152,40 @@ func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 		io.WriteString(stdin, opts.Prompt)
 	}()
 
-	// Stream output to console and capture
+	// Parse events, rendering them to the console as they arrive and watching for signals
+	stream := newClaudeStream(newSignalWatcher(stopRun, stdout, stderr))
+	if opts.Stop != nil {
+		// A stop request lets the current tool call finish, so its work isn't cut off midway
+		go func() {
+			select {
+			case <-opts.Stop:
+				stream.stopAfterTool(stopRun)
+			case <-runCtx.Done():
+			}
+		}()
+	}
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		streamOutput(stdout, &outputBuf, os.Stdout)
+		stream.consume(stdout, os.Stdout)
 	}()
 	go func() {
 		defer wg.Done()
-		streamOutput(stderr, &outputBuf, os.Stderr)
+		stream.consume(stderr, os.Stderr)
 	}()
 
-	// Wait for command to complete
-	err = cmd.Wait()
-	// Wait for output streaming to finish before reading buffer
+	// Drain output before Wait, which closes the pipes
 	wg.Wait()
-	result.Output = outputBuf.String()
-
-	if err != nil {
-		// Check if this was a timeout
-		if ctx.Err() == context.DeadlineExceeded {
-			result.TimedOut = true
-			result.Error = fmt.Errorf("iteration timed out after %v", opts.Timeout)
-			return result, nil
-		}
+	err = cmd.Wait()
+	result.Output = stream.Output()
 
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
-		}
-		result.Error = fmt.Errorf("claude exited with error: %w", err)
+	if finishRun(ctx, opts, result, "claude", err, stream.watcher.release() || stream.stopRequested()) {
+		return result, nil
 	}
 
-	// Parse completion signals from output
-	parseSignals(result)
+	// Fill usage, tool events and signals from the parsed events
+	stream.apply(result)
 
 	return result, nil
 }
@@ -204,20 +220,11 @@ func (c *ClaudeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 	// Interactive mode: pass prompt as argument
 	args = append(args, opts.Prompt)
 
-	// Create context with timeout if specified
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if opts.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
-		defer cancel()
-	} else {
-		ctx = context.Background()
-	}
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "claude", args...)
-	if opts.WorkingDir != "" {
-		cmd.Dir = opts.WorkingDir
-	}
+	cmd := agentCommand(ctx, opts, BinaryName(TypeClaude), args)
 
 	// Inherit terminal for full TUI
 	cmd.Stdin = os.Stdin
@@ -239,6 +246,10 @@ func (c *ClaudeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 			result.Error = fmt.Errorf("session timed out after %v", opts.Timeout)
 			return result, nil
 		}
+		if ctx.Err() == context.Canceled {
+			result.Error = fmt.Errorf("session cancelled: %w", context.Cause(ctx))
+			return result, nil
+		}
 
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			result.ExitCode = exitErr.ExitCode()
@@ -249,8 +260,16 @@ func (c *ClaudeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 	return result, nil
 }
 
-// parseSignals checks the output for COMPLETE/CONTINUE/BLOCKED signals
+// parseSignals checks the output for COMPLETE/CONTINUE/BLOCKED signals and rate limits
 func parseSignals(result *RunResult) {
+	parsePromiseSignals(result)
+
+	// Check for rate limit indicators
+	parseRateLimit(result)
+}
+
+// parsePromiseSignals checks the output for COMPLETE/CONTINUE/BLOCKED signals
+func parsePromiseSignals(result *RunResult) {
 	// Check for COMPLETE signal (with optional commit message)
 	// Format: <promise>COMPLETE</promise> or <promise>COMPLETE: commit message</promise>
 	if idx := strings.Index(result.Output, "<promise>COMPLETE"); idx != -1 {
@@ -287,9 +306,6 @@ func parseSignals(result *RunResult) {
 			result.BlockedReason = reason
 		}
 	}
-
-	// Check for rate limit indicators
-	parseRateLimit(result)
 }
 
 // parseRateLimit detects rate limit errors and extracts retry-after time if available
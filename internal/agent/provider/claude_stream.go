@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// toolInputSummaryLen caps the tool input shown in output and ToolEvents
+const toolInputSummaryLen = 100
+
+// toolInputKeys are the tool input fields that best summarize a call, in order of preference
+var toolInputKeys = []string{"command", "file_path", "path", "pattern", "url", "query", "description"}
+
+// claudeStreamEvent is one line of `claude --output-format stream-json` output
+type claudeStreamEvent struct {
+	Type         string         `json:"type"` // system, assistant, user or result
+	Subtype      string         `json:"subtype"`
+	Message      *claudeMessage `json:"message"`
+	Result       string         `json:"result"`   // Final text (result events)
+	IsError      bool           `json:"is_error"` // Run ended in error (result events)
+	TotalCostUSD float64        `json:"total_cost_usd"`
+	Usage        *claudeUsage   `json:"usage"`
+}
+
+// claudeMessage is the message carried by assistant and user events
+type claudeMessage struct {
+	Content []claudeContent `json:"content"`
+}
+
+// claudeContent is one content block of a message
+type claudeContent struct {
+	Type      string          `json:"type"` // text, thinking, tool_use or tool_result
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	IsError   bool            `json:"is_error"`
+}
+
+// claudeUsage is the token usage reported in the result event
+type claudeUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// claudeStream collects stream-json events from a headless Claude run.
+// stdout and stderr are consumed concurrently, so all state is guarded by mu.
+type claudeStream struct {
+	mu         sync.Mutex
+	events     int                // Number of JSON events parsed
+	transcript strings.Builder    // Human-readable rendering, used as RunResult.Output
+	text       strings.Builder    // Assistant text only, for signal detection
+	raw        strings.Builder    // Lines that weren't events (stderr, CLI warnings)
+	tools      []ToolEvent        // Tool calls in order
+	toolIndex  map[string]int     // tool_use ID -> index in tools
+	pending    map[string]bool    // tool_use IDs still waiting for their result
+	final      *claudeStreamEvent // The result event, if one arrived
+	watcher    *signalWatcher     // Mid-run signal detection (may be nil)
+	stop       func()             // Stops the agent once no tool call is pending (nil = not requested)
+}
+
+// newClaudeStream creates an empty stream collector.
+// Assistant text is passed to watcher (may be nil) as it arrives.
+func newClaudeStream(watcher *signalWatcher) *claudeStream {
+	return &claudeStream{toolIndex: make(map[string]int), pending: make(map[string]bool), watcher: watcher}
+}
+
+// stopAfterTool calls stop once the current tool call, if any, has finished
+func (s *claudeStream) stopAfterTool(stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stop = stop
+	if len(s.pending) == 0 {
+		stop()
+	}
+}
+
+// stopRequested reports whether the run was asked to stop
+func (s *claudeStream) stopRequested() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stop != nil
+}
+
+// consume reads lines from reader, renders each to console and records it
+func (s *claudeStream) consume(reader io.Reader, console io.Writer) {
+	scanner := bufio.NewScanner(reader)
+	// stream-json puts whole messages, including tool results, on one line
+	scanner.Buffer(make([]byte, ScannerInitialBufSize), 16*ScannerMaxBufSize)
+
+	for scanner.Scan() {
+		if rendered := s.handleLine(scanner.Text()); rendered != "" {
+			fmt.Fprintln(console, rendered)
+		}
+	}
+}
+
+// handleLine records one line of output and returns its console rendering
+func (s *claudeStream) handleLine(line string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var event claudeStreamEvent
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") || json.Unmarshal([]byte(line), &event) != nil || event.Type == "" {
+		s.raw.WriteString(line + "\n")
+		s.transcript.WriteString(line + "\n")
+		if s.events == 0 {
+			// Plain text output (no events yet) is scraped for signals like other providers
+			s.watcher.observe(line)
+		}
+		return line
+	}
+	s.events++
+
+	var rendered []string
+	switch event.Type {
+	case "assistant":
+		if event.Message == nil {
+			break
+		}
+		for _, block := range event.Message.Content {
+			switch block.Type {
+			case "text":
+				s.text.WriteString(block.Text + "\n")
+				s.watcher.observe(block.Text)
+				rendered = append(rendered, block.Text)
+			case "tool_use":
+				tool := ToolEvent{Name: block.Name, Input: summarizeToolInput(block.Input)}
+				s.toolIndex[block.ID] = len(s.tools)
+				s.pending[block.ID] = true
+				s.tools = append(s.tools, tool)
+				rendered = append(rendered, formatToolEvent(tool))
+			}
+		}
+	case "user":
+		if event.Message == nil {
+			break
+		}
+		for _, block := range event.Message.Content {
+			if block.Type != "tool_result" {
+				continue
+			}
+			delete(s.pending, block.ToolUseID)
+			if !block.IsError {
+				continue
+			}
+			if i, ok := s.toolIndex[block.ToolUseID]; ok {
+				s.tools[i].IsError = true
+				rendered = append(rendered, fmt.Sprintf("✗ %s failed", s.tools[i].Name))
+			}
+		}
+		if s.stop != nil && len(s.pending) == 0 {
+			s.stop()
+		}
+	case "result":
+		final := event
+		s.final = &final
+		if event.IsError && event.Result != "" {
+			rendered = append(rendered, "Error: "+event.Result)
+		}
+	}
+
+	out := strings.Join(rendered, "\n")
+	if out != "" {
+		s.transcript.WriteString(out + "\n")
+	}
+	return out
+}
+
+// Output returns the human-readable transcript of the run
+func (s *claudeStream) Output() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transcript.String()
+}
+
+// apply fills usage, tool events and signals into result.
+// Signals are only read from assistant text, so file contents or command output
+// that happen to contain a <promise> tag can't end the iteration. Rate limits are
+// only read from error text, so code or prose mentioning "429" can't trigger a wait.
+// Output that contains no events falls back to plain text scraping.
+func (s *claudeStream) apply(result *RunResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.events == 0 {
+		parseSignals(result)
+		return
+	}
+
+	result.ToolEvents = s.tools
+	if s.final != nil {
+		result.CostUSD = s.final.TotalCostUSD
+		if u := s.final.Usage; u != nil {
+			result.InputTokens = u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+			result.OutputTokens = u.OutputTokens
+		}
+	}
+
+	signals := &RunResult{Output: s.text.String()}
+	parsePromiseSignals(signals)
+	result.Complete = signals.Complete
+	result.Continue = signals.Continue
+	result.CommitMessage = signals.CommitMessage
+	result.Blocked = signals.Blocked
+	result.BlockedReason = signals.BlockedReason
+
+	if s.final != nil && !s.final.IsError && result.Error == nil {
+		return
+	}
+	if s.final == nil && result.StoppedEarly {
+		// Stopped after signalling, not a failure
+		return
+	}
+
+	errorText := s.raw.String()
+	if s.final == nil {
+		// The run died mid-stream; the error may be anywhere in the transcript
+		errorText = s.transcript.String()
+	} else if s.final.IsError {
+		errorText += s.final.Result + "\n"
+	}
+	limits := &RunResult{Output: errorText, ExitCode: result.ExitCode, Error: result.Error}
+	parseRateLimit(limits)
+	result.RateLimited = limits.RateLimited
+	result.RetryAfter = limits.RetryAfter
+	result.OverloadExhausted = limits.OverloadExhausted
+}
+
+// summarizeToolInput picks the most descriptive field of a tool input
+func summarizeToolInput(input json.RawMessage) string {
+	if len(input) == 0 {
+		return ""
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(input, &fields); err == nil {
+		for _, key := range toolInputKeys {
+			if value, ok := fields[key].(string); ok && value != "" {
+				return truncateSummary(value)
+			}
+		}
+	}
+	return truncateSummary(string(input))
+}
+
+// truncateSummary flattens a summary to one line and caps its length
+func truncateSummary(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > toolInputSummaryLen {
+		return string(runes[:toolInputSummaryLen-3]) + "..."
+	}
+	return s
+}
+
+// formatToolEvent renders a tool call for console output
+func formatToolEvent(tool ToolEvent) string {
+	if tool.Input == "" {
+		return "→ " + tool.Name
+	}
+	return fmt.Sprintf("→ %s: %s", tool.Name, tool.Input)
+}
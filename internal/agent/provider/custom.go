@@ -0,0 +1,357 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholders substituted in custom provider argument templates
+const (
+	PlaceholderPrompt       = "{{prompt}}"
+	PlaceholderModel        = "{{model}}"
+	PlaceholderSystemPrompt = "{{system_prompt}}"
+)
+
+// Prompt delivery modes for custom providers
+const (
+	PromptViaStdin = "stdin"
+	PromptViaArg   = "arg"
+)
+
+// CustomConfig describes a user-defined agent CLI.
+//
+// Argument templates may contain {{prompt}}, {{model}} and {{system_prompt}}.
+// An argument that is exactly a placeholder is dropped when the value is empty.
+type CustomConfig struct {
+	Binary          string              // Executable name or path
+	Args            []string            // Headless argument template
+	PromptVia       string              // "stdin" (default) or "arg"
+	ModelFlag       string              // Flag used to pass the model, e.g. "--model" (empty = don't pass)
+	Models          map[string]string   // Canonical model name -> CLI model name
+	PermissionArgs  map[string][]string // PermissionMode -> extra arguments
+	InteractiveArgs []string            // Interactive argument template (empty = interactive unsupported)
+}
+
+var (
+	customMu        sync.RWMutex
+	customProviders = map[string]CustomConfig{}
+)
+
+// RegisterCustom makes a custom provider available under the given name.
+// Built-in provider names cannot be overridden.
+func RegisterCustom(name string, cfg CustomConfig) error {
+	if isBuiltin(Type(name)) {
+		return fmt.Errorf("custom provider %q conflicts with a built-in provider", name)
+	}
+	if cfg.Binary == "" {
+		return fmt.Errorf("custom provider %q has no binary", name)
+	}
+	if cfg.PromptVia != "" && cfg.PromptVia != PromptViaStdin && cfg.PromptVia != PromptViaArg {
+		return fmt.Errorf("custom provider %q has invalid prompt_via %q (must be 'stdin' or 'arg')", name, cfg.PromptVia)
+	}
+
+	customMu.Lock()
+	defer customMu.Unlock()
+	customProviders[name] = cfg
+	return nil
+}
+
+// UnregisterCustom removes a custom provider
+func UnregisterCustom(name string) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	delete(customProviders, name)
+}
+
+// ClearCustom removes all registered custom providers
+func ClearCustom() {
+	customMu.Lock()
+	defer customMu.Unlock()
+	customProviders = map[string]CustomConfig{}
+}
+
+// CustomProviders returns the names of registered custom providers, sorted
+func CustomProviders() []string {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	names := make([]string, 0, len(customProviders))
+	for name := range customProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupCustom returns the config for a registered custom provider
+func lookupCustom(p Type) (CustomConfig, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	cfg, ok := customProviders[string(p)]
+	return cfg, ok
+}
+
+// CustomProvider implements Provider for an agent CLI described in config
+type CustomProvider struct {
+	name   string
+	config CustomConfig
+}
+
+// NewCustomProvider creates a provider from a custom config
+func NewCustomProvider(name string, cfg CustomConfig) *CustomProvider {
+	return &CustomProvider{name: name, config: cfg}
+}
+
+// Type returns the custom provider's configured name
+func (c *CustomProvider) Type() Type {
+	return Type(c.name)
+}
+
+// binary returns the CLI to run: an overridden path or the configured binary
+func (c *CustomProvider) binary() string {
+	if path := binaryPath(c.Type()); path != "" {
+		return path
+	}
+	return c.config.Binary
+}
+
+// MapModel converts canonical model name using the configured model map
+func (c *CustomProvider) MapModel(canonical string) string {
+	if mapped, ok := c.config.Models[canonical]; ok {
+		return mapped
+	}
+	return canonical
+}
+
+// MapPermission returns the first configured argument for the permission mode.
+// The full argument list is applied when building the command.
+func (c *CustomProvider) MapPermission(mode PermissionMode) (flag, value string) {
+	args := c.config.PermissionArgs[string(mode)]
+	if len(args) == 0 {
+		return "", ""
+	}
+	if len(args) > 1 {
+		return args[0], args[1]
+	}
+	return args[0], ""
+}
+
+// ListModels returns the CLI model names from the configured model map.
+// Returns nil when no map is configured, since any model may be valid.
+func (c *CustomProvider) ListModels() []string {
+	if len(c.config.Models) == 0 {
+		return nil
+	}
+	models := make([]string, 0, len(c.config.Models))
+	for _, m := range c.config.Models {
+		models = append(models, m)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// SupportsInteractive returns true if interactive_args is configured
+func (c *CustomProvider) SupportsInteractive() bool {
+	return len(c.config.InteractiveArgs) > 0
+}
+
+// SupportsSystemPrompt returns true if the headless template has a {{system_prompt}} slot
+func (c *CustomProvider) SupportsSystemPrompt() bool {
+	return systemPromptInTemplate(c.config.Args)
+}
+
+// SupportsTools returns true
+func (c *CustomProvider) SupportsTools() bool {
+	return true
+}
+
+// Run executes the custom CLI with the given options
+func (c *CustomProvider) Run(opts RunOptions) (*RunResult, error) {
+	if opts.Mode == ModeInteractive {
+		return c.runInteractive(opts)
+	}
+	return c.runHeadless(opts)
+}
+
+// buildArgs expands an argument template and appends model, permission and prompt arguments
+func (c *CustomProvider) buildArgs(template []string, opts RunOptions, promptAsArg bool) []string {
+	model := ""
+	if opts.Model != "" {
+		model = c.MapModel(opts.Model)
+	}
+
+	values := map[string]string{
+		PlaceholderPrompt:       opts.Prompt,
+		PlaceholderModel:        model,
+		PlaceholderSystemPrompt: opts.SystemPrompt,
+	}
+	// Single pass so placeholder text inside a substituted prompt is left alone
+	replacer := strings.NewReplacer(
+		PlaceholderPrompt, opts.Prompt,
+		PlaceholderModel, model,
+		PlaceholderSystemPrompt, opts.SystemPrompt,
+	)
+
+	var args []string
+	promptInTemplate := false
+	for _, arg := range template {
+		if strings.Contains(arg, PlaceholderPrompt) {
+			promptInTemplate = true
+		}
+		if value, ok := values[arg]; ok && value == "" {
+			continue
+		}
+		args = append(args, replacer.Replace(arg))
+	}
+
+	if c.config.ModelFlag != "" && model != "" {
+		args = append(args, c.config.ModelFlag, model)
+	}
+
+	args = append(args, c.config.PermissionArgs[string(opts.Permission)]...)
+
+	if promptAsArg && !promptInTemplate && opts.Prompt != "" {
+		args = append(args, opts.Prompt)
+	}
+
+	return args
+}
+
+// systemPromptInTemplate reports whether the template consumes the system prompt
+func systemPromptInTemplate(template []string) bool {
+	for _, arg := range template {
+		if strings.Contains(arg, PlaceholderSystemPrompt) {
+			return true
+		}
+	}
+	return false
+}
+
+// runHeadless executes the custom CLI with captured output
+func (c *CustomProvider) runHeadless(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
+
+	// Without a template slot for it, the system prompt is prepended to the prompt
+	if opts.SystemPrompt != "" && !systemPromptInTemplate(c.config.Args) {
+		opts.Prompt = opts.SystemPrompt + "\n\n" + opts.Prompt
+		opts.SystemPrompt = ""
+	}
+
+	viaArg := c.config.PromptVia == PromptViaArg
+	args := c.buildArgs(c.config.Args, opts, viaArg)
+
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
+
+	// Separate cancel so the watcher can stop the agent early without reporting a timeout
+	runCtx, stopRun := context.WithCancel(ctx)
+	defer stopRun()
+
+	cmd := agentCommand(runCtx, opts, c.binary(), args)
+
+	var stdin io.WriteCloser
+	if !viaArg {
+		var err error
+		stdin, err = cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+		}
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	// Start command
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", c.binary(), err)
+	}
+
+	// Write prompt to stdin
+	if stdin != nil {
+		go func() {
+			defer stdin.Close()
+			io.WriteString(stdin, opts.Prompt)
+		}()
+	}
+
+	// Stream output to console and capture, watching for signals
+	watcher := newSignalWatcher(stopRun, stdout, stderr)
+	result.Output, err = waitWatched(cmd, stdout, stderr, watcher)
+	if finishRun(ctx, opts, result, c.binary(), err, watcher.release()) {
+		return result, nil
+	}
+
+	// Parse signals - the prompt instructs any LLM to emit the same <promise> format
+	parseSignals(result)
+
+	return result, nil
+}
+
+// runInteractive executes the custom CLI attached to the terminal
+func (c *CustomProvider) runInteractive(opts RunOptions) (*RunResult, error) {
+	if len(c.config.InteractiveArgs) == 0 {
+		return nil, fmt.Errorf("custom provider %q does not support interactive mode (no interactive_args configured)", c.name)
+	}
+
+	result := &RunResult{}
+
+	if opts.SystemPrompt != "" && !systemPromptInTemplate(c.config.InteractiveArgs) {
+		opts.Prompt = opts.SystemPrompt + "\n\n" + opts.Prompt
+		opts.SystemPrompt = ""
+	}
+
+	// The terminal owns stdin, so the prompt always goes on the command line
+	args := c.buildArgs(c.config.InteractiveArgs, opts, true)
+
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
+
+	cmd := agentCommand(ctx, opts, c.binary(), args)
+
+	// Inherit terminal for full TUI
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Start command
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", c.binary(), err)
+	}
+
+	// Wait for command to complete
+	err := cmd.Wait()
+
+	if err != nil {
+		// Check if this was a timeout
+		if ctx.Err() == context.DeadlineExceeded {
+			result.TimedOut = true
+			result.Error = fmt.Errorf("session timed out after %v", opts.Timeout)
+			return result, nil
+		}
+		if ctx.Err() == context.Canceled {
+			result.Error = fmt.Errorf("session cancelled: %w", context.Cause(ctx))
+			return result, nil
+		}
+
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Error = fmt.Errorf("%s exited with error: %w", c.binary(), err)
+	}
+
+	return result, nil
+}
@@ -1,9 +1,38 @@
 package provider
 
 import (
+	"fmt"
+	"os"
 	"os/exec"
+	"sync"
 )
 
+var (
+	binaryPathsMu sync.RWMutex
+	binaryPaths   = map[Type]string{}
+)
+
+// SetBinaryPath runs the provider from path instead of looking its binary up in PATH
+func SetBinaryPath(p Type, path string) {
+	binaryPathsMu.Lock()
+	defer binaryPathsMu.Unlock()
+	binaryPaths[p] = path
+}
+
+// binaryPath returns the provider's overridden binary path, if any
+func binaryPath(p Type) string {
+	binaryPathsMu.RLock()
+	defer binaryPathsMu.RUnlock()
+	return binaryPaths[p]
+}
+
+// ClearBinaryPaths removes all binary path overrides
+func ClearBinaryPaths() {
+	binaryPathsMu.Lock()
+	defer binaryPathsMu.Unlock()
+	binaryPaths = map[Type]string{}
+}
+
 // Detect determines the provider type based on config settings.
 // Resolution order (highest to lowest priority):
 //  1. CLI flag override (if set)
@@ -39,8 +68,13 @@ func Detect(cliOverride, projectProvider, globalProvider string) Type {
 	return TypeClaude
 }
 
-// IsAvailable checks if a provider's binary is available in PATH
+// IsAvailable checks if a provider's binary is available in PATH, or is an
+// executable file when its path is overridden. The API provider needs no
+// binary and is available when $ANTHROPIC_API_KEY is set.
 func IsAvailable(p Type) bool {
+	if p == TypeAPI {
+		return os.Getenv(EnvAnthropicAPIKey) != ""
+	}
 	binary := BinaryName(p)
 	if binary == "" {
 		return false
@@ -49,14 +83,34 @@ func IsAvailable(p Type) bool {
 	return err == nil
 }
 
-// BinaryName returns the executable name for a provider
+// UnavailableReason explains why IsAvailable(p) is false, for error messages
+func UnavailableReason(p Type) string {
+	if p == TypeAPI {
+		return EnvAnthropicAPIKey + " is not set"
+	}
+	return fmt.Sprintf("binary %q not found", BinaryName(p))
+}
+
+// BinaryName returns the executable name for a provider, or its path when
+// overridden with SetBinaryPath
 func BinaryName(p Type) string {
+	if path := binaryPath(p); path != "" {
+		return path
+	}
+
 	switch p {
 	case TypeClaude:
 		return "claude"
 	case TypeOpenCode:
 		return "opencode"
+	case TypeGoose:
+		return "goose"
+	case TypeAmp:
+		return "amp"
 	default:
+		if cfg, ok := lookupCustom(p); ok {
+			return cfg.Binary
+		}
 		return ""
 	}
 }
@@ -66,9 +120,18 @@ func Get(providerType Type) Provider {
 	switch providerType {
 	case TypeOpenCode:
 		return NewOpenCodeProvider()
+	case TypeGoose:
+		return NewGooseProvider()
+	case TypeAmp:
+		return NewAmpProvider()
+	case TypeAPI:
+		return NewAPIProvider()
 	case TypeClaude:
-		fallthrough
+		return NewClaudeProvider()
 	default:
+		if cfg, ok := lookupCustom(providerType); ok {
+			return NewCustomProvider(string(providerType), cfg)
+		}
 		return NewClaudeProvider()
 	}
 }
@@ -96,10 +159,14 @@ func ApplyModelOverrides(canonical string, overrides ModelOverrides, p Provider)
 	return p.MapModel(canonical)
 }
 
-// ValidProviders returns the list of valid provider type strings
+// ValidProviders returns the list of built-in provider type strings.
+// Registered custom providers are listed by CustomProviders.
 func ValidProviders() []string {
 	return []string{
 		string(TypeClaude),
 		string(TypeOpenCode),
+		string(TypeGoose),
+		string(TypeAmp),
+		string(TypeAPI),
 	}
 }
@@ -0,0 +1,315 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// GooseProvider implements Provider for Block's goose CLI
+type GooseProvider struct{}
+
+// NewGooseProvider creates a new goose provider
+func NewGooseProvider() *GooseProvider {
+	return &GooseProvider{}
+}
+
+// Type returns TypeGoose
+func (g *GooseProvider) Type() Type {
+	return TypeGoose
+}
+
+// MapModel converts canonical model name to goose format.
+// Goose takes the bare model ID and resolves the backend from its own config.
+func (g *GooseProvider) MapModel(canonical string) string {
+	switch canonical {
+	case "haiku", "small":
+		return "claude-3-5-haiku-latest"
+	case "sonnet", "medium":
+		return "claude-sonnet-4-5"
+	case "opus", "large":
+		return "claude-opus-4-5"
+	default:
+		return canonical
+	}
+}
+
+// MapPermission converts PermissionMode to goose's GOOSE_MODE environment variable.
+// Goose has no read-only agent, so plan maps to chat mode (no tool use).
+// Approval modes would block on stdin, so edits always run in auto mode.
+func (g *GooseProvider) MapPermission(mode PermissionMode) (flag, value string) {
+	switch mode {
+	case PermissionPlan:
+		return "GOOSE_MODE", "chat"
+	default:
+		return "GOOSE_MODE", "auto"
+	}
+}
+
+// ListModels returns nil: goose accepts any model of its configured provider
+func (g *GooseProvider) ListModels() []string {
+	return nil
+}
+
+// SupportsInteractive returns true
+func (g *GooseProvider) SupportsInteractive() bool {
+	return true
+}
+
+// SupportsSystemPrompt returns true (--system)
+func (g *GooseProvider) SupportsSystemPrompt() bool {
+	return true
+}
+
+// SupportsTools returns true
+func (g *GooseProvider) SupportsTools() bool {
+	return true
+}
+
+// Run executes goose CLI with the given options
+func (g *GooseProvider) Run(opts RunOptions) (*RunResult, error) {
+	if opts.Mode == ModeInteractive {
+		return g.runInteractive(opts)
+	}
+	return g.runHeadless(opts)
+}
+
+// buildArgs returns the `goose run` arguments shared by headless and interactive modes
+func (g *GooseProvider) buildArgs(opts RunOptions, sessionName string) []string {
+	args := []string{"run", "--name", sessionName}
+
+	if opts.SystemPrompt != "" {
+		args = append(args, "--system", opts.SystemPrompt)
+	}
+
+	if opts.Model != "" {
+		args = append(args, "--model", g.MapModel(opts.Model))
+	}
+
+	return args
+}
+
+// permissionEnv returns the GOOSE_MODE entry for the run's permission mode
+func (g *GooseProvider) permissionEnv(opts RunOptions) string {
+	key, value := g.MapPermission(opts.Permission)
+	return key + "=" + value
+}
+
+// runHeadless executes goose in headless mode (goose run -i -)
+func (g *GooseProvider) runHeadless(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
+
+	// Name the session so the export can be located after the run
+	sessionName := newGooseSessionName()
+	args := g.buildArgs(opts, sessionName)
+
+	// Headless mode: read instructions from stdin
+	args = append(args, "-i", "-")
+
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
+
+	// Separate cancel so the watcher can stop the agent early without reporting a timeout
+	runCtx, stopRun := context.WithCancel(ctx)
+	defer stopRun()
+
+	cmd := agentCommand(runCtx, opts, BinaryName(TypeGoose), args, g.permissionEnv(opts))
+
+	// Pipe prompt through stdin
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	// Start command
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start goose: %w", err)
+	}
+
+	// Write prompt to stdin
+	go func() {
+		defer stdin.Close()
+		io.WriteString(stdin, opts.Prompt)
+	}()
+
+	// Stream output to console and capture, watching for signals
+	watcher := newSignalWatcher(stopRun, stdout, stderr)
+	result.Output, err = waitWatched(cmd, stdout, stderr, watcher)
+	if finishRun(ctx, opts, result, "goose", err, watcher.release()) {
+		return result, nil
+	}
+
+	// Parse signals - same format as Claude since the prompt instructs the LLM
+	parseSignals(result)
+
+	// Signal recovery: goose renders markdown to the terminal, which can mangle
+	// the <promise> tags, so fall back to the raw session export
+	if !result.Complete && !result.Continue && !result.Blocked && !result.RateLimited && result.Error == nil {
+		if recovered := g.recoverSignalsFromExport(sessionName, opts.WorkingDir); recovered != nil {
+			result.Complete = recovered.Complete
+			result.Continue = recovered.Continue
+			result.CommitMessage = recovered.CommitMessage
+			result.Blocked = recovered.Blocked
+			result.BlockedReason = recovered.BlockedReason
+		}
+	}
+
+	return result, nil
+}
+
+// runInteractive executes goose in interactive mode (goose run --interactive)
+func (g *GooseProvider) runInteractive(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
+
+	args := g.buildArgs(opts, newGooseSessionName())
+	args = append(args, "--interactive")
+
+	if opts.Prompt != "" {
+		args = append(args, "--text", opts.Prompt)
+	}
+
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
+
+	cmd := agentCommand(ctx, opts, BinaryName(TypeGoose), args, g.permissionEnv(opts))
+
+	// Inherit terminal for full TUI
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Start command
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start goose: %w", err)
+	}
+
+	// Wait for command to complete
+	err := cmd.Wait()
+
+	if err != nil {
+		// Check if this was a timeout
+		if ctx.Err() == context.DeadlineExceeded {
+			result.TimedOut = true
+			result.Error = fmt.Errorf("session timed out after %v", opts.Timeout)
+			return result, nil
+		}
+		if ctx.Err() == context.Canceled {
+			result.Error = fmt.Errorf("session cancelled: %w", context.Cause(ctx))
+			return result, nil
+		}
+
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Error = fmt.Errorf("goose exited with error: %w", err)
+	}
+
+	return result, nil
+}
+
+// newGooseSessionName returns a unique goose session name for one juggle run
+func newGooseSessionName() string {
+	return fmt.Sprintf("juggle-%d", time.Now().UnixNano())
+}
+
+// recoverSignalsFromExport attempts to recover missed <promise> signals by
+// running `goose session export` on the named session.
+func (g *GooseProvider) recoverSignalsFromExport(sessionName, workingDir string) *RunResult {
+	exportOutput, err := g.runGooseExport(sessionName, workingDir)
+	if err != nil || exportOutput == "" {
+		return nil
+	}
+
+	lastAssistantText := extractGooseLastAssistantText(exportOutput)
+	if lastAssistantText == "" {
+		return nil
+	}
+
+	recovered := &RunResult{Output: lastAssistantText}
+	parseSignals(recovered)
+
+	if recovered.Complete || recovered.Continue || recovered.Blocked {
+		fmt.Fprintf(os.Stderr, "[juggle] Recovered signal from goose export (session %s)\n", sessionName)
+		return recovered
+	}
+
+	return nil
+}
+
+// runGooseExport runs `goose session export` for the named session and returns the JSON output
+func (g *GooseProvider) runGooseExport(sessionName, workingDir string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, BinaryName(TypeGoose), "session", "export", "--name", sessionName, "--format", "json")
+	if workingDir != "" {
+		cmd.Dir = workingDir
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+// gooseExport represents the JSON structure of a goose session export
+type gooseExport struct {
+	Messages []gooseMessage `json:"messages"`
+}
+
+// gooseMessage represents a single message in a goose session
+type gooseMessage struct {
+	Role    string         `json:"role"`
+	Content []gooseContent `json:"content"`
+}
+
+// gooseContent represents a content block of a goose message
+type gooseContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// extractGooseLastAssistantText parses export JSON and returns the concatenated
+// text blocks of the last assistant message
+func extractGooseLastAssistantText(exportJSON string) string {
+	var export gooseExport
+	if err := json.Unmarshal([]byte(exportJSON), &export); err != nil {
+		return ""
+	}
+
+	for i := len(export.Messages) - 1; i >= 0; i-- {
+		msg := export.Messages[i]
+		if msg.Role != "assistant" {
+			continue
+		}
+
+		var texts []string
+		for _, c := range msg.Content {
+			if c.Type == "text" && c.Text != "" {
+				texts = append(texts, c.Text)
+			}
+		}
+		return strings.Join(texts, "\n")
+	}
+
+	return ""
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// I/O scheduling classes for ResourceLimits.IOClass
+const (
+	IOClassIdle       = "idle"        // Disk access only when nothing else wants it
+	IOClassBestEffort = "best-effort" // Normal scheduling at the lowest priority
+)
+
+// ResourceLimits caps the CPU, disk and memory an agent CLI and everything it
+// runs may take, so long unattended runs leave the machine usable. Each limit
+// is applied with the tool this machine has for it (nice, ionice, a systemd
+// cgroup scope or prlimit) and skipped where there is none; Describe says
+// which ones apply.
+type ResourceLimits struct {
+	Nice      int    // Niceness to run at, 1-19 (0 = unchanged)
+	IOClass   string // IOClassIdle or IOClassBestEffort ("" = unchanged)
+	MaxMemory int64  // Memory limit in bytes (0 = unlimited)
+}
+
+// limitTools finds the programs that apply limits. Tests replace it.
+var limitTools = struct {
+	lookPath     func(file string) (string, error)
+	systemdScope func() bool // Whether systemd-run can start a user scope
+}{
+	lookPath:     exec.LookPath,
+	systemdScope: systemdScopeWorks,
+}
+
+var (
+	systemdScopeOnce sync.Once
+	systemdScopeOK   bool
+)
+
+// systemdScopeWorks reports whether transient user scopes can be created,
+// which needs systemd-run and a running user manager. Checked once per process.
+func systemdScopeWorks() bool {
+	systemdScopeOnce.Do(func() {
+		if _, err := exec.LookPath("systemd-run"); err != nil {
+			return
+		}
+		systemdScopeOK = exec.Command("systemd-run", "--user", "--scope", "--quiet", "true").Run() == nil
+	})
+	return systemdScopeOK
+}
+
+// plan returns the command prefix that applies the limits on this machine,
+// and a note on each limit saying how it applies or why it doesn't
+func (l *ResourceLimits) plan() (prefix, notes []string) {
+	var memoryNote, ioNote, niceNote string
+	has := func(tool string) bool {
+		_, err := limitTools.lookPath(tool)
+		return err == nil
+	}
+	linux := runtime.GOOS == "linux"
+
+	if l.MaxMemory > 0 {
+		size := FormatMemorySize(l.MaxMemory)
+		switch {
+		case linux && limitTools.systemdScope():
+			prefix = append(prefix, "systemd-run", "--user", "--scope", "--quiet", "-p", "MemoryMax="+strconv.FormatInt(l.MaxMemory, 10), "--")
+			memoryNote = "memory " + size + " (cgroup)"
+		case linux && has("prlimit"):
+			prefix = append(prefix, "prlimit", "--data="+strconv.FormatInt(l.MaxMemory, 10), "--")
+			memoryNote = "memory " + size + " (rlimit)"
+		default:
+			memoryNote = "memory " + size + " not applied (needs systemd-run or prlimit)"
+		}
+	}
+
+	if l.IOClass != "" {
+		switch {
+		case linux && has("ionice"):
+			if l.IOClass == IOClassIdle {
+				prefix = append(prefix, "ionice", "-c", "3", "--")
+			} else {
+				prefix = append(prefix, "ionice", "-c", "2", "-n", "7", "--")
+			}
+			ioNote = l.IOClass + " I/O"
+		default:
+			ioNote = l.IOClass + " I/O not applied (needs ionice)"
+		}
+	}
+
+	if l.Nice > 0 {
+		if has("nice") {
+			prefix = append(prefix, "nice", "-n", strconv.Itoa(l.Nice))
+			niceNote = fmt.Sprintf("nice %d", l.Nice)
+		} else {
+			niceNote = fmt.Sprintf("nice %d not applied (needs nice)", l.Nice)
+		}
+	}
+
+	for _, note := range []string{niceNote, ioNote, memoryNote} {
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+	return prefix, notes
+}
+
+// wrap returns the command that runs name with args under the limits
+func (l *ResourceLimits) wrap(name string, args []string) (string, []string) {
+	prefix, _ := l.plan()
+	if len(prefix) == 0 {
+		return name, args
+	}
+	return prefix[0], append(append(prefix[1:], name), args...)
+}
+
+// Describe summarizes the limits and how they apply on this machine, e.g.
+// "nice 10, idle I/O, memory 4G (cgroup)"
+func (l *ResourceLimits) Describe() string {
+	_, notes := l.plan()
+	return strings.Join(notes, ", ")
+}
+
+// ParseMemorySize parses a memory size such as "512M", "4G" or "4GiB" into
+// bytes. Units are powers of 1024; a bare number is bytes.
+func ParseMemorySize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "IB"), "B")
+	multiplier := int64(1)
+	if n := len(value); n > 0 {
+		switch value[n-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+		if multiplier > 1 {
+			value = value[:n-1]
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid memory size %q (want e.g. 512M or 4G)", s)
+	}
+	return n * multiplier, nil
+}
+
+// FormatMemorySize formats bytes in the largest unit that divides them, e.g. "4G"
+func FormatMemorySize(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}} {
+		if bytes >= unit.size && bytes%unit.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.size, unit.suffix)
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -55,6 +54,26 @@ func (o *OpenCodeProvider) MapPermission(mode PermissionMode) (flag, value strin
 	}
 }
 
+// ListModels returns nil: OpenCode accepts any provider/model identifier
+func (o *OpenCodeProvider) ListModels() []string {
+	return nil
+}
+
+// SupportsInteractive returns true
+func (o *OpenCodeProvider) SupportsInteractive() bool {
+	return true
+}
+
+// SupportsSystemPrompt returns false: OpenCode has no system prompt flag
+func (o *OpenCodeProvider) SupportsSystemPrompt() bool {
+	return false
+}
+
+// SupportsTools returns true
+func (o *OpenCodeProvider) SupportsTools() bool {
+	return true
+}
+
 // Run executes OpenCode CLI with the given options
 func (o *OpenCodeProvider) Run(opts RunOptions) (*RunResult, error) {
 	if opts.Mode == ModeInteractive {
@@ -82,22 +101,15 @@ func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 	// OpenCode takes prompt as argument, not stdin
 	args = append(args, opts.Prompt)
 
-	// Create context with timeout if specified
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if opts.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
-		defer cancel()
-	} else {
-		ctx = context.Background()
-	}
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "opencode", args...)
-	if opts.WorkingDir != "" {
-		cmd.Dir = opts.WorkingDir
-	}
+	// Separate cancel so the watcher can stop the agent early without reporting a timeout
+	runCtx, stopRun := context.WithCancel(ctx)
+	defer stopRun()
 
-	var outputBuf strings.Builder
+	cmd := agentCommand(runCtx, opts, BinaryName(TypeOpenCode), args)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -114,44 +126,25 @@ func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 		return nil, fmt.Errorf("failed to start opencode: %w", err)
 	}
 
-	// Stream output to console and capture
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		streamOutput(stdout, &outputBuf, os.Stdout)
-	}()
-	go func() {
-		defer wg.Done()
-		streamOutput(stderr, &outputBuf, os.Stderr)
-	}()
-
-	// Wait for command to complete
-	err = cmd.Wait()
-	wg.Wait()
-	result.Output = outputBuf.String()
-
-	if err != nil {
-		// Check if this was a timeout
-		if ctx.Err() == context.DeadlineExceeded {
-			result.TimedOut = true
-			result.Error = fmt.Errorf("iteration timed out after %v", opts.Timeout)
-			return result, nil
-		}
-
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
-		}
-		result.Error = fmt.Errorf("opencode exited with error: %w", err)
+	// Stream output to console and capture, watching for signals
+	watcher := newSignalWatcher(stopRun, stdout, stderr)
+	result.Output, err = waitWatched(cmd, stdout, stderr, watcher)
+	if finishRun(ctx, opts, result, "opencode", err, watcher.release()) {
+		return result, nil
 	}
 
 	// Parse signals - same format as Claude since the prompt instructs the LLM
 	parseSignals(result)
 
+	// OpenCode only reports usage in its session export, which also holds any
+	// signals that stdout lost
+	sessionID, export := o.exportLatestSession(opts.WorkingDir)
+	applyExportUsage(result, export)
+
 	// Signal recovery: if no signal found in stdout, try opencode export
 	// OpenCode's stdout capture is unreliable - signals may be lost
 	if !result.Complete && !result.Continue && !result.Blocked && !result.RateLimited && result.Error == nil {
-		if recovered := o.recoverSignalsFromExport(opts.WorkingDir); recovered != nil {
+		if recovered := o.recoverSignalsFromExport(sessionID, export); recovered != nil {
 			if recovered.Complete {
 				result.Complete = true
 				result.CommitMessage = recovered.CommitMessage
@@ -194,20 +187,11 @@ func (o *OpenCodeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 		args = append(args, "--prompt", opts.Prompt)
 	}
 
-	// Create context with timeout if specified
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if opts.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
-		defer cancel()
-	} else {
-		ctx = context.Background()
-	}
+	// Create context with timeout and caller cancellation
+	ctx, cancel := runContext(opts)
+	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "opencode", args...)
-	if opts.WorkingDir != "" {
-		cmd.Dir = opts.WorkingDir
-	}
+	cmd := agentCommand(ctx, opts, BinaryName(TypeOpenCode), args)
 
 	// Inherit terminal for full TUI
 	cmd.Stdin = os.Stdin
@@ -229,6 +213,10 @@ func (o *OpenCodeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 			result.Error = fmt.Errorf("session timed out after %v", opts.Timeout)
 			return result, nil
 		}
+		if ctx.Err() == context.Canceled {
+			result.Error = fmt.Errorf("session cancelled: %w", context.Cause(ctx))
+			return result, nil
+		}
 
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			result.ExitCode = exitErr.ExitCode()
@@ -287,19 +275,26 @@ func (o *OpenCodeProvider) parseRateLimit(result *RunResult) {
 	o.parseOverloadExhausted(result)
 }
 
-// recoverSignalsFromExport attempts to recover missed <promise> signals by
-// running `opencode export` on the most recent session. This handles the common
-// case where OpenCode's stdout doesn't reliably flush the LLM's signal output.
-func (o *OpenCodeProvider) recoverSignalsFromExport(workingDir string) *RunResult {
-	// Get the most recent session ID
-	sessionID := o.getMostRecentSession(workingDir)
+// exportLatestSession runs `opencode export` on the most recent session.
+// Returns empty strings if there is no session or the export fails.
+func (o *OpenCodeProvider) exportLatestSession(workingDir string) (sessionID, export string) {
+	sessionID = o.getMostRecentSession(workingDir)
 	if sessionID == "" {
-		return nil
+		return "", ""
 	}
 
-	// Export session data
-	exportOutput, err := o.runOpenCodeExport(sessionID, workingDir)
-	if err != nil || exportOutput == "" {
+	export, err := o.runOpenCodeExport(sessionID, workingDir)
+	if err != nil {
+		return sessionID, ""
+	}
+	return sessionID, export
+}
+
+// recoverSignalsFromExport attempts to recover missed <promise> signals from
+// the session export. This handles the common case where OpenCode's stdout
+// doesn't reliably flush the LLM's signal output.
+func (o *OpenCodeProvider) recoverSignalsFromExport(sessionID, exportOutput string) *RunResult {
+	if exportOutput == "" {
 		return nil
 	}
 
@@ -333,7 +328,7 @@ func (o *OpenCodeProvider) getMostRecentSession(workingDir string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "opencode", "session", "list")
+	cmd := exec.CommandContext(ctx, BinaryName(TypeOpenCode), "session", "list")
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
@@ -368,7 +363,7 @@ func (o *OpenCodeProvider) runOpenCodeExport(sessionID, workingDir string) (stri
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "opencode", "export", sessionID)
+	cmd := exec.CommandContext(ctx, BinaryName(TypeOpenCode), "export", sessionID)
 	if workingDir != "" {
 		cmd.Dir = workingDir
 	}
@@ -394,7 +389,43 @@ type openCodeMessage struct {
 
 // openCodeMessageInfo contains message metadata
 type openCodeMessageInfo struct {
-	Role string `json:"role"`
+	Role   string          `json:"role"`
+	Cost   float64         `json:"cost"`   // USD, assistant messages only
+	Tokens *openCodeTokens `json:"tokens"` // Assistant messages only
+}
+
+// openCodeTokens is the token usage of one assistant message
+type openCodeTokens struct {
+	Input     int `json:"input"`
+	Output    int `json:"output"`
+	Reasoning int `json:"reasoning"`
+	Cache     struct {
+		Read  int `json:"read"`
+		Write int `json:"write"`
+	} `json:"cache"`
+}
+
+// applyExportUsage sums token usage and cost over the export's assistant messages.
+// Cache reads and writes count as input and reasoning as output, matching Claude.
+func applyExportUsage(result *RunResult, exportJSON string) {
+	if exportJSON == "" {
+		return
+	}
+	var export openCodeExport
+	if err := json.Unmarshal([]byte(exportJSON), &export); err != nil {
+		return
+	}
+
+	for _, msg := range export.Messages {
+		if msg.Info.Role != "assistant" {
+			continue
+		}
+		result.CostUSD += msg.Info.Cost
+		if t := msg.Info.Tokens; t != nil {
+			result.InputTokens += t.Input + t.Cache.Read + t.Cache.Write
+			result.OutputTokens += t.Output + t.Reasoning
+		}
+	}
 }
 
 // openCodePart represents a part of a message (text, tool call, etc.)
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// toolSpamMinRun is the number of similar lines in a row that get collapsed
+	toolSpamMinRun = 5
+
+	// summaryFallbackLines is how much of the output's end extract_summary
+	// keeps when the agent wrote no summary heading
+	summaryFallbackLines = 20
+)
+
+// OutputProcessor rewrites an agent's output, e.g. to remove noise
+type OutputProcessor func(output string) string
+
+// outputProcessors are the post-processors available to output_processors
+var outputProcessors = map[string]OutputProcessor{
+	"strip_ansi":         StripANSI,
+	"collapse_tool_spam": CollapseToolSpam,
+	"extract_summary":    ExtractSummary,
+}
+
+// OutputProcessorNames returns the names of the available post-processors
+func OutputProcessorNames() []string {
+	names := make([]string, 0, len(outputProcessors))
+	for name := range outputProcessors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OutputPipeline is a sequence of post-processors applied to agent output in order
+type OutputPipeline []OutputProcessor
+
+// NewOutputPipeline builds a pipeline from post-processor names
+func NewOutputPipeline(names []string) (OutputPipeline, error) {
+	pipeline := make(OutputPipeline, 0, len(names))
+	for _, name := range names {
+		processor, ok := outputProcessors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown output processor %q (available: %s)", name, strings.Join(OutputProcessorNames(), ", "))
+		}
+		pipeline = append(pipeline, processor)
+	}
+	return pipeline, nil
+}
+
+// Apply runs the output through each post-processor. An empty pipeline
+// returns the output unchanged.
+func (p OutputPipeline) Apply(output string) string {
+	for _, processor := range p {
+		output = processor(output)
+	}
+	return output
+}
+
+// ansiPattern matches terminal escape sequences: CSI (colors, cursor
+// movement), OSC (titles, hyperlinks) and single-character escapes
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// StripANSI removes terminal escape sequences, and keeps only the last
+// rewrite of lines redrawn with carriage returns (spinners, progress bars)
+func StripANSI(output string) string {
+	output = ansiPattern.ReplaceAllString(output, "")
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if j := strings.LastIndex(line, "\r"); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CollapseToolSpam shortens runs of similar lines, such as a long series of
+// file reads or test output, to their first two lines, a count of the lines
+// left out, and the last line. Lines are similar when they start with the
+// same word, ignoring bullets and box-drawing characters; blank lines inside
+// a run are dropped with it.
+func CollapseToolSpam(output string) string {
+	lines := strings.Split(output, "\n")
+	var kept []string
+	for i := 0; i < len(lines); {
+		key := toolSpamKey(lines[i])
+		if key == "" {
+			kept = append(kept, lines[i])
+			i++
+			continue
+		}
+
+		// Extend the run over lines with the same key and blank lines between them
+		run := []string{lines[i]}
+		end := i + 1
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "" {
+				continue
+			}
+			if toolSpamKey(lines[j]) != key {
+				break
+			}
+			run = append(run, lines[j])
+			end = j + 1
+		}
+
+		if len(run) < toolSpamMinRun {
+			kept = append(kept, lines[i:end]...)
+		} else {
+			kept = append(kept, run[0], run[1],
+				fmt.Sprintf("[... %d similar lines collapsed]", len(run)-3),
+				run[len(run)-1])
+		}
+		i = end
+	}
+	return strings.Join(kept, "\n")
+}
+
+// toolSpamKey is the first word of a line after bullets and box-drawing
+// characters, up to a space, parenthesis or colon ("" for blank lines)
+func toolSpamKey(line string) string {
+	trimmed := strings.TrimLeft(line, " \t⏺●•│┃├└─|>*-+")
+	if i := strings.IndexAny(trimmed, " \t(:"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	return trimmed
+}
+
+// summaryHeading matches a line that opens the agent's closing summary,
+// e.g. "## Summary" or "Summary:"
+var summaryHeading = regexp.MustCompile(`(?i)^\s*(?:#+\s*|\*\*)?summary\b`)
+
+// ExtractSummary keeps the agent's closing summary: everything from the last
+// summary heading on, or the last 20 non-blank lines when there is none
+func ExtractSummary(output string) string {
+	if strings.TrimSpace(output) == "" {
+		return output
+	}
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if summaryHeading.MatchString(lines[i]) {
+			return strings.Join(lines[i:], "\n") + "\n"
+		}
+	}
+
+	var tail []string
+	for i := len(lines) - 1; i >= 0 && len(tail) < summaryFallbackLines; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			tail = append(tail, lines[i])
+		}
+	}
+	for i, j := 0, len(tail)-1; i < j; i, j = i+1, j-1 {
+		tail[i], tail[j] = tail[j], tail[i]
+	}
+	return strings.Join(tail, "\n") + "\n"
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	input := "\x1b[1;32m✓ tests pass\x1b[0m\n\x1b]0;title\x07Building\rBuilding.\rBuilding..\r\ndone\n"
+	want := "✓ tests pass\nBuilding..\ndone\n"
+	if got := StripANSI(input); got != want {
+		t.Errorf("StripANSI() = %q, want %q", got, want)
+	}
+}
+
+func TestCollapseToolSpam(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("Looking at the code.\n")
+	for i := 1; i <= 8; i++ {
+		fmt.Fprintf(&input, "⏺ Read(file%d.go)\n\n", i)
+	}
+	input.WriteString("Found the bug.\n- Bash: go build\n- Bash: go test\n")
+
+	got := CollapseToolSpam(input.String())
+	want := "Looking at the code.\n⏺ Read(file1.go)\n⏺ Read(file2.go)\n[... 5 similar lines collapsed]\n⏺ Read(file8.go)\n\nFound the bug.\n- Bash: go build\n- Bash: go test\n"
+	if got != want {
+		t.Errorf("CollapseToolSpam() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExtractSummary(t *testing.T) {
+	withHeading := "Reading files...\n## Summary\nOld summary\nMore work...\n## Summary\n- Fixed the parser\n<promise>CONTINUE</promise>\n"
+	if got, want := ExtractSummary(withHeading), "## Summary\n- Fixed the parser\n<promise>CONTINUE</promise>\n"; got != want {
+		t.Errorf("ExtractSummary() = %q, want %q", got, want)
+	}
+
+	var long strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&long, "line %d\n\n", i)
+	}
+	got := ExtractSummary(long.String())
+	if !strings.HasPrefix(got, "line 11\n") || !strings.HasSuffix(got, "line 30\n") || strings.Count(got, "\n") != summaryFallbackLines {
+		t.Errorf("Expected the last %d non-blank lines, got:\n%s", summaryFallbackLines, got)
+	}
+
+	if got := ExtractSummary(""); got != "" {
+		t.Errorf("Expected empty output to stay empty, got %q", got)
+	}
+}
+
+func TestNewOutputPipeline(t *testing.T) {
+	pipeline, err := NewOutputPipeline([]string{"strip_ansi", "extract_summary"})
+	if err != nil {
+		t.Fatalf("NewOutputPipeline() error: %v", err)
+	}
+	if got := pipeline.Apply("noise\n\x1b[1mSummary:\x1b[0m done\n"); got != "Summary: done\n" {
+		t.Errorf("Apply() = %q", got)
+	}
+
+	if _, err := NewOutputPipeline([]string{"strip_colour"}); err == nil || !strings.Contains(err.Error(), "strip_ansi") {
+		t.Errorf("Expected an error listing the available processors, got %v", err)
+	}
+	if got := OutputPipeline(nil).Apply("raw"); got != "raw" {
+		t.Errorf("Expected an empty pipeline to leave output unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PreflightPrompt asks for the shortest possible reply, so a preflight ping
+// costs a handful of tokens
+const PreflightPrompt = "Reply with the single word OK. Do not use any tools."
+
+var (
+	// ErrPreflightAuth means the provider rejected its credentials
+	ErrPreflightAuth = errors.New("authentication failed")
+	// ErrPreflightModel means the provider doesn't offer the requested model
+	ErrPreflightModel = errors.New("model not available")
+)
+
+// authFailurePatterns appear in agent CLI and API output when the key or
+// login is missing, invalid or expired, or the account can't be billed
+var authFailurePatterns = []string{
+	"invalid api key",
+	"invalid x-api-key",
+	"authentication_error",
+	"authentication failed",
+	"unauthorized",
+	"401",
+	"not logged in",
+	"please run /login",
+	"login required",
+	"token has expired",
+	"credit balance is too low",
+}
+
+// modelFailurePatterns appear next to "model" when the model doesn't exist
+// or the account can't use it
+var modelFailurePatterns = []string{
+	"not_found_error",
+	"not found",
+	"does not exist",
+	"invalid model",
+	"unknown model",
+	"not available",
+	"not supported",
+}
+
+// CheckPreflight inspects the outcome of a preflight ping and returns nil if
+// the provider answered, or an error wrapping ErrPreflightAuth or
+// ErrPreflightModel when the output says why it didn't. Rate limits and
+// overload are not errors: the agent loop already waits those out.
+func CheckPreflight(result *RunResult, err error) error {
+	if err == nil && result != nil {
+		err = result.Error
+	}
+
+	var text string
+	if result != nil {
+		text = result.Output
+	}
+	if err != nil {
+		text += "\n" + err.Error()
+	}
+	lower := strings.ToLower(text)
+
+	for _, pattern := range authFailurePatterns {
+		if strings.Contains(lower, pattern) {
+			return fmt.Errorf("%w: %s", ErrPreflightAuth, firstLine(text, pattern))
+		}
+	}
+	if strings.Contains(lower, "model") {
+		for _, pattern := range modelFailurePatterns {
+			if strings.Contains(lower, pattern) {
+				return fmt.Errorf("%w: %s", ErrPreflightModel, firstLine(text, pattern))
+			}
+		}
+	}
+
+	if result != nil && (result.RateLimited || result.OverloadExhausted) {
+		return nil
+	}
+	if result != nil && result.TimedOut {
+		return fmt.Errorf("no reply before the timeout")
+	}
+	if err != nil {
+		return err
+	}
+	if result == nil || (result.ExitCode != 0 && strings.TrimSpace(result.Output) == "") {
+		return fmt.Errorf("the agent exited without replying")
+	}
+	return nil
+}
+
+// firstLine returns the first line of text containing pattern (case-insensitive),
+// trimmed, to quote in an error
+func firstLine(text, pattern string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(strings.ToLower(line), pattern) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return strings.TrimSpace(text)
+}
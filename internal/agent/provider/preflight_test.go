@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckPreflight(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *RunResult
+		err     error
+		wantErr error
+		ok      bool
+	}{
+		{name: "reply", result: &RunResult{Output: "OK"}, ok: true},
+		{
+			name:    "invalid key",
+			result:  &RunResult{Output: "Invalid API key · Please run /login", ExitCode: 1, Error: errors.New("claude exited with error: exit status 1")},
+			wantErr: ErrPreflightAuth,
+		},
+		{
+			name:    "api 401",
+			err:     errors.New(`API returned 401: {"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`),
+			wantErr: ErrPreflightAuth,
+		},
+		{
+			name:    "unknown model",
+			result:  &RunResult{Output: "API Error: 404 {\"type\":\"error\",\"error\":{\"type\":\"not_found_error\",\"message\":\"model: claude-opus-9\"}}", ExitCode: 1},
+			wantErr: ErrPreflightModel,
+		},
+		{name: "rate limited", result: &RunResult{Output: "429 rate limit", RateLimited: true, ExitCode: 1}, ok: true},
+		{name: "timed out", result: &RunResult{TimedOut: true}},
+		{name: "silent crash", result: &RunResult{ExitCode: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPreflight(tt.result, tt.err)
+			if tt.ok {
+				if err != nil {
+					t.Errorf("expected preflight to pass, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected preflight to fail")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
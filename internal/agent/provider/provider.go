@@ -1,8 +1,11 @@
 // Package provider defines the interface and implementations for AI agent backends.
-// It supports multiple agent CLIs (Claude Code, OpenCode) through a common abstraction.
+// It supports multiple agent CLIs (Claude Code, OpenCode, goose, Amp) through a common abstraction,
+// plus custom providers described entirely in config and a tool-less provider that calls the
+// Anthropic API directly.
 package provider
 
 import (
+	"context"
 	"time"
 )
 
@@ -14,6 +17,12 @@ const (
 	TypeClaude Type = "claude"
 	// TypeOpenCode is the OpenCode CLI provider
 	TypeOpenCode Type = "opencode"
+	// TypeGoose is Block's goose CLI provider
+	TypeGoose Type = "goose"
+	// TypeAmp is Sourcegraph's Amp CLI provider
+	TypeAmp Type = "amp"
+	// TypeAPI calls the Anthropic messages API directly (no CLI, no tools)
+	TypeAPI Type = "api"
 )
 
 // String returns the string representation
@@ -21,9 +30,18 @@ func (p Type) String() string {
 	return string(p)
 }
 
-// IsValid returns true if the provider type is known
+// IsValid returns true if the provider type is built in or a registered custom provider
 func (p Type) IsValid() bool {
-	return p == TypeClaude || p == TypeOpenCode
+	if isBuiltin(p) {
+		return true
+	}
+	_, ok := lookupCustom(p)
+	return ok
+}
+
+// isBuiltin returns true for providers implemented in this package
+func isBuiltin(p Type) bool {
+	return p == TypeClaude || p == TypeOpenCode || p == TypeGoose || p == TypeAmp || p == TypeAPI
 }
 
 // RunMode defines how the agent should be executed
@@ -50,13 +68,18 @@ const (
 
 // RunOptions configures how the agent is executed (provider-agnostic)
 type RunOptions struct {
-	Prompt       string         // The prompt to send to the agent
-	Mode         RunMode        // headless vs interactive
-	Permission   PermissionMode // acceptEdits, plan, bypassPermissions
-	Timeout      time.Duration  // timeout per invocation (0 = no timeout)
-	SystemPrompt string         // optional additional system prompt
-	Model        string         // canonical model name (e.g., "opus", "sonnet", "haiku")
-	WorkingDir   string         // working directory for command execution
+	Prompt       string          // The prompt to send to the agent
+	Mode         RunMode         // headless vs interactive
+	Permission   PermissionMode  // acceptEdits, plan, bypassPermissions
+	Timeout      time.Duration   // timeout per invocation (0 = no timeout)
+	SystemPrompt string          // optional additional system prompt
+	Model        string          // canonical model name (e.g., "opus", "sonnet", "haiku")
+	WorkingDir   string          // working directory for command execution
+	Env          []string        // extra KEY=VALUE environment entries for the agent process
+	Context      context.Context // cancels the run when done (nil = not cancellable)
+	Sandbox      *Sandbox        // run the CLI inside this container (nil = run on the host)
+	Limits       *ResourceLimits // cap the CLI's CPU, disk and memory use (nil = no limits)
+	Stop         <-chan struct{} // closed to stop the agent at its next safe point, e.g. after the current tool call (nil = never)
 }
 
 // RunResult represents the outcome of a single agent run (provider-agnostic)
@@ -69,12 +92,24 @@ type RunResult struct {
 	Blocked           bool          // BLOCKED signal detected
 	BlockedReason     string        // Reason for being blocked
 	TimedOut          bool          // Execution timed out
+	StoppedEarly      bool          // Agent was stopped after signalling or on request instead of exiting on its own
 	RateLimited       bool          // Rate limit error detected
 	RetryAfter        time.Duration // Suggested wait time from rate limit (0 if not specified)
 	OverloadExhausted bool          // Agent exited after exhausting overload retries
+	InputTokens       int           // Input tokens used, including cache reads and writes (0 if not reported)
+	OutputTokens      int           // Output tokens used (0 if not reported)
+	CostUSD           float64       // Cost reported by the provider (0 if not reported)
+	ToolEvents        []ToolEvent   // Tool calls made by the agent (nil if not reported)
 	Error             error         // Execution error (if any)
 }
 
+// ToolEvent is a tool call made by the agent during a run
+type ToolEvent struct {
+	Name    string // Tool name, e.g. "Bash" or "Edit"
+	Input   string // Short summary of the tool input, e.g. the command or file path
+	IsError bool   // The tool reported an error
+}
+
 // Provider defines the interface for AI agent backends
 type Provider interface {
 	// Type returns the provider type identifier
@@ -90,6 +125,38 @@ type Provider interface {
 	// MapPermission converts PermissionMode to provider-specific flag/argument
 	// Returns the flag name and value, or empty strings if not supported
 	MapPermission(mode PermissionMode) (flag, value string)
+
+	// ListModels returns the provider-specific model names the CLI accepts.
+	// Returns nil if the CLI accepts arbitrary model identifiers.
+	ListModels() []string
+
+	// SupportsInteractive reports whether Run implements ModeInteractive
+	SupportsInteractive() bool
+
+	// SupportsSystemPrompt reports whether RunOptions.SystemPrompt is passed to the CLI.
+	// When false, callers should fold the system prompt into the prompt.
+	SupportsSystemPrompt() bool
+
+	// SupportsTools reports whether the agent can read and edit files and run
+	// commands. Providers without tools only give advice (refine, dry-run review)
+	// and can't run the agent loop.
+	SupportsTools() bool
+}
+
+// IsModelSupported reports whether the provider accepts the model, either as given
+// or after mapping from a canonical name
+func IsModelSupported(p Provider, model string) bool {
+	models := p.ListModels()
+	if models == nil {
+		return true
+	}
+	mapped := p.MapModel(model)
+	for _, m := range models {
+		if m == model || m == mapped {
+			return true
+		}
+	}
+	return false
 }
 
 // AutonomousSystemPrompt is appended to force autonomous operation in headless mode
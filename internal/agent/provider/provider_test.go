@@ -1,6 +1,16 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -109,13 +119,434 @@ func TestOpenCodeProvider_MapPermission(t *testing.T) {
 	}
 }
 
+func TestGooseProvider_MapModel(t *testing.T) {
+	p := NewGooseProvider()
+
+	tests := []struct {
+		canonical string
+		want      string
+	}{
+		{"opus", "claude-opus-4-5"},
+		{"sonnet", "claude-sonnet-4-5"},
+		{"haiku", "claude-3-5-haiku-latest"},
+		{"large", "claude-opus-4-5"},
+		{"gpt-4o", "gpt-4o"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.canonical, func(t *testing.T) {
+			got := p.MapModel(tc.canonical)
+			if got != tc.want {
+				t.Errorf("MapModel(%q) = %q, want %q", tc.canonical, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGooseProvider_MapPermission(t *testing.T) {
+	p := NewGooseProvider()
+
+	tests := []struct {
+		mode      PermissionMode
+		wantFlag  string
+		wantValue string
+	}{
+		{PermissionAcceptEdits, "GOOSE_MODE", "auto"},
+		{PermissionPlan, "GOOSE_MODE", "chat"},
+		{PermissionBypass, "GOOSE_MODE", "auto"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			flag, value := p.MapPermission(tc.mode)
+			if flag != tc.wantFlag {
+				t.Errorf("MapPermission(%q) flag = %q, want %q", tc.mode, flag, tc.wantFlag)
+			}
+			if value != tc.wantValue {
+				t.Errorf("MapPermission(%q) value = %q, want %q", tc.mode, value, tc.wantValue)
+			}
+		})
+	}
+}
+
+func TestAmpProvider_MapModel(t *testing.T) {
+	p := NewAmpProvider()
+
+	tests := []struct {
+		canonical string
+		want      string
+	}{
+		{"haiku", "rush"},
+		{"small", "rush"},
+		{"sonnet", "smart"},
+		{"opus", "smart"},
+		{"large", "smart"},
+		{"free", "free"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.canonical, func(t *testing.T) {
+			got := p.MapModel(tc.canonical)
+			if got != tc.want {
+				t.Errorf("MapModel(%q) = %q, want %q", tc.canonical, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAmpProvider_MapPermission(t *testing.T) {
+	p := NewAmpProvider()
+
+	tests := []struct {
+		mode     PermissionMode
+		wantFlag string
+	}{
+		{PermissionAcceptEdits, "--dangerously-allow-all"},
+		{PermissionPlan, ""},
+		{PermissionBypass, "--dangerously-allow-all"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			flag, value := p.MapPermission(tc.mode)
+			if flag != tc.wantFlag {
+				t.Errorf("MapPermission(%q) flag = %q, want %q", tc.mode, flag, tc.wantFlag)
+			}
+			if value != "" {
+				t.Errorf("MapPermission(%q) value = %q, want empty", tc.mode, value)
+			}
+		})
+	}
+}
+
+func TestAmpProvider_InteractiveUnsupported(t *testing.T) {
+	p := NewAmpProvider()
+	if _, err := p.Run(RunOptions{Mode: ModeInteractive}); err == nil {
+		t.Error("expected error for interactive mode")
+	}
+}
+
+func TestRegisterCustom(t *testing.T) {
+	defer ClearCustom()
+
+	if err := RegisterCustom("claude", CustomConfig{Binary: "claude"}); err == nil {
+		t.Error("expected error overriding a built-in provider")
+	}
+	if err := RegisterCustom("aider", CustomConfig{}); err == nil {
+		t.Error("expected error for missing binary")
+	}
+	if err := RegisterCustom("aider", CustomConfig{Binary: "aider", PromptVia: "file"}); err == nil {
+		t.Error("expected error for invalid prompt_via")
+	}
+
+	if err := RegisterCustom("aider", CustomConfig{Binary: "aider"}); err != nil {
+		t.Fatalf("RegisterCustom failed: %v", err)
+	}
+	if !Type("aider").IsValid() {
+		t.Error("expected registered custom provider to be valid")
+	}
+	if got := BinaryName(Type("aider")); got != "aider" {
+		t.Errorf("BinaryName = %q, want %q", got, "aider")
+	}
+	if got := Get(Type("aider")).Type(); got != Type("aider") {
+		t.Errorf("Get returned provider of type %q, want %q", got, "aider")
+	}
+	if got := Detect("", "aider", ""); got != Type("aider") {
+		t.Errorf("Detect = %q, want %q", got, "aider")
+	}
+
+	ClearCustom()
+	if Type("aider").IsValid() {
+		t.Error("expected custom provider to be invalid after ClearCustom")
+	}
+}
+
+func TestCustomProvider_BuildArgs(t *testing.T) {
+	p := NewCustomProvider("aider", CustomConfig{
+		Binary:         "aider",
+		Args:           []string{"--yes", "--system", "{{system_prompt}}", "--message={{prompt}}"},
+		ModelFlag:      "--model",
+		Models:         map[string]string{"opus": "claude-opus-4-5"},
+		PermissionArgs: map[string][]string{"plan": {"--dry-run"}},
+	})
+
+	args := p.buildArgs(p.config.Args, RunOptions{
+		Prompt:     "do {{model}} work",
+		Model:      "opus",
+		Permission: PermissionPlan,
+	}, true)
+
+	// Empty {{system_prompt}} argument is dropped; prompt already in template so not appended
+	want := []string{"--yes", "--system", "--message=do {{model}} work", "--model", "claude-opus-4-5", "--dry-run"}
+	if len(args) != len(want) {
+		t.Fatalf("buildArgs = %q, want %q", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("arg %d = %q, want %q", i, args[i], want[i])
+		}
+	}
+}
+
+func TestCustomProvider_RunHeadless(t *testing.T) {
+	tests := []struct {
+		name   string
+		config CustomConfig
+	}{
+		{"prompt via stdin", CustomConfig{Binary: "sh", Args: []string{"-c", "cat"}}},
+		{"prompt via arg", CustomConfig{Binary: "echo", PromptVia: PromptViaArg}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCustomProvider("test", tt.config)
+			result, err := p.Run(RunOptions{Prompt: "<promise>COMPLETE: feat: done</promise>", Mode: ModeHeadless})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result.Error != nil {
+				t.Fatalf("unexpected run error: %v", result.Error)
+			}
+			if !result.Complete || result.CommitMessage != "feat: done" {
+				t.Errorf("expected COMPLETE signal parsed from output, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestCustomProvider_InteractiveUnsupported(t *testing.T) {
+	p := NewCustomProvider("test", CustomConfig{Binary: "echo"})
+	if _, err := p.Run(RunOptions{Mode: ModeInteractive}); err == nil {
+		t.Error("expected error when interactive_args is not configured")
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	tests := []struct {
+		provider        Provider
+		interactive     bool
+		systemPrompt    bool
+		restrictsModels bool
+		tools           bool
+	}{
+		{NewClaudeProvider(), true, true, false, true},
+		{NewOpenCodeProvider(), true, false, false, true},
+		{NewGooseProvider(), true, true, false, true},
+		{NewAmpProvider(), false, false, true, true},
+		{NewAPIProvider(), false, true, false, false},
+		{NewCustomProvider("bare", CustomConfig{Binary: "bare"}), false, false, false, true},
+		{NewCustomProvider("full", CustomConfig{
+			Binary:          "full",
+			Args:            []string{"--system={{system_prompt}}"},
+			Models:          map[string]string{"opus": "big"},
+			InteractiveArgs: []string{"--tui"},
+		}), true, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider.Type()), func(t *testing.T) {
+			if got := tt.provider.SupportsInteractive(); got != tt.interactive {
+				t.Errorf("SupportsInteractive() = %v, want %v", got, tt.interactive)
+			}
+			if got := tt.provider.SupportsSystemPrompt(); got != tt.systemPrompt {
+				t.Errorf("SupportsSystemPrompt() = %v, want %v", got, tt.systemPrompt)
+			}
+			if got := tt.provider.ListModels() != nil; got != tt.restrictsModels {
+				t.Errorf("ListModels() restricts models = %v, want %v", got, tt.restrictsModels)
+			}
+			if got := tt.provider.SupportsTools(); got != tt.tools {
+				t.Errorf("SupportsTools() = %v, want %v", got, tt.tools)
+			}
+		})
+	}
+}
+
+func TestIsModelSupported(t *testing.T) {
+	amp := NewAmpProvider()
+	custom := NewCustomProvider("test", CustomConfig{Binary: "test", Models: map[string]string{"opus": "big"}})
+
+	tests := []struct {
+		provider Provider
+		model    string
+		want     bool
+	}{
+		{NewClaudeProvider(), "anything", true},
+		{amp, "smart", true},
+		{amp, "haiku", true}, // maps to rush
+		{amp, "gpt-5", false},
+		{custom, "opus", true},
+		{custom, "big", true},
+		{custom, "sonnet", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider.Type())+"/"+tt.model, func(t *testing.T) {
+			if got := IsModelSupported(tt.provider, tt.model); got != tt.want {
+				t.Errorf("IsModelSupported(%q) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+// runClaudeStream feeds stream-json lines through a collector and applies it to a result
+func runClaudeStream(lines []string, result *RunResult) *RunResult {
+	stream := newClaudeStream(nil)
+	for _, line := range lines {
+		stream.handleLine(line)
+	}
+	result.Output = stream.Output()
+	stream.apply(result)
+	return result
+}
+
+func TestClaudeStream_Events(t *testing.T) {
+	result := runClaudeStream([]string{
+		`{"type":"system","subtype":"init","session_id":"abc","model":"claude-opus"}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Running tests"},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./...","timeout":60}}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"main.go","old_string":"a","new_string":"b"}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"<promise>COMPLETE: fix: repair tests</promise>"}]}}`,
+		`{"type":"result","subtype":"success","is_error":false,"result":"done","total_cost_usd":0.25,"usage":{"input_tokens":100,"cache_read_input_tokens":900,"cache_creation_input_tokens":50,"output_tokens":40}}`,
+	}, &RunResult{})
+
+	if !result.Complete || result.CommitMessage != "fix: repair tests" {
+		t.Errorf("expected COMPLETE with commit message, got Complete=%v CommitMessage=%q", result.Complete, result.CommitMessage)
+	}
+	if result.InputTokens != 1050 || result.OutputTokens != 40 || result.CostUSD != 0.25 {
+		t.Errorf("usage = in %d, out %d, cost %v; want 1050, 40, 0.25", result.InputTokens, result.OutputTokens, result.CostUSD)
+	}
+
+	want := []ToolEvent{
+		{Name: "Bash", Input: "go test ./...", IsError: true},
+		{Name: "Edit", Input: "main.go"},
+	}
+	if len(result.ToolEvents) != len(want) {
+		t.Fatalf("ToolEvents = %+v, want %+v", result.ToolEvents, want)
+	}
+	for i := range want {
+		if result.ToolEvents[i] != want[i] {
+			t.Errorf("ToolEvents[%d] = %+v, want %+v", i, result.ToolEvents[i], want[i])
+		}
+	}
+
+	if !strings.Contains(result.Output, "→ Bash: go test ./...") || !strings.Contains(result.Output, "Running tests") {
+		t.Errorf("expected rendered transcript, got:\n%s", result.Output)
+	}
+}
+
+func TestClaudeStream_StopAfterTool(t *testing.T) {
+	stream := newClaudeStream(nil)
+	stream.handleLine(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test ./..."}}]}}`)
+
+	stops := 0
+	stream.stopAfterTool(func() { stops++ })
+	if stops != 0 {
+		t.Fatal("expected the stop to wait for the running tool call")
+	}
+	if !stream.stopRequested() {
+		t.Error("expected the stop to be recorded as requested")
+	}
+
+	stream.handleLine(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`)
+	if stops != 1 {
+		t.Errorf("expected the stop once the tool result arrived, got %d calls", stops)
+	}
+
+	idle := newClaudeStream(nil)
+	idle.stopAfterTool(func() { stops++ })
+	if stops != 2 {
+		t.Error("expected an immediate stop with no tool call running")
+	}
+}
+
+func TestClaudeStream_IgnoresSignalsOutsideAssistantText(t *testing.T) {
+	result := runClaudeStream([]string{
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"prompt.md"}}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"Signal <promise>COMPLETE</promise> when done"}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Handled the HTTP 429 retry path"}]}}`,
+		`{"type":"result","subtype":"success","is_error":false,"result":"ok"}`,
+	}, &RunResult{})
+
+	if result.Complete {
+		t.Error("expected promise tag in tool output to be ignored")
+	}
+	if result.RateLimited {
+		t.Error("expected 429 in assistant text of a successful run to be ignored")
+	}
+}
+
+func TestClaudeStream_RateLimitFromErrorResult(t *testing.T) {
+	result := runClaudeStream([]string{
+		`{"type":"result","subtype":"error_during_execution","is_error":true,"result":"API Error: rate limit exceeded, try again in 30 seconds"}`,
+	}, &RunResult{ExitCode: 1, Error: errors.New("exit status 1")})
+
+	if !result.RateLimited {
+		t.Fatal("expected rate limit from error result")
+	}
+	if result.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %v, want 30s", result.RetryAfter)
+	}
+}
+
+func TestClaudeStream_PlainTextFallback(t *testing.T) {
+	result := runClaudeStream([]string{
+		"Error: unknown option '--output-format'",
+		"<promise>BLOCKED: old CLI</promise>",
+	}, &RunResult{})
+
+	if !result.Blocked || result.BlockedReason != "old CLI" {
+		t.Errorf("expected plain text signals to be parsed, got Blocked=%v BlockedReason=%q", result.Blocked, result.BlockedReason)
+	}
+	if result.ToolEvents != nil {
+		t.Errorf("expected no tool events, got %+v", result.ToolEvents)
+	}
+}
+
+func TestSummarizeToolInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"command":"ls\n  -la"}`, "ls -la"},
+		{`{"file_path":"a.go","content":"x"}`, "a.go"},
+		{`{"todos":[1]}`, `{"todos":[1]}`},
+		{``, ""},
+		{`{"command":"` + strings.Repeat("é", 150) + `"}`, strings.Repeat("é", 97) + "..."},
+	}
+
+	for _, tt := range tests {
+		if got := summarizeToolInput(json.RawMessage(tt.input)); got != tt.want {
+			t.Errorf("summarizeToolInput(%s) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestExtractGooseLastAssistantText(t *testing.T) {
+	export := `{"messages": [
+		{"role": "user", "content": [{"type": "text", "text": "Say <promise>COMPLETE</promise> when done"}]},
+		{"role": "assistant", "content": [{"type": "text", "text": "Working"}, {"type": "toolRequest"}]},
+		{"role": "user", "content": [{"type": "toolResponse"}]},
+		{"role": "assistant", "content": [{"type": "text", "text": "Done."}, {"type": "text", "text": "<promise>CONTINUE: feat: add thing</promise>"}]}
+	]}`
+
+	got := extractGooseLastAssistantText(export)
+	want := "Done.\n<promise>CONTINUE: feat: add thing</promise>"
+	if got != want {
+		t.Errorf("extractGooseLastAssistantText() = %q, want %q", got, want)
+	}
+
+	if got := extractGooseLastAssistantText("not json"); got != "" {
+		t.Errorf("expected empty string for invalid JSON, got %q", got)
+	}
+}
+
 func TestDetect(t *testing.T) {
 	tests := []struct {
-		name           string
-		cliOverride    string
+		name            string
+		cliOverride     string
 		projectProvider string
 		globalProvider  string
-		want           Type
+		want            Type
 	}{
 		{"default to claude", "", "", "", TypeClaude},
 		{"cli override wins", "opencode", "claude", "claude", TypeOpenCode},
@@ -144,6 +575,8 @@ func TestType_IsValid(t *testing.T) {
 	}{
 		{TypeClaude, true},
 		{TypeOpenCode, true},
+		{TypeGoose, true},
+		{TypeAmp, true},
 		{Type("invalid"), false},
 		{Type(""), false},
 	}
@@ -212,13 +645,13 @@ func TestApplyModelOverrides(t *testing.T) {
 
 func TestParseSignals(t *testing.T) {
 	tests := []struct {
-		name          string
-		output        string
-		wantComplete  bool
-		wantContinue  bool
-		wantBlocked   bool
-		wantReason    string
-		wantCommit    string
+		name         string
+		output       string
+		wantComplete bool
+		wantContinue bool
+		wantBlocked  bool
+		wantReason   string
+		wantCommit   string
 	}{
 		{
 			name:         "COMPLETE signal",
@@ -322,6 +755,27 @@ func TestGet(t *testing.T) {
 		}
 	})
 
+	t.Run("returns GooseProvider for TypeGoose", func(t *testing.T) {
+		p := Get(TypeGoose)
+		if p.Type() != TypeGoose {
+			t.Errorf("Get(TypeGoose).Type() = %v, want TypeGoose", p.Type())
+		}
+	})
+
+	t.Run("returns AmpProvider for TypeAmp", func(t *testing.T) {
+		p := Get(TypeAmp)
+		if p.Type() != TypeAmp {
+			t.Errorf("Get(TypeAmp).Type() = %v, want TypeAmp", p.Type())
+		}
+	})
+
+	t.Run("returns APIProvider for TypeAPI", func(t *testing.T) {
+		p := Get(TypeAPI)
+		if p.Type() != TypeAPI {
+			t.Errorf("Get(TypeAPI).Type() = %v, want TypeAPI", p.Type())
+		}
+	})
+
 	t.Run("defaults to ClaudeProvider for unknown type", func(t *testing.T) {
 		p := Get(Type("unknown"))
 		if p.Type() != TypeClaude {
@@ -332,11 +786,11 @@ func TestGet(t *testing.T) {
 
 func TestValidProviders(t *testing.T) {
 	providers := ValidProviders()
-	if len(providers) != 2 {
-		t.Fatalf("expected 2 providers, got %d", len(providers))
+	if len(providers) != 5 {
+		t.Fatalf("expected 5 providers, got %d", len(providers))
 	}
 
-	// Check both providers are present
+	// Check all providers are present
 	found := make(map[string]bool)
 	for _, p := range providers {
 		found[p] = true
@@ -348,6 +802,15 @@ func TestValidProviders(t *testing.T) {
 	if !found["opencode"] {
 		t.Error("expected 'opencode' in valid providers")
 	}
+	if !found["goose"] {
+		t.Error("expected 'goose' in valid providers")
+	}
+	if !found["amp"] {
+		t.Error("expected 'amp' in valid providers")
+	}
+	if !found["api"] {
+		t.Error("expected 'api' in valid providers")
+	}
 }
 
 func TestOpenCodeProvider_ParseRateLimit(t *testing.T) {
@@ -405,3 +868,442 @@ func TestOpenCodeProvider_ParseRateLimitWithRetryAfter(t *testing.T) {
 		t.Errorf("expected RetryAfter=30s, got %v", result.RetryAfter)
 	}
 }
+
+func TestApplyExportUsage(t *testing.T) {
+	export := `{"messages":[
+		{"info":{"role":"user"},"parts":[]},
+		{"info":{"role":"assistant","cost":0.012,"tokens":{"input":100,"output":40,"reasoning":10,"cache":{"read":500,"write":50}}},"parts":[]},
+		{"info":{"role":"assistant","cost":0.003,"tokens":{"input":20,"output":5,"reasoning":0,"cache":{"read":0,"write":0}}},"parts":[]}
+	]}`
+
+	result := &RunResult{}
+	applyExportUsage(result, export)
+
+	if result.InputTokens != 670 {
+		t.Errorf("InputTokens = %d, want 670", result.InputTokens)
+	}
+	if result.OutputTokens != 55 {
+		t.Errorf("OutputTokens = %d, want 55", result.OutputTokens)
+	}
+	if result.CostUSD < 0.0149 || result.CostUSD > 0.0151 {
+		t.Errorf("CostUSD = %v, want 0.015", result.CostUSD)
+	}
+
+	// Missing or malformed exports leave the result untouched
+	empty := &RunResult{}
+	applyExportUsage(empty, "")
+	applyExportUsage(empty, "not json")
+	if empty.InputTokens != 0 || empty.OutputTokens != 0 || empty.CostUSD != 0 {
+		t.Errorf("Expected no usage from bad export, got %+v", empty)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Base: 30 * time.Second, Cap: 2 * time.Minute}
+
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{0, 0, 30 * time.Second},
+		{1, 0, time.Minute},
+		{2, 0, 2 * time.Minute},
+		{3, 0, 2 * time.Minute},    // Capped
+		{1000, 0, 2 * time.Minute}, // No overflow
+		{3, 10 * time.Second, 10*time.Second + RetryAfterBuffer},
+	}
+	for _, tt := range tests {
+		if got := policy.Delay(tt.attempt, tt.retryAfter); got != tt.want {
+			t.Errorf("Delay(%d, %v) = %v, want %v", tt.attempt, tt.retryAfter, got, tt.want)
+		}
+	}
+
+	fixed := RetryPolicy{Base: 10 * time.Minute, Cap: 10 * time.Minute}
+	if got := fixed.Delay(5, 0); got != 10*time.Minute {
+		t.Errorf("fixed Delay(5) = %v, want 10m", got)
+	}
+
+	jittered := RetryPolicy{Base: time.Minute, Cap: time.Minute, Jitter: 0.2}
+	for i := 0; i < 50; i++ {
+		if got := jittered.Delay(0, 0); got < 48*time.Second || got > 72*time.Second {
+			t.Fatalf("jittered Delay = %v, want within ±20%% of 1m", got)
+		}
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	limited := RetryPolicy{MaxAttempts: 3}
+	if limited.Exhausted(2) || !limited.Exhausted(3) {
+		t.Error("Expected policy with MaxAttempts=3 to be exhausted after 3 retries, not before")
+	}
+	if (RetryPolicy{}).Exhausted(1000) {
+		t.Error("Expected policy without MaxAttempts to never be exhausted")
+	}
+}
+
+func TestSignalWatcher_Observe(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		fires bool
+	}{
+		{"complete", []string{"done", "<promise>COMPLETE: feat: x</promise>"}, true},
+		{"blocked", []string{"<promise>BLOCKED: need creds</promise>"}, true},
+		{"split across lines", []string{"<promise>CONTINUE: feat: a", "</promise>"}, true},
+		{"unterminated", []string{"<promise>COMPLETE: feat: x"}, false},
+		{"no signal", []string{"working on it", "</promise> stray close"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newSignalWatcher(func() {})
+			for _, line := range tt.lines {
+				w.observe(line)
+			}
+			if w.fired != tt.fires {
+				t.Errorf("fired = %v, want %v", w.fired, tt.fires)
+			}
+			if w.release() {
+				t.Error("expected release before grace to report not stopped")
+			}
+		})
+	}
+}
+
+func TestClaudeStream_WatchesAssistantTextOnly(t *testing.T) {
+	w := newSignalWatcher(func() {})
+	stream := newClaudeStream(w)
+
+	stream.handleLine(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"<promise>COMPLETE</promise>"}]}}`)
+	if w.fired {
+		t.Fatal("expected promise tag in tool output not to stop the agent")
+	}
+
+	stream.handleLine(`{"type":"assistant","message":{"content":[{"type":"text","text":"<promise>COMPLETE: feat: done</promise>"}]}}`)
+	if !w.fired {
+		t.Error("expected assistant signal to schedule a stop")
+	}
+	w.release()
+}
+
+func TestCustomProvider_StopsAfterSignal(t *testing.T) {
+	oldGrace := SignalExitGrace
+	SignalExitGrace = 50 * time.Millisecond
+	defer func() { SignalExitGrace = oldGrace }()
+
+	// The agent signals, then keeps working; a background child also holds stdout
+	p := NewCustomProvider("test", CustomConfig{
+		Binary: "sh",
+		Args:   []string{"-c", "echo '<promise>COMPLETE: feat: done</promise>'; sleep 30 & sleep 30"},
+	})
+
+	start := time.Now()
+	result, err := p.Run(RunOptions{Prompt: "go", Mode: ModeHeadless})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected agent to be stopped after signalling, took %v", elapsed)
+	}
+	if result.Error != nil {
+		t.Errorf("expected early stop not to be an error, got %v", result.Error)
+	}
+	if !result.StoppedEarly || result.TimedOut {
+		t.Errorf("expected StoppedEarly without TimedOut, got %+v", result)
+	}
+	if !result.Complete || result.CommitMessage != "feat: done" {
+		t.Errorf("expected COMPLETE signal, got %+v", result)
+	}
+}
+
+func TestCustomProvider_ContextCancel(t *testing.T) {
+	p := NewCustomProvider("test", CustomConfig{Binary: "sh", Args: []string{"-c", "exec sleep 30"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	result, err := p.Run(RunOptions{Prompt: "go", Mode: ModeHeadless, Context: ctx})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected cancellation to stop the agent, took %v", elapsed)
+	}
+	if !errors.Is(result.Error, context.Canceled) {
+		t.Errorf("expected context.Canceled error, got %v", result.Error)
+	}
+	if result.TimedOut {
+		t.Error("expected cancellation not to be reported as a timeout")
+	}
+}
+
+func TestSandbox_RunArgs(t *testing.T) {
+	s := &Sandbox{
+		Image:   "juggle-agent:latest",
+		Mounts:  []string{"/work/repo", "/work/main/.juggle"},
+		Env:     []string{"ANTHROPIC_API_KEY"},
+		Network: "none",
+		Args:    []string{"--memory", "4g"},
+	}
+	opts := RunOptions{Mode: ModeHeadless, Env: []string{"DATABASE_URL=postgres://db"}}
+
+	args := strings.Join(s.runArgs("juggle-agent-1-1", opts, "claude", []string{"-p", "--model", "opus"}, []string{"GOOSE_MODE=auto"}), " ")
+	for _, want := range []string{
+		"run --rm -i --init --name juggle-agent-1-1",
+		"-v /work/repo:/work/repo -v /work/main/.juggle:/work/main/.juggle",
+		"-w /work/repo",
+		"--network none",
+		"-e ANTHROPIC_API_KEY -e DATABASE_URL=postgres://db -e GOOSE_MODE=auto",
+		"--memory 4g juggle-agent:latest claude -p --model opus",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in sandbox args, got %q", want, args)
+		}
+	}
+	if strings.Contains(args, " -t ") {
+		t.Errorf("expected no tty for headless runs, got %q", args)
+	}
+
+	opts.Mode = ModeInteractive
+	opts.WorkingDir = "/work/repo/sub"
+	args = strings.Join(s.runArgs("c", opts, "claude", nil, nil), " ")
+	if !strings.Contains(args, " -t ") || !strings.Contains(args, "-w /work/repo/sub") {
+		t.Errorf("expected tty and working dir for interactive runs, got %q", args)
+	}
+	if s.EngineName() != EngineDocker {
+		t.Errorf("expected docker by default, got %s", s.EngineName())
+	}
+}
+
+func TestSandbox_WrapsProviderCommand(t *testing.T) {
+	// A stand-in engine that prints its arguments and replies like an agent
+	engine := filepath.Join(t.TempDir(), "fake-engine")
+	script := "#!/bin/sh\necho \"engine: $*\"\necho '<promise>COMPLETE: feat: sandboxed</promise>'\n"
+	if err := os.WriteFile(engine, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake engine: %v", err)
+	}
+
+	p := NewCustomProvider("test", CustomConfig{Binary: "agent-cli", Args: []string{"--go"}})
+	result, err := p.Run(RunOptions{
+		Prompt:  "go",
+		Mode:    ModeHeadless,
+		Sandbox: &Sandbox{Engine: engine, Image: "img", Mounts: []string{"/repo"}},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(result.Output, "img agent-cli --go") {
+		t.Errorf("expected agent CLI to run inside the image, got %q", result.Output)
+	}
+	if !result.Complete || result.CommitMessage != "feat: sandboxed" {
+		t.Errorf("expected signals from the sandboxed output, got %+v", result)
+	}
+}
+
+func TestBinaryPathOverride(t *testing.T) {
+	defer ClearBinaryPaths()
+
+	pinned := filepath.Join(t.TempDir(), "pinned-agent")
+	script := "#!/bin/sh\necho 'pinned build'\necho '<promise>COMPLETE</promise>'\n"
+	if err := os.WriteFile(pinned, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write pinned binary: %v", err)
+	}
+
+	SetBinaryPath(TypeClaude, pinned)
+	if got := BinaryName(TypeClaude); got != pinned {
+		t.Errorf("BinaryName() = %q, want %q", got, pinned)
+	}
+	if !IsAvailable(TypeClaude) {
+		t.Error("expected pinned binary to be available")
+	}
+
+	SetBinaryPath(TypeClaude, filepath.Join(t.TempDir(), "missing"))
+	if IsAvailable(TypeClaude) {
+		t.Error("expected missing pinned binary to be unavailable")
+	}
+
+	// Custom providers run the pinned path instead of their configured binary
+	SetBinaryPath("pinned", pinned)
+	result, err := NewCustomProvider("pinned", CustomConfig{Binary: "not-on-path"}).Run(RunOptions{Prompt: "go", Mode: ModeHeadless})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(result.Output, "pinned build") || !result.Complete {
+		t.Errorf("expected the pinned binary to run, got %+v", result)
+	}
+
+	ClearBinaryPaths()
+	if got := BinaryName(TypeClaude); got != "claude" {
+		t.Errorf("BinaryName() after clear = %q, want claude", got)
+	}
+}
+
+func TestAPIProvider_Run(t *testing.T) {
+	var got apiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing auth headers: %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"content":[{"type":"text","text":"Split the ball in two."}],"usage":{"input_tokens":120,"cache_read_input_tokens":30,"output_tokens":15}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvAnthropicAPIKey, "test-key")
+	t.Setenv(EnvAnthropicBaseURL, server.URL)
+
+	if !IsAvailable(TypeAPI) {
+		t.Error("expected api provider to be available with a key set")
+	}
+
+	result, err := NewAPIProvider().Run(RunOptions{
+		Prompt:       "Refine these balls",
+		Mode:         ModeHeadless,
+		Model:        "opus",
+		SystemPrompt: "Be brief",
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Output != "Split the ball in two." {
+		t.Errorf("Output = %q", result.Output)
+	}
+	if result.InputTokens != 150 || result.OutputTokens != 15 {
+		t.Errorf("tokens = %d in, %d out, want 150 in, 15 out", result.InputTokens, result.OutputTokens)
+	}
+	if got.Model != "claude-opus-4-5" || len(got.Messages) != 1 || got.Messages[0].Content != "Refine these balls" {
+		t.Errorf("unexpected request %+v", got)
+	}
+	if !strings.HasPrefix(got.System, AdviceSystemPrompt) || !strings.HasSuffix(got.System, "Be brief") {
+		t.Errorf("expected advice and caller system prompts, got %q", got.System)
+	}
+}
+
+func TestAPIProvider_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("retry-after", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvAnthropicAPIKey, "test-key")
+	t.Setenv(EnvAnthropicBaseURL, server.URL)
+
+	result, err := NewAPIProvider().Run(RunOptions{Prompt: "hi", Mode: ModeHeadless})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !result.RateLimited || result.RetryAfter != 30*time.Second {
+		t.Errorf("expected rate limit with 30s retry, got %+v", result)
+	}
+	if !strings.Contains(result.Output, "slow down") {
+		t.Errorf("expected API error message in output, got %q", result.Output)
+	}
+}
+
+func TestAPIProvider_Unavailable(t *testing.T) {
+	t.Setenv(EnvAnthropicAPIKey, "")
+
+	if IsAvailable(TypeAPI) {
+		t.Error("expected api provider to be unavailable without a key")
+	}
+	if got := UnavailableReason(TypeAPI); !strings.Contains(got, EnvAnthropicAPIKey) {
+		t.Errorf("UnavailableReason() = %q, want it to mention %s", got, EnvAnthropicAPIKey)
+	}
+	if _, err := NewAPIProvider().Run(RunOptions{Prompt: "hi", Mode: ModeInteractive}); err == nil {
+		t.Error("expected interactive mode to fail")
+	}
+}
+
+func TestResourceLimits_Wrap(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("ionice, prlimit and systemd scopes are Linux only")
+	}
+	saved := limitTools
+	defer func() { limitTools = saved }()
+
+	installed := map[string]bool{"nice": true, "ionice": true, "prlimit": true}
+	limitTools.lookPath = func(file string) (string, error) {
+		if installed[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", exec.ErrNotFound
+	}
+	scope := false
+	limitTools.systemdScope = func() bool { return scope }
+
+	limits := &ResourceLimits{Nice: 10, IOClass: IOClassIdle, MaxMemory: 4 << 30}
+	name, args := limits.wrap("claude", []string{"-p", "-"})
+	got := name + " " + strings.Join(args, " ")
+	if want := "prlimit --data=4294967296 -- ionice -c 3 -- nice -n 10 claude -p -"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if desc := limits.Describe(); desc != "nice 10, idle I/O, memory 4G (rlimit)" {
+		t.Errorf("unexpected description %q", desc)
+	}
+
+	// A cgroup scope is preferred for memory
+	scope = true
+	if name, _ := limits.wrap("claude", nil); name != "systemd-run" {
+		t.Errorf("expected a systemd scope, got %q", name)
+	}
+
+	// Limits without their tool are skipped and reported
+	scope = false
+	installed = map[string]bool{"nice": true}
+	limits.IOClass = IOClassBestEffort
+	name, args = limits.wrap("claude", nil)
+	if got := name + " " + strings.Join(args, " "); got != "nice -n 10 claude" {
+		t.Errorf("expected only nice, got %q", got)
+	}
+	if desc := limits.Describe(); !strings.Contains(desc, "best-effort I/O not applied") || !strings.Contains(desc, "memory 4G not applied") {
+		t.Errorf("expected the skipped limits in %q", desc)
+	}
+}
+
+func TestSandbox_RunArgsMemoryLimit(t *testing.T) {
+	s := &Sandbox{Image: "juggle-agent:latest"}
+	opts := RunOptions{Limits: &ResourceLimits{Nice: 10, MaxMemory: 512 << 20}}
+	args := strings.Join(s.runArgs("c", opts, "claude", nil, nil), " ")
+	if !strings.Contains(args, "--memory 536870912 juggle-agent:latest claude") {
+		t.Errorf("expected the memory limit on the container, got %q", args)
+	}
+	if strings.Contains(args, "nice") {
+		t.Errorf("expected no nice in sandbox args, got %q", args)
+	}
+}
+
+func TestParseMemorySize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"4G", 4 << 30},
+		{"4GiB", 4 << 30},
+		{"512m", 512 << 20},
+		{"1024", 1024},
+		{"2T", 2 << 40},
+	}
+	for _, tt := range tests {
+		got, err := ParseMemorySize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMemorySize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+		if back, _ := ParseMemorySize(FormatMemorySize(got)); back != got {
+			t.Errorf("FormatMemorySize(%d) doesn't parse back", got)
+		}
+	}
+	for _, bad := range []string{"", "lots", "-1G", "0"} {
+		if _, err := ParseMemorySize(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
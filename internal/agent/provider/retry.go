@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryAfterBuffer is added to a provider's retry-after hint so the retry
+// lands after the limit has reset rather than right on the boundary
+const RetryAfterBuffer = 5 * time.Second
+
+// RetryPolicy computes exponential backoff waits between retries of a failed
+// agent run: Base, 2*Base, 4*Base... up to Cap, each varied by Jitter.
+// Setting Cap equal to Base gives a fixed wait.
+type RetryPolicy struct {
+	Base        time.Duration // Wait before the first retry
+	Cap         time.Duration // Longest wait (0 = uncapped)
+	Jitter      float64       // Random +/- fraction of each wait, e.g. 0.2 for ±20% (0 = exact)
+	MaxAttempts int           // Retries allowed before giving up (0 = unlimited)
+}
+
+// Default retry policies used by the agent loop
+var (
+	// DefaultRateLimitPolicy backs off 30s, 1m, 2m... up to 16m
+	DefaultRateLimitPolicy = RetryPolicy{Base: 30 * time.Second, Cap: 16 * time.Minute}
+
+	// DefaultOverloadPolicy waits a fixed 10m after the agent's own 529 retries are exhausted
+	DefaultOverloadPolicy = RetryPolicy{Base: 10 * time.Minute, Cap: 10 * time.Minute}
+
+	// DefaultCrashPolicy backs off 1s, 2s, 4s and gives up after 3 crashes
+	DefaultCrashPolicy = RetryPolicy{Base: time.Second, Cap: time.Minute, MaxAttempts: 3}
+)
+
+// Delay returns how long to wait before retry number attempt (0 for the first retry).
+// A retryAfter hint from the provider takes precedence over the backoff.
+func (p RetryPolicy) Delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter + RetryAfterBuffer
+	}
+
+	wait := p.Base
+	for i := 0; i < attempt && (p.Cap <= 0 || wait < p.Cap); i++ {
+		wait *= 2
+	}
+	if p.Cap > 0 && wait > p.Cap {
+		wait = p.Cap
+	}
+
+	if p.Jitter > 0 && wait > 0 {
+		spread := float64(wait) * p.Jitter
+		wait += time.Duration((rand.Float64()*2 - 1) * spread)
+		if wait < 0 {
+			wait = 0
+		}
+	}
+	return wait
+}
+
+// Exhausted reports whether attempts retries have used up the policy
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
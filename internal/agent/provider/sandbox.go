@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Container engines supported by Sandbox
+const (
+	EngineDocker = "docker"
+	EnginePodman = "podman"
+)
+
+// sandboxStopWait is how long a sandboxed agent gets to exit after being signalled
+const sandboxStopWait = 10 * time.Second
+
+// sandboxCount numbers containers started by this process so their names are unique
+var sandboxCount atomic.Int64
+
+// Sandbox runs the agent CLI inside a docker or podman container. Only Mounts are
+// shared with the container, so the agent can't read or change the rest of the host.
+type Sandbox struct {
+	Engine  string   // "docker" (default) or "podman"
+	Image   string   // Image with the agent CLI installed
+	Mounts  []string // Absolute host directories mounted read-write at the same path
+	Env     []string // Host environment variables passed through by name, e.g. ANTHROPIC_API_KEY
+	Network string   // Container network, e.g. "none" (empty = engine default)
+	Args    []string // Extra arguments for the engine's "run" command
+}
+
+// EngineName returns the container engine binary, defaulting to docker
+func (s *Sandbox) EngineName() string {
+	if s.Engine == "" {
+		return EngineDocker
+	}
+	return s.Engine
+}
+
+// agentCommand builds the command for an agent CLI invocation with the working
+// directory and environment from opts. When opts.Sandbox is set the CLI runs in its container.
+func agentCommand(ctx context.Context, opts RunOptions, name string, args []string, extraEnv ...string) *exec.Cmd {
+	if opts.Sandbox != nil {
+		return opts.Sandbox.command(ctx, opts, name, args, extraEnv)
+	}
+	if opts.Limits != nil {
+		name, args = opts.Limits.wrap(name, args)
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	if opts.WorkingDir != "" {
+		cmd.Dir = opts.WorkingDir
+	}
+	cmd.Env = commandEnv(opts, extraEnv...)
+	return cmd
+}
+
+// command wraps the agent CLI in "<engine> run". Cancelling ctx signals the
+// container to stop, so the agent gets the same SIGTERM it would get on the host.
+func (s *Sandbox) command(ctx context.Context, opts RunOptions, name string, args []string, extraEnv []string) *exec.Cmd {
+	containerName := fmt.Sprintf("juggle-agent-%d-%d", os.Getpid(), sandboxCount.Add(1))
+	engine := s.EngineName()
+
+	cmd := exec.CommandContext(ctx, engine, s.runArgs(containerName, opts, name, args, extraEnv)...)
+	if opts.WorkingDir != "" {
+		cmd.Dir = opts.WorkingDir
+	}
+	cmd.Cancel = func() error {
+		return exec.Command(engine, "kill", "--signal", "TERM", containerName).Run()
+	}
+	cmd.WaitDelay = sandboxStopWait
+	return cmd
+}
+
+// runArgs returns the engine arguments that run the agent CLI in a throwaway container
+func (s *Sandbox) runArgs(containerName string, opts RunOptions, name string, args []string, extraEnv []string) []string {
+	runArgs := []string{"run", "--rm", "-i", "--init", "--name", containerName}
+	if opts.Mode == ModeInteractive {
+		runArgs = append(runArgs, "-t")
+	}
+
+	// Files the agent writes should belong to the host user, not root
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		if s.EngineName() == EnginePodman {
+			runArgs = append(runArgs, "--userns=keep-id")
+		} else {
+			runArgs = append(runArgs, "--user", fmt.Sprintf("%d:%d", uid, gid))
+		}
+	}
+
+	for _, mount := range s.Mounts {
+		runArgs = append(runArgs, "-v", mount+":"+mount)
+	}
+	workDir := opts.WorkingDir
+	if workDir == "" && len(s.Mounts) > 0 {
+		workDir = s.Mounts[0]
+	}
+	if workDir != "" {
+		runArgs = append(runArgs, "-w", workDir)
+	}
+	if s.Network != "" {
+		runArgs = append(runArgs, "--network", s.Network)
+	}
+
+	// Named variables are copied from the host by the engine; KEY=VALUE entries are set as given
+	for _, key := range s.Env {
+		runArgs = append(runArgs, "-e", key)
+	}
+	for _, entry := range append(append([]string{}, opts.Env...), extraEnv...) {
+		if strings.Contains(entry, "=") {
+			runArgs = append(runArgs, "-e", entry)
+		}
+	}
+
+	// The engine enforces the memory limit; niceness and I/O class don't reach the container
+	if opts.Limits != nil && opts.Limits.MaxMemory > 0 {
+		runArgs = append(runArgs, "--memory", strconv.FormatInt(opts.Limits.MaxMemory, 10))
+	}
+
+	runArgs = append(runArgs, s.Args...)
+	runArgs = append(runArgs, s.Image, name)
+	return append(runArgs, args...)
+}
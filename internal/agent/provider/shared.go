@@ -2,9 +2,14 @@ package provider
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"os"
+	"os/exec"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Buffer size constants for scanner operations
@@ -15,9 +20,21 @@ const (
 	ScannerMaxBufSize = 1024 * 1024
 )
 
+// SignalExitGrace is how long a headless agent may keep running after it has
+// emitted a COMPLETE, CONTINUE or BLOCKED signal before it is stopped.
+// Agents normally exit right after signalling; the grace period lets them
+// flush final output (e.g. Claude's usage summary) before being killed.
+var SignalExitGrace = 5 * time.Second
+
+// pipeCloseDelay is how long output pipes stay open after an early stop, so
+// buffered output is still read. Closing them stops a child process that
+// inherited the pipes from keeping the run alive.
+const pipeCloseDelay = time.Second
+
 // streamOutput reads from reader and writes to both buffer and writer.
 // This is shared between providers for consistent output handling.
-func streamOutput(reader io.Reader, buf *strings.Builder, writer io.Writer) {
+// Each line is passed to watcher (may be nil) for mid-run signal detection.
+func streamOutput(reader io.Reader, buf *strings.Builder, writer io.Writer, watcher *signalWatcher) {
 	scanner := bufio.NewScanner(reader)
 	// Increase scanner buffer for long lines
 	scanner.Buffer(make([]byte, ScannerInitialBufSize), ScannerMaxBufSize)
@@ -27,5 +44,166 @@ func streamOutput(reader io.Reader, buf *strings.Builder, writer io.Writer) {
 		buf.WriteString(line)
 		buf.WriteString("\n")
 		fmt.Fprintln(writer, line)
+		watcher.observe(line)
+	}
+}
+
+// signalWatcher detects a terminal <promise> signal while agent output is
+// still streaming, and stops the process once it has had SignalExitGrace to
+// exit on its own. This saves the time and tokens an agent spends after it
+// has already reported its outcome.
+type signalWatcher struct {
+	mu       sync.Mutex
+	text     strings.Builder
+	fired    bool // Signal seen, stop scheduled
+	stopped  bool // Process was stopped by the watcher
+	released bool // Process exited; stop must not fire
+	timer    *time.Timer
+	cancel   context.CancelFunc
+	pipes    []io.Closer
+}
+
+// newSignalWatcher creates a watcher that stops a run by calling cancel (the
+// command's context) and then closing pipes.
+func newSignalWatcher(cancel context.CancelFunc, pipes ...io.Closer) *signalWatcher {
+	return &signalWatcher{cancel: cancel, pipes: pipes}
+}
+
+// observe records a chunk of agent text and schedules a stop once it
+// completes a COMPLETE, CONTINUE or BLOCKED signal. Safe to call on nil.
+func (w *signalWatcher) observe(text string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.fired || w.released {
+		return
+	}
+	w.text.WriteString(text)
+	w.text.WriteString("\n")
+	if !strings.Contains(text, "</promise>") {
+		return
+	}
+
+	probe := &RunResult{Output: w.text.String()}
+	parsePromiseSignals(probe)
+	if !probe.Complete && !probe.Continue && !probe.Blocked {
+		return
+	}
+
+	w.fired = true
+	w.timer = time.AfterFunc(SignalExitGrace, w.stop)
+}
+
+// stop kills the process, then closes its pipes after pipeCloseDelay
+func (w *signalWatcher) stop() {
+	w.mu.Lock()
+	if w.released {
+		w.mu.Unlock()
+		return
+	}
+	w.stopped = true
+	w.mu.Unlock()
+
+	fmt.Fprintf(os.Stderr, "\n⏹  Agent signalled but kept running, stopping it\n")
+	w.cancel()
+	time.AfterFunc(pipeCloseDelay, func() {
+		for _, pipe := range w.pipes {
+			pipe.Close()
+		}
+	})
+}
+
+// release marks the process as exited and returns whether the watcher stopped it.
+// Safe to call on nil.
+func (w *signalWatcher) release() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.released = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	return w.stopped
+}
+
+// waitWatched streams stdout and stderr to the console through watcher, then
+// waits for cmd to exit. Output is drained before Wait, which closes the pipes.
+func waitWatched(cmd *exec.Cmd, stdout, stderr io.Reader, watcher *signalWatcher) (string, error) {
+	var outputBuf strings.Builder
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		streamOutput(stdout, &outputBuf, os.Stdout, watcher)
+	}()
+	go func() {
+		defer wg.Done()
+		streamOutput(stderr, &outputBuf, os.Stderr, watcher)
+	}()
+
+	wg.Wait()
+	err := cmd.Wait()
+	return outputBuf.String(), err
+}
+
+// finishRun records on result how the agent process named name exited with
+// err. A process stopped early, after signalling or on request, hasn't
+// failed. Returns true when the run timed out or was cancelled, leaving
+// nothing further to parse.
+func finishRun(ctx context.Context, opts RunOptions, result *RunResult, name string, err error, stoppedEarly bool) bool {
+	if stoppedEarly {
+		// Killed after signalling or on request; the exit status isn't a failure
+		result.StoppedEarly = true
+		return false
+	}
+	if err == nil {
+		return false
+	}
+
+	// Check if this was a timeout
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.Error = fmt.Errorf("iteration timed out after %v", opts.Timeout)
+		return true
+	}
+	if ctx.Err() == context.Canceled {
+		result.Error = fmt.Errorf("run cancelled: %w", context.Cause(ctx))
+		return true
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+	}
+	result.Error = fmt.Errorf("%s exited with error: %w", name, err)
+	return false
+}
+
+// runContext returns the context for an agent process: opts.Context (or
+// Background when unset) with opts.Timeout applied
+func runContext(opts RunOptions) (context.Context, context.CancelFunc) {
+	parent := opts.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	if opts.Timeout > 0 {
+		return context.WithTimeout(parent, opts.Timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+// commandEnv returns the environment for an agent process: the current
+// environment plus opts.Env and any extra entries. Later entries win.
+// Returns nil (inherit) when there is nothing to add.
+func commandEnv(opts RunOptions, extra ...string) []string {
+	if len(opts.Env) == 0 && len(extra) == 0 {
+		return nil
 	}
+	env := append(os.Environ(), opts.Env...)
+	return append(env, extra...)
 }
@@ -11,3 +11,19 @@ var RefinePromptTemplate string
 func GetRefinePromptTemplate() string {
 	return RefinePromptTemplate
 }
+
+//go:embed review_prompt.md
+var ReviewPromptTemplate string
+
+// GetReviewPromptTemplate returns the embedded post-run review prompt template.
+func GetReviewPromptTemplate() string {
+	return ReviewPromptTemplate
+}
+
+//go:embed plan_prompt.md
+var PlanPromptTemplate string
+
+// GetPlanPromptTemplate returns the embedded --plan-first planning prompt template.
+func GetPlanPromptTemplate() string {
+	return PlanPromptTemplate
+}
@@ -0,0 +1,14 @@
+package agent
+
+import "github.com/ohare93/juggle/internal/agent/provider"
+
+// RetryPolicy computes backoff waits between retries of a failed agent run.
+// It lives in the provider package so providers can retry with the same policy.
+type RetryPolicy = provider.RetryPolicy
+
+// Default retry policies for rate limits, 529 overload exhaustion and crashes
+var (
+	DefaultRateLimitPolicy = provider.DefaultRateLimitPolicy
+	DefaultOverloadPolicy  = provider.DefaultOverloadPolicy
+	DefaultCrashPolicy     = provider.DefaultCrashPolicy
+)
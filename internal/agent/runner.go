@@ -74,15 +74,66 @@ func (r *ProviderRunner) Run(opts RunOptions) (*RunResult, error) {
 		}
 	}
 
+	// Providers without a system prompt flag would drop it, so fold it into the prompt
+	if opts.SystemPrompt != "" && !p.SupportsSystemPrompt() {
+		opts.Prompt = opts.SystemPrompt + "\n\n" + opts.Prompt
+		opts.SystemPrompt = ""
+	}
+
 	return p.Run(opts)
 }
 
+// Sandbox runs the agent CLI inside a docker or podman container
+type Sandbox = provider.Sandbox
+
+// SandboxRunner wraps another runner so the agent CLI runs inside a container
+// that can only see the sandbox's mounts
+type SandboxRunner struct {
+	Inner   Runner
+	Sandbox *Sandbox
+}
+
+// Run executes the inner runner with the sandbox applied
+func (r *SandboxRunner) Run(opts RunOptions) (*RunResult, error) {
+	opts.Sandbox = r.Sandbox
+	return r.Inner.Run(opts)
+}
+
+// ResourceLimits caps the CPU, disk and memory use of the agent CLI
+type ResourceLimits = provider.ResourceLimits
+
+// LimitsRunner wraps another runner so the agent CLI runs under resource limits
+type LimitsRunner struct {
+	Inner  Runner
+	Limits *ResourceLimits
+}
+
+// Run executes the inner runner with the limits applied
+func (r *LimitsRunner) Run(opts RunOptions) (*RunResult, error) {
+	opts.Limits = r.Limits
+	return r.Inner.Run(opts)
+}
+
 // DefaultRunner is the package-level runner used for agent operations.
 // It uses Claude by default but can be configured to use other providers.
 var DefaultRunner Runner = &ProviderRunner{
 	Provider: provider.NewClaudeProvider(),
 }
 
+// RunnerFor returns a runner of its own for one agent loop, with the given
+// provider and model overrides, so loops sharing a process don't switch each
+// other's provider. A DefaultRunner set with SetRunner (e.g. a test mock) is
+// returned as is.
+// This function is goroutine-safe.
+func RunnerFor(p provider.Provider, overrides provider.ModelOverrides) Runner {
+	runnerMu.RLock()
+	defer runnerMu.RUnlock()
+	if _, ok := DefaultRunner.(*ProviderRunner); ok {
+		return &ProviderRunner{Provider: p, ModelOverrides: overrides}
+	}
+	return DefaultRunner
+}
+
 // SetRunner sets the package-level runner (for testing).
 // This function is goroutine-safe.
 func SetRunner(r Runner) {
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -253,6 +254,57 @@ func TestDefaultRunner(t *testing.T) {
 		// Clean up
 		ResetRunner()
 	})
+
+	t.Run("RunnerFor gives each loop its own provider", func(t *testing.T) {
+		ResetRunner()
+		overrides := provider.ModelOverrides{"opus": "custom-opus"}
+		r, ok := RunnerFor(provider.NewOpenCodeProvider(), overrides).(*ProviderRunner)
+		if !ok {
+			t.Fatal("expected RunnerFor to return a *ProviderRunner")
+		}
+		if r.Provider.Type() != provider.TypeOpenCode || r.ModelOverrides["opus"] != "custom-opus" {
+			t.Errorf("unexpected runner: provider %s, overrides %v", r.Provider.Type(), r.ModelOverrides)
+		}
+		if p := GetProvider(); p == nil || p.Type() != provider.TypeClaude {
+			t.Error("expected RunnerFor to leave the default runner's provider alone")
+		}
+	})
+
+	t.Run("RunnerFor keeps a runner set with SetRunner", func(t *testing.T) {
+		mock := NewMockRunner(&RunResult{Output: "mock"})
+		SetRunner(mock)
+		defer ResetRunner()
+
+		if RunnerFor(provider.NewOpenCodeProvider(), nil) != Runner(mock) {
+			t.Error("expected RunnerFor to return the mock runner")
+		}
+	})
+}
+
+func TestProviderRunner_SystemPromptFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOutput string
+	}{
+		{"unsupported is prepended", []string{"-c", "cat"}, "SYSTEM\n\nPROMPT"},
+		{"supported is passed through", []string{"-c", "printf %s \"$0|\"; cat", "{{system_prompt}}"}, "SYSTEM|PROMPT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &ProviderRunner{
+				Provider: provider.NewCustomProvider("test", provider.CustomConfig{Binary: "sh", Args: tt.args}),
+			}
+			result, err := runner.Run(RunOptions{Prompt: "PROMPT", SystemPrompt: "SYSTEM", Mode: ModeHeadless})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if got := strings.TrimSpace(result.Output); got != tt.wantOutput {
+				t.Errorf("output = %q, want %q", got, tt.wantOutput)
+			}
+		})
+	}
 }
 
 func TestRunOptions_Modes(t *testing.T) {
@@ -0,0 +1,196 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+)
+
+// crashWindow is how long after a crash another one still counts toward
+// max_crash_restarts; a run that lasts longer starts the count over
+const crashWindow = time.Hour
+
+// CrashedRun describes a daemon run whose process died without cleaning up
+type CrashedRun struct {
+	PID           int    `json:"pid"`
+	SessionID     string `json:"session_id"`
+	BallID        string `json:"ball_id,omitempty"`
+	Iteration     int    `json:"iteration,omitempty"` // Iteration the run died in, 0 when unknown
+	MaxIterations int    `json:"max_iterations,omitempty"`
+	Trust         bool   `json:"trust,omitempty"`
+	Model         string `json:"model,omitempty"`
+	Provider      string `json:"provider,omitempty"`
+}
+
+// Remaining returns the iterations a restart runs: the rest of the run,
+// starting over the iteration it died in
+func (c *CrashedRun) Remaining() int {
+	if c.MaxIterations <= 0 {
+		return 0
+	}
+	return max(1, c.MaxIterations-max(c.Iteration, 1)+1)
+}
+
+// crashRecord counts a session's crashes in a row
+type crashRecord struct {
+	count     int
+	lastCrash time.Time
+	pending   bool // A restart is waiting out its backoff
+}
+
+// crashedRun describes the run of a crashed daemon's PID file and last state
+func crashedRun(info *daemon.Info, state *daemon.State) *CrashedRun {
+	run := &CrashedRun{
+		PID:           info.PID,
+		SessionID:     info.SessionID,
+		BallID:        info.BallID,
+		MaxIterations: info.MaxIterations,
+		Trust:         info.Trust,
+		Model:         info.Model,
+		Provider:      info.Provider,
+	}
+	run.Iteration = state.Iteration
+	if state.MaxIterations > 0 {
+		run.MaxIterations = state.MaxIterations
+	}
+	return run
+}
+
+// handleCrash logs a crashed daemon and, with auto_restart, restarts its run
+// after a backoff that doubles with each crash in a row. After
+// max_crash_restarts restarts the session is left alone until a restarted
+// run outlasts crashWindow.
+func (s *Supervisor) handleCrash(st Status) {
+	crash := st.Crash
+	key := loopKey(st.ProjectDir, st.SessionID)
+
+	// Each crash is handled once
+	if state, err := daemon.ReadStateFile(st.ProjectDir, st.SessionID); err == nil && state.Crashed != nil {
+		state.Crashed = nil
+		if err := daemon.WriteStateFile(st.ProjectDir, st.SessionID, state); err != nil {
+			fmt.Fprintf(os.Stderr, "[supervisor] Failed to update state of %s: %v\n", st.SessionID, err)
+			return
+		}
+	}
+
+	s.crashMu.Lock()
+	if s.crashes == nil {
+		s.crashes = make(map[string]*crashRecord)
+	}
+	rec := s.crashes[key]
+	if rec == nil || time.Since(rec.lastCrash) > crashWindow {
+		rec = &crashRecord{}
+		s.crashes[key] = rec
+	}
+	rec.count++
+	rec.lastCrash = time.Now()
+	count := rec.count
+	s.crashMu.Unlock()
+
+	where := "iteration unknown"
+	if crash.Iteration > 0 {
+		where = fmt.Sprintf("iteration %d/%d", crash.Iteration, crash.MaxIterations)
+	}
+	fmt.Fprintf(os.Stderr, "[supervisor] Daemon for %s/%s (PID %d) exited unexpectedly in %s\n",
+		st.ProjectDir, st.SessionID, crash.PID, where)
+
+	if !s.config.AutoRestart {
+		return
+	}
+	maxRestarts := s.config.GetMaxCrashRestarts()
+	if count > maxRestarts {
+		fmt.Fprintf(os.Stderr, "[supervisor] Not restarting %s/%s: it crashed %d times in a row (max_crash_restarts is %d)\n",
+			st.ProjectDir, st.SessionID, count, maxRestarts)
+		return
+	}
+
+	delay := s.config.GetCrashBackoff(count)
+	fmt.Fprintf(os.Stderr, "[supervisor] Restarting %s/%s in %v (restart %d of %d)\n",
+		st.ProjectDir, st.SessionID, delay, count, maxRestarts)
+	s.crashMu.Lock()
+	rec.pending = true
+	s.crashMu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.crashMu.Lock()
+			rec.pending = false
+			s.crashMu.Unlock()
+		}()
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(delay):
+		}
+		if err := s.restartCrashed(st); err != nil {
+			fmt.Fprintf(os.Stderr, "[supervisor] Failed to restart %s/%s: %v\n", st.ProjectDir, st.SessionID, err)
+		}
+	}()
+}
+
+// restartPending reports whether a crashed run of the session is waiting to
+// be restarted
+func (s *Supervisor) restartPending(st Status) bool {
+	s.crashMu.Lock()
+	defer s.crashMu.Unlock()
+	rec := s.crashes[loopKey(st.ProjectDir, st.SessionID)]
+	return rec != nil && rec.pending
+}
+
+// restartCrashed runs a crashed run's remaining iterations again, unless the
+// session got a new run in the meantime
+func (s *Supervisor) restartCrashed(st Status) error {
+	crash := st.Crash
+	if daemon.IsAlive(st.ProjectDir, st.SessionID) || s.hasOwnLoop(st) {
+		fmt.Fprintf(os.Stderr, "[supervisor] Not restarting %s/%s: a run is already going\n", st.ProjectDir, st.SessionID)
+		return nil
+	}
+	sessionID := crash.SessionID
+	if sessionID == "" {
+		sessionID = st.SessionID
+	}
+
+	if s.inProcess() {
+		s.Submit(LoopRequest{
+			ProjectDir: st.ProjectDir,
+			SessionID:  sessionID,
+			BallID:     crash.BallID,
+			Iterations: crash.Remaining(),
+			Trust:      crash.Trust,
+			Model:      crash.Model,
+			Provider:   crash.Provider,
+		})
+		return nil
+	}
+	return s.startDaemon(st.ProjectDir, st.SessionID, restartArgs(sessionID, crash))
+}
+
+// restartArgs are the juggle arguments running a crashed run again
+func restartArgs(sessionID string, crash *CrashedRun) []string {
+	args := []string{"agent", "run", "--daemon", sessionID}
+	n := crash.Remaining()
+	if n == 0 && crash.BallID != "" {
+		n = 1 // --ball without -n runs interactively
+	}
+	if n > 0 {
+		args = append(args, "-n", strconv.Itoa(n))
+	}
+	if crash.BallID != "" {
+		args = append(args, "--ball", crash.BallID)
+	}
+	if crash.Trust {
+		args = append(args, "--trust")
+	}
+	if crash.Model != "" {
+		args = append(args, "--model", crash.Model)
+	}
+	if crash.Provider != "" {
+		args = append(args, "--provider", crash.Provider)
+	}
+	return args
+}
@@ -0,0 +1,82 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestRestartArgs(t *testing.T) {
+	crash := &CrashedRun{SessionID: "feat", BallID: "p-3", Iteration: 2, MaxIterations: 5, Trust: true, Model: "opus", Provider: "claude"}
+	want := []string{"agent", "run", "--daemon", "feat", "-n", "4", "--ball", "p-3", "--trust", "--model", "opus", "--provider", "claude"}
+	if got := restartArgs("feat", crash); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// A ball run of unknown length runs one iteration rather than interactively
+	crash = &CrashedRun{SessionID: "feat", BallID: "p-3"}
+	want = []string{"agent", "run", "--daemon", "feat", "-n", "1", "--ball", "p-3"}
+	if got := restartArgs("feat", crash); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// crashDaemon leaves a session's files as a daemon killed mid-run does
+func crashDaemon(t *testing.T, projectDir, sessionID string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".juggle", "sessions", sessionID), 0755); err != nil {
+		t.Fatal(err)
+	}
+	info := &daemon.Info{PID: 999999999, SessionID: sessionID, ProjectDir: projectDir, MaxIterations: 5, BallID: "p-3", Trust: true}
+	if err := daemon.WritePIDFile(projectDir, sessionID, info); err != nil {
+		t.Fatal(err)
+	}
+	state := &daemon.State{Running: true, Iteration: 2, MaxIterations: 5}
+	if err := daemon.WriteStateFile(projectDir, sessionID, state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCrashedDaemonIsRestarted(t *testing.T) {
+	s, loops := newLoopSupervisor(t, &session.SupervisorConfig{AutoRestart: true, MaxCrashRestarts: 1, CrashBackoffSeconds: 1})
+	projectDir := t.TempDir()
+	crashDaemon(t, projectDir, "feat")
+
+	st := s.checkSession(projectDir, "feat", time.Hour)
+	if st.Running || st.Crash == nil || st.Crash.PID != 999999999 || st.Crash.Remaining() != 4 {
+		t.Fatalf("expected a crash with 4 iterations left, got %+v (crash %+v)", st, st.Crash)
+	}
+
+	s.handlePollResults([]Status{st})
+	if !s.restartPending(st) {
+		t.Fatal("expected a restart to wait out its backoff")
+	}
+	// The crash is handled once, however often the session is polled
+	if again := s.checkSession(projectDir, "feat", time.Hour); again.Crash != nil {
+		t.Errorf("expected the crash to be handled, got %+v", again.Crash)
+	}
+
+	waitFor(t, "the run to restart", func() bool { return loops.startedCount() == 1 })
+	want := LoopRequest{ProjectDir: projectDir, SessionID: "feat", BallID: "p-3", Iterations: 4, Trust: true}
+	loops.mu.Lock()
+	got := loops.started[0]
+	loops.mu.Unlock()
+	got.RequestedAt = time.Time{}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	loops.finish(t, "feat/p-3")
+
+	// Past max_crash_restarts the session is left alone
+	crashDaemon(t, projectDir, "feat")
+	st = s.checkSession(projectDir, "feat", time.Hour)
+	s.handlePollResults([]Status{st})
+	if s.restartPending(st) {
+		t.Error("expected no restart past max_crash_restarts")
+	}
+}
@@ -0,0 +1,398 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/process"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// InboxPollInterval is how often juggle daemon picks up loops requested by
+// juggle agent run --daemon
+const InboxPollInterval = 2 * time.Second
+
+// Loop states reported in LoopStatus
+const (
+	LoopQueued  = "queued"
+	LoopRunning = "running"
+)
+
+// LoopRequest asks the daemon to run an agent loop for a session
+type LoopRequest struct {
+	ProjectDir  string    `json:"project_dir"`
+	SessionID   string    `json:"session_id"`
+	BallID      string    `json:"ball_id,omitempty"`
+	Iterations  int       `json:"iterations,omitempty"` // 0 = the agent run default
+	Trust       bool      `json:"trust,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	Provider    string    `json:"provider,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// StorageID is the session directory the loop works in ("_all" for "all")
+func (r LoopRequest) StorageID() string {
+	if r.SessionID == "all" {
+		return "_all"
+	}
+	return r.SessionID
+}
+
+// LoopRunner runs an agent loop in the daemon's process until it ends or ctx
+// is cancelled
+type LoopRunner func(ctx context.Context, req LoopRequest) error
+
+// LoopStatus is a loop the daemon is running or has queued
+type LoopStatus struct {
+	LoopRequest
+	State     string    `json:"state"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+}
+
+// DaemonStatus is what a running juggle daemon publishes for juggle daemon status
+type DaemonStatus struct {
+	PID       int          `json:"pid"`
+	StartedAt time.Time    `json:"started_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Loops     []LoopStatus `json:"loops"`
+}
+
+// loop is an agent loop the daemon started
+type loop struct {
+	req       LoopRequest
+	cancel    context.CancelFunc
+	startedAt time.Time
+	restart   bool // Run the request again once the loop returns (stalled)
+}
+
+// SetLoopRunner makes the supervisor run agent loops in its own process with
+// run instead of forking a daemon per session, and pick up loops requested by
+// juggle agent run --daemon. Call it before Start.
+func (s *Supervisor) SetLoopRunner(run LoopRunner) {
+	s.loopMu.Lock()
+	defer s.loopMu.Unlock()
+	s.runLoop = run
+	s.loops = make(map[string][]*loop)
+}
+
+// inProcess reports whether loops run in the supervisor's process
+func (s *Supervisor) inProcess() bool {
+	s.loopMu.Lock()
+	defer s.loopMu.Unlock()
+	return s.runLoop != nil
+}
+
+// loopKey identifies a session across projects
+func loopKey(projectDir, storageID string) string {
+	return projectDir + "\x00" + storageID
+}
+
+// Submit queues a loop; it starts once fewer than max_concurrent loops are
+// running in all, and fewer than max_per_session in its session. A request
+// for a ball that already has a loop running or queued is dropped.
+func (s *Supervisor) Submit(req LoopRequest) {
+	if req.RequestedAt.IsZero() {
+		req.RequestedAt = time.Now()
+	}
+	s.loopMu.Lock()
+	if s.hasLoopLocked(req) {
+		s.loopMu.Unlock()
+		fmt.Fprintf(os.Stderr, "[daemon] %s/%s already has a loop, ignoring the request\n", req.ProjectDir, req.SessionID)
+		return
+	}
+	s.queue = append(s.queue, req)
+	s.loopMu.Unlock()
+	s.dispatch()
+}
+
+// HasLoop reports whether a loop for the session is running or queued
+func (s *Supervisor) HasLoop(projectDir, storageID string) bool {
+	s.loopMu.Lock()
+	defer s.loopMu.Unlock()
+	if len(s.loops[loopKey(projectDir, storageID)]) > 0 {
+		return true
+	}
+	for _, req := range s.queue {
+		if req.ProjectDir == projectDir && req.StorageID() == storageID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLoopLocked reports whether the same session and ball already has a loop
+// running or queued. Caller holds loopMu.
+func (s *Supervisor) hasLoopLocked(req LoopRequest) bool {
+	for _, l := range s.loops[loopKey(req.ProjectDir, req.StorageID())] {
+		if l.req.BallID == req.BallID {
+			return true
+		}
+	}
+	for _, queued := range s.queue {
+		if queued.ProjectDir == req.ProjectDir && queued.StorageID() == req.StorageID() && queued.BallID == req.BallID {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch starts queued loops, oldest first, while the limits allow
+func (s *Supervisor) dispatch() {
+	s.loopMu.Lock()
+	if s.runLoop == nil || s.loopCtx == nil || s.loopCtx.Err() != nil {
+		s.loopMu.Unlock()
+		return // Not started yet, or stopping
+	}
+	defer s.writeLoopsFile()
+	defer s.loopMu.Unlock()
+
+	maxConcurrent := s.config.GetMaxConcurrent()
+	maxPerSession := s.config.GetMaxPerSession()
+	kept := s.queue[:0]
+	for _, req := range s.queue {
+		key := loopKey(req.ProjectDir, req.StorageID())
+		if s.runningLocked() >= maxConcurrent || len(s.loops[key]) >= maxPerSession {
+			kept = append(kept, req)
+			continue
+		}
+		ctx, cancel := context.WithCancel(s.loopCtx)
+		l := &loop{req: req, cancel: cancel, startedAt: time.Now()}
+		s.loops[key] = append(s.loops[key], l)
+		s.loopWG.Add(1)
+		go s.runLoopAsync(ctx, l)
+	}
+	s.queue = kept
+}
+
+// runningLocked counts the running loops. Caller holds loopMu.
+func (s *Supervisor) runningLocked() int {
+	n := 0
+	for _, loops := range s.loops {
+		n += len(loops)
+	}
+	return n
+}
+
+// runLoopAsync runs a loop, then frees its slot for the next queued one
+func (s *Supervisor) runLoopAsync(ctx context.Context, l *loop) {
+	defer s.loopWG.Done()
+	req := l.req
+	fmt.Fprintf(os.Stderr, "[daemon] Starting loop for %s/%s\n", req.ProjectDir, req.SessionID)
+	err := s.runLoop(ctx, req)
+	l.cancel()
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "[daemon] Loop for %s/%s failed: %v\n", req.ProjectDir, req.SessionID, err)
+	default:
+		fmt.Fprintf(os.Stderr, "[daemon] Loop for %s/%s ended\n", req.ProjectDir, req.SessionID)
+	}
+
+	s.loopMu.Lock()
+	key := loopKey(req.ProjectDir, req.StorageID())
+	loops := s.loops[key]
+	for i, running := range loops {
+		if running == l {
+			loops = append(loops[:i], loops[i+1:]...)
+			break
+		}
+	}
+	if len(loops) == 0 {
+		delete(s.loops, key)
+	} else {
+		s.loops[key] = loops
+	}
+	if l.restart && s.loopCtx.Err() == nil {
+		req.RequestedAt = time.Now()
+		s.queue = append(s.queue, req)
+	}
+	s.loopMu.Unlock()
+	s.dispatch()
+}
+
+// restartLoops cancels a stalled session's loops and runs them again once
+// they return. Returns whether the session had any.
+func (s *Supervisor) restartLoops(projectDir, storageID string) bool {
+	s.loopMu.Lock()
+	defer s.loopMu.Unlock()
+	loops := s.loops[loopKey(projectDir, storageID)]
+	for _, l := range loops {
+		l.restart = true
+		l.cancel()
+	}
+	return len(loops) > 0
+}
+
+// Loops returns the running loops, then the queued ones
+func (s *Supervisor) Loops() []LoopStatus {
+	s.loopMu.Lock()
+	defer s.loopMu.Unlock()
+	var statuses []LoopStatus
+	for _, loops := range s.loops {
+		for _, l := range loops {
+			statuses = append(statuses, LoopStatus{LoopRequest: l.req, State: LoopRunning, StartedAt: l.startedAt})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].StartedAt.Before(statuses[j].StartedAt)
+	})
+	for _, req := range s.queue {
+		statuses = append(statuses, LoopStatus{LoopRequest: req, State: LoopQueued})
+	}
+	return statuses
+}
+
+// startLoops begins running loops and watching the request inbox
+func (s *Supervisor) startLoops() error {
+	dir := daemonDir(s.configOpts)
+	if err := os.MkdirAll(filepath.Join(dir, requestsDir), 0755); err != nil {
+		return fmt.Errorf("failed to create daemon directory: %w", err)
+	}
+	s.loopMu.Lock()
+	s.loopCtx, s.cancelLoops = context.WithCancel(context.Background())
+	s.loopsStarted = time.Now()
+	s.loopMu.Unlock()
+	s.writeLoopsFile()
+
+	s.wg.Add(1)
+	go s.inboxLoop()
+	return nil
+}
+
+// stopLoops cancels the running loops, waits for them to end and removes
+// the daemon's status file. Queued loops are dropped.
+func (s *Supervisor) stopLoops() {
+	s.loopMu.Lock()
+	s.cancelLoops()
+	s.queue = nil
+	s.loopMu.Unlock()
+	s.loopWG.Wait()
+	os.Remove(filepath.Join(daemonDir(s.configOpts), loopsFile))
+}
+
+// inboxLoop submits the loops requested by juggle agent run --daemon
+func (s *Supervisor) inboxLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(InboxPollInterval)
+	defer ticker.Stop()
+	for {
+		s.readInbox()
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readInbox submits and removes each request in the inbox, oldest first
+func (s *Supervisor) readInbox() {
+	dir := filepath.Join(daemonDir(s.configOpts), requestsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	// Request files are named by time, so name order is request order
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		os.Remove(path)
+		if err != nil {
+			continue
+		}
+		var req LoopRequest
+		if err := json.Unmarshal(data, &req); err != nil || req.ProjectDir == "" || req.SessionID == "" {
+			fmt.Fprintf(os.Stderr, "[daemon] Ignoring invalid request %s\n", entry.Name())
+			continue
+		}
+		s.Submit(req)
+	}
+}
+
+// writeLoopsFile publishes the daemon's loops for juggle daemon status
+func (s *Supervisor) writeLoopsFile() {
+	status := DaemonStatus{
+		PID:       os.Getpid(),
+		StartedAt: s.loopsStarted,
+		UpdatedAt: time.Now(),
+		Loops:     s.Loops(),
+	}
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return
+	}
+	path := filepath.Join(daemonDir(s.configOpts), loopsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
+
+const (
+	requestsDir = "requests"
+	loopsFile   = "loops.json"
+)
+
+// daemonDir is where juggle daemon keeps its status and request inbox,
+// ~/.juggle/daemon by default
+func daemonDir(opts session.ConfigOptions) string {
+	if opts.ConfigHome == "" {
+		opts.ConfigHome, _ = os.UserHomeDir()
+	}
+	if opts.JuggleDirName == "" {
+		opts.JuggleDirName = ".juggle"
+	}
+	return filepath.Join(opts.ConfigHome, opts.JuggleDirName, "daemon")
+}
+
+// RequestLoop hands a loop to the running juggle daemon. It starts within a
+// few seconds, subject to the daemon's limits.
+func RequestLoop(opts session.ConfigOptions, req LoopRequest) error {
+	if req.RequestedAt.IsZero() {
+		req.RequestedAt = time.Now()
+	}
+	dir := filepath.Join(daemonDir(opts), requestsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create daemon request directory: %w", err)
+	}
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.json", req.RequestedAt.UTC().Format("20060102T150405.000000000"), os.Getpid())
+	// Write then rename, so the daemon never reads half a request
+	tmp := filepath.Join(dir, "."+name)
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write daemon request: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, name))
+}
+
+// ReadDaemonStatus returns what a running juggle daemon last published.
+// Returns nil when no daemon is running.
+func ReadDaemonStatus(opts session.ConfigOptions) (*DaemonStatus, error) {
+	data, err := os.ReadFile(filepath.Join(daemonDir(opts), loopsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var status DaemonStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon status: %w", err)
+	}
+	if !process.RunningSince(status.PID, status.StartedAt) {
+		return nil, nil
+	}
+	return &status, nil
+}
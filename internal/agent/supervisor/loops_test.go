@@ -0,0 +1,186 @@
+package supervisor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// fakeLoops runs loops that block until released or cancelled
+type fakeLoops struct {
+	mu      sync.Mutex
+	started []LoopRequest
+	release map[string]chan struct{}
+}
+
+func (f *fakeLoops) run(ctx context.Context, req LoopRequest) error {
+	f.mu.Lock()
+	f.started = append(f.started, req)
+	ch := make(chan struct{})
+	f.release[req.SessionID+"/"+req.BallID] = ch
+	f.mu.Unlock()
+	select {
+	case <-ch:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func (f *fakeLoops) finish(t *testing.T, key string) {
+	t.Helper()
+	f.mu.Lock()
+	ch := f.release[key]
+	f.mu.Unlock()
+	if ch == nil {
+		t.Fatalf("loop %s was not started", key)
+	}
+	close(ch)
+}
+
+func (f *fakeLoops) startedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.started)
+}
+
+// loopStates maps "session/ball" to each loop's state
+func loopStates(s *Supervisor) map[string]string {
+	states := make(map[string]string)
+	for _, l := range s.Loops() {
+		states[l.SessionID+"/"+l.BallID] = l.State
+	}
+	return states
+}
+
+// waitFor polls cond for up to a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newLoopSupervisor(t *testing.T, config *session.SupervisorConfig) (*Supervisor, *fakeLoops) {
+	t.Helper()
+	s := New(config, session.ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"})
+	loops := &fakeLoops{release: make(map[string]chan struct{})}
+	s.SetLoopRunner(loops.run)
+	if err := s.startLoops(); err != nil {
+		t.Fatalf("startLoops: %v", err)
+	}
+	t.Cleanup(func() {
+		close(s.stopCh)
+		s.wg.Wait()
+		s.stopLoops()
+	})
+	return s, loops
+}
+
+func TestLoopLimits(t *testing.T) {
+	s, loops := newLoopSupervisor(t, &session.SupervisorConfig{MaxConcurrent: 2, MaxPerSession: 1})
+
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "a"})
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "a", BallID: "p-2"})
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "b"})
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "c"})
+	waitFor(t, "two loops to start", func() bool { return loops.startedCount() == 2 })
+
+	want := map[string]string{"a/": LoopRunning, "a/p-2": LoopQueued, "b/": LoopRunning, "c/": LoopQueued}
+	if got := loopStates(s); !equalStates(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// The session's next loop takes the freed slot, oldest request first
+	loops.finish(t, "a/")
+	waitFor(t, "a/p-2 to start", func() bool { return loopStates(s)["a/p-2"] == LoopRunning })
+	if got := loopStates(s)["c/"]; got != LoopQueued {
+		t.Errorf("expected c to wait for a free slot, got %q", got)
+	}
+
+	loops.finish(t, "b/")
+	waitFor(t, "c to start", func() bool { return loopStates(s)["c/"] == LoopRunning })
+}
+
+func TestLoopSubmitDropsDuplicates(t *testing.T) {
+	s, loops := newLoopSupervisor(t, &session.SupervisorConfig{MaxConcurrent: 3})
+
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "a"})
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "a"})
+	s.Submit(LoopRequest{ProjectDir: "/other", SessionID: "a"})
+	waitFor(t, "two loops to start", func() bool { return loops.startedCount() == 2 })
+
+	if got := len(s.Loops()); got != 2 {
+		t.Errorf("expected the duplicate request to be dropped, got %d loops", got)
+	}
+	if !s.HasLoop("/p", "a") || s.HasLoop("/p", "b") {
+		t.Error("expected HasLoop to find only session a")
+	}
+}
+
+func TestRestartLoops(t *testing.T) {
+	s, loops := newLoopSupervisor(t, &session.SupervisorConfig{MaxConcurrent: 3})
+
+	s.Submit(LoopRequest{ProjectDir: "/p", SessionID: "all"})
+	waitFor(t, "the loop to start", func() bool { return loops.startedCount() == 1 })
+
+	if !s.restartLoops("/p", "_all") {
+		t.Fatal("expected the session to have a loop to restart")
+	}
+	waitFor(t, "the loop to run again", func() bool { return loops.startedCount() == 2 })
+	if s.restartLoops("/p", "other") {
+		t.Error("expected no loop to restart for another session")
+	}
+}
+
+func TestLoopInboxAndStatus(t *testing.T) {
+	s, loops := newLoopSupervisor(t, &session.SupervisorConfig{MaxConcurrent: 3})
+
+	if err := RequestLoop(s.configOpts, LoopRequest{ProjectDir: "/p", SessionID: "a", Iterations: 4, Trust: true}); err != nil {
+		t.Fatalf("RequestLoop: %v", err)
+	}
+	s.readInbox()
+	waitFor(t, "the requested loop to start", func() bool { return loops.startedCount() == 1 })
+	if req := loops.started[0]; req.Iterations != 4 || !req.Trust {
+		t.Errorf("expected the request's settings to be kept, got %+v", req)
+	}
+
+	status, err := ReadDaemonStatus(s.configOpts)
+	if err != nil || status == nil {
+		t.Fatalf("expected a daemon status, got %v, %v", status, err)
+	}
+	if len(status.Loops) != 1 || status.Loops[0].State != LoopRunning || status.Loops[0].SessionID != "a" {
+		t.Errorf("unexpected loops in status: %+v", status.Loops)
+	}
+
+	// The request was taken out of the inbox
+	s.readInbox()
+	if got := loops.startedCount(); got != 1 {
+		t.Errorf("expected the request to run once, got %d", got)
+	}
+}
+
+func TestReadDaemonStatusWithoutDaemon(t *testing.T) {
+	status, err := ReadDaemonStatus(session.ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"})
+	if err != nil || status != nil {
+		t.Errorf("expected no status, got %v, %v", status, err)
+	}
+}
+
+func equalStates(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -3,20 +3,24 @@
 package supervisor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/process"
 	"github.com/ohare93/juggle/internal/session"
 )
 
 // Supervisor monitors running juggle daemon sessions, detects stalls,
 // recovers missed signals, and optionally auto-launches/restarts daemons.
+// With a LoopRunner set (juggle daemon) it runs the agent loops itself.
 type Supervisor struct {
 	config     *session.SupervisorConfig
 	configOpts session.ConfigOptions
@@ -26,6 +30,20 @@ type Supervisor struct {
 	mu         sync.Mutex
 	running    bool
 	pidFile    string
+
+	// In-process loops, see SetLoopRunner
+	loopMu       sync.Mutex
+	runLoop      LoopRunner
+	loops        map[string][]*loop // Running loops by loopKey
+	queue        []LoopRequest      // Loops waiting for a free slot
+	loopCtx      context.Context
+	cancelLoops  context.CancelFunc
+	loopWG       sync.WaitGroup
+	loopsStarted time.Time
+
+	// Crashed daemons by loopKey, see handleCrash
+	crashMu sync.Mutex
+	crashes map[string]*crashRecord
 }
 
 // Status represents the supervisor's view of a session
@@ -41,6 +59,9 @@ type Status struct {
 	InProgress  int       `json:"in_progress_balls"`
 	Complete    int       `json:"complete_balls"`
 	Blocked     int       `json:"blocked_balls"`
+
+	Schedule *session.Schedule `json:"schedule,omitempty"` // Scheduled runs of the session, if any
+	Crash    *CrashedRun       `json:"crash,omitempty"`    // Run found dead this poll, if any
 }
 
 // New creates a new Supervisor with the given config
@@ -74,6 +95,7 @@ func (s *Supervisor) Start() error {
 	pidData := map[string]interface{}{
 		"pid":        os.Getpid(),
 		"started_at": time.Now(),
+		"in_process": s.runLoop != nil,
 	}
 	data, _ := json.MarshalIndent(pidData, "", "  ")
 	if err := os.WriteFile(s.pidFile, data, 0644); err != nil {
@@ -83,6 +105,14 @@ func (s *Supervisor) Start() error {
 	s.running = true
 	s.stopCh = make(chan struct{})
 
+	if s.inProcess() {
+		if err := s.startLoops(); err != nil {
+			s.running = false
+			os.Remove(s.pidFile)
+			return err
+		}
+	}
+
 	s.wg.Add(1)
 	go s.pollLoop()
 
@@ -101,6 +131,9 @@ func (s *Supervisor) Stop() {
 	s.mu.Unlock()
 
 	s.wg.Wait()
+	if s.inProcess() {
+		s.stopLoops()
+	}
 
 	// Clean up PID file
 	if s.pidFile != "" {
@@ -167,19 +200,32 @@ func (s *Supervisor) pollSessions() ([]Status, error) {
 	stallTimeout := time.Duration(s.config.GetStallTimeout()) * time.Minute
 
 	for _, projectDir := range projects {
+		if s.config.AutoReap {
+			s.reapStaleStorage(projectDir)
+		}
+		s.ageBalls(projectDir)
+
 		sessionsDir := filepath.Join(projectDir, ".juggle", "sessions")
 		entries, err := os.ReadDir(sessionsDir)
 		if err != nil {
 			continue // No sessions in this project
 		}
+		schedules := s.loadSchedules(projectDir)
 
 		for _, entry := range entries {
-			if !entry.IsDir() || entry.Name() == "_all" {
+			// The "all" meta-session is only watched when it is scheduled
+			if !entry.IsDir() || (entry.Name() == "_all" && schedules["_all"] == nil) {
 				continue
 			}
 
 			sessionID := entry.Name()
 			status := s.checkSession(projectDir, sessionID, stallTimeout)
+			if sched := schedules[sessionID]; sched != nil {
+				status.Schedule = sched
+				if !status.Running {
+					status.Status += fmt.Sprintf(" (next scheduled run %s)", sched.NextRun.Format("2006-01-02 15:04"))
+				}
+			}
 			statuses = append(statuses, status)
 		}
 	}
@@ -187,6 +233,63 @@ func (s *Supervisor) pollSessions() ([]Status, error) {
 	return statuses, nil
 }
 
+// loadSchedules returns a project's scheduled runs, keyed by session directory
+func (s *Supervisor) loadSchedules(projectDir string) map[string]*session.Schedule {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return nil
+	}
+	schedules, err := sessionStore.ListSchedules()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[supervisor] failed to load schedules in %s: %v\n", projectDir, err)
+		return nil
+	}
+	return schedules
+}
+
+// ageBalls raises the priority of a project's pending balls that sat untouched
+// for the project's aging.after_days
+func (s *Supervisor) ageBalls(projectDir string) {
+	aging, err := session.GetProjectAging(projectDir)
+	if err != nil || aging.After() <= 0 {
+		return
+	}
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		return
+	}
+	aged, err := store.AgeBalls(aging, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[supervisor] aging error in %s: %v\n", projectDir, err)
+		return
+	}
+	for _, ball := range aged {
+		fmt.Fprintf(os.Stderr, "[supervisor] Aged %s to %s priority\n", ball.ID, ball.Priority)
+	}
+}
+
+// reapStaleStorage removes a project's session storage that has no session or
+// no recent activity, leaving sessions with a running daemon alone
+func (s *Supervisor) reapStaleStorage(projectDir string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return
+	}
+	stale, err := sessionStore.FindStaleStorage(session.ReapOptions{
+		Idle: s.config.GetReapIdle(),
+		Active: func(sessionID string) bool {
+			return daemon.IsAlive(projectDir, sessionID)
+		},
+	})
+	if err != nil || len(stale) == 0 {
+		return
+	}
+	if err := session.RemoveStaleStorage(stale); err != nil {
+		fmt.Fprintf(os.Stderr, "[supervisor] reap error: %v\n", err)
+	}
+	fmt.Fprintf(os.Stderr, "[supervisor] Reaped %d stale session storage item(s) in %s\n", len(stale), projectDir)
+}
+
 // checkSession inspects a single session's state
 func (s *Supervisor) checkSession(projectDir, sessionID string, stallTimeout time.Duration) Status {
 	status := Status{
@@ -247,10 +350,15 @@ func (s *Supervisor) checkSession(projectDir, sessionID string, stallTimeout tim
 	} else {
 		// Not running - check if there's a stale state file
 		state, err := daemon.ReadStateFile(projectDir, sessionID)
-		if err == nil && state != nil && state.Running {
+		if err == nil && state != nil && state.Crashed != nil {
+			// IsRunning found the daemon dead mid-run, see handleCrash
+			status.Crash = crashedRun(state.Crashed, state)
+			status.DaemonPID = state.Crashed.PID
+			status.Status = fmt.Sprintf("Crashed (PID %d)", state.Crashed.PID)
+		} else if err == nil && state != nil && state.Running {
 			// State says running but process is dead - fix it
 			state.Running = false
-			state.Status = "Daemon exited unexpectedly"
+			state.Status = daemon.StatusCrashed
 			daemon.WriteStateFile(projectDir, sessionID, state)
 			status.Status = "Dead (state corrected)"
 		} else if status.Pending > 0 || status.InProgress > 0 {
@@ -276,7 +384,21 @@ func (s *Supervisor) handlePollResults(statuses []Status) {
 		}
 	}
 
+	now := time.Now()
 	for _, st := range statuses {
+		if st.Crash != nil {
+			s.handleCrash(st)
+			continue
+		}
+
+		// Launch scheduled runs that are due
+		if st.Schedule != nil && st.Schedule.Due(now) {
+			if s.fireSchedule(st, runningCount+launched < maxConcurrent) {
+				launched++
+				continue
+			}
+		}
+
 		// Handle stalled daemons
 		if st.Stalled && s.config.AutoRestart {
 			fmt.Fprintf(os.Stderr, "[supervisor] Restarting stalled daemon: %s/%s (PID %d)\n",
@@ -285,6 +407,11 @@ func (s *Supervisor) handlePollResults(statuses []Status) {
 			// Try to recover signals from OpenCode first
 			s.bridge.RecoverSession(st.ProjectDir, st.SessionID)
 
+			// A loop of our own is cancelled and runs again once it returns
+			if st.DaemonPID == os.Getpid() && s.restartLoops(st.ProjectDir, st.SessionID) {
+				continue
+			}
+
 			// Kill the stalled process and restart
 			if st.DaemonPID > 0 {
 				if proc, err := os.FindProcess(st.DaemonPID); err == nil {
@@ -303,7 +430,7 @@ func (s *Supervisor) handlePollResults(statuses []Status) {
 		}
 
 		// Auto-launch for sessions with pending work and no daemon
-		if s.config.AutoLaunch && !st.Running && (st.Pending > 0 || st.InProgress > 0) {
+		if s.config.AutoLaunch && st.SessionID != "_all" && !st.Running && (st.Pending > 0 || st.InProgress > 0) && !s.hasOwnLoop(st) && !s.restartPending(st) {
 			if runningCount+launched < maxConcurrent {
 				fmt.Fprintf(os.Stderr, "[supervisor] Auto-launching daemon for %s/%s (%d pending, %d in progress)\n",
 					st.ProjectDir, st.SessionID, st.Pending, st.InProgress)
@@ -322,16 +449,77 @@ func (s *Supervisor) handlePollResults(statuses []Status) {
 	}
 }
 
-// launchDaemon starts a juggle agent daemon for a session
+// fireSchedule launches a session's due scheduled run as a daemon and moves
+// the schedule on to its next run. A run that is already going takes the
+// scheduled run's place; with no free slot the run waits for the next poll.
+// Returns whether a daemon was launched.
+func (s *Supervisor) fireSchedule(st Status, hasCapacity bool) bool {
+	sched := st.Schedule
+	var result string
+	launched := false
+	switch {
+	case st.Running || s.hasOwnLoop(st):
+		result = "skipped: a run was already going"
+		fmt.Fprintf(os.Stderr, "[supervisor] Skipping scheduled run of %s/%s: a run is already going\n", st.ProjectDir, sched.SessionID)
+	case !hasCapacity:
+		fmt.Fprintf(os.Stderr, "[supervisor] Scheduled run of %s/%s is waiting for a free slot\n", st.ProjectDir, sched.SessionID)
+		return false
+	default:
+		args := []string{"agent", "run", "--daemon", sched.SessionID, "-n", strconv.Itoa(sched.Iterations)}
+		if sched.Trust {
+			args = append(args, "--trust")
+		}
+		if sched.Model != "" {
+			args = append(args, "--model", sched.Model)
+		}
+		fmt.Fprintf(os.Stderr, "[supervisor] Launching scheduled run of %s/%s (%s, %d iterations)\n",
+			st.ProjectDir, sched.SessionID, sched.Cron, sched.Iterations)
+		if s.inProcess() {
+			s.Submit(LoopRequest{ProjectDir: st.ProjectDir, SessionID: sched.SessionID, Iterations: sched.Iterations, Trust: sched.Trust, Model: sched.Model})
+			result = "launched"
+			launched = true
+		} else if err := s.startDaemon(st.ProjectDir, st.SessionID, args); err != nil {
+			result = "failed: " + err.Error()
+			fmt.Fprintf(os.Stderr, "[supervisor] Failed to launch scheduled run of %s: %v\n", sched.SessionID, err)
+		} else {
+			result = "launched"
+			launched = true
+		}
+	}
+
+	sessionStore, err := session.NewSessionStore(st.ProjectDir)
+	if err == nil {
+		if err = sched.Fired(time.Now(), result); err == nil {
+			err = sessionStore.SaveSchedule(st.SessionID, sched)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[supervisor] Failed to update schedule of %s: %v\n", sched.SessionID, err)
+	}
+	return launched
+}
+
+// launchDaemon starts a juggle agent daemon for a session, or queues a loop
+// for it when the supervisor runs loops itself
 func (s *Supervisor) launchDaemon(projectDir, sessionID string) error {
+	if s.inProcess() {
+		s.Submit(LoopRequest{ProjectDir: projectDir, SessionID: sessionID})
+		return nil
+	}
+	return s.startDaemon(projectDir, sessionID, []string{"agent", "run", "--daemon", sessionID})
+}
+
+// startDaemon runs juggle with args in the project, logging to the agent.log
+// of the session directory storageID
+func (s *Supervisor) startDaemon(projectDir, storageID string, args []string) error {
 	juggleBin, err := exec.LookPath("juggle")
 	if err != nil {
 		return fmt.Errorf("juggle binary not found: %w", err)
 	}
 
-	logPath := filepath.Join(projectDir, ".juggle", "sessions", sessionID, "agent.log")
+	logPath := filepath.Join(projectDir, ".juggle", "sessions", storageID, "agent.log")
 
-	cmd := exec.Command(juggleBin, "agent", "run", "--daemon", sessionID)
+	cmd := exec.Command(juggleBin, args...)
 	cmd.Dir = projectDir
 
 	// Redirect output to log file
@@ -342,6 +530,7 @@ func (s *Supervisor) launchDaemon(projectDir, sessionID string) error {
 
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
+	process.Detach(cmd)
 
 	if err := cmd.Start(); err != nil {
 		logFile.Close()
@@ -360,6 +549,11 @@ func (s *Supervisor) launchDaemon(projectDir, sessionID string) error {
 	return nil
 }
 
+// hasOwnLoop reports whether the supervisor runs or has queued a loop for the session
+func (s *Supervisor) hasOwnLoop(st Status) bool {
+	return s.inProcess() && s.HasLoop(st.ProjectDir, st.SessionID)
+}
+
 // supervisorPIDPath returns the path to the supervisor's own PID file
 func supervisorPIDPath() string {
 	home, _ := os.UserHomeDir()
@@ -385,14 +579,9 @@ func IsSupervisorRunning() (bool, int) {
 	}
 
 	pidInt := int(pid)
-	proc, err := os.FindProcess(pidInt)
-	if err != nil {
-		return false, 0
-	}
-
-	// Signal 0 checks if process exists
-	if err := proc.Signal(os.Signal(nil)); err != nil {
-		// Process doesn't exist - clean up stale PID
+	startedAt, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(pidData["started_at"]))
+	if !process.RunningSince(pidInt, startedAt) {
+		// Process doesn't exist (or reused the PID) - clean up stale PID
 		os.Remove(pidFile)
 		return false, 0
 	}
@@ -400,29 +589,28 @@ func IsSupervisorRunning() (bool, int) {
 	return true, pidInt
 }
 
-// StopSupervisor sends SIGTERM to a running supervisor
+// StopSupervisor asks a running supervisor to shut down
 func StopSupervisor() error {
 	running, pid := IsSupervisorRunning()
 	if !running {
 		return fmt.Errorf("supervisor is not running")
 	}
 
-	proc, err := os.FindProcess(pid)
-	if err != nil {
-		return fmt.Errorf("failed to find supervisor process %d: %w", pid, err)
-	}
-
-	if err := proc.Signal(os.Interrupt); err != nil {
+	if err := process.Terminate(pid); err != nil {
 		return fmt.Errorf("failed to stop supervisor (PID %d): %w", pid, err)
 	}
 
-	// Wait briefly for cleanup
-	time.Sleep(500 * time.Millisecond)
+	// Wait briefly for cleanup; on Windows the request takes up to a poll to be seen
+	for deadline := time.Now().Add(2 * time.Second); process.Running(pid) && time.Now().Before(deadline); {
+		time.Sleep(100 * time.Millisecond)
+	}
 
 	// Check if it stopped
 	if stillRunning, _ := IsSupervisorRunning(); stillRunning {
 		// Force kill
-		proc.Kill()
+		if proc, err := os.FindProcess(pid); err == nil {
+			proc.Kill()
+		}
 		os.Remove(supervisorPIDPath())
 	}
 
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	activityWeeks int
+	activityJSON  bool
+)
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Show a calendar heatmap of ball completions and agent iterations",
+	Long: `Render a calendar heatmap of activity per day, like a contribution graph.
+
+Each day counts completed balls (active and archived), agent iterations from the
+agent run history, and other balls last touched that day. Rows are weekdays,
+columns are weeks, and darker cells mean more activity.
+
+Examples:
+  juggle activity              # Last 26 weeks in this project
+  juggle activity --weeks 52   # A full year
+  juggle activity --all        # Across all discovered projects
+  juggle activity --json       # Per-day counts`,
+	Args: cobra.NoArgs,
+	RunE: runActivity,
+}
+
+func init() {
+	activityCmd.Flags().IntVar(&activityWeeks, "weeks", 26, "Number of weeks to show")
+	activityCmd.Flags().BoolVar(&activityJSON, "json", false, "Output per-day counts as JSON")
+	rootCmd.AddCommand(activityCmd)
+}
+
+// activityDay is one day's activity counts
+type activityDay struct {
+	Date        string `json:"date"`
+	Completions int    `json:"completions"`
+	Iterations  int    `json:"iterations"`
+	Touched     int    `json:"touched"`
+}
+
+// Total is the day's overall activity, used for the heatmap shade
+func (d activityDay) Total() int {
+	return d.Completions + d.Iterations + d.Touched
+}
+
+// heatmapShades go from no activity to the busiest days
+var heatmapShades = []lipgloss.Color{"237", "22", "28", "34", "40"}
+
+func runActivity(cmd *cobra.Command, args []string) error {
+	if activityWeeks < 1 {
+		return fmt.Errorf("--weeks must be at least 1")
+	}
+
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	// Discover projects (respects --all flag)
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+	if len(projects) == 0 {
+		fmt.Println("No projects with .juggle directories found.")
+		return nil
+	}
+
+	active, err := session.LoadAllBalls(projects)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	archived, err := session.QueryArchive(projects, session.ArchiveQuery{})
+	if err != nil {
+		return fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	var runs []*session.AgentRunRecord
+	for _, project := range projects {
+		historyStore, err := session.NewAgentHistoryStoreWithConfig(project, GetStoreConfig())
+		if err != nil {
+			continue
+		}
+		records, err := historyStore.LoadHistory()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load agent history for %s: %v\n", project, err)
+			continue
+		}
+		runs = append(runs, records...)
+	}
+
+	end := time.Now()
+	start := heatmapStart(end, activityWeeks)
+	days := collectActivity(append(active, archived...), runs, start, end)
+
+	if activityJSON {
+		list := make([]activityDay, 0, len(days))
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			key := day.Format("2006-01-02")
+			entry := days[key]
+			entry.Date = key
+			list = append(list, entry)
+		}
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(renderHeatmap(days, start, end))
+	fmt.Println()
+	fmt.Println(activitySummary(days, activityWeeks))
+	return nil
+}
+
+// heatmapStart returns the Monday that begins the first of the given number of
+// weeks ending with the week containing end
+func heatmapStart(end time.Time, weeks int) time.Time {
+	day := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+	return day.AddDate(0, 0, -offset-7*(weeks-1))
+}
+
+// collectActivity counts completions, agent iterations and ball touches per
+// day (keyed YYYY-MM-DD in local time) between start and end
+func collectActivity(balls []*session.Ball, runs []*session.AgentRunRecord, start, end time.Time) map[string]activityDay {
+	days := make(map[string]activityDay)
+	add := func(t time.Time, update func(*activityDay)) {
+		if t.IsZero() || t.Before(start) || t.After(end) {
+			return
+		}
+		key := t.Local().Format("2006-01-02")
+		day := days[key]
+		update(&day)
+		days[key] = day
+	}
+
+	for _, ball := range balls {
+		if ball.CompletedAt != nil {
+			add(*ball.CompletedAt, func(d *activityDay) { d.Completions++ })
+			continue
+		}
+		add(ball.LastActivity, func(d *activityDay) { d.Touched++ })
+	}
+	for _, run := range runs {
+		iterations := run.Iterations
+		add(run.StartedAt, func(d *activityDay) { d.Iterations += iterations })
+	}
+	return days
+}
+
+// renderHeatmap draws one row per weekday and one column per week, with month
+// labels above the weeks where a month starts
+func renderHeatmap(days map[string]activityDay, start, end time.Time) string {
+	maxTotal := 0
+	for _, day := range days {
+		if day.Total() > maxTotal {
+			maxTotal = day.Total()
+		}
+	}
+
+	weeks := 0
+	for monday := start; !monday.After(end); monday = monday.AddDate(0, 0, 7) {
+		weeks++
+	}
+	var b strings.Builder
+
+	// Month labels: each week column is two characters wide
+	labels := []rune(strings.Repeat(" ", weeks*2+1))
+	for week := 0; week < weeks; week++ {
+		monday := start.AddDate(0, 0, week*7)
+		if week == 0 || monday.Day() <= 7 {
+			name := monday.Format("Jan")
+			pos := week * 2
+			if pos+len(name) <= len(labels) && (pos == 0 || labels[pos-1] == ' ') {
+				copy(labels[pos:], []rune(name))
+			}
+		}
+	}
+	b.WriteString("     " + strings.TrimRight(string(labels), " ") + "\n")
+
+	weekdays := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	for row := 0; row < 7; row++ {
+		fmt.Fprintf(&b, "%-4s ", weekdays[row])
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, week*7+row)
+			if day.After(end) {
+				break
+			}
+			shade := heatmapShade(days[day.Format("2006-01-02")].Total(), maxTotal)
+			b.WriteString(lipgloss.NewStyle().Foreground(heatmapShades[shade]).Render("■") + " ")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n     Less ")
+	for _, color := range heatmapShades {
+		b.WriteString(lipgloss.NewStyle().Foreground(color).Render("■") + " ")
+	}
+	b.WriteString("More\n")
+	return b.String()
+}
+
+// heatmapShade buckets a day's total into a shade index relative to the
+// busiest day; any activity gets at least the lightest shade
+func heatmapShade(total, maxTotal int) int {
+	if total <= 0 || maxTotal <= 0 {
+		return 0
+	}
+	levels := len(heatmapShades) - 1
+	shade := (total*levels + maxTotal - 1) / maxTotal
+	if shade < 1 {
+		shade = 1
+	}
+	if shade > levels {
+		shade = levels
+	}
+	return shade
+}
+
+// activitySummary totals the window and names the busiest day
+func activitySummary(days map[string]activityDay, weeks int) string {
+	var completions, iterations, active int
+	busiest := activityDay{}
+	for key, day := range days {
+		completions += day.Completions
+		iterations += day.Iterations
+		if day.Total() > 0 {
+			active++
+		}
+		if day.Total() > busiest.Total() || (day.Total() == busiest.Total() && key > busiest.Date) {
+			busiest = day
+			busiest.Date = key
+		}
+	}
+
+	summary := fmt.Sprintf("%d ball(s) completed, %d agent iteration(s) over %d active day(s) in the last %d week(s)",
+		completions, iterations, active, weeks)
+	if busiest.Total() > 0 {
+		summary += fmt.Sprintf("\nBusiest day: %s (%d)", busiest.Date, busiest.Total())
+	}
+	return summary
+}
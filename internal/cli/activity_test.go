@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestHeatmapStart(t *testing.T) {
+	// Thursday 2025-06-12
+	end := time.Date(2025, 6, 12, 15, 0, 0, 0, time.Local)
+	start := heatmapStart(end, 2)
+	want := time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local)
+	if !start.Equal(want) || start.Weekday() != time.Monday {
+		t.Errorf("heatmapStart() = %v, want %v", start, want)
+	}
+}
+
+func TestCollectActivity(t *testing.T) {
+	end := time.Date(2025, 6, 12, 15, 0, 0, 0, time.Local)
+	start := heatmapStart(end, 2)
+	completed := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+
+	balls := []*session.Ball{
+		{ID: "a", CompletedAt: &completed, LastActivity: completed},
+		{ID: "b", LastActivity: time.Date(2025, 6, 10, 18, 0, 0, 0, time.Local)},
+		{ID: "c", LastActivity: time.Date(2025, 5, 1, 12, 0, 0, 0, time.Local)}, // Before the window
+	}
+	runs := []*session.AgentRunRecord{
+		{StartedAt: time.Date(2025, 6, 10, 8, 0, 0, 0, time.Local), Iterations: 4},
+		{StartedAt: time.Date(2025, 6, 11, 8, 0, 0, 0, time.Local), Iterations: 2},
+	}
+
+	days := collectActivity(balls, runs, start, end)
+	if got := days["2025-06-10"]; got.Completions != 1 || got.Touched != 1 || got.Iterations != 4 {
+		t.Errorf("unexpected counts for 2025-06-10: %+v", got)
+	}
+	if got := days["2025-06-11"].Total(); got != 2 {
+		t.Errorf("expected 2 iterations on 2025-06-11, got %d", got)
+	}
+	if len(days) != 2 {
+		t.Errorf("expected activity on 2 days, got %d", len(days))
+	}
+
+	heatmap := renderHeatmap(days, start, end)
+	for _, label := range []string{"Jun", "Mon", "Wed", "Fri", "Less", "More"} {
+		if !strings.Contains(heatmap, label) {
+			t.Errorf("expected heatmap to contain %q, got:\n%s", label, heatmap)
+		}
+	}
+	if !strings.Contains(activitySummary(days, 2), "Busiest day: 2025-06-10 (6)") {
+		t.Errorf("unexpected summary: %s", activitySummary(days, 2))
+	}
+}
+
+func TestHeatmapShade(t *testing.T) {
+	tests := []struct{ total, max, want int }{
+		{0, 10, 0},
+		{1, 10, 1},
+		{5, 10, 2},
+		{10, 10, 4},
+		{3, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := heatmapShade(tt.total, tt.max); got != tt.want {
+			t.Errorf("heatmapShade(%d, %d) = %d, want %d", tt.total, tt.max, got, tt.want)
+		}
+	}
+}
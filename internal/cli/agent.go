@@ -2,9 +2,11 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
+	"io"
 	"math/rand"
 	"os"
 	"os/exec"
@@ -12,13 +14,15 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ohare93/juggle/internal/agent"
 	"github.com/ohare93/juggle/internal/agent/daemon"
 	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/agent/supervisor"
+	"github.com/ohare93/juggle/internal/process"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/tui"
 	"github.com/ohare93/juggle/internal/vcs"
@@ -44,7 +48,8 @@ var (
 	agentModel         string
 	agentDelay         int    // Delay between iterations in minutes (overrides config)
 	agentFuzz          int    // +/- variance in delay minutes (overrides config)
-	agentProvider      string // Agent provider (claude, opencode)
+	agentIgnoreQuiet   bool   // Start iterations during the configured quiet hours
+	agentProvider      string // Agent provider (claude, opencode, goose, amp, api)
 	agentIgnoreLock    bool   // Skip lock acquisition
 	agentClearProgress bool   // Clear session progress before running
 	agentPickBall      bool   // Interactive ball selection
@@ -52,7 +57,20 @@ var (
 	agentMessageFlag   bool   // Track if -m flag was provided (for interactive mode)
 	agentDaemon         bool   // Run in daemon mode (persists after TUI exits)
 	agentMonitor        bool   // Open monitor TUI (connects to running daemon)
+	agentRemote         string // Daemon API address to monitor, e.g. the local end of an SSH tunnel
+	agentRemoteToken    string // Token of the remote daemon API
 	agentSkipHooksCheck bool   // Skip Claude hooks check
+	agentParallel       int    // Number of balls to work on concurrently (0 = sequential)
+	agentMaxTokens      int     // Token budget for the run (0 = from config)
+	agentMaxCost        float64 // Cost budget for the run in USD (0 = from config)
+	agentAllowOverlap   bool    // Skip the duplicate-work check
+	agentMaxDuration    time.Duration // Wall-clock limit for the whole run
+	agentResume         bool          // Continue an interrupted run from its checkpoint
+	agentReview         bool          // Review the run's work once all balls are terminal
+	agentNoPreflight    bool          // Skip the provider check before the first iteration
+	agentPlanFirst      bool          // Spend the first iteration planning each ball in plan mode
+	agentResumeBlocked  bool          // Reopen blocked balls with --message as guidance
+	agentAuto           bool          // Pick the most valuable workable ball across all projects, one after another
 
 	// Refine command flags
 	refineProvider string // Agent provider for refine command
@@ -115,6 +133,15 @@ Examples:
   # Work on specific ball with multiple iterations (non-interactive)
   juggle agent run my-feature --ball juggle-5 -n 3
 
+  # Work on up to 3 balls at once, each in its own worktree (-n is per ball)
+  juggle agent run my-feature --parallel 3
+
+  # Work through the most valuable balls across all projects, 20 iterations in total
+  juggle agent run --auto -n 20
+
+  # Stream iteration, rate limit and completion events as JSON lines on stdout
+  juggle agent run my-feature --json
+
   # Run in interactive mode (full Claude TUI)
   juggle agent run my-feature --interactive
 
@@ -222,6 +249,12 @@ func init() {
 	agentRunCmd.Flags().IntVarP(&agentIterations, "iterations", "n", 10, "Maximum number of iterations")
 	agentRunCmd.Flags().BoolVar(&agentTrust, "trust", false, "Run with --dangerously-skip-permissions (dangerous!)")
 	agentRunCmd.Flags().DurationVarP(&agentTimeout, "timeout", "T", 0, "Timeout per iteration (e.g., 5m, 1h). 0 = no timeout")
+	agentRunCmd.Flags().DurationVar(&agentMaxDuration, "max-duration", 0, "Stop the whole run after this long (e.g., 2h), isolating uncommitted work. 0 = no limit")
+	agentRunCmd.Flags().BoolVar(&agentPlanFirst, "plan-first", false, "Spend the first iteration writing a step plan for each ball in plan mode, then execute against it")
+	agentRunCmd.Flags().BoolVar(&agentNoPreflight, "no-preflight", false, "Skip checking the provider's credentials and model before the first iteration")
+	agentRunCmd.Flags().BoolVar(&agentReview, "review", false, "Once all balls are terminal, run a read-only review iteration and write review.md")
+	agentRunCmd.Flags().BoolVar(&agentResume, "resume", false, "Continue a crashed or killed run from its last checkpoint instead of starting at iteration 1")
+	agentRunCmd.Flags().BoolVar(&agentResumeBlocked, "resume-blocked", false, "Reopen the session's blocked balls as in progress, giving the agent their blocked reasons and --message")
 	agentRunCmd.Flags().BoolVarP(&agentDebug, "debug", "d", false, "Show prompt info before running the agent")
 	agentRunCmd.Flags().BoolVar(&agentDryRun, "dry-run", false, "Show prompt info without running the agent")
 	agentRunCmd.Flags().DurationVar(&agentMaxWait, "max-wait", 0, "Maximum wait time for rate limits before giving up (e.g., 30m). 0 = wait indefinitely")
@@ -230,17 +263,25 @@ func init() {
 	agentRunCmd.Flags().StringVarP(&agentModel, "model", "m", "", "Model to use (opus, sonnet, haiku). Default: opus for large balls, sonnet for others")
 	agentRunCmd.Flags().IntVar(&agentDelay, "delay", 0, "Delay between iterations in minutes (overrides config, 0 = no delay)")
 	agentRunCmd.Flags().IntVar(&agentFuzz, "fuzz", 0, "Random +/- variance in delay minutes (overrides config)")
-	agentRunCmd.Flags().StringVar(&agentProvider, "provider", "", "Agent provider to use (claude, opencode). Default: from config or claude")
+	agentRunCmd.Flags().BoolVar(&agentIgnoreQuiet, "ignore-quiet-hours", false, "Start iterations even during the configured quiet hours")
+	agentRunCmd.Flags().StringVar(&agentProvider, "provider", "", "Agent provider to use (claude, opencode, goose, amp, api, or a custom provider). Default: from config or claude")
 	agentRunCmd.Flags().BoolVar(&agentIgnoreLock, "ignore-lock", false, "Skip lock acquisition (use with caution)")
 	agentRunCmd.Flags().BoolVar(&agentClearProgress, "clear-progress", false, "Clear session progress before running")
 	agentRunCmd.Flags().BoolVar(&agentPickBall, "pick", false, "Interactively select a ball to work on")
 	agentRunCmd.Flags().StringVarP(&agentMessage, "message", "M", "", "Message to append to the agent prompt. If flag is provided without value, opens interactive input")
 	agentRunCmd.Flags().BoolVar(&agentDaemon, "daemon", false, "Run agent as background daemon (persists when TUI exits)")
-	agentRunCmd.Flags().BoolVar(&agentMonitor, "monitor", false, "Open monitor TUI (connects to running daemon if exists)")
+	agentRunCmd.Flags().BoolVar(&agentMonitor, "monitor", false, "Open monitor TUI (connects to running daemon if exists; without a session, pick any running daemon)")
+	agentRunCmd.Flags().StringVar(&agentRemote, "remote", "", "With --monitor, monitor a daemon on another machine through its API at this address (e.g. an SSH tunnel to localhost:7717)")
+	agentRunCmd.Flags().StringVar(&agentRemoteToken, "remote-token", "", "Token of the remote daemon API (default: $"+envDaemonAPIToken+")")
 	agentRunCmd.Flags().BoolVar(&agentSkipHooksCheck, "skip-hooks-check", false, "Skip Claude hooks installation check")
+	agentRunCmd.Flags().IntVar(&agentParallel, "parallel", 0, "Work on up to N balls at once, each agent in its own worktree/workspace")
+	agentRunCmd.Flags().IntVar(&agentMaxTokens, "max-tokens", 0, "Stop the run once input+output tokens reach this many (0 = from config, -1 = unlimited)")
+	agentRunCmd.Flags().Float64Var(&agentMaxCost, "max-cost", 0, "Stop the run once the reported cost reaches this many USD (0 = from config, -1 = unlimited)")
+	agentRunCmd.Flags().BoolVar(&agentAllowOverlap, "allow-overlap", false, "Start even if other sessions recently changed the same paths")
+	agentRunCmd.Flags().BoolVar(&agentAuto, "auto", false, "Without a session, run the most valuable workable ball across all discovered projects, then the next, until -n iterations are used")
 
 	// Refine command flags
-	agentRefineCmd.Flags().StringVar(&refineProvider, "provider", "", "Agent provider to use (claude, opencode). Default: from config or claude")
+	agentRefineCmd.Flags().StringVar(&refineProvider, "provider", "", "Agent provider to use (claude, opencode, goose, amp, api, or a custom provider). Default: from config or claude")
 	agentRefineCmd.Flags().StringVarP(&refineModel, "model", "m", "", "Model to use (opus, sonnet, haiku). Default: sonnet")
 	agentRefineCmd.Flags().StringVarP(&refineMessage, "message", "M", "", "Message to append to the refine prompt. If flag is provided without value, opens interactive input")
 
@@ -303,16 +344,83 @@ type AgentResult struct {
 	TimedOut           bool          `json:"timed_out"`
 	TimeoutMessage     string        `json:"timeout_message,omitempty"`
 	RateLimitExceded   bool          `json:"rate_limit_exceeded"`
+	BudgetExceeded     bool          `json:"budget_exceeded,omitempty"` // Stopped by --max-tokens or --max-cost
+	BudgetMessage      string        `json:"budget_message,omitempty"`
 	TotalWaitTime      time.Duration `json:"total_wait_time,omitempty"`
 	OverloadRetries    int           `json:"overload_retries,omitempty"`    // Number of 529 overload retry waits
 	OverloadWaitTime   time.Duration `json:"overload_wait_time,omitempty"` // Total time spent waiting for overload recovery
 	BallsComplete      int           `json:"balls_complete"`
 	BallsBlocked       int           `json:"balls_blocked"`
 	BallsTotal         int           `json:"balls_total"`
+	InputTokens        int           `json:"input_tokens,omitempty"`  // Summed over all agent runs, including retries
+	OutputTokens       int           `json:"output_tokens,omitempty"` // Summed over all agent runs, including retries
+	CostUSD            float64       `json:"cost_usd,omitempty"`      // Summed over all agent runs, as reported by the provider
+	IterationTimings   []session.IterationTiming `json:"iteration_timings,omitempty"` // Finished iterations, for ETA estimates
+	GateResults        []GateResult  `json:"gate_results,omitempty"` // Build/lint/typecheck gates run before commits
+	NoOpIterations     int           `json:"no_op_iterations,omitempty"` // Iterations that changed no files, balls or progress
+	ReviewPath         string        `json:"review_path,omitempty"`  // Review written after the run, if any
+	ReportPath         string        `json:"report_path,omitempty"`  // Report written at the end of the run, if any
 	StartedAt          time.Time     `json:"started_at"`
 	EndedAt            time.Time     `json:"ended_at"`
 }
 
+// addUsage accumulates the token usage and cost reported by one agent run
+func (r *AgentResult) addUsage(run *agent.RunResult) {
+	r.InputTokens += run.InputTokens
+	r.OutputTokens += run.OutputTokens
+	r.CostUSD += run.CostUSD
+}
+
+// overBudget reports whether the usage so far has reached either limit, and
+// says which one. A zero limit is unlimited.
+func (r *AgentResult) overBudget(maxTokens int, maxCostUSD float64) (bool, string) {
+	if tokens := r.InputTokens + r.OutputTokens; maxTokens > 0 && tokens >= maxTokens {
+		return true, fmt.Sprintf("token budget reached: %d of %d tokens used", tokens, maxTokens)
+	}
+	if maxCostUSD > 0 && r.CostUSD >= maxCostUSD {
+		return true, fmt.Sprintf("cost budget reached: $%.2f of $%.2f spent", r.CostUSD, maxCostUSD)
+	}
+	return false, ""
+}
+
+// loadAgentBudget resolves the run's token and cost limits: explicit loop
+// config wins, 0 falls back to project config, and negative means unlimited
+func loadAgentBudget(config AgentLoopConfig) (int, float64) {
+	maxTokens, maxCostUSD, _ := session.GetProjectAgentBudget(config.ProjectDir)
+	if config.MaxTokens != 0 {
+		maxTokens = config.MaxTokens
+	}
+	if config.MaxCostUSD != 0 {
+		maxCostUSD = config.MaxCostUSD
+	}
+	return maxTokens, maxCostUSD
+}
+
+// errMaxDuration is the cancel cause once a run passes its --max-duration
+var errMaxDuration = errors.New("run exceeded its maximum duration")
+
+// maxDurationExceeded ends a run that passed its wall-clock budget. Work the
+// killed iteration left uncommitted is isolated like a BLOCKED run's.
+func maxDurationExceeded(config AgentLoopConfig, storageID, outputPath string, result *AgentResult) (*AgentResult, error) {
+	result.TimedOut = true
+	result.TimeoutMessage = fmt.Sprintf("Run exceeded max duration of %v", config.MaxDuration)
+	fmt.Println()
+	fmt.Printf("⏱  %s, stopping\n", result.TimeoutMessage)
+
+	backend := vcsBackendForProject(config.ProjectDir)
+	if hasChanges, err := backend.HasChanges(config.ProjectDir); err == nil && hasChanges {
+		// Keep the iterations this run already committed
+		target, _ := backend.GetSnapshotRevision(config.ProjectDir)
+		fmt.Printf("📊 Backing out uncommitted work...\n")
+		isolateWork(backend, config.ProjectDir, target, "TIMEOUT: "+result.TimeoutMessage)
+	}
+
+	logTimeoutToProgress(config.ProjectDir, storageID, result.TimeoutMessage)
+	result.EndedAt = time.Now()
+	saveAgentHistory(config, result, outputPath)
+	return result, nil
+}
+
 // AgentLoopConfig configures the agent loop behavior
 type AgentLoopConfig struct {
 	SessionID            string
@@ -321,16 +429,97 @@ type AgentLoopConfig struct {
 	Trust                bool
 	Debug                bool          // Add debug reasoning instructions to prompt
 	IterDelay            time.Duration // Delay between iterations (set to 0 for tests)
+	QuietHours           *session.QuietHours // Wait out these windows before starting an iteration (nil = none)
 	Timeout              time.Duration // Timeout per iteration (0 = no timeout)
 	MaxWait              time.Duration // Maximum time to wait for rate limits (0 = wait indefinitely)
 	BallID               string        // Specific ball to work on (empty = all session balls)
 	Interactive          bool          // Run in interactive mode (full Claude TUI)
 	Model                string        // Model to use (opus, sonnet, haiku). Empty = auto-select based on ball model_size
 	OverloadRetryMinutes int           // Minutes to wait before retrying after 529 overload exhaustion (-1 = use config default, 0 = no wait)
-	Provider             string        // Agent provider to use (claude, opencode). Empty = from config or claude
+	Provider             string        // Agent provider to use (claude, opencode, goose, amp, api). Empty = from config or claude
 	IgnoreLock           bool          // Skip lock acquisition (use with caution)
 	Message              string        // User message to append to the agent prompt
 	DaemonMode           bool          // Run in daemon mode with file-based state and control
+	MaxTokens            int           // Stop once input+output tokens reach this (0 = project config default, -1 = unlimited)
+	MaxCostUSD           float64       // Stop once the reported cost reaches this (0 = project config default, -1 = unlimited)
+	AllowOverlap         bool          // Skip the check for other sessions recently changing the same paths
+	MaxDuration          time.Duration // Wall-clock limit for the whole run (0 = no limit)
+	Resume               bool          // Continue from the session's checkpoint; MaxIterations 0 = the checkpoint's
+	Review               bool          // Run a review iteration once all balls are terminal (also enabled by project config)
+	Preflight            bool          // Ping the provider before the first iteration and fail fast if it can't serve the run
+	PlanFirst            bool          // Iteration 1 plans unplanned balls in plan mode; later iterations execute the plans
+	ResumeBlocked        bool          // Reopen blocked balls as in_progress, with their blocked reasons and Message in the prompt
+	Events               io.Writer     // NDJSON event stream for --json (nil = no events)
+}
+
+// resolveIterDelay returns the delay between iterations, from --delay and
+// --fuzz or the global config, and prints it when set
+func resolveIterDelay(cmd *cobra.Command) time.Duration {
+	var iterDelay time.Duration
+	var delayMinutes, fuzz int
+
+	// Check if --delay flag was explicitly provided
+	if cmd.Flags().Changed("delay") {
+		delayMinutes = agentDelay
+		// Check if --fuzz was also provided, otherwise default to 0
+		if cmd.Flags().Changed("fuzz") {
+			fuzz = agentFuzz
+		}
+	} else {
+		// Load from config
+		var err error
+		delayMinutes, fuzz, err = session.GetGlobalIterationDelayWithOptions(GetConfigOptions())
+		if err != nil {
+			delayMinutes = 0
+			fuzz = 0
+		}
+		// Override fuzz from flag if set
+		if cmd.Flags().Changed("fuzz") {
+			fuzz = agentFuzz
+		}
+	}
+
+	// If delay is 0, skip the delay feature entirely (regardless of fuzz)
+	if delayMinutes > 0 {
+		iterDelay = calculateFuzzyDelay(delayMinutes, fuzz)
+		fmt.Printf("Iteration delay: %v", iterDelay.Round(time.Second))
+		if fuzz > 0 {
+			fmt.Printf(" (base: %dm ± %dm)", delayMinutes, fuzz)
+		}
+		fmt.Println()
+	}
+	return iterDelay
+}
+
+// resolveQuietHours returns the configured quiet hours, or nil with
+// --ignore-quiet-hours, and prints them when set
+func resolveQuietHours() *session.QuietHours {
+	if agentIgnoreQuiet {
+		return nil
+	}
+	quiet, err := session.GetGlobalQuietHoursWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring quiet hours: %v\n", err)
+		return nil
+	}
+	if quiet != nil {
+		fmt.Printf("Quiet hours: %s\n", quiet)
+	}
+	return quiet
+}
+
+// waitOutQuietHours waits, with a countdown, until the quiet hours covering
+// now are over. Returns false if ctx was cancelled first.
+func waitOutQuietHours(ctx context.Context, quiet *session.QuietHours) bool {
+	if quiet == nil {
+		return true
+	}
+	until := quiet.Until(time.Now())
+	if until.IsZero() {
+		return true
+	}
+	fmt.Printf("🌙 Quiet hours (%s): waiting until %s to start the next iteration\n", quiet, until.Format("Mon Jan 2 15:04"))
+	return waitWithCountdown(ctx, time.Until(until))
 }
 
 // sessionStorageID returns the session ID used for storage (progress, output, lock)
@@ -344,7 +533,7 @@ func sessionStorageID(sessionID string) string {
 
 // RunAgentLoop executes the agent loop with the given configuration.
 // This is the testable core of the agent run command.
-func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
+func RunAgentLoop(ctx context.Context, config AgentLoopConfig) (*AgentResult, error) {
 	startTime := time.Now()
 
 	sessionStore, err := session.NewSessionStore(config.ProjectDir)
@@ -361,13 +550,29 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		var err error
 		juggleSession, err = sessionStore.LoadSession(config.SessionID)
 		if err != nil {
-			return nil, fmt.Errorf("session not found: %s", config.SessionID)
+			return nil, fmt.Errorf("%w: %s", session.ErrSessionNotFound, config.SessionID)
 		}
 	}
 
 	// storageID is used for output paths and progress tracking
 	// For "all" meta-session, this returns "_all"
 	storageID := sessionStorageID(config.SessionID)
+	events := newAgentEvents(config.Events, config.SessionID)
+
+	// Pick up the loop state of a run that crashed or was killed
+	var checkpoint *session.RunCheckpoint
+	if config.Resume {
+		checkpoint, err = sessionStore.LoadCheckpoint(storageID, config.BallID)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint == nil {
+			return nil, fmt.Errorf("no interrupted run to resume for session %s", config.SessionID)
+		}
+		if config.MaxIterations <= 0 {
+			config.MaxIterations = checkpoint.MaxIterations
+		}
+	}
 
 	// Acquire exclusive lock to prevent concurrent agent runs
 	// - If IgnoreLock is true, skip locking entirely
@@ -391,6 +596,8 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		}
 		lockRelease = lock.Release
 	}
+	// The loop may let the lock go early; release it only once
+	lockRelease = sync.OnceValue(lockRelease)
 	defer lockRelease()
 
 	// Create output file path using storage ID
@@ -406,10 +613,95 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	result := &AgentResult{
 		StartedAt: startTime,
 	}
+	// Daemons journal their events, so overnight runs can be looked into later
+	if config.DaemonMode {
+		if err := events.openJournal(config.ProjectDir, storageID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open event journal: %v\n", err)
+		}
+		defer events.closeJournal(result)
+	}
+	if checkpoint != nil {
+		result.StartedAt = checkpoint.StartedAt
+		result.InputTokens = checkpoint.InputTokens
+		result.OutputTokens = checkpoint.OutputTokens
+		result.CostUSD = checkpoint.CostUSD
+		result.IterationTimings = checkpoint.IterationTimings
+	}
+
+	// The wall-clock budget cancels ctx like a user would, but the run ends as timed out
+	if config.MaxDuration > 0 {
+		var cancelDeadline context.CancelFunc
+		ctx, cancelDeadline = context.WithDeadlineCause(ctx, startTime.Add(config.MaxDuration), errMaxDuration)
+		defer cancelDeadline()
+	}
+
+	// juggle agent stop ends the run between iterations, or after the agent's current tool call
+	ctx, cancelStop := context.WithCancelCause(ctx)
+	defer cancelStop(nil)
+	stopper := newStopWatcher(config.ProjectDir, storageID, cancelStop)
+	defer stopper.close()
+
+	// stopped ends the run on a stop request, keeping uncommitted work for review
+	stopped := func() (*AgentResult, error) {
+		defer events.summary(result)
+		fmt.Println()
+		fmt.Println("🛑 Stopped by juggle agent stop")
+		isolateStoppedWork(config.ProjectDir)
+		logStopToProgress(config.ProjectDir, storageID, "Agent run stopped by juggle agent stop")
+		_ = daemon.ClearStopRequest(config.ProjectDir, storageID)
+		result.Blocked = true
+		result.BlockedReason = daemon.StatusStopped
+		result.EndedAt = time.Now()
+		if !config.DaemonMode {
+			// Daemons write their final state on exit; foreground runs leave one for the monitor too
+			_ = daemon.WriteStateFile(config.ProjectDir, storageID, &daemon.State{
+				Iteration:     result.Iterations,
+				MaxIterations: config.MaxIterations,
+				StartedAt:     startTime,
+				Status:        daemon.StatusStopped,
+			})
+		}
+		saveAgentHistory(config, result, outputPath)
+		return result, nil
+	}
+
+	// cancelled ends the run when ctx is cancelled, the same way a monitor TUI cancel does
+	cancelled := func() (*AgentResult, error) {
+		if errors.Is(context.Cause(ctx), errStopRequested) {
+			return stopped()
+		}
+		defer events.summary(result)
+		if errors.Is(context.Cause(ctx), errMaxDuration) {
+			return maxDurationExceeded(config, storageID, outputPath, result)
+		}
+		fmt.Println("🛑 Run cancelled")
+		result.Blocked = true
+		result.BlockedReason = fmt.Sprintf("Cancelled: %v", context.Cause(ctx))
+		result.EndedAt = time.Now()
+		saveAgentHistory(config, result, outputPath)
+		return result, nil
+	}
+
+	// endedEarly ends the run from inside the loop, e.g. on a monitor TUI
+	// cancel, recording its history and summary as a run ending on its own does
+	endedEarly := func() (*AgentResult, error) {
+		defer events.summary(result)
+		result.EndedAt = time.Now()
+		saveAgentHistory(config, result, outputPath)
+		return result, nil
+	}
 
 	// Daemon mode setup: write PID file and initial state
-	var daemonPaused bool // Track pause state for daemon mode
+	var daemonPaused bool   // Track pause state for daemon mode
+	var daemonBallID string // Ball the daemon state last showed, which skip_ball skips
+	var control *daemon.ControlReceiver
 	if config.DaemonMode {
+		// Serve state and control over the daemon API; the files keep working without it
+		apiAddr, apiToken, err := startDaemonAPI()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: daemon API unavailable: %v\n", err)
+		}
+
 		// Write PID file so TUI can find us
 		daemonInfo := &daemon.Info{
 			PID:           os.Getpid(),
@@ -419,10 +711,18 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			MaxIterations: config.MaxIterations,
 			Model:         config.Model,
 			Provider:      config.Provider,
+			BallID:        config.BallID,
+			Trust:         config.Trust,
+			APIAddr:       apiAddr,
+			APIToken:      apiToken,
 		}
 		if err := daemon.WritePIDFile(config.ProjectDir, storageID, daemonInfo); err != nil {
 			return nil, fmt.Errorf("failed to write daemon PID file: %w", err)
 		}
+		// Take pause/resume/cancel commands from the monitor and juggle agent
+		// attach. Closed last, so API watchers get the final state.
+		control = daemon.ListenControl(config.ProjectDir, storageID)
+		defer control.Close()
 		// Ensure cleanup on exit - write final state first so TUI can detect exit
 		defer func() {
 			// Build status message from result
@@ -442,6 +742,8 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 				status = "Timed out"
 			case result.RateLimitExceded:
 				status = "Rate limited"
+			case result.BudgetExceeded:
+				status = "Budget exceeded"
 			case result.OverloadRetries > 0 && result.OverloadWaitTime > 0:
 				status = "Overloaded"
 			default:
@@ -475,13 +777,29 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	// Track crash retry state
 	crashRetries := 0
 	crashRetrying := false // Skip header when retrying after crash
-	const maxCrashRetries = 3
 
-	// Load overload retry interval from config (or use provided override)
-	// -1 means "use config default", 0 means "no wait" (for testing), >0 is explicit minutes
-	overloadRetryMinutes := config.OverloadRetryMinutes
-	if overloadRetryMinutes < 0 {
-		overloadRetryMinutes, _ = session.GetGlobalOverloadRetryMinutesWithOptions(GetConfigOptions())
+	startIteration := 1
+	if checkpoint != nil {
+		startIteration = checkpoint.Iteration
+		totalWaitTime, overloadWaitTime = checkpoint.RateLimitWait, checkpoint.OverloadWait
+		rateLimitRetries, overloadRetries, crashRetries = checkpoint.RateLimitRetries, checkpoint.OverloadRetries, checkpoint.CrashRetries
+	}
+
+	// Backoff for rate limits, overload and crashes, from defaults and config
+	retry := loadRetryPolicies(config)
+
+	// Token and cost budgets, from flags or project config
+	maxTokens, maxCostUSD := loadAgentBudget(config)
+	budgetExceeded := func() bool {
+		over, message := result.overBudget(maxTokens, maxCostUSD)
+		if !over {
+			return false
+		}
+		fmt.Fprintf(os.Stderr, "💸 Stopping: %s\n", message)
+		result.BudgetExceeded = true
+		result.BudgetMessage = message
+		_ = sessionStore.AppendProgress(storageID, "[BUDGET] Agent run stopped: "+message)
+		return true
 	}
 
 	// Configure agent provider based on CLI flag, project config, and global config
@@ -493,16 +811,34 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
 	}
+	registerCustomProviders()
+	registerProviderBinaries(config.ProjectDir)
 	providerType := provider.Detect(config.Provider, projectProvider, globalProvider)
 
-	// Verify provider binary is available
-	if !provider.IsAvailable(providerType) {
-		return nil, fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
-			providerType, provider.BinaryName(providerType))
+	// The project may run the agent CLI in a container instead of on the host
+	sandbox, err := loadAgentSandbox(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify provider binary is available (a sandboxed CLI comes from the image)
+	if sandbox == nil && !provider.IsAvailable(providerType) {
+		return nil, providerUnavailableError(providerType)
 	}
 
 	agentProv := provider.Get(providerType)
-	agent.SetProvider(agentProv)
+	if !agentProv.SupportsTools() {
+		return nil, fmt.Errorf("agent provider %q has no tools and only supports advice mode (agent refine, agent run --dry-run)", providerType)
+	}
+	if config.Interactive && !agentProv.SupportsInteractive() {
+		return nil, fmt.Errorf("agent provider %q does not support interactive mode", providerType)
+	}
+
+	// Agent output is cleaned up per provider before it is saved and passed on
+	outputs, err := newOutputProcessing(config.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
 
 	// Configure model overrides
 	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
@@ -514,7 +850,17 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
 	}
 	modelOverrides := session.MergeModelOverrides(globalOverrides, projectOverrides)
-	agent.SetModelOverrides(modelOverrides)
+
+	// Pending balls left untouched gain priority before the agent picks its work
+	printAgedBalls(ageProjectBalls(config.ProjectDir))
+
+	// --resume-blocked: the user dealt with the blocks, so pick those balls back up
+	if config.ResumeBlocked {
+		config.Message, err = resumeBlockedBalls(config)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Pre-loop check: is there any work the agent can do?
 	// Exit early if all balls are blocked (need human intervention) or no actionable balls exist
@@ -531,7 +877,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		result.BallsBlocked = blockedCount
 
 		if blockedCount > 0 {
-			fmt.Fprintf(os.Stderr, "⏸ No actionable work: %d ball(s) blocked, waiting for human intervention\n", blockedCount)
+			fmt.Fprintf(os.Stderr, "⏸ No actionable work: %d ball(s) blocked or claimed, waiting for human intervention\n", blockedCount)
 			result.Blocked = true
 			return result, nil
 		}
@@ -541,11 +887,190 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		return result, nil
 	}
 
-	for iteration := 1; iteration <= config.MaxIterations; iteration++ {
+	// Another session may have just changed the same paths; see duplicate_work
+	if !config.AllowOverlap {
+		if reason := checkDuplicateWork(config, storageID); reason != "" {
+			fmt.Fprintf(os.Stderr, "⏸ Not starting: %s\n", reason)
+			result.Blocked = true
+			result.BlockedReason = reason
+			result.BallsTotal = totalCount
+			result.EndedAt = time.Now()
+			saveAgentHistory(config, result, outputPath)
+			return result, nil
+		}
+	}
+
+	// Webhooks hear about the run from here on, however it ends
+	notifier := newRunNotifier(config, outputPath)
+	notifier.start()
+	defer notifier.end(result)
+	events.record(AgentEvent{Type: AgentEventRunStart, MaxIterations: config.MaxIterations, BallID: config.BallID, Provider: string(providerType), Model: config.Model})
+
+	// Pace the run by the subscription plan's usage windows, when configured
+	usageWindow := newUsageWindowTracker(config)
+
+	// Runs in other projects on the same account back off with this one
+	sharedLimit := newSharedRateLimit(config, providerType)
+	usageWindow.warnIfShort(config.MaxIterations - startIteration + 1)
+
+	// Snapshots cover the current run only, so rollback iterations match this run's numbering
+	if checkpoint == nil {
+		if err := sessionStore.ClearSnapshots(storageID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to clear iteration snapshots: %v\n", err)
+		}
+	}
+
+	// Daemons run unattended, so their agents get the configured resource limits
+	var limits *provider.ResourceLimits
+	if config.DaemonMode {
+		if limits, err = daemonAgentLimits(); err != nil {
+			return nil, err
+		}
+		if limits != nil {
+			fmt.Printf("🐢 Limits: %s\n", limits.Describe())
+		}
+	}
+
+	// The loop has a runner of its own, so loops sharing a process (juggle daemon) keep their provider
+	loopProv := agentProv
+	newLoopRunner := func(p provider.Provider) agent.Runner {
+		r := agent.RunnerFor(p, modelOverrides)
+		if sandbox != nil {
+			r = &agent.SandboxRunner{Inner: r, Sandbox: sandbox}
+		}
+		if limits != nil {
+			r = &agent.LimitsRunner{Inner: r, Limits: limits}
+		}
+		return r
+	}
+	runner := newLoopRunner(loopProv)
+	if sandbox != nil {
+		fmt.Printf("🔒 Sandbox: %s image %s (mounts: %s)\n", sandbox.EngineName(), sandbox.Image, strings.Join(sandbox.Mounts, ", "))
+	}
+
+	// A cheap ping now beats finding out after a long first iteration that the key expired
+	if config.Preflight && !config.Interactive {
+		if err := runPreflight(ctx, runner, loopProv, config.ProjectDir, providerType, firstIterationModel(config, loopProv, juggleSession)); err != nil {
+			_ = sessionStore.AppendProgress(storageID, "[PREFLIGHT] "+err.Error())
+			return nil, err
+		}
+	}
+
+	// Toolchain detection reads manifests, so build the system prompt once per run
+	sessionPrompt := ""
+	if juggleSession != nil {
+		sessionPrompt = juggleSession.SystemPrompt
+	}
+	systemPrompt, err := agentSystemPrompt(config.ProjectDir, sessionPrompt, systemPromptVars{
+		SessionID:  config.SessionID,
+		BallCount:  totalCount,
+		Provider:   string(providerType),
+		ProjectDir: config.ProjectDir,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Session and ball setup commands run around the iterations; teardown runs on any exit
+	agentEnv := newAgentEnvironment(config.ProjectDir, juggleSession)
+	defer agentEnv.close()
+
+	// The session's docs briefing follows its context docs as they change
+	contextDocs := newContextDocsWatcher(sessionStore, juggleSession)
+	defer contextDocs.close()
+
+	// Estimate time remaining from past iterations, refined as this run's iterations finish
+	eta := newRunETA(config.ProjectDir)
+
+	// Iterations that change nothing are counted, and enough in a row change strategy
+	noOps := newNoOpTracker(config.ProjectDir)
+
+	if checkpoint != nil {
+		fmt.Printf("↩️  Resuming interrupted run from iteration %d/%d (started %s)\n\n",
+			startIteration, config.MaxIterations, checkpoint.StartedAt.Format("2006-01-02 15:04"))
+	} else if previous, _ := sessionStore.LoadCheckpoint(storageID, config.BallID); previous != nil {
+		fmt.Fprintf(os.Stderr, "ℹ️  Starting over; a previous run stopped at iteration %d (--resume continues it instead)\n", previous.Iteration)
+	}
+
+	// --plan-first: iteration 1 plans the balls in plan mode, the rest execute the plans
+	if config.PlanFirst && !config.Interactive && checkpoint == nil && startIteration <= config.MaxIterations {
+		if !waitOutQuietHours(ctx, config.QuietHours) || !usageWindow.waitForCapacity(ctx) {
+			return cancelled()
+		}
+		unplanned, err := ballsToPlan(config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load balls to plan: %v\n", err)
+		}
+		if len(unplanned) > 0 {
+			fmt.Printf("═══════════════════════════ Iteration %d/%d (planning) ═══════════════════════════\n\n", startIteration, config.MaxIterations)
+			planStarted := time.Now()
+			planResult, _, err := runPlanningIteration(ctx, config, runner, unplanned, firstIterationModel(config, loopProv, juggleSession), storageID)
+			if ctx.Err() != nil {
+				return cancelled()
+			}
+			if err != nil {
+				return nil, err
+			}
+			result.addUsage(planResult)
+			usageWindow.record(planStarted, planResult)
+			result.Iterations = startIteration
+			startIteration++
+		}
+	}
+
+	var iterationSnapshot *session.IterationSnapshot
+	for iteration := startIteration; iteration <= config.MaxIterations; iteration++ {
+		if ctx.Err() != nil {
+			return cancelled()
+		}
+		// Retried runs still spend tokens, so check before starting another
+		if budgetExceeded() {
+			break
+		}
+		// Retries are deferred too, since they spend tokens like any iteration
+		if !config.Interactive && !waitOutQuietHours(ctx, config.QuietHours) {
+			return cancelled()
+		}
+		if !usageWindow.waitForCapacity(ctx) {
+			return cancelled()
+		}
+		waited, exceeded := sharedLimit.wait(ctx, config.MaxWait, totalWaitTime+overloadWaitTime)
+		totalWaitTime += waited
+		if ctx.Err() != nil {
+			return cancelled()
+		}
+		if exceeded {
+			result.RateLimitExceded = true
+			result.TotalWaitTime = totalWaitTime
+			logRateLimitToProgress(config.ProjectDir, storageID,
+				fmt.Sprintf("Shared rate limit exceeded max-wait of %v (total waited: %v)", config.MaxWait, totalWaitTime))
+			break
+		}
 		result.Iterations = iteration
+		isRetry := rateLimitRetrying || overloadRetrying || crashRetrying
+		contextDocs.refreshIfChanged()
+
+		// Persist loop state so a crashed or killed run can --resume from here
+		if err := sessionStore.SaveCheckpoint(storageID, &session.RunCheckpoint{
+			Iteration:        iteration,
+			MaxIterations:    config.MaxIterations,
+			BallID:           config.BallID,
+			RateLimitRetries: rateLimitRetries,
+			OverloadRetries:  overloadRetries,
+			CrashRetries:     crashRetries,
+			RateLimitWait:    totalWaitTime,
+			OverloadWait:     overloadWaitTime,
+			InputTokens:      result.InputTokens,
+			OutputTokens:     result.OutputTokens,
+			CostUSD:          result.CostUSD,
+			IterationTimings: result.IterationTimings,
+			StartedAt:        result.StartedAt,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save run checkpoint: %v\n", err)
+		}
 
 		// Print iteration separator and header (skip when retrying after rate limit, overload, or crash)
-		if !rateLimitRetrying && !overloadRetrying && !crashRetrying {
+		if !isRetry {
 			if iteration > 1 {
 				fmt.Println()
 				fmt.Println()
@@ -553,7 +1078,13 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 				fmt.Println()
 				fmt.Println()
 			}
-			fmt.Printf("════════════════════════════════ Iteration %d/%d ════════════════════════════════\n\n", iteration, config.MaxIterations)
+			// The start time lets juggle agent logs --since find the iteration in agent.log
+			fmt.Printf("════════════════════════════ Iteration %d/%d · %s ════════════════════════════\n", iteration, config.MaxIterations, time.Now().Format("15:04:05"))
+			events.emit(AgentEvent{Type: AgentEventIterationStart, Iteration: iteration, MaxIterations: config.MaxIterations, BallID: config.BallID})
+			if usage := session.FormatUsage(result.InputTokens, result.OutputTokens, result.CostUSD); usage != "" {
+				fmt.Printf("📊 Usage so far: %s\n", usage)
+			}
+			fmt.Println()
 		}
 		rateLimitRetrying = false  // Reset for next iteration
 		overloadRetrying = false   // Reset for next iteration
@@ -563,41 +1094,112 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		// Use storageID (maps "all" to "_all") for progress tracking
 		progressBefore := getProgressLineCount(sessionStore, storageID)
 
+		// Snapshot VCS revision and ball states so this iteration can be rolled back
+		if !isRetry {
+			iterationSnapshot = recordIterationSnapshot(sessionStore, config.ProjectDir, config.SessionID, storageID, iteration)
+		}
+
 		// Daemon mode: check for control commands and update state
 		if config.DaemonMode {
 			// Check for pause - wait until resumed
 			for daemonPaused {
-				time.Sleep(500 * time.Millisecond)
-				ctrl, _ := daemon.ReadControlCommand(config.ProjectDir, storageID)
-				if ctrl != nil && ctrl.Command == daemon.CmdResume {
+				ctrl := control.Wait(ctx)
+				if ctrl == nil {
+					return cancelled()
+				}
+				events.record(AgentEvent{Type: AgentEventControl, Iteration: iteration, Command: ctrl.Command, Args: ctrl.Args})
+				if ctrl.Command == daemon.CmdResume {
 					daemonPaused = false
 					fmt.Println("▶️  Resumed by user")
+					events.record(AgentEvent{Type: AgentEventResumed, Iteration: iteration})
 				}
 			}
 
 			// Check for control commands
-			ctrl, _ := daemon.ReadControlCommand(config.ProjectDir, storageID)
-			if ctrl != nil {
+			if ctrl := control.Next(); ctrl != nil {
+				events.record(AgentEvent{Type: AgentEventControl, Iteration: iteration, Command: ctrl.Command, Args: ctrl.Args})
 				switch ctrl.Command {
 				case daemon.CmdCancel:
 					fmt.Println("🛑 Cancelled by user")
 					result.Blocked = true
 					result.BlockedReason = "Cancelled by user via monitor TUI"
-					result.EndedAt = time.Now()
-					return result, nil
+					return endedEarly()
 				case daemon.CmdPause:
 					daemonPaused = true
 					fmt.Println("⏸️  Pausing after this iteration...")
+					events.record(AgentEvent{Type: AgentEventPaused, Iteration: iteration})
+				case daemon.CmdTakeover:
+					// The previous iteration has finished, so hand over before starting this one
+					if !waitForTakeover(ctx, config, control, storageID, iteration, startTime, string(providerType)) {
+						if ctx.Err() != nil {
+							return cancelled()
+						}
+						fmt.Println("🛑 Cancelled by user")
+						result.Blocked = true
+						result.BlockedReason = "Cancelled by user via monitor TUI"
+						return endedEarly()
+					}
 				case daemon.CmdChangeModel:
 					if ctrl.Args != "" {
 						config.Model = ctrl.Args
 						fmt.Printf("🔧 Model changed to %s for next iteration\n", ctrl.Args)
+						events.record(AgentEvent{Type: AgentEventModelChanged, Iteration: iteration, Model: config.Model})
+					}
+				case daemon.CmdChangeProvider:
+					if ctrl.Args == "" {
+						break
+					}
+					newProv, err := providerForSwitch(ctrl.Args, sandbox != nil, config.Interactive)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "⚠️  Keeping provider %s: %v\n", providerType, err)
+						break
+					}
+					// An explicit switch wins over balls' agent_provider, as --provider does
+					providerType = newProv.Type()
+					config.Provider = string(providerType)
+					loopProv = newProv
+					runner = newLoopRunner(loopProv)
+					sharedLimit = newSharedRateLimit(config, providerType)
+					fmt.Printf("🔧 Provider changed to %s for next iteration\n", providerType)
+					if config.Model != "" && !provider.IsModelSupported(loopProv, config.Model) {
+						fmt.Printf("🔧 %s doesn't accept model %s, selecting the model automatically\n", providerType, config.Model)
+						config.Model = ""
 					}
+					_ = sessionStore.AppendProgress(storageID, fmt.Sprintf("[PROVIDER] Switched to %s from the monitor", providerType))
+					events.record(AgentEvent{Type: AgentEventProviderChanged, Iteration: iteration, Provider: string(providerType), Model: config.Model})
 				case daemon.CmdSkipBall:
-					// Mark current ball as blocked and continue
+					// Block the ball the monitor showed (or the run's own ball) and move on
+					ballID, requester := daemon.ParseSkipBallArgs(ctrl.Args)
+					if config.BallID != "" {
+						ballID = config.BallID
+					} else if ballID == "" {
+						ballID = daemonBallID
+					}
+					if ballID == "" {
+						fmt.Println("⏭️  No ball to skip yet")
+						break
+					}
+					skipped, err := skipBall(config.ProjectDir, config.SessionID, storageID, ballID, requester)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: failed to skip ball %s: %v\n", ballID, err)
+						break
+					}
+					if skipped == nil {
+						fmt.Printf("⏭️  Ball %s is already done or blocked, nothing to skip\n", ballID)
+						break
+					}
+					fmt.Printf("⏭️  Skipped ball %s: %s\n", skipped.ShortID(), skipped.BlockedReason)
 					if config.BallID != "" {
-						fmt.Printf("⏭️  Skipping ball %s by user request\n", config.BallID)
-						// Ball skip will be handled by marking it blocked - for now just log
+						// The run is limited to the skipped ball; let other runs have it straight away
+						_ = lockRelease()
+						result.Blocked = true
+						result.BlockedReason = skipped.BlockedReason
+						return endedEarly()
+					}
+					if workable, _, _, err := countWorkableBalls(config.ProjectDir, config.SessionID, config.BallID, config.Interactive); err == nil && workable == 0 {
+						fmt.Fprintf(os.Stderr, "⏸ No actionable work left after skipping %s\n", skipped.ShortID())
+						result.Blocked = true
+						return endedEarly()
 					}
 				}
 			}
@@ -611,18 +1213,37 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 
 		// Check for ball-level AgentProvider override when working on a single ball
 		activeBalls := filterActiveBalls(balls)
+		iterationProvider := providerType
 		if len(activeBalls) == 1 && activeBalls[0].AgentProvider != "" && config.Provider == "" {
 			// Ball has an AgentProvider override and CLI didn't explicitly set one
 			ballProvider := activeBalls[0].AgentProvider
-			if provider.IsAvailable(provider.Type(ballProvider)) {
-				agentProv := provider.Get(provider.Type(ballProvider))
-				agent.SetProvider(agentProv)
-				fmt.Printf("🔧 Provider: %s (ball %s has agent_provider override)\n", ballProvider, activeBalls[0].ShortID())
-			} else {
+			if !provider.IsAvailable(provider.Type(ballProvider)) {
 				fmt.Fprintf(os.Stderr, "⚠️  Ball %s has agent_provider=%q but it's not available, using default\n", activeBalls[0].ShortID(), ballProvider)
+			} else if ballProv := provider.Get(provider.Type(ballProvider)); !ballProv.SupportsTools() {
+				fmt.Fprintf(os.Stderr, "⚠️  Ball %s has agent_provider=%q but it has no tools, using default\n", activeBalls[0].ShortID(), ballProvider)
+			} else if config.Interactive && !ballProv.SupportsInteractive() {
+				fmt.Fprintf(os.Stderr, "⚠️  Ball %s has agent_provider=%q but it doesn't support interactive mode, using default\n", activeBalls[0].ShortID(), ballProvider)
+			} else {
+				loopProv = ballProv
+				runner = newLoopRunner(loopProv)
+				iterationProvider = ballProv.Type()
+				fmt.Printf("🔧 Provider: %s (ball %s has agent_provider override)\n", ballProvider, activeBalls[0].ShortID())
 			}
 		}
 
+		// Ball-scoped setup and timeout only apply when the iteration targets a single ball
+		var scopeBall *session.Ball
+		if len(activeBalls) == 1 {
+			scopeBall = activeBalls[0]
+		}
+		if err := agentEnv.prepare(scopeBall); err != nil {
+			return nil, fmt.Errorf("setup failed: %w", err)
+		}
+		timeout := iterationTimeout(config.Timeout, scopeBall)
+		if timeout != config.Timeout {
+			fmt.Printf("⏱️  Timeout: %s (ball %s has a timeout override)\n", formatBallTimeout(timeout), scopeBall.ShortID())
+		}
+
 		// Get session default model
 		var sessionDefaultModel session.ModelSize
 		if juggleSession != nil {
@@ -630,11 +1251,26 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		}
 
 		// Select optimal model for this iteration
-		modelSelection := selectModelForIteration(config, balls, sessionDefaultModel)
+		modelSelection := noOps.selection(selectModelForProvider(config, loopProv, balls, sessionDefaultModel))
 
 		// Log model selection (only if not explicitly set)
 		if config.Model == "" {
-			fmt.Printf("🤖 Model: %s (%s)\n\n", modelSelection.Model, modelSelection.Reason)
+			fmt.Printf("🤖 Model: %s (%s)\n", modelSelection.Model, modelSelection.Reason)
+		}
+		if !isRetry {
+			events.emit(AgentEvent{Type: AgentEventModelSelected, Iteration: iteration, Model: modelSelection.Model, Reason: modelSelection.Reason})
+		}
+
+		// Rough ETA from past iteration durations and the balls left to work
+		var estimatedEnd time.Time
+		if workable, _, _, err := countWorkableBalls(config.ProjectDir, config.SessionID, config.BallID, config.Interactive); err == nil {
+			if remaining, ok := eta.estimate(modelSelection.Model, workable, config.MaxIterations-iteration+1); ok {
+				estimatedEnd = time.Now().Add(remaining)
+				fmt.Printf("⏳ ETA: %s (%d workable ball(s), done around %s)\n", formatETA(remaining), workable, estimatedEnd.Format("15:04"))
+			}
+		}
+		if config.Model == "" || !estimatedEnd.IsZero() {
+			fmt.Println()
 		}
 
 		// Daemon mode: update state file for TUI to read
@@ -646,6 +1282,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 				currentBallTitle = activeBalls[0].Title
 				acsTotal = len(activeBalls[0].AcceptanceCriteria)
 			}
+			daemonBallID = currentBallID
 			state := &daemon.State{
 				Running:          true,
 				Paused:           daemonPaused,
@@ -658,13 +1295,14 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 				Model:            modelSelection.Model,
 				Provider:         string(providerType),
 				StartedAt:        startTime,
+				EstimatedEnd:     estimatedEnd,
 			}
 			// Best effort - don't fail if state write fails
 			_ = daemon.WriteStateFile(config.ProjectDir, storageID, state)
 		}
 
 		// Generate prompt using export command
-		prompt, err := generateAgentPrompt(config.ProjectDir, config.SessionID, config.Debug, config.BallID, config.Message)
+		prompt, err := generateAgentPrompt(config.ProjectDir, config.SessionID, config.Debug, config.BallID, noOps.message(outputs.message(config.Message)))
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate prompt: %w", err)
 		}
@@ -674,8 +1312,11 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			Prompt:     prompt,
 			Mode:       agent.ModeHeadless,
 			Permission: agent.PermissionAcceptEdits,
-			Timeout:    config.Timeout,
+			Timeout:    timeout,
 			Model:      modelSelection.Model,
+			Env:        append(agentEnv.vars(), "JUGGLE_SESSION_ID="+config.SessionID, "JUGGLE_BALL_ID="+config.BallID),
+			Context:    ctx,
+			Stop:       stopper.stop,
 		}
 		if config.Interactive {
 			opts.Mode = agent.ModeInteractive
@@ -683,37 +1324,63 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		if config.Trust {
 			opts.Permission = agent.PermissionBypass
 		}
-		// Add autonomous system prompt for headless mode
+		// Add autonomous system prompt and toolchain briefing for headless mode
 		if !config.Interactive {
-			opts.SystemPrompt = agent.AutonomousSystemPrompt
+			opts.SystemPrompt = systemPrompt
 		}
 
 		// Run agent with options using the Runner interface
-		runResult, err := agent.DefaultRunner.Run(opts)
+		runStarted := time.Now()
+		progressBeforeRun := loadProgressText(sessionStore, storageID)
+		stopper.running.Store(true)
+		runResult, err := runner.Run(opts)
+		stopper.running.Store(false)
+		if ctx.Err() != nil {
+			// The agent was killed mid-iteration; its result is incomplete
+			if runResult != nil {
+				_ = os.WriteFile(outputPath, []byte(outputs.apply(iterationProvider, runResult.Output)), 0644)
+			}
+			return cancelled()
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to run agent: %w", err)
 		}
 
+		// Tokens are spent even when the run is retried, so count every run
+		result.addUsage(runResult)
+		usageWindow.record(runStarted, runResult)
+		if usage := session.FormatUsage(runResult.InputTokens, runResult.OutputTokens, runResult.CostUSD); usage != "" {
+			fmt.Printf("\n📊 Iteration usage: %s\n", usage)
+		}
+
+		// A stop requested during the iteration ends the run now that the agent has returned
+		if stopper.requested.Load() {
+			_ = os.WriteFile(outputPath, []byte(outputs.apply(iterationProvider, runResult.Output)), 0644)
+			return stopped()
+		}
+
 		// Check for subprocess crash (non-zero exit, not rate limit/overload)
 		if runResult.Error != nil && runResult.ExitCode != 0 && !runResult.RateLimited && !runResult.OverloadExhausted {
-			waitTime := time.Duration(math.Pow(2, float64(crashRetries))) * time.Second
-			if waitTime > 60*time.Second {
-				waitTime = 60 * time.Second
+			if retry.crash.Exhausted(crashRetries) {
+				return nil, fmt.Errorf("agent crashed %d times, giving up (last error: %v)", crashRetries+1, runResult.Error)
 			}
-
+			waitTime := retry.crash.Delay(crashRetries, 0)
 			crashRetries++
-			if crashRetries > maxCrashRetries {
-				return nil, fmt.Errorf("agent crashed %d times, giving up (last error: %v)", crashRetries, runResult.Error)
-			}
 
+			attempt := fmt.Sprintf("%d", crashRetries)
+			if retry.crash.MaxAttempts > 0 {
+				attempt += fmt.Sprintf("/%d", retry.crash.MaxAttempts)
+			}
 			logCrashToProgress(config.ProjectDir, storageID,
-				fmt.Sprintf("Agent crashed (exit code %d), waiting %v before retry (attempt %d/%d)",
-					runResult.ExitCode, waitTime, crashRetries, maxCrashRetries))
+				fmt.Sprintf("Agent crashed (exit code %d), waiting %v before retry (attempt %s)",
+					runResult.ExitCode, waitTime, attempt))
 
-			fmt.Printf("💥 Agent crashed (exit code %d). Waiting %v before retry (attempt %d/%d)...\n",
-				runResult.ExitCode, waitTime, crashRetries, maxCrashRetries)
+			fmt.Printf("💥 Agent crashed (exit code %d). Waiting %v before retry (attempt %s)...\n",
+				runResult.ExitCode, waitTime, attempt)
 
-			waitWithCountdown(waitTime)
+			if !waitWithCountdown(ctx, waitTime) {
+				return cancelled()
+			}
 			crashRetrying = true
 
 			iteration--
@@ -722,7 +1389,27 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 
 		// Check for rate limit
 		if runResult.RateLimited {
-			waitTime := calculateWaitTime(runResult.RetryAfter, rateLimitRetries)
+			// Back off from where the other runs on this account got to
+			waitTime := retry.rateLimit.Delay(sharedLimit.attempts(rateLimitRetries), runResult.RetryAfter)
+			// Without a hint from the provider, wait for the usage window to
+			// reset rather than backing off blindly. Later attempts back off,
+			// in case the window's reset time is off.
+			resetWait, resets, knowsReset := usageWindow.rateLimited()
+			waitForReset := knowsReset && runResult.RetryAfter == 0 && rateLimitRetries == 0
+			if waitForReset {
+				waitTime = resetWait
+			}
+			// Hold off the other runs on this account too, even if this one gives up
+			sharedLimit.limited(waitTime)
+
+			// Check if we've run out of retries
+			if retry.rateLimit.Exhausted(rateLimitRetries) {
+				result.RateLimitExceded = true
+				result.TotalWaitTime = totalWaitTime
+				logRateLimitToProgress(config.ProjectDir, storageID,
+					fmt.Sprintf("Rate limit retries exhausted after %d attempts (total waited: %v)", rateLimitRetries, totalWaitTime))
+				break
+			}
 
 			// Check if we've exceeded max wait
 			if config.MaxWait > 0 && totalWaitTime+waitTime > config.MaxWait {
@@ -737,10 +1424,18 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			logRateLimitToProgress(config.ProjectDir, storageID,
 				fmt.Sprintf("Rate limited, waiting %v before retry (attempt %d)", waitTime, rateLimitRetries+1))
 
-			fmt.Printf("⏳ Rate limited. Waiting %v before retry...\n", waitTime)
+			if waitForReset {
+				fmt.Printf("⏳ Rate limited. Waiting %v for the usage window to reset at %s...\n", waitTime.Round(time.Second), resets.Format("15:04"))
+			} else {
+				fmt.Printf("⏳ Rate limited. Waiting %v before retry...\n", waitTime)
+			}
+			events.emit(AgentEvent{Type: AgentEventRateLimited, Iteration: iteration, Attempt: rateLimitRetries + 1, WaitSeconds: waitTime.Seconds()})
+			notifier.rateLimitWait(iteration, waitTime)
 
 			// Wait with countdown display
-			waitWithCountdown(waitTime)
+			if !waitWithCountdown(ctx, waitTime) {
+				return cancelled()
+			}
 
 			totalWaitTime += waitTime
 			rateLimitRetries++
@@ -754,19 +1449,26 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		// Reset retry counters on successful run
 		rateLimitRetries = 0
 		crashRetries = 0
+		sharedLimit.succeeded(runStarted)
 
 		// Check for 529 overload exhaustion (Claude's built-in retries exhausted)
 		if runResult.OverloadExhausted {
-			waitTime := time.Duration(overloadRetryMinutes) * time.Minute
+			waitTime := retry.overload.Delay(overloadRetries, 0)
 
-			// Check if we've exceeded max wait
-			if config.MaxWait > 0 && totalWaitTime+overloadWaitTime+waitTime > config.MaxWait {
+			// Check if we've run out of retries or exceeded max wait
+			exhausted := retry.overload.Exhausted(overloadRetries)
+			if exhausted || (config.MaxWait > 0 && totalWaitTime+overloadWaitTime+waitTime > config.MaxWait) {
 				result.RateLimitExceded = true
 				result.TotalWaitTime = totalWaitTime + overloadWaitTime
 				result.OverloadRetries = overloadRetries
 				result.OverloadWaitTime = overloadWaitTime
-				logOverloadToProgress(config.ProjectDir, storageID,
-					fmt.Sprintf("Overload retry exceeded max-wait of %v (total waited: %v)", config.MaxWait, totalWaitTime+overloadWaitTime))
+				if exhausted {
+					logOverloadToProgress(config.ProjectDir, storageID,
+						fmt.Sprintf("Overload retries exhausted after %d attempts (total waited: %v)", overloadRetries, totalWaitTime+overloadWaitTime))
+				} else {
+					logOverloadToProgress(config.ProjectDir, storageID,
+						fmt.Sprintf("Overload retry exceeded max-wait of %v (total waited: %v)", config.MaxWait, totalWaitTime+overloadWaitTime))
+				}
 				break
 			}
 
@@ -778,7 +1480,9 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			fmt.Printf("⏳ Waiting %v before restarting agent...\n", waitTime)
 
 			// Wait with countdown display
-			waitWithCountdown(waitTime)
+			if !waitWithCountdown(ctx, waitTime) {
+				return cancelled()
+			}
 
 			overloadWaitTime += waitTime
 			overloadRetries++
@@ -792,14 +1496,113 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		// Check for timeout
 		if runResult.TimedOut {
 			result.TimedOut = true
-			result.TimeoutMessage = fmt.Sprintf("Iteration %d timed out after %v", iteration, config.Timeout)
+			result.TimeoutMessage = fmt.Sprintf("Iteration %d timed out after %v", iteration, timeout)
 			// Log timeout to progress
 			logTimeoutToProgress(config.ProjectDir, storageID, result.TimeoutMessage)
 			break
 		}
 
 		// Save output to file (ignore errors for test compatibility)
-		_ = os.WriteFile(outputPath, []byte(runResult.Output), 0644)
+		_ = os.WriteFile(outputPath, []byte(outputs.apply(iterationProvider, runResult.Output)), 0644)
+
+		// Judge the agent's own work, before juggle changes anything below
+		progressAdded := strings.TrimPrefix(loadProgressText(sessionStore, storageID), progressBeforeRun)
+		noOp := iterationWasNoOp(config.ProjectDir, config.SessionID, iterationSnapshot, progressAdded)
+		if noOp {
+			result.NoOpIterations++
+		}
+
+		// Balls the agent split become epics before the ball state is checked
+		applyAgentSplits(config, runResult.Output)
+
+		// Only iterations that ran to the end count toward the ETA
+		timing := session.IterationTiming{Model: modelSelection.Model, Duration: time.Since(runStarted)}
+		result.IterationTimings = append(result.IterationTimings, timing)
+		eta.add(timing)
+		events.record(AgentEvent{Type: AgentEventIterationEnd, Iteration: iteration, Model: timing.Model, DurationSeconds: timing.Duration.Seconds(), NoOp: noOp})
+
+		// Remember what changed before guardrails may isolate it
+		recordIterationTouches(config.ProjectDir, storageID, config.BallID, iterationSnapshot)
+
+		// Count the attempt and block balls that have used up their retry budget
+		recordBallAttempts(config.ProjectDir, config.SessionID, storageID, config.BallID, iterationSnapshot)
+
+		// Refuse to auto-commit oversized diffs; the work is isolated for review instead
+		if (runResult.Complete || runResult.Continue) && runResult.CommitMessage != "" {
+			if enforceDiffLimit(config.ProjectDir, config.SessionID, storageID, config.BallID, iterationSnapshot) {
+				runResult.CommitMessage = ""
+			}
+		}
+
+		// Fold formatter and lint fixes into the upcoming auto-commit
+		if (runResult.Complete || runResult.Continue) && runResult.CommitMessage != "" {
+			runFormatters(config.ProjectDir, storageID, iterationSnapshot)
+		}
+
+		// Secret and license gates abort the commit and stop the run for human review
+		if (runResult.Complete || runResult.Continue) && runResult.CommitMessage != "" {
+			if reason := enforceCommitGates(config.ProjectDir, config.SessionID, storageID, config.BallID, iterationSnapshot); reason != "" {
+				result.Blocked = true
+				result.BlockedReason = reason
+				break
+			}
+		}
+
+		// Failing gates or tests skip the commit and send the work back to the agent
+		if (runResult.Complete || runResult.Continue) && runResult.CommitMessage != "" {
+			gateResults, summary := runGates(ctx, config.ProjectDir, config.SessionID, storageID, config.BallID, iteration, iterationSnapshot)
+			result.GateResults = append(result.GateResults, gateResults...)
+			if summary == "" {
+				summary = runValidation(config.ProjectDir, config.SessionID, storageID, config.BallID, iterationSnapshot)
+			}
+			if summary != "" {
+				fmt.Println()
+				fmt.Printf("❌ Validation failed, skipping auto-commit: %s\n", summary)
+				reopened, err := reopenBallsAfterValidation(config.ProjectDir, config.SessionID, config.BallID, iterationSnapshot)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to reopen balls: %v\n", err)
+				}
+				for _, id := range reopened {
+					fmt.Printf("↩️  Ball %s reopened for fixes\n", id)
+				}
+				runResult.CommitMessage = ""
+				runResult.Complete = false
+				runResult.Continue = false
+			}
+		}
+
+		// A second model checks completed balls against their criteria before the commit
+		if runResult.Complete || runResult.Continue {
+			if reopened := verifyCompletedBalls(ctx, runner, config.ProjectDir, config.SessionID, storageID, config.BallID, iterationSnapshot); len(reopened) > 0 {
+				fmt.Println()
+				fmt.Printf("❌ Verifier rejected %d ball(s), skipping auto-commit\n", len(reopened))
+				runResult.CommitMessage = ""
+				runResult.Complete = false
+				runResult.Continue = false
+			}
+		}
+
+		// Report balls that stayed complete through validation and verification
+		if events.enabled() {
+			completed, _ := ballsCompletedSince(config.ProjectDir, config.SessionID, config.BallID, iterationSnapshot)
+			for _, ball := range completed {
+				events.emit(AgentEvent{Type: AgentEventBallComplete, Iteration: iteration, BallID: ball.ID, Title: ball.Title})
+			}
+		}
+		notifier.ballChanges(iteration, iterationSnapshot)
+
+		// Balls left unfinished count against the model; enough failures move them up
+		if config.Model == "" {
+			recordModelFailures(config.ProjectDir, config.SessionID, storageID, config.BallID, modelSelection.Model, iterationSnapshot)
+		}
+
+		// Spinning without changing anything nudges, escalates or stops the run
+		if reason := noOps.record(config, storageID, noOp, modelSelection.Model); reason != "" {
+			fmt.Fprintf(os.Stderr, "💤 Stopping: %s\n", reason)
+			result.Blocked = true
+			result.BlockedReason = reason
+			break
+		}
 
 		// Check for completion signals (already parsed by Runner)
 		if runResult.Complete {
@@ -829,6 +1632,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 							}
 						}
 					}
+					recordCompletedSnapshot(sessionStore, config.ProjectDir, config.SessionID, storageID, iteration)
 					result.Complete = true
 					result.BallsComplete = complete
 					result.BallsBlocked = blocked
@@ -871,12 +1675,21 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 					}
 				}
 
+				// Record the state after this iteration so rollback can return to it
+				recordCompletedSnapshot(sessionStore, config.ProjectDir, config.SessionID, storageID, iteration)
+
 				// Update ball counts for progress tracking
-				_, complete, blocked, total := checkBallsTerminal(config.ProjectDir, config.SessionID, config.BallID)
+				terminal, complete, blocked, total := checkBallsTerminal(config.ProjectDir, config.SessionID, config.BallID)
 				result.BallsComplete = complete
 				result.BallsBlocked = blocked
 				result.BallsTotal = total
 
+				// The last ball may have just used up its retry budget
+				if total > 0 && terminal == total {
+					result.Complete = true
+					break
+				}
+
 				continue
 			}
 		}
@@ -904,24 +1717,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 					fmt.Printf("🔍 Detected uncommitted changes despite no progress update\n")
 					fmt.Printf("📊 Backing out work and accepting BLOCKED signal...\n")
 
-					// Describe the working copy with BLOCKED reason
-					descMsg := fmt.Sprintf("BLOCKED: %s", runResult.BlockedReason)
-					if err := backend.DescribeWorkingCopy(config.ProjectDir, descMsg); err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to describe working copy: %v\n", err)
-					}
-
-					// Isolate work and reset to clean state
-					isolatedRev, err := backend.IsolateAndReset(config.ProjectDir, "")
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to isolate work: %v\n", err)
-					} else if isolatedRev != "" {
-						fmt.Printf("✓ Isolated work in revision: %s\n", isolatedRev)
-
-						// Verify working copy is clean after reset
-						if stillDirty, checkErr := backend.HasChanges(config.ProjectDir); checkErr == nil && stillDirty {
-							fmt.Fprintf(os.Stderr, "Warning: working copy still has changes after reset\n")
-						}
-					}
+					isolateWork(backend, config.ProjectDir, "", fmt.Sprintf("BLOCKED: %s", runResult.BlockedReason))
 
 					result.Blocked = true
 					result.BlockedReason = runResult.BlockedReason
@@ -950,43 +1746,46 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			break
 		}
 
+		// The rest may all be waiting on balls that just got blocked
+		if workable, _, _, err := countWorkableBalls(config.ProjectDir, config.SessionID, config.BallID, config.Interactive); err == nil && total > 0 && workable == 0 {
+			fmt.Fprintf(os.Stderr, "⏸ No actionable work: remaining balls are claimed or waiting on blocked dependencies\n")
+			result.Blocked = true
+			break
+		}
+
+		if budgetExceeded() {
+			break
+		}
+
 		// Delay before next iteration (unless this was the last one)
 		if iteration < config.MaxIterations && config.IterDelay > 0 {
-			time.Sleep(config.IterDelay)
+			if !sleepContext(ctx, config.IterDelay) {
+				return cancelled()
+			}
 		}
 	}
 
 	result.TotalWaitTime = totalWaitTime + overloadWaitTime
 	result.OverloadRetries = overloadRetries
 	result.OverloadWaitTime = overloadWaitTime
+	// Every ball is terminal, so review what the run did
+	if result.Complete && !config.Interactive && reviewEnabled(config) {
+		result.ReviewPath = runReview(ctx, runner, config, sessionStore, storageID)
+	}
 	result.EndedAt = time.Now()
 
+	// The run ended on its own, so there is nothing to resume
+	if err := sessionStore.ClearCheckpoint(storageID, config.BallID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to clear run checkpoint: %v\n", err)
+	}
+
 	// Save run history (best-effort, don't fail the run if this errors)
 	saveAgentHistory(config, result, outputPath)
+	events.summary(result)
 
 	return result, nil
 }
 
-// calculateWaitTime determines how long to wait before retrying after rate limit
-// Uses the explicit retry-after time if provided, otherwise exponential backoff
-func calculateWaitTime(retryAfter time.Duration, retryCount int) time.Duration {
-	if retryAfter > 0 {
-		// Use the time specified by Claude, with a small buffer
-		return retryAfter + 5*time.Second
-	}
-
-	// Exponential backoff: 30s, 1m, 2m, 4m, 8m, 16m (capped at 16m)
-	baseWait := 30 * time.Second
-	maxWait := 16 * time.Minute
-
-	wait := baseWait * time.Duration(1<<retryCount) // 2^retryCount
-	if wait > maxWait {
-		wait = maxWait
-	}
-
-	return wait
-}
-
 // calculateFuzzyDelay calculates the actual delay to use with random variance.
 // baseMinutes is the base delay in minutes, fuzz is the +/- variance in minutes.
 // The actual delay will be: base + random(-fuzz, fuzz) minutes.
@@ -1010,8 +1809,9 @@ func CalculateFuzzyDelayForTest(baseMinutes, fuzz int) time.Duration {
 	return calculateFuzzyDelay(baseMinutes, fuzz)
 }
 
-// waitWithCountdown waits for the specified duration, showing periodic countdown updates
-func waitWithCountdown(duration time.Duration) {
+// waitWithCountdown waits for the specified duration, showing periodic countdown updates.
+// Returns false if ctx was cancelled before the wait finished.
+func waitWithCountdown(ctx context.Context, duration time.Duration) bool {
 	remaining := duration
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -1024,9 +1824,33 @@ func waitWithCountdown(duration time.Duration) {
 				fmt.Printf("  ... %v remaining\n", remaining.Round(time.Second))
 			}
 		case <-time.After(remaining):
-			return
+			return true
+		case <-ctx.Done():
+			return false
 		}
 	}
+	return ctx.Err() == nil
+}
+
+// sleepContext sleeps for duration. Returns false if ctx was cancelled first.
+func sleepContext(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// commandContext returns the command's context, or Background when the
+// command wasn't started through Execute (e.g. called directly from tests)
+func commandContext(cmd *cobra.Command) context.Context {
+	if cmd != nil && cmd.Context() != nil {
+		return cmd.Context()
+	}
+	return context.Background()
 }
 
 // logRateLimitToProgress logs a rate limit event to the session's progress file
@@ -1069,82 +1893,73 @@ type SessionSelection struct {
 }
 
 // selectSessionForAgent shows an interactive session selector for agent run.
+// With --all, projects are loaded concurrently and their sessions listed as
+// they arrive, under a spinner. Ctrl-C cancels loading or the prompt.
 // Returns the selected session info or nil if cancelled.
-func selectSessionForAgent(cwd string) (*SessionSelection, error) {
-	// Load config to discover projects
-	config, err := LoadConfigForCommand()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %w", err)
-	}
-
-	// Create store for current directory
-	store, err := NewStoreForCommand(cwd)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create store: %w", err)
-	}
+func selectSessionForAgent(ctx context.Context, cwd string) (*SessionSelection, error) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
 
-	// Get session store for local sessions
-	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize session store: %w", err)
-	}
-
-	// Collect sessions based on scope (--all flag)
-	type sessionInfo struct {
-		ID          string
-		Description string
-		ProjectDir  string
-		BallCount   int
+	var sessions []SessionInfo
+	printSession := func(spinner *selectorSpinner, s SessionInfo) {
+		if len(sessions) == 0 {
+			spinner.printf(os.Stdout, "Select a session to run the agent on:\n\n")
+		}
+		sessions = append(sessions, s)
+		prefix := fmt.Sprintf("  %d. %s", len(sessions), s.ID)
+		ballInfo := fmt.Sprintf("(%d balls)", s.BallCount)
+		if s.Description != "" {
+			spinner.printf(os.Stdout, "%s - %s %s\n", prefix, s.Description, ballInfo)
+		} else {
+			spinner.printf(os.Stdout, "%s %s\n", prefix, ballInfo)
+		}
+		// Show project directory if viewing all projects
+		if GlobalOpts.AllProjects {
+			spinner.printf(os.Stdout, "     📁 %s\n", s.ProjectDir)
+		}
 	}
-	var sessions []sessionInfo
 
 	if GlobalOpts.AllProjects {
-		// Discover all projects and their sessions
-		projects, err := DiscoverProjectsForCommand(config, store)
+		projects, err := selectorProjectDirs(cwd)
 		if err != nil {
-			return nil, fmt.Errorf("failed to discover projects: %w", err)
+			return nil, err
 		}
 
-		for _, projectPath := range projects {
-			projSessionStore, err := session.NewSessionStore(projectPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create session store for %s: %v\n", projectPath, err)
-				continue
+		var spinner *selectorSpinner
+		if isTerminal(os.Stdout.Fd()) {
+			spinner = startSelectorSpinner(os.Stdout, fmt.Sprintf("Loading sessions from %d projects...", len(projects)))
+		}
+		loaded := 0
+		err = loadSelectorProjects(ctx, projects, func(p selectorProject) {
+			loaded++
+			if p.err != nil {
+				spinner.printf(os.Stderr, "Warning: %v\n", p.err)
 			}
-
-			projSessions, err := projSessionStore.ListSessions()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to list sessions for %s: %v\n", projectPath, err)
-				continue
+			for _, s := range p.sessions {
+				printSession(spinner, s)
 			}
-
-			for _, s := range projSessions {
-				// Count balls for this session
-				balls, _ := session.LoadBallsBySession([]string{projectPath}, s.ID)
-				sessions = append(sessions, sessionInfo{
-					ID:          s.ID,
-					Description: s.Description,
-					ProjectDir:  projectPath,
-					BallCount:   len(balls),
-				})
+			spinner.setLabel(fmt.Sprintf("Loading sessions... %d/%d projects", loaded, len(projects)))
+		})
+		spinner.stop()
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Println("Cancelled.")
+				return nil, nil
 			}
+			return nil, err
 		}
 	} else {
 		// Local sessions only
-		localSessions, err := sessionStore.ListSessions()
+		sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize session store: %w", err)
+		}
+		localSessions, err := loadProjectSessions(sessionStore, cwd)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list sessions: %w", err)
 		}
-
 		for _, s := range localSessions {
-			// Count balls for this session
-			balls, _ := session.LoadBallsBySession([]string{cwd}, s.ID)
-			sessions = append(sessions, sessionInfo{
-				ID:          s.ID,
-				Description: s.Description,
-				ProjectDir:  cwd,
-				BallCount:   len(balls),
-			})
+			printSession(nil, s)
 		}
 	}
 
@@ -1156,33 +1971,30 @@ func selectSessionForAgent(cwd string) (*SessionSelection, error) {
 		return nil, fmt.Errorf("no sessions found in %s. Create one with: juggle sessions create <id>", scopeMsg)
 	}
 
-	// Display session selector
-	fmt.Println("Select a session to run the agent on:")
-	fmt.Println()
-
-	for i, s := range sessions {
-		prefix := fmt.Sprintf("  %d. %s", i+1, s.ID)
-		ballInfo := fmt.Sprintf("(%d balls)", s.BallCount)
-		if s.Description != "" {
-			fmt.Printf("%s - %s %s\n", prefix, s.Description, ballInfo)
-		} else {
-			fmt.Printf("%s %s\n", prefix, ballInfo)
-		}
-		// Show project directory if viewing all projects
-		if GlobalOpts.AllProjects {
-			fmt.Printf("     📁 %s\n", s.ProjectDir)
-		}
-	}
 	fmt.Println()
 	fmt.Print("Enter number (or 'q' to cancel): ")
 
-	// Read selection
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return nil, fmt.Errorf("failed to read input: %w", err)
+	// Read selection, giving up on Ctrl-C instead of waiting for a line
+	type readResult struct {
+		input string
+		err   error
+	}
+	read := make(chan readResult, 1)
+	go func() {
+		input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		read <- readResult{input, err}
+	}()
+	var input string
+	select {
+	case <-ctx.Done():
+		fmt.Println()
+		return nil, nil
+	case r := <-read:
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", r.err)
+		}
+		input = strings.TrimSpace(r.input)
 	}
-	input = strings.TrimSpace(input)
 
 	// Handle cancel
 	if input == "q" || input == "Q" || input == "" {
@@ -1191,7 +2003,7 @@ func selectSessionForAgent(cwd string) (*SessionSelection, error) {
 
 	// Parse selection
 	var idx int
-	_, err = fmt.Sscanf(input, "%d", &idx)
+	_, err := fmt.Sscanf(input, "%d", &idx)
 	if err != nil || idx < 1 || idx > len(sessions) {
 		return nil, fmt.Errorf("invalid selection: %s", input)
 	}
@@ -1210,93 +2022,63 @@ func selectSessionForAgent(cwd string) (*SessionSelection, error) {
 	}, nil
 }
 
-// SelectSessionForAgentForTest is an exported wrapper for testing
-func SelectSessionForAgentForTest(cwd string) (*SessionSelection, error) {
-	return selectSessionForAgent(cwd)
-}
-
-// SessionInfo holds information about a session for testing/display
-type SessionInfo struct {
-	ID          string
-	Description string
-	ProjectDir  string
-	BallCount   int
-}
-
-// GetSessionsForSelectorForTest returns the list of sessions that would be shown in the selector.
-// This is for testing purposes to verify cross-project session discovery.
-func GetSessionsForSelectorForTest(cwd string) ([]SessionInfo, error) {
-	// Load config to discover projects
+// selectorProjectDirs returns the projects the --all session selector lists
+func selectorProjectDirs(cwd string) ([]string, error) {
 	config, err := LoadConfigForCommand()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-
-	// Create store for current directory
 	store, err := NewStoreForCommand(cwd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store: %w", err)
 	}
-
-	// Get session store for local sessions
-	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	projects, err := DiscoverProjectsForCommand(config, store)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize session store: %w", err)
+		return nil, fmt.Errorf("failed to discover projects: %w", err)
 	}
+	return projects, nil
+}
 
-	var sessions []SessionInfo
-
-	if GlobalOpts.AllProjects {
-		// Discover all projects and their sessions
-		projects, err := DiscoverProjectsForCommand(config, store)
-		if err != nil {
-			return nil, fmt.Errorf("failed to discover projects: %w", err)
-		}
-
-		for _, projectPath := range projects {
-			projSessionStore, err := session.NewSessionStore(projectPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create session store for %s: %v\n", projectPath, err)
-				continue
-			}
+// SelectSessionForAgentForTest is an exported wrapper for testing
+func SelectSessionForAgentForTest(cwd string) (*SessionSelection, error) {
+	return selectSessionForAgent(context.Background(), cwd)
+}
 
-			projSessions, err := projSessionStore.ListSessions()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to list sessions for %s: %v\n", projectPath, err)
-				continue
-			}
+// SessionInfo holds information about a session for testing/display
+type SessionInfo struct {
+	ID          string
+	Description string
+	ProjectDir  string
+	BallCount   int
+}
 
-			for _, s := range projSessions {
-				// Count balls for this session
-				balls, _ := session.LoadBallsBySession([]string{projectPath}, s.ID)
-				sessions = append(sessions, SessionInfo{
-					ID:          s.ID,
-					Description: s.Description,
-					ProjectDir:  projectPath,
-					BallCount:   len(balls),
-				})
-			}
+// GetSessionsForSelectorForTest returns the list of sessions that would be shown in the selector.
+// This is for testing purposes to verify cross-project session discovery.
+func GetSessionsForSelectorForTest(cwd string) ([]SessionInfo, error) {
+	if !GlobalOpts.AllProjects {
+		sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize session store: %w", err)
 		}
-	} else {
-		// Local sessions only
-		localSessions, err := sessionStore.ListSessions()
+		sessions, err := loadProjectSessions(sessionStore, cwd)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list sessions: %w", err)
 		}
-
-		for _, s := range localSessions {
-			// Count balls for this session
-			balls, _ := session.LoadBallsBySession([]string{cwd}, s.ID)
-			sessions = append(sessions, SessionInfo{
-				ID:          s.ID,
-				Description: s.Description,
-				ProjectDir:  cwd,
-				BallCount:   len(balls),
-			})
-		}
+		return sessions, nil
 	}
 
-	return sessions, nil
+	projects, err := selectorProjectDirs(cwd)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []SessionInfo
+	err = loadSelectorProjects(context.Background(), projects, func(p selectorProject) {
+		if p.err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", p.err)
+		}
+		sessions = append(sessions, p.sessions...)
+	})
+	return sessions, err
 }
 
 // BallSelection holds the result of selecting a ball for agent run
@@ -1488,6 +2270,8 @@ func SelectBallForAgentForTest(cwd string, sessionFilter string) (*BallSelection
 }
 
 func runAgentRun(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+
 	// Get current directory
 	cwd, err := GetWorkingDir()
 	if err != nil {
@@ -1497,10 +2281,51 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 	// Track which project directory to use (may change if session is in different project)
 	projectDir := cwd
 
+	if cmd.Flags().Changed("parallel") {
+		if agentParallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1")
+		}
+		if agentBallID != "" || agentPickBall || agentInteractive || agentDaemon || agentMonitor || agentDryRun || agentDebug || agentResume || agentResumeBlocked {
+			return fmt.Errorf("--parallel cannot be combined with --ball, --pick, --interactive, --daemon, --monitor, --dry-run, --debug, --resume or --resume-blocked")
+		}
+	}
+
+	// --json streams events on stdout; everything else moves to stderr so the stream stays parseable
+	var events io.Writer
+	if GlobalOpts.JSONOutput {
+		if agentParallel > 0 || agentPickBall || agentInteractive || agentDaemon || agentMonitor || agentDryRun || agentDebug {
+			return fmt.Errorf("--json cannot be combined with --parallel, --pick, --interactive, --daemon, --monitor, --dry-run or --debug")
+		}
+		stdout := os.Stdout
+		events = stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+
+	if agentAuto {
+		if len(args) > 0 {
+			return fmt.Errorf("--auto picks balls across all projects and takes no session argument")
+		}
+		if agentBallID != "" || agentPickBall || agentParallel > 0 || agentInteractive || agentDaemon || agentMonitor || agentResume || agentResumeBlocked {
+			return fmt.Errorf("--auto cannot be combined with --ball, --pick, --parallel, --interactive, --daemon, --monitor, --resume or --resume-blocked")
+		}
+		return runAutoAgentRun(ctx, cmd, cwd, events)
+	}
+
 	// Handle --monitor flag: start daemon if needed and open monitor TUI
+	if agentRemote != "" && !agentMonitor {
+		return fmt.Errorf("--remote requires --monitor")
+	}
 	if agentMonitor {
+		if agentRemote != "" {
+			var sessionID string
+			if len(args) > 0 {
+				sessionID = args[0]
+			}
+			return monitorRemoteDaemon(ctx, agentRemote, sessionID)
+		}
 		if len(args) == 0 {
-			return fmt.Errorf("--monitor requires a session ID argument")
+			return pickDaemonToMonitor()
 		}
 		sessionID := args[0]
 		storageID := sessionStorageID(sessionID)
@@ -1512,6 +2337,14 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		}
 
 		if !running {
+			if handed, err := handLoopToDaemon(cmd, projectDir, sessionID, agentIterations); err != nil {
+				return err
+			} else if handed {
+				// Give the daemon time to pick up the request and write the PID file
+				time.Sleep(supervisor.InboxPollInterval + time.Second)
+				return launchMonitorTUI(projectDir, sessionID, storageID, true)
+			}
+
 			// No daemon running - start one in the background
 			fmt.Printf("Starting agent daemon for session %s...\n", sessionID)
 
@@ -1532,6 +2365,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 			daemonCmd.Stdout = logFile
 			daemonCmd.Stderr = logFile
 			daemonCmd.Dir = projectDir
+			process.Detach(daemonCmd)
 
 			if err := daemonCmd.Start(); err != nil {
 				logFile.Close()
@@ -1601,7 +2435,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		}
 
 		// Run agent loop for the selected ball
-		_, err = RunAgentLoop(AgentLoopConfig{
+		_, err = RunAgentLoop(ctx, AgentLoopConfig{
 			SessionID:     selected.SessionID,
 			ProjectDir:    projectDir,
 			MaxIterations: 1,
@@ -1632,7 +2466,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("session-id is required in non-interactive mode (use 'all' to target all balls)")
 		}
 		// Show selector
-		selected, err := selectSessionForAgent(cwd)
+		selected, err := selectSessionForAgent(commandContext(cmd), cwd)
 		if err != nil {
 			return err
 		}
@@ -1651,10 +2485,14 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 	if (agentBallID != "" || agentInteractive) && !cmd.Flags().Changed("iterations") {
 		iterations = 1
 	}
-	// --ball implies interactive mode (unless -n was explicitly set for multiple iterations)
-	if agentBallID != "" && !cmd.Flags().Changed("iterations") {
+	// --ball implies interactive mode (unless -n was explicitly set for multiple iterations, or events are streamed)
+	if agentBallID != "" && !cmd.Flags().Changed("iterations") && !agentResume && events == nil {
 		interactive = true
 	}
+	// A resumed run keeps its original iteration limit unless -n is given
+	if agentResume && !cmd.Flags().Changed("iterations") {
+		iterations = 0
+	}
 
 	// Handle --message flag
 	// If flag was provided but value is empty, prompt for interactive input
@@ -1699,6 +2537,9 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		if agentTimeout > 0 {
 			fmt.Printf("Timeout per iteration: %v\n", agentTimeout)
 		}
+		if agentMaxDuration > 0 {
+			fmt.Printf("Max run duration: %v\n", agentMaxDuration)
+		}
 		if agentMaxWait > 0 {
 			fmt.Printf("Max rate limit wait: %v\n", agentMaxWait)
 		}
@@ -1713,7 +2554,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		if agentDryRun {
 			fmt.Println()
 			fmt.Println("(Dry run - agent not started)")
-			return nil
+			return reviewDryRunPrompt(projectDir, prompt)
 		}
 
 		// If debug, continue to run the agent
@@ -1731,10 +2572,18 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 
 	if agentBallID != "" {
 		fmt.Printf("Starting agent for ball: %s (session: %s)\n", agentBallID, sessionID)
+	} else if agentParallel > 0 {
+		fmt.Printf("Starting up to %d parallel agents for session: %s\n", agentParallel, sessionID)
 	} else {
 		fmt.Printf("Starting agent for session: %s\n", sessionID)
 	}
-	fmt.Printf("Max iterations: %d\n", iterations)
+	if agentParallel > 0 {
+		fmt.Printf("Max iterations per ball: %d\n", iterations)
+	} else if iterations == 0 {
+		fmt.Println("Max iterations: from the interrupted run")
+	} else {
+		fmt.Printf("Max iterations: %d\n", iterations)
+	}
 	fmt.Println()
 
 	// Print timeout if specified
@@ -1742,40 +2591,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Timeout per iteration: %v\n", agentTimeout)
 	}
 
-	// Load iteration delay settings (flags override config)
-	var iterDelay time.Duration
-	var delayMinutes, fuzz int
-
-	// Check if --delay flag was explicitly provided
-	if cmd.Flags().Changed("delay") {
-		delayMinutes = agentDelay
-		// Check if --fuzz was also provided, otherwise default to 0
-		if cmd.Flags().Changed("fuzz") {
-			fuzz = agentFuzz
-		}
-	} else {
-		// Load from config
-		var err error
-		delayMinutes, fuzz, err = session.GetGlobalIterationDelayWithOptions(GetConfigOptions())
-		if err != nil {
-			delayMinutes = 0
-			fuzz = 0
-		}
-		// Override fuzz from flag if set
-		if cmd.Flags().Changed("fuzz") {
-			fuzz = agentFuzz
-		}
-	}
-
-	// If delay is 0, skip the delay feature entirely (regardless of fuzz)
-	if delayMinutes > 0 {
-		iterDelay = calculateFuzzyDelay(delayMinutes, fuzz)
-		fmt.Printf("Iteration delay: %v", iterDelay.Round(time.Second))
-		if fuzz > 0 {
-			fmt.Printf(" (base: %dm ± %dm)", delayMinutes, fuzz)
-		}
-		fmt.Println()
-	}
+	iterDelay := resolveIterDelay(cmd)
 
 	// Clear session progress if requested
 	if agentClearProgress {
@@ -1797,21 +2613,26 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	if agentParallel > 0 {
+		return runParallelAgentRun(ctx, cmd, projectDir, sessionID, iterations, message)
+	}
+
 	// Handle daemon mode: fork to background if not already the child process
 	storageID := sessionStorageID(sessionID)
 	if os.Getenv("JUGGLE_DAEMON_CHILD") == "1" {
 		// We are the daemon child process - clear the env var and continue
 		os.Unsetenv("JUGGLE_DAEMON_CHILD")
 
-		// Set up signal handling for graceful shutdown
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
-		go func() {
-			<-sigChan
-			// Send cancel command to self via control file
-			daemon.SendControlCommand(projectDir, storageID, daemon.CmdCancel, "signal")
-		}()
+		// Cancel the run on SIGTERM/SIGINT so the current agent is stopped and teardown runs
+		var stop context.CancelFunc
+		ctx, stop = process.NotifyContext(ctx)
+		defer stop()
 	} else if agentDaemon {
+		// A running juggle daemon takes the loop instead of a process of its own
+		if handed, err := handLoopToDaemon(cmd, projectDir, sessionID, iterations); handed || err != nil {
+			return err
+		}
+
 		// We are the parent - fork a child process and exit
 
 		// Ensure session directory exists for log file
@@ -1831,6 +2652,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		daemonCmd.Stdout = logFile
 		daemonCmd.Stderr = logFile
 		daemonCmd.Dir = projectDir
+		process.Detach(daemonCmd)
 
 		if err := daemonCmd.Start(); err != nil {
 			logFile.Close()
@@ -1917,6 +2739,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		Trust:                agentTrust,
 		Debug:                false, // Debug mode now just shows prompt info, doesn't affect prompt content
 		IterDelay:            iterDelay,
+		QuietHours:           resolveQuietHours(),
 		Timeout:              agentTimeout,
 		MaxWait:              agentMaxWait,
 		BallID:               agentBallID,
@@ -1927,9 +2750,19 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		IgnoreLock:           agentIgnoreLock, // Skip lock acquisition if set
 		Message:              message,         // User message to append to prompt
 		DaemonMode:           agentDaemon,     // Run as daemon with file-based state/control
-	}
-
-	result, err := RunAgentLoop(loopConfig)
+		MaxTokens:            agentMaxTokens,
+		MaxCostUSD:           agentMaxCost,
+		AllowOverlap:         agentAllowOverlap,
+		MaxDuration:          agentMaxDuration,
+		Resume:               agentResume,
+		Review:               agentReview,
+		Preflight:            !agentNoPreflight,
+		PlanFirst:            agentPlanFirst,
+		ResumeBlocked:        agentResumeBlocked,
+		Events:               events,
+	}
+
+	result, err := RunAgentLoop(ctx, loopConfig)
 	if err != nil {
 		return err
 	}
@@ -1942,6 +2775,22 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Iterations: %d\n", result.Iterations)
 	fmt.Printf("Balls: %d complete, %d blocked, %d total\n", result.BallsComplete, result.BallsBlocked, result.BallsTotal)
 	fmt.Printf("Time elapsed: %s\n", elapsed.Round(time.Second))
+	if usage := session.FormatUsage(result.InputTokens, result.OutputTokens, result.CostUSD); usage != "" {
+		fmt.Printf("Usage: %s\n", usage)
+	}
+
+	if failed := gateFailures(result.GateResults); failed != "" {
+		fmt.Printf("Failed gates: %s\n", failed)
+	}
+	if result.ReviewPath != "" {
+		fmt.Printf("Review: %s\n", result.ReviewPath)
+	}
+	if result.ReportPath != "" {
+		fmt.Printf("Report: %s\n", result.ReportPath)
+	}
+	if result.NoOpIterations > 0 {
+		fmt.Printf("No-op iterations: %d\n", result.NoOpIterations)
+	}
 
 	if result.TotalWaitTime > 0 {
 		fmt.Printf("Total wait time: %v\n", result.TotalWaitTime.Round(time.Second))
@@ -1958,6 +2807,8 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Status: TIMEOUT (%s)\n", result.TimeoutMessage)
 	} else if result.RateLimitExceded {
 		fmt.Printf("Status: RATE_LIMIT_EXCEEDED (max-wait: %v)\n", agentMaxWait)
+	} else if result.BudgetExceeded {
+		fmt.Printf("Status: BUDGET_EXCEEDED (%s)\n", result.BudgetMessage)
 	} else {
 		fmt.Println("Status: Max iterations reached")
 	}
@@ -1967,9 +2818,68 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 	outputPath := filepath.Join(projectDir, ".juggle", "sessions", outputStorageID, "last_output.txt")
 	fmt.Printf("\nOutput saved to: %s\n", outputPath)
 
+	// Let wrappers tell a run the rate limit stopped from one that ran its course
+	if result.RateLimitExceded {
+		return rateLimitedError(fmt.Errorf("rate limit wait exceeded --max-wait of %v", agentMaxWait))
+	}
 	return nil
 }
 
+// reviewDryRunPrompt asks a tool-less provider to review the generated prompt
+// after a dry run. Providers with tools are left alone: a dry run must not
+// start them.
+func reviewDryRunPrompt(projectDir, prompt string) error {
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	registerCustomProviders()
+	providerType := provider.Detect(agentProvider, projectProvider, globalProvider)
+
+	agentProv := provider.Get(providerType)
+	if agentProv.SupportsTools() {
+		return nil
+	}
+	if !provider.IsAvailable(providerType) {
+		return providerUnavailableError(providerType)
+	}
+	agent.SetProvider(agentProv)
+
+	fmt.Println()
+	fmt.Println("=== Prompt Review ===")
+	fmt.Println()
+	review := "Review this prompt for an autonomous coding agent before it runs. Point out unclear or conflicting instructions, balls whose acceptance criteria can't be verified, and anything the agent is likely to get wrong. Do not do the work yourself.\n\n" + prompt
+	_, err = runAdvice(review, agentModel, projectDir)
+	return err
+}
+
+// startDaemonAPI serves the daemon API, on daemon_api_listen with the stored
+// token when configured. If that address is taken, e.g. by another daemon
+// process, the API listens on a free port as usual.
+func startDaemonAPI() (addr, token string, err error) {
+	opts := GetConfigOptions()
+	config, err := session.LoadConfigWithOptions(opts)
+	if err != nil || config.DaemonAPIListen == "" {
+		return daemon.StartAPI()
+	}
+	tokenPath, err := session.DaemonAPITokenPath(opts)
+	if err == nil {
+		token, err = daemon.LoadOrCreateAPIToken(tokenPath)
+	}
+	if err == nil {
+		addr, token, err = daemon.StartAPIWithOptions(daemon.APIOptions{Addr: config.DaemonAPIListen, Token: token})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: daemon API can't listen on %s, using a free port: %v\n", config.DaemonAPIListen, err)
+		return daemon.StartAPI()
+	}
+	return addr, token, nil
+}
+
 // launchMonitorTUI launches the TUI in agent monitor mode
 func launchMonitorTUI(projectDir, sessionID, storageID string, daemonRunning bool) error {
 	// Load config
@@ -2005,6 +2915,9 @@ func launchMonitorTUI(projectDir, sessionID, storageID string, daemonRunning boo
 		w.Start()
 	}
 
+	// The provider switch offers custom providers too
+	registerCustomProviders()
+
 	// Create model in monitor mode
 	model := tui.InitialMonitorModel(store, sessionStore, config, true, w, storageID, daemonRunning)
 
@@ -2067,14 +2980,20 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 		}
 	}
 
-	// Filter out complete and blocked balls by default (they clutter the context for no gain)
+	// Filter out complete and blocked balls by default (they clutter the context for no gain),
+	// balls still waiting on a dependency in the session, and balls a person has claimed
 	// Exception: when a specific ball is requested, allow it even if complete/blocked
 	if ballID == "" {
+		states := session.BallStates(balls)
 		filteredBalls := make([]*session.Ball, 0, len(balls))
 		for _, ball := range balls {
-			if ball.State != session.StateComplete && ball.State != session.StateResearched && ball.State != session.StateBlocked {
-				filteredBalls = append(filteredBalls, ball)
+			if ball.State == session.StateComplete || ball.State == session.StateResearched || ball.State == session.StateBlocked {
+				continue
+			}
+			if len(session.UnmetDependencies(ball, states)) > 0 || ball.IsClaimed() {
+				continue
 			}
+			filteredBalls = append(filteredBalls, ball)
 		}
 		balls = filteredBalls
 	}
@@ -2084,7 +3003,7 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 	if ballID != "" {
 		matches := session.ResolveBallByPrefix(balls, ballID)
 		if len(matches) == 0 {
-			return "", fmt.Errorf("ball %s not found in session %s", ballID, sessionID)
+			return "", fmt.Errorf("%w in session %s", session.NewBallNotFoundError(ballID), sessionID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
@@ -2114,6 +3033,7 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 }
 
 // countWorkableBalls returns counts of balls the agent can work on (pending/in_progress) vs blocked
+// Balls waiting on dependencies in the session, or claimed by a person, count as blocked
 // This is used for pre-loop validation to exit early when there's no actionable work
 // Balls in complete/researched states are excluded (same as agent export)
 // If ballID is specified, only counts that specific ball
@@ -2147,44 +3067,55 @@ func countWorkableBalls(projectDir, sessionID, ballID string, interactive bool)
 	// "all" is a meta-session that means "all balls in repo"
 	isAllSession := sessionID == "all"
 
-	// Count balls with session tag (or all balls if using "all" meta-session)
+	// Collect balls with session tag (or all balls if using "all" meta-session)
+	var sessionBalls []*session.Ball
 	for _, ball := range allBalls {
-		var matchesSession bool
 		if isAllSession {
-			matchesSession = true // Include all balls
-		} else {
-			for _, tag := range ball.Tags {
-				if tag == sessionID {
-					matchesSession = true
-					break
-				}
+			sessionBalls = append(sessionBalls, ball)
+			continue
+		}
+		for _, tag := range ball.Tags {
+			if tag == sessionID {
+				sessionBalls = append(sessionBalls, ball)
+				break
 			}
 		}
+	}
+	states := session.BallStates(sessionBalls)
 
-		if matchesSession {
-			// If filtering by specific ball, skip others
-			if ballID != "" && ball.ID != ballID && ball.ShortID() != ballID {
-				continue
-			}
+	for _, ball := range sessionBalls {
+		// If filtering by specific ball, skip others
+		if ballID != "" && ball.ID != ballID && ball.ShortID() != ballID {
+			continue
+		}
 
-			// Skip states that are excluded from agent exports
-			// (complete, researched are not shown to the agent)
-			switch ball.State {
-			case session.StateComplete, session.StateResearched:
-				continue
-			case session.StatePending, session.StateInProgress:
+		// Skip states that are excluded from agent exports
+		// (complete, researched are not shown to the agent)
+		switch ball.State {
+		case session.StateComplete, session.StateResearched:
+			continue
+		case session.StatePending, session.StateInProgress:
+			// A ball waiting on dependencies only becomes workable once they're done,
+			// so when nothing else is workable its chain ends in a blocked ball.
+			// An explicitly targeted ball is always workable.
+			// A claimed ball waits for its person, even when targeted, unless they're here.
+			if ballID == "" && len(session.UnmetDependencies(ball, states)) > 0 {
+				blocked++
+			} else if ball.IsClaimed() && !interactive {
+				blocked++
+			} else {
 				workable++
-				total++
-			case session.StateBlocked:
-				// If user is running interactively or explicitly targeted this ball,
-				// treat it as workable (they ARE the human intervention)
-				if interactive || (ballID != "" && (ball.ID == ballID || ball.ShortID() == ballID)) {
-					workable++
-				} else {
-					blocked++
-				}
-				total++
 			}
+			total++
+		case session.StateBlocked:
+			// If user is running interactively or explicitly targeted this ball,
+			// treat it as workable (they ARE the human intervention)
+			if interactive || (ballID != "" && (ball.ID == ballID || ball.ShortID() == ballID)) {
+				workable++
+			} else {
+				blocked++
+			}
+			total++
 		}
 	}
 
@@ -2266,6 +3197,29 @@ func logTimeoutToProgress(projectDir, sessionID, message string) {
 	_ = sessionStore.AppendProgress(sessionID, entry)
 }
 
+// isolateWork describes the working copy, moves its changes into their own
+// revision and resets to targetRevision (empty = the backend's default), so
+// stopped work is kept for review
+func isolateWork(backend vcs.VCS, projectDir, targetRevision, description string) {
+	if err := backend.DescribeWorkingCopy(projectDir, description); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to describe working copy: %v\n", err)
+	}
+
+	isolatedRev, err := backend.IsolateAndReset(projectDir, targetRevision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to isolate work: %v\n", err)
+		return
+	}
+	if isolatedRev != "" {
+		fmt.Printf("✓ Isolated work in revision: %s\n", isolatedRev)
+
+		// Verify working copy is clean after reset
+		if stillDirty, checkErr := backend.HasChanges(projectDir); checkErr == nil && stillDirty {
+			fmt.Fprintf(os.Stderr, "Warning: working copy still has changes after reset\n")
+		}
+	}
+}
+
 // getProgressLineCount returns the number of lines in the session's progress file.
 // Used to detect if progress was updated during an iteration.
 func getProgressLineCount(store *session.SessionStore, sessionID string) int {
@@ -2290,7 +3244,8 @@ func GetProgressLineCountForTest(store *session.SessionStore, sessionID string)
 	return getProgressLineCount(store, sessionID)
 }
 
-// saveAgentHistory saves the agent run history to the history file
+// saveAgentHistory saves the agent run history to the history file and
+// writes the run's report
 func saveAgentHistory(config AgentLoopConfig, result *AgentResult, outputPath string) {
 	historyStore, err := session.NewAgentHistoryStore(config.ProjectDir)
 	if err != nil {
@@ -2310,6 +3265,8 @@ func saveAgentHistory(config AgentLoopConfig, result *AgentResult, outputPath st
 		record.SetTimeout(result.Iterations, result.TimeoutMessage, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
 	} else if result.RateLimitExceded {
 		record.SetRateLimitExceeded(result.Iterations, result.TotalWaitTime, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
+	} else if result.BudgetExceeded {
+		record.SetBudgetExceeded(result.Iterations, result.BudgetMessage, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
 	} else {
 		// Max iterations reached
 		record.SetMaxIterations(result.Iterations, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
@@ -2318,6 +3275,15 @@ func saveAgentHistory(config AgentLoopConfig, result *AgentResult, outputPath st
 	// Preserve total wait time and ended time from result
 	record.TotalWaitTime = result.TotalWaitTime
 	record.EndedAt = result.EndedAt
+	record.InputTokens = result.InputTokens
+	record.OutputTokens = result.OutputTokens
+	record.CostUSD = result.CostUSD
+	record.IterationTimings = result.IterationTimings
+	if result.Iterations > 0 {
+		record.StartRevision, record.EndRevision = runRevisions(config.ProjectDir, sessionStorageID(config.SessionID))
+	}
+	result.ReportPath = writeRunReport(config, result, record)
+	record.ReportFile = result.ReportPath
 
 	_ = historyStore.AppendRecord(record)
 }
@@ -2383,15 +3349,19 @@ func runAgentRefine(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
 	}
+	registerCustomProviders()
+	registerProviderBinaries(cwd)
 	providerType := provider.Detect(refineProvider, projectProvider, globalProvider)
 
 	// Verify provider binary is available
 	if !provider.IsAvailable(providerType) {
-		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
-			providerType, provider.BinaryName(providerType))
+		return providerUnavailableError(providerType)
 	}
 
 	agentProv := provider.Get(providerType)
+	if agentProv.SupportsTools() && !agentProv.SupportsInteractive() {
+		return fmt.Errorf("agent provider %q does not support interactive mode", providerType)
+	}
 	agent.SetProvider(agentProv)
 
 	// Configure model overrides
@@ -2406,6 +3376,16 @@ func runAgentRefine(cmd *cobra.Command, args []string) error {
 	modelOverrides := session.MergeModelOverrides(globalOverrides, projectOverrides)
 	agent.SetModelOverrides(modelOverrides)
 
+	// Tool-less providers can't explore the repo, so ask once for advice
+	if !agentProv.SupportsTools() {
+		output, err := runAdvice(prompt, refineModel, cwd)
+		if err != nil {
+			return fmt.Errorf("refinement failed: %w", err)
+		}
+		applySplitsToBalls(parseSplits(output), balls)
+		return nil
+	}
+
 	// Run agent in interactive + plan mode
 	opts := agent.RunOptions{
 		Prompt:     prompt,
@@ -2423,6 +3403,40 @@ func runAgentRefine(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runAdvice sends prompt to the configured tool-less provider in one headless
+// request, prints the reply and returns it
+func runAdvice(prompt, model, workingDir string) (string, error) {
+	fmt.Println("🔎 Advice mode: the provider has no tools, so it can only review the prompt and suggest changes.")
+	fmt.Println()
+
+	result, err := agent.DefaultRunner.Run(agent.RunOptions{
+		Prompt:     prompt,
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      model,
+		WorkingDir: workingDir,
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RateLimited {
+		if result.RetryAfter > 0 {
+			return "", rateLimitedError(fmt.Errorf("rate limited, retry after %v", result.RetryAfter))
+		}
+		return "", rateLimitedError(fmt.Errorf("rate limited: %s", result.Output))
+	}
+
+	fmt.Println(result.Output)
+	if result.InputTokens > 0 || result.OutputTokens > 0 {
+		fmt.Println()
+		fmt.Printf("Tokens: %d in, %d out\n", result.InputTokens, result.OutputTokens)
+	}
+	return result.Output, nil
+}
+
 // loadBallsForRefine loads balls based on scope:
 // - If sessionID provided, filter by session tag
 // - If GlobalOpts.AllProjects, load from all discovered projects
@@ -2487,6 +3501,24 @@ func loadBallsForRefine(projectDir, sessionID string) ([]*session.Ball, error) {
 func generateRefinePrompt(projectDir, sessionID string, balls []*session.Ball, message string) (string, error) {
 	var buf strings.Builder
 
+	writeRefineContext(&buf, projectDir, sessionID, balls)
+
+	// Write instructions section with refinement template
+	writeRefineInstructions(&buf, agent.GetRefinePromptTemplate())
+
+	// Append user message if provided
+	if message != "" {
+		buf.WriteString("\n<user-message>\n")
+		buf.WriteString(message)
+		buf.WriteString("\n</user-message>\n")
+	}
+
+	return buf.String(), nil
+}
+
+// writeRefineContext writes the session context and balls sections shared by
+// the refine and review prompts
+func writeRefineContext(buf *strings.Builder, projectDir, sessionID string, balls []*session.Ball) {
 	// Write context section with session info if available
 	buf.WriteString("<context>\n")
 	if sessionID != "" {
@@ -2504,6 +3536,10 @@ func generateRefinePrompt(projectDir, sessionID string, balls []*session.Ball, m
 						buf.WriteString("\n")
 					}
 				}
+				if juggleSession.DocsBriefing != nil && juggleSession.Context == "" {
+					buf.WriteString("\n")
+				}
+				writeDocsBriefing(buf, juggleSession)
 			}
 		}
 	}
@@ -2515,26 +3551,19 @@ func generateRefinePrompt(projectDir, sessionID string, balls []*session.Ball, m
 		if i > 0 {
 			buf.WriteString("\n")
 		}
-		writeBallForRefine(&buf, ball)
+		writeBallForRefine(buf, ball)
 	}
 	buf.WriteString("</balls>\n\n")
+}
 
-	// Write instructions section with refinement template
+// writeRefineInstructions writes a prompt template as the instructions section
+func writeRefineInstructions(buf *strings.Builder, template string) {
 	buf.WriteString("<instructions>\n")
-	buf.WriteString(agent.GetRefinePromptTemplate())
-	if !strings.HasSuffix(agent.GetRefinePromptTemplate(), "\n") {
+	buf.WriteString(template)
+	if !strings.HasSuffix(template, "\n") {
 		buf.WriteString("\n")
 	}
 	buf.WriteString("</instructions>\n")
-
-	// Append user message if provided
-	if message != "" {
-		buf.WriteString("\n<user-message>\n")
-		buf.WriteString(message)
-		buf.WriteString("\n</user-message>\n")
-	}
-
-	return buf.String(), nil
 }
 
 // LoadBallsForRefineForTest is an exported wrapper for testing
@@ -2650,8 +3679,55 @@ type ModelSelection struct {
 // 4. Choose based on ball model preferences (prioritize matching balls)
 // 5. Default to "opus" (largest/most capable model)
 //
+// The selected model is then validated against the current provider's ListModels.
+//
 // The function returns the model to use and reason for selection.
 func selectModelForIteration(config AgentLoopConfig, balls []*session.Ball, defaultSessionModel session.ModelSize) *ModelSelection {
+	return selectModelForProvider(config, agent.GetProvider(), balls, defaultSessionModel)
+}
+
+// selectModelForProvider is selectModelForIteration for a loop's own
+// provider. A nil provider skips validation.
+func selectModelForProvider(config AgentLoopConfig, p provider.Provider, balls []*session.Ball, defaultSessionModel session.ModelSize) *ModelSelection {
+	selection := preferredModelForIteration(config, balls, defaultSessionModel)
+	if p != nil {
+		validateModelForProvider(selection, p)
+	}
+	return selection
+}
+
+// validateModelForProvider replaces a model the provider doesn't accept with a
+// supported one, preferring larger canonical models. Providers that accept
+// arbitrary models (ListModels returns nil) are left alone.
+func validateModelForProvider(selection *ModelSelection, p provider.Provider) {
+	models := p.ListModels()
+	if len(models) == 0 || provider.IsModelSupported(p, selection.Model) {
+		return
+	}
+
+	fallback := models[0]
+	for _, canonical := range []string{"opus", "sonnet", "haiku"} {
+		if provider.IsModelSupported(p, canonical) {
+			fallback = canonical
+			break
+		}
+	}
+
+	selection.Reason = fmt.Sprintf("%s not supported by %s, using %s", selection.Model, p.Type(), fallback)
+	selection.Model = fallback
+}
+
+// iterationTimeout returns the timeout for an iteration: the ball's override
+// when the iteration targets a single ball that has one, else the run's
+func iterationTimeout(runTimeout time.Duration, ball *session.Ball) time.Duration {
+	if ball != nil && ball.TimeoutMinutes > 0 {
+		return ball.Timeout()
+	}
+	return runTimeout
+}
+
+// preferredModelForIteration picks a model from the flag, ball and session preferences
+func preferredModelForIteration(config AgentLoopConfig, balls []*session.Ball, defaultSessionModel session.ModelSize) *ModelSelection {
 	// If model explicitly provided via --model flag, use it
 	if config.Model != "" {
 		return &ModelSelection{
@@ -2678,31 +3754,39 @@ func selectModelForIteration(config AgentLoopConfig, balls []*session.Ball, defa
 		}
 	}
 
-	// Count balls by model preference
-	modelCounts := countBallsByModel(activeBalls)
-
-	// If session has a default model and there are balls without explicit preference,
-	// count those as preferring the session default
-	if defaultSessionModel != "" && defaultSessionModel != session.ModelSizeBlank {
-		blankCount := modelCounts[""]
-		sessionModel := mapModelSizeToString(defaultSessionModel)
-		modelCounts[sessionModel] += blankCount
-		delete(modelCounts, "")
+	// Balls that kept failing at a smaller model move the iteration up
+	if ball := mostEscalatedBall(activeBalls); ball != nil {
+		return &ModelSelection{
+			Model:      ball.EscalatedModel,
+			Reason:     fmt.Sprintf("ball %s escalated after repeated failures", ball.ShortID()),
+			BallsCount: 1,
+		}
 	}
 
-	// Find the model with most balls (prefer larger models on tie)
+	// Count balls by model preference. Balls without an explicit preference
+	// count towards their estimate, the session default, or else their kind's default
+	modelCounts, modelWork := countBallsByModel(activeBalls, defaultSessionModel)
+
+	// Find the model with the most estimated work (prefer larger models on tie).
+	// Balls without an estimate count as one iteration, so this is a plain
+	// ball count until juggle agent estimate has run.
 	selectedModel := "opus"
 	maxCount := 0
+	maxWork := 0
 	selectedReason := "default (no model preferences specified)"
 
 	// Check in order of preference (larger models first for ties)
 	modelPriority := []string{"opus", "sonnet", "haiku"}
 	for _, model := range modelPriority {
-		count := modelCounts[model]
-		if count > maxCount {
-			maxCount = count
+		work := modelWork[model]
+		if work > maxWork {
+			maxWork = work
+			maxCount = modelCounts[model]
 			selectedModel = model
-			selectedReason = fmt.Sprintf("%d ball(s) prefer %s model", count, model)
+			selectedReason = fmt.Sprintf("%d ball(s) prefer %s model", maxCount, model)
+			if work != maxCount {
+				selectedReason += fmt.Sprintf(" (~%d estimated iterations)", work)
+			}
 		}
 	}
 
@@ -2735,14 +3819,18 @@ func filterActiveBalls(balls []*session.Ball) []*session.Ball {
 	return active
 }
 
-// countBallsByModel counts how many balls prefer each model size
-func countBallsByModel(balls []*session.Ball) map[string]int {
-	counts := make(map[string]int)
+// countBallsByModel counts how many balls prefer each model size, and the
+// iterations they are estimated to take. Balls with no preference at all are
+// counted under "".
+func countBallsByModel(balls []*session.Ball, sessionDefaultModel session.ModelSize) (counts, work map[string]int) {
+	counts = make(map[string]int)
+	work = make(map[string]int)
 	for _, ball := range balls {
-		model := mapModelSizeToString(ball.ModelSize)
+		model := mapModelSizeToString(ball.PreferredModelSize(sessionDefaultModel))
 		counts[model]++
+		work[model] += ball.EstimatedIterations()
 	}
-	return counts
+	return counts, work
 }
 
 // mapModelSizeToString converts ModelSize to the string used by Claude CLI
@@ -2769,11 +3857,8 @@ func prioritizeBallsByModel(balls []*session.Ball, currentModel string, sessionD
 
 	// Determine which ModelSize values match the current model
 	matchesModel := func(ball *session.Ball) bool {
-		ballModel := ball.ModelSize
-		// If ball has no preference, use session default
-		if ballModel == "" || ballModel == session.ModelSizeBlank {
-			ballModel = sessionDefaultModel
-		}
+		// If ball has no preference, use session default, then its kind's default
+		ballModel := ball.PreferredModelSize(sessionDefaultModel)
 		// Convert to string and compare
 		return mapModelSizeToString(ballModel) == currentModel || ballModel == session.ModelSizeBlank
 	}
@@ -2946,15 +4031,19 @@ func runAgentSetupRepo(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
 	}
+	registerCustomProviders()
+	registerProviderBinaries(cwd)
 	providerType := provider.Detect("", projectProvider, globalProvider)
 
 	// Verify provider binary is available
 	if !provider.IsAvailable(providerType) {
-		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
-			providerType, provider.BinaryName(providerType))
+		return providerUnavailableError(providerType)
 	}
 
 	agentProv := provider.Get(providerType)
+	if !agentProv.SupportsInteractive() {
+		return fmt.Errorf("agent provider %q does not support interactive mode", providerType)
+	}
 	agent.SetProvider(agentProv)
 
 	prompt := "Use the /sandbox-setup skill to configure this repository for autonomous agent operations. Analyze the codebase, ask me questions about my workflow, and update .claude/settings.json with appropriate permissions."
@@ -2994,7 +4083,8 @@ func FilterActiveBallsForTest(balls []*session.Ball) []*session.Ball {
 
 // CountBallsByModelForTest is an exported wrapper for testing
 func CountBallsByModelForTest(balls []*session.Ball) map[string]int {
-	return countBallsByModel(balls)
+	counts, _ := countBallsByModel(balls, session.ModelSizeBlank)
+	return counts
 }
 
 // loadBallsForModelSelection loads balls for model selection purposes.
@@ -3045,14 +4135,20 @@ func loadBallsForModelSelection(projectDir, sessionID, ballID string) ([]*sessio
 		}
 	}
 
-	// Filter out complete and blocked balls by default (they clutter the context for no gain)
+	// Filter out complete and blocked balls by default (they clutter the context for no gain),
+	// balls still waiting on a dependency in the session, and balls a person has claimed
 	// Exception: when a specific ball is requested, allow it even if complete/blocked
 	if ballID == "" {
+		states := session.BallStates(balls)
 		filteredBalls := make([]*session.Ball, 0, len(balls))
 		for _, ball := range balls {
-			if ball.State != session.StateComplete && ball.State != session.StateResearched && ball.State != session.StateBlocked {
-				filteredBalls = append(filteredBalls, ball)
+			if ball.State == session.StateComplete || ball.State == session.StateResearched || ball.State == session.StateBlocked {
+				continue
+			}
+			if len(session.UnmetDependencies(ball, states)) > 0 || ball.IsClaimed() {
+				continue
 			}
+			filteredBalls = append(filteredBalls, ball)
 		}
 		balls = filteredBalls
 	}
@@ -3061,7 +4157,7 @@ func loadBallsForModelSelection(projectDir, sessionID, ballID string) ([]*sessio
 	if ballID != "" {
 		matches := session.ResolveBallByPrefix(balls, ballID)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball %s not found in session %s", ballID, sessionID)
+			return nil, fmt.Errorf("%w in session %s", session.NewBallNotFoundError(ballID), sessionID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
@@ -3093,12 +4189,7 @@ type CommitResult struct {
 // This is called by juggle after the agent signals completion.
 // Returns nil if there are no changes to commit.
 func performVCSCommit(projectDir, commitMessage string) (*CommitResult, error) {
-	// Load VCS settings
-	globalVCS, _ := session.GetGlobalVCSWithOptions(GetConfigOptions())
-	projectVCS, _ := session.GetProjectVCS(projectDir)
-
-	// Get the appropriate backend
-	backend := vcs.GetBackendForProject(projectDir, vcs.VCSType(projectVCS), vcs.VCSType(globalVCS))
+	backend := vcsBackendForProject(projectDir)
 
 	// Perform commit
 	vcsResult, err := backend.Commit(projectDir, commitMessage)
@@ -3115,6 +4206,13 @@ func performVCSCommit(projectDir, commitMessage string) (*CommitResult, error) {
 	}, nil
 }
 
+// vcsBackendForProject returns the VCS backend for a project using config resolution
+func vcsBackendForProject(projectDir string) vcs.VCS {
+	globalVCS, _ := session.GetGlobalVCSWithOptions(GetConfigOptions())
+	projectVCS, _ := session.GetProjectVCS(projectDir)
+	return vcs.GetBackendForProject(projectDir, vcs.VCSType(projectVCS), vcs.VCSType(globalVCS))
+}
+
 // performJJCommit is kept for backward compatibility - delegates to performVCSCommit
 func performJJCommit(projectDir, commitMessage string) (*CommitResult, error) {
 	return performVCSCommit(projectDir, commitMessage)
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var attachTrust bool
+
+var agentAttachCmd = &cobra.Command{
+	Use:   "attach <session>",
+	Short: "Take over a running agent daemon interactively",
+	Long: `Pause a daemon's headless loop after its current iteration and open an
+interactive agent session on the same ball, seeded with the agent prompt and
+session progress. When you exit the session the headless loop resumes.
+
+The monitor TUI does the same with 't'. Press Ctrl+C while waiting to give up
+the takeover and leave the loop running.
+
+Examples:
+  juggle agent attach my-feature
+  juggle agent attach my-feature --trust`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentAttach,
+}
+
+func init() {
+	agentAttachCmd.Flags().BoolVar(&attachTrust, "trust", false, "Run the interactive session with full permissions")
+	agentCmd.AddCommand(agentAttachCmd)
+}
+
+// takeoverPrompt tells the interactive agent how it got here
+const takeoverPrompt = "The user has taken over a headless agent run between iterations. Work on the current ball together with them; the headless loop resumes from the session progress and ball state when this session ends, so keep both up to date."
+
+func runAgentAttach(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	storageID := sessionStorageID(sessionID)
+	projectDir, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	running, _, err := daemon.IsRunning(projectDir, storageID)
+	if err != nil {
+		return fmt.Errorf("failed to check daemon: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("no agent daemon is running for session %s (start one with: juggle agent run %s --daemon)", sessionID, sessionID)
+	}
+
+	requestedAt := time.Now()
+	if err := daemon.SendControlCommand(projectDir, storageID, daemon.CmdTakeover, ""); err != nil {
+		return fmt.Errorf("failed to request takeover: %w", err)
+	}
+	fmt.Println("⏳ Waiting for the current iteration to finish... (Ctrl+C to give up)")
+
+	// Ctrl+C gives up while waiting; during the session it belongs to the agent
+	// CLI, and this process must survive it to resume the loop
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	state, err := waitForTakeoverState(ctx, projectDir, storageID, requestedAt)
+	if err != nil {
+		withdrawTakeover(projectDir, storageID)
+		return err
+	}
+	defer func() {
+		if err := daemon.SendControlCommand(projectDir, storageID, daemon.CmdResume, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to resume the daemon: %v (resume it from the monitor)\n", err)
+			return
+		}
+		fmt.Println("▶️  Headless loop resumed")
+	}()
+
+	if err := configureInteractiveProvider(projectDir, state.Provider); err != nil {
+		return err
+	}
+	prompt, err := generateAgentPrompt(projectDir, sessionID, false, state.CurrentBallID, takeoverPrompt)
+	if err != nil {
+		return fmt.Errorf("failed to generate prompt: %w", err)
+	}
+
+	ballInfo := ""
+	if state.CurrentBallID != "" {
+		ballInfo = fmt.Sprintf(" on %s", state.CurrentBallID)
+	}
+	fmt.Printf("🙋 Loop paused after iteration %d; starting interactive session%s\n\n", state.Iteration, ballInfo)
+
+	opts := agent.RunOptions{
+		Prompt:     prompt,
+		Mode:       agent.ModeInteractive,
+		Permission: agent.PermissionAcceptEdits,
+		Model:      state.Model,
+		WorkingDir: projectDir,
+		Env:        []string{"JUGGLE_SESSION_ID=" + sessionID, "JUGGLE_BALL_ID=" + state.CurrentBallID},
+	}
+	if attachTrust {
+		opts.Permission = agent.PermissionBypass
+	}
+	if _, err := agent.DefaultRunner.Run(opts); err != nil {
+		return fmt.Errorf("interactive session failed: %w", err)
+	}
+	return nil
+}
+
+// waitForTakeoverState polls the daemon state until the loop reports it is
+// held for takeover, the daemon exits, or ctx is cancelled
+func waitForTakeoverState(ctx context.Context, projectDir, storageID string, requestedAt time.Time) (*daemon.State, error) {
+	for {
+		state, err := daemon.ReadStateFile(projectDir, storageID)
+		if err == nil && state.Status == daemon.StatusTakeover && !state.LastUpdated.Before(requestedAt) {
+			return state, nil
+		}
+		if running, _, _ := daemon.IsRunning(projectDir, storageID); !running {
+			return nil, fmt.Errorf("the agent run ended before it could be taken over")
+		}
+		if !sleepContext(ctx, 500*time.Millisecond) {
+			return nil, fmt.Errorf("takeover cancelled; the loop keeps running")
+		}
+	}
+}
+
+// withdrawTakeover takes back a takeover request the daemon hasn't seen, or
+// resumes the loop if it already paused for it
+func withdrawTakeover(projectDir, storageID string) {
+	if ctrl, _ := daemon.ReadControlCommand(projectDir, storageID); ctrl != nil && ctrl.Command == daemon.CmdTakeover {
+		return
+	}
+	_ = daemon.SendControlCommand(projectDir, storageID, daemon.CmdResume, "")
+}
+
+// configureInteractiveProvider selects the agent provider the daemon runs
+// with, falling back to the configured one, for an interactive session
+func configureInteractiveProvider(projectDir, preferred string) error {
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	registerCustomProviders()
+	registerProviderBinaries(projectDir)
+	providerType := provider.Detect(preferred, projectProvider, globalProvider)
+
+	if !provider.IsAvailable(providerType) {
+		return providerUnavailableError(providerType)
+	}
+	agentProv := provider.Get(providerType)
+	if !agentProv.SupportsInteractive() {
+		return fmt.Errorf("agent provider %q does not support interactive mode", providerType)
+	}
+	agent.SetProvider(agentProv)
+	return nil
+}
+
+// waitForTakeover holds the daemon loop between iterations while the user
+// works interactively via juggle agent attach. Returns false if the run was
+// cancelled instead of resumed.
+func waitForTakeover(ctx context.Context, config AgentLoopConfig, control *daemon.ControlReceiver, storageID string, iteration int, startTime time.Time, providerType string) bool {
+	// Keep the last iteration's ball so attach can pick it up
+	state, err := daemon.ReadStateFile(config.ProjectDir, storageID)
+	if err != nil {
+		state = &daemon.State{
+			Iteration:     iteration - 1,
+			MaxIterations: config.MaxIterations,
+			Model:         config.Model,
+			Provider:      providerType,
+			StartedAt:     startTime,
+		}
+	}
+	state.Running = true
+	state.Paused = true
+	state.Status = daemon.StatusTakeover
+	_ = daemon.WriteStateFile(config.ProjectDir, storageID, state)
+
+	fmt.Println("🙋 Paused for interactive takeover")
+	logTakeoverToProgress(config.ProjectDir, storageID, "Headless loop paused for an interactive session")
+
+	for {
+		ctrl := control.Wait(ctx)
+		if ctrl == nil {
+			return false
+		}
+		switch ctrl.Command {
+		case daemon.CmdResume:
+			fmt.Println("▶️  Resumed after takeover")
+			logTakeoverToProgress(config.ProjectDir, storageID, "Interactive session ended, headless loop resumed")
+			return true
+		case daemon.CmdCancel:
+			return false
+		}
+	}
+}
+
+// logTakeoverToProgress logs an interactive takeover to the session's progress file
+func logTakeoverToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[TAKEOVER] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// retryBudgetReason is the blocked reason of a ball that used up its attempts
+const retryBudgetReason = "exceeded retry budget"
+
+// recordBallAttempts counts the iteration as an attempt on each ball it worked
+// on, and blocks the balls that have now used up max_ball_attempts so the loop
+// stops retrying them. It returns the IDs of the balls it blocked.
+func recordBallAttempts(projectDir, sessionID, storageID, ballID string, snap *session.IterationSnapshot) []string {
+	maxAttempts, err := session.GetProjectMaxBallAttempts(projectDir)
+	if err != nil {
+		maxAttempts = 0
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil
+	}
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return nil
+	}
+
+	var blocked []string
+	for _, ball := range attemptedBalls(balls, ballID, snap) {
+		ball.IncrementAttemptCount()
+		if exceedsRetryBudget(ball, maxAttempts) {
+			if err := ball.SetBlocked(retryBudgetReason); err == nil {
+				blocked = append(blocked, ball.ID)
+			}
+		}
+		if err := store.UpdateBall(ball); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record attempt on %s: %v\n", ball.ID, err)
+		}
+	}
+
+	for _, id := range blocked {
+		fmt.Printf("⛔ %s used %d attempts, blocked: %s\n", id, maxAttempts, retryBudgetReason)
+		logRetryBudgetToProgress(projectDir, storageID, id, maxAttempts)
+	}
+	return blocked
+}
+
+// attemptedBalls returns the balls an iteration worked on: the targeted ball
+// when the run is limited to one, otherwise those whose state changed during
+// the iteration or that are still in progress after it
+func attemptedBalls(balls []*session.Ball, ballID string, snap *session.IterationSnapshot) []*session.Ball {
+	if ballID != "" {
+		for _, ball := range balls {
+			if ball.ID == ballID {
+				return []*session.Ball{ball}
+			}
+		}
+		return nil
+	}
+
+	before := make(map[string]session.BallState)
+	if snap != nil {
+		for _, b := range snap.Balls {
+			before[b.ID] = b.State
+		}
+	}
+
+	var attempted []*session.Ball
+	for _, ball := range balls {
+		state, known := before[ball.ID]
+		if ball.State == session.StateInProgress || (known && state != ball.State) {
+			attempted = append(attempted, ball)
+		}
+	}
+	return attempted
+}
+
+// exceedsRetryBudget reports whether a ball still being worked on has used up
+// its attempts (a budget of 0 or less is unlimited)
+func exceedsRetryBudget(ball *session.Ball, maxAttempts int) bool {
+	if maxAttempts <= 0 || ball.AttemptCount < maxAttempts {
+		return false
+	}
+	return ball.State == session.StatePending || ball.State == session.StateInProgress
+}
+
+// logRetryBudgetToProgress logs a ball blocked by its retry budget to the session's progress file
+func logRetryBudgetToProgress(projectDir, sessionID, ballID string, maxAttempts int) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[RETRY_BUDGET] %s blocked after %d attempts: %s", ballID, maxAttempts, retryBudgetReason)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
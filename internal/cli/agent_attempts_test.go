@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAttemptedBalls(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "working", State: session.StateInProgress},
+		{ID: "finished", State: session.StateComplete},
+		{ID: "untouched", State: session.StatePending},
+		{ID: "new", State: session.StatePending},
+	}
+	snap := &session.IterationSnapshot{Balls: []session.BallStateSnapshot{
+		{ID: "working", State: session.StateInProgress},
+		{ID: "finished", State: session.StateInProgress},
+		{ID: "untouched", State: session.StatePending},
+	}}
+
+	var ids []string
+	for _, ball := range attemptedBalls(balls, "", snap) {
+		ids = append(ids, ball.ID)
+	}
+	if len(ids) != 2 || ids[0] != "working" || ids[1] != "finished" {
+		t.Errorf("Expected working and finished, got %v", ids)
+	}
+
+	targeted := attemptedBalls(balls, "untouched", snap)
+	if len(targeted) != 1 || targeted[0].ID != "untouched" {
+		t.Errorf("Expected only the targeted ball, got %v", targeted)
+	}
+	if got := attemptedBalls(balls, "missing", snap); len(got) != 0 {
+		t.Errorf("Expected no balls for an unknown target, got %v", got)
+	}
+}
+
+func TestExceedsRetryBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		ball        session.Ball
+		maxAttempts int
+		want        bool
+	}{
+		{"unlimited", session.Ball{State: session.StateInProgress, AttemptCount: 50}, 0, false},
+		{"under budget", session.Ball{State: session.StateInProgress, AttemptCount: 2}, 3, false},
+		{"used up", session.Ball{State: session.StateInProgress, AttemptCount: 3}, 3, true},
+		{"used up while pending", session.Ball{State: session.StatePending, AttemptCount: 4}, 3, true},
+		{"completed on last attempt", session.Ball{State: session.StateComplete, AttemptCount: 3}, 3, false},
+		{"already blocked", session.Ball{State: session.StateBlocked, AttemptCount: 3}, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exceedsRetryBudget(&tt.ball, tt.maxAttempts); got != tt.want {
+				t.Errorf("exceedsRetryBudget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// runAutoAgentRun runs "agent run --auto": it picks the most valuable workable
+// ball across all discovered projects, runs it until it is done or blocked,
+// then picks the next, until the -n iterations are used up or nothing is left.
+// With --json, each ball's run streams its events to events.
+func runAutoAgentRun(ctx context.Context, cmd *cobra.Command, cwd string, events io.Writer) error {
+	projects, err := autoProjectDirs(cwd)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		return fmt.Errorf("no projects with .juggle directories found")
+	}
+
+	if agentDryRun {
+		balls, err := rankAutoBalls(projects, nil)
+		if err != nil {
+			return err
+		}
+		if len(balls) == 0 {
+			fmt.Println("No workable balls in any project.")
+			return nil
+		}
+		fmt.Println("Balls --auto would pick, in order:")
+		for i, ball := range balls {
+			fmt.Printf("  %d. %s (%s) %s %s\n", i+1, ball.ID, ball.Priority, ball.Title, StyleDim.Render(ball.WorkingDir))
+		}
+		fmt.Println("\n(Dry run - agent not started)")
+		return nil
+	}
+
+	message := agentMessage
+	if cmd.Flags().Changed("message") && message == "" {
+		message, err = getMessageInteractive()
+		if err != nil {
+			return err
+		}
+	}
+
+	if agentTrust {
+		fmt.Println("⚠️  WARNING: Running with --trust flag. Agent has full system permissions.")
+		fmt.Println("    Only use this if you trust the agent and understand the risks.")
+		fmt.Println()
+	}
+	fmt.Printf("Starting agent in auto mode across %d project(s)\n", len(projects))
+	fmt.Printf("Max iterations: %d\n", agentIterations)
+	iterDelay := resolveIterDelay(cmd)
+	quietHours := resolveQuietHours()
+
+	var deadline time.Time
+	if agentMaxDuration > 0 {
+		deadline = time.Now().Add(agentMaxDuration)
+	}
+
+	attempted := make(map[string]bool)
+	remaining := agentIterations
+	var ran, complete, blocked int
+	for remaining > 0 && ctx.Err() == nil {
+		var maxDuration time.Duration
+		if !deadline.IsZero() {
+			if maxDuration = time.Until(deadline); maxDuration <= 0 {
+				fmt.Println("\nMax run duration reached.")
+				break
+			}
+		}
+
+		ball, err := nextAutoBall(projects, attempted)
+		if err != nil {
+			return err
+		}
+		if ball == nil {
+			fmt.Println("\nNo workable balls left in any project.")
+			break
+		}
+		attempted[ball.ID] = true
+
+		fmt.Printf("\n▶ %s (%s, %d iteration(s) left) in %s: %s\n", ball.ID, ball.Priority, remaining, ball.WorkingDir, ball.Title)
+		result, runErr := RunAgentLoop(ctx, AgentLoopConfig{
+			SessionID:            "all",
+			ProjectDir:           ball.WorkingDir,
+			MaxIterations:        remaining,
+			BallID:               ball.ID,
+			Trust:                agentTrust,
+			IterDelay:            iterDelay,
+			QuietHours:           quietHours,
+			Timeout:              agentTimeout,
+			MaxWait:              agentMaxWait,
+			Model:                agentModel,
+			OverloadRetryMinutes: -1,
+			Provider:             agentProvider,
+			IgnoreLock:           agentIgnoreLock,
+			Message:              message,
+			MaxTokens:            agentMaxTokens,
+			MaxCostUSD:           agentMaxCost,
+			AllowOverlap:         agentAllowOverlap,
+			MaxDuration:          maxDuration,
+			Review:               agentReview,
+			Preflight:            !agentNoPreflight && ran == 0,
+			PlanFirst:            agentPlanFirst,
+			Events:               events,
+		})
+		status, summary := queueOutcome(result, runErr)
+		fmt.Printf("■ %s %s: %s\n", ball.ID, status, summary)
+		if runErr != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			// The ball couldn't be run (e.g. locked by another agent); move on
+			continue
+		}
+
+		ran++
+		remaining -= result.Iterations
+		switch {
+		case result.Complete:
+			complete++
+		case result.Blocked:
+			blocked++
+		}
+		if result.RateLimitExceded || result.BudgetExceeded {
+			break
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("=== Summary ===")
+	fmt.Printf("Balls: %d complete, %d blocked, %d run\n", complete, blocked, ran)
+	fmt.Printf("Iterations: %d of %d\n", agentIterations-remaining, agentIterations)
+	return nil
+}
+
+// autoProjectDirs returns every discovered project, regardless of --all, plus
+// the current one if it has a .juggle directory
+func autoProjectDirs(cwd string) ([]string, error) {
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	projects, err := session.DiscoverProjects(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover projects: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(cwd, GetStoreConfig().JuggleDirName)); err == nil {
+		for _, project := range projects {
+			if filepath.Clean(project) == filepath.Clean(cwd) {
+				return projects, nil
+			}
+		}
+		projects = append(projects, cwd)
+	}
+	return projects, nil
+}
+
+// nextAutoBall returns the most valuable workable ball across the projects
+// that hasn't been attempted yet. Nil means none is left.
+func nextAutoBall(projects []string, attempted map[string]bool) (*session.Ball, error) {
+	balls, err := rankAutoBalls(projects, attempted)
+	if err != nil || len(balls) == 0 {
+		return nil, err
+	}
+	return balls[0], nil
+}
+
+// rankAutoBalls returns the workable balls across the projects, most valuable
+// first. A ball is workable when it is pending or in progress, its
+// dependencies are done, and no agent or person is working on it. They are
+// ordered like an agent run orders a session (in-progress first, then
+// priority and hotspot score), then by how many other balls wait on them, then
+// oldest first.
+func rankAutoBalls(projects []string, attempted map[string]bool) ([]*session.Ball, error) {
+	all, err := session.LoadAllBalls(projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+	states := session.BallStates(all)
+
+	dependents := make(map[string]int)
+	for _, ball := range all {
+		if ball.State == session.StateComplete || ball.State == session.StateResearched {
+			continue
+		}
+		for _, dep := range ball.DependsOn {
+			dependents[dep]++
+		}
+	}
+
+	var balls []*session.Ball
+	for _, ball := range all {
+		if attempted[ball.ID] {
+			continue
+		}
+		if ball.State != session.StatePending && ball.State != session.StateInProgress {
+			continue
+		}
+		if len(session.UnmetDependencies(ball, states)) > 0 || ball.IsClaimed() {
+			continue
+		}
+		if locked, _ := session.IsBallLocked(ball.WorkingDir, ball.ID); locked {
+			continue
+		}
+		balls = append(balls, ball)
+	}
+
+	// sortBallsForAgent is stable, so this order breaks its ties
+	sort.SliceStable(balls, func(i, j int) bool {
+		if dependents[balls[i].ID] != dependents[balls[j].ID] {
+			return dependents[balls[i].ID] > dependents[balls[j].ID]
+		}
+		return balls[i].StartedAt.Before(balls[j].StartedAt)
+	})
+	sortBallsForAgent(balls)
+	return balls, nil
+}
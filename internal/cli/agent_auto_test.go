@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestRankAutoBalls(t *testing.T) {
+	projectA := t.TempDir()
+	projectB := t.TempDir()
+	now := time.Now()
+
+	save := func(project string, ball *session.Ball) {
+		t.Helper()
+		store, err := session.NewStore(project)
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		ball.WorkingDir = project
+		ball.LastActivity = now
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to save ball: %v", err)
+		}
+	}
+	save(projectA, &session.Ball{ID: "a-old", Title: "Old medium", Priority: session.PriorityMedium, State: session.StatePending, StartedAt: now.Add(-48 * time.Hour)})
+	save(projectA, &session.Ball{ID: "a-new", Title: "New medium", Priority: session.PriorityMedium, State: session.StatePending, StartedAt: now})
+	save(projectA, &session.Ball{ID: "a-waiting", Title: "Waits on b-base", Priority: session.PriorityUrgent, State: session.StatePending, DependsOn: []string{"b-base"}, StartedAt: now})
+	save(projectA, &session.Ball{ID: "a-done", Title: "Done", Priority: session.PriorityUrgent, State: session.StateComplete, StartedAt: now})
+	save(projectB, &session.Ball{ID: "b-base", Title: "Unblocks a-waiting", Priority: session.PriorityMedium, State: session.StatePending, StartedAt: now})
+	save(projectB, &session.Ball{ID: "b-high", Title: "High", Priority: session.PriorityHigh, State: session.StatePending, StartedAt: now})
+	save(projectB, &session.Ball{ID: "b-blocked", Title: "Blocked", Priority: session.PriorityUrgent, State: session.StateBlocked, StartedAt: now})
+
+	balls, err := rankAutoBalls([]string{projectA, projectB}, nil)
+	if err != nil {
+		t.Fatalf("rankAutoBalls() error = %v", err)
+	}
+	var order []string
+	for _, ball := range balls {
+		order = append(order, ball.ID)
+	}
+	want := []string{"b-high", "b-base", "a-old", "a-new"}
+	if len(order) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, order)
+		}
+	}
+
+	next, err := nextAutoBall([]string{projectA, projectB}, map[string]bool{"b-high": true, "b-base": true})
+	if err != nil {
+		t.Fatalf("nextAutoBall() error = %v", err)
+	}
+	if next == nil || next.ID != "a-old" || next.WorkingDir != projectA {
+		t.Errorf("Expected a-old from project A once b-high and b-base were attempted, got %+v", next)
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var agentCleanCmd = &cobra.Command{
+	Use:   "clean <session>",
+	Short: "Remove files left behind by a dead agent run",
+	Long: `Remove the PID, state, control and stop files of a session's daemon, and
+the session lock files, after the run died without cleaning up.
+
+juggle already removes a stale PID file when it finds its process gone, or
+running another program that got the PID since. Use this when files are left
+regardless, e.g. a state file that still shows a crashed run.
+
+The command refuses while a daemon or foreground loop is running for the
+session; stop it with juggle agent stop first.
+
+Examples:
+  juggle agent clean my-feature`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentClean,
+}
+
+func init() {
+	agentCmd.AddCommand(agentCleanCmd)
+}
+
+func runAgentClean(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	storageID := sessionStorageID(sessionID)
+	projectDir, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	if agentLoopActive(projectDir, sessionStore, storageID) {
+		return fmt.Errorf("an agent run is active for session %s; stop it with juggle agent stop first", sessionID)
+	}
+	removed, err := daemon.CleanStale(projectDir, storageID)
+	if errors.Is(err, daemon.ErrDaemonAlive) {
+		return fmt.Errorf("an agent run is active for session %s; stop it with juggle agent stop first", sessionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove daemon files: %w", err)
+	}
+	lockRemoved, err := sessionStore.RemoveStaleLock(storageID)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 && !lockRemoved {
+		fmt.Printf("Nothing to clean for session %s\n", sessionID)
+		return nil
+	}
+	for _, path := range removed {
+		fmt.Printf("  removed %s\n", filepath.Base(path))
+	}
+	if lockRemoved {
+		fmt.Println("  removed stale session lock")
+	}
+	fmt.Printf("✓ Cleaned session %s\n", sessionID)
+	return nil
+}
@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/vcs"
+	"github.com/spf13/cobra"
+)
+
+// diffRunsMaxFiles caps each list of files in the code comparison
+const diffRunsMaxFiles = 20
+
+var diffRunsShowDiff bool
+
+var agentDiffRunsCmd = &cobra.Command{
+	Use:   "diff-runs <run-a> <run-b>",
+	Short: "Compare two agent runs",
+	Long: `Compare two agent runs from the project's run history, side by side: how
+they ended, iterations used, balls completed and blocked, time, rate-limit
+waits, tokens and cost, and the code each one changed.
+
+Use it after changing prompts, models or providers to see whether runs
+actually improved.
+
+A run is given by its history ID, or by session: "<session>" is the session's
+latest run and "<session>~N" the Nth run before it. Code changes are compared
+for runs that recorded the revisions they started and ended at.
+
+Examples:
+  juggle agent diff-runs my-feature~1 my-feature
+  juggle agent diff-runs my-feature other-feature --diff`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAgentDiffRuns,
+}
+
+func init() {
+	agentDiffRunsCmd.Flags().BoolVar(&diffRunsShowDiff, "diff", false, "Also print each run's full code diff")
+	agentCmd.AddCommand(agentDiffRunsCmd)
+}
+
+func runAgentDiffRuns(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	historyStore, err := session.NewAgentHistoryStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to open run history: %w", err)
+	}
+	a, err := historyStore.FindRecord(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := historyStore.FindRecord(args[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("A: %s %s\n", runLabel(a), StyleDim.Render("("+a.ID+")"))
+	fmt.Printf("B: %s %s\n\n", runLabel(b), StyleDim.Render("("+b.ID+")"))
+	fmt.Printf("%-18s %-22s %-22s %s\n", "", "A", "B", "Change")
+	for _, row := range compareRuns(a, b) {
+		fmt.Printf("%-18s %-22s %-22s %s\n", row[0], row[1], row[2], row[3])
+	}
+
+	backend := vcsBackendForProject(cwd)
+	diffA, errA := runDiff(backend, cwd, a)
+	diffB, errB := runDiff(backend, cwd, b)
+	fmt.Println()
+	fmt.Println("Code changes:")
+	fmt.Printf("  A: %s\n", describeRunDiff(a, diffA, errA))
+	fmt.Printf("  B: %s\n", describeRunDiff(b, diffB, errB))
+	if errA == nil && errB == nil && a.StartRevision != "" && b.StartRevision != "" {
+		both, onlyA, onlyB := compareFiles(vcs.SummarizeDiff(diffA).Files, vcs.SummarizeDiff(diffB).Files)
+		printFileList("Changed by both", both)
+		printFileList("Only in A", onlyA)
+		printFileList("Only in B", onlyB)
+	}
+
+	if diffRunsShowDiff {
+		for _, run := range []struct {
+			name string
+			diff string
+		}{{"A", diffA}, {"B", diffB}} {
+			fmt.Printf("\n═══ Diff of run %s ═══\n", run.name)
+			if run.diff == "" {
+				fmt.Println("(no diff)")
+				continue
+			}
+			fmt.Print(run.diff)
+		}
+	}
+	return nil
+}
+
+// runLabel names a run by session and start time, e.g. "my-feature, 2026-01-02 15:04"
+func runLabel(record *session.AgentRunRecord) string {
+	return fmt.Sprintf("%s, %s", record.SessionID, record.StartedAt.Format("2006-01-02 15:04"))
+}
+
+// compareRuns lists the two runs' metrics side by side as label, A, B and
+// the change from A to B (empty when there is nothing to compare)
+func compareRuns(a, b *session.AgentRunRecord) [][4]string {
+	rows := [][4]string{
+		{"Result", a.Result, b.Result, ""},
+		{"Models", runModels(a), runModels(b), ""},
+		{"Iterations", fmt.Sprintf("%d of %d", a.Iterations, a.MaxIterations), fmt.Sprintf("%d of %d", b.Iterations, b.MaxIterations), signedInt(b.Iterations - a.Iterations)},
+		{"Balls completed", fmt.Sprintf("%d of %d", a.BallsComplete, a.BallsTotal), fmt.Sprintf("%d of %d", b.BallsComplete, b.BallsTotal), signedInt(b.BallsComplete - a.BallsComplete)},
+		{"Balls blocked", fmt.Sprintf("%d", a.BallsBlocked), fmt.Sprintf("%d", b.BallsBlocked), signedInt(b.BallsBlocked - a.BallsBlocked)},
+		{"Duration", a.Duration().Round(time.Second).String(), b.Duration().Round(time.Second).String(), signedDuration(b.Duration() - a.Duration())},
+		{"Wait time", a.TotalWaitTime.Round(time.Second).String(), b.TotalWaitTime.Round(time.Second).String(), signedDuration(b.TotalWaitTime - a.TotalWaitTime)},
+	}
+
+	tokensA, tokensB := a.InputTokens+a.OutputTokens, b.InputTokens+b.OutputTokens
+	change := ""
+	if diff := tokensB - tokensA; diff > 0 {
+		change = "+" + session.FormatTokenCount(diff)
+	} else if diff < 0 {
+		change = "-" + session.FormatTokenCount(-diff)
+	}
+	rows = append(rows, [4]string{"Tokens", session.FormatTokenCount(tokensA), session.FormatTokenCount(tokensB), change})
+
+	change = ""
+	if diff := b.CostUSD - a.CostUSD; diff >= 0.00005 {
+		change = fmt.Sprintf("+$%.4f", diff)
+	} else if diff <= -0.00005 {
+		change = fmt.Sprintf("-$%.4f", -diff)
+	}
+	rows = append(rows, [4]string{"Cost", fmt.Sprintf("$%.4f", a.CostUSD), fmt.Sprintf("$%.4f", b.CostUSD), change})
+	return rows
+}
+
+// runModels lists the models a run's iterations used, "-" if none were recorded
+func runModels(record *session.AgentRunRecord) string {
+	seen := make(map[string]bool)
+	var models []string
+	for _, timing := range record.IterationTimings {
+		if timing.Model != "" && !seen[timing.Model] {
+			seen[timing.Model] = true
+			models = append(models, timing.Model)
+		}
+	}
+	if len(models) == 0 {
+		return "-"
+	}
+	return strings.Join(models, ", ")
+}
+
+// signedInt formats a change in a count, "" when there is none
+func signedInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+// signedDuration formats a change in time to the second, "" when there is none
+func signedDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d > 0:
+		return "+" + d.String()
+	case d < 0:
+		return "-" + (-d).String()
+	default:
+		return ""
+	}
+}
+
+// runDiff returns the code a run changed, between the revisions it recorded.
+// Returns "" for runs without recorded revisions.
+func runDiff(backend vcs.VCS, projectDir string, record *session.AgentRunRecord) (string, error) {
+	if record.StartRevision == "" || record.EndRevision == "" {
+		return "", nil
+	}
+	return backend.DiffRevisions(projectDir, record.StartRevision, record.EndRevision)
+}
+
+// describeRunDiff summarizes a run's code changes, e.g. "3 file(s), 42 line(s) (1a2b3c4..5d6e7f8)"
+func describeRunDiff(record *session.AgentRunRecord, diff string, err error) string {
+	switch {
+	case record.StartRevision == "" || record.EndRevision == "":
+		return StyleDim.Render("no revisions recorded")
+	case err != nil:
+		return StyleDim.Render(fmt.Sprintf("failed to diff: %v", err))
+	}
+	summary := vcs.SummarizeDiff(diff)
+	return fmt.Sprintf("%d file(s), %d line(s) %s", len(summary.Files), summary.Lines,
+		StyleDim.Render(fmt.Sprintf("(%s..%s)", shortRevision(record.StartRevision), shortRevision(record.EndRevision))))
+}
+
+// shortRevision abbreviates a commit hash for display
+func shortRevision(revision string) string {
+	if len(revision) > 8 {
+		return revision[:8]
+	}
+	return revision
+}
+
+// compareFiles splits the files two runs changed into those both changed and
+// those only one did, each sorted
+func compareFiles(a, b []string) (both, onlyA, onlyB []string) {
+	inB := make(map[string]bool, len(b))
+	for _, file := range b {
+		inB[file] = true
+	}
+	inA := make(map[string]bool, len(a))
+	for _, file := range a {
+		inA[file] = true
+		if inB[file] {
+			both = append(both, file)
+		} else {
+			onlyA = append(onlyA, file)
+		}
+	}
+	for _, file := range b {
+		if !inA[file] {
+			onlyB = append(onlyB, file)
+		}
+	}
+	sort.Strings(both)
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return both, onlyA, onlyB
+}
+
+// printFileList prints a titled list of files, capped at diffRunsMaxFiles
+func printFileList(title string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Printf("  %s (%d):\n", title, len(files))
+	for i, file := range files {
+		if i == diffRunsMaxFiles {
+			fmt.Printf("    ... and %d more\n", len(files)-diffRunsMaxFiles)
+			break
+		}
+		fmt.Printf("    %s\n", file)
+	}
+}
+
+// runRevisions returns the snapshot revision taken before a run's first
+// iteration and the one it ended at, so its changes can be compared later.
+// Either is "" when it can't be determined.
+func runRevisions(projectDir, storageID string) (start, end string) {
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return "", ""
+	}
+	if snapshots, err := sessionStore.LoadSnapshots(storageID); err == nil && len(snapshots) > 0 {
+		start = snapshots[0].Revision
+	}
+	end, _ = vcsBackendForProject(projectDir).GetSnapshotRevision(projectDir)
+	return start, end
+}
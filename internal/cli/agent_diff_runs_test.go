@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestCompareRuns(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	a := session.NewAgentRunRecord("feature", t.TempDir(), start)
+	a.MaxIterations = 10
+	a.SetMaxIterations(10, 2, 1, 4)
+	a.EndedAt = start.Add(20 * time.Minute)
+	a.InputTokens, a.OutputTokens, a.CostUSD = 90000, 10000, 1.5
+	a.IterationTimings = []session.IterationTiming{{Model: "sonnet"}, {Model: "sonnet"}}
+
+	b := session.NewAgentRunRecord("feature", t.TempDir(), start.Add(time.Hour))
+	b.MaxIterations = 10
+	b.SetComplete(6, 4, 0, 4)
+	b.EndedAt = start.Add(time.Hour + 12*time.Minute)
+	b.InputTokens, b.OutputTokens, b.CostUSD = 50000, 10000, 1.5
+	b.IterationTimings = []session.IterationTiming{{Model: "opus"}, {Model: "sonnet"}}
+
+	want := [][4]string{
+		{"Result", "max_iterations", "complete", ""},
+		{"Models", "sonnet", "opus, sonnet", ""},
+		{"Iterations", "10 of 10", "6 of 10", "-4"},
+		{"Balls completed", "2 of 4", "4 of 4", "+2"},
+		{"Balls blocked", "1", "0", "-1"},
+		{"Duration", "20m0s", "12m0s", "-8m0s"},
+		{"Wait time", "0s", "0s", ""},
+		{"Tokens", "100.0k", "60.0k", "-40.0k"},
+		{"Cost", "$1.5000", "$1.5000", ""},
+	}
+	if got := compareRuns(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("compareRuns() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCompareFiles(t *testing.T) {
+	both, onlyA, onlyB := compareFiles([]string{"b.go", "a.go", "c.go"}, []string{"c.go", "d.go", "a.go"})
+	if !reflect.DeepEqual(both, []string{"a.go", "c.go"}) {
+		t.Errorf("both = %v", both)
+	}
+	if !reflect.DeepEqual(onlyA, []string{"b.go"}) {
+		t.Errorf("onlyA = %v", onlyA)
+	}
+	if !reflect.DeepEqual(onlyB, []string{"d.go"}) {
+		t.Errorf("onlyB = %v", onlyB)
+	}
+}
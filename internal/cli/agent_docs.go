@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/watcher"
+)
+
+// contextDocsWatcher keeps a session's docs briefing current during a run:
+// it watches the session's context docs and refreshes the briefing before the
+// next iteration when one has changed
+type contextDocsWatcher struct {
+	sessionStore *session.SessionStore
+	sessionID    string
+	watcher      *watcher.Watcher
+	done         chan struct{}
+	changed      atomic.Bool
+}
+
+// newContextDocsWatcher refreshes the session's docs briefing if its docs
+// changed since the last run, then watches them. Returns nil if the session
+// has no context docs; watching is best-effort, and the briefing is still
+// refreshed at the start of each run when it fails.
+func newContextDocsWatcher(sessionStore *session.SessionStore, sess *session.JuggleSession) *contextDocsWatcher {
+	if sess == nil || len(sess.ContextDocs) == 0 {
+		return nil
+	}
+	d := &contextDocsWatcher{sessionStore: sessionStore, sessionID: sess.ID}
+	d.refresh()
+
+	w, err := watcher.New()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to watch context docs: %v\n", err)
+		return d
+	}
+	var paths []string
+	for _, doc := range sess.ContextDocs {
+		paths = append(paths, filepath.Join(sessionStore.ProjectDir(), doc))
+	}
+	if err := w.WatchFiles(paths); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to watch context docs: %v\n", err)
+		w.Close()
+		return d
+	}
+	w.Start()
+	d.watcher = w
+	d.done = make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-d.done:
+				return
+			case event := <-w.Events:
+				if event.Type == watcher.DocsChanged {
+					d.changed.Store(true)
+				}
+			}
+		}
+	}()
+	return d
+}
+
+// refreshIfChanged refreshes the briefing if a doc changed since the last check
+func (d *contextDocsWatcher) refreshIfChanged() {
+	if d == nil || !d.changed.Swap(false) {
+		return
+	}
+	d.refresh()
+}
+
+// refresh rebuilds the briefing, noting it in progress when it changed
+func (d *contextDocsWatcher) refresh() {
+	changed, err := d.sessionStore.RefreshDocsBriefing(d.sessionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to refresh docs briefing: %v\n", err)
+		return
+	}
+	if changed {
+		fmt.Println("📄 Context docs changed; docs briefing refreshed")
+		_ = d.sessionStore.AppendProgress(d.sessionID, "[DOCS] Docs briefing refreshed from changed context docs")
+	}
+}
+
+// close stops watching the docs
+func (d *contextDocsWatcher) close() {
+	if d == nil || d.watcher == nil {
+		return
+	}
+	close(d.done)
+	d.watcher.Close()
+}
+
+// writeDocsBriefing writes the session's docs briefing into a prompt's
+// <context> section, after the session context
+func writeDocsBriefing(buf *strings.Builder, sess *session.JuggleSession) {
+	if sess.DocsBriefing == nil || sess.DocsBriefing.Summary == "" {
+		return
+	}
+	if sess.Context != "" {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("Project docs briefing (from " + strings.Join(sess.ContextDocs, ", ") + "):\n\n")
+	buf.WriteString(sess.DocsBriefing.Summary)
+	if !strings.HasSuffix(sess.DocsBriefing.Summary, "\n") {
+		buf.WriteString("\n")
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// agentEnvironment runs session and ball setup commands around agent iterations
+// and supplies their environment variables to the provider.
+//
+// Session setup runs once, before the first iteration. Ball setup runs when an
+// iteration is scoped to a single ball, and that ball's teardown runs when the
+// scope moves to another ball or the run ends. Teardown is best-effort.
+type agentEnvironment struct {
+	projectDir string
+	session    *session.JuggleSession // nil when the session has no record (e.g. "all")
+	sessionUp  bool
+	ball       *session.Ball // Ball whose setup has started, if any
+}
+
+// newAgentEnvironment creates the environment for a run. sess may be nil.
+func newAgentEnvironment(projectDir string, sess *session.JuggleSession) *agentEnvironment {
+	return &agentEnvironment{projectDir: projectDir, session: sess}
+}
+
+// prepare runs any setup still needed before an iteration scoped to ball (nil = no single ball).
+// Returns an error if a setup command fails; teardown for what already ran is left to close.
+func (e *agentEnvironment) prepare(ball *session.Ball) error {
+	if !e.sessionUp {
+		e.sessionUp = true
+		if e.session != nil {
+			if err := e.run("session setup", e.session.Setup); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.ball != nil && (ball == nil || ball.ID != e.ball.ID) {
+		e.teardownBall()
+	}
+	if ball == nil || e.ball != nil {
+		return nil
+	}
+
+	e.ball = ball
+	return e.run(fmt.Sprintf("ball %s setup", ball.ShortID()), ball.Setup)
+}
+
+// vars returns the KEY=VALUE entries for the agent: session env overridden by ball env
+func (e *agentEnvironment) vars() []string {
+	merged := make(map[string]string)
+	if e.session != nil {
+		for key, value := range e.session.Env {
+			merged[key] = value
+		}
+	}
+	if e.ball != nil {
+		for key, value := range e.ball.Env {
+			merged[key] = value
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return session.EnvList(merged)
+}
+
+// close runs ball teardown, then session teardown
+func (e *agentEnvironment) close() {
+	if e.ball != nil {
+		e.teardownBall()
+	}
+	if e.sessionUp && e.session != nil {
+		if err := e.run("session teardown", e.session.Teardown); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	e.sessionUp = false
+}
+
+// teardownBall runs the current ball's teardown and clears the ball scope
+func (e *agentEnvironment) teardownBall() {
+	ball := e.ball
+	e.ball = nil
+	// A ball whose setup failed partway still gets its teardown, to clean up what did start
+	if err := e.run(fmt.Sprintf("ball %s teardown", ball.ShortID()), ball.Teardown); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// run executes commands in order with the merged environment, stopping at the first failure
+func (e *agentEnvironment) run(label string, commands []string) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	env := append(os.Environ(), e.vars()...)
+	for _, command := range commands {
+		fmt.Printf("⚙️  Running %s: %s\n", label, command)
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = e.projectDir
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s command %q failed: %w", label, command, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// modelLadder lists the canonical models from smallest to largest; failing
+// balls escalate one step at a time
+var modelLadder = []string{"haiku", "sonnet", "opus"}
+
+// nextLargerModel returns the model one step up the ladder, or "" for the
+// largest model and models not on the ladder
+func nextLargerModel(model string) string {
+	i := slices.Index(modelLadder, model)
+	if i < 0 || i == len(modelLadder)-1 {
+		return ""
+	}
+	return modelLadder[i+1]
+}
+
+// mostEscalatedBall returns the ball escalated to the largest model, or nil
+// if none was escalated
+func mostEscalatedBall(balls []*session.Ball) *session.Ball {
+	var most *session.Ball
+	for _, ball := range balls {
+		if ball.EscalatedModel == "" {
+			continue
+		}
+		if most == nil || slices.Index(modelLadder, ball.EscalatedModel) > slices.Index(modelLadder, most.EscalatedModel) {
+			most = ball
+		}
+	}
+	return most
+}
+
+// recordModelFailures counts an iteration at model against the balls it
+// worked on but left unfinished, including those reopened by validation or
+// the verifier. Balls that reach the project's escalate_after_failures move
+// up to the next larger model. Returns the IDs of the escalated balls.
+func recordModelFailures(projectDir, sessionID, storageID, ballID, model string, snap *session.IterationSnapshot) []string {
+	threshold, err := session.GetProjectEscalateAfterFailures(projectDir)
+	if err != nil || threshold <= 0 {
+		return nil
+	}
+	next := nextLargerModel(model)
+	if next == "" {
+		return nil // Already at the top, or a model juggle can't rank
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil
+	}
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return nil
+	}
+
+	var escalated []string
+	for _, ball := range attemptedBalls(balls, ballID, snap) {
+		// Finished or blocked balls didn't fail, and pinned models are left alone
+		unfinished := ball.State == session.StatePending || ball.State == session.StateInProgress
+		if !unfinished || ball.ModelOverride != "" {
+			continue
+		}
+		ball.FailedAttempts++
+		if ball.FailedAttempts >= threshold {
+			ball.Escalate(next)
+			escalated = append(escalated, ball.ID)
+		}
+		if err := store.UpdateBall(ball); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record failed attempt on %s: %v\n", ball.ID, err)
+		}
+	}
+
+	for _, id := range escalated {
+		fmt.Printf("⬆️  %s failed %d attempt(s) at %s, escalating to %s\n", id, threshold, model, next)
+		logEscalationToProgress(projectDir, storageID, id, threshold, model, next)
+	}
+	return escalated
+}
+
+// logEscalationToProgress logs a ball's model escalation to the session's progress file
+func logEscalationToProgress(projectDir, sessionID, ballID string, failures int, from, to string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[ESCALATE] %s failed %d attempt(s) at %s, escalated to %s", ballID, failures, from, to)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestNextLargerModel(t *testing.T) {
+	for model, want := range map[string]string{
+		"haiku":  "sonnet",
+		"sonnet": "opus",
+		"opus":   "",
+		"gpt-5":  "",
+		"":       "",
+	} {
+		if got := nextLargerModel(model); got != want {
+			t.Errorf("nextLargerModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestMostEscalatedBall(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "plain"},
+		{ID: "to-sonnet", EscalatedModel: "sonnet"},
+		{ID: "to-opus", EscalatedModel: "opus"},
+	}
+	if got := mostEscalatedBall(balls); got == nil || got.ID != "to-opus" {
+		t.Errorf("Expected the ball escalated to opus, got %v", got)
+	}
+	if got := mostEscalatedBall(balls[:1]); got != nil {
+		t.Errorf("Expected no escalated ball, got %v", got.ID)
+	}
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// estimateTimeout bounds each batch so a stuck estimate can't hang the command
+const estimateTimeout = 5 * time.Minute
+
+var (
+	estimateModel     string
+	estimateProvider  string
+	estimateBatchSize int
+	estimateForce     bool
+	estimateDryRun    bool
+)
+
+// agentEstimateCmd asks a cheap model to size up pending balls
+var agentEstimateCmd = &cobra.Command{
+	Use:   "estimate [session]",
+	Short: "Estimate model size, iterations and risks for pending balls",
+	Long: `Send pending balls, in batches, to a cheap model and record its estimate
+on each ball: the model size it needs, how many agent iterations it should take,
+and risks that could make it harder than it looks.
+
+Agent runs use the estimates when picking a model: a ball without its own
+model_size prefers its estimated size over the session default, and the model
+is chosen by estimated iterations of work rather than by ball count.
+
+Balls that already have an estimate are skipped unless --force is given.
+
+Ball Selection:
+- No argument: Pending balls in current repo
+- Session arg: Pending balls with that session tag
+- --all flag: Pending balls from all discovered projects
+
+Examples:
+  juggle agent estimate
+  juggle agent estimate my-feature --force
+  juggle agent estimate --model sonnet --batch-size 5 --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentEstimate,
+}
+
+func init() {
+	agentEstimateCmd.Flags().StringVarP(&estimateModel, "model", "m", "haiku", "Model that makes the estimates")
+	agentEstimateCmd.Flags().StringVar(&estimateProvider, "provider", "", "Agent provider to use. Default: from config or claude")
+	agentEstimateCmd.Flags().IntVar(&estimateBatchSize, "batch-size", 10, "Balls sent to the model per request")
+	agentEstimateCmd.Flags().BoolVarP(&estimateForce, "force", "f", false, "Re-estimate balls that already have an estimate")
+	agentEstimateCmd.Flags().BoolVar(&estimateDryRun, "dry-run", false, "Print the estimates without saving them")
+	agentCmd.AddCommand(agentEstimateCmd)
+}
+
+// estimatePattern matches one ball's estimate in the model's reply
+var estimatePattern = regexp.MustCompile(`(?s)<estimate\s+([^>]*)>(.*?)</estimate>`)
+
+// estimateAttrPattern matches an estimate's ball="...", size="..." and iterations="..." attributes
+var estimateAttrPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func runAgentEstimate(cmd *cobra.Command, args []string) error {
+	var sessionID string
+	if len(args) > 0 {
+		sessionID = args[0]
+	}
+	if estimateBatchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	candidates, err := loadBallsForRefine(cwd, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	var balls []*session.Ball
+	for _, ball := range candidates {
+		if ball.State != session.StatePending || (ball.Estimate != nil && !estimateForce) {
+			continue
+		}
+		balls = append(balls, ball)
+	}
+	if len(balls) == 0 {
+		fmt.Println("No pending balls to estimate.")
+		return nil
+	}
+
+	model, err := configureEstimateProvider(cwd)
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext(cmd)
+	estimated := 0
+	for start := 0; start < len(balls); start += estimateBatchSize {
+		if ctx.Err() != nil {
+			break
+		}
+		batch := balls[start:min(start+estimateBatchSize, len(balls))]
+		fmt.Printf("🔮 Estimating %d ball(s) with %s...\n", len(batch), model)
+
+		result, err := agent.DefaultRunner.Run(agent.RunOptions{
+			Prompt:     buildEstimatePrompt(batch),
+			Mode:       agent.ModeHeadless,
+			Permission: agent.PermissionPlan,
+			Model:      model,
+			WorkingDir: cwd,
+			Timeout:    estimateTimeout,
+			Context:    ctx,
+		})
+		if err == nil && result.Error != nil {
+			err = result.Error
+		}
+		if err == nil && result.RateLimited {
+			err = rateLimitedError(fmt.Errorf("rate limited while estimating"))
+		}
+		if err != nil {
+			return fmt.Errorf("estimate failed: %w", err)
+		}
+
+		estimates := parseEstimates(result.Output, model)
+		for _, ball := range batch {
+			estimate := estimates[ball.ID]
+			if estimate == nil {
+				estimate = estimates[ball.ShortID()]
+			}
+			if estimate == nil {
+				fmt.Fprintf(os.Stderr, "⚠️  No estimate for %s\n", ball.ShortID())
+				continue
+			}
+			if !estimateDryRun {
+				if err := saveBallEstimate(ball, estimate); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to save estimate for %s: %v\n", ball.ShortID(), err)
+					continue
+				}
+			}
+			fmt.Printf("  %s %s %s\n", ball.ShortID(), formatEstimate(estimate), StyleDim.Render(ball.Title))
+			estimated++
+		}
+	}
+
+	if estimateDryRun {
+		fmt.Printf("\n%d ball(s) estimated (dry run, nothing saved)\n", estimated)
+	} else {
+		fmt.Printf("\n✓ %d ball(s) estimated\n", estimated)
+	}
+	return nil
+}
+
+// configureEstimateProvider selects the agent provider for estimates and
+// returns the model to ask, replaced with a supported one if needed
+func configureEstimateProvider(projectDir string) (string, error) {
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	registerCustomProviders()
+	registerProviderBinaries(projectDir)
+	providerType := provider.Detect(estimateProvider, projectProvider, globalProvider)
+
+	if !provider.IsAvailable(providerType) {
+		return "", providerUnavailableError(providerType)
+	}
+	agentProv := provider.Get(providerType)
+	agent.SetProvider(agentProv)
+
+	globalOverrides, _ := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	projectOverrides, _ := session.GetProjectModelOverrides(projectDir)
+	agent.SetModelOverrides(session.MergeModelOverrides(globalOverrides, projectOverrides))
+
+	selection := &ModelSelection{Model: estimateModel}
+	validateModelForProvider(selection, agentProv)
+	if selection.Reason != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", selection.Reason)
+	}
+	return selection.Model, nil
+}
+
+// buildEstimatePrompt asks the model to size up each ball in the batch and
+// answer with one <estimate> element per ball
+func buildEstimatePrompt(balls []*session.Ball) string {
+	var b strings.Builder
+	b.WriteString("You are estimating tasks for an autonomous coding agent. Do not change any files.\n")
+	b.WriteString("For each task below, estimate:\n")
+	b.WriteString("- size: the smallest model that can do it well: small (simple, mechanical), medium (typical feature work), or large (design decisions, subtle bugs, wide changes)\n")
+	b.WriteString("- iterations: how many agent iterations it will take (one iteration is one focused session ending in a commit)\n")
+	b.WriteString("- risks: a sentence on what could make it harder than it looks, or nothing if it is straightforward\n\n")
+
+	for _, ball := range balls {
+		fmt.Fprintf(&b, "## %s\n", ball.ID)
+		fmt.Fprintf(&b, "Title: %s\n", ball.Title)
+		if !ball.Kind.IsCode() {
+			fmt.Fprintf(&b, "Kind: %s\n", ball.Kind)
+		}
+		if ball.Context != "" {
+			fmt.Fprintf(&b, "Context: %s\n", ball.Context)
+		}
+		if len(ball.AcceptanceCriteria) > 0 {
+			b.WriteString("Acceptance Criteria:\n")
+			for i, ac := range ball.AcceptanceCriteria {
+				fmt.Fprintf(&b, "  %d. %s\n", i+1, ac)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("Reply with one element per task, and nothing else:\n")
+	b.WriteString("<estimate ball=\"task-id\" size=\"small|medium|large\" iterations=\"N\">risks</estimate>\n")
+	return b.String()
+}
+
+// parseEstimates extracts the estimates in the model's reply, keyed by ball
+// ID. Estimates with an unknown size or no iteration count are dropped.
+func parseEstimates(output, model string) map[string]*session.BallEstimate {
+	estimates := make(map[string]*session.BallEstimate)
+	for _, match := range estimatePattern.FindAllStringSubmatch(output, -1) {
+		var ballID string
+		estimate := &session.BallEstimate{
+			Risks:       strings.TrimSpace(match[2]),
+			Model:       model,
+			EstimatedAt: time.Now(),
+		}
+		for _, attr := range estimateAttrPattern.FindAllStringSubmatch(match[1], -1) {
+			value := strings.TrimSpace(attr[2])
+			switch attr[1] {
+			case "ball":
+				ballID = value
+			case "size":
+				estimate.ModelSize = session.ModelSize(strings.ToLower(value))
+			case "iterations":
+				estimate.Iterations, _ = strconv.Atoi(value)
+			}
+		}
+		if ballID == "" || estimate.ModelSize == session.ModelSizeBlank ||
+			!session.ValidateModelSize(string(estimate.ModelSize)) || estimate.Iterations < 1 {
+			continue
+		}
+		estimates[ballID] = estimate
+	}
+	return estimates
+}
+
+// saveBallEstimate records the estimate on the ball in its project's store
+func saveBallEstimate(ball *session.Ball, estimate *session.BallEstimate) error {
+	store, err := NewStoreForCommand(ball.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	ball.SetEstimate(estimate)
+	return store.UpdateBall(ball)
+}
+
+// formatEstimate summarizes an estimate for display
+func formatEstimate(estimate *session.BallEstimate) string {
+	s := fmt.Sprintf("%s, ~%d iteration(s)", estimate.ModelSize, estimate.Iterations)
+	if estimate.Risks != "" {
+		s += " - " + estimate.Risks
+	}
+	return s
+}
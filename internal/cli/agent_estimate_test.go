@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestParseEstimates(t *testing.T) {
+	output := `Here are my estimates.
+
+<estimate ball="proj-a1b2" size="small" iterations="1"></estimate>
+<estimate ball="proj-c3d4" size="Large" iterations="4">
+Touches the storage format; migrations may be needed.
+</estimate>
+<estimate ball="proj-e5f6" size="huge" iterations="2">bad size</estimate>
+<estimate ball="proj-g7h8" size="medium" iterations="none">bad count</estimate>`
+
+	estimates := parseEstimates(output, "haiku")
+	if len(estimates) != 2 {
+		t.Fatalf("Expected 2 valid estimates, got %d: %v", len(estimates), estimates)
+	}
+
+	small := estimates["proj-a1b2"]
+	if small == nil || small.ModelSize != session.ModelSizeSmall || small.Iterations != 1 || small.Risks != "" || small.Model != "haiku" {
+		t.Errorf("Unexpected estimate for proj-a1b2: %+v", small)
+	}
+	large := estimates["proj-c3d4"]
+	if large == nil || large.ModelSize != session.ModelSizeLarge || large.Iterations != 4 ||
+		large.Risks != "Touches the storage format; migrations may be needed." {
+		t.Errorf("Unexpected estimate for proj-c3d4: %+v", large)
+	}
+}
+
+func TestBuildEstimatePrompt(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "proj-a1b2", Title: "Add export flag", AcceptanceCriteria: []string{"Flag documented"}},
+		{ID: "proj-c3d4", Title: "Write the upgrade guide", Kind: session.BallKindDocs},
+	}
+
+	prompt := buildEstimatePrompt(balls)
+	for _, want := range []string{"## proj-a1b2", "Title: Add export flag", "1. Flag documented", "## proj-c3d4", "Kind: docs", `<estimate ball="task-id"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+}
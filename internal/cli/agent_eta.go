@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// etaHistoryRuns caps how many past runs feed the ETA, so it tracks recent speed
+const etaHistoryRuns = 50
+
+// runETA estimates how long an agent run has left from past iteration
+// durations per model and the iterations this run has finished so far
+type runETA struct {
+	history      []session.IterationTiming
+	current      []session.IterationTiming
+	itersPerBall float64
+}
+
+// newRunETA loads the project's recent agent history. Runs without timings
+// still tell how many iterations a ball usually takes.
+func newRunETA(projectDir string) *runETA {
+	eta := &runETA{itersPerBall: 1}
+	historyStore, err := session.NewAgentHistoryStore(projectDir)
+	if err != nil {
+		return eta
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil {
+		return eta
+	}
+	if len(records) > etaHistoryRuns {
+		records = records[:etaHistoryRuns] // Most recent first
+	}
+	iterations, balls := 0, 0
+	for _, record := range records {
+		eta.history = append(eta.history, record.IterationTimings...)
+		if record.BallsComplete > 0 {
+			iterations += record.Iterations
+			balls += record.BallsComplete
+		}
+	}
+	if balls > 0 && iterations >= balls {
+		eta.itersPerBall = float64(iterations) / float64(balls)
+	}
+	return eta
+}
+
+// add records a finished iteration of this run
+func (e *runETA) add(timing session.IterationTiming) {
+	e.current = append(e.current, timing)
+}
+
+// perIteration picks the best guess for one iteration with model: this run's
+// own iterations with it, then past runs with it, then any model
+func (e *runETA) perIteration(model string) (time.Duration, bool) {
+	for _, candidate := range []struct {
+		timings []session.IterationTiming
+		model   string
+	}{{e.current, model}, {e.history, model}, {e.current, ""}, {e.history, ""}} {
+		if avg, ok := session.AverageIterationDuration(candidate.timings, candidate.model); ok {
+			return avg, true
+		}
+	}
+	return 0, false
+}
+
+// estimate returns the time left to work through the workable balls, capped
+// at the iterations the run has left. ok is false without any timings to go on.
+func (e *runETA) estimate(model string, workable, iterationsLeft int) (time.Duration, bool) {
+	per, ok := e.perIteration(model)
+	if !ok || workable <= 0 || iterationsLeft <= 0 {
+		return 0, false
+	}
+	needed := int(math.Ceil(float64(workable) * e.itersPerBall))
+	if needed > iterationsLeft {
+		needed = iterationsLeft
+	}
+	return per * time.Duration(needed), true
+}
+
+// formatETA renders a rough duration such as "~45s", "~12m" or "~1h05m"
+func formatETA(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("~%ds", int(d.Round(time.Second).Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("~%dm", int(d.Round(time.Minute).Minutes()))
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("~%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestRunETA_Estimate(t *testing.T) {
+	eta := &runETA{
+		history: []session.IterationTiming{
+			{Model: "opus", Duration: 10 * time.Minute},
+			{Model: "sonnet", Duration: 4 * time.Minute},
+		},
+		itersPerBall: 1.5,
+	}
+
+	// 3 balls at 1.5 iterations each, 10m per opus iteration
+	if got, ok := eta.estimate("opus", 3, 10); !ok || got != 50*time.Minute {
+		t.Errorf("estimate(opus, 3, 10) = %v, %v; want 50m, true", got, ok)
+	}
+	// Capped by the iterations left in the run
+	if got, _ := eta.estimate("opus", 3, 2); got != 20*time.Minute {
+		t.Errorf("estimate(opus, 3, 2) = %v, want 20m", got)
+	}
+	// A model without history falls back to all models
+	if got, _ := eta.estimate("haiku", 2, 10); got != 21*time.Minute {
+		t.Errorf("estimate(haiku, 2, 10) = %v, want 21m", got)
+	}
+
+	// This run's own iterations take over as they finish
+	eta.add(session.IterationTiming{Model: "opus", Duration: 2 * time.Minute})
+	if got, _ := eta.estimate("opus", 1, 10); got != 4*time.Minute {
+		t.Errorf("estimate after add = %v, want 4m", got)
+	}
+
+	if _, ok := (&runETA{itersPerBall: 1}).estimate("opus", 3, 10); ok {
+		t.Error("expected no estimate without any timings")
+	}
+	if _, ok := eta.estimate("opus", 0, 10); ok {
+		t.Error("expected no estimate without workable balls")
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{40 * time.Second, "~40s"},
+		{12*time.Minute + 20*time.Second, "~12m"},
+		{65 * time.Minute, "~1h05m"},
+	}
+	for _, tt := range tests {
+		if got := formatETA(tt.d); got != tt.want {
+			t.Errorf("formatETA(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
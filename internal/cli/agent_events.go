@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Event types written by "agent run --json" and to a daemon's event journal
+const (
+	AgentEventIterationStart = "iteration_start"
+	AgentEventModelSelected  = "model_selected"
+	AgentEventRateLimited    = "rate_limited"
+	AgentEventBallComplete   = "ball_complete"
+	AgentEventRunSummary     = "run_summary"
+)
+
+// Event types only written to a daemon's event journal
+const (
+	AgentEventRunStart        = "run_start"
+	AgentEventIterationEnd    = "iteration_end"
+	AgentEventControl         = "control"
+	AgentEventPaused          = "paused"
+	AgentEventResumed         = "resumed"
+	AgentEventModelChanged    = "model_changed"
+	AgentEventProviderChanged = "provider_changed"
+)
+
+// eventJournalFile is the append-only journal of a daemon's events, in its
+// session directory
+const eventJournalFile = "events.jsonl"
+
+// AgentEvent is one line of the NDJSON stream "agent run --json" writes to
+// stdout, and of a daemon's event journal. Fields that don't apply to an
+// event type are left out.
+type AgentEvent struct {
+	Type            string       `json:"type"`
+	Time            time.Time    `json:"time"`
+	Session         string       `json:"session"`
+	Iteration       int          `json:"iteration,omitempty"`
+	MaxIterations   int          `json:"max_iterations,omitempty"`
+	BallID          string       `json:"ball_id,omitempty"`
+	Title           string       `json:"title,omitempty"`
+	Provider        string       `json:"provider,omitempty"`
+	Model           string       `json:"model,omitempty"`
+	Reason          string       `json:"reason,omitempty"`           // Why the model was picked
+	Attempt         int          `json:"attempt,omitempty"`          // Rate limit retry, starting at 1
+	WaitSeconds     float64      `json:"wait_seconds,omitempty"`     // Wait before the rate limit retry
+	DurationSeconds float64      `json:"duration_seconds,omitempty"` // How long the iteration's agent ran
+	NoOp            bool         `json:"no_op,omitempty"`            // The iteration changed nothing
+	Command         string       `json:"command,omitempty"`          // Control command received
+	Args            string       `json:"args,omitempty"`             // Its arguments
+	Status          string       `json:"status,omitempty"`           // How the run ended, see agentRunStatus
+	Result          *AgentResult `json:"result,omitempty"`
+}
+
+// agentEventsMu keeps lines whole when several loops share a writer (--auto)
+var agentEventsMu sync.Mutex
+
+// agentEvents writes one loop's events to the --json stream and the
+// session's event journal; without either it drops them
+type agentEvents struct {
+	w          io.Writer
+	journal    io.WriteCloser
+	session    string
+	summarized bool
+}
+
+func newAgentEvents(w io.Writer, sessionID string) *agentEvents {
+	return &agentEvents{w: w, session: sessionID}
+}
+
+// enabled reports whether events go anywhere
+func (e *agentEvents) enabled() bool {
+	return e.w != nil || e.journal != nil
+}
+
+// openJournal starts appending events to the session's events.jsonl
+func (e *agentEvents) openJournal(projectDir, storageID string) error {
+	dir := filepath.Join(projectDir, ".juggle", "sessions", storageID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, eventJournalFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	e.journal = f
+	return nil
+}
+
+// closeJournal records how the run ended, unless summary already has, and
+// closes the journal. A result without an end time means the loop returned
+// an error.
+func (e *agentEvents) closeJournal(result *AgentResult) {
+	if e.journal == nil {
+		return
+	}
+	if !e.summarized {
+		status := agentRunStatus(result)
+		if result.EndedAt.IsZero() {
+			status = "failed"
+		}
+		e.write(e.journal, e.stamp(AgentEvent{Type: AgentEventRunSummary, Iteration: result.Iterations, Status: status, Result: result}))
+	}
+	_ = e.journal.Close()
+	e.journal = nil
+}
+
+// emit stamps the event with the time and session and writes it as one line
+func (e *agentEvents) emit(event AgentEvent) {
+	if !e.enabled() {
+		return
+	}
+	event = e.stamp(event)
+	if e.w != nil {
+		e.write(e.w, event)
+	}
+	if e.journal != nil {
+		e.write(e.journal, event)
+	}
+}
+
+// record writes an event to the journal only
+func (e *agentEvents) record(event AgentEvent) {
+	if e.journal != nil {
+		e.write(e.journal, e.stamp(event))
+	}
+}
+
+// stamp fills in the event's time and session
+func (e *agentEvents) stamp(event AgentEvent) AgentEvent {
+	event.Time = time.Now()
+	event.Session = e.session
+	return event
+}
+
+// write writes one event as one line
+func (e *agentEvents) write(w io.Writer, event AgentEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	agentEventsMu.Lock()
+	defer agentEventsMu.Unlock()
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// summary reports how the run ended, with its full result
+func (e *agentEvents) summary(result *AgentResult) {
+	e.summarized = true
+	e.emit(AgentEvent{Type: AgentEventRunSummary, Iteration: result.Iterations, Status: agentRunStatus(result), Result: result})
+}
+
+// agentRunStatus names how a run ended, matching the Status line of its summary
+func agentRunStatus(result *AgentResult) string {
+	switch {
+	case result.Complete:
+		return "complete"
+	case result.Blocked:
+		return "blocked"
+	case result.TimedOut:
+		return "timeout"
+	case result.RateLimitExceded:
+		return "rate_limit_exceeded"
+	case result.BudgetExceeded:
+		return "budget_exceeded"
+	default:
+		return "max_iterations"
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAgentEvents_Emit(t *testing.T) {
+	var out bytes.Buffer
+	events := newAgentEvents(&out, "my-session")
+	events.emit(AgentEvent{Type: AgentEventIterationStart, Iteration: 1, MaxIterations: 5})
+	events.summary(&AgentResult{Iterations: 1, Blocked: true, BlockedReason: "needs input"})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one line per event, got %q", out.String())
+	}
+	if strings.Contains(lines[0], `"ball_id"`) || strings.Contains(lines[0], `"result"`) {
+		t.Errorf("Expected fields that don't apply to be left out, got %s", lines[0])
+	}
+
+	var summary AgentEvent
+	if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+		t.Fatalf("Failed to parse event: %v", err)
+	}
+	if summary.Type != AgentEventRunSummary || summary.Session != "my-session" || summary.Status != "blocked" ||
+		summary.Result == nil || summary.Result.BlockedReason != "needs input" {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+
+	// Without a writer, events are dropped
+	newAgentEvents(nil, "my-session").emit(AgentEvent{Type: AgentEventRunSummary})
+}
+
+func TestAgentEvents_Journal(t *testing.T) {
+	projectDir := t.TempDir()
+	journal := filepath.Join(projectDir, ".juggle", "sessions", "my-session", eventJournalFile)
+	readJournal := func() []AgentEvent {
+		t.Helper()
+		data, err := os.ReadFile(journal)
+		if err != nil {
+			t.Fatalf("Failed to read journal: %v", err)
+		}
+		var events []AgentEvent
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var event AgentEvent
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				t.Fatalf("Failed to parse journal line %q: %v", line, err)
+			}
+			events = append(events, event)
+		}
+		return events
+	}
+
+	// Events go to both the stream and the journal
+	var out bytes.Buffer
+	events := newAgentEvents(&out, "my-session")
+	if err := events.openJournal(projectDir, "my-session"); err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	events.record(AgentEvent{Type: AgentEventControl, Iteration: 2, Command: "change_model", Args: "opus"})
+	events.emit(AgentEvent{Type: AgentEventIterationStart, Iteration: 2})
+	result := &AgentResult{Iterations: 2, Complete: true}
+	events.summary(result)
+	events.closeJournal(result)
+
+	// The stream doesn't get journal-only events
+	if got := strings.Count(out.String(), "\n"); got != 2 || strings.Contains(out.String(), AgentEventControl) {
+		t.Errorf("Expected iteration_start and run_summary streamed, got %q", out.String())
+	}
+	got := readJournal()
+	if len(got) != 3 || got[0].Command != "change_model" || got[0].Args != "opus" || got[2].Status != "complete" {
+		t.Fatalf("Unexpected journal: %+v", got)
+	}
+
+	// A later run appends, and one that returns an error is still recorded as ended
+	events = newAgentEvents(nil, "my-session")
+	if err := events.openJournal(projectDir, "my-session"); err != nil {
+		t.Fatalf("Failed to reopen journal: %v", err)
+	}
+	events.record(AgentEvent{Type: AgentEventPaused, Iteration: 1})
+	events.closeJournal(&AgentResult{Iterations: 1})
+
+	got = readJournal()
+	if len(got) != 5 || got[3].Type != AgentEventPaused || got[4].Type != AgentEventRunSummary || got[4].Status != "failed" {
+		t.Errorf("Unexpected journal after the second run: %+v", got)
+	}
+}
+
+func TestAgentRunStatus(t *testing.T) {
+	tests := []struct {
+		result AgentResult
+		want   string
+	}{
+		{AgentResult{Complete: true}, "complete"},
+		{AgentResult{Blocked: true}, "blocked"},
+		{AgentResult{TimedOut: true}, "timeout"},
+		{AgentResult{RateLimitExceded: true}, "rate_limit_exceeded"},
+		{AgentResult{BudgetExceeded: true}, "budget_exceeded"},
+		{AgentResult{Iterations: 10}, "max_iterations"},
+	}
+	for _, tt := range tests {
+		if got := agentRunStatus(&tt.result); got != tt.want {
+			t.Errorf("agentRunStatus(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// runFormatters runs the project's configured formatters and linters on files touched
+// since the iteration snapshot, so their fixes land in the same auto-commit.
+// Best-effort: a failing formatter is reported but never blocks the commit.
+func runFormatters(projectDir, storageID string, snap *session.IterationSnapshot) {
+	formatters, err := session.GetProjectFormatters(projectDir)
+	if err != nil || len(formatters) == 0 {
+		return
+	}
+	if snap == nil || snap.Revision == "" {
+		return
+	}
+
+	changed, err := vcsBackendForProject(projectDir).ChangedFiles(projectDir, snap.Revision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to list changed files for formatting: %v\n", err)
+		return
+	}
+
+	// Deleted files show up in the diff but can't be formatted
+	touched := make([]string, 0, len(changed))
+	for _, path := range changed {
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err == nil {
+			touched = append(touched, path)
+		}
+	}
+	if len(touched) == 0 {
+		return
+	}
+
+	for _, formatter := range formatters {
+		if formatter.Command == "" {
+			continue
+		}
+
+		var files []string
+		for _, path := range touched {
+			if formatter.Matches(path) {
+				files = append(files, path)
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		fmt.Printf("🧹 Running %s on %d file(s)\n", formatter.Command, len(files))
+		if err := runFormatterCommand(projectDir, formatter.Command, files); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: formatter %q failed: %v\n", formatter.Command, err)
+			logFormatToProgress(projectDir, storageID,
+				fmt.Sprintf("%s failed on %d file(s): %v", formatter.Command, len(files), err))
+		}
+	}
+}
+
+// runFormatterCommand runs a shell command with the files appended as arguments.
+// Files are passed as positional parameters so paths are never re-parsed by the shell.
+func runFormatterCommand(projectDir, command string, files []string) error {
+	args := append([]string{"-c", command + ` "$@"`, "sh"}, files...)
+	cmd := exec.Command("sh", args...)
+	cmd.Dir = projectDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// logFormatToProgress logs a formatter failure to the session's progress file
+func logFormatToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[FORMAT] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// GateResult is the outcome of one build, lint or typecheck gate on one iteration
+type GateResult struct {
+	Iteration int           `json:"iteration"`
+	Name      string        `json:"name"`
+	Passed    bool          `json:"passed"`
+	TimedOut  bool          `json:"timed_out,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// String describes the result for progress and summaries, e.g. "lint failed (12s): exit status 1"
+func (g GateResult) String() string {
+	took := g.Duration.Round(time.Second)
+	switch {
+	case g.Passed:
+		return fmt.Sprintf("%s passed (%s)", g.Name, took)
+	case g.TimedOut:
+		return fmt.Sprintf("%s timed out after %s", g.Name, took)
+	default:
+		return fmt.Sprintf("%s failed (%s): %s", g.Name, took, g.Error)
+	}
+}
+
+// runGates runs the project's gates in order on an iteration the agent asked
+// to commit, stopping at the first failure. Gates with on=complete only run when
+// the iteration completed a ball. Like validation, iterations that could only
+// have worked on docs, research or ops balls skip them. Returns the results of
+// the gates that ran and a failure summary, or "" if they all passed.
+func runGates(ctx context.Context, projectDir, sessionID, storageID, ballID string, iteration int, snap *session.IterationSnapshot) ([]GateResult, string) {
+	gates, err := session.GetProjectGates(projectDir)
+	if err != nil || len(gates) == 0 {
+		return nil, ""
+	}
+	if onlyNonCodeBalls(projectDir, sessionID, ballID, snap) {
+		fmt.Println("🚧 Only non-code balls in this iteration, skipping gates")
+		return nil, ""
+	}
+
+	completedBalls, err := ballsCompletedSince(projectDir, sessionID, ballID, snap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load balls for gates: %v\n", err)
+	}
+	completed := len(completedBalls) > 0
+
+	var results []GateResult
+	for _, gate := range gates {
+		if gate.Command == "" {
+			continue
+		}
+		if gate.On != "" && gate.On != session.GateOnCommit && !gate.OnComplete() {
+			fmt.Fprintf(os.Stderr, "Warning: gate %s has unknown trigger %q, running it on every commit\n", gate.Label(), gate.On)
+		}
+		if gate.OnComplete() && !completed {
+			continue
+		}
+
+		fmt.Printf("🚧 Gate %s: %s\n", gate.Label(), gate.Command)
+		result, output := runGate(ctx, projectDir, gate)
+		result.Iteration = iteration
+		results = append(results, result)
+		if result.Passed {
+			fmt.Printf("✓ Gate %s\n", result)
+			continue
+		}
+
+		fmt.Printf("❌ Gate %s\n", result)
+		logGatesToProgress(projectDir, storageID, results, tailLines(output, validationOutputLines))
+		return results, "gate " + result.String()
+	}
+
+	if len(results) > 0 {
+		logGatesToProgress(projectDir, storageID, results, "")
+	}
+	return results, ""
+}
+
+// runGate runs one gate command, streaming its output to the console.
+// Returns the result (without iteration) and the captured output.
+func runGate(ctx context.Context, projectDir string, gate session.GateConfig) (GateResult, string) {
+	gateCtx := ctx
+	if timeout := gate.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		gateCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(gateCtx, "sh", "-c", gate.Command)
+	cmd.Dir = projectDir
+	// Don't wait forever on pipes held open by the killed command's children
+	cmd.WaitDelay = time.Second
+
+	var output strings.Builder
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+
+	started := time.Now()
+	err := cmd.Run()
+	result := GateResult{
+		Name:     gate.Label(),
+		Passed:   err == nil,
+		Duration: time.Since(started),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.TimedOut = errors.Is(gateCtx.Err(), context.DeadlineExceeded)
+	}
+	return result, output.String()
+}
+
+// gateFailures summarizes the failed gates of a run, e.g. "lint (iteration 2), build (iteration 4)"
+func gateFailures(results []GateResult) string {
+	var failed []string
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, fmt.Sprintf("%s (iteration %d)", result.Name, result.Iteration))
+		}
+	}
+	return strings.Join(failed, ", ")
+}
+
+// logGatesToProgress logs an iteration's gate results, and the output of a
+// failing gate, to the session's progress file
+func logGatesToProgress(projectDir, sessionID string, results []GateResult, failureOutput string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	parts := make([]string, len(results))
+	for i, result := range results {
+		parts[i] = result.String()
+	}
+	entry := fmt.Sprintf("[GATE] Iteration %d: %s", results[0].Iteration, strings.Join(parts, ", "))
+	if failureOutput != "" {
+		entry += "\n" + failureOutput
+	}
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
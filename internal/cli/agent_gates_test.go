@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGateResultString(t *testing.T) {
+	tests := []struct {
+		result GateResult
+		want   string
+	}{
+		{GateResult{Name: "build", Passed: true, Duration: 3200 * time.Millisecond}, "build passed (3s)"},
+		{GateResult{Name: "lint", Duration: 12 * time.Second, Error: "exit status 1"}, "lint failed (12s): exit status 1"},
+		{GateResult{Name: "typecheck", TimedOut: true, Duration: time.Minute, Error: "signal: killed"}, "typecheck timed out after 1m0s"},
+	}
+	for _, tt := range tests {
+		if got := tt.result.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestGateFailures(t *testing.T) {
+	results := []GateResult{
+		{Iteration: 1, Name: "build", Passed: true},
+		{Iteration: 2, Name: "lint"},
+		{Iteration: 4, Name: "build"},
+	}
+	if got, want := gateFailures(results), "lint (iteration 2), build (iteration 4)"; got != want {
+		t.Errorf("gateFailures() = %q, want %q", got, want)
+	}
+	if got := gateFailures(results[:1]); got != "" {
+		t.Errorf("gateFailures() with no failures = %q, want empty", got)
+	}
+}
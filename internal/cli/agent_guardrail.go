@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/scan"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// needsReviewTag is added to balls whose work was held back by a guardrail
+const needsReviewTag = "needs-review"
+
+// enforceDiffLimit checks the iteration's diff against the project's diff_limit.
+// When the limit is exceeded the work is isolated away from the working copy and
+// the balls completed this iteration are blocked with the needs-review tag.
+// Returns true if the limit was exceeded and the auto-commit must be skipped.
+func enforceDiffLimit(projectDir, sessionID, storageID, ballID string, snap *session.IterationSnapshot) bool {
+	limit, err := session.GetProjectDiffLimit(projectDir)
+	if err != nil || limit == nil {
+		return false
+	}
+	if snap == nil || snap.Revision == "" {
+		// Nothing to diff against
+		return false
+	}
+
+	backend := vcsBackendForProject(projectDir)
+	files, lines, err := backend.DiffStat(projectDir, snap.Revision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to compute diff size: %v\n", err)
+		return false
+	}
+	if !limit.Exceeded(files, lines) {
+		return false
+	}
+
+	summary := fmt.Sprintf("diff of %d files/%d lines exceeds limit (max %s)", files, lines, formatDiffLimit(limit))
+	fmt.Println()
+	fmt.Printf("🛑 Iteration %s, skipping auto-commit\n", summary)
+
+	if err := backend.DescribeWorkingCopy(projectDir, "NEEDS REVIEW: "+summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to describe working copy: %v\n", err)
+	}
+
+	// Isolate before touching balls: the reset may rewrite tracked .juggle files
+	isolatedRev, err := backend.IsolateAndReset(projectDir, snap.Revision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to isolate work: %v\n", err)
+	} else if isolatedRev != "" {
+		fmt.Printf("✓ Isolated work in revision: %s\n", isolatedRev)
+		summary = fmt.Sprintf("%s, isolated in %s", summary, isolatedRev)
+	}
+
+	marked, err := markBallsNeedsReview(projectDir, sessionID, ballID, snap, "needs review: "+summary)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to mark balls for review: %v\n", err)
+	}
+	for _, id := range marked {
+		fmt.Printf("⏸  Ball %s blocked for review\n", id)
+	}
+
+	logDiffLimitToProgress(projectDir, storageID, summary)
+	return true
+}
+
+// markBallsNeedsReview blocks the balls whose work was held back and tags them needs-review.
+// A specific ballID is always marked; otherwise balls that reached complete since the
+// snapshot are marked, falling back to in-progress balls. Returns the IDs of marked balls.
+func markBallsNeedsReview(projectDir, sessionID, ballID string, snap *session.IterationSnapshot, reason string) ([]string, error) {
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := make(map[string]session.BallState, len(snap.Balls))
+	for _, saved := range snap.Balls {
+		before[saved.ID] = saved.State
+	}
+
+	var targets []*session.Ball
+	for _, ball := range balls {
+		if ballID != "" {
+			if ball.ID == ballID || ball.ShortID() == ballID {
+				targets = append(targets, ball)
+			}
+			continue
+		}
+		if ball.State == session.StateComplete && before[ball.ID] != session.StateComplete {
+			targets = append(targets, ball)
+		}
+	}
+	if len(targets) == 0 && ballID == "" {
+		for _, ball := range balls {
+			if ball.State == session.StateInProgress {
+				targets = append(targets, ball)
+			}
+		}
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	marked := make([]string, 0, len(targets))
+	for _, ball := range targets {
+		ball.ForceSetState(session.StateBlocked)
+		ball.BlockedReason = reason
+		ball.CompletedAt = nil
+		ball.AddTag(needsReviewTag)
+		if err := store.UpdateBall(ball); err != nil {
+			return marked, fmt.Errorf("failed to update ball %s: %w", ball.ID, err)
+		}
+		marked = append(marked, ball.ShortID())
+	}
+	return marked, nil
+}
+
+// formatDiffLimit renders the configured limits for messages
+func formatDiffLimit(limit *session.DiffLimitConfig) string {
+	switch {
+	case limit.MaxFiles > 0 && limit.MaxLines > 0:
+		return fmt.Sprintf("%d files/%d lines", limit.MaxFiles, limit.MaxLines)
+	case limit.MaxFiles > 0:
+		return fmt.Sprintf("%d files", limit.MaxFiles)
+	default:
+		return fmt.Sprintf("%d lines", limit.MaxLines)
+	}
+}
+
+// logDiffLimitToProgress logs a diff limit violation to the session's progress file
+func logDiffLimitToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[DIFF_LIMIT] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
+
+// enforceCommitGates runs the project's commit gates on the iteration's diff.
+// On a hit the findings are logged (secrets redacted), the affected balls are
+// blocked for review, and the reason is returned so the caller can abort the
+// commit and stop the run. The work is left uncommitted in the working copy so
+// no secret is ever written to history. Returns "" if all gates pass.
+func enforceCommitGates(projectDir, sessionID, storageID, ballID string, snap *session.IterationSnapshot) string {
+	gates, err := session.GetProjectCommitGates(projectDir)
+	if err != nil || !gates.Enabled() {
+		return ""
+	}
+	if snap == nil || snap.Revision == "" {
+		return ""
+	}
+
+	diff, err := vcsBackendForProject(projectDir).Diff(projectDir, snap.Revision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to compute diff for commit gates: %v\n", err)
+		return ""
+	}
+
+	files := scan.ParseUnifiedDiff(diff)
+
+	var secrets []scan.SecretFinding
+	if gates.SecretScan {
+		secrets = scan.ScanSecrets(files)
+	}
+	missingLicense := scan.MissingLicenseHeaders(files, gates.LicenseHeader, gates.NeedsLicenseHeader)
+
+	if len(secrets) == 0 && len(missingLicense) == 0 {
+		return ""
+	}
+
+	fmt.Println()
+	for _, f := range secrets {
+		msg := fmt.Sprintf("secret (%s) in %s:%d: %s", f.Rule, f.Path, f.Line, f.Redacted)
+		fmt.Printf("🛑 Commit gate: %s\n", msg)
+		logCommitGateToProgress(projectDir, storageID, msg)
+	}
+	for _, path := range missingLicense {
+		msg := fmt.Sprintf("missing license header in new file %s", path)
+		fmt.Printf("🛑 Commit gate: %s\n", msg)
+		logCommitGateToProgress(projectDir, storageID, msg)
+	}
+
+	var parts []string
+	if len(secrets) > 0 {
+		parts = append(parts, fmt.Sprintf("%d possible secret(s)", len(secrets)))
+	}
+	if len(missingLicense) > 0 {
+		parts = append(parts, fmt.Sprintf("%d file(s) missing license header", len(missingLicense)))
+	}
+	reason := "needs review: commit gate failed: " + strings.Join(parts, ", ")
+	fmt.Printf("⚠️  Commit aborted, work left uncommitted for review\n")
+
+	marked, err := markBallsNeedsReview(projectDir, sessionID, ballID, snap, reason)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to mark balls for review: %v\n", err)
+	}
+	for _, id := range marked {
+		fmt.Printf("⏸  Ball %s blocked for review\n", id)
+	}
+
+	return reason
+}
+
+// logCommitGateToProgress logs a commit gate finding to the session's progress file
+func logCommitGateToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[COMMIT_GATE] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
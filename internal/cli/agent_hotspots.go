@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/hotspot"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// maxHotPaths caps the paths recorded on a ball, hottest first
+const maxHotPaths = 5
+
+var (
+	hotspotsSince  time.Duration
+	hotspotsDryRun bool
+)
+
+// agentHotspotsCmd weights balls by the churn and ownership of the code they affect
+var agentHotspotsCmd = &cobra.Command{
+	Use:   "hotspots [session]",
+	Short: "Score balls by recent churn and CODEOWNERS of the paths they affect",
+	Long: `Find the paths each ball affects and record how hot they are, so agent runs
+work on balls touching frequently changed, owned code first.
+
+A ball's paths are the files and directories named in its title, context,
+acceptance criteria and plan that exist in the project, plus files agents
+touched while working on it. Each path scores one point per commit touching it
+within --since, and a bonus when CODEOWNERS (.github/, root or docs/) assigns
+it an owner.
+
+Within the same state and priority, agent runs order balls by this score, and
+the hottest paths are included in the agent prompt. Run it again to refresh the
+scores as the code changes.
+
+Ball Selection:
+- No argument: Incomplete balls in current repo
+- Session arg: Incomplete balls with that session tag
+- --all flag: Incomplete balls from all discovered projects
+
+Examples:
+  juggle agent hotspots
+  juggle agent hotspots my-feature --since 720h
+  juggle agent hotspots --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentHotspots,
+}
+
+func init() {
+	agentHotspotsCmd.Flags().DurationVar(&hotspotsSince, "since", 90*24*time.Hour, "Count commits made within this long")
+	agentHotspotsCmd.Flags().BoolVar(&hotspotsDryRun, "dry-run", false, "Print the scores without saving them")
+	agentCmd.AddCommand(agentHotspotsCmd)
+}
+
+// projectHotspotSignals are the churn and ownership signals of one project
+type projectHotspotSignals struct {
+	churn   map[string]int
+	owners  *hotspot.CodeOwners
+	touched map[string][]string // Files agents touched, by ball ID
+}
+
+func runAgentHotspots(cmd *cobra.Command, args []string) error {
+	var sessionID string
+	if len(args) > 0 {
+		sessionID = args[0]
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	balls, err := loadBallsForRefine(cwd, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	if len(balls) == 0 {
+		fmt.Println("No balls to analyze.")
+		return nil
+	}
+
+	since := time.Now().Add(-hotspotsSince)
+	signals := make(map[string]*projectHotspotSignals)
+	analyzed := 0
+	for _, ball := range balls {
+		projectSignals, ok := signals[ball.WorkingDir]
+		if !ok {
+			projectSignals = loadHotspotSignals(ball.WorkingDir, since)
+			signals[ball.WorkingDir] = projectSignals
+		}
+
+		result := analyzeBallHotspot(ball, projectSignals)
+		if !hotspotsDryRun {
+			if err := saveBallHotspot(ball, result); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save hotspot for %s: %v\n", ball.ShortID(), err)
+				continue
+			}
+		}
+		if result == nil {
+			fmt.Printf("  %s %s %s\n", ball.ShortID(), StyleDim.Render("no paths found"), StyleDim.Render(ball.Title))
+			continue
+		}
+		fmt.Printf("  %s score %d: %s %s\n", ball.ShortID(), result.Score, formatHotspot(result), StyleDim.Render(ball.Title))
+		analyzed++
+	}
+
+	if hotspotsDryRun {
+		fmt.Printf("\n%d of %d ball(s) have hot paths (dry run, nothing saved)\n", analyzed, len(balls))
+	} else {
+		fmt.Printf("\n✓ %d of %d ball(s) have hot paths\n", analyzed, len(balls))
+	}
+	return nil
+}
+
+// loadHotspotSignals gathers a project's commit churn since the given time,
+// its CODEOWNERS and the files agents touched per ball. Signals that can't be
+// read are left out with a warning.
+func loadHotspotSignals(projectDir string, since time.Time) *projectHotspotSignals {
+	signals := &projectHotspotSignals{touched: make(map[string][]string)}
+
+	churn, err := vcsBackendForProject(projectDir).Churn(projectDir, since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read commit history of %s: %v\n", projectDir, err)
+	}
+	signals.churn = churn
+
+	owners, err := hotspot.LoadCodeOwners(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load CODEOWNERS of %s: %v\n", projectDir, err)
+	}
+	signals.owners = owners
+
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return signals
+	}
+	touches, err := sessionStore.RecentFileTouches(since)
+	if err != nil {
+		return signals
+	}
+	for _, sessionTouches := range touches {
+		for _, touch := range sessionTouches {
+			if touch.BallID != "" {
+				signals.touched[touch.BallID] = append(signals.touched[touch.BallID], touch.Path)
+			}
+		}
+	}
+	return signals
+}
+
+// analyzeBallHotspot scores the paths the ball affects. It returns nil when
+// the ball names no paths in the project and agents haven't touched any.
+func analyzeBallHotspot(ball *session.Ball, signals *projectHotspotSignals) *session.BallHotspot {
+	text := []string{ball.Title, ball.Context}
+	text = append(text, ball.AcceptanceCriteria...)
+	text = append(text, ball.Plan...)
+	text = append(text, signals.touched[ball.ID]...)
+
+	paths := hotspot.ExtractPaths(ball.WorkingDir, strings.Join(text, "\n"))
+	if len(paths) == 0 {
+		return nil
+	}
+
+	result := hotspot.Analyze(paths, signals.churn, signals.owners)
+	hot := &session.BallHotspot{
+		Score:      result.Score,
+		Owners:     result.Owners(),
+		AnalyzedAt: time.Now(),
+	}
+	for _, p := range result.Paths[:min(maxHotPaths, len(result.Paths))] {
+		hot.Paths = append(hot.Paths, p.Path)
+	}
+	return hot
+}
+
+// saveBallHotspot records the hotspot analysis on the ball in its project's
+// store. A nil hotspot clears an earlier analysis.
+func saveBallHotspot(ball *session.Ball, hot *session.BallHotspot) error {
+	if hot == nil && ball.Hotspot == nil {
+		return nil
+	}
+	store, err := NewStoreForCommand(ball.WorkingDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	ball.SetHotspot(hot)
+	return store.UpdateBall(ball)
+}
+
+// formatHotspot summarizes a hotspot analysis for display
+func formatHotspot(hot *session.BallHotspot) string {
+	if len(hot.Paths) == 0 {
+		return "no paths found"
+	}
+	s := strings.Join(hot.Paths, ", ")
+	if len(hot.Owners) > 0 {
+		s += " (owners: " + strings.Join(hot.Owners, " ") + ")"
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/hotspot"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAnalyzeBallHotspot(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, name := range []string{"internal/cli/agent.go", "internal/cli/show.go", "docs/commands.md"} {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	owners, err := hotspot.ParseCodeOwners(strings.NewReader("/docs/ @writers\n"))
+	if err != nil {
+		t.Fatalf("ParseCodeOwners failed: %v", err)
+	}
+	signals := &projectHotspotSignals{
+		churn:   map[string]int{"internal/cli/agent.go": 7, "docs/commands.md": 1},
+		owners:  owners,
+		touched: map[string][]string{"proj-a1b2": {"internal/cli/show.go"}},
+	}
+
+	ball := &session.Ball{
+		ID:                 "proj-a1b2",
+		WorkingDir:         projectDir,
+		Title:              "Fix agent.go output",
+		AcceptanceCriteria: []string{"internal/cli/agent.go prints the summary", "Documented in docs/commands.md"},
+	}
+	hot := analyzeBallHotspot(ball, signals)
+	if hot == nil {
+		t.Fatal("Expected a hotspot")
+	}
+	if hot.Score != 7+1+hotspot.OwnedWeight {
+		t.Errorf("Expected score %d, got %d", 7+1+hotspot.OwnedWeight, hot.Score)
+	}
+	wantPaths := []string{"internal/cli/agent.go", "docs/commands.md", "internal/cli/show.go"}
+	if !reflect.DeepEqual(hot.Paths, wantPaths) {
+		t.Errorf("Expected paths %v, got %v", wantPaths, hot.Paths)
+	}
+	if !reflect.DeepEqual(hot.Owners, []string{"@writers"}) {
+		t.Errorf("Expected owners [@writers], got %v", hot.Owners)
+	}
+
+	unrelated := &session.Ball{ID: "proj-c3d4", WorkingDir: projectDir, Title: "Think about naming"}
+	if hot := analyzeBallHotspot(unrelated, signals); hot != nil {
+		t.Errorf("Expected no hotspot for a ball naming no paths, got %+v", hot)
+	}
+}
+
+func TestFormatHotspot(t *testing.T) {
+	hot := &session.BallHotspot{Score: 9, Paths: []string{"internal/vcs", "README.md"}, Owners: []string{"@vcs-team"}}
+	if got := formatHotspot(hot); got != "internal/vcs, README.md (owners: @vcs-team)" {
+		t.Errorf("Unexpected format: %q", got)
+	}
+	if got := formatHotspot(&session.BallHotspot{}); got != "no paths found" {
+		t.Errorf("Unexpected format for no paths: %q", got)
+	}
+}
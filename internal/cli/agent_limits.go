@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// daemonAgentLimits returns the resource limits for the agents a daemon
+// starts, or nil when agent_limits isn't configured
+func daemonAgentLimits() (*provider.ResourceLimits, error) {
+	cfg, err := session.GetGlobalAgentLimitsWithOptions(GetConfigOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agent limits: %w", err)
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	return resourceLimits(cfg)
+}
+
+// resourceLimits checks the configured limits and converts them for the
+// provider, returning nil when they set nothing
+func resourceLimits(cfg *session.AgentLimitsConfig) (*provider.ResourceLimits, error) {
+	if cfg.Nice < 0 || cfg.Nice > 19 {
+		return nil, fmt.Errorf("invalid agent_limits nice: %d (must be 1-19, or 0 to leave unchanged)", cfg.Nice)
+	}
+	if cfg.IOClass != "" && cfg.IOClass != provider.IOClassIdle && cfg.IOClass != provider.IOClassBestEffort {
+		return nil, fmt.Errorf("invalid agent_limits io_class: %q (must be %q or %q)", cfg.IOClass, provider.IOClassIdle, provider.IOClassBestEffort)
+	}
+	limits := &provider.ResourceLimits{Nice: cfg.Nice, IOClass: cfg.IOClass}
+	if cfg.MaxMemory != "" {
+		bytes, err := provider.ParseMemorySize(cfg.MaxMemory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid agent_limits max_memory: %w", err)
+		}
+		limits.MaxMemory = bytes
+	}
+	if *limits == (provider.ResourceLimits{}) {
+		return nil, nil
+	}
+	return limits, nil
+}
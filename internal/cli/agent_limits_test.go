@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestResourceLimits(t *testing.T) {
+	limits, err := resourceLimits(&session.AgentLimitsConfig{Nice: 10, IOClass: "idle", MaxMemory: "4G"})
+	if err != nil {
+		t.Fatalf("resourceLimits failed: %v", err)
+	}
+	want := provider.ResourceLimits{Nice: 10, IOClass: provider.IOClassIdle, MaxMemory: 4 << 30}
+	if limits == nil || *limits != want {
+		t.Errorf("expected %+v, got %+v", want, limits)
+	}
+
+	if limits, err := resourceLimits(&session.AgentLimitsConfig{}); err != nil || limits != nil {
+		t.Errorf("expected no limits for an empty config, got %+v, %v", limits, err)
+	}
+
+	for _, bad := range []session.AgentLimitsConfig{
+		{Nice: 20},
+		{Nice: -5},
+		{IOClass: "realtime"},
+		{MaxMemory: "plenty"},
+	} {
+		if _, err := resourceLimits(&bad); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
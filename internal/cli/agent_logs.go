@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// logFollowInterval is how often juggle agent logs -f checks the log for new output
+const logFollowInterval = 500 * time.Millisecond
+
+var (
+	logsFollow bool
+	logsLines  int
+	logsSince  string
+)
+
+var agentLogsCmd = &cobra.Command{
+	Use:   "logs <session>",
+	Short: "Show the output of an agent daemon",
+	Long: `Show the agent.log of a session's daemon, with its iteration separators
+highlighted. Without --follow it prints the last --lines lines and exits.
+
+--since starts at the first iteration (or, for loops in juggle daemon, the
+first log line) at or after the given time. It accepts a duration (30m, 2h,
+1d) or a date (YYYY-MM-DD). Log lines only carry the time of day, so they are
+dated back from when the log was last written.
+
+With --follow the log keeps printing as the daemon writes it, like tail -f,
+until Ctrl+C. A daemon started afterwards restarts the log, and it is followed
+from the top.
+
+Examples:
+  juggle agent logs my-feature
+  juggle agent logs my-feature -f
+  juggle agent logs my-feature --since 1h --lines 0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentLogs,
+}
+
+func init() {
+	agentLogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new output as the daemon writes it")
+	agentLogsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "Number of lines to show from the end of the log (0 = all)")
+	agentLogsCmd.Flags().StringVar(&logsSince, "since", "", "Start at the first iteration within a duration (30m, 2h, 1d) or since a date (YYYY-MM-DD)")
+	agentCmd.AddCommand(agentLogsCmd)
+}
+
+func runAgentLogs(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	projectDir, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if logsLines < 0 {
+		return fmt.Errorf("--lines must be 0 or more")
+	}
+	var since time.Time
+	if logsSince != "" {
+		if since, err = parseSince(logsSince, time.Now()); err != nil {
+			return err
+		}
+	}
+	logPath := filepath.Join(projectDir, ".juggle", "sessions", sessionStorageID(sessionID), "agent.log")
+
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	info, err := os.Stat(logPath)
+	if os.IsNotExist(err) {
+		if !logsFollow {
+			return fmt.Errorf("no agent log for session %s (start a daemon with: juggle agent run %s --daemon)", sessionID, sessionID)
+		}
+		fmt.Println(StyleDim.Render(fmt.Sprintf("Waiting for session %s's daemon to start logging... (Ctrl+C to stop)", sessionID)))
+		return followLog(ctx, os.Stdout, logPath, 0)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read agent log: %w", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to read agent log: %w", err)
+	}
+	// A partial last line is printed once the daemon finishes it
+	complete := data[:bytes.LastIndexByte(data, '\n')+1]
+	lines := splitLogLines(complete)
+	for _, line := range selectLogLines(lines, logLineTimes(lines, info.ModTime()), since, logsLines) {
+		fmt.Println(styleLogLine(line))
+	}
+
+	if !logsFollow {
+		return nil
+	}
+	return followLog(ctx, os.Stdout, logPath, int64(len(complete)))
+}
+
+// followLog prints lines appended to the log from offset on until ctx is
+// cancelled. A log that shrinks was restarted by a new daemon and is printed
+// from the top.
+func followLog(ctx context.Context, w io.Writer, logPath string, offset int64) error {
+	var partial []byte
+	for {
+		file, err := os.Open(logPath)
+		if err == nil {
+			if info, err := file.Stat(); err == nil && info.Size() < offset {
+				fmt.Fprintln(w, StyleDim.Render("── Log restarted ──"))
+				offset, partial = 0, nil
+			}
+			if _, err := file.Seek(offset, io.SeekStart); err == nil {
+				data, _ := io.ReadAll(file)
+				offset += int64(len(data))
+				partial = append(partial, data...)
+				if end := bytes.LastIndexByte(partial, '\n'); end >= 0 {
+					for _, line := range splitLogLines(partial[:end+1]) {
+						fmt.Fprintln(w, styleLogLine(line))
+					}
+					partial = append([]byte(nil), partial[end+1:]...)
+				}
+			}
+			file.Close()
+		}
+		if !sleepContext(ctx, logFollowInterval) {
+			return nil
+		}
+	}
+}
+
+// splitLogLines splits complete log lines
+func splitLogLines(data []byte) []string {
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+var (
+	// logClockPrefix is the time loops in juggle daemon start their log lines with
+	logClockPrefix = regexp.MustCompile(`^(\d{2}:\d{2}:\d{2}) `)
+	// logIterationHeader is the separator agent runs start each iteration with
+	logIterationHeader = regexp.MustCompile(`^═+ Iteration \d+/\d+.*?(?: · (\d{2}:\d{2}:\d{2}))? ═+$`)
+)
+
+// logLineClock returns the time of day a log line carries, if any
+func logLineClock(line string) (string, bool) {
+	if m := logClockPrefix.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+	if m := logIterationHeader.FindStringSubmatch(line); m != nil && m[1] != "" {
+		return m[1], true
+	}
+	return "", false
+}
+
+// logLineTimes dates the lines that carry a time of day; the rest get the
+// zero time. The log runs forward in time and was last written at end, so
+// each time is dated on the latest day that keeps it before the lines after it.
+func logLineTimes(lines []string, end time.Time) []time.Time {
+	times := make([]time.Time, len(lines))
+	latest := end
+	for i := len(lines) - 1; i >= 0; i-- {
+		clock, ok := logLineClock(lines[i])
+		if !ok {
+			continue
+		}
+		tod, err := time.ParseInLocation("15:04:05", clock, latest.Location())
+		if err != nil {
+			continue
+		}
+		t := time.Date(latest.Year(), latest.Month(), latest.Day(), tod.Hour(), tod.Minute(), tod.Second(), 0, latest.Location())
+		if t.After(latest) {
+			t = t.AddDate(0, 0, -1)
+		}
+		times[i] = t
+		latest = t
+	}
+	return times
+}
+
+// selectLogLines picks the lines from the first dated one at or after since
+// (all lines when since is zero), then the last limit of those (all when 0)
+func selectLogLines(lines []string, times []time.Time, since time.Time, limit int) []string {
+	if !since.IsZero() {
+		start := len(lines)
+		for i, t := range times {
+			if !t.IsZero() && !t.Before(since) {
+				start = i
+				break
+			}
+		}
+		lines = lines[start:]
+	}
+	if limit > 0 && len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	return lines
+}
+
+var logIterationStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true) // Cyan bold
+
+// styleLogLine highlights iteration separators
+func styleLogLine(line string) string {
+	switch {
+	case logIterationHeader.MatchString(line), strings.Contains(line, "▶ Iteration "):
+		return logIterationStyle.Render(line)
+	case strings.Trim(line, "═") == "" && line != "":
+		return StyleDim.Render(line)
+	default:
+		return line
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogLineTimes(t *testing.T) {
+	lines := []string{
+		"════════════════ Iteration 1/3 · 23:58:00 ════════════════",
+		"working...",
+		"════════════════ Iteration 2/3 · 00:05:10 ════════════════",
+		"00:06:00 ✓ Ball p-1 complete: Login",
+		"════════════════ Iteration 3/3 (planning) ════════════════",
+	}
+	end := time.Date(2026, 3, 2, 0, 10, 0, 0, time.Local)
+	times := logLineTimes(lines, end)
+
+	want := []time.Time{
+		time.Date(2026, 3, 1, 23, 58, 0, 0, time.Local), // Before midnight, the day before
+		{},
+		time.Date(2026, 3, 2, 0, 5, 10, 0, time.Local),
+		time.Date(2026, 3, 2, 0, 6, 0, 0, time.Local),
+		{},
+	}
+	for i := range want {
+		if !times[i].Equal(want[i]) {
+			t.Errorf("Line %d: expected %v, got %v", i, want[i], times[i])
+		}
+	}
+}
+
+func TestSelectLogLines(t *testing.T) {
+	lines := []string{"header", "it 1", "out 1", "it 2", "out 2a", "out 2b"}
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	times := []time.Time{{}, day.Add(time.Hour), {}, day.Add(2 * time.Hour), {}, {}}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		limit int
+		want  []string
+	}{
+		{"all", time.Time{}, 0, lines},
+		{"last lines", time.Time{}, 2, []string{"out 2a", "out 2b"}},
+		{"since an iteration", day.Add(90 * time.Minute), 0, []string{"it 2", "out 2a", "out 2b"}},
+		{"since and lines", day.Add(30 * time.Minute), 3, []string{"it 2", "out 2a", "out 2b"}},
+		{"since after the end", day.Add(3 * time.Hour), 0, []string{}},
+	}
+	for _, tt := range tests {
+		got := selectLogLines(lines, times, tt.since, tt.limit)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestLogLineClock(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"════ Iteration 2/5 · 14:03:22 ════", "14:03:22"},
+		{"15:04:05 ▶ Iteration 2/5", "15:04:05"},
+		{"════ Iteration 1/5 (planning) ════", ""},
+		{"Ran tests at 14:03:22", ""},
+	}
+	for _, tt := range tests {
+		got, ok := logLineClock(tt.line)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("logLineClock(%q) = %q, %v; want %q", tt.line, got, ok, tt.want)
+		}
+	}
+}
+
+func TestFollowLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "agent.log")
+	if err := os.WriteFile(logPath, []byte("old line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- followLog(ctx, &out, logPath, int64(len("old line\n"))) }()
+
+	appendLog := func(text string) {
+		f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(text)
+		f.Close()
+		time.Sleep(2 * logFollowInterval)
+	}
+	appendLog("new line\nhalf a ")
+	appendLog("line\n")
+	// A new daemon truncates the log
+	if err := os.WriteFile(logPath, []byte("fresh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * logFollowInterval)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("followLog failed: %v", err)
+	}
+
+	got := out.String()
+	if strings.Contains(got, "old line") {
+		t.Errorf("Expected only lines after the offset, got %q", got)
+	}
+	for _, want := range []string{"new line\n", "half a line\n", "Log restarted", "fresh\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in output, got %q", want, got)
+		}
+	}
+}
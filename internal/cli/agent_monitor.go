@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/tui"
+	"github.com/ohare93/juggle/pkg/daemonapi"
+)
+
+// envDaemonAPIToken holds the token of a remote daemon API, used when
+// --remote-token isn't given
+const envDaemonAPIToken = "JUGGLE_DAEMON_API_TOKEN"
+
+// pickDaemonToMonitor lists the daemons running in any discovered project
+// and opens the monitor TUI on the one picked
+func pickDaemonToMonitor() error {
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	projects, err := session.DiscoverProjects(config)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+	// The current project counts even outside the search paths
+	if cwd, err := GetWorkingDir(); err == nil && !slices.Contains(projects, cwd) {
+		projects = append(projects, cwd)
+	}
+
+	picker := tui.NewDaemonPickerModel(func() []tui.RunningDaemon {
+		return runningDaemons(projects)
+	})
+	final, err := tea.NewProgram(picker, tea.WithAltScreen()).Run()
+	if err != nil {
+		return fmt.Errorf("TUI error: %w", err)
+	}
+	selected := final.(tui.DaemonPickerModel).Selected()
+	if selected == nil {
+		return nil
+	}
+	return launchMonitorTUI(selected.ProjectDir, selected.SessionID, selected.StorageID, true)
+}
+
+// runningDaemons returns the daemons running in the projects, those going
+// longest first
+func runningDaemons(projects []string) []tui.RunningDaemon {
+	var daemons []tui.RunningDaemon
+	for _, projectDir := range projects {
+		for _, run := range projectAgentRuns(projectDir) {
+			if run.State != runStateDaemon && run.State != runStatePaused {
+				continue
+			}
+			daemons = append(daemons, tui.RunningDaemon{
+				ProjectDir:    run.ProjectDir,
+				SessionID:     run.SessionID,
+				StorageID:     sessionStorageID(run.SessionID),
+				PID:           run.PID,
+				Paused:        run.State == runStatePaused,
+				BallID:        run.BallID,
+				BallTitle:     run.BallTitle,
+				Iteration:     run.Iteration,
+				MaxIterations: run.MaxIterations,
+				Model:         run.Model,
+				StartedAt:     run.StartedAt,
+			})
+		}
+	}
+	slices.SortStableFunc(daemons, func(a, b tui.RunningDaemon) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return daemons
+}
+
+// monitorRemoteDaemon opens the monitor TUI on a loop of the daemon whose
+// API listens at addr: the loop of the session, or one picked from the
+// daemon's loops when sessionID is empty
+func monitorRemoteDaemon(ctx context.Context, addr, sessionID string) error {
+	token := agentRemoteToken
+	if token == "" {
+		token = os.Getenv(envDaemonAPIToken)
+	}
+	if token == "" {
+		return fmt.Errorf("--remote needs the daemon's API token: pass --remote-token or set %s (it is in ~/.juggle/daemon-api-token on the daemon's machine)", envDaemonAPIToken)
+	}
+	client, err := daemon.DialRemoteAPI(addr, token)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	daemons, err := remoteDaemons(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to reach the daemon API at %s: %w", addr, err)
+	}
+
+	var selected *tui.RunningDaemon
+	if sessionID != "" {
+		storageID := sessionStorageID(sessionID)
+		var matches []tui.RunningDaemon
+		for _, d := range daemons {
+			if d.StorageID == storageID {
+				matches = append(matches, d)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return fmt.Errorf("no loop runs for session %s at %s", sessionID, addr)
+		case 1:
+			selected = &matches[0]
+		default:
+			var projects []string
+			for _, d := range matches {
+				projects = append(projects, d.ProjectDir)
+			}
+			return fmt.Errorf("session %s runs in several projects at %s (%s); run without a session to pick one", sessionID, addr, strings.Join(projects, ", "))
+		}
+	} else {
+		picker := tui.NewDaemonPickerModel(func() []tui.RunningDaemon {
+			daemons, _ := remoteDaemons(ctx, client)
+			return daemons
+		})
+		final, err := tea.NewProgram(picker, tea.WithAltScreen()).Run()
+		if err != nil {
+			return fmt.Errorf("TUI error: %w", err)
+		}
+		if selected = final.(tui.DaemonPickerModel).Selected(); selected == nil {
+			return nil
+		}
+	}
+
+	registerCustomProviders()
+	remote := tui.RemoteDaemon{Addr: addr, Token: token, ProjectDir: selected.ProjectDir}
+	_, err = tea.NewProgram(tui.InitialRemoteMonitorModel(remote, selected.StorageID), tea.WithAltScreen()).Run()
+	return err
+}
+
+// remoteDaemons lists the loops a daemon API serves, as the daemon picker
+// shows them
+func remoteDaemons(ctx context.Context, client *daemon.APIClient) ([]tui.RunningDaemon, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := client.ListLoops(ctx, &daemonapi.ListLoopsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var daemons []tui.RunningDaemon
+	for _, loop := range resp.GetLoops() {
+		state := daemon.StateFromProto(loop.GetState())
+		sessionID := loop.GetSessionId()
+		if sessionID == "_all" {
+			sessionID = "all"
+		}
+		daemons = append(daemons, tui.RunningDaemon{
+			ProjectDir:    loop.GetProjectDir(),
+			SessionID:     sessionID,
+			StorageID:     loop.GetSessionId(),
+			Paused:        state.Paused,
+			BallID:        state.CurrentBallID,
+			BallTitle:     state.CurrentBallTitle,
+			Iteration:     state.Iteration,
+			MaxIterations: state.MaxIterations,
+			Model:         state.Model,
+			StartedAt:     state.StartedAt,
+		})
+	}
+	return daemons, nil
+}
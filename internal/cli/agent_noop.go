@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// noOpNudge is appended to the prompt of the iteration after the agent spun
+// its wheels for too long
+const noOpNudge = "Your last %d iterations changed no files, updated no balls and logged no real progress. " +
+	"Don't repeat the same approach: pick one ball, make a concrete change and log what you did. " +
+	"If you can't make progress on it, mark it blocked with the reason."
+
+// progressTagPattern matches juggle's own progress entries, e.g. "[RATE_LIMIT] ..."
+var progressTagPattern = regexp.MustCompile(`^\[[A-Z][A-Z0-9_]*\]`)
+
+// progressTimestampPattern matches the timestamp `juggle progress append` adds
+var progressTimestampPattern = regexp.MustCompile(`^\[\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\]\s*`)
+
+// progressAnnouncementPattern matches entries that only announce work is
+// starting or continuing, without saying what was done
+var progressAnnouncementPattern = regexp.MustCompile(`(?i)^(starting|started|beginning|continuing|resuming|picking up|working on|looking at|iteration \d+)\b`)
+
+// noOpTracker counts consecutive iterations that did nothing and changes the
+// run's strategy once there are no_op.max_consecutive of them in a row
+type noOpTracker struct {
+	settings    *session.NoOpConfig
+	consecutive int
+	nudge       string // Appended to the next iteration's prompt, then cleared
+	model       string // Larger model the rest of the run escalated to
+}
+
+// newNoOpTracker loads the no-op settings from project config
+func newNoOpTracker(projectDir string) *noOpTracker {
+	settings, err := session.GetProjectNoOp(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load no-op settings: %v\n", err)
+	}
+	return &noOpTracker{settings: settings}
+}
+
+// message returns the user message for the next iteration's prompt, with the
+// nudge appended once after the agent was found spinning
+func (t *noOpTracker) message(userMessage string) string {
+	nudge := t.nudge
+	t.nudge = ""
+	if nudge == "" {
+		return userMessage
+	}
+	if userMessage == "" {
+		return nudge
+	}
+	return userMessage + "\n\n" + nudge
+}
+
+// selection moves the iteration to the model the run escalated to, if any
+func (t *noOpTracker) selection(selection *ModelSelection) *ModelSelection {
+	if t.model == "" {
+		return selection
+	}
+	return &ModelSelection{
+		Model:      t.model,
+		Reason:     "escalated after consecutive no-op iterations",
+		BallsCount: selection.BallsCount,
+	}
+}
+
+// record counts an iteration that ran to the end. After enough no-ops in a
+// row it applies the configured action and returns a reason when the run
+// should stop.
+func (t *noOpTracker) record(config AgentLoopConfig, storageID string, noOp bool, model string) string {
+	if !noOp {
+		t.consecutive = 0
+		return ""
+	}
+	t.consecutive++
+	fmt.Printf("💤 No-op iteration: no file changes, ball updates or progress (%d in a row)\n", t.consecutive)
+
+	action := t.settings.Strategy()
+	if action == "off" || t.consecutive < t.settings.Limit() {
+		return ""
+	}
+	count := t.consecutive
+	t.consecutive = 0
+
+	if action == "escalate" {
+		if next := nextLargerModel(model); next != "" && config.Model == "" {
+			t.model = next
+			fmt.Printf("⬆️  %d no-op iterations in a row, escalating to %s\n", count, next)
+			logNoOpToProgress(config.ProjectDir, storageID, fmt.Sprintf("%d consecutive no-op iterations at %s, escalated to %s", count, model, next))
+			return ""
+		}
+		// Nothing larger to move to, so stop rather than keep spinning
+		action = "stop"
+	}
+	if action == "stop" {
+		reason := fmt.Sprintf("%d consecutive no-op iterations", count)
+		logNoOpToProgress(config.ProjectDir, storageID, "Agent run stopped: "+reason)
+		return reason
+	}
+
+	t.nudge = fmt.Sprintf(noOpNudge, count)
+	fmt.Printf("👉 %d no-op iterations in a row, nudging the agent\n", count)
+	logNoOpToProgress(config.ProjectDir, storageID, fmt.Sprintf("%d consecutive no-op iterations, nudged the agent", count))
+	return ""
+}
+
+// iterationWasNoOp reports whether an iteration changed no files, no session
+// ball states and logged no progress beyond boilerplate. Anything it can't
+// check, such as files outside a repository, counts as a change.
+func iterationWasNoOp(projectDir, sessionID string, snap *session.IterationSnapshot, progressAdded string) bool {
+	if snap == nil || snap.Revision == "" || hasSubstantiveProgress(progressAdded) {
+		return false
+	}
+
+	files, err := vcsBackendForProject(projectDir).ChangedFiles(projectDir, snap.Revision)
+	if err != nil || len(files) > 0 {
+		return false
+	}
+
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return false
+	}
+	before := make(map[string]session.BallState, len(snap.Balls))
+	for _, saved := range snap.Balls {
+		before[saved.ID] = saved.State
+	}
+	for _, ball := range balls {
+		if state, known := before[ball.ID]; !known || state != ball.State {
+			return false
+		}
+	}
+	return true
+}
+
+// hasSubstantiveProgress reports whether progress added during an iteration
+// says more than boilerplate: juggle's own tagged entries, and agent entries
+// of a couple of words or that only announce work starting
+func hasSubstantiveProgress(added string) bool {
+	for _, line := range strings.Split(added, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || progressTagPattern.MatchString(line) {
+			continue
+		}
+		text := progressTimestampPattern.ReplaceAllString(line, "")
+		words := len(strings.Fields(text))
+		if words < 3 || (words <= 6 && progressAnnouncementPattern.MatchString(text)) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// loadProgressText returns the session's progress, or "" if it can't be read
+func loadProgressText(store *session.SessionStore, storageID string) string {
+	progress, err := store.LoadProgress(storageID)
+	if err != nil {
+		return ""
+	}
+	return progress
+}
+
+// logNoOpToProgress logs a no-op strategy change to the session's progress file
+func logNoOpToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[NO_OP] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,20 @@
+package cli
+
+import "testing"
+
+func TestHasSubstantiveProgress(t *testing.T) {
+	for added, want := range map[string]bool{
+		"":                                      false,
+		"[RATE_LIMIT] Rate limited, waiting 1m": false,
+		"[2026-01-02 15:04:05] Starting work\n": false,
+		"[2026-01-02 15:04:05] Working on ball juggle-5\n":                            false,
+		"[2026-01-02 15:04:05] ok\n":                                                  false,
+		"[2026-01-02 15:04:05] Added retry to the fetch client and covered it\n":      true,
+		"Continuing\nFound the parser drops trailing commas in nested arrays\n":       true,
+		"[2026-01-02 15:04:05] Working on ball juggle-5, the parser now handles it\n": true,
+	} {
+		if got := hasSubstantiveProgress(added); got != want {
+			t.Errorf("hasSubstantiveProgress(%q) = %v, want %v", added, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ohare93/juggle/internal/notify"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// runNotifier sends one agent loop's lifecycle events to the project's
+// webhooks and, when configured, desktop notifications and email; with none
+// configured it does nothing
+type runNotifier struct {
+	*notify.Notifier
+	config     AgentLoopConfig
+	outputPath string // The session's last_output.txt
+}
+
+// newRunNotifier starts delivering to the webhooks in project config and the
+// desktop notifications and email in global config
+func newRunNotifier(config AgentLoopConfig, outputPath string) *runNotifier {
+	hooks, err := session.GetProjectWebhooks(config.ProjectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load webhooks: %v\n", err)
+	}
+	for _, hook := range hooks {
+		switch hook.Format {
+		case "", session.WebhookFormatJSON, session.WebhookFormatSlack, session.WebhookFormatDiscord:
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: webhook %s has unknown format %q, sending JSON\n", hook.URL, hook.Format)
+		}
+		warnUnknownEvents("webhook "+hook.URL, hook.Events)
+	}
+	desktop, err := session.GetGlobalDesktopNotificationsWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load desktop notification settings: %v\n", err)
+	}
+	if desktop != nil {
+		warnUnknownEvents("desktop_notifications", desktop.Events)
+	}
+	email, err := session.GetGlobalEmailWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load email settings: %v\n", err)
+	}
+	if email != nil {
+		warnUnknownEvents("email", email.Events)
+	}
+	channels := append(notify.Webhooks(hooks), notify.Desktop(desktop), notify.Email(email))
+	return &runNotifier{Notifier: notify.New(channels...), config: config, outputPath: outputPath}
+}
+
+// warnUnknownEvents warns about event types a channel's config lists that
+// no event has
+func warnUnknownEvents(channel string, events []string) {
+	for _, event := range events {
+		if !slices.Contains(notify.EventTypes, event) {
+			fmt.Fprintf(os.Stderr, "Warning: %s lists unknown event %q\n", channel, event)
+		}
+	}
+}
+
+// send fills in the run's project and session and queues the event
+func (r *runNotifier) send(event notify.Event) {
+	event.Project = r.config.ProjectDir
+	event.Session = r.config.SessionID
+	event.Daemon = r.config.DaemonMode
+	r.Send(event)
+}
+
+// start reports the run starting
+func (r *runNotifier) start() {
+	r.send(notify.Event{Type: notify.EventRunStart, MaxIterations: r.config.MaxIterations})
+}
+
+// ballChanges reports the balls completed or blocked during an iteration
+func (r *runNotifier) ballChanges(iteration int, snap *session.IterationSnapshot) {
+	if !r.Enabled() {
+		return
+	}
+	completed, _ := ballsCompletedSince(r.config.ProjectDir, r.config.SessionID, r.config.BallID, snap)
+	for _, ball := range completed {
+		r.send(notify.Event{Type: notify.EventBallComplete, Iteration: iteration, BallID: ball.ID, Title: ball.Title})
+	}
+	blocked, _ := ballsEnteredStateSince(r.config.ProjectDir, r.config.SessionID, r.config.BallID, snap, session.StateBlocked)
+	for _, ball := range blocked {
+		r.send(notify.Event{Type: notify.EventBallBlocked, Iteration: iteration, BallID: ball.ID, Title: ball.Title, Reason: ball.BlockedReason, OutputFile: r.outputPath})
+	}
+}
+
+// rateLimitWait reports the run waiting out a rate limit before retrying
+func (r *runNotifier) rateLimitWait(iteration int, wait time.Duration) {
+	r.send(notify.Event{Type: notify.EventRateLimitWait, Iteration: iteration, WaitSeconds: wait.Seconds()})
+}
+
+// end reports how the run ended and waits for delivery. A result without an
+// end time means the loop returned an error.
+func (r *runNotifier) end(result *AgentResult) {
+	if !r.Enabled() {
+		return
+	}
+	status := agentRunStatus(result)
+	if result.EndedAt.IsZero() {
+		status = "failed"
+	}
+	r.send(notify.Event{
+		Type:          notify.EventRunEnd,
+		Iteration:     result.Iterations,
+		MaxIterations: r.config.MaxIterations,
+		Status:        status,
+		Reason:        result.BlockedReason,
+		Summary:       runSummary(result),
+		OutputFile:    r.outputPath,
+		Result:        result,
+	})
+	r.Close()
+}
+
+// runSummary says what a run got done, e.g.
+// "3/5 balls complete, 1 blocked, 7 iterations in 42m"
+func runSummary(result *AgentResult) string {
+	summary := fmt.Sprintf("%d/%d balls complete, %d blocked, %d iteration%s", result.BallsComplete, result.BallsTotal, result.BallsBlocked, result.Iterations, pluralS(result.Iterations))
+	if !result.StartedAt.IsZero() && !result.EndedAt.IsZero() {
+		summary += " in " + formatDuration(result.EndedAt.Sub(result.StartedAt))
+	}
+	return summary
+}
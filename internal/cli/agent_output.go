@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// maxPreviousOutput caps the processed output of an iteration carried into
+// the next prompt, keeping its end where agents summarize their work
+const maxPreviousOutput = 4000
+
+// outputProcessing applies the output_processors configured for each provider
+// to iteration output, and carries the processed output into the next prompt
+type outputProcessing struct {
+	pipelines map[string]provider.OutputPipeline
+	previous  string // Processed output of the last iteration
+}
+
+// newOutputProcessing loads the project's output processors, failing on
+// unknown processor names so a typo doesn't go unnoticed for a whole run
+func newOutputProcessing(projectDir string) (*outputProcessing, error) {
+	configured, err := session.GetProjectOutputProcessors(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load output processors: %w", err)
+	}
+	o := &outputProcessing{pipelines: make(map[string]provider.OutputPipeline)}
+	for name, processors := range configured {
+		pipeline, err := provider.NewOutputPipeline(processors)
+		if err != nil {
+			return nil, fmt.Errorf("output_processors for %s: %w", name, err)
+		}
+		o.pipelines[name] = pipeline
+	}
+	return o, nil
+}
+
+// pipeline returns the processors for a provider, falling back to "*"
+func (o *outputProcessing) pipeline(providerType provider.Type) provider.OutputPipeline {
+	if pipeline, ok := o.pipelines[string(providerType)]; ok {
+		return pipeline
+	}
+	return o.pipelines["*"]
+}
+
+// apply processes an iteration's output with the provider's processors.
+// Output is only carried into the next prompt when processors are
+// configured; raw output is too noisy to pass on.
+func (o *outputProcessing) apply(providerType provider.Type, output string) string {
+	pipeline := o.pipeline(providerType)
+	if len(pipeline) == 0 {
+		o.previous = ""
+		return output
+	}
+	processed := pipeline.Apply(output)
+	o.previous = processed
+	return processed
+}
+
+// message adds the previous iteration's processed output to the user's message
+func (o *outputProcessing) message(userMessage string) string {
+	if o.previous == "" {
+		return userMessage
+	}
+	previous := []rune(o.previous)
+	if len(previous) > maxPreviousOutput {
+		previous = append([]rune("..."), previous[len(previous)-maxPreviousOutput:]...)
+	}
+	section := "Output of your previous iteration:\n\n" + string(previous)
+	if userMessage == "" {
+		return section
+	}
+	return userMessage + "\n\n" + section
+}
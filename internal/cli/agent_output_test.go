@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+)
+
+func TestOutputProcessing(t *testing.T) {
+	o := &outputProcessing{pipelines: map[string]provider.OutputPipeline{
+		"opencode": {provider.StripANSI},
+		"*":        {provider.ExtractSummary},
+	}}
+
+	if got := o.apply(provider.TypeOpenCode, "\x1b[32mok\x1b[0m"); got != "ok" {
+		t.Errorf("Expected the opencode pipeline, got %q", got)
+	}
+	if got := o.apply(provider.TypeClaude, "noise\nSummary: fixed it\n"); got != "Summary: fixed it\n" {
+		t.Errorf("Expected the \"*\" pipeline for other providers, got %q", got)
+	}
+	if got := o.message("keep going"); got != "keep going\n\nOutput of your previous iteration:\n\nSummary: fixed it\n" {
+		t.Errorf("Expected the processed output in the next message, got %q", got)
+	}
+
+	o.previous = strings.Repeat("x", maxPreviousOutput+100)
+	if got := o.message(""); !strings.HasSuffix(got, "\n\n..."+strings.Repeat("x", maxPreviousOutput)) {
+		t.Errorf("Expected the carried output capped to its end, got %d characters", len(got))
+	}
+
+	// Without processors, output is saved as is and not carried forward
+	none := &outputProcessing{}
+	if got := none.apply(provider.TypeClaude, "raw"); got != "raw" || none.message("msg") != "msg" {
+		t.Errorf("Expected raw output and an unchanged message, got %q / %q", got, none.message("msg"))
+	}
+}
@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// workOverlap is a recent change by another session to a path a ball is about to work on
+type workOverlap struct {
+	BallID     string
+	ProjectDir string
+	SessionID  string
+	Touch      session.FileTouch
+}
+
+// checkDuplicateWork looks for other sessions, across discovered projects, that
+// changed the paths the run's balls are about to work on within the configured
+// window. Depending on the duplicate_work mode the overlap is only reported, must
+// be confirmed, or stops the run. Returns a blocked reason when the run must not
+// start.
+func checkDuplicateWork(config AgentLoopConfig, storageID string) string {
+	settings, err := session.GetProjectDuplicateWork(config.ProjectDir)
+	if err != nil {
+		return ""
+	}
+	mode := settings.CheckMode()
+	if mode == "off" {
+		return ""
+	}
+
+	balls, err := ballsAboutToStart(config)
+	if err != nil || len(balls) == 0 {
+		return ""
+	}
+	since := time.Now().Add(-settings.Window())
+	overlaps := findDuplicateWork(config.ProjectDir, storageID, balls, overlapProjects(config.ProjectDir), since)
+	if len(overlaps) == 0 {
+		return ""
+	}
+
+	fmt.Fprintln(os.Stderr, "⚠️  Possible duplicate work: other sessions recently changed paths these balls cover")
+	for _, line := range describeOverlaps(overlaps, config.ProjectDir) {
+		fmt.Fprintf(os.Stderr, "   %s\n", line)
+	}
+	summary := summarizeOverlaps(overlaps)
+	logOverlapToProgress(config.ProjectDir, storageID, summary)
+
+	switch mode {
+	case "block":
+		return "duplicate work: " + summary
+	case "confirm":
+		if config.DaemonMode || !isTerminal(os.Stdin.Fd()) {
+			return "duplicate work needs confirmation: " + summary
+		}
+		ok, err := ConfirmSingleKey("Start anyway?")
+		if err != nil || !ok {
+			return "duplicate work not confirmed: " + summary
+		}
+	case "warn":
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: unknown duplicate_work mode %q, treating as warn\n", mode)
+	}
+	return ""
+}
+
+// ballsAboutToStart returns the ball the run targets, or the session's balls
+// the agent can work on now
+func ballsAboutToStart(config AgentLoopConfig) ([]*session.Ball, error) {
+	balls, err := loadSessionBallsForSnapshot(config.ProjectDir, config.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	states := session.BallStates(balls)
+
+	var starting []*session.Ball
+	for _, ball := range balls {
+		if config.BallID != "" {
+			if ball.ID == config.BallID || ball.ShortID() == config.BallID {
+				return []*session.Ball{ball}, nil
+			}
+			continue
+		}
+		if ball.State != session.StatePending && ball.State != session.StateInProgress {
+			continue
+		}
+		if len(session.UnmetDependencies(ball, states)) > 0 || ball.IsClaimed() {
+			continue
+		}
+		starting = append(starting, ball)
+	}
+	return starting, nil
+}
+
+// overlapProjects returns the discovered projects, always including projectDir
+func overlapProjects(projectDir string) []string {
+	projects := []string{projectDir}
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return projects
+	}
+	discovered, err := session.DiscoverProjects(config)
+	if err != nil {
+		return projects
+	}
+	for _, project := range discovered {
+		if project != projectDir {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
+
+// findDuplicateWork matches each ball's paths against other sessions' file
+// touches since the given time. A ball's paths are the files and directories
+// its title, context and acceptance criteria mention, plus the files this
+// session already changed for it.
+func findDuplicateWork(projectDir, storageID string, balls []*session.Ball, projects []string, since time.Time) []workOverlap {
+	juggleDirName := GetStoreConfig().JuggleDirName
+	ownStorage, _ := session.ResolveStorageDir(projectDir, juggleDirName)
+
+	type projectTouches struct {
+		dir       string
+		sameStore bool
+		touches   map[string][]session.FileTouch
+	}
+	var all []projectTouches
+	var own []session.FileTouch
+	seenStores := make(map[string]bool)
+	for _, project := range projects {
+		// Worktrees share their main repo's store, so read each store once
+		storage, err := session.ResolveStorageDir(project, juggleDirName)
+		if err != nil || seenStores[storage] {
+			continue
+		}
+		seenStores[storage] = true
+
+		store, err := session.NewSessionStoreWithConfig(project, GetStoreConfig())
+		if err != nil {
+			continue
+		}
+		touches, err := store.RecentFileTouches(since)
+		if err != nil || len(touches) == 0 {
+			continue
+		}
+		sameStore := storage == ownStorage
+		if sameStore {
+			own = touches[storageID]
+		}
+		all = append(all, projectTouches{dir: project, sameStore: sameStore, touches: touches})
+	}
+
+	var overlaps []workOverlap
+	for _, ball := range balls {
+		paths := ballPaths(ball, projectDir, own)
+		if len(paths) == 0 {
+			continue
+		}
+		for _, project := range all {
+			for sessionID, touches := range project.touches {
+				if project.sameStore && sessionID == storageID {
+					continue
+				}
+				for _, touch := range touches {
+					if touchCoversPaths(touch, project.sameStore, projectDir, paths) {
+						overlaps = append(overlaps, workOverlap{BallID: ball.ShortID(), ProjectDir: project.dir, SessionID: sessionID, Touch: touch})
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		return overlaps[i].Touch.At.After(overlaps[j].Touch.At)
+	})
+	return overlaps
+}
+
+// ballPaths returns the slash-separated paths, relative to projectDir, a ball
+// is likely to change: existing files and directories its text mentions and
+// the files own (this session's touches) records for it
+func ballPaths(ball *session.Ball, projectDir string, own []session.FileTouch) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	text := append([]string{ball.Title, ball.Context}, ball.AcceptanceCriteria...)
+	for _, field := range strings.Fields(strings.Join(text, " ")) {
+		// Strip quoting and punctuation; a trailing "." ends a sentence, a leading one is a dotfile
+		token := strings.TrimRight(strings.TrimLeft(field, "`'\"([{"), "`'\")]},:;!?.")
+		if !strings.ContainsAny(token, "/.") || filepath.IsAbs(token) || strings.Contains(token, "..") {
+			continue
+		}
+		clean := filepath.ToSlash(filepath.Clean(token))
+		if clean == "." {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(clean))); err == nil {
+			add(clean)
+		}
+	}
+	for _, touch := range own {
+		if touch.BallID == ball.ID {
+			add(touch.Path)
+		}
+	}
+	return paths
+}
+
+// touchCoversPaths reports whether a touch changed one of paths (or a file
+// under one of them). Touches from the same store also match by relative path,
+// so worktrees of the repo count as the same tree.
+func touchCoversPaths(touch session.FileTouch, sameStore bool, projectDir string, paths []string) bool {
+	for _, path := range paths {
+		if pathWithin(touch.AbsPath(), filepath.Join(projectDir, filepath.FromSlash(path))) {
+			return true
+		}
+		if sameStore && pathWithin(touch.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathWithin reports whether path is dir or lies under it
+func pathWithin(path, dir string) bool {
+	path, dir = filepath.ToSlash(path), filepath.ToSlash(dir)
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// describeOverlaps renders one line per overlap, most recent first, capped at ten
+func describeOverlaps(overlaps []workOverlap, projectDir string) []string {
+	var lines []string
+	for i, overlap := range overlaps {
+		if i == 10 {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(overlaps)-i))
+			break
+		}
+		where := overlap.SessionID
+		if overlap.ProjectDir != projectDir {
+			where += " in " + overlap.ProjectDir
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s changed by session %s %s ago",
+			overlap.BallID, overlap.Touch.Path, where, time.Since(overlap.Touch.At).Round(time.Minute)))
+	}
+	return lines
+}
+
+// summarizeOverlaps names the sessions involved, for progress and blocked reasons
+func summarizeOverlaps(overlaps []workOverlap) string {
+	seen := make(map[string]bool)
+	var sessions []string
+	for _, overlap := range overlaps {
+		if !seen[overlap.SessionID] {
+			seen[overlap.SessionID] = true
+			sessions = append(sessions, overlap.SessionID)
+		}
+	}
+	return fmt.Sprintf("%d recent change(s) by session(s) %s overlap this run's balls (e.g. %s)",
+		len(overlaps), strings.Join(sessions, ", "), overlaps[0].Touch.Path)
+}
+
+// recordIterationTouches records the files the iteration changed as touched by
+// the session, so runs of other sessions can spot overlapping work
+func recordIterationTouches(projectDir, storageID, ballID string, snap *session.IterationSnapshot) {
+	if snap == nil || snap.Revision == "" {
+		return
+	}
+	files, err := vcsBackendForProject(projectDir).ChangedFiles(projectDir, snap.Revision)
+	if err != nil || len(files) == 0 {
+		return
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	touches := make([]session.FileTouch, 0, len(files))
+	for _, file := range files {
+		touches = append(touches, session.NewFileTouch(projectDir, file, ballID, now))
+	}
+	_ = sessionStore.RecordFileTouches(storageID, touches)
+}
+
+// logOverlapToProgress logs possible duplicate work to the session's progress file
+func logOverlapToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[OVERLAP] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,429 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/vcs"
+	"github.com/spf13/cobra"
+)
+
+// parallelStopWait is how long a child agent gets to shut down after being interrupted
+const parallelStopWait = 30 * time.Second
+
+// ParallelAgentOptions configures a parallel agent run across a session's balls
+type ParallelAgentOptions struct {
+	ProjectDir string
+	SessionID  string
+	Parallel   int      // Maximum number of agents running at once
+	Iterations int      // Maximum iterations for each ball's agent
+	ChildArgs  []string // Extra "agent run" flags passed to every child agent
+	IgnoreLock bool     // Skip the session lock
+	Output     io.Writer
+
+	// Launch runs one child agent inside its workspace, writing its output to out.
+	// Nil re-runs this juggle binary as "juggle agent run <session> --ball <id>".
+	Launch func(ctx context.Context, job *ParallelJob, out io.Writer) error
+}
+
+// ParallelJob tracks one ball worked on by a child agent in its own workspace
+type ParallelJob struct {
+	BallID        string
+	Title         string
+	WorkspaceDir  string
+	WorkspaceName string // Branch (git) or workspace (jj) name
+	StartedAt     time.Time
+	EndedAt       time.Time
+	Err           error
+	State         session.BallState // Ball state once the agent finished
+	BlockedReason string
+	Kept          bool // Workspace left in place because it has uncommitted changes
+}
+
+// ParallelAgentResult summarises a parallel agent run
+type ParallelAgentResult struct {
+	Jobs     []*ParallelJob
+	Complete int
+	Blocked  int
+	Failed   int
+}
+
+// parallelRun holds the state shared by the scheduler and its child agents
+type parallelRun struct {
+	opts    ParallelAgentOptions
+	backend vcs.VCS
+	out     *parallelOutput
+}
+
+// runParallelAgentRun runs "agent run --parallel", passing the run's flags on to every child agent
+func runParallelAgentRun(ctx context.Context, cmd *cobra.Command, projectDir, sessionID string, iterations int, message string) error {
+	var childArgs []string
+	if agentTrust {
+		childArgs = append(childArgs, "--trust")
+	}
+	if agentTimeout > 0 {
+		childArgs = append(childArgs, "--timeout", agentTimeout.String())
+	}
+	if agentMaxWait > 0 {
+		childArgs = append(childArgs, "--max-wait", agentMaxWait.String())
+	}
+	if agentMaxDuration > 0 {
+		childArgs = append(childArgs, "--max-duration", agentMaxDuration.String())
+	}
+	if agentMaxTokens != 0 {
+		childArgs = append(childArgs, "--max-tokens", strconv.Itoa(agentMaxTokens))
+	}
+	if agentMaxCost != 0 {
+		childArgs = append(childArgs, "--max-cost", strconv.FormatFloat(agentMaxCost, 'f', -1, 64))
+	}
+	if agentAllowOverlap {
+		childArgs = append(childArgs, "--allow-overlap")
+	}
+	if agentNoPreflight {
+		childArgs = append(childArgs, "--no-preflight")
+	}
+	if agentPlanFirst {
+		childArgs = append(childArgs, "--plan-first")
+	}
+	if agentModel != "" {
+		childArgs = append(childArgs, "--model", agentModel)
+	}
+	if agentProvider != "" {
+		childArgs = append(childArgs, "--provider", agentProvider)
+	}
+	if cmd.Flags().Changed("delay") {
+		childArgs = append(childArgs, "--delay", strconv.Itoa(agentDelay))
+	}
+	if cmd.Flags().Changed("fuzz") {
+		childArgs = append(childArgs, "--fuzz", strconv.Itoa(agentFuzz))
+	}
+	if agentIgnoreQuiet {
+		childArgs = append(childArgs, "--ignore-quiet-hours")
+	}
+	if message != "" {
+		childArgs = append(childArgs, "--message", message)
+	}
+
+	result, err := RunParallelAgents(ctx, ParallelAgentOptions{
+		ProjectDir: projectDir,
+		SessionID:  sessionID,
+		Parallel:   agentParallel,
+		Iterations: iterations,
+		ChildArgs:  childArgs,
+		IgnoreLock: agentIgnoreLock,
+		Output:     os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("=== Summary ===")
+	fmt.Printf("Balls: %d complete, %d blocked, %d failed, %d started\n",
+		result.Complete, result.Blocked, result.Failed, len(result.Jobs))
+	for _, job := range result.Jobs {
+		if job.Kept {
+			fmt.Printf("Kept workspace for %s: %s\n", job.BallID, job.WorkspaceDir)
+		}
+	}
+	if len(result.Jobs) == 0 {
+		fmt.Println("No workable balls (all done, blocked, locked or waiting on dependencies)")
+	} else {
+		fmt.Println("Each ball's commits are on its own juggle-<ball-id> branch (git) or workspace (jj)")
+	}
+	return nil
+}
+
+// RunParallelAgents works through a session's balls with up to opts.Parallel agents at once.
+// Each ball gets its own git worktree (or jj workspace) linked to the project's .juggle/,
+// and its agent holds that ball's lock. A ball only starts once its dependencies are done.
+func RunParallelAgents(ctx context.Context, opts ParallelAgentOptions) (*ParallelAgentResult, error) {
+	if opts.Parallel < 1 {
+		return nil, fmt.Errorf("--parallel must be at least 1")
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	projectDir, err := filepath.Abs(opts.ProjectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	opts.ProjectDir = projectDir
+	if !vcs.AutoDetectExists(opts.ProjectDir) {
+		return nil, fmt.Errorf("parallel runs need a git or jj repository to isolate each ball's work")
+	}
+	if isWt, err := session.IsWorktree(opts.ProjectDir, GetStoreConfig().JuggleDirName); err == nil && isWt {
+		linkedMain, _ := session.GetLinkedMainRepo(opts.ProjectDir, GetStoreConfig().JuggleDirName)
+		return nil, fmt.Errorf("run parallel agents from the main repo: %s", linkedMain)
+	}
+
+	// Hold the session lock so a regular run can't pick up the same balls
+	if !opts.IgnoreLock {
+		sessionStore, err := session.NewSessionStoreWithConfig(opts.ProjectDir, GetStoreConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session store: %w", err)
+		}
+		lock, err := sessionStore.AcquireSessionLock(sessionStorageID(opts.SessionID))
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Release()
+	}
+
+	run := &parallelRun{
+		opts:    opts,
+		backend: vcsBackendForProject(opts.ProjectDir),
+		out:     &parallelOutput{w: opts.Output},
+	}
+	result := &ParallelAgentResult{}
+	attempted := make(map[string]bool)
+	done := make(chan *ParallelJob)
+	running := 0
+
+	for {
+		for ctx.Err() == nil && running < opts.Parallel {
+			ball, err := nextParallelBall(opts.ProjectDir, opts.SessionID, attempted)
+			if err != nil {
+				run.out.status("✗ failed to load balls: %v", err)
+				break
+			}
+			if ball == nil {
+				break
+			}
+			attempted[ball.ID] = true
+
+			job := &ParallelJob{BallID: ball.ID, Title: ball.Title}
+			result.Jobs = append(result.Jobs, job)
+			if err := run.prepareWorkspace(job); err != nil {
+				job.Err = err
+				result.Failed++
+				run.out.status("✗ %s: %v", job.BallID, err)
+				continue
+			}
+
+			running++
+			run.out.status("▶ %s started: %s (%s)", job.BallID, job.Title, job.WorkspaceDir)
+			go run.launch(ctx, job, done)
+		}
+
+		if running == 0 {
+			break
+		}
+		job := <-done
+		running--
+		run.finish(job, result)
+	}
+
+	return result, nil
+}
+
+// nextParallelBall returns the highest-priority workable ball that hasn't been attempted,
+// isn't locked by another agent or claimed by a person, and has all its dependencies done.
+// Nil means none is ready.
+func nextParallelBall(projectDir, sessionID string, attempted map[string]bool) (*session.Ball, error) {
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	sortBallsForAgent(balls)
+
+	states := session.BallStates(balls)
+
+	for _, ball := range balls {
+		if attempted[ball.ID] {
+			continue
+		}
+		if ball.State != session.StatePending && ball.State != session.StateInProgress {
+			continue
+		}
+		if len(session.UnmetDependencies(ball, states)) > 0 || ball.IsClaimed() {
+			continue
+		}
+		if locked, _ := session.IsBallLocked(projectDir, ball.ID); locked {
+			continue
+		}
+		return ball, nil
+	}
+	return nil, nil
+}
+
+// prepareWorkspace creates (or reuses) the ball's workspace next to the project and links
+// its .juggle/ back to the project so the child agent shares ball and session state.
+func (r *parallelRun) prepareWorkspace(job *ParallelJob) error {
+	juggleDirName := GetStoreConfig().JuggleDirName
+	job.WorkspaceName = "juggle-" + job.BallID
+	job.WorkspaceDir = filepath.Join(filepath.Dir(r.opts.ProjectDir), filepath.Base(r.opts.ProjectDir)+"-"+job.BallID)
+
+	if _, err := os.Stat(job.WorkspaceDir); err == nil {
+		// Left over from an earlier run that had uncommitted changes
+		linkedMain, _ := session.GetLinkedMainRepo(job.WorkspaceDir, juggleDirName)
+		if linkedMain != r.opts.ProjectDir {
+			return fmt.Errorf("%s already exists and is not linked to this repo", job.WorkspaceDir)
+		}
+		return nil
+	}
+
+	if err := r.backend.AddWorkspace(r.opts.ProjectDir, job.WorkspaceDir, job.WorkspaceName); err != nil {
+		return err
+	}
+	if err := session.RegisterWorktree(r.opts.ProjectDir, job.WorkspaceDir, juggleDirName); err != nil {
+		_ = r.backend.RemoveWorkspace(r.opts.ProjectDir, job.WorkspaceDir, job.WorkspaceName)
+		return fmt.Errorf("failed to link workspace: %w", err)
+	}
+	return nil
+}
+
+// launch runs the child agent for a job and reports back on done
+func (r *parallelRun) launch(ctx context.Context, job *ParallelJob, done chan<- *ParallelJob) {
+	out := r.out.forBall(job.BallID)
+	job.StartedAt = time.Now()
+	launch := r.opts.Launch
+	if launch == nil {
+		launch = r.launchChild
+	}
+	job.Err = launch(ctx, job, out)
+	out.Flush()
+	job.EndedAt = time.Now()
+	done <- job
+}
+
+// launchChild re-runs juggle as a single-ball agent inside the job's workspace
+func (r *parallelRun) launchChild(ctx context.Context, job *ParallelJob, out io.Writer) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate juggle executable: %w", err)
+	}
+
+	args := []string{"agent", "run", r.opts.SessionID,
+		"--ball", job.BallID,
+		"--iterations", strconv.Itoa(r.opts.Iterations),
+		"--skip-hooks-check",
+	}
+	args = append(args, r.opts.ChildArgs...)
+	if GlobalOpts.ConfigHome != "" {
+		args = append(args, "--config-home", GlobalOpts.ConfigHome)
+	}
+
+	cmd := exec.CommandContext(ctx, exe, args...)
+	cmd.Dir = job.WorkspaceDir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// Interrupt rather than kill so the child records its progress and releases its lock
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = parallelStopWait
+	return cmd.Run()
+}
+
+// finish records a job's outcome and removes its workspace unless it holds uncommitted work
+func (r *parallelRun) finish(job *ParallelJob, result *ParallelAgentResult) {
+	store, err := NewStoreForCommand(r.opts.ProjectDir)
+	if err == nil {
+		if ball, err := store.GetBallByID(job.BallID); err == nil {
+			job.State = ball.State
+			job.BlockedReason = ball.BlockedReason
+		}
+	}
+
+	if changed, err := r.uncommittedFiles(job.WorkspaceDir); err != nil || changed > 0 {
+		job.Kept = true
+	} else {
+		juggleDirName := GetStoreConfig().JuggleDirName
+		_ = session.ForgetWorktree(r.opts.ProjectDir, job.WorkspaceDir, juggleDirName)
+		if err := r.backend.RemoveWorkspace(r.opts.ProjectDir, job.WorkspaceDir, job.WorkspaceName); err != nil {
+			job.Kept = true
+		}
+	}
+
+	elapsed := job.EndedAt.Sub(job.StartedAt).Round(time.Second)
+	switch {
+	case job.Err != nil:
+		result.Failed++
+		r.out.status("✗ %s failed after %s: %v", job.BallID, elapsed, job.Err)
+	case job.State == session.StateComplete || job.State == session.StateResearched:
+		result.Complete++
+		r.out.status("✓ %s %s in %s", job.BallID, job.State, elapsed)
+	case job.State == session.StateBlocked:
+		result.Blocked++
+		r.out.status("⏸ %s blocked after %s: %s", job.BallID, elapsed, job.BlockedReason)
+	default:
+		r.out.status("• %s stopped after %s (%s)", job.BallID, elapsed, job.State)
+	}
+	if job.Kept {
+		r.out.status("  %s has uncommitted changes, kept at %s", job.BallID, job.WorkspaceDir)
+	}
+}
+
+// uncommittedFiles counts files changed in a workspace since its last commit, excluding .juggle/
+func (r *parallelRun) uncommittedFiles(workspaceDir string) (int, error) {
+	base, err := r.backend.GetSnapshotRevision(workspaceDir)
+	if err != nil {
+		return 0, err
+	}
+	files, err := r.backend.ChangedFiles(workspaceDir, base)
+	if err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// parallelOutput merges the output of concurrent agents into one stream,
+// prefixing every line with the ball it came from
+type parallelOutput struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// status writes a scheduler line to the merged output
+func (p *parallelOutput) status(format string, args ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, format+"\n", args...)
+}
+
+// forBall returns a writer that prefixes each complete line with the ball ID
+func (p *parallelOutput) forBall(ballID string) *ballLineWriter {
+	return &ballLineWriter{parent: p, prefix: "[" + ballID + "] "}
+}
+
+// ballLineWriter buffers partial lines so output from different agents never interleaves mid-line
+type ballLineWriter struct {
+	parent *parallelOutput
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (b *ballLineWriter) Write(data []byte) (int, error) {
+	b.buf.Write(data)
+	for {
+		line, err := b.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the partial line for the next write
+			b.buf.Write(line)
+			return len(data), nil
+		}
+		b.writeLine(line)
+	}
+}
+
+// Flush writes any trailing partial line
+func (b *ballLineWriter) Flush() {
+	if b.buf.Len() > 0 {
+		b.writeLine(append(b.buf.Bytes(), '\n'))
+		b.buf.Reset()
+	}
+}
+
+func (b *ballLineWriter) writeLine(line []byte) {
+	b.parent.mu.Lock()
+	defer b.parent.mu.Unlock()
+	fmt.Fprintf(b.parent.w, "%s%s", b.prefix, line)
+}
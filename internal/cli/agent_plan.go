@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// planBlockPattern matches one ball's plan in the planning iteration's output
+var planBlockPattern = regexp.MustCompile(`(?s)<plan ball="([^"]+)">(.*?)</plan>`)
+
+// planStepPrefix matches a step's list marker: "1.", "2)", "-" or "*"
+var planStepPrefix = regexp.MustCompile(`^(\d+[.)]|[-*])\s+`)
+
+// ballsToPlan returns the balls the run will work on that have no plan yet
+func ballsToPlan(config AgentLoopConfig) ([]*session.Ball, error) {
+	balls, err := loadBallsForModelSelection(config.ProjectDir, config.SessionID, config.BallID)
+	if err != nil {
+		return nil, err
+	}
+	var unplanned []*session.Ball
+	for _, ball := range filterActiveBalls(balls) {
+		if len(ball.Plan) > 0 || ball.IsClaimed() {
+			continue
+		}
+		// Blocked balls wait for a person, unless this run targets one
+		if ball.State == session.StateBlocked && config.BallID == "" {
+			continue
+		}
+		unplanned = append(unplanned, ball)
+	}
+	return unplanned, nil
+}
+
+// runPlanningIteration is the first iteration of a --plan-first run: a
+// read-only run in plan mode that writes a step list for each ball without
+// one, stored on the ball for later iterations to execute. Returns the run's
+// result (nil if there was nothing to plan) and the number of balls planned.
+func runPlanningIteration(ctx context.Context, config AgentLoopConfig, runner agent.Runner, balls []*session.Ball, model, storageID string) (*agent.RunResult, int, error) {
+	fmt.Printf("📋 Planning %d ball(s) in plan mode before executing...\n", len(balls))
+
+	var buf strings.Builder
+	writeRefineContext(&buf, config.ProjectDir, config.SessionID, balls)
+	writeRefineInstructions(&buf, agent.GetPlanPromptTemplate())
+
+	runResult, err := runner.Run(agent.RunOptions{
+		Prompt:     buf.String(),
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Timeout:    config.Timeout,
+		Model:      model,
+		WorkingDir: config.ProjectDir,
+		Context:    ctx,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to run planning iteration: %w", err)
+	}
+	if runResult.Error != nil || runResult.RateLimited {
+		fmt.Fprintf(os.Stderr, "⚠️  Planning iteration failed, executing without a plan: %v\n", runResult.Error)
+		logPlanToProgress(config.ProjectDir, storageID, "Planning iteration failed, executing without a plan")
+		return runResult, 0, nil
+	}
+
+	plans := parsePlans(runResult.Output)
+	planned := 0
+	for _, ball := range balls {
+		steps, ok := plans[ball.ID]
+		if !ok {
+			steps, ok = plans[ball.ShortID()]
+		}
+		if !ok || len(steps) == 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  No plan for %s, it will be executed without one\n", ball.ShortID())
+			continue
+		}
+
+		store, err := NewStoreForCommand(ball.WorkingDir)
+		if err != nil {
+			return runResult, planned, fmt.Errorf("failed to create store: %w", err)
+		}
+		ball.SetPlan(steps)
+		if err := store.UpdateBall(ball); err != nil {
+			return runResult, planned, fmt.Errorf("failed to save plan for %s: %w", ball.ID, err)
+		}
+		planned++
+		fmt.Printf("📋 %s: %d step(s)\n", ball.ShortID(), len(steps))
+		logPlanToProgress(config.ProjectDir, storageID, fmt.Sprintf("Planned %s in %d step(s):\n%s", ball.ID, len(steps), formatPlanSteps(steps)))
+	}
+	return runResult, planned, nil
+}
+
+// parsePlans extracts each ball's steps from the planning iteration's output,
+// keyed by the ball ID the agent gave
+func parsePlans(output string) map[string][]string {
+	plans := make(map[string][]string)
+	for _, match := range planBlockPattern.FindAllStringSubmatch(output, -1) {
+		var steps []string
+		for _, line := range strings.Split(match[2], "\n") {
+			step := strings.TrimSpace(planStepPrefix.ReplaceAllString(strings.TrimSpace(line), ""))
+			if step != "" {
+				steps = append(steps, step)
+			}
+		}
+		plans[strings.TrimSpace(match[1])] = steps
+	}
+	return plans
+}
+
+// formatPlanSteps numbers a plan's steps, one per line
+func formatPlanSteps(steps []string) string {
+	lines := make([]string, len(steps))
+	for i, step := range steps {
+		lines[i] = fmt.Sprintf("  %d. %s", i+1, step)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// logPlanToProgress logs the planning iteration to the session's progress file
+func logPlanToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[PLAN] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
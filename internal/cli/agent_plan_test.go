@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlans(t *testing.T) {
+	output := `Here is the plan.
+
+<plan ball="proj-a1b2">
+1. Add Timeout to Config in config/config.go
+2) Read it in LoadConfig
+
+- Run go test ./config/...
+</plan>
+
+<plan ball="c3d4">
+* Update the README
+</plan>
+<plan ball="e5f6">
+</plan>`
+
+	plans := parsePlans(output)
+	want := map[string][]string{
+		"proj-a1b2": {"Add Timeout to Config in config/config.go", "Read it in LoadConfig", "Run go test ./config/..."},
+		"c3d4":      {"Update the README"},
+		"e5f6":      nil,
+	}
+	if !reflect.DeepEqual(plans, want) {
+		t.Errorf("parsePlans() = %#v, want %#v", plans, want)
+	}
+
+	if plans := parsePlans("no plan blocks here"); len(plans) != 0 {
+		t.Errorf("Expected no plans, got %v", plans)
+	}
+}
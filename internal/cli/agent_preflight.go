@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// preflightTimeout bounds the preflight ping; a healthy provider answers in seconds
+const preflightTimeout = 2 * time.Minute
+
+// runPreflight checks the provider can serve the run before its first
+// iteration: the model is one the provider offers, and a one-word ping with
+// that model succeeds. It fails fast on bad credentials or an unknown model
+// instead of finding out after a long iteration. Rate limits are left to the loop.
+func runPreflight(ctx context.Context, runner agent.Runner, p provider.Provider, projectDir string, providerType provider.Type, model string) error {
+	if p != nil && !provider.IsModelSupported(p, model) {
+		return fmt.Errorf("preflight failed: %w: %s does not offer %s (supported: %v)",
+			provider.ErrPreflightModel, providerType, model, p.ListModels())
+	}
+
+	fmt.Printf("🩺 Preflight: checking %s with %s... ", providerType, model)
+	started := time.Now()
+	result, err := runner.Run(agent.RunOptions{
+		Prompt:     provider.PreflightPrompt,
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      model,
+		WorkingDir: projectDir,
+		Timeout:    preflightTimeout,
+		Context:    ctx,
+	})
+	if ctx.Err() != nil {
+		fmt.Println()
+		return ctx.Err()
+	}
+	if err := provider.CheckPreflight(result, err); err != nil {
+		fmt.Println("failed")
+		hint := "skip the check with --no-preflight"
+		switch {
+		case errors.Is(err, provider.ErrPreflightAuth):
+			hint = "check the API key or log in to the agent CLI, then try again"
+		case errors.Is(err, provider.ErrPreflightModel):
+			hint = "choose another model with --model"
+		}
+		return fmt.Errorf("preflight failed for agent provider %q with model %s: %w (%s)", providerType, model, err, hint)
+	}
+
+	if result != nil && (result.RateLimited || result.OverloadExhausted) {
+		fmt.Println("rate limited, the run will wait it out")
+		return nil
+	}
+	fmt.Printf("ok (%s)\n", time.Since(started).Round(100*time.Millisecond))
+	return nil
+}
+
+// firstIterationModel returns the model the run's first iteration will use
+func firstIterationModel(config AgentLoopConfig, p provider.Provider, juggleSession *session.JuggleSession) string {
+	var sessionDefaultModel session.ModelSize
+	if juggleSession != nil {
+		sessionDefaultModel = juggleSession.DefaultModel
+	}
+	balls, err := loadBallsForModelSelection(config.ProjectDir, config.SessionID, config.BallID)
+	if err != nil {
+		balls = nil
+	}
+	return selectModelForProvider(config, p, balls, sessionDefaultModel).Model
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+)
+
+// providerForSwitch checks that a running loop can move to the named provider
+// and returns it. A sandboxed loop runs the agent CLI from its image, so the
+// host doesn't need the provider's binary.
+func providerForSwitch(name string, sandboxed, interactive bool) (provider.Provider, error) {
+	p := provider.Type(strings.TrimSpace(name))
+	if !p.IsValid() {
+		valid := append(provider.ValidProviders(), provider.CustomProviders()...)
+		return nil, fmt.Errorf("unknown agent provider %q (must be one of %s)", name, strings.Join(valid, ", "))
+	}
+	if !sandboxed && !provider.IsAvailable(p) {
+		return nil, providerUnavailableError(p)
+	}
+	prov := provider.Get(p)
+	if !prov.SupportsTools() {
+		return nil, fmt.Errorf("agent provider %q has no tools and can't run the agent loop", p)
+	}
+	if interactive && !prov.SupportsInteractive() {
+		return nil, fmt.Errorf("agent provider %q does not support interactive mode", p)
+	}
+	return prov, nil
+}
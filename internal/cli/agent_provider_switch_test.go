@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+)
+
+func TestProviderForSwitch(t *testing.T) {
+	// Sandboxed, so the result doesn't depend on which CLIs this machine has
+	prov, err := providerForSwitch("opencode", true, false)
+	if err != nil {
+		t.Fatalf("Expected opencode to be accepted, got %v", err)
+	}
+	if prov.Type() != provider.TypeOpenCode {
+		t.Errorf("Expected opencode, got %s", prov.Type())
+	}
+
+	if _, err := providerForSwitch("nope", true, false); err == nil || !strings.Contains(err.Error(), "unknown agent provider") {
+		t.Errorf("Expected an unknown provider error, got %v", err)
+	}
+	if _, err := providerForSwitch("api", true, false); err == nil || !strings.Contains(err.Error(), "no tools") {
+		t.Errorf("Expected the tool-less api provider to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,497 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ohare93/juggle/internal/process"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queuePriority   string
+	queueIterations int
+	queueTrust      bool
+	queueClearAll   bool
+	queueDaemon     bool
+)
+
+var agentQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Queue balls and sessions for unattended agent runs",
+	Long: `Queue balls or whole sessions, from any project, for a single worker to run
+the agent on one after another. Useful for overnight batches.
+
+The worker takes the highest-priority item first (urgent, high, medium, low),
+then the earliest added, and records each item's result in the queue. Balls are
+queued at their own priority, sessions at medium, unless --priority is given.
+The queue is stored in ~/.juggle/queue.json.
+
+Run without a subcommand to list the queue.
+
+Examples:
+  juggle agent queue add my-feature             # Queue a session
+  juggle agent queue add a1b2 c3d4 -p high      # Queue two balls
+  juggle agent queue                            # Show queued items and results
+  juggle agent queue run                        # Drain the queue in the foreground
+  juggle agent queue run --daemon               # Drain it in the background
+  juggle agent queue clear                      # Drop finished items`,
+	Args: cobra.NoArgs,
+	RunE: runQueueList,
+}
+
+var agentQueueAddCmd = &cobra.Command{
+	Use:   "add <session-or-ball-id>...",
+	Short: "Add sessions or balls to the queue",
+	Long: `Add sessions or balls to the queue. Each argument is first looked up as a
+session in the current project, then as a ball across discovered projects.
+
+Balls run in the first of their tags that is a session (or "all") for 3
+iterations by default; sessions run for 10.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runQueueAdd,
+}
+
+var agentQueueListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List queued items and finished results",
+	Args:    cobra.NoArgs,
+	RunE:    runQueueList,
+}
+
+var agentQueueRemoveCmd = &cobra.Command{
+	Use:     "remove <item-id>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove items from the queue",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runQueueRemove,
+}
+
+var agentQueueClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove finished items (--all for every item not running)",
+	Args:  cobra.NoArgs,
+	RunE:  runQueueClear,
+}
+
+var agentQueueRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Work through the queue until it is empty",
+	Long: `Run the agent on each queued item in priority order until none are left.
+
+Only one worker runs at a time. Items a stopped worker left running go back in
+line when the next worker starts, as does the current item when the worker is
+interrupted. With --daemon the worker runs in the background and logs to
+~/.juggle/queue.log; each item's run shows up in the monitor like any daemon.`,
+	Args: cobra.NoArgs,
+	RunE: runQueueRun,
+}
+
+func init() {
+	agentQueueAddCmd.Flags().StringVarP(&queuePriority, "priority", "p", "", "Queue priority: low, medium, high, urgent (default: ball priority, medium for sessions)")
+	agentQueueAddCmd.Flags().IntVarP(&queueIterations, "iterations", "n", 0, "Maximum iterations for the item (default: 3 for balls, 10 for sessions)")
+	agentQueueAddCmd.Flags().BoolVar(&queueTrust, "trust", false, "Run the agent with full permissions")
+	agentQueueClearCmd.Flags().BoolVar(&queueClearAll, "all", false, "Also remove items still waiting to run")
+	agentQueueRunCmd.Flags().BoolVar(&queueDaemon, "daemon", false, "Run the worker in the background")
+
+	agentQueueCmd.AddCommand(agentQueueAddCmd)
+	agentQueueCmd.AddCommand(agentQueueListCmd)
+	agentQueueCmd.AddCommand(agentQueueRemoveCmd)
+	agentQueueCmd.AddCommand(agentQueueClearCmd)
+	agentQueueCmd.AddCommand(agentQueueRunCmd)
+	agentCmd.AddCommand(agentQueueCmd)
+}
+
+func runQueueAdd(cmd *cobra.Command, args []string) error {
+	if queuePriority != "" && !session.ValidatePriority(queuePriority) {
+		return fmt.Errorf("invalid priority %q (use low, medium, high or urgent)", queuePriority)
+	}
+	if queueIterations < 0 {
+		return fmt.Errorf("--iterations must be positive")
+	}
+
+	var items []*session.QueueItem
+	for _, target := range args {
+		item, err := resolveQueueTarget(target)
+		if err != nil {
+			return err
+		}
+		if queuePriority != "" {
+			item.Priority = session.Priority(queuePriority)
+		}
+		if queueIterations > 0 {
+			item.Iterations = queueIterations
+		}
+		item.Trust = queueTrust
+		items = append(items, item)
+	}
+
+	store, err := session.NewQueueStore(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	if err := store.Add(items...); err != nil {
+		return err
+	}
+	for _, item := range items {
+		fmt.Printf("✓ Queued #%d: %s %s (%s, %d iterations)\n", item.ID, item.Kind, item.Target, item.Priority, item.Iterations)
+	}
+	return nil
+}
+
+// resolveQueueTarget turns an argument into a queue item: a session in the
+// current project, or else a ball in any discovered project
+func resolveQueueTarget(target string) (*session.QueueItem, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	projectDir := cwd
+
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err == nil {
+		if target == "all" {
+			return &session.QueueItem{
+				Kind: session.QueueKindSession, Target: target, Title: "All balls in the project",
+				ProjectDir: projectDir, SessionID: target, Priority: session.PriorityMedium, Iterations: 10,
+			}, nil
+		}
+		if juggleSession, err := sessionStore.LoadSession(target); err == nil {
+			return &session.QueueItem{
+				Kind: session.QueueKindSession, Target: target, Title: juggleSession.Description,
+				ProjectDir: projectDir, SessionID: target, Priority: session.PriorityMedium, Iterations: 10,
+			}, nil
+		}
+	}
+
+	ball, _, err := findBallByID(target)
+	if err != nil {
+		return nil, fmt.Errorf("no session or ball %q: %w", target, err)
+	}
+	return &session.QueueItem{
+		Kind: session.QueueKindBall, Target: ball.ID, Title: ball.Title,
+		ProjectDir: ball.WorkingDir, SessionID: unblockSessionID(ball), Priority: ball.Priority, Iterations: 3,
+	}, nil
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	store, err := session.NewQueueStore(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	items, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	var waiting, finished []*session.QueueItem
+	for _, item := range items {
+		if item.Finished() {
+			finished = append(finished, item)
+		} else {
+			waiting = append(waiting, item)
+		}
+	}
+	session.SortQueue(waiting)
+	// Most recent results first
+	for i, j := 0, len(finished)-1; i < j; i, j = i+1, j-1 {
+		finished[i], finished[j] = finished[j], finished[i]
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(append(waiting, finished...), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(items) == 0 {
+		fmt.Println("The queue is empty. Add work with: juggle agent queue add <session-or-ball>")
+		return nil
+	}
+
+	fmt.Printf("Queue (%d waiting):\n", len(waiting))
+	if len(waiting) == 0 {
+		fmt.Println(StyleDim.Render("  (nothing waiting)"))
+	}
+	for _, item := range waiting {
+		fmt.Printf("  %s\n", formatQueueItem(item))
+	}
+
+	if len(finished) > 0 {
+		fmt.Printf("\nFinished (%d):\n", len(finished))
+		for _, item := range finished {
+			fmt.Printf("  %s\n", formatQueueItem(item))
+			if item.Result != "" {
+				fmt.Printf("      %s\n", StyleDim.Render(item.Result))
+			}
+		}
+	}
+	return nil
+}
+
+// formatQueueItem renders one line of the queue listing
+func formatQueueItem(item *session.QueueItem) string {
+	status := fmt.Sprintf("%-10s", item.Status)
+	switch item.Status {
+	case session.QueueRunning:
+		status = StyleInProgress.Render(status)
+	case session.QueueComplete:
+		status = StyleComplete.Render(status)
+	case session.QueueBlocked, session.QueueFailed:
+		status = StyleBlocked.Render(status)
+	default:
+		status = StylePending.Render(status)
+	}
+
+	line := fmt.Sprintf("#%-3d %s %-7s %-8s %s", item.ID, status, item.Kind, item.Priority, item.Target)
+	if item.Title != "" && item.Title != item.Target {
+		line += " " + StyleDim.Render(truncate(item.Title, 50))
+	}
+	line += " " + StyleDim.Render("("+filepath.Base(item.ProjectDir)+")")
+	return line
+}
+
+func runQueueRemove(cmd *cobra.Command, args []string) error {
+	ids := make(map[int]bool)
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid queue item ID %q", arg)
+		}
+		ids[id] = true
+	}
+
+	store, err := session.NewQueueStore(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	removed := 0
+	err = store.Update(func(items []*session.QueueItem) ([]*session.QueueItem, error) {
+		kept := items[:0]
+		for _, item := range items {
+			if ids[item.ID] && item.Status != session.QueueRunning {
+				removed++
+				continue
+			}
+			if ids[item.ID] {
+				fmt.Fprintf(os.Stderr, "Skipping #%d: it is running\n", item.ID)
+			}
+			kept = append(kept, item)
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed %d item(s) from the queue\n", removed)
+	return nil
+}
+
+func runQueueClear(cmd *cobra.Command, args []string) error {
+	store, err := session.NewQueueStore(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	removed := 0
+	err = store.Update(func(items []*session.QueueItem) ([]*session.QueueItem, error) {
+		kept := items[:0]
+		for _, item := range items {
+			if item.Finished() || (queueClearAll && item.Status == session.QueueQueued) {
+				removed++
+				continue
+			}
+			kept = append(kept, item)
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Cleared %d item(s) from the queue\n", removed)
+	return nil
+}
+
+func runQueueRun(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	daemonMode := false
+	if os.Getenv("JUGGLE_DAEMON_CHILD") == "1" {
+		// We are the background worker - clear the env var and continue
+		os.Unsetenv("JUGGLE_DAEMON_CHILD")
+		daemonMode = true
+
+		// Interrupt the current item on SIGTERM/SIGINT so it goes back in line
+		var stop context.CancelFunc
+		ctx, stop = process.NotifyContext(ctx)
+		defer stop()
+	} else if queueDaemon {
+		return startQueueDaemon()
+	}
+
+	store, err := session.NewQueueStore(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	ran, err := drainQueue(ctx, store, daemonMode)
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		fmt.Printf("\nQueue worker stopped after %d item(s)\n", ran)
+		return nil
+	}
+	fmt.Printf("\nQueue drained: ran %d item(s)\n", ran)
+	return nil
+}
+
+// startQueueDaemon re-executes the queue worker in the background, logging to
+// ~/.juggle/queue.log
+func startQueueDaemon() error {
+	opts := GetConfigOptions()
+	logPath := filepath.Join(opts.ConfigHome, opts.JuggleDirName, "queue.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+
+	workerCmd := exec.Command(os.Args[0], os.Args[1:]...)
+	workerCmd.Env = append(os.Environ(), "JUGGLE_DAEMON_CHILD=1")
+	workerCmd.Stdout = logFile
+	workerCmd.Stderr = logFile
+	process.Detach(workerCmd)
+	if err := workerCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start queue worker: %w", err)
+	}
+
+	fmt.Printf("Queue worker started (PID %d)\n", workerCmd.Process.Pid)
+	fmt.Printf("Log file: %s\n", logPath)
+	fmt.Println("Check progress with: juggle agent queue")
+	return nil
+}
+
+// drainQueue runs the agent on queued items, highest priority first, until
+// none are left or ctx is cancelled, recording each item's result. Returns the
+// number of items run.
+func drainQueue(ctx context.Context, store *session.QueueStore, daemonMode bool) (int, error) {
+	release, err := store.AcquireWorker()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	// Holding the worker lock, anything marked running was left by a worker that died
+	err = store.Update(func(items []*session.QueueItem) ([]*session.QueueItem, error) {
+		for _, item := range items {
+			if item.Status == session.QueueRunning {
+				item.Status = session.QueueQueued
+				item.StartedAt = nil
+			}
+		}
+		return items, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	for ctx.Err() == nil {
+		var item session.QueueItem
+		err := store.Update(func(items []*session.QueueItem) ([]*session.QueueItem, error) {
+			next := session.NextQueued(items)
+			if next == nil {
+				return items, nil
+			}
+			now := time.Now()
+			next.Status = session.QueueRunning
+			next.StartedAt = &now
+			item = *next
+			return items, nil
+		})
+		if err != nil {
+			return ran, err
+		}
+		if item.ID == 0 {
+			break
+		}
+
+		fmt.Printf("\n▶ Queue item #%d: %s %s (%s) in %s\n", item.ID, item.Kind, item.Target, item.Priority, item.ProjectDir)
+		config := AgentLoopConfig{
+			SessionID:     item.SessionID,
+			ProjectDir:    item.ProjectDir,
+			MaxIterations: item.Iterations,
+			Trust:         item.Trust,
+			DaemonMode:    daemonMode,
+		}
+		if item.Kind == session.QueueKindBall {
+			config.BallID = item.Target
+		}
+		result, runErr := RunAgentLoop(ctx, config)
+		status, summary := queueOutcome(result, runErr)
+		interrupted := ctx.Err() != nil
+
+		err = store.Update(func(items []*session.QueueItem) ([]*session.QueueItem, error) {
+			for _, queued := range items {
+				if queued.ID != item.ID {
+					continue
+				}
+				if interrupted {
+					// Put it back in line for the next worker
+					queued.Status = session.QueueQueued
+					queued.StartedAt = nil
+					break
+				}
+				now := time.Now()
+				queued.Status = status
+				queued.Result = summary
+				queued.EndedAt = &now
+			}
+			return items, nil
+		})
+		if err != nil {
+			return ran, err
+		}
+		if interrupted {
+			break
+		}
+		ran++
+		fmt.Printf("■ Queue item #%d %s: %s\n", item.ID, status, summary)
+	}
+	return ran, nil
+}
+
+// queueOutcome maps an agent run to the queue status and result summary it records
+func queueOutcome(result *AgentResult, err error) (session.QueueItemStatus, string) {
+	if err != nil {
+		return session.QueueFailed, err.Error()
+	}
+	balls := fmt.Sprintf("%d iteration(s), %d/%d ball(s) complete", result.Iterations, result.BallsComplete, result.BallsTotal)
+	switch {
+	case result.Complete:
+		return session.QueueComplete, balls
+	case result.Blocked:
+		return session.QueueBlocked, result.BlockedReason
+	case result.TimedOut:
+		return session.QueueIncomplete, result.TimeoutMessage
+	case result.RateLimitExceded:
+		return session.QueueIncomplete, "rate limit exceeded; " + balls
+	case result.BudgetExceeded:
+		return session.QueueIncomplete, result.BudgetMessage
+	default:
+		return session.QueueIncomplete, "max iterations reached; " + balls
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestQueueOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *AgentResult
+		err    error
+		status session.QueueItemStatus
+		want   string
+	}{
+		{"error", nil, errors.New("boom"), session.QueueFailed, "boom"},
+		{"complete", &AgentResult{Complete: true, Iterations: 2, BallsComplete: 3, BallsTotal: 3}, nil, session.QueueComplete, "2 iteration(s), 3/3 ball(s) complete"},
+		{"blocked", &AgentResult{Blocked: true, BlockedReason: "needs API key"}, nil, session.QueueBlocked, "needs API key"},
+		{"budget", &AgentResult{BudgetExceeded: true, BudgetMessage: "cost limit"}, nil, session.QueueIncomplete, "cost limit"},
+		{"max iterations", &AgentResult{Iterations: 10, BallsComplete: 1, BallsTotal: 4}, nil, session.QueueIncomplete, "max iterations reached; 10 iteration(s), 1/4 ball(s) complete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, summary := queueOutcome(tt.result, tt.err)
+			if status != tt.status || summary != tt.want {
+				t.Errorf("queueOutcome() = %s, %q; want %s, %q", status, summary, tt.status, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrainQueue_RecordsResultsAndRequeuesStale(t *testing.T) {
+	store, err := session.NewQueueStore(session.ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"})
+	if err != nil {
+		t.Fatalf("NewQueueStore failed: %v", err)
+	}
+	// A session in a project that doesn't exist fails to start
+	missing := filepath.Join(t.TempDir(), "gone")
+	if err := store.Add(&session.QueueItem{Kind: session.QueueKindSession, Target: "nope", SessionID: "nope", ProjectDir: missing, Iterations: 1}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	// Left running by a worker that died
+	if err := store.Update(func(items []*session.QueueItem) ([]*session.QueueItem, error) {
+		items[0].Status = session.QueueRunning
+		return items, nil
+	}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	ran, err := drainQueue(context.Background(), store, false)
+	if err != nil {
+		t.Fatalf("drainQueue failed: %v", err)
+	}
+	if ran != 1 {
+		t.Errorf("expected 1 item run, got %d", ran)
+	}
+
+	items, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if items[0].Status != session.QueueFailed || items[0].Result == "" || items[0].EndedAt == nil {
+		t.Errorf("expected a recorded failure, got %+v", items[0])
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// sharedRateLimit coordinates one agent loop's rate limit backoff with the
+// other juggle processes on the machine using the same provider, so they
+// don't all retry at once; interactive runs don't take part
+type sharedRateLimit struct {
+	state *session.SharedRateLimit
+}
+
+// newSharedRateLimit opens the machine-wide rate limit state for the provider
+func newSharedRateLimit(config AgentLoopConfig, providerType provider.Type) *sharedRateLimit {
+	r := &sharedRateLimit{}
+	if config.Interactive {
+		return r
+	}
+	state, err := session.OpenSharedRateLimitWithOptions(GetConfigOptions(), string(providerType))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: not sharing rate limits with other runs: %v\n", err)
+		return r
+	}
+	r.state = state
+	return r
+}
+
+// wait holds the loop back while a rate limit any run recorded is in effect,
+// or while another run probes whether it has lifted. Returns how long it
+// waited, and exceeded when waiting would go past maxWait (0 = no limit)
+// given the waitedSoFar. The caller checks ctx for cancellation.
+func (r *sharedRateLimit) wait(ctx context.Context, maxWait, waitedSoFar time.Duration) (waited time.Duration, exceeded bool) {
+	if r.state == nil {
+		return 0, false
+	}
+	for ctx.Err() == nil {
+		wait, err := r.state.Wait(time.Now(), os.Getpid())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to check shared rate limit: %v\n", err)
+			return waited, false
+		}
+		if wait <= 0 {
+			return waited, false
+		}
+		if maxWait > 0 && waitedSoFar+waited+wait > maxWait {
+			return waited, true
+		}
+		fmt.Printf("⏳ Another juggle run was rate limited. Waiting %v before starting...\n", wait.Round(time.Second))
+		if !waitWithCountdown(ctx, wait) {
+			return waited, false
+		}
+		waited += wait
+	}
+	return waited, false
+}
+
+// attempts returns the larger of the loop's own rate limit retries and the
+// rate limits other runs hit in a row, so backoff keeps growing across runs
+func (r *sharedRateLimit) attempts(retries int) int {
+	if r.state == nil {
+		return retries
+	}
+	shared, err := r.state.Attempts()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read shared rate limit: %v\n", err)
+		return retries
+	}
+	return max(retries, shared)
+}
+
+// limited tells the other runs to hold off for wait
+func (r *sharedRateLimit) limited(wait time.Duration) {
+	if r.state == nil {
+		return
+	}
+	if err := r.state.RecordLimited(time.Now(), wait); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to share rate limit: %v\n", err)
+	}
+}
+
+// succeeded lifts the shared rate limit after a run that started at startedAt got through
+func (r *sharedRateLimit) succeeded(startedAt time.Time) {
+	if r.state == nil {
+		return
+	}
+	if err := r.state.RecordSuccess(startedAt); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to clear shared rate limit: %v\n", err)
+	}
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// reportMaxFiles caps the changed files listed in a run report
+const reportMaxFiles = 50
+
+var (
+	reportList   bool
+	reportOutput string
+)
+
+var agentReportCmd = &cobra.Command{
+	Use:   "report <session>",
+	Short: "Show the report of a session's latest agent run",
+	Long: `Show the Markdown report written at the end of a session's latest agent run.
+
+Every run that got through at least one iteration leaves a report in
+.juggle/sessions/<session>/reports/, covering how the run ended, its
+iterations and token usage, the balls it touched, a summary of the changes it
+made, failed gates, and what is left blocked.
+
+Examples:
+  juggle agent report my-feature
+  juggle agent report my-feature --output run.md
+  juggle agent report my-feature --list`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentReport,
+}
+
+func init() {
+	agentReportCmd.Flags().BoolVar(&reportList, "list", false, "List the session's reports, oldest first")
+	agentReportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the latest report to this file instead of printing it")
+	agentCmd.AddCommand(agentReportCmd)
+}
+
+func runAgentReport(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	storageID := sessionStorageID(sessionID)
+	projectDir, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	if reportList {
+		paths, err := sessionStore.ListReports(storageID)
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			fmt.Printf("No run reports for session %s\n", sessionID)
+			return nil
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return nil
+	}
+
+	path, err := sessionStore.LatestReport(storageID)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return fmt.Errorf("no run reports for session %s", sessionID)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read report: %w", err)
+	}
+
+	if reportOutput != "" {
+		if err := os.WriteFile(reportOutput, data, 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		fmt.Printf("✓ Report written to %s\n", reportOutput)
+		return nil
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+// runReport is what a run report covers, gathered once the run has ended
+type runReport struct {
+	sessionID    string
+	result       *AgentResult
+	record       *session.AgentRunRecord
+	touched      []touchedBall
+	blocked      []*session.Ball // Balls left blocked, touched or not
+	filesChanged int
+	linesChanged int
+	changedFiles []string
+}
+
+// touchedBall is a ball the run changed the state of or edited files for
+type touchedBall struct {
+	ball   *session.Ball
+	before session.BallState // Empty if the ball was created during the run
+	files  int
+}
+
+// writeRunReport writes the report of a run that got through at least one
+// iteration to the session's reports directory. Best-effort: returns the
+// report's path, or "" if none was written.
+func writeRunReport(config AgentLoopConfig, result *AgentResult, record *session.AgentRunRecord) string {
+	if result.Iterations == 0 {
+		return ""
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(config.ProjectDir, GetStoreConfig())
+	if err != nil {
+		return ""
+	}
+	storageID := sessionStorageID(config.SessionID)
+
+	report := gatherRunReport(config, sessionStore, storageID, result, record)
+	path, err := sessionStore.SaveReport(storageID, config.BallID, result.StartedAt, report.markdown())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return ""
+	}
+	return path
+}
+
+// gatherRunReport collects the balls the run touched and the changes it made
+// since the run's first snapshot. Signals that can't be read are left out.
+func gatherRunReport(config AgentLoopConfig, sessionStore *session.SessionStore, storageID string, result *AgentResult, record *session.AgentRunRecord) *runReport {
+	report := &runReport{sessionID: config.SessionID, result: result, record: record}
+
+	// The first snapshot was taken before the run's first iteration
+	var start *session.IterationSnapshot
+	if snapshots, err := sessionStore.LoadSnapshots(storageID); err == nil && len(snapshots) > 0 {
+		start = snapshots[0]
+	}
+	before := make(map[string]session.BallState)
+	if start != nil {
+		for _, saved := range start.Balls {
+			before[saved.ID] = saved.State
+		}
+	}
+
+	files := make(map[string]map[string]bool)
+	if metrics, err := sessionStore.LoadMetrics(storageID); err == nil {
+		for _, touch := range metrics.FileTouches {
+			if touch.BallID == "" || touch.At.Before(result.StartedAt) {
+				continue
+			}
+			if files[touch.BallID] == nil {
+				files[touch.BallID] = make(map[string]bool)
+			}
+			files[touch.BallID][touch.Path] = true
+		}
+	}
+
+	balls, _ := loadSessionBallsForSnapshot(config.ProjectDir, config.SessionID)
+	for _, ball := range balls {
+		if config.BallID != "" && ball.ID != config.BallID && ball.ShortID() != config.BallID {
+			continue
+		}
+		if ball.State == session.StateBlocked {
+			report.blocked = append(report.blocked, ball)
+		}
+		prev, existed := before[ball.ID]
+		if start == nil {
+			prev, existed = ball.State, true // No record of the state before the run
+		}
+		if !existed || prev != ball.State || len(files[ball.ID]) > 0 {
+			report.touched = append(report.touched, touchedBall{ball: ball, before: prev, files: len(files[ball.ID])})
+		}
+	}
+
+	if start != nil && start.Revision != "" {
+		backend := vcsBackendForProject(config.ProjectDir)
+		report.filesChanged, report.linesChanged, _ = backend.DiffStat(config.ProjectDir, start.Revision)
+		report.changedFiles, _ = backend.ChangedFiles(config.ProjectDir, start.Revision)
+		sort.Strings(report.changedFiles)
+	}
+	return report
+}
+
+// markdown renders the report
+func (r *runReport) markdown() string {
+	var b strings.Builder
+	result := r.result
+
+	fmt.Fprintf(&b, "# Agent Run: %s\n\n", r.sessionID)
+	fmt.Fprintf(&b, "- **Result:** %s\n", r.record.Result)
+	fmt.Fprintf(&b, "- **Started:** %s\n", result.StartedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "- **Duration:** %s\n", result.EndedAt.Sub(result.StartedAt).Round(time.Second))
+	iterations := fmt.Sprintf("%d of %d", result.Iterations, r.record.MaxIterations)
+	if result.NoOpIterations > 0 {
+		iterations += fmt.Sprintf(" (%d no-op)", result.NoOpIterations)
+	}
+	if avg, ok := session.AverageIterationDuration(result.IterationTimings, ""); ok {
+		iterations += fmt.Sprintf(", %s on average", avg.Round(time.Second))
+	}
+	fmt.Fprintf(&b, "- **Iterations:** %s\n", iterations)
+	if usage := session.FormatUsage(result.InputTokens, result.OutputTokens, result.CostUSD); usage != "" {
+		fmt.Fprintf(&b, "- **Tokens:** %s\n", usage)
+	}
+	fmt.Fprintf(&b, "- **Balls:** %d complete, %d blocked, %d total\n", result.BallsComplete, result.BallsBlocked, result.BallsTotal)
+	if result.ReviewPath != "" {
+		fmt.Fprintf(&b, "- **Review:** %s\n", result.ReviewPath)
+	}
+
+	b.WriteString("\n## Balls Touched\n\n")
+	if len(r.touched) == 0 {
+		b.WriteString("None.\n")
+	} else {
+		b.WriteString("| Ball | Title | State | Files |\n|---|---|---|---|\n")
+		for _, t := range r.touched {
+			state := string(t.ball.State)
+			if t.before != t.ball.State {
+				from := string(t.before)
+				if from == "" {
+					from = "new"
+				}
+				state = from + " → " + state
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %d |\n", t.ball.ID, markdownCell(t.ball.Title), state, t.files)
+		}
+	}
+
+	b.WriteString("\n## Changes\n\n")
+	if r.filesChanged == 0 {
+		b.WriteString("No changes.\n")
+	} else {
+		fmt.Fprintf(&b, "%d file(s) changed, %d line(s)\n\n", r.filesChanged, r.linesChanged)
+		for i, file := range r.changedFiles {
+			if i == reportMaxFiles {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(r.changedFiles)-reportMaxFiles)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", filepath.ToSlash(file))
+		}
+	}
+
+	var failed []GateResult
+	for _, gate := range result.GateResults {
+		if !gate.Passed {
+			failed = append(failed, gate)
+		}
+	}
+	if len(failed) > 0 {
+		b.WriteString("\n## Failed Gates\n\n")
+		for _, gate := range failed {
+			fmt.Fprintf(&b, "- Iteration %d: %s\n", gate.Iteration, gate)
+		}
+	}
+
+	b.WriteString("\n## Blockers\n\n")
+	reason := runReason(r.record)
+	if reason == "" && len(r.blocked) == 0 {
+		b.WriteString("None.\n")
+	}
+	if reason != "" {
+		fmt.Fprintf(&b, "- **Run:** %s\n", reason)
+	}
+	for _, ball := range r.blocked {
+		fmt.Fprintf(&b, "- **%s** %s", ball.ID, ball.Title)
+		if ball.BlockedReason != "" {
+			b.WriteString(": " + ball.BlockedReason)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// runReason is why a run stopped short, if it did
+func runReason(record *session.AgentRunRecord) string {
+	switch {
+	case record.BlockedReason != "":
+		return record.BlockedReason
+	case record.TimeoutMessage != "":
+		return record.TimeoutMessage
+	default:
+		return record.ErrorMessage
+	}
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
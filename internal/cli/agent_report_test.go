@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestRunReportMarkdown(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	result := &AgentResult{
+		Iterations:    3,
+		BallsComplete: 1,
+		BallsBlocked:  1,
+		BallsTotal:    2,
+		InputTokens:   12000,
+		OutputTokens:  1500,
+		GateResults:   []GateResult{{Iteration: 2, Name: "lint", Error: "exit status 1"}},
+		StartedAt:     start,
+		EndedAt:       start.Add(5 * time.Minute),
+	}
+	record := session.NewAgentRunRecord("my-feature", t.TempDir(), start)
+	record.MaxIterations = 10
+	record.SetMaxIterations(3, 1, 1, 2)
+
+	done := &session.Ball{ID: "proj-1", Title: "Parse a|b flags", State: session.StateComplete}
+	stuck := &session.Ball{ID: "proj-2", Title: "Add retries", State: session.StateBlocked, BlockedReason: "needs an API key"}
+	report := &runReport{
+		sessionID:    "my-feature",
+		result:       result,
+		record:       record,
+		touched:      []touchedBall{{ball: done, before: session.StateInProgress, files: 2}, {ball: stuck, before: session.StateBlocked}},
+		blocked:      []*session.Ball{stuck},
+		filesChanged: 2,
+		linesChanged: 40,
+		changedFiles: []string{"flags.go", "flags_test.go"},
+	}
+
+	md := report.markdown()
+	for _, want := range []string{
+		"# Agent Run: my-feature\n",
+		"- **Result:** max_iterations\n",
+		"- **Duration:** 5m0s\n",
+		"- **Iterations:** 3 of 10\n",
+		"- **Tokens:** 12.0k in / 1.5k out\n",
+		"| proj-1 | Parse a\\|b flags | in_progress → complete | 2 |\n",
+		"| proj-2 | Add retries | blocked | 0 |\n",
+		"2 file(s) changed, 40 line(s)\n\n- flags.go\n- flags_test.go\n",
+		"## Failed Gates\n\n- Iteration 2: lint failed",
+		"- **proj-2** Add retries: needs an API key\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "**Run:**") {
+		t.Errorf("Expected no run-level blocker for a run that hit max iterations, got:\n%s", md)
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// resumedBall is a blocked ball --resume-blocked reopened, with what blocked it
+type resumedBall struct {
+	ID     string
+	Title  string
+	Reason string
+}
+
+// resumeBlockedBalls moves the session's blocked balls (only the run's ball
+// with --ball) back to in_progress, logging the guidance to progress, and
+// returns the prompt message: the guidance plus what blocked each ball.
+func resumeBlockedBalls(config AgentLoopConfig) (string, error) {
+	balls, err := loadSessionBallsForSnapshot(config.ProjectDir, config.SessionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load blocked balls: %w", err)
+	}
+	if config.BallID != "" {
+		balls = session.ResolveBallByPrefix(balls, config.BallID)
+	}
+
+	var blocked []*session.Ball
+	for _, ball := range balls {
+		if ball.State == session.StateBlocked {
+			blocked = append(blocked, ball)
+		}
+	}
+
+	var resumed []resumedBall
+	err = unblockBalls(blocked, session.StateInProgress, config.Message, func(ball *session.Ball, reason string, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to reopen %s: %w", ball.ShortID(), err)
+		}
+		fmt.Printf("↩️  Reopened blocked ball %s %s\n", ball.ShortID(), StyleDim.Render("(was: "+reason+")"))
+		resumed = append(resumed, resumedBall{ID: ball.ID, Title: ball.Title, Reason: reason})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resumed) == 0 {
+		fmt.Println("No blocked balls to resume.")
+	}
+	return resumeBlockedMessage(resumed, config.Message), nil
+}
+
+// resumeBlockedMessage tells the agent which balls were reopened and why they
+// were blocked, followed by the user's guidance for getting past it
+func resumeBlockedMessage(resumed []resumedBall, message string) string {
+	if len(resumed) == 0 {
+		return message
+	}
+	var buf strings.Builder
+	buf.WriteString("These balls were blocked and have been reopened for this run:\n")
+	for _, ball := range resumed {
+		reason := ball.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Fprintf(&buf, "- %s: %s (was blocked: %s)\n", ball.ID, ball.Title, reason)
+	}
+	if message != "" {
+		buf.WriteString("\nGuidance for getting past the block:\n")
+		buf.WriteString(message)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\nIf the block still stands, mark the ball blocked again with the updated reason.")
+	return buf.String()
+}
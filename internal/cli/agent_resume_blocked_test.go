@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResumeBlockedMessage(t *testing.T) {
+	if got := resumeBlockedMessage(nil, "keep going"); got != "keep going" {
+		t.Errorf("expected the message unchanged with nothing reopened, got %q", got)
+	}
+
+	got := resumeBlockedMessage([]resumedBall{
+		{ID: "proj-1", Title: "Call the payments API", Reason: "missing API key"},
+		{ID: "proj-2", Title: "Fix flaky test"},
+	}, "the API key is now in .env")
+	for _, want := range []string{
+		"- proj-1: Call the payments API (was blocked: missing API key)",
+		"- proj-2: Fix flaky test (was blocked: no reason given)",
+		"Guidance for getting past the block:\nthe API key is now in .env",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in message, got:\n%s", want, got)
+		}
+	}
+
+	if got := resumeBlockedMessage([]resumedBall{{ID: "proj-1", Title: "x", Reason: "y"}}, ""); strings.Contains(got, "Guidance") {
+		t.Errorf("expected no guidance section without a message, got:\n%s", got)
+	}
+}
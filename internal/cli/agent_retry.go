@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// retryPolicies are the agent loop's backoff policies for each kind of failed run
+type retryPolicies struct {
+	rateLimit agent.RetryPolicy
+	overload  agent.RetryPolicy
+	crash     agent.RetryPolicy
+}
+
+// loadRetryPolicies builds the loop's retry policies from the defaults, the
+// global overload wait, the project's retry config and the loop config, in
+// increasing order of precedence
+func loadRetryPolicies(config AgentLoopConfig) retryPolicies {
+	policies := retryPolicies{
+		rateLimit: agent.DefaultRateLimitPolicy,
+		overload:  agent.DefaultOverloadPolicy,
+		crash:     agent.DefaultCrashPolicy,
+	}
+
+	// The global overload_retry_minutes setting predates per-project policies
+	overloadMinutes, _ := session.GetGlobalOverloadRetryMinutesWithOptions(GetConfigOptions())
+	policies.overload.Base = time.Duration(overloadMinutes) * time.Minute
+	policies.overload.Cap = policies.overload.Base
+
+	if retry, err := session.GetProjectRetry(config.ProjectDir); err == nil && retry != nil {
+		applyRetryConfig(&policies.rateLimit, retry.RateLimit)
+		applyRetryConfig(&policies.overload, retry.Overload)
+		applyRetryConfig(&policies.crash, retry.Crash)
+	}
+
+	// -1 means "use config", 0 means "no wait" (for testing), >0 is explicit minutes
+	if config.OverloadRetryMinutes >= 0 {
+		policies.overload.Base = time.Duration(config.OverloadRetryMinutes) * time.Minute
+		policies.overload.Cap = policies.overload.Base
+	}
+	return policies
+}
+
+// applyRetryConfig overrides the fields of policy that are set in cfg
+func applyRetryConfig(policy *agent.RetryPolicy, cfg *session.RetryPolicyConfig) {
+	if cfg == nil {
+		return
+	}
+	if cfg.BaseSeconds > 0 {
+		policy.Base = time.Duration(cfg.BaseSeconds) * time.Second
+		if policy.Cap < policy.Base {
+			policy.Cap = policy.Base
+		}
+	}
+	if cfg.CapSeconds > 0 {
+		policy.Cap = time.Duration(cfg.CapSeconds) * time.Second
+	}
+	if cfg.Jitter > 0 {
+		policy.Jitter = cfg.Jitter
+	}
+	switch {
+	case cfg.MaxAttempts < 0:
+		policy.MaxAttempts = 0 // Unlimited
+	case cfg.MaxAttempts > 0:
+		policy.MaxAttempts = cfg.MaxAttempts
+	}
+}
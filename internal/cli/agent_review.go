@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+const (
+	// reviewDiffLimit caps how much of the run's diff the reviewer sees
+	reviewDiffLimit = 100000
+	// reviewTimeout bounds the review iteration so it can't hold up the end of a run
+	reviewTimeout = 15 * time.Minute
+)
+
+// reviewEnabled reports whether the run ends with a review iteration, from
+// --review or the project's review setting
+func reviewEnabled(config AgentLoopConfig) bool {
+	if config.Review {
+		return true
+	}
+	enabled, err := session.GetProjectReview(config.ProjectDir)
+	return err == nil && enabled
+}
+
+// runReview runs the review iteration once every ball is terminal: a read-only,
+// plan-mode agent run given the run's diff and the acceptance criteria of the
+// balls it finished. The findings are written to the session's review.md.
+// Best-effort: returns the review's path, or "" if none was written.
+func runReview(ctx context.Context, runner agent.Runner, config AgentLoopConfig, sessionStore *session.SessionStore, storageID string) string {
+	// The first snapshot was taken before the run's first iteration
+	var start *session.IterationSnapshot
+	if snapshots, err := sessionStore.LoadSnapshots(storageID); err == nil && len(snapshots) > 0 {
+		start = snapshots[0]
+	}
+
+	balls, err := ballsCompletedSince(config.ProjectDir, config.SessionID, config.BallID, start)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load balls for review: %v\n", err)
+		return ""
+	}
+	if len(balls) == 0 {
+		fmt.Println("📝 No balls were completed in this run, skipping review")
+		return ""
+	}
+
+	diff := ""
+	if start != nil && start.Revision != "" {
+		diff, err = vcsBackendForProject(config.ProjectDir).Diff(config.ProjectDir, start.Revision)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to diff the run for review: %v\n", err)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("═════════════════════════════════ Review ══════════════════════════════════")
+	fmt.Printf("📝 Reviewing %d completed ball(s)...\n", len(balls))
+	result, err := runner.Run(agent.RunOptions{
+		Prompt:     generateReviewPrompt(config.ProjectDir, config.SessionID, balls, diff),
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      config.Model,
+		WorkingDir: config.ProjectDir,
+		Timeout:    reviewTimeout,
+		Context:    ctx,
+	})
+	if err == nil && result.Error != nil {
+		err = result.Error
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: review failed: %v\n", err)
+		logReviewToProgress(config.ProjectDir, storageID, fmt.Sprintf("Review failed: %v", err))
+		return ""
+	}
+	findings := strings.TrimSpace(result.Output)
+	if findings == "" {
+		fmt.Fprintln(os.Stderr, "Warning: review produced no output")
+		return ""
+	}
+
+	ids := make([]string, len(balls))
+	for i, ball := range balls {
+		ids[i] = ball.ID
+	}
+	content := fmt.Sprintf("# Review: %s\n\n_Reviewed %s on %s_\n\n%s\n",
+		config.SessionID, strings.Join(ids, ", "), time.Now().Format("2006-01-02 15:04"), findings)
+	if err := sessionStore.SaveReview(storageID, config.BallID, content); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return ""
+	}
+
+	path := sessionStore.ReviewFilePath(storageID, config.BallID)
+	logReviewToProgress(config.ProjectDir, storageID, fmt.Sprintf("Review of %d ball(s) written to %s", len(balls), path))
+	fmt.Printf("📝 Review written to %s\n", path)
+	return path
+}
+
+// generateReviewPrompt builds the review prompt: the session context and balls
+// as in agent refine, the run's diff, and the review instructions
+func generateReviewPrompt(projectDir, sessionID string, balls []*session.Ball, diff string) string {
+	var buf strings.Builder
+
+	writeRefineContext(&buf, projectDir, sessionID, balls)
+
+	buf.WriteString("<diff>\n")
+	switch {
+	case diff == "":
+		buf.WriteString("(no diff available - read the files the balls mention)\n")
+	case len(diff) > reviewDiffLimit:
+		buf.WriteString(diff[:reviewDiffLimit])
+		buf.WriteString("\n... (diff truncated)\n")
+	default:
+		buf.WriteString(diff)
+		if !strings.HasSuffix(diff, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString("</diff>\n\n")
+
+	writeRefineInstructions(&buf, agent.GetReviewPromptTemplate())
+	return buf.String()
+}
+
+// logReviewToProgress logs the review iteration to the session's progress file
+func logReviewToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[REVIEW] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackToIteration int
+	rollbackAfter       bool
+	rollbackList        bool
+	rollbackForce       bool
+)
+
+// agentRollbackCmd restores the working copy and ball states to a previous iteration
+var agentRollbackCmd = &cobra.Command{
+	Use:   "rollback <session>",
+	Short: "Discard agent work back to the start of an iteration",
+	Long: `Roll back the most recent agent run for a session to the state it was in
+immediately before the given iteration started, or with --after, right after
+it succeeded.
+
+Before each iteration, and again after each iteration that succeeds, juggle
+records the VCS revision and the state of every ball in the session. Rolling back:
+  - Resets the working copy and commits to the recorded revision
+    (git: reset --hard; jj: new change on top of the recorded revision)
+  - Restores ball states that changed since the snapshot
+  - Drops snapshots for later iterations
+
+Uncommitted changes are discarded. Untracked files are left in place.
+
+Examples:
+  # Discard everything the agent did from iteration 3 onwards
+  juggle agent rollback my-feature --to-iteration 3
+
+  # Keep iteration 3's work, discard everything after it
+  juggle agent rollback my-feature --to-iteration 3 --after
+
+  # See which iterations can be rolled back to
+  juggle agent rollback my-feature --list
+
+  # Skip the confirmation prompt
+  juggle agent rollback my-feature --to-iteration 3 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentRollback,
+}
+
+func init() {
+	agentRollbackCmd.Flags().IntVar(&rollbackToIteration, "to-iteration", 0, "Iteration to roll back to (state before it started)")
+	agentRollbackCmd.Flags().BoolVar(&rollbackAfter, "after", false, "Roll back to the state after the iteration succeeded instead")
+	agentRollbackCmd.Flags().BoolVar(&rollbackList, "list", false, "List the recorded snapshots without rolling back")
+	agentRollbackCmd.Flags().BoolVarP(&rollbackForce, "force", "f", false, "Skip confirmation prompt")
+
+	agentCmd.AddCommand(agentRollbackCmd)
+}
+
+// recordIterationSnapshot saves the current VCS revision and session ball states.
+// Best-effort: failures are logged but never stop the agent loop. Returns nil
+// if the snapshot could not be taken.
+func recordIterationSnapshot(sessionStore *session.SessionStore, projectDir, sessionID, storageID string, iteration int) *session.IterationSnapshot {
+	snap := takeIterationSnapshot(projectDir, sessionID, iteration)
+	if snap == nil {
+		return nil
+	}
+	if err := sessionStore.AppendSnapshot(storageID, snap); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record iteration snapshot: %v\n", err)
+	}
+	return snap
+}
+
+// recordCompletedSnapshot saves the VCS revision and session ball states after an
+// iteration succeeded, so the run can be rolled back to just after it. Best-effort
+// like recordIterationSnapshot.
+func recordCompletedSnapshot(sessionStore *session.SessionStore, projectDir, sessionID, storageID string, iteration int) {
+	snap := takeIterationSnapshot(projectDir, sessionID, iteration)
+	if snap == nil {
+		return
+	}
+	snap.Completed = true
+	if err := sessionStore.AppendSnapshot(storageID, snap); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record iteration snapshot: %v\n", err)
+	}
+}
+
+// takeIterationSnapshot captures the current VCS revision and session ball states
+func takeIterationSnapshot(projectDir, sessionID string, iteration int) *session.IterationSnapshot {
+	// Revision stays empty outside a repository; ball states are still worth keeping
+	revision, _ := vcsBackendForProject(projectDir).GetSnapshotRevision(projectDir)
+
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load balls for snapshot: %v\n", err)
+		return nil
+	}
+	return session.NewIterationSnapshot(iteration, revision, balls)
+}
+
+// loadSessionBallsForSnapshot loads all balls in the project belonging to the session,
+// regardless of state. The "all" meta-session includes every ball.
+func loadSessionBallsForSnapshot(projectDir, sessionID string) ([]*session.Ball, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+
+	if sessionID == "all" {
+		return balls, nil
+	}
+
+	filtered := make([]*session.Ball, 0, len(balls))
+	for _, ball := range balls {
+		for _, tag := range ball.Tags {
+			if tag == sessionID {
+				filtered = append(filtered, ball)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+func runAgentRollback(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if rollbackList {
+		return listRollbackSnapshots(cwd, args[0])
+	}
+	if !cmd.Flags().Changed("to-iteration") {
+		return fmt.Errorf("--to-iteration is required (see --list for the recorded iterations)")
+	}
+	return rollbackAgentRun(cwd, args[0], rollbackToIteration, rollbackAfter, rollbackForce)
+}
+
+// RollbackAgentRunForTest is an exported wrapper for testing (skips confirmation)
+func RollbackAgentRunForTest(projectDir, sessionID string, iteration int) error {
+	return rollbackAgentRun(projectDir, sessionID, iteration, false, true)
+}
+
+// RollbackAgentRunAfterForTest is RollbackAgentRunForTest for the state after the iteration
+func RollbackAgentRunAfterForTest(projectDir, sessionID string, iteration int) error {
+	return rollbackAgentRun(projectDir, sessionID, iteration, true, true)
+}
+
+// listRollbackSnapshots prints the iterations the session can be rolled back to
+func listRollbackSnapshots(cwd, sessionID string) error {
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+	snapshots, err := sessionStore.LoadSnapshots(sessionStorageID(sessionID))
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No iteration snapshots recorded for session %s\n", sessionID)
+		return nil
+	}
+
+	for _, snap := range snapshots {
+		point := fmt.Sprintf("before iteration %d", snap.Iteration)
+		flags := fmt.Sprintf("--to-iteration %d", snap.Iteration)
+		if snap.Completed {
+			point = fmt.Sprintf("after iteration %d", snap.Iteration)
+			flags += " --after"
+		}
+		revision := snap.Revision
+		if revision == "" {
+			revision = "(no revision)"
+		}
+		fmt.Printf("  %-20s %-14s %s  %s\n", point, truncate(revision, 12),
+			snap.CreatedAt.Format("2006-01-02 15:04"), StyleDim.Render(flags))
+	}
+	return nil
+}
+
+// rollbackAgentRun restores the project to the snapshot taken before the given
+// iteration, or after it succeeded when after is set
+func rollbackAgentRun(cwd, sessionID string, iteration int, after, force bool) error {
+	storageID := sessionStorageID(sessionID)
+
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	if running, info, _ := daemon.IsRunning(cwd, storageID); running {
+		return fmt.Errorf("agent daemon is running for session %s (PID %d), stop it before rolling back", sessionID, info.PID)
+	}
+
+	// Hold the session lock so no agent run starts mid-rollback
+	lock, err := sessionStore.AcquireSessionLock(storageID)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	snapshots, err := sessionStore.LoadSnapshots(storageID)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no iteration snapshots recorded for session %s", sessionID)
+	}
+
+	point := "before"
+	snap := session.FindSnapshot(snapshots, iteration)
+	if after {
+		point = "after"
+		snap = session.FindCompletedSnapshot(snapshots, iteration)
+	}
+	if snap == nil {
+		if after {
+			return fmt.Errorf("iteration %d has no snapshot from after it succeeded (see --list)", iteration)
+		}
+		return fmt.Errorf("no snapshot for iteration %d (available: 1-%d)",
+			iteration, snapshots[len(snapshots)-1].Iteration)
+	}
+
+	fmt.Printf("Rolling back session %s to %s iteration %d\n", sessionID, point, snap.Iteration)
+	if snap.Revision != "" {
+		fmt.Printf("  Revision: %s\n", snap.Revision)
+	} else {
+		fmt.Println("  Revision: (none recorded, VCS will not be reset)")
+	}
+	fmt.Printf("  Snapshot taken: %s\n", snap.CreatedAt.Format(time.RFC3339))
+	fmt.Println()
+
+	if !force {
+		fmt.Print("Uncommitted changes will be discarded. ")
+		confirmed, err := ConfirmSingleKey("Continue?")
+		if err != nil {
+			return fmt.Errorf("operation cancelled")
+		}
+		if !confirmed {
+			fmt.Println("Rollback cancelled.")
+			return nil
+		}
+	}
+
+	if snap.Revision != "" {
+		if err := vcsBackendForProject(cwd).ResetToRevision(cwd, snap.Revision); err != nil {
+			return fmt.Errorf("failed to reset to revision %s: %w", snap.Revision, err)
+		}
+		fmt.Printf("✓ Reset to revision %s\n", snap.Revision)
+	}
+
+	restored, err := restoreBallStates(cwd, snap)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Restored %d ball state(s)\n", restored)
+
+	if err := sessionStore.TruncateSnapshots(storageID, snap); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to truncate snapshots: %v\n", err)
+	}
+
+	entry := fmt.Sprintf("[%s] [ROLLBACK] Rolled back to %s iteration %d (revision %s), restored %d ball(s)\n",
+		time.Now().Format("2006-01-02 15:04:05"), point, snap.Iteration, snap.Revision, restored)
+	_ = sessionStore.AppendProgress(storageID, entry)
+
+	return nil
+}
+
+// restoreBallStates resets balls whose state changed since the snapshot.
+// Balls that no longer exist (deleted or archived) are skipped with a warning.
+// Returns the number of balls restored.
+func restoreBallStates(projectDir string, snap *session.IterationSnapshot) (int, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	restored := 0
+	for _, saved := range snap.Balls {
+		ball, err := store.GetBallByID(saved.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ball %s no longer exists, skipping\n", saved.ID)
+			continue
+		}
+
+		if ball.State == saved.State && ball.BlockedReason == saved.BlockedReason {
+			continue
+		}
+
+		ball.ForceSetState(saved.State)
+		ball.BlockedReason = saved.BlockedReason
+		if saved.State != session.StateComplete && saved.State != session.StateResearched {
+			ball.CompletedAt = nil
+		}
+
+		if err := store.UpdateBall(ball); err != nil {
+			return restored, fmt.Errorf("failed to restore ball %s: %w", ball.ID, err)
+		}
+		restored++
+	}
+
+	return restored, nil
+}
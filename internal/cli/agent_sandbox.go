@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// loadAgentSandbox returns the container the agent CLI should run in, or nil when
+// the project has no sandbox configured or it doesn't apply to this run
+func loadAgentSandbox(config AgentLoopConfig) (*agent.Sandbox, error) {
+	cfg, err := session.GetProjectSandbox(config.ProjectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sandbox config: %w", err)
+	}
+	if !cfg.Applies(config.Trust) {
+		return nil, nil
+	}
+
+	sandbox := &agent.Sandbox{
+		Engine:  cfg.Engine,
+		Image:   cfg.Image,
+		Env:     cfg.Env,
+		Network: cfg.Network,
+		Args:    cfg.Args,
+	}
+	if engine := sandbox.EngineName(); engine != provider.EngineDocker && engine != provider.EnginePodman {
+		return nil, fmt.Errorf("sandbox engine %q is not supported (use %q or %q)", engine, provider.EngineDocker, provider.EnginePodman)
+	}
+	if _, err := exec.LookPath(sandbox.EngineName()); err != nil {
+		return nil, fmt.Errorf("sandbox engine %q not found in PATH", sandbox.EngineName())
+	}
+
+	projectDir, err := filepath.Abs(config.ProjectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+	sandbox.Mounts = []string{projectDir}
+
+	// A linked worktree keeps its balls in the main repo, which the agent's juggle calls need
+	storageDir, err := session.ResolveStorageDir(projectDir, GetStoreConfig().JuggleDirName)
+	if err == nil && storageDir != projectDir {
+		sandbox.Mounts = append(sandbox.Mounts, filepath.Join(storageDir, juggleDirName()))
+	}
+	return sandbox, nil
+}
+
+// juggleDirName returns the name of the project's juggle directory
+func juggleDirName() string {
+	if name := GetStoreConfig().JuggleDirName; name != "" {
+		return name
+	}
+	return ".juggle"
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/supervisor"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleCron       string
+	scheduleIterations int
+	scheduleTrust      bool
+	scheduleModel      string
+	scheduleRemove     bool
+)
+
+var agentScheduleCmd = &cobra.Command{
+	Use:   "schedule [session-id]",
+	Short: "Run the agent on a session on a cron schedule",
+	Long: `Schedule agent runs of a session with a cron expression, e.g. to work
+through a session overnight.
+
+The supervisor (juggle supervisor start) launches each due run as a daemon, so
+scheduled runs show up in the monitor TUI like any other. It checks schedules
+on every poll, so a run starts up to one poll interval late. If the session's
+daemon is still going when the next run is due, that run is skipped.
+
+The expression has five fields in local time: minute, hour, day of month,
+month and day of week, or one of @hourly, @daily, @weekly, @monthly, @yearly.
+Schedules are stored in .juggle/sessions/<id>/schedule.json.
+
+Without a session, lists the project's schedules.
+
+Examples:
+  juggle agent schedule my-feature --cron "0 2 * * *" -n 5   # Every night at 02:00
+  juggle agent schedule all --cron "0 9 * * mon-fri"         # Weekday mornings
+  juggle agent schedule                                      # List schedules
+  juggle agent schedule my-feature --remove`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentSchedule,
+}
+
+func init() {
+	agentScheduleCmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression for the runs (e.g. \"0 2 * * *\")")
+	agentScheduleCmd.Flags().IntVarP(&scheduleIterations, "iterations", "n", 10, "Maximum iterations per run")
+	agentScheduleCmd.Flags().BoolVar(&scheduleTrust, "trust", false, "Run the agent with full permissions")
+	agentScheduleCmd.Flags().StringVarP(&scheduleModel, "model", "m", "", "Model for the runs (default: chosen per ball)")
+	agentScheduleCmd.Flags().BoolVar(&scheduleRemove, "remove", false, "Remove the session's schedule")
+	agentCmd.AddCommand(agentScheduleCmd)
+}
+
+func runAgentSchedule(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	if len(args) == 0 {
+		if scheduleCron != "" || scheduleRemove {
+			return fmt.Errorf("--cron and --remove need a session")
+		}
+		return listSchedules(sessionStore)
+	}
+
+	sessionID := args[0]
+	storageID := sessionStorageID(sessionID)
+	switch {
+	case scheduleRemove:
+		if scheduleCron != "" {
+			return fmt.Errorf("--cron and --remove can't be used together")
+		}
+		existing, err := sessionStore.LoadSchedule(storageID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("session %s has no schedule", sessionID)
+		}
+		if err := sessionStore.RemoveSchedule(storageID); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed the schedule of %s\n", sessionID)
+		refreshInstalledSchedule()
+		return nil
+
+	case scheduleCron == "":
+		sched, err := sessionStore.LoadSchedule(storageID)
+		if err != nil {
+			return err
+		}
+		if sched == nil {
+			return fmt.Errorf("session %s has no schedule (add one with --cron)", sessionID)
+		}
+		return printSchedules([]*session.Schedule{sched})
+	}
+
+	if scheduleIterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
+	if sessionID != "all" {
+		if _, err := sessionStore.LoadSession(sessionID); err != nil {
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, sessionID)
+		}
+	}
+
+	sched, err := session.NewSchedule(sessionID, scheduleCron, scheduleIterations, time.Now())
+	if err != nil {
+		return err
+	}
+	sched.Trust = scheduleTrust
+	sched.Model = scheduleModel
+	if err := sessionStore.SaveSchedule(storageID, sched); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Scheduled %s: %s, %d iterations per run\n", sessionID, sched.Cron, sched.Iterations)
+	fmt.Printf("  Next run: %s\n", sched.NextRun.Format("Mon 2006-01-02 15:04"))
+	refreshInstalledSchedule()
+	if running, _ := supervisor.IsSupervisorRunning(); !running {
+		fmt.Println(StyleDim.Render("  The supervisor launches scheduled runs; start it with: juggle supervisor start"))
+	}
+	return nil
+}
+
+// listSchedules prints the project's schedules, soonest first
+func listSchedules(sessionStore *session.SessionStore) error {
+	byStorage, err := sessionStore.ListSchedules()
+	if err != nil {
+		return err
+	}
+	schedules := make([]*session.Schedule, 0, len(byStorage))
+	for _, sched := range byStorage {
+		schedules = append(schedules, sched)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].NextRun.Before(schedules[j].NextRun) })
+
+	if len(schedules) == 0 && !GlobalOpts.JSONOutput {
+		fmt.Println("No scheduled runs. Add one with: juggle agent schedule <session> --cron \"0 2 * * *\"")
+		return nil
+	}
+	return printSchedules(schedules)
+}
+
+// printSchedules prints schedules as a table, or as JSON with --json
+func printSchedules(schedules []*session.Schedule) error {
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(schedules, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%-24s %-18s %-5s %-17s %s\n", "SESSION", "CRON", "ITER", "NEXT RUN", "LAST RUN")
+	for _, sched := range schedules {
+		last := "never"
+		if sched.LastRun != nil {
+			last = sched.LastRun.Format("2006-01-02 15:04") + " " + sched.LastResult
+		}
+		fmt.Printf("%-24s %-18s %-5d %-17s %s\n",
+			truncate(sched.SessionID, 24), truncate(sched.Cron, 18), sched.Iterations,
+			sched.NextRun.Format("2006-01-02 15:04"), StyleDim.Render(last))
+	}
+	return nil
+}
+
+// refreshInstalledSchedule updates the timer of a daemon installed with
+// juggle daemon install; the schedule itself is saved either way
+func refreshInstalledSchedule() {
+	if err := refreshDaemonSchedule(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update the daemon's timer: %v\n", err)
+	}
+}
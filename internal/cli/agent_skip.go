@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// skippedReason is the blocked reason of a ball skipped from the monitor TUI
+func skippedReason(requester string) string {
+	if requester == "" {
+		return "skipped by user via monitor TUI"
+	}
+	return fmt.Sprintf("skipped by %s via monitor TUI", requester)
+}
+
+// skipBall blocks the ball a skip_ball command names, noting who asked, so
+// the loop moves on to the next workable ball. It returns the ball, or nil
+// when it is already complete, researched or blocked.
+func skipBall(projectDir, sessionID, storageID, ballID, requester string) (*session.Ball, error) {
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	var ball *session.Ball
+	for _, b := range balls {
+		if b.ID == ballID || b.ShortID() == ballID {
+			ball = b
+			break
+		}
+	}
+	if ball == nil {
+		return nil, fmt.Errorf("ball %s not found in session %s", ballID, sessionID)
+	}
+	if ball.State != session.StatePending && ball.State != session.StateInProgress {
+		return nil, nil
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	reason := skippedReason(requester)
+	if err := ball.SetBlocked(reason); err != nil {
+		return nil, err
+	}
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, fmt.Errorf("failed to update ball %s: %w", ball.ID, err)
+	}
+
+	logSkipToProgress(projectDir, storageID, ball.ShortID(), reason)
+	return ball, nil
+}
+
+// logSkipToProgress logs a skipped ball to the session's progress file
+func logSkipToProgress(projectDir, sessionID, ballID, reason string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[SKIP] %s blocked: %s", ballID, reason)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestSkipBall(t *testing.T) {
+	projectDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.CreateSession("payments", "Payments"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	ball, err := session.NewBall(projectDir, "Add refunds", session.PriorityMedium)
+	if err != nil {
+		t.Fatalf("failed to create ball: %v", err)
+	}
+	ball.Tags = []string{"payments"}
+	ball.State = session.StateInProgress
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("failed to save ball: %v", err)
+	}
+
+	skipped, err := skipBall(projectDir, "payments", "payments", ball.ShortID(), "alice")
+	if err != nil {
+		t.Fatalf("skipBall failed: %v", err)
+	}
+	if skipped == nil || skipped.ID != ball.ID {
+		t.Fatalf("expected %s to be skipped, got %+v", ball.ID, skipped)
+	}
+
+	saved, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("failed to load ball: %v", err)
+	}
+	if saved.State != session.StateBlocked || saved.BlockedReason != "skipped by alice via monitor TUI" {
+		t.Errorf("expected ball blocked by alice's skip, got %s %q", saved.State, saved.BlockedReason)
+	}
+	progress, err := sessionStore.LoadProgress("payments")
+	if err != nil {
+		t.Fatalf("failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[SKIP] "+ball.ShortID()) {
+		t.Errorf("expected a [SKIP] progress entry, got %q", progress)
+	}
+
+	// A blocked ball has nothing left to skip
+	if again, err := skipBall(projectDir, "payments", "payments", ball.ID, ""); err != nil || again != nil {
+		t.Errorf("expected no skip of a blocked ball, got %+v, %v", again, err)
+	}
+	if _, err := skipBall(projectDir, "payments", "payments", "missing", ""); err == nil {
+		t.Error("expected an error for a ball outside the session")
+	}
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// Run states shown by juggle agent status
+const (
+	runStateDaemon     = "daemon"     // A daemon is running the loop
+	runStateForeground = "foreground" // A loop runs in a terminal
+	runStatePaused     = "paused"
+	runStateCrashed    = "crashed" // The daemon died mid-run
+	runStateEnded      = "ended"
+)
+
+var agentStatusRunning bool
+
+var agentStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the agent runs of all sessions",
+	Long: `Show the agent loop of each session that has run one: whether it is running
+(as a daemon or in a terminal), the ball it works on, its iteration and its
+last status. Runs in the current project are shown; use --all for every
+discovered project, and --json for machine-readable output.
+
+Examples:
+  juggle agent status
+  juggle agent status --all --running
+  juggle agent status --all --json`,
+	Args: cobra.NoArgs,
+	RunE: runAgentStatus,
+}
+
+func init() {
+	agentStatusCmd.Flags().BoolVar(&agentStatusRunning, "running", false, "Only show runs that are going")
+	agentCmd.AddCommand(agentStatusCmd)
+}
+
+// sessionRunStatus is one session's agent run as juggle agent status shows it
+type sessionRunStatus struct {
+	ProjectDir    string    `json:"project_dir"`
+	SessionID     string    `json:"session_id"`
+	State         string    `json:"state"` // daemon, foreground, paused, crashed or ended
+	PID           int       `json:"pid,omitempty"`
+	BallID        string    `json:"ball_id,omitempty"`
+	BallTitle     string    `json:"ball_title,omitempty"`
+	Iteration     int       `json:"iteration,omitempty"`
+	MaxIterations int       `json:"max_iterations,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	Provider      string    `json:"provider,omitempty"`
+	Status        string    `json:"status,omitempty"` // Last status the loop reported
+	StartedAt     time.Time `json:"started_at,omitempty"`
+	LastUpdated   time.Time `json:"last_updated,omitempty"`
+}
+
+// Running reports whether the run is still going
+func (r sessionRunStatus) Running() bool {
+	return r.State == runStateDaemon || r.State == runStateForeground || r.State == runStatePaused
+}
+
+func runAgentStatus(cmd *cobra.Command, args []string) error {
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	projects, err := DiscoverProjectsForCommand(config, nil)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+
+	var runs []sessionRunStatus
+	for _, projectDir := range projects {
+		for _, run := range projectAgentRuns(projectDir) {
+			if !agentStatusRunning || run.Running() {
+				runs = append(runs, run)
+			}
+		}
+	}
+	sortAgentRuns(runs)
+
+	if GlobalOpts.JSONOutput {
+		if runs == nil {
+			runs = []sessionRunStatus{}
+		}
+		data, err := json.MarshalIndent(runs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(runs) == 0 {
+		if agentStatusRunning {
+			fmt.Println("No agent runs are going.")
+		} else {
+			fmt.Println("No agent runs yet. Start one with: juggle agent run <session>")
+		}
+		return nil
+	}
+	printAgentRuns(runs, len(projects) > 1)
+	return nil
+}
+
+// projectAgentRuns returns the runs of a project's sessions that have run an
+// agent loop
+func projectAgentRuns(projectDir string) []sessionRunStatus {
+	sessionsDir := filepath.Join(projectDir, ".juggle", "sessions")
+	entries, err := os.ReadDir(sessionsDir)
+	if err != nil {
+		return nil
+	}
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return nil
+	}
+
+	var runs []sessionRunStatus
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if run, ok := sessionRunOf(projectDir, sessionStore, entry.Name()); ok {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// sessionRunOf reads a session's run from its PID, state and lock files.
+// Returns false for a session that never ran a loop.
+func sessionRunOf(projectDir string, sessionStore *session.SessionStore, storageID string) (sessionRunStatus, bool) {
+	sessionID := storageID
+	if storageID == "_all" {
+		sessionID = "all"
+	}
+	run := sessionRunStatus{ProjectDir: projectDir, SessionID: sessionID}
+
+	running, info, _ := daemon.IsRunning(projectDir, storageID)
+	state, _ := daemon.ReadStateFile(projectDir, storageID)
+	switch {
+	case running:
+		run.State = runStateDaemon
+		run.PID = info.PID
+		run.BallID = info.BallID
+		run.MaxIterations = info.MaxIterations
+		run.Model = info.Model
+		run.Provider = info.Provider
+		run.StartedAt = info.StartedAt
+	default:
+		if locked, lock := sessionStore.IsLocked(storageID); locked {
+			run.State = runStateForeground
+			if lock != nil {
+				run.PID = lock.PID
+				run.StartedAt = lock.StartedAt
+			}
+		} else if state == nil {
+			return run, false
+		} else if state.Crashed != nil {
+			run.State = runStateCrashed
+			run.PID = state.Crashed.PID
+		} else {
+			run.State = runStateEnded
+		}
+	}
+
+	if state != nil {
+		if state.Paused && running {
+			run.State = runStatePaused
+		}
+		if state.CurrentBallID != "" {
+			run.BallID = state.CurrentBallID
+			run.BallTitle = state.CurrentBallTitle
+		}
+		run.Iteration = state.Iteration
+		if state.MaxIterations > 0 {
+			run.MaxIterations = state.MaxIterations
+		}
+		if state.Model != "" {
+			run.Model = state.Model
+			run.Provider = state.Provider
+		}
+		run.Status = state.Status
+		if !state.StartedAt.IsZero() {
+			run.StartedAt = state.StartedAt
+		}
+		run.LastUpdated = state.LastUpdated
+	}
+	return run, true
+}
+
+// sortAgentRuns puts runs that are going first, then the most recent
+func sortAgentRuns(runs []sessionRunStatus) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].Running() != runs[j].Running() {
+			return runs[i].Running()
+		}
+		return runs[i].LastUpdated.After(runs[j].LastUpdated)
+	})
+}
+
+// printAgentRuns prints runs as a table, naming each run's project when they
+// come from several
+func printAgentRuns(runs []sessionRunStatus, showProject bool) {
+	fmt.Printf("%-24s %-18s %-9s %-22s %s\n", "SESSION", "STATE", "ITERATION", "BALL", "STATUS")
+	for _, run := range runs {
+		iteration := "-"
+		if run.MaxIterations > 0 {
+			iteration = fmt.Sprintf("%d/%d", run.Iteration, run.MaxIterations)
+		}
+		ball := run.BallID
+		if ball == "" {
+			ball = "-"
+		}
+		state := run.State
+		if run.PID > 0 && run.Running() {
+			state = fmt.Sprintf("%s/%d", state, run.PID)
+		}
+		status := run.Status
+		if !run.Running() && !run.LastUpdated.IsZero() {
+			status += fmt.Sprintf(" (%s ago)", formatDuration(time.Since(run.LastUpdated)))
+		}
+
+		line := fmt.Sprintf("%-24s %-18s %-9s %-22s %s", truncate(run.SessionID, 24), state, iteration, truncate(ball, 22), status)
+		switch run.State {
+		case runStateCrashed:
+			line = StyleBlocked.Render(line)
+		case runStateEnded:
+			line = StyleDim.Render(line)
+		}
+		fmt.Println(line)
+		if showProject {
+			fmt.Println(StyleProject.Render("  " + run.ProjectDir))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+)
+
+func TestProjectAgentRuns(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, id := range []string{"live", "done", "crashed", "never", "_all"} {
+		if err := os.MkdirAll(filepath.Join(projectDir, ".juggle", "sessions", id), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRun := func(storageID string, pid int, state *daemon.State) {
+		t.Helper()
+		if pid > 0 {
+			if err := daemon.WritePIDFile(projectDir, storageID, &daemon.Info{PID: pid, SessionID: storageID, MaxIterations: 5, BallID: "p-9"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := daemon.WriteStateFile(projectDir, storageID, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRun("live", os.Getpid(), &daemon.State{Running: true, Iteration: 2, MaxIterations: 5, CurrentBallID: "p-1", CurrentBallTitle: "Login", Status: "Working"})
+	writeRun("done", 0, &daemon.State{Iteration: 3, MaxIterations: 3, Status: "Complete"})
+	writeRun("crashed", 999999999, &daemon.State{Running: true, Iteration: 1, MaxIterations: 5})
+	writeRun("_all", 0, &daemon.State{Status: "No workable balls"})
+
+	runs := make(map[string]sessionRunStatus)
+	for _, run := range projectAgentRuns(projectDir) {
+		runs[run.SessionID] = run
+	}
+	if len(runs) != 4 {
+		t.Fatalf("Expected 4 runs (not the session that never ran), got %+v", runs)
+	}
+	if live := runs["live"]; live.State != runStateDaemon || live.PID != os.Getpid() || live.BallID != "p-1" || live.Iteration != 2 || live.MaxIterations != 5 || live.Status != "Working" {
+		t.Errorf("Unexpected live run: %+v", live)
+	}
+	if done := runs["done"]; done.State != runStateEnded || done.Running() || done.Status != "Complete" {
+		t.Errorf("Unexpected ended run: %+v", done)
+	}
+	if crashed := runs["crashed"]; crashed.State != runStateCrashed || crashed.PID != 999999999 || crashed.Status != daemon.StatusCrashed {
+		t.Errorf("Unexpected crashed run: %+v", crashed)
+	}
+	if _, ok := runs["all"]; !ok {
+		t.Errorf("Expected the all meta-session to be named all, got %+v", runs)
+	}
+}
+
+func TestRunningDaemons(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, id := range []string{"live", "paused", "done"} {
+		if err := os.MkdirAll(filepath.Join(projectDir, ".juggle", "sessions", id), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	daemon.WritePIDFile(projectDir, "live", &daemon.Info{PID: os.Getpid(), SessionID: "live"})
+	daemon.WriteStateFile(projectDir, "live", &daemon.State{Running: true, Iteration: 2, MaxIterations: 5})
+	daemon.WritePIDFile(projectDir, "paused", &daemon.Info{PID: os.Getpid(), SessionID: "paused"})
+	daemon.WriteStateFile(projectDir, "paused", &daemon.State{Running: true, Paused: true})
+	daemon.WriteStateFile(projectDir, "done", &daemon.State{Status: "Complete"})
+
+	daemons := runningDaemons([]string{projectDir})
+	if len(daemons) != 2 {
+		t.Fatalf("Expected the 2 running daemons, got %+v", daemons)
+	}
+	for _, d := range daemons {
+		if d.ProjectDir != projectDir || d.PID != os.Getpid() || d.Paused != (d.SessionID == "paused") {
+			t.Errorf("Unexpected daemon: %+v", d)
+		}
+	}
+}
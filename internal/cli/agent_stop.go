@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// stopPollInterval is how often a running loop checks for a stop request,
+// and juggle agent stop checks whether the loop has ended
+const stopPollInterval = 500 * time.Millisecond
+
+// errStopRequested is the cancel cause once juggle agent stop asks a run to end
+var errStopRequested = errors.New("stopped by juggle agent stop")
+
+var (
+	stopNoWait  bool
+	stopTimeout time.Duration
+)
+
+var agentStopCmd = &cobra.Command{
+	Use:   "stop <session>",
+	Short: "Safely stop a running agent loop",
+	Long: `Stop the agent loop running for a session, whether it runs as a daemon or
+in another terminal.
+
+The loop lets the agent finish its current tool call (Claude), or its current
+iteration (other providers), so no edit is cut off midway. It then moves
+uncommitted changes into their own revision for review, keeping the iterations
+it already committed, logs the stop to the session progress, and writes a final
+"Stopped" state for the monitor.
+
+The command waits for the loop to end; Ctrl+C stops waiting but leaves the
+stop request in place.
+
+Examples:
+  juggle agent stop my-feature
+  juggle agent stop my-feature --no-wait
+  juggle agent stop my-feature --timeout 30m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentStop,
+}
+
+func init() {
+	agentStopCmd.Flags().BoolVar(&stopNoWait, "no-wait", false, "Request the stop and return without waiting for the loop to end")
+	agentStopCmd.Flags().DurationVar(&stopTimeout, "timeout", 10*time.Minute, "How long to wait for the loop to end")
+	agentCmd.AddCommand(agentStopCmd)
+}
+
+func runAgentStop(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	storageID := sessionStorageID(sessionID)
+	projectDir, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	if !agentLoopActive(projectDir, sessionStore, storageID) {
+		return fmt.Errorf("no agent run is active for session %s", sessionID)
+	}
+	if err := daemon.RequestStop(projectDir, storageID); err != nil {
+		return fmt.Errorf("failed to request stop: %w", err)
+	}
+	fmt.Printf("🛑 Stop requested for session %s\n", sessionID)
+	if stopNoWait {
+		return nil
+	}
+
+	fmt.Println("⏳ Waiting for the current tool call to finish... (Ctrl+C to stop waiting)")
+	ctx, stop := signal.NotifyContext(commandContext(cmd), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, stopTimeout)
+	defer cancel()
+	for agentLoopActive(projectDir, sessionStore, storageID) {
+		if !sleepContext(ctx, stopPollInterval) {
+			return fmt.Errorf("the loop is still running; it stops at its next safe point (the request stays in place)")
+		}
+	}
+
+	status := daemon.StatusStopped
+	if state, err := daemon.ReadStateFile(projectDir, storageID); err == nil && state.Status != "" {
+		status = state.Status
+	}
+	fmt.Printf("✓ Agent loop ended: %s\n", status)
+	return nil
+}
+
+// agentLoopActive reports whether a daemon or foreground agent loop holds the session
+func agentLoopActive(projectDir string, sessionStore *session.SessionStore, storageID string) bool {
+	if daemon.IsAlive(projectDir, storageID) {
+		return true
+	}
+	locked, _ := sessionStore.IsLocked(storageID)
+	return locked
+}
+
+// stopWatcher watches for juggle agent stop while a loop runs. Between
+// iterations it cancels the run; during one it closes stop so the provider
+// can end the agent at a safe point, and the loop stops once the agent returns.
+type stopWatcher struct {
+	stop      chan struct{}
+	done      chan struct{}
+	running   atomic.Bool // An agent iteration is in progress
+	requested atomic.Bool
+}
+
+// newStopWatcher clears any stop request left from an earlier run, then
+// watches for a new one
+func newStopWatcher(projectDir, storageID string, cancel context.CancelCauseFunc) *stopWatcher {
+	_ = daemon.ClearStopRequest(projectDir, storageID)
+	w := &stopWatcher{stop: make(chan struct{}), done: make(chan struct{})}
+
+	go func() {
+		ticker := time.NewTicker(stopPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+			if !daemon.StopRequested(projectDir, storageID) {
+				continue
+			}
+			w.requested.Store(true)
+			close(w.stop)
+			if !w.running.Load() {
+				cancel(errStopRequested)
+			}
+			return
+		}
+	}()
+	return w
+}
+
+// close stops watching
+func (w *stopWatcher) close() {
+	close(w.done)
+}
+
+// isolateStoppedWork moves what a stopped iteration left uncommitted into its
+// own revision for review, keeping the iterations the run already committed
+func isolateStoppedWork(projectDir string) {
+	backend := vcsBackendForProject(projectDir)
+	if hasChanges, err := backend.HasChanges(projectDir); err != nil || !hasChanges {
+		return
+	}
+	target, _ := backend.GetSnapshotRevision(projectDir)
+	fmt.Printf("📊 Backing out uncommitted work...\n")
+	isolateWork(backend, projectDir, target, "STOPPED: "+daemon.StatusStopped)
+}
+
+// logStopToProgress logs a stop to the session's progress file
+func logStopToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[STOP] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
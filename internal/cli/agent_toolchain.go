@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/toolchain"
+)
+
+// systemPromptFile is the project's system prompt template, relative to the
+// juggle directory
+const systemPromptFile = "prompts/system.md"
+
+// systemPromptVars are the variables available to system prompt templates
+type systemPromptVars struct {
+	SessionID  string // Session being run ("all" for the whole repo)
+	BallCount  int    // Open balls in the session when the run starts
+	Provider   string // Agent provider type, e.g. "claude"
+	ProjectDir string // Project directory the agent works in
+	Default    string // The built-in autonomous directive
+}
+
+// agentSystemPrompt builds the headless system prompt: the autonomous
+// directive, or the session's or project's template rendered with vars,
+// followed by the project's toolchain briefing, if any
+func agentSystemPrompt(projectDir string, sessionPrompt string, vars systemPromptVars) (string, error) {
+	prompt, err := renderSystemPrompt(projectDir, sessionPrompt, vars)
+	if err != nil {
+		return "", err
+	}
+
+	briefing := toolchainBriefing(projectDir)
+	if briefing == "" {
+		return prompt, nil
+	}
+	return prompt + "\n\n" + briefing, nil
+}
+
+// renderSystemPrompt renders the session's system prompt template, falling back
+// to .juggle/prompts/system.md and then the built-in autonomous directive
+func renderSystemPrompt(projectDir string, sessionPrompt string, vars systemPromptVars) (string, error) {
+	text, source := sessionPrompt, "session system_prompt"
+	if strings.TrimSpace(text) == "" {
+		path := systemPromptPath(projectDir)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return agent.AutonomousSystemPrompt, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read system prompt template: %w", err)
+		}
+		text, source = string(data), path
+	}
+
+	tmpl, err := template.New(source).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid system prompt template %s: %w", source, err)
+	}
+	vars.Default = agent.AutonomousSystemPrompt
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("invalid system prompt template %s: %w", source, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// validateSystemPrompt checks a template parses and only uses known variables
+func validateSystemPrompt(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	_, err := renderSystemPrompt("", text, systemPromptVars{})
+	return err
+}
+
+// systemPromptPath returns where the project's system prompt template lives.
+// Worktrees share the main repo's template.
+func systemPromptPath(projectDir string) string {
+	storageDir, err := session.ResolveStorageDir(projectDir, GetStoreConfig().JuggleDirName)
+	if err != nil {
+		storageDir = projectDir
+	}
+	return filepath.Join(storageDir, juggleDirName(), systemPromptFile)
+}
+
+// toolchainBriefing describes how to build, test and lint the project,
+// from detected manifests and the project's toolchain config
+func toolchainBriefing(projectDir string) string {
+	config, err := session.GetProjectToolchain(projectDir)
+	if err != nil {
+		config = nil // Fall back to detection only
+	}
+	if config != nil && config.Disabled {
+		return ""
+	}
+
+	var overrides toolchain.Commands
+	var notes string
+	if config != nil {
+		overrides = toolchain.Commands{Build: config.Build, Test: config.Test, Lint: config.Lint}
+		notes = config.Notes
+	}
+	return toolchain.Briefing(toolchain.Detect(projectDir), overrides, notes)
+}
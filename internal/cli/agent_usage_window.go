@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	agentprovider "github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// usageWindowTracker paces one agent loop by the subscription plan's usage
+// windows; with no usage_window in global config it does nothing
+type usageWindowTracker struct {
+	window *session.UsageWindow
+}
+
+// newUsageWindowTracker loads the usage windows from global config.
+// Interactive runs aren't paced.
+func newUsageWindowTracker(config AgentLoopConfig) *usageWindowTracker {
+	t := &usageWindowTracker{}
+	if config.Interactive {
+		return t
+	}
+	window, err := session.LoadUsageWindowWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring usage window: %v\n", err)
+		return t
+	}
+	t.window = window
+	return t
+}
+
+// resetsAt returns when the current window resets; with no rolling window
+// open, the next run opens one
+func (t *usageWindowTracker) resetsAt(now time.Time) time.Time {
+	if resets := t.window.ResetsAt(now); !resets.IsZero() {
+		return resets
+	}
+	return now.Add(t.window.Config.Length())
+}
+
+// warnIfShort warns when the run's iterations likely need more tokens than
+// are left in the current window
+func (t *usageWindowTracker) warnIfShort(iterations int) {
+	if t.window == nil {
+		return
+	}
+	now := time.Now()
+	remaining, ok := t.window.Remaining(now)
+	perRun := t.window.PerRun()
+	need := perRun * iterations
+	if !ok || perRun == 0 || need <= remaining {
+		return
+	}
+
+	capacity := t.window.Capacity()
+	resets := (need - remaining + capacity - 1) / capacity
+	fmt.Printf("⚠️  This run may need ~%s tokens (%d iterations × ~%s) but ~%s are left in the usage window, which resets at %s.\n",
+		session.FormatTokenCount(need), iterations, session.FormatTokenCount(perRun),
+		session.FormatTokenCount(remaining), t.resetsAt(now).Format("15:04"))
+	fmt.Printf("    Expect it to wait for %d window reset(s), or run fewer iterations with -n.\n", resets)
+}
+
+// waitForCapacity waits for the window to reset when what is left of it
+// likely won't cover another run but a fresh window would. Returns false if
+// ctx was cancelled first.
+func (t *usageWindowTracker) waitForCapacity(ctx context.Context) bool {
+	if t.window == nil {
+		return true
+	}
+	now := time.Now()
+	remaining, ok := t.window.Remaining(now)
+	perRun := t.window.PerRun()
+	resets := t.window.ResetsAt(now)
+	if !ok || perRun == 0 || remaining >= perRun || t.window.Capacity() < perRun || resets.IsZero() {
+		return true
+	}
+
+	fmt.Printf("🔋 ~%s tokens left in the usage window, less than a run's ~%s. Waiting for it to reset at %s...\n",
+		session.FormatTokenCount(remaining), session.FormatTokenCount(perRun), resets.Format("15:04"))
+	return waitWithCountdown(ctx, time.Until(resets)+agentprovider.RetryAfterBuffer)
+}
+
+// record adds a run's tokens to the current window
+func (t *usageWindowTracker) record(startedAt time.Time, run *agent.RunResult) {
+	tokens := run.InputTokens + run.OutputTokens
+	if t.window == nil || tokens == 0 {
+		return
+	}
+	if err := t.window.Record(startedAt, tokens); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record usage window: %v\n", err)
+	}
+}
+
+// rateLimited learns the plan's capacity from a rate limit and returns how
+// long until the current window resets. ok is false when that isn't known.
+func (t *usageWindowTracker) rateLimited() (wait time.Duration, resets time.Time, ok bool) {
+	if t.window == nil {
+		return 0, time.Time{}, false
+	}
+	now := time.Now()
+	if err := t.window.RecordLimit(now); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record usage window: %v\n", err)
+	}
+	resets = t.window.ResetsAt(now)
+	if resets.IsZero() {
+		return 0, resets, false
+	}
+	return time.Until(resets) + agentprovider.RetryAfterBuffer, resets, true
+}
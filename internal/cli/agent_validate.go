@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/testselect"
+)
+
+// validationOutputLines is how much of a failing test run is kept in the progress log
+const validationOutputLines = 20
+
+// runValidation runs the project's validation command on the iteration's changes.
+// With affected test selection configured, only tests touched by the files changed
+// since the snapshot are run. Iterations that could only have worked on docs,
+// research or ops balls are not validated. Returns a failure summary, or "" if
+// validation passed, was skipped or isn't configured.
+func runValidation(projectDir, sessionID, storageID, ballID string, snap *session.IterationSnapshot) string {
+	validation, err := session.GetProjectValidation(projectDir)
+	if err != nil || !validation.Enabled() {
+		return ""
+	}
+
+	if onlyNonCodeBalls(projectDir, sessionID, ballID, snap) {
+		fmt.Println("🧪 Only non-code balls in this iteration, skipping validation")
+		return ""
+	}
+
+	command, args, skip := validationCommand(projectDir, validation, snap)
+	if skip {
+		fmt.Println("🧪 No tests affected by this iteration's changes, skipping validation")
+		return ""
+	}
+
+	if len(args) > 0 {
+		fmt.Printf("🧪 Validating: %s (%d affected)\n", command, len(args))
+	} else {
+		fmt.Printf("🧪 Validating: %s\n", command)
+	}
+
+	output, err := runValidationCommand(projectDir, command, args)
+	if err == nil {
+		return ""
+	}
+
+	summary := fmt.Sprintf("%s failed: %v", command, err)
+	if tail := tailLines(output, validationOutputLines); tail != "" {
+		logValidationToProgress(projectDir, storageID, summary+"\n"+tail)
+	} else {
+		logValidationToProgress(projectDir, storageID, summary)
+	}
+	return summary
+}
+
+// validationCommand chooses between the full validation command and an affected
+// test run. Returns skip=true when selection found no affected tests.
+// Any selection problem falls back to the full command.
+func validationCommand(projectDir string, validation *session.ValidationConfig, snap *session.IterationSnapshot) (command string, args []string, skip bool) {
+	if validation.Affected == "" {
+		return validation.Command, nil, false
+	}
+	if snap == nil || snap.Revision == "" {
+		// Nothing to diff against
+		return validation.Command, nil, false
+	}
+
+	changed, err := vcsBackendForProject(projectDir).ChangedFiles(projectDir, snap.Revision)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to list changed files for test selection: %v\n", err)
+		return validation.Command, nil, false
+	}
+
+	var selection testselect.Selection
+	switch validation.Affected {
+	case session.AffectedGo:
+		selection, err = testselect.Go(projectDir, changed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to select affected Go packages: %v\n", err)
+			return validation.Command, nil, false
+		}
+	case session.AffectedJest:
+		selection = testselect.Jest(projectDir, changed)
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: unknown validation.affected mode %q, running full validation\n", validation.Affected)
+		return validation.Command, nil, false
+	}
+
+	if selection.Full {
+		return validation.Command, nil, false
+	}
+	if selection.Empty() {
+		return "", nil, true
+	}
+	return validation.SelectionCommand(), selection.Args, false
+}
+
+// onlyNonCodeBalls reports whether every ball the iteration could have worked
+// on is a non-code kind. Those are the balls still open when the iteration
+// started, plus any created during it; with a ballID, only that ball.
+func onlyNonCodeBalls(projectDir, sessionID, ballID string, snap *session.IterationSnapshot) bool {
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return false
+	}
+
+	finished := make(map[string]bool)
+	if snap != nil {
+		for _, saved := range snap.Balls {
+			finished[saved.ID] = saved.State == session.StateComplete || saved.State == session.StateResearched
+		}
+	}
+
+	considered := 0
+	for _, ball := range balls {
+		if ballID != "" {
+			if ball.ID != ballID && ball.ShortID() != ballID {
+				continue
+			}
+		} else if finished[ball.ID] {
+			continue
+		}
+		if ball.Kind.IsCode() {
+			return false
+		}
+		considered++
+	}
+	return considered > 0
+}
+
+// runValidationCommand runs a shell command with args appended, streaming its
+// output to the console and returning it.
+// Args are passed as positional parameters so paths are never re-parsed by the shell.
+func runValidationCommand(projectDir, command string, args []string) (string, error) {
+	shellArgs := append([]string{"-c", command + ` "$@"`, "sh"}, args...)
+	cmd := exec.Command("sh", shellArgs...)
+	cmd.Dir = projectDir
+
+	var output strings.Builder
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &output)
+	err := cmd.Run()
+	return output.String(), err
+}
+
+// reopenBallsAfterValidation moves balls completed since the snapshot back to
+// in_progress so the next iteration fixes the failing gates or tests.
+// With a ballID, only that ball is considered. Returns the reopened IDs.
+func reopenBallsAfterValidation(projectDir, sessionID, ballID string, snap *session.IterationSnapshot) ([]string, error) {
+	completed, err := ballsCompletedSince(projectDir, sessionID, ballID, snap)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	var reopened []string
+	for _, ball := range completed {
+		ball.ForceSetState(session.StateInProgress)
+		ball.CompletedAt = nil
+		if err := store.UpdateBall(ball); err != nil {
+			return reopened, fmt.Errorf("failed to update ball %s: %w", ball.ID, err)
+		}
+		reopened = append(reopened, ball.ShortID())
+	}
+	return reopened, nil
+}
+
+// tailLines returns the last n lines of s
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// logValidationToProgress logs a validation failure to the session's progress file
+func logValidationToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[VALIDATION] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
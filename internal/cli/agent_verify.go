@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+const (
+	// verifyDiffLimit caps how much of the iteration's diff the verifier sees
+	verifyDiffLimit = 60000
+	// verifyTimeout bounds each verifier run so a stuck check can't stall the loop
+	verifyTimeout = 5 * time.Minute
+)
+
+// verifyCompletedBalls asks a second model to check each ball completed since
+// the snapshot against its acceptance criteria and the iteration's diff. Balls
+// the verifier rejects are moved back to in_progress and the reason is logged
+// to progress for the next iteration. A verifier that fails to run or gives no
+// verdict accepts the ball. Returns the reopened IDs; nil when verify_completion
+// is off.
+func verifyCompletedBalls(ctx context.Context, runner agent.Runner, projectDir, sessionID, storageID, ballID string, snap *session.IterationSnapshot) []string {
+	enabled, model, err := session.GetProjectVerifyCompletion(projectDir)
+	if err != nil || !enabled {
+		return nil
+	}
+
+	balls, err := ballsCompletedSince(projectDir, sessionID, ballID, snap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load balls for verification: %v\n", err)
+		return nil
+	}
+	if len(balls) == 0 {
+		return nil
+	}
+
+	diff := ""
+	if snap != nil && snap.Revision != "" {
+		diff, err = vcsBackendForProject(projectDir).Diff(projectDir, snap.Revision)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to diff iteration for verification: %v\n", err)
+		}
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create store: %v\n", err)
+		return nil
+	}
+
+	var reopened []string
+	for _, ball := range balls {
+		fmt.Printf("🔍 Verifying %s with %s...\n", ball.ShortID(), model)
+		result, err := runner.Run(agent.RunOptions{
+			Prompt:     buildVerifyPrompt(ball, diff),
+			Mode:       agent.ModeHeadless,
+			Permission: agent.PermissionPlan,
+			Model:      model,
+			WorkingDir: projectDir,
+			Timeout:    verifyTimeout,
+			Context:    ctx,
+		})
+		if err == nil && result.Error != nil {
+			err = result.Error
+		}
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: verifier failed for %s, accepting completion: %v\n", ball.ShortID(), err)
+		case result.Blocked:
+			reason := result.BlockedReason
+			if reason == "" {
+				reason = "verifier rejected the completion"
+			}
+			ball.ForceSetState(session.StateInProgress)
+			ball.CompletedAt = nil
+			ball.CompletionNote = ""
+			if err := store.UpdateBall(ball); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to reopen ball %s: %v\n", ball.ID, err)
+				continue
+			}
+			logVerifyToProgress(projectDir, storageID, fmt.Sprintf("%s reopened: %s", ball.ID, reason))
+			fmt.Printf("↩️  Ball %s reopened by verifier: %s\n", ball.ShortID(), reason)
+			reopened = append(reopened, ball.ShortID())
+		case result.Complete:
+			fmt.Printf("✓ Verified %s\n", ball.ShortID())
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: verifier gave no verdict for %s, accepting completion\n", ball.ShortID())
+		}
+	}
+	return reopened
+}
+
+// ballsCompletedSince returns the session balls that reached complete after the
+// snapshot was taken. With a ballID, only that ball is considered.
+func ballsCompletedSince(projectDir, sessionID, ballID string, snap *session.IterationSnapshot) ([]*session.Ball, error) {
+	return ballsEnteredStateSince(projectDir, sessionID, ballID, snap, session.StateComplete)
+}
+
+// ballsEnteredStateSince returns the session balls that moved into state after
+// the snapshot was taken. With a ballID, only that ball is considered.
+func ballsEnteredStateSince(projectDir, sessionID, ballID string, snap *session.IterationSnapshot, state session.BallState) ([]*session.Ball, error) {
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := make(map[string]session.BallState)
+	if snap != nil {
+		for _, saved := range snap.Balls {
+			before[saved.ID] = saved.State
+		}
+	}
+
+	var entered []*session.Ball
+	for _, ball := range balls {
+		if ball.State != state || before[ball.ID] == state {
+			continue
+		}
+		if ballID != "" && ball.ID != ballID && ball.ShortID() != ballID {
+			continue
+		}
+		entered = append(entered, ball)
+	}
+	return entered, nil
+}
+
+// buildVerifyPrompt asks the verifier to judge one ball against its criteria
+// and answer with a COMPLETE or BLOCKED promise
+func buildVerifyPrompt(ball *session.Ball, diff string) string {
+	var b strings.Builder
+	b.WriteString("You are reviewing another agent's work. Do not change any files.\n")
+	b.WriteString("Decide whether the changes below meet every acceptance criterion of the task.\n\n")
+
+	fmt.Fprintf(&b, "TASK: %s\n", ball.Title)
+	if ball.Context != "" {
+		fmt.Fprintf(&b, "\nCONTEXT:\n%s\n", ball.Context)
+	}
+	b.WriteString("\nACCEPTANCE CRITERIA:\n")
+	if len(ball.AcceptanceCriteria) == 0 {
+		b.WriteString("(none listed; judge against the task and context)\n")
+	}
+	for i, ac := range ball.AcceptanceCriteria {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, ac)
+	}
+	if ball.CompletionNote != "" {
+		fmt.Fprintf(&b, "\nAGENT'S COMPLETION NOTE:\n%s\n", ball.CompletionNote)
+	}
+
+	b.WriteString("\nCHANGES:\n")
+	switch {
+	case strings.TrimSpace(diff) == "":
+		b.WriteString("(no diff available; inspect the working copy if you need to)\n")
+	case len(diff) > verifyDiffLimit:
+		fmt.Fprintf(&b, "```diff\n%s\n```\n(diff truncated at %d bytes)\n", diff[:verifyDiffLimit], verifyDiffLimit)
+	default:
+		fmt.Fprintf(&b, "```diff\n%s\n```\n", strings.TrimRight(diff, "\n"))
+	}
+
+	b.WriteString("\nIf every criterion is met, reply with <promise>COMPLETE</promise>.\n")
+	b.WriteString("Otherwise reply with <promise>BLOCKED: which criteria are not met and what is missing</promise>.\n")
+	return b.String()
+}
+
+// logVerifyToProgress logs a rejected completion to the session's progress file
+func logVerifyToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	entry := fmt.Sprintf("[VERIFY] %s", message)
+	_ = sessionStore.AppendProgress(sessionID, entry)
+}
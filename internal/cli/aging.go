@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// agedMarker follows the priority of balls that aging raised
+const agedMarker = "↑"
+
+// ageProjectBalls raises the priority of the project's pending balls that sat
+// untouched for aging.after_days, and returns them. Best-effort: problems are
+// reported as warnings so they never get in the way of the command.
+func ageProjectBalls(projectDir string) []*session.Ball {
+	aging, err := session.GetProjectAging(projectDir)
+	if err != nil || aging.After() <= 0 {
+		return nil
+	}
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil
+	}
+	aged, err := store.AgeBalls(aging, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to age balls in %s: %v\n", projectDir, err)
+		return nil
+	}
+	return aged
+}
+
+// printAgedBalls reports the balls aging just raised
+func printAgedBalls(aged []*session.Ball) {
+	for _, ball := range aged {
+		bump := ball.Aged[len(ball.Aged)-1]
+		fmt.Printf("%s %s aged: %s → %s (untouched since %s)\n", agedMarker, ball.ShortID(), bump.From, bump.To, ball.LastActivity.Format("2006-01-02"))
+	}
+}
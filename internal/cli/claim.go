@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	claimTTL   time.Duration
+	claimBy    string
+	claimNote  string
+	claimForce bool
+)
+
+var claimCmd = &cobra.Command{
+	Use:   "claim <ball-id>",
+	Short: "Claim a ball so agents leave it alone",
+	Long: `Mark a ball as being worked on by a person. Agent loops, daemons, parallel
+runs and the queue skip claimed balls until the claim is released with
+'juggle unclaim' or its TTL runs out.
+
+Claiming again extends your claim. Taking over someone else's active claim
+needs --force.
+
+Examples:
+  juggle claim a1b2
+  juggle claim a1b2 --ttl 30m --note "fixing the flaky test by hand"
+  juggle claim a1b2 --by alice --force`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runClaim,
+}
+
+var unclaimCmd = &cobra.Command{
+	Use:   "unclaim <ball-id>",
+	Short: "Release a claim so agents can work on the ball again",
+	Long: `Remove a ball's claim. The next agent iteration can pick the ball up again.
+
+Examples:
+  juggle unclaim a1b2`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runUnclaim,
+}
+
+func init() {
+	claimCmd.Flags().DurationVar(&claimTTL, "ttl", session.DefaultClaimTTL, "How long the claim lasts (e.g. 30m, 2h)")
+	claimCmd.Flags().StringVar(&claimBy, "by", "", "Who holds the claim (default: current user)")
+	claimCmd.Flags().StringVarP(&claimNote, "note", "m", "", "What you're doing with the ball")
+	claimCmd.Flags().BoolVar(&claimForce, "force", false, "Take over another person's active claim")
+	rootCmd.AddCommand(claimCmd)
+	rootCmd.AddCommand(unclaimCmd)
+}
+
+func runClaim(cmd *cobra.Command, args []string) error {
+	if claimTTL <= 0 {
+		return fmt.Errorf("--ttl must be positive")
+	}
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	who := claimBy
+	if who == "" {
+		who = claimOwner()
+	}
+	if existing := ball.ActiveClaim(); existing != nil && existing.By != who && !claimForce {
+		return fmt.Errorf("ball %s is already claimed by %s until %s (use --force to take it over)",
+			ball.ShortID(), existing.By, existing.ExpiresAt.Format("15:04"))
+	}
+
+	ball.Claim(who, claimNote, claimTTL)
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ Claimed %s for %s %s\n", ball.ShortID(), who,
+		StyleDim.Render(fmt.Sprintf("(%s, until %s)", formatDuration(claimTTL), ball.Claimed.ExpiresAt.Format("15:04"))))
+	if locked, _ := session.IsBallLocked(ball.WorkingDir, ball.ID); locked {
+		fmt.Println(StyleDim.Render("An agent is working on this ball now; it moves on after its current iteration."))
+	}
+
+	entry := fmt.Sprintf("[CLAIM] %s claimed by %s for %s; leave it alone until released", ball.ID, who, formatDuration(claimTTL))
+	if claimNote != "" {
+		entry += ": " + claimNote
+	}
+	logToBallSessions(ball, entry)
+	return nil
+}
+
+func runUnclaim(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+	if ball.Claimed == nil {
+		fmt.Printf("%s is not claimed\n", ball.ShortID())
+		return nil
+	}
+
+	who := ball.Claimed.By
+	ball.ReleaseClaim()
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+	fmt.Printf("✓ Released %s %s\n", ball.ShortID(), StyleDim.Render("(was claimed by "+who+")"))
+	logToBallSessions(ball, fmt.Sprintf("[CLAIM] %s released by %s", ball.ID, who))
+	return nil
+}
+
+// claimOwner names the person claiming a ball when --by isn't given
+func claimOwner() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "someone"
+}
+
+// logToBallSessions logs an entry to the progress of the ball's sessions
+func logToBallSessions(ball *session.Ball, entry string) {
+	sessionStore, err := session.NewSessionStore(ball.WorkingDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+	for _, tag := range ball.Tags {
+		if _, err := sessionStore.LoadSession(tag); err != nil {
+			continue
+		}
+		_ = sessionStore.AppendProgress(tag, entry)
+	}
+}
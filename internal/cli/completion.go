@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ohare93/juggle/internal/examples"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
@@ -102,6 +103,20 @@ func CompletePriorities(cmd *cobra.Command, args []string, toComplete string) ([
 	return priorities, cobra.ShellCompDirectiveNoFileComp
 }
 
+// CompleteBallKinds provides completion for ball kind values
+func CompleteBallKinds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	kinds := []string{"code", "docs", "research", "ops"}
+	return kinds, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteExampleTopics provides completion for workflow example topics
+func CompleteExampleTopics(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return examples.Topics(), cobra.ShellCompDirectiveNoFileComp
+}
+
 // CompleteTags provides completion for existing tags
 func CompleteTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	// Get current working directory
@@ -6,9 +6,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	agentprovider "github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/notify"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/vcs"
 	"github.com/spf13/cobra"
@@ -29,7 +34,27 @@ Commands:
 
   config delay show           Show current iteration delay settings
   config delay set <mins>     Set delay between iterations (in minutes)
-  config delay clear          Remove iteration delay`,
+  config delay clear          Remove iteration delay
+
+  config quiet-hours show     Show when agent runs pause
+  config quiet-hours set <w>  Pause agent runs during these hours
+  config quiet-hours clear    Remove quiet hours
+
+  config usage-window show    Show the plan's usage window and usage
+  config usage-window set     Configure the plan's usage windows
+  config usage-window clear   Stop pacing runs by usage windows
+
+  config agent-limits show    Show the resource limits of daemons' agents
+  config agent-limits set     Limit the CPU, disk and memory of daemons' agents
+  config agent-limits clear   Remove the limits
+
+  config desktop-notify show  Show which desktop notifications agent runs send
+  config desktop-notify set   Turn on desktop notifications
+  config desktop-notify clear Turn off desktop notifications
+
+  config email show           Show where agent runs email summaries
+  config email set            Email summaries over SMTP
+  config email clear          Stop emailing summaries`,
 	RunE: runConfigShow,
 }
 
@@ -62,6 +87,9 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	// Iteration delay
 	fmt.Printf("  %s: %d\n", keyStyle.Render("iteration_delay_minutes"), globalConfig.IterationDelayMinutes)
 	fmt.Printf("  %s: %d\n", keyStyle.Render("iteration_delay_fuzz"), globalConfig.IterationDelayFuzz)
+	if globalConfig.QuietHours != "" {
+		fmt.Printf("  %s: %s\n", keyStyle.Render("quiet_hours"), globalConfig.QuietHours)
+	}
 
 	// Show warnings for unknown fields
 	unknownFields := globalConfig.GetUnknownFields()
@@ -250,6 +278,8 @@ func init() {
 	configPathsPruneCmd.Flags().BoolVarP(&configPathsPruneYesFlag, "yes", "y", false, "Skip confirmation prompt")
 	configPathsCmd.AddCommand(configPathsListCmd)
 	configPathsCmd.AddCommand(configPathsPruneCmd)
+	configPathsCmd.AddCommand(configPathsIgnoreCmd)
+	configPathsCmd.AddCommand(configPathsUnignoreCmd)
 
 	configCmd.AddCommand(configPathsCmd)
 }
@@ -262,8 +292,10 @@ var configPathsCmd = &cobra.Command{
 	Long: `Manage the search paths used to discover juggle projects.
 
 Commands:
-  config paths list           List current search paths
-  config paths prune          Remove non-existent paths from config`,
+  config paths list               List search paths and ignore patterns
+  config paths prune              Remove non-existent and ignored paths from config
+  config paths ignore <pattern>   Exclude matching directories from discovery
+  config paths unignore <pattern> Remove an ignore pattern`,
 	RunE: runConfigPathsList,
 }
 
@@ -275,8 +307,9 @@ var configPathsListCmd = &cobra.Command{
 
 var configPathsPruneCmd = &cobra.Command{
 	Use:   "prune",
-	Short: "Remove non-existent paths from search_paths",
-	Long: `Remove non-existent directories from the global search_paths config.
+	Short: "Remove non-existent and ignored paths from search_paths",
+	Long: `Remove non-existent directories, and directories matching ignore_paths,
+from the global search_paths config.
 
 This is useful for cleaning up stale paths (e.g., from deleted projects or
 old test directories that were accidentally added).
@@ -285,6 +318,32 @@ Use --yes (-y) to skip the confirmation prompt.`,
 	RunE: runConfigPathsPrune,
 }
 
+var configPathsIgnoreCmd = &cobra.Command{
+	Use:   "ignore <pattern>",
+	Short: "Exclude matching directories from project discovery",
+	Long: `Add a pattern to ignore_paths. Matching directories are never added to
+search_paths and are skipped by --all views, the TUI and the supervisor, even
+if they contain a .juggle directory.
+
+A pattern without a slash matches any path component; a pattern with a slash
+is matched against the whole path and its parents. Patterns use glob syntax
+and may start with ~/.
+
+Examples:
+  juggle config paths ignore node_modules
+  juggle config paths ignore '*.bak'
+  juggle config paths ignore '~/src/vendor/*'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigPathsIgnore,
+}
+
+var configPathsUnignoreCmd = &cobra.Command{
+	Use:   "unignore <pattern>",
+	Short: "Remove an ignore pattern",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigPathsUnignore,
+}
+
 func runConfigPathsList(cmd *cobra.Command, args []string) error {
 	config, err := session.LoadConfigWithOptions(GetConfigOptions())
 	if err != nil {
@@ -302,15 +361,82 @@ func runConfigPathsList(cmd *cobra.Command, args []string) error {
 
 	existStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
 	missingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	ignoredStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 
 	for _, path := range config.SearchPaths {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
+		if config.IsIgnoredPath(path) {
+			fmt.Printf("  %s %s\n", ignoredStyle.Render("-"), ignoredStyle.Render(path+" (ignored)"))
+		} else if _, err := os.Stat(path); os.IsNotExist(err) {
 			fmt.Printf("  %s %s\n", missingStyle.Render("✗"), path)
 		} else {
 			fmt.Printf("  %s %s\n", existStyle.Render("✓"), path)
 		}
 	}
 
+	printIgnorePaths(config)
+	return nil
+}
+
+// printIgnorePaths lists the ignore patterns, if any
+func printIgnorePaths(config *session.Config) {
+	if len(config.IgnorePaths) == 0 {
+		return
+	}
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	fmt.Println()
+	fmt.Println(labelStyle.Render("Ignore Patterns:"))
+	fmt.Println()
+	for _, pattern := range config.IgnorePaths {
+		fmt.Printf("  %s\n", pattern)
+	}
+}
+
+func runConfigPathsIgnore(cmd *cobra.Command, args []string) error {
+	pattern := strings.TrimSpace(args[0])
+	if pattern == "" {
+		return fmt.Errorf("pattern cannot be empty")
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !config.AddIgnorePath(pattern) {
+		fmt.Printf("Already ignoring: %s\n", pattern)
+		return nil
+	}
+	if err := config.SaveWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Ignoring: %s\n", pattern)
+	matched := 0
+	for _, path := range config.SearchPaths {
+		if config.IsIgnoredPath(path) {
+			matched++
+		}
+	}
+	if matched > 0 {
+		fmt.Printf("%d search path(s) now ignored. Run 'juggle config paths prune' to remove them.\n", matched)
+	}
+	return nil
+}
+
+func runConfigPathsUnignore(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !config.RemoveIgnorePath(args[0]) {
+		return fmt.Errorf("not an ignore pattern: %s", args[0])
+	}
+	if err := config.SaveWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Printf("No longer ignoring: %s\n", args[0])
 	return nil
 }
 
@@ -324,7 +450,7 @@ func runConfigPathsPrune(cmd *cobra.Command, args []string) error {
 	var toRemove []string
 	var toKeep []string
 	for _, path := range config.SearchPaths {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, err := os.Stat(path); os.IsNotExist(err) || config.IsIgnoredPath(path) {
 			toRemove = append(toRemove, path)
 		} else {
 			toKeep = append(toKeep, path)
@@ -332,12 +458,12 @@ func runConfigPathsPrune(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(toRemove) == 0 {
-		fmt.Println("No non-existent paths to remove.")
+		fmt.Println("No non-existent or ignored paths to remove.")
 		return nil
 	}
 
 	// Show what will be removed
-	fmt.Printf("Found %d non-existent path(s) to remove:\n", len(toRemove))
+	fmt.Printf("Found %d non-existent or ignored path(s) to remove:\n", len(toRemove))
 	for _, path := range toRemove {
 		fmt.Printf("  - %s\n", path)
 	}
@@ -882,6 +1008,651 @@ func runConfigDelayClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// configQuietHoursCmd is the parent command for quiet hours settings
+var configQuietHoursCmd = &cobra.Command{
+	Use:   "quiet-hours",
+	Short: "Manage agent quiet hours (global)",
+	Long: `Manage the hours during which agent runs don't start new iterations.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+During quiet hours a running agent finishes its current iteration, then waits
+with a countdown until the quiet hours end, so autonomous runs only spend
+tokens outside working hours. Interactive runs and runs started with
+--ignore-quiet-hours are not affected.
+
+Quiet hours are comma-separated HH:MM-HH:MM windows in local time, each
+optionally limited to a day or range of days. A window ending before it
+starts runs past midnight.
+
+Commands:
+  config quiet-hours show           Show current quiet hours
+  config quiet-hours set <windows>  Set quiet hours
+  config quiet-hours clear          Remove quiet hours
+
+Examples:
+  juggle config quiet-hours set 09:00-18:00
+  juggle config quiet-hours set "mon-fri 09:00-18:00"
+  juggle config quiet-hours set "mon-fri 09:00-12:00, mon-fri 13:00-18:00"
+  juggle config quiet-hours clear`,
+	RunE: runConfigQuietHoursShow,
+}
+
+var configQuietHoursShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show current quiet hours",
+	RunE:  runConfigQuietHoursShow,
+}
+
+var configQuietHoursSetCmd = &cobra.Command{
+	Use:   "set <windows>",
+	Short: "Set the hours during which agent runs pause",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runConfigQuietHoursSet,
+}
+
+var configQuietHoursClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove quiet hours",
+	RunE:  runConfigQuietHoursClear,
+}
+
+func init() {
+	configQuietHoursCmd.AddCommand(configQuietHoursShowCmd)
+	configQuietHoursCmd.AddCommand(configQuietHoursSetCmd)
+	configQuietHoursCmd.AddCommand(configQuietHoursClearCmd)
+
+	configCmd.AddCommand(configQuietHoursCmd)
+}
+
+func runConfigQuietHoursShow(cmd *cobra.Command, args []string) error {
+	quiet, err := session.GetGlobalQuietHoursWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load quiet hours: %w", err)
+	}
+
+	if quiet == nil {
+		fmt.Println("No quiet hours configured.")
+		fmt.Println("\nSet them with: juggle config quiet-hours set 09:00-18:00")
+		return nil
+	}
+
+	fmt.Printf("Quiet hours: %s\n", quiet)
+	if until := quiet.Until(time.Now()); !until.IsZero() {
+		fmt.Printf("Currently quiet until %s\n", until.Format("Mon Jan 2 15:04"))
+	}
+	return nil
+}
+
+func runConfigQuietHoursSet(cmd *cobra.Command, args []string) error {
+	// Accept the windows unquoted, e.g. set mon-fri 09:00-18:00
+	spec := strings.Join(args, " ")
+	if err := session.UpdateGlobalQuietHoursWithOptions(GetConfigOptions(), spec); err != nil {
+		return fmt.Errorf("failed to save quiet hours: %w", err)
+	}
+
+	fmt.Printf("Set quiet hours: %s\n", strings.TrimSpace(spec))
+	return nil
+}
+
+func runConfigQuietHoursClear(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalQuietHoursWithOptions(GetConfigOptions(), ""); err != nil {
+		return fmt.Errorf("failed to clear quiet hours: %w", err)
+	}
+
+	fmt.Println("Cleared quiet hours.")
+	return nil
+}
+
+// Usage window command variables
+var (
+	configUsageWindowHours    int
+	configUsageWindowResetsAt string
+	configUsageWindowTokens   int
+)
+
+// configUsageWindowCmd is the parent command for usage window settings
+var configUsageWindowCmd = &cobra.Command{
+	Use:   "usage-window",
+	Short: "Manage subscription plan usage windows (global)",
+	Long: `Manage the usage windows of your subscription plan, e.g. the 5-hour
+windows of Claude Pro and Max plans, whose limits reset at the end of each window.
+
+This is a global setting stored in ~/.juggle/config.json. The usage agent runs
+observe is shared by every project and stored in ~/.juggle/usage_window.json.
+
+With usage windows configured, agent runs:
+  - record the tokens each iteration uses in the current window
+  - warn at the start when the run likely won't fit in what's left of it
+  - wait for the window to reset, rather than backing off, when rate limited
+  - wait for the reset before an iteration that likely won't fit
+
+The tokens per window are learned from rate limits unless set with --tokens.
+
+Commands:
+  config usage-window show    Show the current window and usage
+  config usage-window set     Configure the windows
+  config usage-window clear   Remove the configuration
+
+Examples:
+  juggle config usage-window set                      # Rolling 5-hour windows
+  juggle config usage-window set --resets-at 14:00    # Windows reset at 14:00, 19:00, ...
+  juggle config usage-window set --hours 5 --tokens 2000000
+  juggle config usage-window clear`,
+	RunE: runConfigUsageWindowShow,
+}
+
+var configUsageWindowShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the current usage window and usage",
+	RunE:  runConfigUsageWindowShow,
+}
+
+var configUsageWindowSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Configure the plan's usage windows",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigUsageWindowSet,
+}
+
+var configUsageWindowClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Stop pacing agent runs by usage windows",
+	RunE:  runConfigUsageWindowClear,
+}
+
+func init() {
+	configUsageWindowSetCmd.Flags().IntVar(&configUsageWindowHours, "hours", session.DefaultUsageWindowHours, "Window length in hours")
+	configUsageWindowSetCmd.Flags().StringVar(&configUsageWindowResetsAt, "resets-at", "", "A time a window reset (HH:MM today or RFC 3339); default: rolling windows starting with the first run")
+	configUsageWindowSetCmd.Flags().IntVar(&configUsageWindowTokens, "tokens", 0, "Tokens available per window (0 = learn from rate limits)")
+
+	configUsageWindowCmd.AddCommand(configUsageWindowShowCmd)
+	configUsageWindowCmd.AddCommand(configUsageWindowSetCmd)
+	configUsageWindowCmd.AddCommand(configUsageWindowClearCmd)
+
+	configCmd.AddCommand(configUsageWindowCmd)
+}
+
+func runConfigUsageWindowShow(cmd *cobra.Command, args []string) error {
+	window, err := session.LoadUsageWindowWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load usage window: %w", err)
+	}
+
+	if window == nil {
+		fmt.Println("No usage window configured.")
+		fmt.Println("\nConfigure one with: juggle config usage-window set")
+		return nil
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	fmt.Println(labelStyle.Render("Usage Window:"))
+	fmt.Println()
+	fmt.Printf("  Length: %v\n", window.Config.Length())
+	if window.Config.ResetAt != nil {
+		fmt.Printf("  Resets: every %v from %s\n", window.Config.Length(), window.Config.ResetAt.Local().Format("2006-01-02 15:04"))
+	} else {
+		fmt.Println("  Resets: rolling, starting with the first run after a window ends")
+	}
+	switch {
+	case window.Config.TokenLimit > 0:
+		fmt.Printf("  Capacity: %s tokens (configured)\n", session.FormatTokenCount(window.Config.TokenLimit))
+	case window.State.ObservedLimit > 0:
+		fmt.Printf("  Capacity: ~%s tokens (learned from the last rate limit)\n", session.FormatTokenCount(window.State.ObservedLimit))
+	default:
+		fmt.Println("  Capacity: unknown until a run is rate limited")
+	}
+	if perRun := window.PerRun(); perRun > 0 {
+		fmt.Printf("  Average iteration: ~%s tokens\n", session.FormatTokenCount(perRun))
+	}
+
+	fmt.Println()
+	now := time.Now()
+	start := window.Start(now)
+	if start.IsZero() {
+		fmt.Println("No window is open; the next run opens one.")
+		return nil
+	}
+	fmt.Printf("Current window: %s - %s\n", start.Format("15:04"), window.ResetsAt(now).Format("15:04"))
+	fmt.Printf("  Used: %s tokens\n", session.FormatTokenCount(window.Used(now)))
+	if remaining, ok := window.Remaining(now); ok {
+		fmt.Printf("  Left: ~%s tokens\n", session.FormatTokenCount(remaining))
+	}
+	return nil
+}
+
+func runConfigUsageWindowSet(cmd *cobra.Command, args []string) error {
+	if configUsageWindowHours < 1 {
+		return fmt.Errorf("invalid --hours: %d (must be at least 1)", configUsageWindowHours)
+	}
+	if configUsageWindowTokens < 0 {
+		return fmt.Errorf("invalid --tokens: %d (must be a non-negative integer)", configUsageWindowTokens)
+	}
+
+	window := &session.UsageWindowConfig{Hours: configUsageWindowHours, TokenLimit: configUsageWindowTokens}
+	if configUsageWindowResetsAt != "" {
+		resetAt, err := parseResetsAt(configUsageWindowResetsAt, time.Now())
+		if err != nil {
+			return err
+		}
+		window.ResetAt = &resetAt
+	}
+	if err := session.UpdateGlobalUsageWindowWithOptions(GetConfigOptions(), window); err != nil {
+		return fmt.Errorf("failed to save usage window: %w", err)
+	}
+
+	fmt.Printf("Set usage window: %d hour(s)", configUsageWindowHours)
+	if window.ResetAt != nil {
+		fmt.Printf(", resetting from %s", window.ResetAt.Format("2006-01-02 15:04"))
+	} else {
+		fmt.Print(", rolling")
+	}
+	if window.TokenLimit > 0 {
+		fmt.Printf(", %s tokens per window", session.FormatTokenCount(window.TokenLimit))
+	}
+	fmt.Println()
+	return nil
+}
+
+// parseResetsAt parses --resets-at: an RFC 3339 time, or HH:MM for that time
+// today in local time
+func parseResetsAt(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --resets-at: %s (want HH:MM or an RFC 3339 time)", value)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+}
+
+func runConfigUsageWindowClear(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalUsageWindowWithOptions(GetConfigOptions(), nil); err != nil {
+		return fmt.Errorf("failed to clear usage window: %w", err)
+	}
+
+	fmt.Println("Cleared usage window.")
+	return nil
+}
+
+// Agent limits command variables
+var (
+	configAgentLimitsNice      int
+	configAgentLimitsIOClass   string
+	configAgentLimitsMaxMemory string
+)
+
+// configAgentLimitsCmd is the parent command for agent resource limits
+var configAgentLimitsCmd = &cobra.Command{
+	Use:   "agent-limits",
+	Short: "Manage resource limits for agents started by daemons (global)",
+	Long: `Manage the CPU, disk and memory limits of the agent CLIs that daemons start
+(juggle agent run --daemon, juggle daemon and the supervisor), so overnight
+runs don't starve the machine. Foreground runs aren't limited.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+Each limit uses the tool the machine has for it and is skipped where there is
+none; the daemon's log says which ones apply:
+  --nice          nice
+  --io-class      ionice (Linux)
+  --max-memory    a systemd user scope (cgroup), else prlimit (Linux);
+                  the container's memory limit when the run is sandboxed
+
+Commands:
+  config agent-limits show    Show the limits and how they apply here
+  config agent-limits set     Set the limits
+  config agent-limits clear   Remove the limits
+
+Examples:
+  juggle config agent-limits set --nice 10 --io-class idle --max-memory 4G
+  juggle config agent-limits clear`,
+	RunE: runConfigAgentLimitsShow,
+}
+
+var configAgentLimitsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the resource limits of daemons' agents",
+	RunE:  runConfigAgentLimitsShow,
+}
+
+var configAgentLimitsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Limit the CPU, disk and memory of daemons' agents",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigAgentLimitsSet,
+}
+
+var configAgentLimitsClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the resource limits of daemons' agents",
+	RunE:  runConfigAgentLimitsClear,
+}
+
+func init() {
+	configAgentLimitsSetCmd.Flags().IntVar(&configAgentLimitsNice, "nice", 0, "CPU niceness, 1-19 (0 = unchanged)")
+	configAgentLimitsSetCmd.Flags().StringVar(&configAgentLimitsIOClass, "io-class", "", "Disk I/O class: idle or best-effort")
+	configAgentLimitsSetCmd.Flags().StringVar(&configAgentLimitsMaxMemory, "max-memory", "", "Memory limit, e.g. 4G")
+
+	configAgentLimitsCmd.AddCommand(configAgentLimitsShowCmd)
+	configAgentLimitsCmd.AddCommand(configAgentLimitsSetCmd)
+	configAgentLimitsCmd.AddCommand(configAgentLimitsClearCmd)
+
+	configCmd.AddCommand(configAgentLimitsCmd)
+}
+
+func runConfigAgentLimitsShow(cmd *cobra.Command, args []string) error {
+	limits, err := daemonAgentLimits()
+	if err != nil {
+		return err
+	}
+	if limits == nil {
+		fmt.Println("No agent limits configured.")
+		fmt.Println("\nConfigure them with: juggle config agent-limits set")
+		return nil
+	}
+	fmt.Printf("Agent limits: %s\n", limits.Describe())
+	return nil
+}
+
+func runConfigAgentLimitsSet(cmd *cobra.Command, args []string) error {
+	cfg := &session.AgentLimitsConfig{
+		Nice:      configAgentLimitsNice,
+		IOClass:   configAgentLimitsIOClass,
+		MaxMemory: configAgentLimitsMaxMemory,
+	}
+	limits, err := resourceLimits(cfg)
+	if err != nil {
+		return err
+	}
+	if limits == nil {
+		return fmt.Errorf("nothing to set: pass --nice, --io-class or --max-memory")
+	}
+	if err := session.UpdateGlobalAgentLimitsWithOptions(GetConfigOptions(), cfg); err != nil {
+		return fmt.Errorf("failed to save agent limits: %w", err)
+	}
+
+	fmt.Printf("Set agent limits: %s\n", limits.Describe())
+	return nil
+}
+
+func runConfigAgentLimitsClear(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalAgentLimitsWithOptions(GetConfigOptions(), nil); err != nil {
+		return fmt.Errorf("failed to clear agent limits: %w", err)
+	}
+
+	fmt.Println("Cleared agent limits.")
+	return nil
+}
+
+var (
+	configDesktopNotifyEvents        []string
+	configDesktopNotifyRateLimitWait int
+)
+
+// configDesktopNotifyCmd is the parent command for desktop notifications
+var configDesktopNotifyCmd = &cobra.Command{
+	Use:   "desktop-notify",
+	Short: "Manage desktop notifications from agent runs (global)",
+	Long: `Manage the OS notifications agent runs on this machine show: notify-send
+on Linux, osascript on macOS and a toast on Windows. They are off until set.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+By default a notification is shown when a run ends, when a ball becomes
+blocked and when a rate-limit wait is 15 minutes or more.
+
+Commands:
+  config desktop-notify show    Show the notification settings
+  config desktop-notify set     Turn notifications on
+  config desktop-notify clear   Turn notifications off
+
+Examples:
+  juggle config desktop-notify set
+  juggle config desktop-notify set --events run_end,ball_blocked
+  juggle config desktop-notify set --rate-limit-wait 30
+  juggle config desktop-notify clear`,
+	RunE: runConfigDesktopNotifyShow,
+}
+
+var configDesktopNotifyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show which desktop notifications agent runs send",
+	RunE:  runConfigDesktopNotifyShow,
+}
+
+var configDesktopNotifySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Turn on desktop notifications",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigDesktopNotifySet,
+}
+
+var configDesktopNotifyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Turn off desktop notifications",
+	RunE:  runConfigDesktopNotifyClear,
+}
+
+func init() {
+	configDesktopNotifySetCmd.Flags().StringSliceVar(&configDesktopNotifyEvents, "events", nil, "Event types to show (default: ball_blocked, rate_limit_wait, run_end)")
+	configDesktopNotifySetCmd.Flags().IntVar(&configDesktopNotifyRateLimitWait, "rate-limit-wait", 0, "Show rate-limit waits at least this many minutes long (default: 15)")
+
+	configDesktopNotifyCmd.AddCommand(configDesktopNotifyShowCmd)
+	configDesktopNotifyCmd.AddCommand(configDesktopNotifySetCmd)
+	configDesktopNotifyCmd.AddCommand(configDesktopNotifyClearCmd)
+
+	configCmd.AddCommand(configDesktopNotifyCmd)
+}
+
+func runConfigDesktopNotifyShow(cmd *cobra.Command, args []string) error {
+	cfg, err := session.GetGlobalDesktopNotificationsWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load desktop notification settings: %w", err)
+	}
+	if cfg == nil || !cfg.Enabled {
+		fmt.Println("Desktop notifications are off.")
+		fmt.Println("\nTurn them on with: juggle config desktop-notify set")
+		return nil
+	}
+	fmt.Printf("Desktop notifications: %s\n", describeDesktopNotifications(cfg))
+	return nil
+}
+
+func runConfigDesktopNotifySet(cmd *cobra.Command, args []string) error {
+	for _, event := range configDesktopNotifyEvents {
+		if !slices.Contains(notify.EventTypes, event) {
+			return fmt.Errorf("unknown event %q (must be one of %s)", event, strings.Join(notify.EventTypes, ", "))
+		}
+	}
+	if configDesktopNotifyRateLimitWait < 0 {
+		return fmt.Errorf("--rate-limit-wait must be 0 or more minutes")
+	}
+	cfg := &session.DesktopNotificationsConfig{
+		Enabled:              true,
+		Events:               configDesktopNotifyEvents,
+		RateLimitWaitMinutes: configDesktopNotifyRateLimitWait,
+	}
+	if err := session.UpdateGlobalDesktopNotificationsWithOptions(GetConfigOptions(), cfg); err != nil {
+		return fmt.Errorf("failed to save desktop notification settings: %w", err)
+	}
+
+	fmt.Printf("Turned on desktop notifications: %s\n", describeDesktopNotifications(cfg))
+	return nil
+}
+
+func runConfigDesktopNotifyClear(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalDesktopNotificationsWithOptions(GetConfigOptions(), nil); err != nil {
+		return fmt.Errorf("failed to clear desktop notification settings: %w", err)
+	}
+
+	fmt.Println("Turned off desktop notifications.")
+	return nil
+}
+
+// describeDesktopNotifications lists the events shown, e.g.
+// "run_end, ball_blocked, rate_limit_wait (15m0s or more)"
+func describeDesktopNotifications(cfg *session.DesktopNotificationsConfig) string {
+	events := cfg.Events
+	if len(events) == 0 {
+		events = notify.DefaultDesktopEvents
+	}
+	parts := make([]string, len(events))
+	for i, event := range events {
+		parts[i] = event
+		if event == notify.EventRateLimitWait {
+			parts[i] += fmt.Sprintf(" (%v or more)", cfg.GetRateLimitWait())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+var (
+	configEmailHost     string
+	configEmailPort     int
+	configEmailUsername string
+	configEmailFrom     string
+	configEmailTo       []string
+	configEmailEvents   []string
+)
+
+// configEmailCmd is the parent command for summary emails
+var configEmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Manage summary emails from agent runs (global)",
+	Long: `Manage the emails agent runs send over SMTP, for long unattended runs. By
+default an email is sent when a run ends, with what it got done, and when a
+ball is blocked and needs a human.
+
+This is a global setting stored in ~/.juggle/config.json. The SMTP password
+is read from ` + session.EnvSMTPPassword + `, or from email.password in the
+config file.
+
+Port 587 (the default) upgrades to TLS with STARTTLS; port 465 uses TLS from
+the start.
+
+Commands:
+  config email show    Show the email settings
+  config email set     Send summary emails
+  config email clear   Stop sending summary emails
+
+Examples:
+  juggle config email set --host smtp.example.com --username me@example.com \
+    --from me@example.com --to me@example.com
+  juggle config email set --host localhost --port 25 --from juggle@myhost \
+    --to me@example.com --events run_end
+  juggle config email clear`,
+	RunE: runConfigEmailShow,
+}
+
+var configEmailShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show where agent runs email summaries",
+	RunE:  runConfigEmailShow,
+}
+
+var configEmailSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Email summaries of agent runs over SMTP",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEmailSet,
+}
+
+var configEmailClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Stop emailing summaries of agent runs",
+	RunE:  runConfigEmailClear,
+}
+
+func init() {
+	configEmailSetCmd.Flags().StringVar(&configEmailHost, "host", "", "SMTP server")
+	configEmailSetCmd.Flags().IntVar(&configEmailPort, "port", 0, "SMTP port (default: 587)")
+	configEmailSetCmd.Flags().StringVar(&configEmailUsername, "username", "", "SMTP login (omit for no authentication)")
+	configEmailSetCmd.Flags().StringVar(&configEmailFrom, "from", "", "Sender address")
+	configEmailSetCmd.Flags().StringSliceVar(&configEmailTo, "to", nil, "Recipient addresses")
+	configEmailSetCmd.Flags().StringSliceVar(&configEmailEvents, "events", nil, "Event types to email (default: ball_blocked, run_end)")
+
+	configEmailCmd.AddCommand(configEmailShowCmd)
+	configEmailCmd.AddCommand(configEmailSetCmd)
+	configEmailCmd.AddCommand(configEmailClearCmd)
+
+	configCmd.AddCommand(configEmailCmd)
+}
+
+func runConfigEmailShow(cmd *cobra.Command, args []string) error {
+	cfg, err := session.GetGlobalEmailWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load email settings: %w", err)
+	}
+	if cfg == nil {
+		fmt.Println("Summary emails are off.")
+		fmt.Println("\nTurn them on with: juggle config email set --host <server> --from <address> --to <address>")
+		return nil
+	}
+	fmt.Println(describeEmail(cfg))
+	if cfg.Username != "" && cfg.GetPassword() == "" {
+		fmt.Printf("\nWarning: no SMTP password; set %s\n", session.EnvSMTPPassword)
+	}
+	return nil
+}
+
+func runConfigEmailSet(cmd *cobra.Command, args []string) error {
+	if configEmailHost == "" || configEmailFrom == "" || len(configEmailTo) == 0 {
+		return fmt.Errorf("--host, --from and --to are required")
+	}
+	for _, event := range configEmailEvents {
+		if !slices.Contains(notify.EventTypes, event) {
+			return fmt.Errorf("unknown event %q (must be one of %s)", event, strings.Join(notify.EventTypes, ", "))
+		}
+	}
+	if configEmailPort < 0 || configEmailPort > 65535 {
+		return fmt.Errorf("invalid --port: %d", configEmailPort)
+	}
+
+	cfg := &session.EmailConfig{
+		Host:     configEmailHost,
+		Port:     configEmailPort,
+		Username: configEmailUsername,
+		From:     configEmailFrom,
+		To:       configEmailTo,
+		Events:   configEmailEvents,
+	}
+	// Keep a password already in the config file
+	if existing, err := session.GetGlobalEmailWithOptions(GetConfigOptions()); err == nil && existing != nil {
+		cfg.Password = existing.Password
+	}
+	if err := session.UpdateGlobalEmailWithOptions(GetConfigOptions(), cfg); err != nil {
+		return fmt.Errorf("failed to save email settings: %w", err)
+	}
+
+	fmt.Println(describeEmail(cfg))
+	if cfg.Username != "" && cfg.GetPassword() == "" {
+		fmt.Printf("\nSet the SMTP password in %s before runs send email.\n", session.EnvSMTPPassword)
+	}
+	return nil
+}
+
+func runConfigEmailClear(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalEmailWithOptions(GetConfigOptions(), nil); err != nil {
+		return fmt.Errorf("failed to clear email settings: %w", err)
+	}
+
+	fmt.Println("Stopped summary emails.")
+	return nil
+}
+
+// describeEmail says where summary emails go and what they cover, e.g.
+// "Emailing ball_blocked, run_end to me@example.com via smtp.example.com:587"
+func describeEmail(cfg *session.EmailConfig) string {
+	events := cfg.Events
+	if len(events) == 0 {
+		events = notify.EmailEvents
+	}
+	return fmt.Sprintf("Emailing %s to %s via %s:%d",
+		strings.Join(events, ", "), strings.Join(cfg.To, ", "), cfg.Host, cfg.GetPort())
+}
+
 // VCS command variables
 var configVCSProjectFlag bool
 
@@ -1087,6 +1858,11 @@ You can override this globally or per-project.
 Available providers:
   claude    - Claude Code CLI (default)
   opencode  - OpenCode CLI
+  goose     - goose CLI
+  amp       - Sourcegraph Amp CLI
+
+Custom providers defined under custom_providers in ~/.juggle/config.json
+can be selected by name like any built-in provider.
 
 Resolution order (highest to lowest priority):
   1. CLI flag (--provider on agent commands)
@@ -1096,7 +1872,7 @@ Resolution order (highest to lowest priority):
 
 Commands:
   config provider show              Show current provider settings
-  config provider set <provider>    Set provider (claude or opencode)
+  config provider set <provider>    Set provider (claude, opencode, goose, amp, api or custom)
   config provider clear             Clear provider setting
 
 Examples:
@@ -1117,10 +1893,13 @@ var configProviderShowCmd = &cobra.Command{
 
 var configProviderSetCmd = &cobra.Command{
 	Use:   "set <provider>",
-	Short: "Set agent provider (claude or opencode)",
+	Short: "Set agent provider (claude, opencode, goose, amp, api or custom)",
 	Long: `Set the agent provider.
 
-Valid providers: claude, opencode
+Valid providers: claude, opencode, goose, amp, api, or a name defined under
+custom_providers in the global config. The api provider calls the Anthropic
+API directly using $ANTHROPIC_API_KEY; it has no tools, so it only works for
+agent refine and agent run --dry-run reviews.
 
 Use --project to set for the current project only (stored in .juggle/config.json).
 Without --project, sets the global default (stored in ~/.juggle/config.json).`,
@@ -1172,6 +1951,12 @@ func runConfigProviderShow(cmd *cobra.Command, args []string) error {
 		fmt.Println(valueStyle.Render(globalProvider))
 	}
 
+	// Custom providers defined in global config
+	if custom := registerCustomProviders(); len(custom) > 0 {
+		fmt.Printf("  %s: ", keyStyle.Render("custom"))
+		fmt.Println(valueStyle.Render(strings.Join(custom, ", ")))
+	}
+
 	// Try to load project config
 	cwd, err := GetWorkingDir()
 	if err == nil {
@@ -1184,6 +1969,19 @@ func runConfigProviderShow(cmd *cobra.Command, args []string) error {
 				fmt.Println(valueStyle.Render(projectProvider))
 			}
 
+			// Pinned binaries replace the PATH lookup
+			if binaries, err := session.GetProjectProviderBinaries(cwd); err == nil {
+				names := make([]string, 0, len(binaries))
+				for name := range binaries {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					fmt.Printf("  %s: ", keyStyle.Render("binary ("+name+")"))
+					fmt.Println(valueStyle.Render(binaries[name]))
+				}
+			}
+
 			// Show effective provider
 			effective := resolveProvider(projectProvider, globalProvider)
 			fmt.Println()
@@ -1197,13 +1995,16 @@ func runConfigProviderShow(cmd *cobra.Command, args []string) error {
 
 func runConfigProviderSet(cmd *cobra.Command, args []string) error {
 	provider := strings.ToLower(strings.TrimSpace(args[0]))
-	if provider != "claude" && provider != "opencode" {
-		return fmt.Errorf("invalid provider: %s (must be 'claude' or 'opencode')", args[0])
+	customProviders := registerCustomProviders()
+	if provider == "" || !session.ValidateAgentProvider(provider, customProviders...) {
+		return fmt.Errorf("invalid provider: %s (must be 'claude', 'opencode', 'goose', 'amp' or a custom provider)", args[0])
 	}
 
 	// Check if CLI is available in PATH
-	if _, err := exec.LookPath(provider); err != nil {
-		fmt.Printf("Warning: %s not found in PATH. Install it before running agents.\n", provider)
+	if binary := agentprovider.BinaryName(agentprovider.Type(provider)); binary != "" {
+		if _, err := exec.LookPath(binary); err != nil {
+			fmt.Printf("Warning: %s not found in PATH. Install it before running agents.\n", binary)
+		}
 	}
 
 	if configProviderProjectFlag {
@@ -1211,7 +2012,7 @@ func runConfigProviderSet(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to get current directory: %w", err)
 		}
-		if err := session.UpdateProjectAgentProvider(cwd, provider); err != nil {
+		if err := session.UpdateProjectAgentProvider(cwd, provider, customProviders...); err != nil {
 			return fmt.Errorf("failed to set project provider: %w", err)
 		}
 		fmt.Printf("Set project provider to: %s\n", provider)
@@ -1245,6 +2046,62 @@ func runConfigProviderClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// configCustomProviders are the custom providers last registered from global config
+var configCustomProviders []string
+
+// registerCustomProviders registers the custom providers from global config with the
+// provider package so they can be detected and run by name. Invalid definitions are
+// reported and skipped. Providers registered by an embedding program are kept.
+// Returns the names of all registered providers.
+func registerCustomProviders() []string {
+	custom, err := session.GetGlobalCustomProvidersWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load custom providers: %v\n", err)
+		return nil
+	}
+
+	// Drop providers removed from config since the last call
+	for _, name := range configCustomProviders {
+		agentprovider.UnregisterCustom(name)
+	}
+	configCustomProviders = nil
+	for name, cfg := range custom {
+		err := agentprovider.RegisterCustom(name, agentprovider.CustomConfig{
+			Binary:          cfg.Binary,
+			Args:            cfg.Args,
+			PromptVia:       cfg.PromptVia,
+			ModelFlag:       cfg.ModelFlag,
+			Models:          cfg.Models,
+			PermissionArgs:  cfg.PermissionArgs,
+			InteractiveArgs: cfg.InteractiveArgs,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping custom provider: %v\n", err)
+			continue
+		}
+		configCustomProviders = append(configCustomProviders, name)
+	}
+	return agentprovider.CustomProviders()
+}
+
+// registerProviderBinaries points providers at the binaries pinned in the project
+// config instead of PATH. Relative paths are reported and skipped.
+func registerProviderBinaries(projectDir string) {
+	agentprovider.ClearBinaryPaths()
+	binaries, err := session.GetProjectProviderBinaries(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load provider binaries: %v\n", err)
+		return
+	}
+	for name, path := range binaries {
+		if !filepath.IsAbs(path) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping provider binary for %s: %q is not an absolute path\n", name, path)
+			continue
+		}
+		agentprovider.SetBinaryPath(agentprovider.Type(name), path)
+	}
+}
+
 // resolveProvider determines the effective provider using resolution priority
 func resolveProvider(projectProvider, globalProvider string) string {
 	if projectProvider != "" {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ohare93/juggle/internal/agent"
 	"github.com/ohare93/juggle/internal/session"
 )
 
@@ -799,6 +800,42 @@ func TestConfigProviderSet_Invalid(t *testing.T) {
 	}
 }
 
+// TestConfigProviderSet_Custom tests selecting a custom provider from global config
+func TestConfigProviderSet_Custom(t *testing.T) {
+	tmpDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	opts := session.ConfigOptions{
+		ConfigHome:    tmpDir,
+		JuggleDirName: ".juggle",
+	}
+	config, err := session.LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load global config: %v", err)
+	}
+	config.CustomProviders = map[string]session.CustomProviderConfig{
+		"aider": {Binary: "aider", PromptVia: "arg"},
+	}
+	if err := config.SaveWithOptions(opts); err != nil {
+		t.Fatalf("failed to save global config: %v", err)
+	}
+
+	configProviderProjectFlag = true
+	err = runConfigProviderSet(configProviderSetCmd, []string{"aider"})
+	configProviderProjectFlag = false
+	if err != nil {
+		t.Fatalf("failed to set custom project provider: %v", err)
+	}
+
+	provider, err := session.GetProjectAgentProvider(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to get project provider: %v", err)
+	}
+	if provider != "aider" {
+		t.Errorf("expected provider 'aider', got '%s'", provider)
+	}
+}
+
 // TestConfigProviderClear_Global tests clearing global setting
 func TestConfigProviderClear_Global(t *testing.T) {
 	tmpDir, cleanup := setupTestProject(t)
@@ -859,3 +896,115 @@ func TestConfigProviderClear_Project(t *testing.T) {
 		t.Errorf("expected empty provider after clear, got '%s'", provider)
 	}
 }
+
+// TestAgentSystemPrompt_Toolchain tests the toolchain briefing in the agent system prompt
+func TestAgentSystemPrompt_Toolchain(t *testing.T) {
+	projectDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	prompt, err := agentSystemPrompt(projectDir, "", systemPromptVars{})
+	if err != nil {
+		t.Fatalf("agentSystemPrompt failed: %v", err)
+	}
+	if !strings.HasPrefix(prompt, agent.AutonomousSystemPrompt) {
+		t.Errorf("expected system prompt to start with the autonomous directive, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "test: go test ./...") {
+		t.Errorf("expected detected Go test command in system prompt, got:\n%s", prompt)
+	}
+
+	config, err := session.LoadProjectConfig(projectDir)
+	if err != nil {
+		t.Fatalf("failed to load project config: %v", err)
+	}
+	config.Toolchain = &session.ToolchainConfig{Test: "make test"}
+	if err := session.SaveProjectConfig(projectDir, config); err != nil {
+		t.Fatalf("failed to save project config: %v", err)
+	}
+	prompt, _ = agentSystemPrompt(projectDir, "", systemPromptVars{})
+	if !strings.Contains(prompt, "test: make test") || strings.Contains(prompt, "go test") {
+		t.Errorf("expected configured test command to replace detected one, got:\n%s", prompt)
+	}
+
+	config.Toolchain = &session.ToolchainConfig{Disabled: true}
+	if err := session.SaveProjectConfig(projectDir, config); err != nil {
+		t.Fatalf("failed to save project config: %v", err)
+	}
+	if prompt, _ = agentSystemPrompt(projectDir, "", systemPromptVars{}); prompt != agent.AutonomousSystemPrompt {
+		t.Errorf("expected only the autonomous directive when disabled, got:\n%s", prompt)
+	}
+}
+
+// TestAgentSystemPrompt_Template tests project and session system prompt templates
+func TestAgentSystemPrompt_Template(t *testing.T) {
+	projectDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	vars := systemPromptVars{SessionID: "docs", BallCount: 3, Provider: "claude"}
+	promptsDir := filepath.Join(projectDir, ".juggle", "prompts")
+	if err := os.MkdirAll(promptsDir, 0755); err != nil {
+		t.Fatalf("failed to create prompts dir: %v", err)
+	}
+	template := "{{.Default}}\nSession {{.SessionID}} has {{.BallCount}} balls for {{.Provider}}."
+	if err := os.WriteFile(filepath.Join(promptsDir, "system.md"), []byte(template), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	prompt, err := agentSystemPrompt(projectDir, "", vars)
+	if err != nil {
+		t.Fatalf("agentSystemPrompt failed: %v", err)
+	}
+	want := agent.AutonomousSystemPrompt + "\nSession docs has 3 balls for claude."
+	if prompt != want {
+		t.Errorf("expected rendered project template, got:\n%s", prompt)
+	}
+
+	prompt, err = agentSystemPrompt(projectDir, "Only edit docs for {{.SessionID}}.", vars)
+	if err != nil {
+		t.Fatalf("agentSystemPrompt failed: %v", err)
+	}
+	if prompt != "Only edit docs for docs." {
+		t.Errorf("expected session template to win, got:\n%s", prompt)
+	}
+
+	if _, err := agentSystemPrompt(projectDir, "{{.Unknown}}", vars); err == nil {
+		t.Error("expected an error for an unknown variable")
+	}
+	if err := validateSystemPrompt("{{.SessionID"); err == nil {
+		t.Error("expected an error for an unparseable template")
+	}
+}
+
+func TestLoadRetryPolicies(t *testing.T) {
+	projectDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	config, err := session.LoadProjectConfig(projectDir)
+	if err != nil {
+		t.Fatalf("failed to load project config: %v", err)
+	}
+	config.Retry = &session.RetryConfig{
+		RateLimit: &session.RetryPolicyConfig{BaseSeconds: 5, Jitter: 0.1, MaxAttempts: 4},
+		Crash:     &session.RetryPolicyConfig{MaxAttempts: -1},
+	}
+	if err := session.SaveProjectConfig(projectDir, config); err != nil {
+		t.Fatalf("failed to save project config: %v", err)
+	}
+
+	policies := loadRetryPolicies(AgentLoopConfig{ProjectDir: projectDir, OverloadRetryMinutes: 2})
+
+	wantRateLimit := agent.RetryPolicy{Base: 5 * time.Second, Cap: agent.DefaultRateLimitPolicy.Cap, Jitter: 0.1, MaxAttempts: 4}
+	if policies.rateLimit != wantRateLimit {
+		t.Errorf("rate limit policy = %+v, want %+v", policies.rateLimit, wantRateLimit)
+	}
+	if policies.crash.MaxAttempts != 0 || policies.crash.Base != agent.DefaultCrashPolicy.Base {
+		t.Errorf("crash policy = %+v, want defaults with unlimited attempts", policies.crash)
+	}
+	if policies.overload.Base != 2*time.Minute || policies.overload.Cap != 2*time.Minute {
+		t.Errorf("overload policy = %+v, want fixed 2m wait from loop config", policies.overload)
+	}
+}
@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/agent/supervisor"
+	"github.com/ohare93/juggle/internal/process"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// daemonRunFlags are the agent run flags a loop handed to juggle daemon keeps;
+// runs using any other flag get a process of their own
+var daemonRunFlags = map[string]bool{
+	"daemon":     true,
+	"iterations": true,
+	"ball":       true,
+	"trust":      true,
+	"model":      true,
+	"provider":   true,
+	"monitor":    true, // --monitor starts a loop when none is running
+}
+
+var (
+	daemonForeground  bool
+	daemonStopTimeout time.Duration
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run agent loops for many sessions in one background process",
+	Long: `juggle daemon runs agent loops for many sessions and projects side by side in
+a single background process, instead of one forked process per session.
+
+It is the supervisor (see juggle supervisor) with the loops in its own process:
+it polls sessions, fires scheduled runs, restarts stalled loops and auto-launches
+sessions with pending work, all under the "supervisor" config. While it runs,
+juggle agent run --daemon hands its loop to it.
+
+Loops start once fewer than max_concurrent are running in all, and fewer than
+max_per_session (default 1) in their session; the rest wait in line. Each loop
+writes the same state files as a forked daemon, so the monitor TUI and
+juggle agent stop work as usual, and logs its progress to the session's agent.log.
+
+Examples:
+  juggle daemon start               # Start in the background
+  juggle daemon start --foreground  # Run in this terminal
+  juggle daemon status              # Show the running and waiting loops
+  juggle daemon stop                # Stop the daemon and its loops
+  juggle daemon install --systemd   # Start it on boot (--launchd on macOS)
+  juggle daemon docker ops/juggle   # Write a Dockerfile and compose file running it`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the daemon",
+	Long: `Start juggle daemon in the background, logging to ~/.juggle/daemon.log.
+Use --foreground to run it in this terminal instead.
+
+Configuration is read from ~/.juggle/config.json under the "supervisor" key.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonStart,
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the daemon",
+	Long: `Stop juggle daemon. Running loops are cancelled and wrap up as a cancelled
+agent run does; waiting loops are dropped. Use juggle agent stop first to end a
+loop at a safe point instead.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonStop,
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the daemon's running and waiting loops",
+	Args:  cobra.NoArgs,
+	RunE:  runDaemonStatus,
+}
+
+func init() {
+	daemonStartCmd.Flags().BoolVar(&daemonForeground, "foreground", false, "Run in this terminal instead of the background")
+	daemonStopCmd.Flags().DurationVar(&daemonStopTimeout, "timeout", 2*time.Minute, "How long to wait for the daemon to end")
+
+	daemonCmd.AddCommand(daemonStartCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+	daemonCmd.AddCommand(daemonStatusCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemonStart(cmd *cobra.Command, args []string) error {
+	if running, pid := supervisor.IsSupervisorRunning(); running {
+		return fmt.Errorf("a supervisor or daemon is already running (PID %d)", pid)
+	}
+
+	child := os.Getenv("JUGGLE_DAEMON_CHILD") == "1"
+	if !child && !daemonForeground {
+		return startDaemonProcess()
+	}
+	os.Unsetenv("JUGGLE_DAEMON_CHILD")
+
+	config, err := loadSupervisorConfig()
+	if err != nil {
+		return err
+	}
+	sv := supervisor.New(config, GetConfigOptions())
+	sv.SetLoopRunner(runDaemonLoop)
+
+	fmt.Printf("Starting juggle daemon (poll every %d min, max concurrent %d, max per session %d)\n",
+		config.GetPollInterval(), config.GetMaxConcurrent(), config.GetMaxPerSession())
+	if err := sv.Start(); err != nil {
+		return err
+	}
+
+	ctx, stop := process.NotifyContext(commandContext(cmd))
+	defer stop()
+	if !child {
+		fmt.Println("Daemon running. Press Ctrl+C to stop.")
+	}
+	<-ctx.Done()
+
+	fmt.Println("Stopping daemon, waiting for its loops to wrap up...")
+	sv.Stop()
+	fmt.Println("Daemon stopped.")
+	return nil
+}
+
+// startDaemonProcess re-executes juggle daemon start in the background,
+// logging to ~/.juggle/daemon.log
+func startDaemonProcess() error {
+	opts := GetConfigOptions()
+	logPath := filepath.Join(opts.ConfigHome, opts.JuggleDirName, "daemon.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+
+	daemonProc := exec.Command(os.Args[0], os.Args[1:]...)
+	daemonProc.Env = append(os.Environ(), "JUGGLE_DAEMON_CHILD=1")
+	daemonProc.Stdout = logFile
+	daemonProc.Stderr = logFile
+	process.Detach(daemonProc)
+	if err := daemonProc.Start(); err != nil {
+		return fmt.Errorf("failed to start daemon: %w", err)
+	}
+
+	fmt.Printf("Daemon started (PID %d)\n", daemonProc.Process.Pid)
+	fmt.Printf("Log file: %s\n", logPath)
+	fmt.Println("Check on it with: juggle daemon status")
+	return nil
+}
+
+func runDaemonStop(cmd *cobra.Command, args []string) error {
+	status, err := supervisor.ReadDaemonStatus(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		return fmt.Errorf("juggle daemon is not running")
+	}
+	if err := process.Terminate(status.PID); err != nil {
+		return fmt.Errorf("failed to stop daemon (PID %d): %w", status.PID, err)
+	}
+	fmt.Printf("🛑 Stopping daemon (PID %d)...\n", status.PID)
+
+	ctx, cancel := context.WithTimeout(commandContext(cmd), daemonStopTimeout)
+	defer cancel()
+	for process.Running(status.PID) {
+		if !sleepContext(ctx, stopPollInterval) {
+			return fmt.Errorf("the daemon is still wrapping up its loops (PID %d)", status.PID)
+		}
+	}
+	fmt.Println("✓ Daemon stopped")
+	return nil
+}
+
+func runDaemonStatus(cmd *cobra.Command, args []string) error {
+	status, err := supervisor.ReadDaemonStatus(GetConfigOptions())
+	if err != nil {
+		return err
+	}
+	if GlobalOpts.JSONOutput {
+		data, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	if status == nil {
+		fmt.Println("juggle daemon is not running")
+		return nil
+	}
+
+	fmt.Printf("juggle daemon: running (PID %d, up %s)\n\n", status.PID, time.Since(status.StartedAt).Round(time.Second))
+	if len(status.Loops) == 0 {
+		fmt.Println("No loops running or waiting.")
+		return nil
+	}
+	fmt.Printf("%-30s %-8s %-10s %s\n", "SESSION", "STATE", "ITERATION", "PROJECT / STATUS")
+	for _, l := range status.Loops {
+		fmt.Printf("%-30s %-8s %-10s %s\n", loopLabel(l.LoopRequest), l.State, loopIteration(l), l.ProjectDir)
+		if l.State == supervisor.LoopRunning {
+			if state, err := daemon.ReadStateFile(l.ProjectDir, l.StorageID()); err == nil && state.Status != "" {
+				fmt.Printf("%-30s %s\n", "", StyleDim.Render(state.Status))
+			}
+		}
+	}
+	return nil
+}
+
+// loopLabel names a loop by session, and ball when it targets one
+func loopLabel(req supervisor.LoopRequest) string {
+	if req.BallID != "" {
+		return req.SessionID + " (" + req.BallID + ")"
+	}
+	return req.SessionID
+}
+
+// loopIteration shows how far a running loop is, e.g. "3/10"
+func loopIteration(l supervisor.LoopStatus) string {
+	if l.State != supervisor.LoopRunning {
+		return "-"
+	}
+	state, err := daemon.ReadStateFile(l.ProjectDir, l.StorageID())
+	if err != nil || state.MaxIterations == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", state.Iteration, state.MaxIterations)
+}
+
+// runDaemonLoop runs one agent loop in the daemon, logging its events to the
+// session's agent.log for the monitor
+func runDaemonLoop(ctx context.Context, req supervisor.LoopRequest) error {
+	logPath := filepath.Join(req.ProjectDir, ".juggle", "sessions", req.StorageID(), "agent.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+	fmt.Fprintf(logFile, "Agent loop for %s started by juggle daemon (PID %d)\n", loopLabel(req), os.Getpid())
+
+	iterations := req.Iterations
+	if iterations <= 0 {
+		iterations = 10
+	}
+	result, err := RunAgentLoop(ctx, AgentLoopConfig{
+		SessionID:     req.SessionID,
+		ProjectDir:    req.ProjectDir,
+		BallID:        req.BallID,
+		MaxIterations: iterations,
+		Trust:         req.Trust,
+		Model:         req.Model,
+		Provider:      req.Provider,
+		DaemonMode:    true,
+		Events:        &daemonLogWriter{w: logFile},
+	})
+	if err != nil {
+		fmt.Fprintf(logFile, "%s ✗ Agent loop failed: %v\n", time.Now().Format("15:04:05"), err)
+		return err
+	}
+	_, summary := queueOutcome(result, nil)
+	fmt.Fprintf(logFile, "%s ■ Agent loop ended: %s\n", time.Now().Format("15:04:05"), summary)
+	return nil
+}
+
+// daemonLogWriter turns a loop's event stream into readable agent.log lines.
+// Loops in the daemon share its stdout, so their own output goes to daemon.log.
+type daemonLogWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write takes whole event lines, as agentEvents writes them
+func (d *daemonLogWriter) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSpace(string(p)), "\n") {
+		var event AgentEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		if text := describeAgentEvent(event); text != "" {
+			fmt.Fprintf(d.w, "%s %s\n", event.Time.Format("15:04:05"), text)
+		}
+	}
+	return len(p), nil
+}
+
+// describeAgentEvent renders an event as a log line ("" to leave it out)
+func describeAgentEvent(event AgentEvent) string {
+	switch event.Type {
+	case AgentEventIterationStart:
+		return fmt.Sprintf("▶ Iteration %d/%d", event.Iteration, event.MaxIterations)
+	case AgentEventModelSelected:
+		if event.Reason != "" {
+			return fmt.Sprintf("🤖 Model: %s (%s)", event.Model, event.Reason)
+		}
+		return "🤖 Model: " + event.Model
+	case AgentEventRateLimited:
+		return fmt.Sprintf("⏳ Rate limited, retry %d in %s", event.Attempt, (time.Duration(event.WaitSeconds) * time.Second).String())
+	case AgentEventBallComplete:
+		return fmt.Sprintf("✓ Ball %s complete: %s", event.BallID, event.Title)
+	case AgentEventRunSummary:
+		return fmt.Sprintf("Run %s after %d iteration(s)", strings.ReplaceAll(event.Status, "_", " "), event.Iteration)
+	default:
+		return ""
+	}
+}
+
+// handLoopToDaemon hands juggle agent run --daemon's loop to a running juggle
+// daemon. Returns false when there is no daemon, or the run uses flags a
+// daemon loop doesn't keep, and it needs a process of its own.
+func handLoopToDaemon(cmd *cobra.Command, projectDir, sessionID string, iterations int) (bool, error) {
+	status, err := supervisor.ReadDaemonStatus(GetConfigOptions())
+	if err != nil || status == nil {
+		return false, nil
+	}
+	if !flagsFitDaemon(cmd) {
+		return false, nil
+	}
+
+	err = supervisor.RequestLoop(GetConfigOptions(), supervisor.LoopRequest{
+		ProjectDir: projectDir,
+		SessionID:  sessionID,
+		BallID:     agentBallID,
+		Iterations: iterations,
+		Trust:      agentTrust,
+		Model:      agentModel,
+		Provider:   agentProvider,
+	})
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("Agent loop handed to juggle daemon (PID %d)\n", status.PID)
+	fmt.Printf("Log file: %s\n", filepath.Join(projectDir, ".juggle", "sessions", sessionStorageID(sessionID), "agent.log"))
+	fmt.Printf("Monitor with: juggle agent run --monitor %s\n", sessionID)
+	return true, nil
+}
+
+// flagsFitDaemon reports whether the command only sets flags a daemon loop keeps
+func flagsFitDaemon(cmd *cobra.Command) bool {
+	fits := true
+	local := cmd.LocalFlags()
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if local.Lookup(f.Name) != nil && !daemonRunFlags[f.Name] {
+			fits = false
+		}
+	})
+	return fits
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// The files juggle daemon docker writes
+const (
+	dockerfileName  = "Dockerfile"
+	composeFileName = "compose.yaml"
+)
+
+// containerHome is the home directory of the daemon in the container, where
+// the host's juggle config directory is mounted
+const containerHome = "/home/juggle"
+
+var daemonDockerForce bool
+
+var daemonDockerCmd = &cobra.Command{
+	Use:   "docker [dir]",
+	Short: "Write a Dockerfile and compose file that run the daemon",
+	Long: `Write a Dockerfile and compose.yaml to dir (default: the current directory)
+that run juggle daemon in a container, so a team can host it on a shared box.
+
+The image installs this version of juggle with go install. The compose file
+mounts the juggle config directory (~/.juggle) and every search path at the
+same paths as on this machine, so the container sees the projects, session
+state and daemon API token the host does. It runs as your user, so files it
+writes in projects keep their owner.
+
+The daemon API only listens on loopback, so the container uses host
+networking: with daemon_api_listen set, the API is on the host's loopback and
+juggle agent run --monitor --remote reaches it as for a daemon on the host.
+Host networking needs Docker on Linux.
+
+Agent CLIs (claude, opencode, ...) and their credentials aren't in the image;
+add them to the Dockerfile before building.
+
+Examples:
+  juggle daemon docker ops/juggle   # Write the files to ops/juggle
+  docker compose -f ops/juggle/compose.yaml up -d --build`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDaemonDocker,
+}
+
+func init() {
+	daemonDockerCmd.Flags().BoolVarP(&daemonDockerForce, "force", "f", false, "Overwrite existing files")
+
+	daemonCmd.AddCommand(daemonDockerCmd)
+}
+
+// dockerRecipe is what the generated container files need from this machine
+type dockerRecipe struct {
+	Version     string   // juggle version the image installs
+	ConfigDir   string   // Host juggle config directory, e.g. ~/.juggle
+	JuggleDir   string   // Name of the juggle directory, e.g. .juggle
+	SearchPaths []string // Host project directories, mounted at the same paths
+	User        string   // uid:gid to run as; empty for the image default
+}
+
+func runDaemonDocker(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	opts := GetConfigOptions()
+	recipe := dockerRecipe{
+		Version:   cmd.Root().Version,
+		ConfigDir: filepath.Join(opts.ConfigHome, opts.JuggleDirName),
+		JuggleDir: opts.JuggleDirName,
+	}
+	for _, searchPath := range config.SearchPaths {
+		if abs, err := filepath.Abs(searchPath); err == nil {
+			recipe.SearchPaths = append(recipe.SearchPaths, abs)
+		}
+	}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		recipe.User = fmt.Sprintf("%d:%d", uid, gid)
+	}
+
+	files := []struct {
+		name, content string
+	}{
+		{dockerfileName, renderDockerfile(recipe)},
+		{composeFileName, renderCompose(recipe)},
+	}
+	if !daemonDockerForce {
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(dir, file.name)); err == nil {
+				return fmt.Errorf("%s already exists; use --force to overwrite it", filepath.Join(dir, file.name))
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.name), []byte(file.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+		fmt.Printf("✓ Wrote %s\n", filepath.Join(dir, file.name))
+	}
+
+	if config.DaemonAPIListen == "" {
+		fmt.Println("\nSet daemon_api_listen (e.g. \"127.0.0.1:7717\") in the config so monitors can find the API.")
+	}
+	fmt.Printf("Add your agent CLI to the Dockerfile, then start it with: docker compose -f %s up -d --build\n", filepath.Join(dir, composeFileName))
+	return nil
+}
+
+// dockerModuleVersion returns the go install version for a juggle version:
+// its release tag, or latest for a development build
+func dockerModuleVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if version == "" || strings.ContainsAny(version, " +") || !strings.ContainsAny(version[:1], "0123456789") {
+		return "latest"
+	}
+	return "v" + version
+}
+
+// renderDockerfile writes a two-stage image: juggle built with go install,
+// then copied onto a slim base with git, which agent runs need
+func renderDockerfile(recipe dockerRecipe) string {
+	var b strings.Builder
+	b.WriteString("# Written by juggle daemon docker\n")
+	b.WriteString("FROM golang:1.25 AS build\n")
+	fmt.Fprintf(&b, "ARG JUGGLE_VERSION=%s\n", dockerModuleVersion(recipe.Version))
+	b.WriteString("RUN CGO_ENABLED=0 go install github.com/ohare93/juggle/cmd/juggle@${JUGGLE_VERSION}\n\n")
+	b.WriteString("FROM debian:bookworm-slim\n")
+	b.WriteString("RUN apt-get update \\\n")
+	b.WriteString("    && apt-get install -y --no-install-recommends ca-certificates git \\\n")
+	b.WriteString("    && rm -rf /var/lib/apt/lists/*\n")
+	b.WriteString("COPY --from=build /go/bin/juggle /usr/local/bin/juggle\n\n")
+	b.WriteString("# Install the agent CLIs the daemon runs here, e.g. for Claude:\n")
+	b.WriteString("# RUN apt-get update && apt-get install -y --no-install-recommends nodejs npm \\\n")
+	b.WriteString("#     && npm install -g @anthropic-ai/claude-code\n\n")
+	b.WriteString("# Writable by whichever user compose runs the daemon as\n")
+	fmt.Fprintf(&b, "ENV HOME=%s\n", containerHome)
+	fmt.Fprintf(&b, "RUN mkdir -p %s && chmod 777 %s\n", containerHome, containerHome)
+	fmt.Fprintf(&b, "ENTRYPOINT [%s]\n", strings.Join(quoteAll(dockerDaemonArgs(recipe)), ", "))
+	return b.String()
+}
+
+// renderCompose writes the compose service running the image built from
+// the Dockerfile next to it
+func renderCompose(recipe dockerRecipe) string {
+	var b strings.Builder
+	b.WriteString("# Written by juggle daemon docker\n")
+	b.WriteString("services:\n")
+	b.WriteString("  juggle-daemon:\n")
+	b.WriteString("    build: .\n")
+	b.WriteString("    restart: unless-stopped\n")
+	// Leave running loops time to wrap up, as juggle daemon stop does
+	b.WriteString("    stop_grace_period: 150s\n")
+	b.WriteString("    # The daemon API only listens on loopback; share the host's\n")
+	b.WriteString("    network_mode: host\n")
+	if recipe.User != "" {
+		fmt.Fprintf(&b, "    user: %s\n", yamlQuote(recipe.User))
+	}
+	b.WriteString("    volumes:\n")
+	writeBind(&b, recipe.ConfigDir, path.Join(containerHome, recipe.JuggleDir))
+	for _, searchPath := range recipe.SearchPaths {
+		writeBind(&b, searchPath, filepath.ToSlash(searchPath))
+	}
+	return b.String()
+}
+
+// writeBind writes a bind mount in the long syntax, which allows colons in paths
+func writeBind(b *strings.Builder, source, target string) {
+	b.WriteString("      - type: bind\n")
+	fmt.Fprintf(b, "        source: %s\n", yamlQuote(source))
+	fmt.Fprintf(b, "        target: %s\n", yamlQuote(target))
+}
+
+// dockerDaemonArgs is the command the container runs. The config home is
+// the container's HOME, so only a non-default juggle directory is passed on.
+func dockerDaemonArgs(recipe dockerRecipe) []string {
+	args := []string{"juggle", "daemon", "start", "--foreground"}
+	if recipe.JuggleDir != "" && recipe.JuggleDir != ".juggle" {
+		args = append(args, "--juggle-dir", recipe.JuggleDir)
+	}
+	return args
+}
+
+// yamlQuote quotes s as a YAML (and JSON) double-quoted string
+func yamlQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// quoteAll quotes each of args for a Dockerfile exec form
+func quoteAll(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = yamlQuote(arg)
+	}
+	return quoted
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDockerfile(t *testing.T) {
+	dockerfile := renderDockerfile(dockerRecipe{Version: "0.2.0", JuggleDir: ".juggle-dev"})
+
+	for _, want := range []string{
+		"ARG JUGGLE_VERSION=v0.2.0\n",
+		"go install github.com/ohare93/juggle/cmd/juggle@${JUGGLE_VERSION}\n",
+		"ENV HOME=" + containerHome + "\n",
+		`ENTRYPOINT ["juggle", "daemon", "start", "--foreground", "--juggle-dir", ".juggle-dev"]` + "\n",
+	} {
+		if !strings.Contains(dockerfile, want) {
+			t.Errorf("Expected %q in the Dockerfile, got:\n%s", want, dockerfile)
+		}
+	}
+}
+
+func TestRenderCompose(t *testing.T) {
+	compose := renderCompose(dockerRecipe{
+		ConfigDir:   "/home/a/.juggle",
+		JuggleDir:   ".juggle",
+		SearchPaths: []string{"/home/a/my projects"},
+		User:        "1000:1000",
+	})
+
+	for _, want := range []string{
+		"    network_mode: host\n",
+		"    user: \"1000:1000\"\n",
+		"        source: \"/home/a/.juggle\"\n        target: \"" + containerHome + "/.juggle\"\n",
+		"        source: \"/home/a/my projects\"\n        target: \"/home/a/my projects\"\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("Expected %q in the compose file, got:\n%s", want, compose)
+		}
+	}
+}
+
+func TestDockerModuleVersion(t *testing.T) {
+	tests := map[string]string{
+		"0.2.0":  "v0.2.0",
+		"v1.4.1": "v1.4.1",
+		"dev":    "latest",
+		"":       "latest",
+	}
+	for version, want := range tests {
+		if got := dockerModuleVersion(version); got != want {
+			t.Errorf("dockerModuleVersion(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
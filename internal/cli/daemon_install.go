@@ -0,0 +1,418 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent/supervisor"
+	"github.com/ohare93/juggle/internal/cronexpr"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// The systemd user units juggle daemon install writes
+const (
+	systemdServiceName = "juggle-daemon.service"
+	systemdTimerName   = "juggle-daemon.timer"
+)
+
+// errNoSystemctl is returned when systemctl isn't installed
+var errNoSystemctl = errors.New("systemctl not found")
+
+// systemctl runs systemctl --user; a variable so tests can stand in for it
+var systemctl = func(args ...string) error {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return errNoSystemctl
+	}
+	out, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl --user %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+var (
+	daemonInstallSystemd bool
+	daemonInstallLaunchd bool
+)
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Start the daemon on boot",
+	Long: `Install juggle daemon as a service, so it starts on its own and is restarted
+if it fails.
+
+--systemd (Linux) writes a user service, juggle-daemon.service, to
+~/.config/systemd/user, enables it and starts it. Its output goes to the
+journal (journalctl --user -u juggle-daemon). User services start when you
+log in; to start the daemon at boot instead, enable lingering:
+loginctl enable-linger $USER
+
+--launchd (macOS) writes a LaunchAgent, com.github.ohare93.juggle.daemon.plist,
+to ~/Library/LaunchAgents and loads it. It starts when you log in, logs to
+~/.juggle/daemon.log and is started again if it crashes.
+
+When any project has scheduled runs (juggle agent schedule), a timer
+(juggle-daemon.timer, or the com.github.ohare93.juggle.schedule agent) is
+installed too. It starts the daemon at each run's time, so scheduled runs fire
+even when the daemon was stopped. juggle agent schedule keeps it up to date.
+
+Examples:
+  juggle daemon install --systemd
+  juggle daemon install --launchd
+  juggle daemon uninstall`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonInstall,
+}
+
+var daemonUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the daemon service installed with juggle daemon install",
+	Long: `Stop and remove the systemd units or LaunchAgents written by
+juggle daemon install. A daemon running under them is stopped as
+juggle daemon stop does.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonUninstall,
+}
+
+func init() {
+	daemonInstallCmd.Flags().BoolVar(&daemonInstallSystemd, "systemd", false, "Install a systemd user service (and timer for scheduled runs)")
+	daemonInstallCmd.Flags().BoolVar(&daemonInstallLaunchd, "launchd", false, "Install a macOS LaunchAgent (and one for scheduled runs)")
+
+	daemonCmd.AddCommand(daemonInstallCmd)
+	daemonCmd.AddCommand(daemonUninstallCmd)
+}
+
+func runDaemonInstall(cmd *cobra.Command, args []string) error {
+	if daemonInstallSystemd && daemonInstallLaunchd {
+		return fmt.Errorf("--systemd and --launchd can't be used together")
+	}
+	if !daemonInstallSystemd && !daemonInstallLaunchd {
+		switch runtime.GOOS {
+		case "windows":
+			return fmt.Errorf("juggle daemon install isn't available on Windows; start the daemon with: juggle daemon start")
+		case "darwin":
+			return fmt.Errorf("choose how to install the daemon: --launchd")
+		}
+		return fmt.Errorf("choose how to install the daemon: --systemd (Linux) or --launchd (macOS)")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the juggle binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	command := append([]string{exe}, daemonStartArgs()...)
+	if daemonInstallLaunchd {
+		return installLaunchd(command)
+	}
+	return installSystemd(command)
+}
+
+// installSystemd writes, enables and starts the systemd user service
+// running command, and the timer for scheduled runs
+func installSystemd(command []string) error {
+	unitDir, err := systemdUserDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", unitDir, err)
+	}
+
+	servicePath := filepath.Join(unitDir, systemdServiceName)
+	service := renderSystemdService(command, os.Getenv("PATH"))
+	if err := os.WriteFile(servicePath, []byte(service), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", servicePath, err)
+	}
+	fmt.Printf("✓ Wrote %s\n", servicePath)
+	events, err := writeSystemdTimer(unitDir)
+	if err != nil {
+		return err
+	}
+
+	if err := systemctl("daemon-reload"); err != nil {
+		if errors.Is(err, errNoSystemctl) {
+			fmt.Println(StyleDim.Render("systemctl not found; enable the service with: systemctl --user enable --now " + systemdServiceName))
+			return nil
+		}
+		return fmt.Errorf("the units are written but systemd could not be reached: %w", err)
+	}
+	units := []string{systemdServiceName}
+	if events > 0 {
+		units = append(units, systemdTimerName)
+	}
+	if running, pid := supervisor.IsSupervisorRunning(); running {
+		// Starting the service now would fail while another daemon runs
+		if err := systemctl(append([]string{"enable"}, units...)...); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Enabled %s\n", strings.Join(units, " and "))
+		fmt.Println(StyleDim.Render(fmt.Sprintf("  A daemon is already running (PID %d); the service starts from the next login or boot", pid)))
+	} else {
+		if err := systemctl(append([]string{"enable", "--now"}, units...)...); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Enabled and started %s\n", strings.Join(units, " and "))
+	}
+	if events > 0 {
+		fmt.Printf("  The timer starts the daemon for %d scheduled run time(s)\n", events)
+	}
+	if !lingerEnabled() {
+		fmt.Println(StyleDim.Render("  The daemon starts when you log in; to start it at boot run: loginctl enable-linger $USER"))
+	}
+	return nil
+}
+
+func runDaemonUninstall(cmd *cobra.Command, args []string) error {
+	removedSystemd, err := uninstallSystemd()
+	if err != nil {
+		return err
+	}
+	removedLaunchd, err := uninstallLaunchd()
+	if err != nil {
+		return err
+	}
+	if !removedSystemd && !removedLaunchd {
+		return fmt.Errorf("juggle daemon is not installed (install it with: juggle daemon install --systemd or --launchd)")
+	}
+	return nil
+}
+
+// uninstallSystemd stops and removes the systemd units, returning whether
+// there were any
+func uninstallSystemd() (bool, error) {
+	unitDir, err := systemdUserDir()
+	if err != nil {
+		return false, err
+	}
+	var units []string
+	for _, name := range []string{systemdTimerName, systemdServiceName} {
+		if _, err := os.Stat(filepath.Join(unitDir, name)); err == nil {
+			units = append(units, name)
+		}
+	}
+	if len(units) == 0 {
+		return false, nil
+	}
+
+	if err := systemctl(append([]string{"disable", "--now"}, units...)...); err != nil && !errors.Is(err, errNoSystemctl) {
+		return false, err
+	}
+	for _, name := range units {
+		path := filepath.Join(unitDir, name)
+		if err := os.Remove(path); err != nil {
+			return false, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		fmt.Printf("✓ Removed %s\n", path)
+	}
+	if err := systemctl("daemon-reload"); err != nil && !errors.Is(err, errNoSystemctl) {
+		return false, err
+	}
+	return true, nil
+}
+
+// refreshDaemonSchedule brings the timer of a daemon installed with juggle
+// daemon install in line with the scheduled runs after a schedule changes.
+// Without an installed daemon it does nothing.
+func refreshDaemonSchedule() error {
+	if err := refreshSystemdTimer(); err != nil {
+		return err
+	}
+	return refreshLaunchdSchedule()
+}
+
+// refreshSystemdTimer rewrites an installed service's timer
+func refreshSystemdTimer() error {
+	unitDir, err := systemdUserDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(unitDir, systemdServiceName)); err != nil {
+		return nil
+	}
+	events, err := writeSystemdTimer(unitDir)
+	if err != nil {
+		return err
+	}
+	if err := systemctl("daemon-reload"); err != nil {
+		if errors.Is(err, errNoSystemctl) {
+			return nil
+		}
+		return err
+	}
+	if events == 0 {
+		return nil
+	}
+	// Restarting picks up the new times of a timer that was already running
+	if err := systemctl("enable", systemdTimerName); err != nil {
+		return err
+	}
+	return systemctl("restart", systemdTimerName)
+}
+
+// writeSystemdTimer writes the timer for the scheduled runs of all projects,
+// or stops and removes it when there are none. Returns the number of
+// calendar events in the timer.
+func writeSystemdTimer(unitDir string) (int, error) {
+	timerPath := filepath.Join(unitDir, systemdTimerName)
+	exprs, err := scheduleExprs()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool)
+	var events []string
+	for _, expr := range exprs {
+		for _, event := range expr.OnCalendar() {
+			if !seen[event] {
+				seen[event] = true
+				events = append(events, event)
+			}
+		}
+	}
+	sort.Strings(events)
+	if len(events) == 0 {
+		if _, err := os.Stat(timerPath); err != nil {
+			return 0, nil
+		}
+		if err := systemctl("disable", "--now", systemdTimerName); err != nil && !errors.Is(err, errNoSystemctl) {
+			return 0, err
+		}
+		if err := os.Remove(timerPath); err != nil {
+			return 0, fmt.Errorf("failed to remove %s: %w", timerPath, err)
+		}
+		fmt.Printf("✓ Removed %s (no scheduled runs)\n", timerPath)
+		return 0, nil
+	}
+	if err := os.WriteFile(timerPath, []byte(renderSystemdTimer(events)), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", timerPath, err)
+	}
+	fmt.Printf("✓ Wrote %s\n", timerPath)
+	return len(events), nil
+}
+
+// scheduleExprs returns the cron expressions of the scheduled runs in all
+// projects, in no particular order
+func scheduleExprs() ([]*cronexpr.Expr, error) {
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	projects, err := session.DiscoverProjects(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover projects: %w", err)
+	}
+
+	var exprs []*cronexpr.Expr
+	for _, projectDir := range projects {
+		sessionStore, err := session.NewSessionStore(projectDir)
+		if err != nil {
+			continue
+		}
+		schedules, err := sessionStore.ListSchedules()
+		if err != nil {
+			continue
+		}
+		for _, sched := range schedules {
+			if expr, err := cronexpr.Parse(sched.Cron); err == nil {
+				exprs = append(exprs, expr)
+			}
+		}
+	}
+	return exprs, nil
+}
+
+// daemonStartArgs are the arguments the service runs juggle with, keeping
+// the global flags that point it at another config
+func daemonStartArgs() []string {
+	args := []string{"daemon", "start", "--foreground"}
+	if GlobalOpts.ConfigHome != "" {
+		args = append(args, "--config-home", GlobalOpts.ConfigHome)
+	}
+	if GlobalOpts.JuggleDir != "" && GlobalOpts.JuggleDir != ".juggle" {
+		args = append(args, "--juggle-dir", GlobalOpts.JuggleDir)
+	}
+	return args
+}
+
+// renderSystemdService writes the service unit running command. path is the
+// PATH the daemon gets, so it finds the agent CLIs the user's shell does.
+func renderSystemdService(command []string, path string) string {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = strings.ReplaceAll(systemdQuote(arg), "$", "$$")
+	}
+
+	var b strings.Builder
+	b.WriteString("# Written by juggle daemon install; remove with juggle daemon uninstall\n")
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=juggle daemon: agent loops for juggle sessions\n")
+	b.WriteString("After=network-online.target\n\n")
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	if path != "" {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote("PATH="+path))
+	}
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=30\n")
+	// Leave running loops time to wrap up, as juggle daemon stop does
+	b.WriteString("TimeoutStopSec=150\n\n")
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.String()
+}
+
+// renderSystemdTimer writes the timer unit starting the service at events.
+// Persistent catches up on a run missed while the machine was off.
+func renderSystemdTimer(events []string) string {
+	var b strings.Builder
+	b.WriteString("# Written by juggle daemon install; kept up to date by juggle agent schedule\n")
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=Start the juggle daemon for scheduled agent runs\n\n")
+	b.WriteString("[Timer]\n")
+	for _, event := range events {
+		fmt.Fprintf(&b, "OnCalendar=%s\n", event)
+	}
+	b.WriteString("Persistent=true\n")
+	fmt.Fprintf(&b, "Unit=%s\n\n", systemdServiceName)
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=timers.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes a unit file value, escaping specifiers
+func systemdQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%").Replace(s) + `"`
+}
+
+// systemdUserDir returns where systemd looks for the user's own units
+func systemdUserDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// lingerEnabled reports whether systemd keeps the user's services running
+// without a login, so they start at boot
+func lingerEnabled() bool {
+	u, err := user.Current()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join("/var/lib/systemd/linger", u.Username))
+	return err == nil
+}
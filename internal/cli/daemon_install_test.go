@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestRenderSystemdService(t *testing.T) {
+	unit := renderSystemdService([]string{"/opt/my tools/juggle", "daemon", "start", "--foreground", "--config-home", "/home/a/100%$x"}, "/usr/bin:/home/a/.local/bin")
+
+	for _, want := range []string{
+		`ExecStart="/opt/my tools/juggle" "daemon" "start" "--foreground" "--config-home" "/home/a/100%%$$x"`,
+		`Environment="PATH=/usr/bin:/home/a/.local/bin"`,
+		"Restart=on-failure",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want+"\n") {
+			t.Errorf("Expected %q in the service, got:\n%s", want, unit)
+		}
+	}
+}
+
+func TestRenderSystemdTimer(t *testing.T) {
+	unit := renderSystemdTimer([]string{"*-*-* 02:00:00", "Mon..Fri *-*-* 09:30:00"})
+
+	for _, want := range []string{
+		"OnCalendar=*-*-* 02:00:00\nOnCalendar=Mon..Fri *-*-* 09:30:00\n",
+		"Persistent=true\n",
+		"Unit=" + systemdServiceName + "\n",
+		"WantedBy=timers.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("Expected %q in the timer, got:\n%s", want, unit)
+		}
+	}
+}
+
+// setupScheduledProject points the config at a project in a temp dir with
+// a nightly schedule, and returns the temp dir and the project's session
+// store. The home directory moves into the temp dir too, so no real service
+// is touched.
+func setupScheduledProject(t *testing.T) (string, *session.SessionStore) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", filepath.Join(tmpDir, "home"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+	projectDir := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(projectDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	GlobalOpts.ConfigHome = tmpDir
+	t.Cleanup(func() { GlobalOpts.ConfigHome = "" })
+	cfg := &session.Config{SearchPaths: []string{projectDir}}
+	if err := cfg.SaveWithOptions(session.ConfigOptions{ConfigHome: tmpDir, JuggleDirName: ".juggle"}); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	sched, err := session.NewSchedule("nightly", "0 2 * * *", 5, time.Now())
+	if err != nil {
+		t.Fatalf("NewSchedule failed: %v", err)
+	}
+	if err := sessionStore.SaveSchedule("nightly", sched); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+	return tmpDir, sessionStore
+}
+
+func TestDaemonInstallSystemd(t *testing.T) {
+	tmpDir, sessionStore := setupScheduledProject(t)
+
+	var calls []string
+	defer func(orig func(...string) error) { systemctl = orig }(systemctl)
+	systemctl = func(args ...string) error {
+		calls = append(calls, strings.Join(args, " "))
+		return nil
+	}
+
+	daemonInstallSystemd = true
+	defer func() { daemonInstallSystemd = false }()
+	if err := runDaemonInstall(nil, nil); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	unitDir := filepath.Join(tmpDir, "xdg", "systemd", "user")
+	service, err := os.ReadFile(filepath.Join(unitDir, systemdServiceName))
+	if err != nil {
+		t.Fatalf("Expected the service to be written: %v", err)
+	}
+	if !strings.Contains(string(service), `"daemon" "start" "--foreground" "--config-home" "`+tmpDir+`"`) {
+		t.Errorf("Expected the service to start the daemon with this config, got:\n%s", service)
+	}
+	timer, err := os.ReadFile(filepath.Join(unitDir, systemdTimerName))
+	if err != nil {
+		t.Fatalf("Expected a timer for the schedule: %v", err)
+	}
+	if !strings.Contains(string(timer), "OnCalendar=*-*-* 02:00:00\n") {
+		t.Errorf("Expected the schedule's time in the timer, got:\n%s", timer)
+	}
+	if calls[0] != "daemon-reload" || !strings.HasSuffix(calls[1], systemdServiceName+" "+systemdTimerName) {
+		t.Errorf("Expected a reload, then both units enabled, got %q", calls)
+	}
+
+	// Removing the last schedule removes the timer
+	if err := sessionStore.RemoveSchedule("nightly"); err != nil {
+		t.Fatalf("RemoveSchedule failed: %v", err)
+	}
+	calls = nil
+	if err := refreshSystemdTimer(); err != nil {
+		t.Fatalf("refreshSystemdTimer failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(unitDir, systemdTimerName)); !os.IsNotExist(err) {
+		t.Errorf("Expected the timer to be removed, got %v", err)
+	}
+	if len(calls) == 0 || calls[0] != "disable --now "+systemdTimerName {
+		t.Errorf("Expected the timer to be stopped, got %q", calls)
+	}
+
+	if err := runDaemonUninstall(nil, nil); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(unitDir, systemdServiceName)); !os.IsNotExist(err) {
+		t.Errorf("Expected the service to be removed, got %v", err)
+	}
+	if err := runDaemonUninstall(nil, nil); err == nil {
+		t.Error("Expected an error uninstalling when nothing is installed")
+	}
+}
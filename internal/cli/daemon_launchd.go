@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent/supervisor"
+)
+
+// The LaunchAgents juggle daemon install --launchd writes
+const (
+	launchdDaemonLabel   = "com.github.ohare93.juggle.daemon"
+	launchdScheduleLabel = "com.github.ohare93.juggle.schedule"
+)
+
+// errNoLaunchctl is returned when launchctl isn't installed
+var errNoLaunchctl = errors.New("launchctl not found")
+
+// launchctl runs launchctl; a variable so tests can stand in for it
+var launchctl = func(args ...string) error {
+	if _, err := exec.LookPath("launchctl"); err != nil {
+		return errNoLaunchctl
+	}
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("launchctl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// installLaunchd writes and loads the LaunchAgent running command, and the
+// agent starting it for scheduled runs
+func installLaunchd(command []string) error {
+	agentDir, err := launchAgentsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(agentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", agentDir, err)
+	}
+	opts := GetConfigOptions()
+	logPath := filepath.Join(opts.ConfigHome, opts.JuggleDirName, "daemon.log")
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	plistPath := filepath.Join(agentDir, launchdDaemonLabel+".plist")
+	plist := renderLaunchdDaemon(command, os.Getenv("PATH"), logPath)
+	if err := os.WriteFile(plistPath, []byte(plist), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", plistPath, err)
+	}
+	fmt.Printf("✓ Wrote %s\n", plistPath)
+	scheduled, err := writeLaunchdSchedule(agentDir)
+	if err != nil {
+		return err
+	}
+
+	if running, pid := supervisor.IsSupervisorRunning(); running {
+		// Loading the agent now would start a second daemon, which fails
+		// and is started again until the first one stops
+		fmt.Println(StyleDim.Render(fmt.Sprintf("A daemon is already running (PID %d); the LaunchAgent loads from the next login", pid)))
+	} else {
+		if err := launchdLoad(launchdDaemonLabel, plistPath); err != nil {
+			if errors.Is(err, errNoLaunchctl) {
+				fmt.Println(StyleDim.Render("launchctl not found; load the agent with: launchctl bootstrap " + launchdDomain() + " " + plistPath))
+				return nil
+			}
+			return err
+		}
+		fmt.Printf("✓ Loaded and started %s\n", launchdDaemonLabel)
+	}
+	if scheduled > 0 {
+		if err := launchdLoad(launchdScheduleLabel, filepath.Join(agentDir, launchdScheduleLabel+".plist")); err != nil && !errors.Is(err, errNoLaunchctl) {
+			return err
+		}
+		fmt.Printf("  %s starts the daemon for %d scheduled run time(s)\n", launchdScheduleLabel, scheduled)
+	}
+	fmt.Println(StyleDim.Render("  Its output goes to " + logPath))
+	return nil
+}
+
+// uninstallLaunchd unloads and removes the LaunchAgents, returning whether
+// there were any
+func uninstallLaunchd() (bool, error) {
+	agentDir, err := launchAgentsDir()
+	if err != nil {
+		return false, err
+	}
+	removed := false
+	for _, label := range []string{launchdScheduleLabel, launchdDaemonLabel} {
+		path := filepath.Join(agentDir, label+".plist")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		launchdUnload(label)
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		fmt.Printf("✓ Removed %s\n", path)
+		removed = true
+	}
+	return removed, nil
+}
+
+// refreshLaunchdSchedule rewrites and reloads an installed daemon's schedule
+// agent
+func refreshLaunchdSchedule() error {
+	agentDir, err := launchAgentsDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(agentDir, launchdDaemonLabel+".plist")); err != nil {
+		return nil
+	}
+	scheduled, err := writeLaunchdSchedule(agentDir)
+	if err != nil || scheduled == 0 {
+		return err
+	}
+	if err := launchdLoad(launchdScheduleLabel, filepath.Join(agentDir, launchdScheduleLabel+".plist")); err != nil && !errors.Is(err, errNoLaunchctl) {
+		return err
+	}
+	return nil
+}
+
+// writeLaunchdSchedule writes the agent starting the daemon for the
+// scheduled runs of all projects, or unloads and removes it when there are
+// none. Returns the number of calendar intervals in the agent.
+func writeLaunchdSchedule(agentDir string) (int, error) {
+	plistPath := filepath.Join(agentDir, launchdScheduleLabel+".plist")
+	exprs, err := scheduleExprs()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool)
+	var intervals []map[string]int
+	for _, expr := range exprs {
+		for _, interval := range expr.StartCalendarIntervals() {
+			if key := fmt.Sprint(interval); !seen[key] {
+				seen[key] = true
+				intervals = append(intervals, interval)
+			}
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return fmt.Sprint(intervals[i]) < fmt.Sprint(intervals[j]) })
+
+	if len(intervals) == 0 {
+		if _, err := os.Stat(plistPath); err != nil {
+			return 0, nil
+		}
+		launchdUnload(launchdScheduleLabel)
+		if err := os.Remove(plistPath); err != nil {
+			return 0, fmt.Errorf("failed to remove %s: %w", plistPath, err)
+		}
+		fmt.Printf("✓ Removed %s (no scheduled runs)\n", plistPath)
+		return 0, nil
+	}
+	if err := os.WriteFile(plistPath, []byte(renderLaunchdSchedule(intervals)), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", plistPath, err)
+	}
+	fmt.Printf("✓ Wrote %s\n", plistPath)
+	return len(intervals), nil
+}
+
+// launchdLoad loads an agent, unloading the definition loaded before, if any
+func launchdLoad(label, plistPath string) error {
+	launchdUnload(label)
+	return launchctl("bootstrap", launchdDomain(), plistPath)
+}
+
+// launchdUnload unloads an agent, stopping it. An agent that isn't loaded is
+// left be.
+func launchdUnload(label string) {
+	_ = launchctl("bootout", launchdDomain()+"/"+label)
+}
+
+// launchdDomain is the launchd domain of the user's login session
+func launchdDomain() string {
+	return "gui/" + strconv.Itoa(os.Getuid())
+}
+
+// launchAgentsDir returns where launchd looks for the user's agents
+func launchAgentsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents"), nil
+}
+
+// renderLaunchdDaemon writes the agent running command, logging to logPath.
+// It starts at login and again whenever it exits unsuccessfully; a daemon
+// stopped with juggle daemon stop stays stopped.
+func renderLaunchdDaemon(command []string, path, logPath string) string {
+	var b strings.Builder
+	writePlistHeader(&b, launchdDaemonLabel)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range command {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", plistEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if path != "" {
+		fmt.Fprintf(&b, "\t<key>EnvironmentVariables</key>\n\t<dict>\n\t\t<key>PATH</key>\n\t\t<string>%s</string>\n\t</dict>\n", plistEscape(path))
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	b.WriteString("\t<key>ThrottleInterval</key>\n\t<integer>30</integer>\n")
+	// Leave running loops time to wrap up, as juggle daemon stop does
+	b.WriteString("\t<key>ExitTimeOut</key>\n\t<integer>150</integer>\n")
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", plistEscape(logPath))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", plistEscape(logPath))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// renderLaunchdSchedule writes the agent starting the daemon agent at
+// intervals. kickstart leaves a daemon that is already running be.
+func renderLaunchdSchedule(intervals []map[string]int) string {
+	var b strings.Builder
+	writePlistHeader(&b, launchdScheduleLabel)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range []string{"/bin/launchctl", "kickstart", launchdDomain() + "/" + launchdDaemonLabel} {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", plistEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>StartCalendarInterval</key>\n\t<array>\n")
+	for _, interval := range intervals {
+		b.WriteString("\t\t<dict>\n")
+		for _, key := range []string{"Month", "Day", "Weekday", "Hour", "Minute"} {
+			if v, ok := interval[key]; ok {
+				fmt.Fprintf(&b, "\t\t\t<key>%s</key>\n\t\t\t<integer>%d</integer>\n", key, v)
+			}
+		}
+		b.WriteString("\t\t</dict>\n")
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// writePlistHeader starts a property list with the agent's label
+func writePlistHeader(b *strings.Builder, label string) {
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<!-- Written by juggle daemon install; remove with juggle daemon uninstall -->` + "\n")
+	b.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	fmt.Fprintf(b, "\t<key>Label</key>\n\t<string>%s</string>\n", label)
+}
+
+// plistEscape escapes a string for a property list
+func plistEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderLaunchdDaemon(t *testing.T) {
+	plist := renderLaunchdDaemon([]string{"/Users/a/bin/juggle", "daemon", "start", "--foreground"}, "/usr/bin:/opt/homebrew/bin", "/Users/a/.juggle/daemon.log")
+
+	for _, want := range []string{
+		"<string>" + launchdDaemonLabel + "</string>",
+		"<string>/Users/a/bin/juggle</string>\n\t\t<string>daemon</string>\n\t\t<string>start</string>\n\t\t<string>--foreground</string>",
+		"<key>PATH</key>\n\t\t<string>/usr/bin:/opt/homebrew/bin</string>",
+		"<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>",
+		"<key>StandardOutPath</key>\n\t<string>/Users/a/.juggle/daemon.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("Expected %q in the agent, got:\n%s", want, plist)
+		}
+	}
+	if escaped := renderLaunchdDaemon([]string{"/a&b/<juggle>"}, "", "/log"); !strings.Contains(escaped, "<string>/a&amp;b/&lt;juggle&gt;</string>") {
+		t.Errorf("Expected arguments to be escaped, got:\n%s", escaped)
+	}
+}
+
+func TestRenderLaunchdSchedule(t *testing.T) {
+	plist := renderLaunchdSchedule([]map[string]int{{"Hour": 2, "Minute": 0}, {"Weekday": 1, "Hour": 9, "Minute": 30}})
+
+	for _, want := range []string{
+		"<string>kickstart</string>\n\t\t<string>" + launchdDomain() + "/" + launchdDaemonLabel + "</string>",
+		"<dict>\n\t\t\t<key>Hour</key>\n\t\t\t<integer>2</integer>\n\t\t\t<key>Minute</key>\n\t\t\t<integer>0</integer>\n\t\t</dict>",
+		"<key>Weekday</key>\n\t\t\t<integer>1</integer>\n\t\t\t<key>Hour</key>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("Expected %q in the agent, got:\n%s", want, plist)
+		}
+	}
+}
+
+func TestDaemonInstallLaunchd(t *testing.T) {
+	tmpDir, sessionStore := setupScheduledProject(t)
+
+	var calls []string
+	defer func(orig func(...string) error) { launchctl = orig }(launchctl)
+	launchctl = func(args ...string) error {
+		calls = append(calls, strings.Join(args, " "))
+		return nil
+	}
+
+	daemonInstallLaunchd = true
+	defer func() { daemonInstallLaunchd = false }()
+	if err := runDaemonInstall(nil, nil); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	agentDir := filepath.Join(tmpDir, "home", "Library", "LaunchAgents")
+	daemonPlist, err := os.ReadFile(filepath.Join(agentDir, launchdDaemonLabel+".plist"))
+	if err != nil {
+		t.Fatalf("Expected the daemon agent to be written: %v", err)
+	}
+	if !strings.Contains(string(daemonPlist), "<string>"+filepath.Join(tmpDir, ".juggle", "daemon.log")+"</string>") {
+		t.Errorf("Expected the agent to log to this config's daemon.log, got:\n%s", daemonPlist)
+	}
+	schedulePlist, err := os.ReadFile(filepath.Join(agentDir, launchdScheduleLabel+".plist"))
+	if err != nil {
+		t.Fatalf("Expected a schedule agent for the schedule: %v", err)
+	}
+	if !strings.Contains(string(schedulePlist), "<key>Hour</key>\n\t\t\t<integer>2</integer>") {
+		t.Errorf("Expected the schedule's time in the agent, got:\n%s", schedulePlist)
+	}
+	bootstrap := "bootstrap " + launchdDomain() + " " + filepath.Join(agentDir, launchdScheduleLabel+".plist")
+	if calls[len(calls)-1] != bootstrap {
+		t.Errorf("Expected the schedule agent to be loaded last, got %q", calls)
+	}
+
+	// Removing the last schedule removes the schedule agent
+	if err := sessionStore.RemoveSchedule("nightly"); err != nil {
+		t.Fatalf("RemoveSchedule failed: %v", err)
+	}
+	calls = nil
+	if err := refreshDaemonSchedule(); err != nil {
+		t.Fatalf("refreshDaemonSchedule failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(agentDir, launchdScheduleLabel+".plist")); !os.IsNotExist(err) {
+		t.Errorf("Expected the schedule agent to be removed, got %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "bootout "+launchdDomain()+"/"+launchdScheduleLabel {
+		t.Errorf("Expected the schedule agent to be unloaded, got %q", calls)
+	}
+
+	if err := runDaemonUninstall(nil, nil); err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(agentDir, launchdDaemonLabel+".plist")); !os.IsNotExist(err) {
+		t.Errorf("Expected the daemon agent to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDaemonLogWriter(t *testing.T) {
+	var out bytes.Buffer
+	events := newAgentEvents(&daemonLogWriter{w: &out}, "my-session")
+	events.emit(AgentEvent{Type: AgentEventIterationStart, Iteration: 2, MaxIterations: 5})
+	events.emit(AgentEvent{Type: AgentEventModelSelected, Model: "sonnet", Reason: "session default"})
+	events.emit(AgentEvent{Type: AgentEventRateLimited, Attempt: 1, WaitSeconds: 90})
+	events.emit(AgentEvent{Type: AgentEventBallComplete, BallID: "proj-1", Title: "Add login"})
+	events.summary(&AgentResult{Iterations: 5})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"▶ Iteration 2/5",
+		"🤖 Model: sonnet (session default)",
+		"⏳ Rate limited, retry 1 in 1m30s",
+		"✓ Ball proj-1 complete: Add login",
+		"Run max iterations after 5 iteration(s)",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected one line per event, got %q", out.String())
+	}
+	for i, line := range lines {
+		// Lines start with the event's time, e.g. "15:04:05 "
+		if len(line) < 9 || line[9:] != want[i] {
+			t.Errorf("Line %d: expected %q after the time, got %q", i, want[i], line)
+		}
+	}
+}
+
+func TestFlagsFitDaemon(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		root := &cobra.Command{Use: "juggle"}
+		root.PersistentFlags().Bool("json", false, "")
+		cmd := &cobra.Command{Use: "run"}
+		cmd.Flags().Bool("daemon", false, "")
+		cmd.Flags().IntP("iterations", "n", 10, "")
+		cmd.Flags().String("model", "", "")
+		cmd.Flags().Duration("timeout", 0, "")
+		root.AddCommand(cmd)
+		return cmd
+	}
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--daemon"}, true},
+		{[]string{"--daemon", "-n", "5", "--model", "haiku", "--json"}, true},
+		{[]string{"--daemon", "--timeout", "10m"}, false},
+	}
+	for _, tt := range tests {
+		cmd := newCmd()
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("ParseFlags(%v): %v", tt.args, err)
+		}
+		if got := flagsFitDaemon(cmd); got != tt.want {
+			t.Errorf("flagsFitDaemon(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
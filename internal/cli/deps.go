@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Manage ball dependencies",
+	Long: `Manage which balls must be done before another can start.
+
+A ball waiting on a dependency that isn't complete (or researched) is left out
+of the agent prompt and doesn't count as workable, so the agent loop works
+through dependency chains in order. Cycles are rejected.
+
+Examples:
+  juggle deps add a1b2 c3d4 e5f6   # a1b2 waits for c3d4 and e5f6
+  juggle deps remove a1b2 c3d4
+  juggle deps show a1b2            # Dependencies and the balls waiting on it`,
+}
+
+var depsAddCmd = &cobra.Command{
+	Use:               "add <ball-id> <dependency-id>...",
+	Short:             "Make a ball wait for other balls",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runDepsAdd,
+}
+
+var depsRemoveCmd = &cobra.Command{
+	Use:               "remove <ball-id> <dependency-id>...",
+	Aliases:           []string{"rm"},
+	Short:             "Stop a ball waiting for other balls",
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runDepsRemove,
+}
+
+var depsShowCmd = &cobra.Command{
+	Use:               "show <ball-id>",
+	Short:             "Show a ball's dependencies and dependents",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runDepsShow,
+}
+
+func init() {
+	depsCmd.AddCommand(depsAddCmd)
+	depsCmd.AddCommand(depsRemoveCmd)
+	depsCmd.AddCommand(depsShowCmd)
+	rootCmd.AddCommand(depsCmd)
+}
+
+func runDepsAdd(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	deps, err := resolveDependencyIDsForUpdate(store, args[1:], ball.ID)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		ball.AddDependency(dep)
+	}
+	if err := checkDependencyCycles(store, ball); err != nil {
+		return err
+	}
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ %s now depends on: %s\n", ball.ShortID(), strings.Join(ball.DependsOn, ", "))
+	return nil
+}
+
+func runDepsRemove(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, id := range args[1:] {
+		// Dependencies may point at balls that were since archived or deleted,
+		// so match the stored IDs before resolving against active balls
+		if ball.RemoveDependency(id) {
+			removed++
+			continue
+		}
+		deps, err := resolveDependencyIDsForUpdate(store, []string{id}, ball.ID)
+		if err != nil {
+			return err
+		}
+		if ball.RemoveDependency(deps[0]) {
+			removed++
+		}
+	}
+	if removed == 0 {
+		return fmt.Errorf("%s does not depend on %s", ball.ShortID(), strings.Join(args[1:], ", "))
+	}
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ Removed %d dependency(ies) from %s\n", removed, ball.ShortID())
+	return nil
+}
+
+func runDepsShow(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	archived, _ := store.LoadArchivedBalls() // Archived dependencies are done
+
+	fmt.Printf("%s %s\n", StyleHighlight.Render(ball.ID), ball.Title)
+
+	fmt.Println("\nDepends on:")
+	if len(ball.DependsOn) == 0 {
+		fmt.Println(StyleDim.Render("  (none)"))
+	}
+	for _, depID := range ball.DependsOn {
+		dep := findDependency(depID, balls, archived)
+		if dep == nil {
+			fmt.Printf("  ? %s %s\n", depID, StyleDim.Render("(not found)"))
+			continue
+		}
+		printDependencyLine(dep)
+	}
+
+	fmt.Println("\nRequired by:")
+	dependents := 0
+	for _, other := range balls {
+		for _, depID := range other.DependsOn {
+			if depID == ball.ID || depID == ball.ShortID() {
+				printDependencyLine(other)
+				dependents++
+				break
+			}
+		}
+	}
+	if dependents == 0 {
+		fmt.Println(StyleDim.Render("  (none)"))
+	}
+
+	if unmet := session.UnmetDependencies(ball, session.BallStates(balls)); len(unmet) > 0 {
+		fmt.Printf("\n⏳ Waiting on %d ball(s); the agent won't pick this ball up until they're done\n", len(unmet))
+	}
+	return nil
+}
+
+// checkDependencyCycles fails if the ball's dependencies, together with the
+// store's other balls, form a cycle
+func checkDependencyCycles(store *session.Store, ball *session.Ball) error {
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls for dependency check: %w", err)
+	}
+	// Replace the ball in the list with the modified version
+	for i, b := range balls {
+		if b.ID == ball.ID {
+			balls[i] = ball
+			break
+		}
+	}
+	if err := session.DetectCircularDependencies(balls); err != nil {
+		return fmt.Errorf("dependency error: %w", err)
+	}
+	return nil
+}
+
+// findDependency looks a dependency ID up among active, then archived balls
+func findDependency(id string, active, archived []*session.Ball) *session.Ball {
+	for _, balls := range [][]*session.Ball{active, archived} {
+		for _, ball := range balls {
+			if ball.ID == id || ball.ShortID() == id {
+				return ball
+			}
+		}
+	}
+	return nil
+}
+
+// printDependencyLine prints one ball in a deps listing with its state
+func printDependencyLine(ball *session.Ball) {
+	mark := "○"
+	style := StylePending
+	switch ball.State {
+	case session.StateComplete, session.StateResearched:
+		mark, style = "✓", StyleComplete
+	case session.StateInProgress:
+		style = StyleInProgress
+	case session.StateBlocked:
+		mark, style = "✗", StyleBlocked
+	}
+	state := lipgloss.NewStyle().Width(12).Render(string(ball.State))
+	fmt.Printf("  %s %s %s %s\n", mark, ball.ID, style.Render(state), ball.Title)
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// ErrorCode classifies a command's error for --json output and exit codes,
+// so wrappers and tests can tell failures apart without matching messages
+type ErrorCode string
+
+const (
+	// CodeError is any error without a more specific code
+	CodeError ErrorCode = "error"
+	// CodeNotFound means a ball, session or other named thing doesn't exist
+	CodeNotFound ErrorCode = "not_found"
+	// CodeAmbiguous means an ID prefix matched more than one ball
+	CodeAmbiguous ErrorCode = "ambiguous"
+	// CodeLocked means another agent holds the session or ball lock
+	CodeLocked ErrorCode = "locked"
+	// CodeProviderUnavailable means the agent provider can't be run or rejected its credentials or model
+	CodeProviderUnavailable ErrorCode = "provider_unavailable"
+	// CodeRateLimited means the provider's rate limit stopped the command
+	CodeRateLimited ErrorCode = "rate_limited"
+	// CodeConfigInvalid means a config file couldn't be parsed
+	CodeConfigInvalid ErrorCode = "config_invalid"
+)
+
+// exitCodes maps each error code to the process exit code. 1 stays the
+// catch-all; 2 is left for usage errors.
+var exitCodes = map[ErrorCode]int{
+	CodeError:               1,
+	CodeNotFound:            3,
+	CodeAmbiguous:           4,
+	CodeLocked:              5,
+	CodeProviderUnavailable: 6,
+	CodeRateLimited:         7,
+	CodeConfigInvalid:       8,
+}
+
+// defaultHints suggest a next step for each code when the error has none of its own
+var defaultHints = map[ErrorCode]string{
+	CodeNotFound:            "run 'juggle balls' or 'juggle sessions list' to see what exists",
+	CodeAmbiguous:           "use more characters of the ID, or the full ID",
+	CodeLocked:              "wait for the other agent to finish",
+	CodeProviderUnavailable: "check the provider is installed and logged in, or pick another with --provider",
+	CodeRateLimited:         "wait and try again, or raise --max-wait",
+	CodeConfigInvalid:       "fix the JSON in the file named above",
+}
+
+// CommandError gives an error a code and a hint for fixing it. Errors from
+// the session and provider packages are classified by their sentinels, so
+// only errors without one need wrapping.
+type CommandError struct {
+	Code ErrorCode
+	Hint string
+	Err  error
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns err's code and a hint for fixing it
+func ClassifyError(err error) (ErrorCode, string) {
+	code := CodeError
+	var cmdErr *CommandError
+	var ambiguous *session.AmbiguousIDError
+	switch {
+	case errors.As(err, &cmdErr):
+		code = cmdErr.Code
+		if cmdErr.Hint != "" {
+			return code, cmdErr.Hint
+		}
+	case errors.Is(err, session.ErrBallNotFound), errors.Is(err, session.ErrSessionNotFound):
+		code = CodeNotFound
+	case errors.As(err, &ambiguous):
+		code = CodeAmbiguous
+	case errors.Is(err, session.ErrSessionLocked), errors.Is(err, session.ErrBallLocked):
+		code = CodeLocked
+	case errors.Is(err, provider.ErrPreflightAuth), errors.Is(err, provider.ErrPreflightModel):
+		code = CodeProviderUnavailable
+	case errors.Is(err, session.ErrConfigInvalid):
+		code = CodeConfigInvalid
+	}
+	return code, defaultHints[code]
+}
+
+// ExitCode returns the process exit code for a command's error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code, _ := ClassifyError(err)
+	return exitCodes[code]
+}
+
+// reportedError is an error already printed as JSON, so it only sets the exit code
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reportedError) Unwrap() error {
+	return e.err
+}
+
+// ReportError prints a command's error for humans, with a hint when there is
+// one. Errors already printed as JSON are not printed again.
+func ReportError(w io.Writer, err error) {
+	var reported *reportedError
+	if errors.As(err, &reported) {
+		return
+	}
+	fmt.Fprintf(w, "Error: %v\n", err)
+	if _, hint := ClassifyError(err); hint != "" {
+		fmt.Fprintf(w, "Hint: %s\n", hint)
+	}
+}
+
+// jsonError is the --json form of a command's error
+type jsonError struct {
+	Error string    `json:"error"`
+	Code  ErrorCode `json:"code"`
+	Hint  string    `json:"hint,omitempty"`
+}
+
+// printJSONError outputs an error in JSON format to stdout, with its code and
+// hint. Returns an error that only sets the exit code, so cobra and main don't
+// print it again to stderr.
+func printJSONError(err error) error {
+	code, hint := ClassifyError(err)
+	data, _ := json.Marshal(jsonError{Error: err.Error(), Code: code, Hint: hint})
+	fmt.Println(string(data))
+	return &reportedError{err: err}
+}
+
+// providerUnavailableError reports an agent provider whose CLI or key is missing
+func providerUnavailableError(p provider.Type) error {
+	return &CommandError{
+		Code: CodeProviderUnavailable,
+		Err:  fmt.Errorf("agent provider %q is not available (%s)", p, provider.UnavailableReason(p)),
+	}
+}
+
+// rateLimitedError reports a command stopped by the provider's rate limit
+func rateLimitedError(err error) error {
+	return &CommandError{Code: CodeRateLimited, Err: err}
+}
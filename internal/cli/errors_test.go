@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code ErrorCode
+		exit int
+	}{
+		{"plain", errors.New("boom"), CodeError, 1},
+		{"ball not found", fmt.Errorf("%w: a1b2", session.ErrBallNotFound), CodeNotFound, 3},
+		{"typed ball not found", fmt.Errorf("%w in session s", session.NewBallNotFoundError("a1b2")), CodeNotFound, 3},
+		{"session not found", session.NewSessionNotFoundError("feature"), CodeNotFound, 3},
+		{"ambiguous", fmt.Errorf("%w (use <project>:<id> to pick one)", session.NewAmbiguousIDError("a1", []string{"a1b2", "a1c3"})), CodeAmbiguous, 4},
+		{"session locked", session.NewSessionLockedError("feature", nil), CodeLocked, 5},
+		{"ball locked", session.NewBallLockedError("a1b2", nil), CodeLocked, 5},
+		{"provider missing", providerUnavailableError(provider.TypeGoose), CodeProviderUnavailable, 6},
+		{"preflight auth", fmt.Errorf("preflight failed: %w", provider.ErrPreflightAuth), CodeProviderUnavailable, 6},
+		{"rate limited", rateLimitedError(errors.New("rate limited: 429")), CodeRateLimited, 7},
+		{"config invalid", fmt.Errorf("failed to load config: %w", session.NewConfigInvalidError("/p/config.json", errors.New("unexpected EOF"))), CodeConfigInvalid, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, hint := ClassifyError(tt.err)
+			if code != tt.code {
+				t.Errorf("ClassifyError() code = %s, want %s", code, tt.code)
+			}
+			if code != CodeError && hint == "" {
+				t.Errorf("Expected a hint for %s", code)
+			}
+			if got := ExitCode(tt.err); got != tt.exit {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.exit)
+			}
+		})
+	}
+
+	if ExitCode(nil) != 0 {
+		t.Error("Expected exit code 0 without an error")
+	}
+}
+
+func TestCommandErrorHint(t *testing.T) {
+	err := &CommandError{Code: CodeNotFound, Hint: "run 'juggle worktree list'", Err: errors.New("alias not found")}
+	if code, hint := ClassifyError(fmt.Errorf("wrapped: %w", err)); code != CodeNotFound || hint != "run 'juggle worktree list'" {
+		t.Errorf("ClassifyError() = %s, %q; want the error's own code and hint", code, hint)
+	}
+}
+
+func TestReportError(t *testing.T) {
+	var buf bytes.Buffer
+	ReportError(&buf, session.NewSessionNotFoundError("feature"))
+	if out := buf.String(); !strings.HasPrefix(out, "Error: session feature not found\n") || !strings.Contains(out, "Hint: ") {
+		t.Errorf("Unexpected report:\n%s", out)
+	}
+
+	buf.Reset()
+	ReportError(&buf, &reportedError{err: errors.New("already printed")})
+	if buf.Len() != 0 {
+		t.Errorf("Expected errors printed as JSON not to be reported again, got %q", buf.String())
+	}
+}
+
+func TestJSONErrorShape(t *testing.T) {
+	code, hint := ClassifyError(fmt.Errorf("%w: a1b2", session.ErrBallNotFound))
+	data, err := json.Marshal(jsonError{Error: "ball not found: a1b2", Code: code, Hint: hint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]string
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["error"] != "ball not found: a1b2" || decoded["code"] != "not_found" || decoded["hint"] == "" {
+		t.Errorf("Unexpected JSON error: %s", data)
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples [topic]",
+	Short: "Show runnable workflow examples",
+	Long: `Show copy-pastable workflows for common juggle setups.
+
+Without a topic, lists the available examples. With a topic, prints the full
+example. The same examples are shown in the TUI help view (press ? then Tab).
+
+Examples:
+  juggle examples              # List example topics
+  juggle examples overnight    # Leave the agent running overnight
+  juggle examples spec-import  # Turn a spec.md into balls`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: CompleteExampleTopics,
+	RunE:              runExamples,
+}
+
+func runExamples(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		fmt.Print(renderExampleList(examples.All()))
+		return nil
+	}
+
+	example, ok := examples.Find(args[0])
+	if !ok {
+		return fmt.Errorf("unknown example %q (available: %s)", args[0], strings.Join(examples.Topics(), ", "))
+	}
+	fmt.Println(RenderMarkdown(example.Body))
+	return nil
+}
+
+// renderExampleList formats example topics with their summaries
+func renderExampleList(all []examples.Example) string {
+	width := 0
+	for _, example := range all {
+		if len(example.Topic) > width {
+			width = len(example.Topic)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("Workflow examples:\n\n")
+	for _, example := range all {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, example.Topic, example.Summary)
+	}
+	b.WriteString("\nRun 'juggle examples <topic>' to see one.\n")
+	return b.String()
+}
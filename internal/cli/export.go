@@ -158,7 +158,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if exportBallID != "" {
 		matches := session.ResolveBallByPrefix(balls, exportBallID)
 		if len(matches) == 0 {
-			return fmt.Errorf("ball not found: %s", exportBallID)
+			return fmt.Errorf("%w: %s", session.ErrBallNotFound, exportBallID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
@@ -278,7 +278,7 @@ func filterByBallIDs(balls []*session.Ball, ballIDsStr string, projects []string
 	for _, requestedID := range requestedIDs {
 		matches := session.ResolveBallByPrefix(balls, requestedID)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball ID not found: %s", requestedID)
+			return nil, &CommandError{Code: CodeNotFound, Err: fmt.Errorf("ball ID not found: %s", requestedID)}
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
@@ -467,6 +467,7 @@ func exportRalph(projectDir, sessionID string, balls []*session.Ball) ([]byte, e
 			buf.WriteString("\n")
 		}
 	}
+	writeDocsBriefing(&buf, juggleSession)
 	buf.WriteString("</context>\n\n")
 
 	// Write <progress> section
@@ -547,6 +548,11 @@ func writeBallForRalph(buf *strings.Builder, ball *session.Ball) {
 		buf.WriteString(fmt.Sprintf("Depends On: %s\n", strings.Join(ball.DependsOn, ", ")))
 	}
 
+	// Hot paths found by juggle agent hotspots
+	if ball.Hotspot != nil && len(ball.Hotspot.Paths) > 0 {
+		buf.WriteString(fmt.Sprintf("Hot Paths: %s\n", formatHotspot(ball.Hotspot)))
+	}
+
 	// Blocked reason if blocked
 	if ball.State == session.StateBlocked && ball.BlockedReason != "" {
 		buf.WriteString(fmt.Sprintf("Blocked: %s\n", ball.BlockedReason))
@@ -618,6 +624,7 @@ func exportAgent(projectDir, sessionID string, balls []*session.Ball, debug bool
 			buf.WriteString("\n")
 		}
 	}
+	writeDocsBriefing(&buf, juggleSession)
 	buf.WriteString("</context>\n\n")
 
 	// Write <session> section with the session ID
@@ -702,6 +709,13 @@ func exportAgent(projectDir, sessionID string, balls []*session.Ball, debug bool
 		}
 	}
 
+	// Add instructions for docs, research and ops balls, which aren't code changes
+	for _, kind := range ballKinds(balls) {
+		if instructions := agent.KindInstructions(string(kind)); instructions != "" {
+			buf.WriteString("\n" + instructions)
+		}
+	}
+
 	// Inject debug instructions if enabled
 	if debug {
 		buf.WriteString("\n## DEBUG MODE\n\n")
@@ -740,6 +754,12 @@ func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
 	if ball.ModelSize != "" {
 		header += fmt.Sprintf(" (model: %s)", ball.ModelSize)
 	}
+	if !ball.Kind.IsCode() {
+		header += fmt.Sprintf(" (kind: %s)", ball.Kind)
+	}
+	if ball.Epic {
+		header += " (epic)"
+	}
 	buf.WriteString(header + "\n")
 
 	// Title
@@ -753,11 +773,29 @@ func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
 		}
 	}
 
+	// Plan from a --plan-first planning iteration
+	if len(ball.Plan) > 0 {
+		buf.WriteString("Plan (work through these steps in order; adjust if a step proves wrong):\n")
+		for i, step := range ball.Plan {
+			buf.WriteString(fmt.Sprintf("  %d. %s\n", i+1, step))
+		}
+	}
+
 	// Dependencies
 	if len(ball.DependsOn) > 0 {
 		buf.WriteString(fmt.Sprintf("Depends On: %s\n", strings.Join(ball.DependsOn, ", ")))
 	}
 
+	// Epic this ball was split from
+	if ball.Parent != "" {
+		buf.WriteString(fmt.Sprintf("Parent: %s\n", ball.Parent))
+	}
+
+	// Risks flagged by juggle agent estimate
+	if ball.Estimate != nil && ball.Estimate.Risks != "" {
+		buf.WriteString(fmt.Sprintf("Risks: %s\n", ball.Estimate.Risks))
+	}
+
 	// Blocked reason if blocked
 	if ball.State == session.StateBlocked && ball.BlockedReason != "" {
 		buf.WriteString(fmt.Sprintf("Blocked: %s\n", ball.BlockedReason))
@@ -769,6 +807,20 @@ func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
 	}
 }
 
+// ballKinds returns the distinct non-code kinds among balls, in a fixed order
+func ballKinds(balls []*session.Ball) []session.BallKind {
+	var kinds []session.BallKind
+	for _, kind := range []session.BallKind{session.BallKindDocs, session.BallKindResearch, session.BallKindOps} {
+		for _, ball := range balls {
+			if ball.Kind == kind {
+				kinds = append(kinds, kind)
+				break
+			}
+		}
+	}
+	return kinds
+}
+
 // SortBallsForAgentExport sorts balls so in_progress balls come first,
 // followed by pending balls, then blocked balls.
 // Complete balls should be filtered out before calling this.
@@ -784,31 +836,12 @@ func SortBallsForAgentExport(balls []*session.Ball) {
 // Within each state, balls are sorted by:
 // 1. Dependencies satisfied (balls with all deps complete come first)
 // 2. Priority (urgent > high > medium > low)
+// 3. Hotspot score from juggle agent hotspots (hotter first)
 func sortBallsForAgent(balls []*session.Ball) {
-	// Build a map of ball states for dependency checking
-	ballStates := make(map[string]session.BallState)
-	for _, ball := range balls {
-		ballStates[ball.ID] = ball.State
-		// Also map by short ID for dependency resolution
-		ballStates[ball.ShortID()] = ball.State
-	}
-
-	// Helper to check if all dependencies are satisfied (complete or researched)
+	// Dependencies missing from the set (e.g. already complete) count as satisfied
+	ballStates := session.BallStates(balls)
 	allDepsSatisfied := func(ball *session.Ball) bool {
-		if len(ball.DependsOn) == 0 {
-			return true
-		}
-		for _, depID := range ball.DependsOn {
-			state, exists := ballStates[depID]
-			if !exists {
-				// Dependency not in current set - assume satisfied
-				continue
-			}
-			if state != session.StateComplete && state != session.StateResearched {
-				return false
-			}
-		}
-		return true
+		return len(session.UnmetDependencies(ball, ballStates)) == 0
 	}
 
 	// State priority: in_progress first, then pending, then blocked, then complete
@@ -847,6 +880,11 @@ func sortBallsForAgent(balls []*session.Ball) {
 		// Then sort by priority within each state
 		priorityI := priorityOrder[balls[i].Priority]
 		priorityJ := priorityOrder[balls[j].Priority]
-		return priorityI < priorityJ
+		if priorityI != priorityJ {
+			return priorityI < priorityJ
+		}
+
+		// Then by hotspot score, so hot paths are worked on first
+		return balls[i].HotspotScore() > balls[j].HotspotScore()
 	})
 }
@@ -312,3 +312,37 @@ func TestExportRalph_OnlySessionACs(t *testing.T) {
 		t.Error("expected output to contain 'Session-Level Requirements' header")
 	}
 }
+
+// TestExportAgent_BallKinds tests that non-code balls are labelled and get their kind's instructions
+func TestExportAgent_BallKinds(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+
+	code := &session.Ball{ID: "app-1", Title: "Add endpoint", State: session.StatePending, Priority: session.PriorityMedium}
+	docs := &session.Ball{ID: "app-2", Title: "Document endpoint", State: session.StatePending, Priority: session.PriorityMedium, Kind: session.BallKindDocs}
+
+	output, err := exportAgent(tmpDir, "kinds", []*session.Ball{code}, false, false)
+	if err != nil {
+		t.Fatalf("failed to export Agent: %v", err)
+	}
+	if strings.Contains(string(output), "kind:") {
+		t.Errorf("expected no kind labels or instructions for code balls, got:\n%s", output)
+	}
+
+	output, err = exportAgent(tmpDir, "kinds", []*session.Ball{code, docs}, false, false)
+	if err != nil {
+		t.Fatalf("failed to export Agent: %v", err)
+	}
+	outputStr := string(output)
+	if !strings.Contains(outputStr, "## app-2 [pending] (priority: medium) (kind: docs)") {
+		t.Error("expected docs ball header to include its kind")
+	}
+	if !strings.Contains(outputStr, "## Documentation Balls (kind: docs)") {
+		t.Error("expected docs instructions in output")
+	}
+	if strings.Contains(outputStr, "(kind: research)") || strings.Contains(outputStr, "(kind: ops)") {
+		t.Error("expected only instructions for kinds present in the session")
+	}
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gcDryRun bool
+	gcIdle   time.Duration
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove stale session storage",
+	Long: `Remove session storage that has no session or no recent activity.
+
+Agent runs on the "all" meta-session and interrupted daemons leave directories,
+state files and logs behind in .juggle/sessions. gc removes:
+  - session directories with no session.json (including "_all") untouched for --idle
+  - agent.pid, agent.state, agent.ctrl, agent.sock, agent.log, events.jsonl
+    and last_output.txt files of real sessions untouched for --idle
+
+Sessions with a running daemon or a held agent lock are never touched, and
+session.json and progress.txt of real sessions are always kept. The supervisor
+runs the same cleanup on every poll.
+
+Examples:
+  juggle gc --dry-run        # Report what would be removed
+  juggle gc --idle 24h       # Remove storage idle for a day
+  juggle gc --all            # Clean up every discovered project`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Report stale storage without removing it")
+	gcCmd.Flags().DurationVar(&gcIdle, "idle", session.DefaultReapIdle, "Treat storage untouched for this long as stale")
+	rootCmd.AddCommand(gcCmd)
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+
+	var stale []session.StaleStorage
+	for _, projectDir := range projects {
+		items, err := findStaleSessionStorage(projectDir, gcIdle)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", projectDir, err)
+			continue
+		}
+		stale = append(stale, items...)
+	}
+
+	if len(stale) == 0 {
+		fmt.Println("No stale session storage.")
+		return nil
+	}
+
+	var total int64
+	for _, item := range stale {
+		total += item.Size
+	}
+	if gcDryRun {
+		fmt.Printf("Would remove %d item(s), %s:\n", len(stale), formatBytes(total))
+	} else {
+		fmt.Printf("Removing %d item(s), %s:\n", len(stale), formatBytes(total))
+	}
+	for _, item := range stale {
+		path := item.Path
+		if rel, err := filepath.Rel(cwd, path); err == nil && !GlobalOpts.AllProjects {
+			path = rel
+		}
+		idle := "never used"
+		if !item.LastUsed.IsZero() {
+			idle = "idle " + formatDuration(time.Since(item.LastUsed))
+		}
+		fmt.Printf("  %s %s\n", path, StyleDim.Render(fmt.Sprintf("(%s, %s, %s)", item.Reason, idle, formatBytes(item.Size))))
+	}
+	if gcDryRun {
+		return nil
+	}
+
+	if err := session.RemoveStaleStorage(stale); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Freed %s\n", formatBytes(total))
+	return nil
+}
+
+// findStaleSessionStorage finds a project's stale session storage, skipping
+// sessions whose daemon is still running
+func findStaleSessionStorage(projectDir string, idle time.Duration) ([]session.StaleStorage, error) {
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	return sessionStore.FindStaleStorage(session.ReapOptions{
+		Idle: idle,
+		Active: func(sessionID string) bool {
+			return daemon.IsAlive(sessionStore.ProjectDir(), sessionID)
+		},
+	})
+}
+
+// formatBytes formats a size in bytes for display
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
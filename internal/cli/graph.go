@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// graphTitleLen caps the ball title shown in each node
+const graphTitleLen = 40
+
+var (
+	graphFormat string
+	graphOutput string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [session-id]",
+	Short: "Render balls as a Mermaid or Graphviz dependency graph",
+	Long: `Render a session's balls as a dependency graph, for embedding a picture of
+the plan in design docs or pull requests.
+
+Each ball is a node colored by its state. Solid edges point from a dependency
+to the ball waiting on it; dashed edges point from a child ball to the epic it
+was split from. Dependencies that aren't in the graph (another session, or
+archived) are drawn as dashed outlines. Epics are drawn with a double border.
+
+Without a session, or with "all", every ball in the project is included.
+
+Formats:
+  mermaid   A flowchart for Markdown renderers that support Mermaid (default)
+  dot       Graphviz, for "dot -Tsvg"
+
+Examples:
+  juggle graph my-feature                        # Mermaid to stdout
+  juggle graph my-feature -o docs/plan.mmd       # Write it to a file
+  juggle graph --format dot | dot -Tsvg > plan.svg`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "mermaid", "Output format: mermaid or dot")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write the graph to a file instead of stdout")
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	if graphFormat != "mermaid" && graphFormat != "dot" {
+		return fmt.Errorf("invalid format: %s (must be mermaid or dot)", graphFormat)
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	sessionID := "all"
+	if len(args) == 1 {
+		sessionID = args[0]
+	}
+	balls, err := loadSessionBallsForSnapshot(cwd, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	graph := buildBallGraph(balls)
+	var out string
+	if graphFormat == "dot" {
+		out = graph.dot()
+	} else {
+		out = graph.mermaid()
+	}
+
+	if graphOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(graphOutput, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	fmt.Printf("✓ Graph of %d balls written to %s\n", len(graph.nodes), graphOutput)
+	return nil
+}
+
+// graphEdge points from the ball that has to finish first to the one waiting on it
+type graphEdge struct {
+	from, to string
+	parent   bool // Child to epic rather than dependency to dependent
+}
+
+// ballGraph is the nodes and edges rendered by juggle graph
+type ballGraph struct {
+	nodes    []*session.Ball
+	external []string // IDs referenced by edges but not in nodes
+	edges    []graphEdge
+}
+
+// graphStateStyles are the fill and border colors of each state's nodes
+var graphStateStyles = []struct {
+	state        session.BallState
+	fill, stroke string
+}{
+	{session.StatePending, "#e5e7eb", "#6b7280"},
+	{session.StateInProgress, "#fde68a", "#b45309"},
+	{session.StateBlocked, "#fca5a5", "#b91c1c"},
+	{session.StateComplete, "#bbf7d0", "#15803d"},
+	{session.StateResearched, "#bfdbfe", "#1d4ed8"},
+}
+
+// buildBallGraph collects the dependency and parent/child edges between balls,
+// sorted by ID so the output is stable between runs
+func buildBallGraph(balls []*session.Ball) *ballGraph {
+	g := &ballGraph{nodes: append([]*session.Ball(nil), balls...)}
+	sort.Slice(g.nodes, func(i, j int) bool { return g.nodes[i].ID < g.nodes[j].ID })
+
+	known := make(map[string]bool, len(g.nodes))
+	for _, ball := range g.nodes {
+		known[ball.ID] = true
+	}
+	external := make(map[string]bool)
+	reference := func(id string) {
+		if !known[id] && !external[id] {
+			external[id] = true
+			g.external = append(g.external, id)
+		}
+	}
+
+	// An epic depends on its children, so skip the dependency edges the
+	// parent edges already draw
+	children := make(map[graphEdge]bool)
+	for _, ball := range g.nodes {
+		if ball.Parent != "" {
+			children[graphEdge{from: ball.ID, to: ball.Parent}] = true
+		}
+	}
+
+	for _, ball := range g.nodes {
+		if ball.Parent != "" {
+			reference(ball.Parent)
+			g.edges = append(g.edges, graphEdge{from: ball.ID, to: ball.Parent, parent: true})
+		}
+		for _, dep := range ball.DependsOn {
+			if children[graphEdge{from: dep, to: ball.ID}] {
+				continue
+			}
+			reference(dep)
+			g.edges = append(g.edges, graphEdge{from: dep, to: ball.ID})
+		}
+	}
+	sort.Strings(g.external)
+	return g
+}
+
+// graphLabel is a node's short ID and truncated title
+func graphLabel(ball *session.Ball) (string, string) {
+	return ball.ShortID(), truncate(ball.Title, graphTitleLen)
+}
+
+// mermaid renders the graph as a Mermaid flowchart
+func (g *ballGraph) mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, style := range graphStateStyles {
+		fmt.Fprintf(&b, "    classDef %s fill:%s,stroke:%s\n", style.state, style.fill, style.stroke)
+	}
+	b.WriteString("    classDef external fill:#ffffff,stroke:#9ca3af,stroke-dasharray:4 4\n")
+
+	for _, ball := range g.nodes {
+		id, title := graphLabel(ball)
+		label := mermaidEscape(id + "<br/>" + title)
+		if ball.Epic {
+			fmt.Fprintf(&b, "    %s[[\"%s\"]]:::%s\n", mermaidID(ball.ID), label, ball.State)
+		} else {
+			fmt.Fprintf(&b, "    %s[\"%s\"]:::%s\n", mermaidID(ball.ID), label, ball.State)
+		}
+	}
+	for _, id := range g.external {
+		fmt.Fprintf(&b, "    %s[\"%s\"]:::external\n", mermaidID(id), mermaidEscape(id))
+	}
+
+	for _, e := range g.edges {
+		if e.parent {
+			fmt.Fprintf(&b, "    %s -.-> %s\n", mermaidID(e.from), mermaidID(e.to))
+		} else {
+			fmt.Fprintf(&b, "    %s --> %s\n", mermaidID(e.from), mermaidID(e.to))
+		}
+	}
+	return b.String()
+}
+
+// mermaidID turns a ball ID into a node ID Mermaid accepts
+func mermaidID(id string) string {
+	var b strings.Builder
+	b.WriteString("b_")
+	for _, r := range id {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// mermaidEscape makes text safe inside a quoted Mermaid label
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
+
+// dot renders the graph as a Graphviz digraph
+func (g *ballGraph) dot() string {
+	styles := make(map[session.BallState][2]string, len(graphStateStyles))
+	for _, style := range graphStateStyles {
+		styles[style.state] = [2]string{style.fill, style.stroke}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph juggle {\n")
+	b.WriteString("    rankdir=TB;\n")
+	b.WriteString("    node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for _, ball := range g.nodes {
+		id, title := graphLabel(ball)
+		color := styles[ball.State]
+		peripheries := ""
+		if ball.Epic {
+			peripheries = ", peripheries=2"
+		}
+		fmt.Fprintf(&b, "    %s [label=%s, fillcolor=\"%s\", color=\"%s\"%s];\n",
+			dotQuote(ball.ID), dotQuote(id+"\n"+title), color[0], color[1], peripheries)
+	}
+	for _, id := range g.external {
+		fmt.Fprintf(&b, "    %s [label=%s, style=\"rounded,dashed\", color=\"#9ca3af\"];\n", dotQuote(id), dotQuote(id))
+	}
+
+	for _, e := range g.edges {
+		if e.parent {
+			fmt.Fprintf(&b, "    %s -> %s [style=dashed];\n", dotQuote(e.from), dotQuote(e.to))
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s;\n", dotQuote(e.from), dotQuote(e.to))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes a Graphviz ID or label, keeping newlines as line breaks
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func graphTestBalls() []*session.Ball {
+	return []*session.Ball{
+		{ID: "app-epic", Title: "Ship \"login\"", State: session.StateInProgress, Epic: true, DependsOn: []string{"app-child"}},
+		{ID: "app-child", Title: "Add form", State: session.StateComplete, Parent: "app-epic"},
+		{ID: "app-docs", Title: "Write docs", State: session.StatePending, DependsOn: []string{"app-epic", "app-gone"}},
+	}
+}
+
+func TestBuildBallGraph(t *testing.T) {
+	g := buildBallGraph(graphTestBalls())
+
+	if len(g.nodes) != 3 || g.nodes[0].ID != "app-child" || g.nodes[2].ID != "app-epic" {
+		t.Errorf("Expected nodes sorted by ID, got %v", g.nodes)
+	}
+	if len(g.external) != 1 || g.external[0] != "app-gone" {
+		t.Errorf("Expected app-gone as the only external node, got %v", g.external)
+	}
+
+	want := []graphEdge{
+		{from: "app-child", to: "app-epic", parent: true},
+		{from: "app-epic", to: "app-docs"},
+		{from: "app-gone", to: "app-docs"},
+	}
+	if len(g.edges) != len(want) {
+		t.Fatalf("Expected %d edges, got %v", len(want), g.edges)
+	}
+	for i := range want {
+		if g.edges[i] != want[i] {
+			t.Errorf("Edge %d: expected %+v, got %+v", i, want[i], g.edges[i])
+		}
+	}
+}
+
+func TestBallGraphMermaid(t *testing.T) {
+	out := buildBallGraph(graphTestBalls()).mermaid()
+
+	for _, want := range []string{
+		"flowchart TD\n",
+		"classDef in_progress fill:#fde68a",
+		`b_app_epic[["epic<br/>Ship #quot;login#quot;"]]:::in_progress`,
+		`b_app_child["child<br/>Add form"]:::complete`,
+		`b_app_gone["app-gone"]:::external`,
+		"b_app_child -.-> b_app_epic",
+		"b_app_epic --> b_app_docs",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected mermaid output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "b_app_child --> b_app_epic") {
+		t.Errorf("Expected the epic's dependency on its child to be drawn once, got:\n%s", out)
+	}
+}
+
+func TestBallGraphDot(t *testing.T) {
+	out := buildBallGraph(graphTestBalls()).dot()
+
+	for _, want := range []string{
+		"digraph juggle {\n",
+		`"app-epic" [label="epic\nShip \"login\"", fillcolor="#fde68a", color="#b45309", peripheries=2];`,
+		`"app-gone" [label="app-gone", style="rounded,dashed"`,
+		`"app-child" -> "app-epic" [style=dashed];`,
+		`"app-epic" -> "app-docs";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected dot output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("Expected dot output to close the digraph, got:\n%s", out)
+	}
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// handoffProgressLines is how much of the session's progress log the bundle includes
+const handoffProgressLines = 20
+
+// handoffMaxFiles caps the changed files listed in the working copy section
+const handoffMaxFiles = 30
+
+var handoffOutput string
+
+var handoffCmd = &cobra.Command{
+	Use:   "handoff <session-id>",
+	Short: "Write a markdown handoff bundle for a session",
+	Long: `Write a single markdown document describing where a session stands, for
+handing the work to a teammate (or to yourself later) without digging through .juggle.
+
+The bundle includes:
+  - Session description, context and acceptance criteria
+  - Open balls (in progress, pending, blocked) with context, ACs and blocked reasons
+  - The current branch/revision and any uncommitted changes
+  - The last agent run and recent progress
+  - Commands to resume the agent
+
+Use "all" to describe every ball in the project.
+
+Examples:
+  juggle handoff my-feature                  # Print the bundle
+  juggle handoff my-feature -o HANDOFF.md    # Write it to a file`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHandoff,
+}
+
+func init() {
+	handoffCmd.Flags().StringVarP(&handoffOutput, "output", "o", "", "Write the bundle to a file instead of stdout")
+}
+
+func runHandoff(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	bundle, err := generateHandoff(cwd, args[0], time.Now())
+	if err != nil {
+		return err
+	}
+
+	if handoffOutput == "" {
+		fmt.Print(bundle)
+		return nil
+	}
+
+	if err := os.WriteFile(handoffOutput, []byte(bundle), 0644); err != nil {
+		return fmt.Errorf("failed to write handoff: %w", err)
+	}
+	fmt.Printf("✓ Handoff for %s written to %s\n", args[0], handoffOutput)
+	return nil
+}
+
+// GenerateHandoffForTest is an exported wrapper for testing
+func GenerateHandoffForTest(projectDir, sessionID string, now time.Time) (string, error) {
+	return generateHandoff(projectDir, sessionID, now)
+}
+
+// generateHandoff renders the markdown handoff bundle for a session
+func generateHandoff(projectDir, sessionID string, now time.Time) (string, error) {
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return "", fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	// "all" is a meta-session with no session file
+	var juggleSession *session.JuggleSession
+	if sessionID != "all" {
+		juggleSession, err = sessionStore.LoadSession(sessionID)
+		if err != nil {
+			return "", fmt.Errorf("failed to load session: %w", err)
+		}
+	}
+
+	balls, err := loadSessionBallsForSnapshot(projectDir, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	var open, blocked []*session.Ball
+	done := 0
+	for _, ball := range balls {
+		switch ball.State {
+		case session.StateComplete, session.StateResearched:
+			done++
+		case session.StateBlocked:
+			blocked = append(blocked, ball)
+		default:
+			open = append(open, ball)
+		}
+	}
+	sortBallsForAgent(open)
+	sortBallsForAgent(blocked)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Handoff: %s\n\n", sessionID)
+	fmt.Fprintf(&b, "_Generated %s in `%s`_\n\n", now.Format("2006-01-02 15:04"), projectDir)
+
+	if juggleSession != nil && juggleSession.Description != "" {
+		fmt.Fprintf(&b, "> %s\n\n", juggleSession.Description)
+	}
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Balls: %d open, %d blocked, %d done\n", len(open), len(blocked), done)
+	if line := handoffLastRun(projectDir, sessionID); line != "" {
+		fmt.Fprintf(&b, "- Last agent run: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	if juggleSession != nil {
+		if strings.TrimSpace(juggleSession.Context) != "" {
+			b.WriteString("## Context\n\n")
+			b.WriteString(strings.TrimSpace(juggleSession.Context))
+			b.WriteString("\n\n")
+		}
+		if len(juggleSession.AcceptanceCriteria) > 0 {
+			b.WriteString("## Session Acceptance Criteria\n\n")
+			writeHandoffList(&b, juggleSession.AcceptanceCriteria)
+			b.WriteString("\n")
+		}
+	}
+
+	if len(blocked) > 0 {
+		b.WriteString("## Blocked\n\n")
+		for _, ball := range blocked {
+			writeHandoffBall(&b, ball)
+		}
+	}
+
+	b.WriteString("## Open Balls\n\n")
+	if len(open) == 0 {
+		b.WriteString("None.\n\n")
+	}
+	for _, ball := range open {
+		writeHandoffBall(&b, ball)
+	}
+
+	writeHandoffWorkingCopy(&b, projectDir)
+
+	progress, err := sessionStore.LoadProgress(sessionStorageID(sessionID))
+	if err == nil && strings.TrimSpace(progress) != "" {
+		fmt.Fprintf(&b, "## Recent Progress\n\n```\n%s\n```\n\n", limitToLastLines(progress, handoffProgressLines))
+	}
+
+	writeHandoffResume(&b, sessionID, blocked)
+
+	return b.String(), nil
+}
+
+// writeHandoffBall renders one ball as a markdown subsection
+func writeHandoffBall(b *strings.Builder, ball *session.Ball) {
+	fmt.Fprintf(b, "### %s: %s\n\n", ball.ID, ball.Title)
+
+	details := []string{
+		fmt.Sprintf("State: %s", ball.State),
+		fmt.Sprintf("Priority: %s", ball.Priority),
+	}
+	if ball.Kind != session.BallKindBlank {
+		details = append(details, fmt.Sprintf("Kind: %s", ball.Kind))
+	}
+	if len(ball.DependsOn) > 0 {
+		details = append(details, fmt.Sprintf("Depends on: %s", strings.Join(ball.DependsOn, ", ")))
+	}
+	fmt.Fprintf(b, "%s\n\n", strings.Join(details, " · "))
+
+	if ball.State == session.StateBlocked && ball.BlockedReason != "" {
+		fmt.Fprintf(b, "**Blocked:** %s\n\n", ball.BlockedReason)
+	}
+	if strings.TrimSpace(ball.Context) != "" {
+		b.WriteString(strings.TrimSpace(ball.Context))
+		b.WriteString("\n\n")
+	}
+	if len(ball.AcceptanceCriteria) > 0 {
+		b.WriteString("Acceptance criteria:\n\n")
+		writeHandoffList(b, ball.AcceptanceCriteria)
+		b.WriteString("\n")
+	}
+	if strings.TrimSpace(ball.Output) != "" {
+		b.WriteString("Output:\n\n")
+		b.WriteString(strings.TrimSpace(ball.Output))
+		b.WriteString("\n\n")
+	}
+}
+
+// writeHandoffList renders items as a numbered markdown list
+func writeHandoffList(b *strings.Builder, items []string) {
+	for i, item := range items {
+		fmt.Fprintf(b, "%d. %s\n", i+1, item)
+	}
+}
+
+// writeHandoffWorkingCopy renders the current revision and uncommitted changes.
+// VCS errors are reported inline rather than failing the handoff.
+func writeHandoffWorkingCopy(b *strings.Builder, projectDir string) {
+	backend := vcsBackendForProject(projectDir)
+	b.WriteString("## Working Copy\n\n")
+	fmt.Fprintf(b, "- VCS: %s\n", backend.Type())
+
+	revision, err := backend.GetCurrentRevision(projectDir)
+	if err != nil {
+		fmt.Fprintf(b, "- Revision: unavailable (%v)\n\n", err)
+		return
+	}
+	fmt.Fprintf(b, "- Revision: `%s`\n", revision)
+
+	base, err := backend.GetSnapshotRevision(projectDir)
+	if err != nil {
+		b.WriteString("\n")
+		return
+	}
+	files, lines, err := backend.DiffStat(projectDir, base)
+	if err != nil || files == 0 {
+		b.WriteString("- Uncommitted changes: none\n\n")
+		return
+	}
+	fmt.Fprintf(b, "- Uncommitted changes: %d files, %d lines\n\n", files, lines)
+
+	changed, err := backend.ChangedFiles(projectDir, base)
+	if err != nil || len(changed) == 0 {
+		return
+	}
+	for i, path := range changed {
+		if i == handoffMaxFiles {
+			fmt.Fprintf(b, "- ... and %d more\n", len(changed)-handoffMaxFiles)
+			break
+		}
+		fmt.Fprintf(b, "- `%s`\n", path)
+	}
+	b.WriteString("\n")
+}
+
+// handoffLastRun summarises the session's most recent agent run, or "" if it has none
+func handoffLastRun(projectDir, sessionID string) string {
+	historyStore, err := session.NewAgentHistoryStore(projectDir)
+	if err != nil {
+		return ""
+	}
+	records, err := historyStore.LoadHistoryBySession(sessionID)
+	if err != nil || len(records) == 0 {
+		return ""
+	}
+
+	// History is sorted most recent first
+	last := records[0]
+	line := fmt.Sprintf("%s on %s, %d/%d iterations, %d/%d balls complete",
+		last.Result, last.StartedAt.Format("2006-01-02 15:04"),
+		last.Iterations, last.MaxIterations, last.BallsComplete, last.BallsTotal)
+	switch {
+	case last.BlockedReason != "":
+		line += fmt.Sprintf(" (%s)", last.BlockedReason)
+	case last.ErrorMessage != "":
+		line += fmt.Sprintf(" (%s)", last.ErrorMessage)
+	case last.TimeoutMessage != "":
+		line += fmt.Sprintf(" (%s)", last.TimeoutMessage)
+	}
+	if usage := session.FormatUsage(last.InputTokens, last.OutputTokens, last.CostUSD); usage != "" {
+		line += fmt.Sprintf(" · %s", usage)
+	}
+	return line
+}
+
+// writeHandoffResume renders the commands needed to pick the session back up
+func writeHandoffResume(b *strings.Builder, sessionID string, blocked []*session.Ball) {
+	b.WriteString("## How to Resume\n\n")
+	if len(blocked) > 0 {
+		b.WriteString("Resolve the blocked balls above, then unblock them:\n\n```bash\n")
+		for _, ball := range blocked {
+			fmt.Fprintf(b, "juggle update %s --state in_progress\n", ball.ID)
+		}
+		b.WriteString("```\n\n")
+	}
+	b.WriteString("Review the session and start the agent:\n\n```bash\n")
+	if sessionID != "all" {
+		fmt.Fprintf(b, "juggle sessions show %s\n", sessionID)
+	}
+	fmt.Fprintf(b, "juggle agent run %s\n", sessionID)
+	b.WriteString("```\n")
+}
@@ -144,7 +144,7 @@ func runImportRalph(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to create session store: %w", err)
 		}
 		if _, err := sessionStore.LoadSession(importSessionID); err != nil {
-			return fmt.Errorf("session not found: %s", importSessionID)
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, importSessionID)
 		}
 	}
 
@@ -170,6 +170,9 @@ func importPRDFile(prdPath, projectDir, sessionID string) error {
 		return fmt.Errorf("failed to create store: %w", err)
 	}
 
+	// Keep changes in memory to write them with one rewrite of balls.jsonl
+	store = store.Cached()
+
 	// Load existing balls
 	balls, err := store.LoadBalls()
 	if err != nil {
@@ -232,6 +235,10 @@ func importPRDFile(prdPath, projectDir, sessionID string) error {
 		existingTitles[story.Title] = true
 	}
 
+	if err := store.Flush(); err != nil {
+		return fmt.Errorf("failed to save balls: %w", err)
+	}
+
 	fmt.Printf("\nImport complete: %d imported, %d skipped\n", imported, skipped)
 	return nil
 }
@@ -300,7 +307,7 @@ func runImportGitHub(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to create session store: %w", err)
 		}
 		if _, err := sessionStore.LoadSession(importSessionID); err != nil {
-			return fmt.Errorf("session not found: %s", importSessionID)
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, importSessionID)
 		}
 	}
 
@@ -404,6 +411,9 @@ func ImportGitHubIssues(issues []GitHubIssue, projectDir, sessionID string) erro
 		return fmt.Errorf("failed to create store: %w", err)
 	}
 
+	// Keep changes in memory to write them with one rewrite of balls.jsonl
+	store = store.Cached()
+
 	// Load existing balls
 	balls, err := store.LoadBalls()
 	if err != nil {
@@ -472,6 +482,10 @@ func ImportGitHubIssues(issues []GitHubIssue, projectDir, sessionID string) erro
 		existingTitles[issue.Title] = true
 	}
 
+	if err := store.Flush(); err != nil {
+		return fmt.Errorf("failed to save balls: %w", err)
+	}
+
 	fmt.Printf("\nImport complete: %d imported, %d skipped\n", imported, skipped)
 	return nil
 }
@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/linear"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importLinearTeam      string
+	importLinearProject   string
+	importLinearLabel     string
+	importLinearState     string
+	importLinearLimit     int
+	importLinearWriteBack bool
+)
+
+// linearTagPrefix marks a ball imported from Linear; the issue identifier follows
+const linearTagPrefix = "linear#"
+
+// importLinearCmd imports Linear issues as balls
+var importLinearCmd = &cobra.Command{
+	Use:   "linear",
+	Short: "Import Linear issues as balls",
+	Long: `Import issues from Linear as juggle balls.
+
+Creates balls from issues with the following mappings:
+  - issue title       → title
+  - issue description → acceptance criteria (parsed from lists, like github)
+  - issue identifier  → tag linear#<identifier>, e.g. linear#ENG-123
+  - issue labels      → tags
+  - issue project     → session (created if missing), unless --session is given
+  - priority          → urgent, high, medium (also "no priority") or low
+  - estimate          → model size hint: up to 1 point small, up to 3 medium, more large
+  - completed state   → state: complete (canceled issues are skipped)
+
+The API key comes from "linear.api_key" in ~/.juggle/config.json or the
+LINEAR_API_KEY environment variable. "linear.team" sets a default team key.
+
+With --write-back (or "linear.write_back": true), issues in the import that are
+still open in Linear but whose ball is complete are moved to the team's first
+completed workflow state.
+
+Skips issues that were already imported (matching by linear# tag).
+
+Examples:
+  # Import open issues from the ENG team
+  juggle import linear --team ENG
+
+  # Import one project's issues into a specific session
+  juggle import linear --project "Auth Revamp" --session auth
+
+  # Import, then mark issues of completed balls done in Linear
+  juggle import linear --write-back`,
+	Args: cobra.NoArgs,
+	RunE: runImportLinear,
+}
+
+func init() {
+	importLinearCmd.Flags().StringVarP(&importSessionID, "session", "s", "", "Session ID to tag imported balls with (default: one per Linear project)")
+	importLinearCmd.Flags().StringVar(&importLinearTeam, "team", "", "Team key to import from, e.g. ENG (default: linear.team from config, or all teams)")
+	importLinearCmd.Flags().StringVar(&importLinearProject, "project", "", "Filter by project name")
+	importLinearCmd.Flags().StringVar(&importLinearLabel, "label", "", "Filter by label name")
+	importLinearCmd.Flags().StringVar(&importLinearState, "state", "open", "Filter by state (open, all)")
+	importLinearCmd.Flags().IntVar(&importLinearLimit, "limit", 100, "Maximum number of issues to import")
+	importLinearCmd.Flags().BoolVar(&importLinearWriteBack, "write-back", false, "Move issues of completed balls to a completed state in Linear (default: linear.write_back from config)")
+	importCmd.AddCommand(importLinearCmd)
+}
+
+func runImportLinear(cmd *cobra.Command, args []string) error {
+	if importLinearState != "open" && importLinearState != "all" {
+		return fmt.Errorf("invalid state: %s (must be open or all)", importLinearState)
+	}
+
+	config, err := session.GetGlobalLinearConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiKey := config.GetAPIKey()
+	if apiKey == "" {
+		return fmt.Errorf("no Linear API key: set linear.api_key in ~/.juggle/config.json or %s", session.EnvLinearAPIKey)
+	}
+	team := importLinearTeam
+	writeBack := importLinearWriteBack
+	if config != nil {
+		if team == "" {
+			team = config.Team
+		}
+		if !cmd.Flags().Changed("write-back") {
+			writeBack = config.WriteBack
+		}
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	// Validate session exists if specified
+	if importSessionID != "" {
+		sessionStore, err := session.NewSessionStore(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to create session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(importSessionID); err != nil {
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, importSessionID)
+		}
+	}
+
+	ctx := commandContext(cmd)
+	client := linear.NewClient(apiKey)
+	issues, err := client.Issues(ctx, linear.IssueFilter{
+		Team:          team,
+		Project:       importLinearProject,
+		Label:         importLinearLabel,
+		IncludeClosed: importLinearState == "all",
+		Limit:         importLinearLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	if len(issues) == 0 {
+		fmt.Println("No issues found matching the criteria.")
+		return nil
+	}
+
+	if err := ImportLinearIssues(issues, cwd, importSessionID); err != nil {
+		return err
+	}
+	if writeBack {
+		return writeBackLinearCompletions(ctx, client, issues, cwd)
+	}
+	return nil
+}
+
+// ImportLinearIssues imports Linear issues as balls (exported for testing)
+func ImportLinearIssues(issues []linear.Issue, projectDir, sessionID string) error {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	// Keep changes in memory to write them with one rewrite of balls.jsonl
+	store = store.Cached()
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	imported, err := linearBallsByIdentifier(store)
+	if err != nil {
+		return err
+	}
+
+	var created, skipped int
+	sessions := make(map[string]bool)
+	for _, issue := range issues {
+		if imported[issue.Identifier] != nil {
+			fmt.Printf("Skipped: %s - \"%s\" (already exists)\n", issue.Identifier, issue.Title)
+			skipped++
+			continue
+		}
+		if issue.State.Type == "canceled" {
+			fmt.Printf("Skipped: %s - \"%s\" (canceled)\n", issue.Identifier, issue.Title)
+			skipped++
+			continue
+		}
+
+		ball, err := session.NewBall(projectDir, issue.Title, linearPriority(issue.Priority))
+		if err != nil {
+			fmt.Printf("Warning: failed to create ball for %s: %v\n", issue.Identifier, err)
+			continue
+		}
+
+		if criteria := ParseAcceptanceCriteria(issue.Description); len(criteria) > 0 {
+			ball.SetAcceptanceCriteria(criteria)
+		}
+		if issue.URL != "" {
+			ball.Context = fmt.Sprintf("Linear %s: %s", issue.Identifier, issue.URL)
+		}
+		if size := linearModelSize(issue.Estimate); size != session.ModelSizeBlank {
+			ball.Estimate = &session.BallEstimate{ModelSize: size, Model: "linear", EstimatedAt: time.Now()}
+		}
+		if issue.State.Type == "completed" {
+			ball.State = session.StateComplete
+			now := time.Now()
+			ball.CompletedAt = &now
+		}
+
+		ball.AddTag(linearTagPrefix + issue.Identifier)
+		for _, label := range issue.Labels {
+			ball.AddTag(label)
+		}
+
+		tag := sessionID
+		if tag == "" && issue.Project != nil {
+			tag = linearSessionID(issue.Project.Name)
+			if tag != "" && !sessions[tag] {
+				if err := ensureLinearSession(sessionStore, tag, issue.Project.Name); err != nil {
+					fmt.Printf("Warning: %v\n", err)
+					tag = ""
+				} else {
+					sessions[tag] = true
+				}
+			}
+		}
+		if tag != "" {
+			ball.AddTag(tag)
+		}
+
+		if err := store.AppendBall(ball); err != nil {
+			fmt.Printf("Warning: failed to create ball for %s: %v\n", issue.Identifier, err)
+			continue
+		}
+		created++
+		fmt.Printf("Imported: %s → %s (%s)\n", issue.Identifier, ball.ID, ball.State)
+		imported[issue.Identifier] = ball
+	}
+
+	if err := store.Flush(); err != nil {
+		return fmt.Errorf("failed to save balls: %w", err)
+	}
+
+	fmt.Printf("\nImport complete: %d imported, %d skipped\n", created, skipped)
+	return nil
+}
+
+// writeBackLinearCompletions moves issues that are open in Linear but whose
+// ball is complete to a completed workflow state
+func writeBackLinearCompletions(ctx context.Context, client *linear.Client, issues []linear.Issue, projectDir string) error {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	balls, err := linearBallsByIdentifier(store)
+	if err != nil {
+		return err
+	}
+
+	completed := 0
+	for _, issue := range linearIssuesToComplete(issues, balls) {
+		state, err := client.CompleteIssue(ctx, issue.Identifier)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to complete %s in Linear: %v\n", issue.Identifier, err)
+			continue
+		}
+		if state.ID == "" {
+			continue
+		}
+		completed++
+		fmt.Printf("Completed in Linear: %s → %s\n", issue.Identifier, state.Name)
+	}
+	if completed > 0 {
+		fmt.Printf("\nWrite-back complete: %d issue(s) completed\n", completed)
+	}
+	return nil
+}
+
+// linearIssuesToComplete returns the open issues whose ball is complete
+func linearIssuesToComplete(issues []linear.Issue, balls map[string]*session.Ball) []linear.Issue {
+	var open []linear.Issue
+	for _, issue := range issues {
+		ball := balls[issue.Identifier]
+		if ball != nil && ball.State == session.StateComplete && !issue.State.Done() {
+			open = append(open, issue)
+		}
+	}
+	return open
+}
+
+// linearBallsByIdentifier returns the project's active and archived balls
+// imported from Linear, keyed by issue identifier
+func linearBallsByIdentifier(store *session.Store) (map[string]*session.Ball, error) {
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+	archived, err := store.LoadArchivedBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	byIdentifier := make(map[string]*session.Ball)
+	for _, ball := range append(archived, balls...) {
+		for _, tag := range ball.Tags {
+			if identifier, ok := strings.CutPrefix(tag, linearTagPrefix); ok {
+				byIdentifier[identifier] = ball
+			}
+		}
+	}
+	return byIdentifier, nil
+}
+
+// ensureLinearSession creates the session for a Linear project unless it exists
+func ensureLinearSession(sessionStore *session.SessionStore, id, projectName string) error {
+	if _, err := sessionStore.LoadSession(id); err == nil {
+		return nil
+	}
+	if _, err := sessionStore.CreateSession(id, "Linear project: "+projectName); err != nil {
+		return fmt.Errorf("failed to create session %s: %w", id, err)
+	}
+	fmt.Printf("Created session: %s\n", id)
+	return nil
+}
+
+// linearSessionPattern matches runs of characters not allowed in a session ID
+var linearSessionPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// linearSessionID turns a Linear project name into a session ID, e.g.
+// "Auth Revamp (Q3)" → "auth-revamp-q3"
+func linearSessionID(projectName string) string {
+	return strings.Trim(linearSessionPattern.ReplaceAllString(strings.ToLower(projectName), "-"), "-")
+}
+
+// linearPriority maps Linear's priority (0 none, 1 urgent ... 4 low) to a ball priority
+func linearPriority(priority int) session.Priority {
+	switch priority {
+	case 1:
+		return session.PriorityUrgent
+	case 2:
+		return session.PriorityHigh
+	case 4:
+		return session.PriorityLow
+	default:
+		return session.PriorityMedium
+	}
+}
+
+// linearModelSize maps an issue's point estimate to a model size hint
+func linearModelSize(estimate *float64) session.ModelSize {
+	switch {
+	case estimate == nil || *estimate <= 0:
+		return session.ModelSizeBlank
+	case *estimate <= 1:
+		return session.ModelSizeSmall
+	case *estimate <= 3:
+		return session.ModelSizeMedium
+	default:
+		return session.ModelSizeLarge
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/ohare93/juggle/internal/linear"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestLinearSessionID(t *testing.T) {
+	tests := map[string]string{
+		"Auth Revamp":      "auth-revamp",
+		"Auth Revamp (Q3)": "auth-revamp-q3",
+		"  --API v2--  ":   "api-v2",
+		"!!!":              "",
+	}
+	for name, want := range tests {
+		if got := linearSessionID(name); got != want {
+			t.Errorf("linearSessionID(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLinearModelSize(t *testing.T) {
+	points := func(p float64) *float64 { return &p }
+	tests := []struct {
+		estimate *float64
+		want     session.ModelSize
+	}{
+		{nil, session.ModelSizeBlank},
+		{points(0), session.ModelSizeBlank},
+		{points(1), session.ModelSizeSmall},
+		{points(2), session.ModelSizeMedium},
+		{points(3), session.ModelSizeMedium},
+		{points(8), session.ModelSizeLarge},
+	}
+	for _, tt := range tests {
+		if got := linearModelSize(tt.estimate); got != tt.want {
+			t.Errorf("linearModelSize(%v) = %q, want %q", tt.estimate, got, tt.want)
+		}
+	}
+}
+
+func TestLinearIssuesToComplete(t *testing.T) {
+	issues := []linear.Issue{
+		{Identifier: "ENG-1", State: linear.State{Type: "started"}},
+		{Identifier: "ENG-2", State: linear.State{Type: "unstarted"}},
+		{Identifier: "ENG-3", State: linear.State{Type: "completed"}},
+		{Identifier: "ENG-4", State: linear.State{Type: "started"}},
+	}
+	balls := map[string]*session.Ball{
+		"ENG-1": {State: session.StateComplete},
+		"ENG-2": {State: session.StatePending},
+		"ENG-3": {State: session.StateComplete},
+	}
+
+	got := linearIssuesToComplete(issues, balls)
+	if len(got) != 1 || got[0].Identifier != "ENG-1" {
+		t.Errorf("Expected only ENG-1 to be completed, got %+v", got)
+	}
+}
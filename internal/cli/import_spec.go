@@ -103,7 +103,7 @@ func runImportSpec(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to create session store: %w", err)
 		}
 		if _, err := sessionStore.LoadSession(importSpecSessionID); err != nil {
-			return fmt.Errorf("session not found: %s", importSpecSessionID)
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, importSpecSessionID)
 		}
 	}
 
@@ -19,14 +19,17 @@ import (
 // knownCommands maps top-level subcommand names to their subcommands (if any).
 // Used to provide helpful error messages when a ball ID looks like a command.
 var knownCommands = map[string][]string{
+	"activity": {},
 	"agent":    {"run", "refine"},
 	"audit":    {},
 	"balls":    {},
 	"check":    {},
 	"config":   {"ac", "delay", "vcs"},
 	"delete":   {},
+	"deps":     {"add", "remove", "show"},
 	"edit":     {},
 	"export":   {},
+	"gc":       {},
 	"history":  {},
 	"import":   {"ralph", "github"},
 	"list":     {},
@@ -44,6 +47,7 @@ var knownCommands = map[string][]string{
 	"tag":      {"add", "rm", "list"},
 	"tui":      {},
 	"unarchive": {},
+	"unblock":   {},
 	"update":   {},
 	"worktree": {"add", "forget", "list", "status"},
 }
@@ -108,7 +112,7 @@ func enhanceBallNotFoundError(err error, ballID string, args []string) error {
 	// Warn about unused arguments if we got extra args after ball ID
 	if len(args) > 1 {
 		unusedArgs := args[1:]
-		return fmt.Errorf("%s\nNote: unused arguments after ball ID: %s", errStr, strings.Join(unusedArgs, " "))
+		return fmt.Errorf("%w\nNote: unused arguments after ball ID: %s", err, strings.Join(unusedArgs, " "))
 	}
 
 	return err
@@ -344,10 +348,7 @@ func listAllBalls(cmd *cobra.Command) error {
 	if BallsListOpts.JSONOutput {
 		data, err := json.MarshalIndent(allBalls, "", "  ")
 		if err != nil {
-			errResp := map[string]string{"error": err.Error()}
-			errData, _ := json.Marshal(errResp)
-			fmt.Println(string(errData))
-			return err
+			return printJSONError(err)
 		}
 		fmt.Println(string(data))
 		return nil
@@ -505,6 +506,12 @@ func listAllBalls(cmd *cobra.Command) error {
 // By default only searches current project; use --all flag for cross-project search
 // Returns the ball and a store configured for that ball's working directory
 func findBallByID(ballID string) (*session.Ball, *session.Store, error) {
+	return findBallByIDWithArchive(ballID, false)
+}
+
+// findBallByIDWithArchive is findBallByID that also searches archived balls
+// when includeArchive is set. IDs may be project-qualified ("api:12").
+func findBallByIDWithArchive(ballID string, includeArchive bool) (*session.Ball, *session.Store, error) {
 	config, err := LoadConfigForCommand()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load config: %w", err)
@@ -530,21 +537,25 @@ func findBallByID(ballID string) (*session.Ball, *session.Store, error) {
 		return nil, nil, fmt.Errorf("failed to load balls: %w", err)
 	}
 
+	var archivedBalls []*session.Ball
+	if includeArchive {
+		archivedBalls, err = session.LoadArchivedBalls(projects)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load archived balls: %w", err)
+		}
+	}
+
 	// Use prefix matching
-	matches := session.ResolveBallByPrefix(allBalls, ballID)
+	matches := session.ResolveBallByPrefixWithArchive(allBalls, archivedBalls, ballID, includeArchive)
 	if len(matches) == 0 {
 		// If not found and we're in local mode, suggest using --all
 		if !GlobalOpts.AllProjects {
-			return nil, nil, fmt.Errorf("ball not found in current project: %s (use --all to search all projects)", ballID)
+			return nil, nil, fmt.Errorf("%w in current project: %s (use --all to search all projects)", session.ErrBallNotFound, ballID)
 		}
-		return nil, nil, fmt.Errorf("ball not found: %s", ballID)
+		return nil, nil, fmt.Errorf("%w: %s", session.ErrBallNotFound, ballID)
 	}
 	if len(matches) > 1 {
-		matchingIDs := make([]string, len(matches))
-		for i, m := range matches {
-			matchingIDs[i] = m.ID
-		}
-		return nil, nil, fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+		return nil, nil, ambiguousBallError(ballID, matches, archivedBalls)
 	}
 
 	ball := matches[0]
@@ -556,6 +567,35 @@ func findBallByID(ballID string) (*session.Ball, *session.Store, error) {
 	return ball, ballStore, nil
 }
 
+// ambiguousBallError lists the balls a prefix matched by full ID, plus the
+// project-qualified ID when they span projects, marking archived ones
+func ambiguousBallError(ballID string, matches, archived []*session.Ball) error {
+	isArchived := make(map[*session.Ball]bool, len(archived))
+	for _, ball := range archived {
+		isArchived[ball] = true
+	}
+	projects := make(map[string]bool)
+	for _, m := range matches {
+		projects[m.ProjectName()] = true
+	}
+
+	matchingIDs := make([]string, len(matches))
+	for i, m := range matches {
+		matchingIDs[i] = m.ID
+		if len(projects) > 1 {
+			matchingIDs[i] += " (" + m.QualifiedID() + ")"
+		}
+		if isArchived[m] {
+			matchingIDs[i] += " [archived]"
+		}
+	}
+	err := session.NewAmbiguousIDError(ballID, matchingIDs)
+	if len(projects) > 1 {
+		return fmt.Errorf("%w (use <project>:<id> to pick one)", err)
+	}
+	return err
+}
+
 func handleBallCommand(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("ball ID required")
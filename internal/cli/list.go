@@ -1,12 +1,165 @@
 package cli
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listArchived bool
+	listSince    string
+	listSession  string
+	listLimit    int
+	listPage     int
+)
+
+// listPageSize is the page size of --page when --limit isn't given
+const listPageSize = 50
+
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List all sessions (alias for status)",
-	Long:  `List all active sessions. This is an alias for the status command.`,
-	RunE:  runStatus, // Reuse status command
+	Short: "List all sessions (alias for status), or archived balls with --archived",
+	Long: `List all active sessions. This is an alias for the status command.
+
+With --archived, list archived (done) balls instead, most recently completed
+first. Archived balls can be opened with juggle show <id>.
+
+Examples:
+  juggle list                                # Same as juggle status
+  juggle list --archived                     # Archived balls in this project
+  juggle list --archived --since 90d         # Completed in the last 90 days
+  juggle list --archived --session auth      # Archived balls from one session
+  juggle list --archived --limit 100 --page 3  # Archived balls 201-300
+  juggle list --archived --since 2025-06-01 --all`,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listArchived, "archived", false, "List archived balls instead of active sessions")
+	listCmd.Flags().StringVar(&listSince, "since", "", "With --archived: completed within a duration (90d, 2w, 12h) or since a date (YYYY-MM-DD)")
+	listCmd.Flags().StringVar(&listSession, "session", "", "With --archived: only balls from this session")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "With --archived: maximum number of results (0 = no limit), the page size with --page")
+	listCmd.Flags().IntVar(&listPage, "page", 0, fmt.Sprintf("With --archived: show this page of --limit results (default page size %d)", listPageSize))
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	if !listArchived {
+		for _, name := range []string{"since", "session", "limit", "page"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s requires --archived", name)
+			}
+		}
+		return runStatus(cmd, args)
+	}
+
+	query := session.ArchiveQuery{
+		Session: listSession,
+		Limit:   listLimit,
+		SortBy:  session.SortByCompletedDesc,
+	}
+	if cmd.Flags().Changed("page") {
+		if listPage < 1 {
+			return fmt.Errorf("--page must be 1 or more")
+		}
+		if query.Limit <= 0 {
+			query.Limit = listPageSize
+		}
+		query.Offset = (listPage - 1) * query.Limit
+	}
+	// Ask for one more to tell whether there is a next page
+	pageSize := query.Limit
+	if pageSize > 0 {
+		query.Limit++
+	}
+	if listSince != "" {
+		since, err := parseSince(listSince, time.Now())
+		if err != nil {
+			return err
+		}
+		query.CompletedAfter = &since
+	}
+
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	// Discover projects (respects --all flag)
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+
+	balls, err := session.QueryArchive(projects, query)
+	if err != nil {
+		return fmt.Errorf("failed to query archive: %w", err)
+	}
+	more := pageSize > 0 && len(balls) > pageSize
+	if more {
+		balls = balls[:pageSize]
+	}
+	if len(balls) == 0 {
+		fmt.Println("No archived balls found matching criteria.")
+		return nil
+	}
+
+	if query.Offset > 0 || more {
+		fmt.Printf("Archived balls %d-%d", query.Offset+1, query.Offset+len(balls))
+	} else {
+		fmt.Printf("%d archived ball(s)", len(balls))
+	}
+	if query.CompletedAfter != nil {
+		fmt.Printf(" completed since %s", query.CompletedAfter.Format("2006-01-02"))
+	}
+	if query.Session != "" {
+		fmt.Printf(" in session %s", query.Session)
+	}
+	fmt.Println()
+	fmt.Println()
+
+	renderArchivedBalls(balls)
+	if more {
+		next := listPage + 1
+		if listPage == 0 {
+			next = 2
+		}
+		fmt.Printf("\nMore archived balls match; see them with --page %d\n", next)
+	}
+	return nil
+}
+
+// parseSince turns a --since value into a cutoff time. It accepts a date
+// (YYYY-MM-DD), a number of days or weeks ("90d", "2w"), or a Go duration ("36h").
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				break
+			}
+			return now.Add(-time.Duration(count) * unit), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 90d, 2w, 12h or YYYY-MM-DD)", value)
 }
@@ -95,7 +95,7 @@ func runLoopUpdate(cmd *cobra.Command, args []string) error {
 		if sessionID == "" {
 			err := fmt.Errorf("session ID required: provide as first argument or set JUGGLE_SESSION_ID")
 			if loopUpdateJSONFlag {
-				return printLoopUpdateJSONError(err)
+				return printJSONError(err)
 			}
 			return err
 		}
@@ -115,7 +115,7 @@ func runLoopUpdate(cmd *cobra.Command, args []string) error {
 	if !validStates[state] {
 		err := fmt.Errorf("invalid state '%s': must be one of starting, working, blocked, testing, complete", state)
 		if loopUpdateJSONFlag {
-			return printLoopUpdateJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -124,7 +124,7 @@ func runLoopUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		err = fmt.Errorf("failed to get current directory: %w", err)
 		if loopUpdateJSONFlag {
-			return printLoopUpdateJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -133,7 +133,7 @@ func runLoopUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		err = fmt.Errorf("failed to initialize session store: %w", err)
 		if loopUpdateJSONFlag {
-			return printLoopUpdateJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -152,7 +152,7 @@ func runLoopUpdate(cmd *cobra.Command, args []string) error {
 	if err := store.WriteAgentUpdate(storageID, entry); err != nil {
 		err = fmt.Errorf("failed to write agent update: %w", err)
 		if loopUpdateJSONFlag {
-			return printLoopUpdateJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -190,13 +190,6 @@ func printLoopUpdateJSONSuccess(sessionID, ballID, state, message, timestamp str
 	return nil
 }
 
-func printLoopUpdateJSONError(err error) error {
-	errResp := map[string]string{"error": err.Error()}
-	data, _ := json.Marshal(errResp)
-	fmt.Println(string(data))
-	return nil // Return nil so the error is in JSON, not stderr
-}
-
 // runLoopHookEvent processes Claude Code hook events and updates session metrics
 func runLoopHookEvent(cmd *cobra.Command, args []string) error {
 	eventType := args[0]
@@ -234,7 +227,7 @@ func runLoopHookEvent(cmd *cobra.Command, args []string) error {
 	// Process based on event type
 	switch eventType {
 	case "post-tool":
-		return handlePostToolEvent(store, storageID, inputData)
+		return handlePostToolEvent(store, storageID, cwd, inputData)
 	case "tool-failure":
 		return handleToolFailureEvent(store, storageID, inputData)
 	case "stop":
@@ -265,7 +258,7 @@ type StopPayload struct {
 	} `json:"usage"`
 }
 
-func handlePostToolEvent(store *session.SessionStore, sessionID string, inputData []byte) error {
+func handlePostToolEvent(store *session.SessionStore, sessionID, workDir string, inputData []byte) error {
 	var payload PostToolPayload
 	if err := json.Unmarshal(inputData, &payload); err != nil {
 		return nil // Invalid JSON, fail silently
@@ -274,7 +267,8 @@ func handlePostToolEvent(store *session.SessionStore, sessionID string, inputDat
 	// Determine the file path from tool input
 	filePath := payload.ToolInput.FilePath
 
-	return store.UpdateMetricsFromPostTool(sessionID, payload.ToolName, filePath)
+	// Changed files are attributed to the ball the agent loop is working on
+	return store.UpdateMetricsFromPostTool(sessionID, payload.ToolName, filePath, workDir, os.Getenv("JUGGLE_BALL_ID"))
 }
 
 func handleToolFailureEvent(store *session.SessionStore, sessionID string, inputData []byte) error {
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -36,6 +37,7 @@ Non-interactive mode (for headless agents):
   juggle plan "Task intent" --non-interactive              # Uses defaults
   juggle plan "Task" -p high -c "AC1" --non-interactive    # With options
   juggle plan "Task" --context "Background info" --non-interactive
+  juggle plan "Document the sync API" --kind docs --non-interactive
 
 In non-interactive mode:
   - Intent is required (via args or --intent flag)
@@ -43,6 +45,8 @@ In non-interactive mode:
   - Priority defaults to 'medium' if not specified
   - State is always 'pending' (new balls start in pending state)
   - Tags, session, and acceptance criteria default to empty if not specified
+  - Kind defaults to code; docs, research and ops balls get their own agent
+    instructions and skip build/test validation
 
 Planned balls can be started later with: juggle <ball-id>`,
 	RunE: runPlan,
@@ -55,6 +59,7 @@ var contextFlag string
 var nonInteractiveFlag bool
 var editFlag bool
 var planJSONFlag bool
+var kindFlag string
 
 func init() {
 	planCmd.Flags().StringVarP(&intentFlag, "intent", "i", "", "What are you planning to work on?")
@@ -65,6 +70,8 @@ func init() {
 	planCmd.Flags().StringSliceVarP(&tagsFlag, "tags", "t", []string{}, "Tags for categorization")
 	planCmd.Flags().StringVarP(&sessionFlag, "session", "s", "", "Session ID to link this ball to (adds session ID as tag)")
 	planCmd.Flags().StringVarP(&modelSizeFlag, "model-size", "m", "", "Preferred LLM model size: small, medium, large (blank for default)")
+	planCmd.Flags().StringVar(&kindFlag, "kind", "", "Kind of work: code, docs, research, ops (default: code)")
+	planCmd.RegisterFlagCompletionFunc("kind", CompleteBallKinds)
 	planCmd.Flags().StringSliceVar(&dependsOnFlag, "depends-on", []string{}, "Ball IDs this ball depends on (can be specified multiple times)")
 	planCmd.Flags().BoolVar(&nonInteractiveFlag, "non-interactive", false, "Skip interactive prompts, use defaults for unspecified fields (headless mode)")
 	planCmd.Flags().BoolVar(&editFlag, "edit", false, "Open $EDITOR with YAML template instead of TUI form")
@@ -99,6 +106,14 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	// Build acceptance criteria list from flags (merge --ac and --criteria)
 	acceptanceCriteria := append(acceptanceCriteriaFlag, criteriaAliasFlag...)
 
+	if !session.ValidateBallKind(kindFlag) {
+		err := fmt.Errorf("invalid kind %q, must be one of: code, docs, research, ops", kindFlag)
+		if planJSONFlag {
+			return printJSONError(err)
+		}
+		return err
+	}
+
 	// Determine which mode to use
 	isTTY := term.IsTerminal(int(os.Stdin.Fd()))
 
@@ -159,6 +174,14 @@ func runPlanTUI(store *session.Store, cwd, intent string, acceptanceCriteria []s
 		return nil
 	}
 
+	// The form has no kind field, so apply --kind to the created ball
+	if kindFlag != "" {
+		result.Ball.SetKind(session.BallKind(kindFlag))
+		if err := store.UpdateBall(result.Ball); err != nil {
+			return fmt.Errorf("failed to set ball kind: %w", err)
+		}
+	}
+
 	// Ensure project is in search paths for discovery
 	_ = session.EnsureProjectInSearchPaths(cwd)
 
@@ -186,7 +209,7 @@ func runPlanTUI(store *session.Store, cwd, intent string, acceptanceCriteria []s
 			Interactive:   true,          // Interactive mode for user involvement
 		}
 
-		_, err := RunAgentLoop(agentConfig)
+		_, err := RunAgentLoop(context.Background(), agentConfig)
 		if err != nil {
 			return fmt.Errorf("agent error: %w", err)
 		}
@@ -210,7 +233,7 @@ func runPlanEditor(store *session.Store, cwd, intent string, acceptanceCriteria
 	}
 
 	// Create YAML template
-	yamlContent := createNewBallYAMLTemplate(intent, contextFlag, priority, tagsFlag, sessionFlag, modelSizeFlag, acceptanceCriteria)
+	yamlContent := createNewBallYAMLTemplate(intent, contextFlag, priority, tagsFlag, sessionFlag, modelSizeFlag, kindFlag, acceptanceCriteria)
 
 	// Run the editor-based creation
 	result, err := runEditorForNewBall(yamlContent)
@@ -338,6 +361,9 @@ func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceC
 		ball.ModelSize = ms
 	}
 
+	// Set kind if provided (validated in runPlan)
+	ball.Kind = session.BallKind(kindFlag)
+
 	// Set dependencies if provided
 	if len(dependsOnFlag) > 0 {
 		resolvedDeps, err := resolveDependencyIDs(store, dependsOnFlag)
@@ -396,7 +422,7 @@ func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceC
 }
 
 // createNewBallYAMLTemplate creates a YAML template for new ball creation
-func createNewBallYAMLTemplate(intent, context, priority string, tags []string, sessionID, modelSize string, acceptanceCriteria []string) string {
+func createNewBallYAMLTemplate(intent, context, priority string, tags []string, sessionID, modelSize, kind string, acceptanceCriteria []string) string {
 	// Add session ID to tags if provided
 	allTags := tags
 	if sessionID != "" {
@@ -428,7 +454,7 @@ func createNewBallYAMLTemplate(intent, context, priority string, tags []string,
 # Close without saving to cancel
 #
 # Required: title
-# Optional: context, priority, tags, acceptance_criteria, model_size, depends_on
+# Optional: context, priority, tags, acceptance_criteria, model_size, kind, depends_on
 
 # Brief title describing what this ball is about (50 chars recommended)
 title: %s
@@ -448,9 +474,12 @@ acceptance_criteria: %s
 # Preferred LLM model size: small, medium, large (or empty for default)
 model_size: %s
 
+# Kind of work: code, docs, research, ops (or empty for code)
+kind: %s
+
 # Ball IDs this ball depends on (must complete before this one)
 depends_on: []
-`, intent, context, priority, tagsYAML, acYAML, modelSize)
+`, intent, context, priority, tagsYAML, acYAML, modelSize, kind)
 }
 
 // editorResult holds the result of running the editor
@@ -522,6 +551,7 @@ type NewBallYAML struct {
 	Tags               []string `yaml:"tags"`
 	AcceptanceCriteria []string `yaml:"acceptance_criteria"`
 	ModelSize          string   `yaml:"model_size"`
+	Kind               string   `yaml:"kind"`
 	DependsOn          []string `yaml:"depends_on"`
 }
 
@@ -590,6 +620,13 @@ func parseNewBallYAML(yamlContent, cwd string, store *session.Store) (*session.B
 		}
 	}
 
+	// Set kind
+	kind := strings.TrimSpace(yamlBall.Kind)
+	if !session.ValidateBallKind(kind) {
+		return nil, fmt.Errorf("invalid kind: %s (must be code, docs, research, ops, or empty)", kind)
+	}
+	ball.Kind = session.BallKind(kind)
+
 	// Store depends_on for later resolution (not resolved here to avoid circular import)
 	ball.DependsOn = yamlBall.DependsOn
 
@@ -634,7 +671,7 @@ func resolveDependencyIDs(store *session.Store, ids []string) ([]string, error)
 		// Use prefix matching
 		matches := session.ResolveBallByPrefix(balls, id)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", session.ErrBallNotFound, id)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
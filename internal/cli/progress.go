@@ -58,7 +58,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 		if sessionID == "" {
 			err := fmt.Errorf("session ID required: provide as first argument or set JUGGLE_SESSION_ID")
 			if progressAppendJSONFlag {
-				return printProgressAppendJSONError(err)
+				return printJSONError(err)
 			}
 			return err
 		}
@@ -69,7 +69,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		err = fmt.Errorf("failed to get current directory: %w", err)
 		if progressAppendJSONFlag {
-			return printProgressAppendJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -78,7 +78,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		err = fmt.Errorf("failed to initialize session store: %w", err)
 		if progressAppendJSONFlag {
-			return printProgressAppendJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -97,7 +97,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 	if err := store.AppendProgress(storageID, entry); err != nil {
 		err = fmt.Errorf("failed to append progress: %w", err)
 		if progressAppendJSONFlag {
-			return printProgressAppendJSONError(err)
+			return printJSONError(err)
 		}
 		return err
 	}
@@ -130,10 +130,3 @@ func printProgressAppendJSONSuccess(sessionID, text, timestamp string) error {
 	fmt.Println(string(data))
 	return nil
 }
-
-func printProgressAppendJSONError(err error) error {
-	errResp := map[string]string{"error": err.Error()}
-	data, _ := json.Marshal(errResp)
-	fmt.Println(string(data))
-	return nil // Return nil so the error is in JSON, not stderr
-}
@@ -71,6 +71,10 @@ func GetWorkingDir() (string, error) {
 
 // GetStoreConfig returns StoreConfig based on global flags
 func GetStoreConfig() session.StoreConfig {
+	// Flags aren't parsed when juggle is embedded as a library
+	if GlobalOpts.JuggleDir == "" {
+		return session.DefaultStoreConfig()
+	}
 	return session.StoreConfig{
 		JuggleDirName: GlobalOpts.JuggleDir,
 	}
@@ -236,6 +240,7 @@ func customHelpFunc(cmd *cobra.Command, args []string) {
 	fmt.Println("  -v, --version          Version for juggle")
 	fmt.Println()
 	fmt.Println("Quickstart: https://github.com/ohare93/juggle?tab=readme-ov-file#quick-start")
+	fmt.Println("Workflow examples: juggle examples")
 	fmt.Println()
 	fmt.Println("Use \"juggle [command] --help\" for more information about a command.")
 }
@@ -283,4 +288,7 @@ func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(supervisorCmd)
 	rootCmd.AddCommand(cronCmd)
+	rootCmd.AddCommand(handoffCmd)
+	rootCmd.AddCommand(examplesCmd)
+	rootCmd.AddCommand(tutorialCmd)
 }
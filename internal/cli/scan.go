@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/ohare93/juggle/internal/scan"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// todoTitleMax caps the length of a ball title taken from a comment
+const todoTitleMax = 80
+
+var (
+	scanTodosSessionID string
+	scanTodosBy        string
+	scanTodosDryRun    bool
+	scanTodosYes       bool
+)
+
+// scanCmd is the parent command for finding work in the codebase
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Find untracked work in the codebase",
+	Long:  `Scan the project's files for latent work and propose balls for it.`,
+}
+
+var scanTodosCmd = &cobra.Command{
+	Use:   "todos [path...]",
+	Short: "Propose balls from TODO and FIXME comments",
+	Long: `Find TODO and FIXME comments in the project and turn them into balls.
+
+Files come from the VCS, so anything .gitignore excludes (and .juggle/) is
+skipped, as are binary files. Give paths to scan only those files or directories.
+
+Comments are grouped by module (the file's directory) or, with --by file, by
+file, and each group is proposed as one ball: the comment text becomes the
+title for a single comment, every comment is listed with its file:line in the
+context, and resolving each one is an acceptance criterion. Groups with a FIXME
+are high priority, the rest medium. Balls are tagged "todo".
+
+Each proposal is shown for you to accept or skip. Comments already listed in
+an existing ball's context are not proposed again.
+
+Examples:
+  juggle scan todos
+  juggle scan todos internal/ --by file
+  juggle scan todos --dry-run
+  juggle scan todos --yes --session cleanup`,
+	RunE: runScanTodos,
+}
+
+func init() {
+	scanTodosCmd.Flags().StringVarP(&scanTodosSessionID, "session", "s", "", "Session ID to tag created balls with")
+	scanTodosCmd.Flags().StringVar(&scanTodosBy, "by", "module", "Group comments into balls by module or file")
+	scanTodosCmd.Flags().BoolVar(&scanTodosDryRun, "dry-run", false, "List the proposed balls without creating them")
+	scanTodosCmd.Flags().BoolVarP(&scanTodosYes, "yes", "y", false, "Create every proposed ball without asking")
+
+	scanCmd.AddCommand(scanTodosCmd)
+	rootCmd.AddCommand(scanCmd)
+}
+
+// todoProposal is a ball proposed for a group of TODO and FIXME comments
+type todoProposal struct {
+	Title    string
+	Context  string
+	Priority session.Priority
+	Criteria []string
+}
+
+func runScanTodos(cmd *cobra.Command, args []string) error {
+	if scanTodosBy != "module" && scanTodosBy != "file" {
+		return fmt.Errorf("--by must be module or file, got %q", scanTodosBy)
+	}
+	if !scanTodosDryRun && !scanTodosYes && !isTerminal(os.Stdin.Fd()) {
+		return fmt.Errorf("proposals need a terminal to accept; use --yes to create them all or --dry-run to list them")
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if scanTodosSessionID != "" {
+		sessionStore, err := session.NewSessionStore(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to create session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(scanTodosSessionID); err != nil {
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, scanTodosSessionID)
+		}
+	}
+
+	files, err := vcsBackendForProject(cwd).ListFiles(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to list project files: %w", err)
+	}
+	todos := scan.FindTodos(cwd, filterScanPaths(files, args))
+	if len(todos) == 0 {
+		fmt.Println("No TODO or FIXME comments found.")
+		return nil
+	}
+
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	existing, err := store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load existing balls: %w", err)
+	}
+	proposals := todoProposals(scan.GroupTodos(todos, scanTodosBy == "module"), existing)
+	fmt.Printf("Found %d TODO/FIXME comment(s); %d ball(s) proposed\n\n", len(todos), len(proposals))
+	if len(proposals) == 0 {
+		fmt.Println("Every comment is already tracked by a ball.")
+		return nil
+	}
+
+	// Accepted balls are written together once review ends, even if it's cut short
+	var created int
+	err = store.Batch(func(store *session.Store) error {
+		for i, proposal := range proposals {
+			printTodoProposal(i+1, len(proposals), proposal)
+			if scanTodosDryRun {
+				continue
+			}
+			if !scanTodosYes {
+				accept, err := ConfirmSingleKey("Create ball?")
+				if err != nil {
+					return err
+				}
+				if !accept {
+					fmt.Println()
+					continue
+				}
+			}
+			ball, err := createTodoBall(store, cwd, proposal, scanTodosSessionID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to create ball for %q: %v\n", proposal.Title, err)
+				continue
+			}
+			created++
+			fmt.Printf("✓ Created %s\n\n", ball.ShortID())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if scanTodosDryRun {
+		fmt.Println("Run without --dry-run to create these balls.")
+		return nil
+	}
+	fmt.Printf("%d of %d ball(s) created\n", created, len(proposals))
+	if created > 0 {
+		_ = session.EnsureProjectInSearchPaths(cwd)
+	}
+	return nil
+}
+
+// filterScanPaths keeps the files under one of the given paths (all files
+// when none are given) and drops paths outside the project
+func filterScanPaths(files, paths []string) []string {
+	var kept []string
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		if strings.HasPrefix(file, "../") {
+			continue
+		}
+		if len(paths) == 0 {
+			kept = append(kept, file)
+			continue
+		}
+		for _, p := range paths {
+			p = strings.TrimSuffix(path.Clean(filepath.ToSlash(p)), "/")
+			if p == "." || file == p || strings.HasPrefix(file, p+"/") {
+				kept = append(kept, file)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// todoProposals turns comment groups into ball proposals, leaving out
+// comments an existing ball already lists and groups left empty by that
+func todoProposals(groups []scan.TodoGroup, existing []*session.Ball) []todoProposal {
+	var proposals []todoProposal
+	for _, group := range groups {
+		var todos []scan.TodoComment
+		for _, todo := range group.Todos {
+			if !todoTracked(todo, existing) {
+				todos = append(todos, todo)
+			}
+		}
+		if len(todos) == 0 {
+			continue
+		}
+
+		proposal := todoProposal{
+			Title:    todoGroupTitle(group.Key, todos),
+			Priority: session.PriorityMedium,
+		}
+		var context strings.Builder
+		context.WriteString("Found by juggle scan todos:\n")
+		for _, todo := range todos {
+			fmt.Fprintf(&context, "- %s", todoReference(todo))
+			if todo.Text != "" {
+				context.WriteString(": " + todo.Text)
+			}
+			context.WriteString("\n")
+			if todo.Tag == "FIXME" {
+				proposal.Priority = session.PriorityHigh
+			}
+			proposal.Criteria = append(proposal.Criteria, fmt.Sprintf("%s at %s:%d is resolved and the comment removed", todo.Tag, todo.Path, todo.Line))
+		}
+		proposal.Context = strings.TrimSuffix(context.String(), "\n")
+		proposals = append(proposals, proposal)
+	}
+	return proposals
+}
+
+// todoReference identifies a comment in a ball's context, e.g. "main.go:12 TODO"
+func todoReference(todo scan.TodoComment) string {
+	return fmt.Sprintf("%s:%d %s", todo.Path, todo.Line, todo.Tag)
+}
+
+// todoTracked reports whether an existing ball's context already lists the comment
+func todoTracked(todo scan.TodoComment, existing []*session.Ball) bool {
+	entry := "- " + todoReference(todo)
+	for _, ball := range existing {
+		if strings.Contains(ball.Context, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// todoGroupTitle titles a proposal: the comment itself when the group has
+// one, otherwise a count of the comments in the file or module
+func todoGroupTitle(key string, todos []scan.TodoComment) string {
+	if len(todos) > 1 {
+		return fmt.Sprintf("Resolve %d TODO/FIXME comments in %s", len(todos), key)
+	}
+	todo := todos[0]
+	if todo.Text == "" {
+		return fmt.Sprintf("Resolve %s in %s:%d", todo.Tag, todo.Path, todo.Line)
+	}
+	title := []rune(todo.Text)
+	title[0] = unicode.ToUpper(title[0])
+	if len(title) > todoTitleMax {
+		title = append(title[:todoTitleMax-3], []rune("...")...)
+	}
+	return fmt.Sprintf("%s (%s)", string(title), path.Base(todo.Path))
+}
+
+// printTodoProposal shows a proposed ball for review
+func printTodoProposal(n, total int, proposal todoProposal) {
+	fmt.Printf("%s %s\n", StyleDim.Render(fmt.Sprintf("[%d/%d]", n, total)), proposal.Title)
+	fmt.Printf("  Priority: %s\n", proposal.Priority)
+	for _, line := range strings.Split(proposal.Context, "\n")[1:] {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
+// createTodoBall saves a proposal as a pending ball tagged "todo"
+func createTodoBall(store *session.Store, projectDir string, proposal todoProposal, sessionID string) (*session.Ball, error) {
+	ball, err := session.NewBall(projectDir, proposal.Title, proposal.Priority)
+	if err != nil {
+		return nil, err
+	}
+	ball.State = session.StatePending
+	ball.Context = proposal.Context
+	ball.SetAcceptanceCriteria(proposal.Criteria)
+	ball.AddTag("todo")
+	if sessionID != "" {
+		ball.AddTag(sessionID)
+	}
+	if err := store.AppendBall(ball); err != nil {
+		return nil, err
+	}
+	return ball, nil
+}
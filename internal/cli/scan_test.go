@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/scan"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestTodoProposals(t *testing.T) {
+	groups := []scan.TodoGroup{
+		{Key: "internal/db", Todos: []scan.TodoComment{
+			{Path: "internal/db/db.go", Line: 12, Tag: "TODO", Text: "retry on deadlock"},
+			{Path: "internal/db/pool.go", Line: 40, Tag: "FIXME", Text: "leaks connections"},
+		}},
+		{Key: "cmd", Todos: []scan.TodoComment{
+			{Path: "cmd/main.go", Line: 3, Tag: "TODO", Text: "handle the timeout"},
+		}},
+		{Key: "web", Todos: []scan.TodoComment{
+			{Path: "web/app.js", Line: 7, Tag: "TODO"},
+		}},
+		{Key: "lib", Todos: []scan.TodoComment{
+			{Path: "lib/cache.go", Line: 5, Tag: "TODO", Text: "evict old entries"},
+		}},
+	}
+	existing := []*session.Ball{{Context: "Found by juggle scan todos:\n- lib/cache.go:5 TODO: evict old entries"}}
+
+	proposals := todoProposals(groups, existing)
+	if len(proposals) != 3 {
+		t.Fatalf("expected the tracked comment's group to be left out, got %d proposals", len(proposals))
+	}
+
+	db := proposals[0]
+	if db.Title != "Resolve 2 TODO/FIXME comments in internal/db" || db.Priority != session.PriorityHigh {
+		t.Errorf("unexpected module proposal: %q (%s)", db.Title, db.Priority)
+	}
+	if !strings.Contains(db.Context, "- internal/db/db.go:12 TODO: retry on deadlock") ||
+		!strings.Contains(db.Context, "- internal/db/pool.go:40 FIXME: leaks connections") {
+		t.Errorf("expected file:line references in context, got:\n%s", db.Context)
+	}
+	if len(db.Criteria) != 2 || db.Criteria[1] != "FIXME at internal/db/pool.go:40 is resolved and the comment removed" {
+		t.Errorf("unexpected criteria: %v", db.Criteria)
+	}
+
+	if proposals[1].Title != "Handle the timeout (main.go)" || proposals[1].Priority != session.PriorityMedium {
+		t.Errorf("unexpected single-comment proposal: %q (%s)", proposals[1].Title, proposals[1].Priority)
+	}
+	if proposals[2].Title != "Resolve TODO in web/app.js:7" {
+		t.Errorf("unexpected title for a comment without text: %q", proposals[2].Title)
+	}
+
+	// A created ball's context marks its comments as tracked
+	again := todoProposals(groups[1:2], []*session.Ball{{Context: proposals[1].Context}})
+	if len(again) != 0 {
+		t.Errorf("expected no proposals for comments already in a ball, got %+v", again)
+	}
+}
+
+func TestFilterScanPaths(t *testing.T) {
+	files := []string{"main.go", "internal/db/db.go", "internal/dbx/x.go", "../outside.go"}
+
+	if got := filterScanPaths(files, nil); !reflect.DeepEqual(got, files[:3]) {
+		t.Errorf("expected every file inside the project, got %v", got)
+	}
+	if got := filterScanPaths(files, []string{"internal/db/"}); !reflect.DeepEqual(got, []string{"internal/db/db.go"}) {
+		t.Errorf("expected only internal/db, got %v", got)
+	}
+	if got := filterScanPaths(files, []string{"main.go", "."}); len(got) != 3 {
+		t.Errorf("expected . to keep every file, got %v", got)
+	}
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// selectorWorkers caps how many projects the session selector loads at once
+const selectorWorkers = 8
+
+// selectorSpinnerFrames are the frames of the selector's loading spinner
+var selectorSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// selectorProject is one project's sessions, loaded for the session selector
+type selectorProject struct {
+	index    int
+	dir      string
+	sessions []SessionInfo
+	err      error
+}
+
+// loadProjectSessions lists a project's sessions with their ball counts. The
+// project's balls are loaded once and counted per session tag.
+func loadProjectSessions(sessionStore *session.SessionStore, projectDir string) ([]SessionInfo, error) {
+	projSessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	if len(projSessions) == 0 {
+		return nil, nil
+	}
+
+	balls, _ := session.LoadAllBalls([]string{projectDir})
+	counts := make(map[string]int)
+	for _, ball := range balls {
+		for _, tag := range ball.Tags {
+			counts[tag]++
+		}
+	}
+
+	sessions := make([]SessionInfo, 0, len(projSessions))
+	for _, s := range projSessions {
+		sessions = append(sessions, SessionInfo{
+			ID:          s.ID,
+			Description: s.Description,
+			ProjectDir:  projectDir,
+			BallCount:   counts[s.ID],
+		})
+	}
+	return sessions, nil
+}
+
+// loadSelectorProjects loads the sessions of each project concurrently. It
+// calls onProject once per project, in project order, as soon as that project
+// and every one before it are loaded, so results can be shown as they arrive.
+// Loading stops early, returning ctx's error, when ctx is cancelled.
+func loadSelectorProjects(ctx context.Context, projects []string, onProject func(p selectorProject)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	results := make(chan selectorProject)
+	var wg sync.WaitGroup
+	for w := 0; w < min(selectorWorkers, len(projects)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				p := selectorProject{index: i, dir: projects[i]}
+				sessionStore, err := session.NewSessionStore(projects[i])
+				if err != nil {
+					p.err = fmt.Errorf("failed to create session store for %s: %w", projects[i], err)
+				} else if p.sessions, err = loadProjectSessions(sessionStore, projects[i]); err != nil {
+					p.err = fmt.Errorf("failed to list sessions for %s: %w", projects[i], err)
+				}
+				select {
+				case results <- p:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := range projects {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Hold back projects that finish early until those before them are in
+	pending := make(map[int]selectorProject)
+	next := 0
+	for next < len(projects) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case p, ok := <-results:
+			if !ok {
+				return ctx.Err()
+			}
+			pending[p.index] = p
+			for {
+				ready, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				onProject(ready)
+				next++
+			}
+		}
+	}
+	return nil
+}
+
+// selectorSpinner draws a one-line loading spinner below the selector's
+// output. Lines printed through it are written above the spinner. A nil
+// spinner prints lines without drawing anything, for non-terminal output.
+type selectorSpinner struct {
+	mu    sync.Mutex
+	w     io.Writer
+	label string
+	frame int
+	drawn bool
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// startSelectorSpinner starts a spinner on w showing label
+func startSelectorSpinner(w io.Writer, label string) *selectorSpinner {
+	s := &selectorSpinner{w: w, label: label, done: make(chan struct{})}
+	s.draw()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.mu.Lock()
+				s.frame++
+				s.draw()
+				s.mu.Unlock()
+			}
+		}
+	}()
+	return s
+}
+
+// draw redraws the spinner line. Callers hold mu, except on start.
+func (s *selectorSpinner) draw() {
+	frame := selectorSpinnerFrames[s.frame%len(selectorSpinnerFrames)]
+	fmt.Fprintf(s.w, "\r\033[K%s %s", frame, s.label)
+	s.drawn = true
+}
+
+// erase clears the spinner line so the cursor is back at its start
+func (s *selectorSpinner) erase() {
+	if s.drawn {
+		fmt.Fprint(s.w, "\r\033[K")
+		s.drawn = false
+	}
+}
+
+// setLabel changes the text shown next to the spinner
+func (s *selectorSpinner) setLabel(label string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.label = label
+	s.draw()
+}
+
+// printf writes a line above the spinner
+func (s *selectorSpinner) printf(w io.Writer, format string, args ...any) {
+	if s == nil {
+		fmt.Fprintf(w, format, args...)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.erase()
+	fmt.Fprintf(w, format, args...)
+	s.draw()
+}
+
+// stop stops the spinner and clears its line, leaving the terminal as it was
+func (s *selectorSpinner) stop() {
+	if s == nil {
+		return
+	}
+	close(s.done)
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.erase()
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestLoadSelectorProjects(t *testing.T) {
+	var projects []string
+	for i := 0; i < selectorWorkers*2; i++ {
+		dir := t.TempDir()
+		sessionStore, err := session.NewSessionStore(dir)
+		if err != nil {
+			t.Fatalf("Failed to create session store: %v", err)
+		}
+		id := "session-" + filepath.Base(dir)
+		if _, err := sessionStore.CreateSession(id, ""); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		store, err := session.NewStore(dir)
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		ball, err := session.NewBall(dir, "Counted ball", session.PriorityMedium)
+		if err != nil {
+			t.Fatalf("Failed to create ball: %v", err)
+		}
+		ball.Tags = []string{id}
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to save ball: %v", err)
+		}
+		projects = append(projects, dir)
+	}
+
+	var got []selectorProject
+	err := loadSelectorProjects(context.Background(), projects, func(p selectorProject) {
+		got = append(got, p)
+	})
+	if err != nil {
+		t.Fatalf("loadSelectorProjects() error = %v", err)
+	}
+	if len(got) != len(projects) {
+		t.Fatalf("Expected %d projects, got %d", len(projects), len(got))
+	}
+	for i, p := range got {
+		if p.index != i || p.dir != projects[i] {
+			t.Errorf("Expected project %d to be %s, got %d %s", i, projects[i], p.index, p.dir)
+		}
+		if p.err != nil || len(p.sessions) != 1 || p.sessions[0].BallCount != 1 {
+			t.Errorf("Expected one session with one ball in %s, got %+v (err %v)", p.dir, p.sessions, p.err)
+		}
+	}
+}
+
+func TestLoadSelectorProjects_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	projects := []string{t.TempDir(), t.TempDir()}
+	err := loadSelectorProjects(ctx, projects, func(selectorProject) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSelectorSpinner(t *testing.T) {
+	var out bytes.Buffer
+	spinner := startSelectorSpinner(&out, "Loading...")
+	spinner.printf(&out, "  1. first\n")
+	spinner.stop()
+
+	got := out.String()
+	if !strings.Contains(got, "Loading...") || !strings.Contains(got, "\r\033[K  1. first\n") {
+		t.Errorf("Expected the line printed above the spinner, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("Expected stop to clear the spinner line, got %q", got)
+	}
+
+	// A nil spinner just prints
+	var plain bytes.Buffer
+	var none *selectorSpinner
+	none.printf(&plain, "  %d. %s\n", 2, "second")
+	none.setLabel("ignored")
+	none.stop()
+	if plain.String() != "  2. second\n" {
+		t.Errorf("Expected plain output from a nil spinner, got %q", plain.String())
+	}
+}
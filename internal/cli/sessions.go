@@ -139,7 +139,11 @@ Examples:
   juggle sessions edit my-session                    # Open in editor
   juggle sessions edit my-session -m "New description"
   juggle sessions edit my-session --ac "AC1" --ac "AC2"
-  juggle sessions edit my-session --default-model medium`,
+  juggle sessions edit my-session --default-model medium
+  juggle sessions edit my-session --env API_URL=http://localhost:8080
+  juggle sessions edit my-session --setup "docker compose up -d db" --teardown "docker compose down"
+  juggle sessions edit my-session --system-prompt "{{.Default}} Only touch the docs/ directory."
+  juggle sessions edit my-session --context-docs README.md --context-docs ARCHITECTURE.md`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSessionsEdit,
 }
@@ -152,6 +156,11 @@ var (
 	sessionEditDefaultModelFlag  string
 	sessionEditACAppendFlag      []string
 	sessionEditACRemoveFlag      []string
+	sessionEditEnvFlag           []string
+	sessionEditSetupFlag         []string
+	sessionEditTeardownFlag      []string
+	sessionEditSystemPromptFlag  string
+	sessionEditContextDocsFlag   []string
 )
 
 func init() {
@@ -178,6 +187,11 @@ func init() {
 	sessionsEditCmd.Flags().StringSliceVar(&sessionEditACAppendFlag, "ac-append", []string{}, "Append acceptance criteria (can be specified multiple times)")
 	sessionsEditCmd.Flags().StringSliceVar(&sessionEditACRemoveFlag, "ac-remove", []string{}, "Remove acceptance criteria by text (can be specified multiple times)")
 	sessionsEditCmd.Flags().StringVar(&sessionEditDefaultModelFlag, "default-model", "", "Set default model size (small|medium|large)")
+	sessionsEditCmd.Flags().StringArrayVar(&sessionEditEnvFlag, "env", nil, "Set agent environment variable KEY=VALUE (KEY= removes it, can be specified multiple times)")
+	sessionsEditCmd.Flags().StringArrayVar(&sessionEditSetupFlag, "setup", nil, "Replace setup commands run before the agent loop (can be specified multiple times, \"\" clears)")
+	sessionsEditCmd.Flags().StringArrayVar(&sessionEditTeardownFlag, "teardown", nil, "Replace teardown commands run after the agent loop (can be specified multiple times, \"\" clears)")
+	sessionsEditCmd.Flags().StringVar(&sessionEditSystemPromptFlag, "system-prompt", "", "Set the agent system prompt template for this session (\"\" clears)")
+	sessionsEditCmd.Flags().StringArrayVar(&sessionEditContextDocsFlag, "context-docs", nil, "Replace the project docs summarized into the agent prompt (can be specified multiple times, \"\" clears)")
 
 	// Add subcommands
 	sessionsCmd.AddCommand(sessionsCreateCmd)
@@ -336,10 +350,7 @@ func runSessionsList(cmd *cobra.Command, args []string) error {
 	if sessionsListJSONFlag {
 		data, err := json.MarshalIndent(sessions, "", "  ")
 		if err != nil {
-			errResp := map[string]string{"error": err.Error()}
-			errData, _ := json.Marshal(errResp)
-			fmt.Println(string(errData))
-			return err
+			return printJSONError(err)
 		}
 		fmt.Println(string(data))
 		return nil
@@ -446,10 +457,7 @@ func runSessionsShow(cmd *cobra.Command, args []string) error {
 		}
 		data, err := json.MarshalIndent(response, "", "  ")
 		if err != nil {
-			errResp := map[string]string{"error": err.Error()}
-			errData, _ := json.Marshal(errResp)
-			fmt.Println(string(errData))
-			return err
+			return printJSONError(err)
 		}
 		fmt.Println(string(data))
 		return nil
@@ -493,6 +501,18 @@ func runSessionsShow(cmd *cobra.Command, args []string) error {
 		fmt.Println("  (no context set)")
 	}
 
+	// Context docs section, only when the session has a docs briefing
+	if len(sess.ContextDocs) > 0 {
+		fmt.Println()
+		fmt.Println(labelStyle.Render("Context Docs:"))
+		for _, doc := range sess.ContextDocs {
+			fmt.Printf("  - %s\n", doc)
+		}
+		if sess.DocsBriefing != nil {
+			fmt.Printf("  Briefing refreshed %s\n", sess.DocsBriefing.Refreshed.Format(time.RFC3339))
+		}
+	}
+
 	// Balls section
 	fmt.Println()
 	fmt.Printf("%s (%d)\n", labelStyle.Render("Balls:"), len(sessionBalls))
@@ -710,7 +730,7 @@ func runSessionsDelete(cmd *cobra.Command, args []string) error {
 
 	// Verify session exists
 	if _, err := store.LoadSession(id); err != nil {
-		return fmt.Errorf("session not found: %s", id)
+		return fmt.Errorf("%w: %s", session.ErrSessionNotFound, id)
 	}
 
 	// Confirm deletion (skip with --yes flag)
@@ -748,7 +768,7 @@ func runSessionsProgress(cmd *cobra.Command, args []string) error {
 
 	// Verify session exists
 	if _, err := store.LoadSession(id); err != nil {
-		return fmt.Errorf("session not found: %s", id)
+		return fmt.Errorf("%w: %s", session.ErrSessionNotFound, id)
 	}
 
 	// Load progress
@@ -783,7 +803,7 @@ func runSessionsProgressClear(cmd *cobra.Command, args []string) error {
 	// Verify session exists (skip for _all virtual session)
 	if id != "_all" && id != "all" {
 		if _, err := store.LoadSession(id); err != nil {
-			return fmt.Errorf("session not found: %s", id)
+			return fmt.Errorf("%w: %s", session.ErrSessionNotFound, id)
 		}
 	}
 
@@ -829,7 +849,7 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 	// Load session to verify it exists
 	sess, err := store.LoadSession(id)
 	if err != nil {
-		return fmt.Errorf("session not found: %s", id)
+		return fmt.Errorf("%w: %s", session.ErrSessionNotFound, id)
 	}
 
 	// Check if any flags are provided
@@ -838,7 +858,12 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 		len(sessionEditACFlag) > 0 ||
 		len(sessionEditACAppendFlag) > 0 ||
 		len(sessionEditACRemoveFlag) > 0 ||
-		sessionEditDefaultModelFlag != ""
+		sessionEditDefaultModelFlag != "" ||
+		sessionEditEnvFlag != nil ||
+		sessionEditSetupFlag != nil ||
+		sessionEditTeardownFlag != nil ||
+		cmd.Flags().Changed("system-prompt") ||
+		sessionEditContextDocsFlag != nil
 
 	// If no flags provided, open in editor
 	if !hasFlags {
@@ -928,6 +953,64 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 		modified = true
 	}
 
+	for _, assignment := range sessionEditEnvFlag {
+		key, value, err := session.ParseEnvAssignment(assignment)
+		if err != nil {
+			return err
+		}
+		if err := store.UpdateSessionEnvVar(id, key, value); err != nil {
+			return fmt.Errorf("failed to update env: %w", err)
+		}
+		if value == "" {
+			fmt.Printf("✓ Removed env: %s\n", key)
+		} else {
+			fmt.Printf("✓ Set env: %s\n", key)
+		}
+		modified = true
+	}
+
+	if sessionEditSetupFlag != nil {
+		if err := store.UpdateSessionSetup(id, sessionEditSetupFlag); err != nil {
+			return fmt.Errorf("failed to update setup commands: %w", err)
+		}
+		fmt.Printf("✓ Updated setup commands\n")
+		modified = true
+	}
+
+	if sessionEditTeardownFlag != nil {
+		if err := store.UpdateSessionTeardown(id, sessionEditTeardownFlag); err != nil {
+			return fmt.Errorf("failed to update teardown commands: %w", err)
+		}
+		fmt.Printf("✓ Updated teardown commands\n")
+		modified = true
+	}
+
+	if cmd.Flags().Changed("system-prompt") {
+		if err := validateSystemPrompt(sessionEditSystemPromptFlag); err != nil {
+			return err
+		}
+		if err := store.UpdateSessionSystemPrompt(id, sessionEditSystemPromptFlag); err != nil {
+			return fmt.Errorf("failed to update system prompt: %w", err)
+		}
+		if sessionEditSystemPromptFlag == "" {
+			fmt.Printf("✓ Cleared system prompt\n")
+		} else {
+			fmt.Printf("✓ Updated system prompt\n")
+		}
+		modified = true
+	}
+
+	if sessionEditContextDocsFlag != nil {
+		if err := store.UpdateSessionContextDocs(id, sessionEditContextDocsFlag); err != nil {
+			return fmt.Errorf("failed to update context docs: %w", err)
+		}
+		if _, err := store.RefreshDocsBriefing(id); err != nil {
+			return fmt.Errorf("failed to build docs briefing: %w", err)
+		}
+		fmt.Printf("✓ Updated context docs\n")
+		modified = true
+	}
+
 	if modified {
 		fmt.Printf("\n✓ Session %s updated successfully\n", id)
 	}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,25 +12,47 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var showJSONFlag bool
+var (
+	showJSONFlag     bool
+	showArchivedFlag bool
+)
 
 var showCmd = &cobra.Command{
 	Use:   "show <session-id>",
 	Short: "Show detailed information about a session",
-	Long:  `Display detailed information about a specific session.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runShow,
+	Long: `Display detailed information about a specific ball or session.
+
+Ball IDs can be a short ID prefix, a full ID, or project-qualified
+(<project>:<id>) to pick a ball in another project with --all. Archived balls
+are found when no active ball matches; --archived searches both at once.
+
+Examples:
+  juggle show a1b2
+  juggle show api:a1b2 --all
+  juggle show a1b2 --archived   # Also search archived balls`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
 }
 
 func init() {
 	showCmd.Flags().BoolVar(&showJSONFlag, "json", false, "Output as JSON")
+	showCmd.Flags().BoolVar(&showArchivedFlag, "archived", false, "Also search archived balls")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
 	id := args[0]
 
 	// Try to find a ball first
-	foundBall, _, err := findBallByID(id)
+	foundBall, _, err := findBallByIDWithArchive(id, showArchivedFlag)
+	var ambiguous *session.AmbiguousIDError
+	if err != nil && !showArchivedFlag && !errors.As(err, &ambiguous) {
+		// Fall back to archived balls so done work can still be looked up
+		if archived, _, archiveErr := findArchivedBallByID(id); archiveErr == nil {
+			foundBall, err = archived, nil
+		} else if errors.As(archiveErr, &ambiguous) {
+			err = archiveErr
+		}
+	}
 	if err == nil {
 		if showJSONFlag {
 			return printBallJSON(foundBall)
@@ -37,6 +60,12 @@ func runShow(cmd *cobra.Command, args []string) error {
 		renderBallDetails(foundBall)
 		return nil
 	}
+	if errors.As(err, &ambiguous) {
+		if showJSONFlag {
+			return printJSONError(err)
+		}
+		return err
+	}
 
 	// Ball not found, try to find a session
 	cwd, cwdErr := GetWorkingDir()
@@ -117,17 +146,6 @@ func printBallJSON(ball *session.Ball) error {
 	return nil
 }
 
-// printJSONError outputs an error in JSON format to stdout
-// Returns nil to prevent cobra from printing the error again to stderr
-// Note: This means exit code will be 0 even on errors when using --json
-// Callers can check the JSON "error" field to detect failures
-func printJSONError(err error) error {
-	errResp := map[string]string{"error": err.Error()}
-	data, _ := json.Marshal(errResp)
-	fmt.Println(string(data))
-	return nil
-}
-
 func renderBallDetails(ball *session.Ball) {
 	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
 	valueStyle := lipgloss.NewStyle()
@@ -140,14 +158,28 @@ func renderBallDetails(ball *session.Ball) {
 	fmt.Println(labelStyle.Render("Title:"), valueStyle.Render(ball.Title))
 	fmt.Println(labelStyle.Render("Priority:"), valueStyle.Render(string(ball.Priority)))
 	fmt.Println(labelStyle.Render("State:"), valueStyle.Render(string(ball.State)))
+	if ball.Kind != session.BallKindBlank {
+		fmt.Println(labelStyle.Render("Kind:"), valueStyle.Render(string(ball.Kind)))
+	}
 
 	if ball.BlockedReason != "" {
 		fmt.Println(labelStyle.Render("Blocked:"), valueStyle.Render(ball.BlockedReason))
 	}
 
+	if claim := ball.ActiveClaim(); claim != nil {
+		claimInfo := fmt.Sprintf("%s until %s", claim.By, claim.ExpiresAt.Format("2006-01-02 15:04"))
+		if claim.Note != "" {
+			claimInfo += " (" + claim.Note + ")"
+		}
+		fmt.Println(labelStyle.Render("Claimed:"), valueStyle.Render(claimInfo))
+	}
+
 	fmt.Println(labelStyle.Render("Started:"), valueStyle.Render(ball.StartedAt.Format("2006-01-02 15:04:05")))
 	fmt.Println(labelStyle.Render("Last Activity:"), valueStyle.Render(ball.LastActivity.Format("2006-01-02 15:04:05")))
 	fmt.Println(labelStyle.Render("Updates:"), valueStyle.Render(fmt.Sprintf("%d", ball.UpdateCount)))
+	if ball.CompletedAt != nil {
+		fmt.Println(labelStyle.Render("Completed:"), valueStyle.Render(ball.CompletedAt.Format("2006-01-02 15:04:05")))
+	}
 
 	if len(ball.Tags) > 0 {
 		fmt.Println(labelStyle.Render("Tags:"), valueStyle.Render(strings.Join(ball.Tags, ", ")))
@@ -157,6 +189,32 @@ func renderBallDetails(ball *session.Ball) {
 		fmt.Println(labelStyle.Render("Depends On:"), valueStyle.Render(strings.Join(ball.DependsOn, ", ")))
 	}
 
+	if ball.Epic {
+		fmt.Println(labelStyle.Render("Epic:"), valueStyle.Render("split into the balls it depends on"))
+	}
+	if ball.Parent != "" {
+		fmt.Println(labelStyle.Render("Parent:"), valueStyle.Render(ball.Parent))
+	}
+
+	if ball.Estimate != nil {
+		fmt.Println(labelStyle.Render("Estimate:"), valueStyle.Render(formatEstimate(ball.Estimate)))
+	}
+	if ball.Hotspot != nil {
+		fmt.Println(labelStyle.Render("Hotspot:"), valueStyle.Render(fmt.Sprintf("score %d: %s", ball.Hotspot.Score, formatHotspot(ball.Hotspot))))
+	}
+	if ball.AttemptCount > 0 {
+		fmt.Println(labelStyle.Render("Attempts:"), valueStyle.Render(fmt.Sprintf("%d agent iteration(s)", ball.AttemptCount)))
+	}
+	for _, bump := range ball.Aged {
+		fmt.Println(labelStyle.Render("Aged:"), valueStyle.Render(bump.String()))
+	}
+	if ball.TimeoutMinutes > 0 {
+		fmt.Println(labelStyle.Render("Timeout:"), valueStyle.Render(formatBallTimeout(ball.Timeout())+" per iteration"))
+	}
+	if ball.EscalatedModel != "" {
+		fmt.Println(labelStyle.Render("Escalated:"), valueStyle.Render(fmt.Sprintf("to %s after repeated failures", ball.EscalatedModel)))
+	}
+
 	if len(ball.AcceptanceCriteria) > 0 {
 		fmt.Printf("\n%s\n", labelStyle.Render("Acceptance Criteria:"))
 		for i, ac := range ball.AcceptanceCriteria {
@@ -164,6 +222,15 @@ func renderBallDetails(ball *session.Ball) {
 		}
 	}
 
+	if len(ball.Plan) > 0 {
+		fmt.Printf("\n%s\n", labelStyle.Render("Plan:"))
+		for i, step := range ball.Plan {
+			fmt.Printf("  %d. %s\n", i+1, step)
+		}
+	}
+
+	renderAgentEnvironment(labelStyle, ball.Env, ball.Setup, ball.Teardown)
+
 	if ball.CompletionNote != "" {
 		fmt.Println(labelStyle.Render("\nCompletion Note:"), valueStyle.Render(ball.CompletionNote))
 	}
@@ -211,6 +278,8 @@ func renderSessionDetails(sess *session.JuggleSession, balls []*session.Ball, pr
 		fmt.Println("  (no context set)")
 	}
 
+	renderAgentEnvironment(labelStyle, sess.Env, sess.Setup, sess.Teardown)
+
 	// Balls section
 	fmt.Println()
 	fmt.Printf("%s (%d)\n", labelStyle.Render("Balls:"), len(balls))
@@ -245,3 +314,25 @@ func renderSessionDetails(sess *session.JuggleSession, balls []*session.Ball, pr
 		fmt.Println("  (no progress logged)")
 	}
 }
+
+// renderAgentEnvironment prints env vars and setup/teardown commands, if any are set
+func renderAgentEnvironment(labelStyle lipgloss.Style, env map[string]string, setup, teardown []string) {
+	if len(env) > 0 {
+		fmt.Printf("\n%s\n", labelStyle.Render("Environment:"))
+		for _, entry := range session.EnvList(env) {
+			fmt.Printf("  %s\n", entry)
+		}
+	}
+	if len(setup) > 0 {
+		fmt.Printf("\n%s\n", labelStyle.Render("Setup:"))
+		for _, command := range setup {
+			fmt.Printf("  $ %s\n", command)
+		}
+	}
+	if len(teardown) > 0 {
+		fmt.Printf("\n%s\n", labelStyle.Render("Teardown:"))
+		for _, command := range teardown {
+			fmt.Printf("  $ %s\n", command)
+		}
+	}
+}
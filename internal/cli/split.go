@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <ball-id>",
+	Short: "Split a ball into child balls read from stdin",
+	Long: `Create child balls from a <split> block on stdin and mark the ball as an
+epic that depends on them. Children inherit the ball's sessions, kind, model
+size and priority unless they give their own. Agents only come back to the
+epic, to check its own acceptance criteria, once every child is done.
+
+Agent runs apply <split> blocks in their output automatically; this command
+lets interactive sessions, such as agent refine, do the same.
+
+Each child is a <child> element with a title, an optional priority, and its
+acceptance criteria one per line:
+
+  <split ball="a1b2">
+  <child title="Add the schema migration" priority="high">
+  - Migration adds the sessions.archived column
+  - Migration rolls back cleanly
+  </child>
+  <child title="Archive sessions from the CLI">
+  - juggle sessions archive <id> sets archived
+  </child>
+  </split>
+
+Examples:
+  juggle split a1b2 < split.txt`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runSplit,
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+}
+
+// splitBlockPattern matches one ball's split in an agent's output
+var splitBlockPattern = regexp.MustCompile(`(?s)<split ball="([^"]+)">(.*?)</split>`)
+
+// splitChildPattern matches one child ball within a split
+var splitChildPattern = regexp.MustCompile(`(?s)<child\s*([^>]*)>(.*?)</child>`)
+
+// splitAttrPattern matches a child's title="..." and priority="..." attributes
+var splitAttrPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// ballSplit is a split proposed by an agent: the ball to split and its children
+type ballSplit struct {
+	BallID   string
+	Children []splitChild
+}
+
+// splitChild is one child ball of a proposed split
+type splitChild struct {
+	Title    string
+	Priority string
+	ACs      []string
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	input, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return fmt.Errorf("failed to read split from stdin: %w", err)
+	}
+	children := parseSplitChildren(string(input))
+	if splits := parseSplits(string(input)); len(splits) > 0 {
+		if len(splits) > 1 {
+			return fmt.Errorf("stdin has %d <split> blocks, expected one", len(splits))
+		}
+		if id := splits[0].BallID; id != ball.ID && id != ball.ShortID() {
+			return fmt.Errorf("split block is for ball %s, not %s", id, ball.ShortID())
+		}
+		children = splits[0].Children
+	}
+
+	created, err := applySplit(ball, store, children)
+	if err != nil {
+		return err
+	}
+	printSplit(ball, created)
+	return nil
+}
+
+// parseSplits extracts the splits an agent proposed from its output
+func parseSplits(output string) []ballSplit {
+	var splits []ballSplit
+	for _, match := range splitBlockPattern.FindAllStringSubmatch(output, -1) {
+		splits = append(splits, ballSplit{
+			BallID:   strings.TrimSpace(match[1]),
+			Children: parseSplitChildren(match[2]),
+		})
+	}
+	return splits
+}
+
+// parseSplitChildren extracts the <child> elements of a split
+func parseSplitChildren(block string) []splitChild {
+	var children []splitChild
+	for _, match := range splitChildPattern.FindAllStringSubmatch(block, -1) {
+		var child splitChild
+		for _, attr := range splitAttrPattern.FindAllStringSubmatch(match[1], -1) {
+			switch attr[1] {
+			case "title":
+				child.Title = strings.TrimSpace(attr[2])
+			case "priority":
+				child.Priority = strings.TrimSpace(attr[2])
+			}
+		}
+		for _, line := range strings.Split(match[2], "\n") {
+			ac := strings.TrimSpace(planStepPrefix.ReplaceAllString(strings.TrimSpace(line), ""))
+			if ac != "" {
+				child.ACs = append(child.ACs, ac)
+			}
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+// applySplit creates parent's children in store and marks parent as an epic
+// depending on them. Every child is checked before anything is written.
+func applySplit(parent *session.Ball, store *session.Store, children []splitChild) ([]*session.Ball, error) {
+	if parent.State == session.StateComplete || parent.State == session.StateResearched {
+		return nil, fmt.Errorf("ball %s is %s, only open balls can be split", parent.ShortID(), parent.State)
+	}
+	if parent.Epic {
+		return nil, fmt.Errorf("ball %s is already split", parent.ShortID())
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("no <child> balls to split %s into", parent.ShortID())
+	}
+
+	created := make([]*session.Ball, 0, len(children))
+	for i, child := range children {
+		if child.Title == "" {
+			return nil, fmt.Errorf("child %d of %s has no title", i+1, parent.ShortID())
+		}
+		if child.Priority != "" && !session.ValidatePriority(child.Priority) {
+			return nil, fmt.Errorf("child %q has invalid priority %q", child.Title, child.Priority)
+		}
+		ball, err := session.NewChildBall(parent, child.Title, session.Priority(child.Priority))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create child %q: %w", child.Title, err)
+		}
+		ball.SetAcceptanceCriteria(child.ACs)
+		created = append(created, ball)
+	}
+
+	// Write the children and the epic together, rewriting balls.jsonl once, so
+	// the epic never waits on balls that don't exist
+	ids := make([]string, len(created))
+	for i, ball := range created {
+		ids[i] = ball.ID
+	}
+	err := store.Batch(func(store *session.Store) error {
+		for _, ball := range created {
+			if err := store.AppendBall(ball); err != nil {
+				return fmt.Errorf("failed to save child %q: %w", ball.Title, err)
+			}
+		}
+		parent.MarkEpic(ids)
+		if err := store.UpdateBall(parent); err != nil {
+			return fmt.Errorf("failed to update ball: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logToBallSessions(parent, fmt.Sprintf("[SPLIT] %s split into %s", parent.ID, strings.Join(ids, ", ")))
+	return created, nil
+}
+
+// applyAgentSplits applies the <split> blocks in an iteration's output to the
+// session's balls. Splits are best-effort: a bad one is reported and skipped.
+func applyAgentSplits(config AgentLoopConfig, output string) {
+	splits := parseSplits(output)
+	if len(splits) == 0 {
+		return
+	}
+	balls, err := loadBallsForModelSelection(config.ProjectDir, config.SessionID, config.BallID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load balls for split: %v\n", err)
+		return
+	}
+	applySplitsToBalls(splits, balls)
+}
+
+// applySplitsToBalls applies each split to its ball in balls, reporting
+// splits for balls outside of them
+func applySplitsToBalls(splits []ballSplit, balls []*session.Ball) {
+	for _, split := range splits {
+		var parent *session.Ball
+		for _, ball := range balls {
+			if ball.ID == split.BallID || ball.ShortID() == split.BallID {
+				parent = ball
+				break
+			}
+		}
+		if parent == nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Agent proposed splitting %s, which isn't one of this run's balls\n", split.BallID)
+			continue
+		}
+
+		store, err := NewStoreForCommand(parent.WorkingDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create store: %v\n", err)
+			continue
+		}
+		created, err := applySplit(parent, store, split.Children)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping proposed split: %v\n", err)
+			continue
+		}
+		printSplit(parent, created)
+	}
+}
+
+// printSplit reports the children a ball was split into
+func printSplit(parent *session.Ball, children []*session.Ball) {
+	fmt.Printf("✂️  Split %s into %d ball(s):\n", parent.ShortID(), len(children))
+	for _, child := range children {
+		fmt.Printf("   %s %s %s\n", child.ShortID(), child.Title,
+			StyleDim.Render(fmt.Sprintf("(%s, %d AC)", child.Priority, len(child.AcceptanceCriteria))))
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestParseSplits(t *testing.T) {
+	output := `This ball is too big for one iteration.
+
+<split ball="proj-a1b2">
+<child title="Add the schema migration" priority="high">
+- Migration adds the archived column
+2. Migration rolls back cleanly
+</child>
+<child title="Archive sessions from the CLI">
+</child>
+</split>
+
+<promise>CONTINUE</promise>`
+
+	splits := parseSplits(output)
+	want := []ballSplit{{
+		BallID: "proj-a1b2",
+		Children: []splitChild{
+			{Title: "Add the schema migration", Priority: "high", ACs: []string{"Migration adds the archived column", "Migration rolls back cleanly"}},
+			{Title: "Archive sessions from the CLI"},
+		},
+	}}
+	if !reflect.DeepEqual(splits, want) {
+		t.Errorf("parseSplits() = %#v, want %#v", splits, want)
+	}
+
+	if splits := parseSplits("no split blocks here"); len(splits) != 0 {
+		t.Errorf("Expected no splits, got %v", splits)
+	}
+}
+
+func TestApplySplit(t *testing.T) {
+	dir := t.TempDir()
+	store, err := session.NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	parent, err := session.NewBall(dir, "Add session archiving", session.PriorityMedium)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	parent.Tags = []string{"my-feature"}
+	if err := store.AppendBall(parent); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+
+	// Nothing is written when a child is invalid
+	if _, err := applySplit(parent, store, []splitChild{{Title: "Ok"}, {Title: "Bad", Priority: "asap"}}); err == nil {
+		t.Fatal("Expected an error for an invalid priority")
+	}
+	if balls, _ := store.LoadBalls(); len(balls) != 1 {
+		t.Fatalf("Expected only the parent after a failed split, got %d balls", len(balls))
+	}
+
+	children, err := applySplit(parent, store, []splitChild{
+		{Title: "Add the schema migration", Priority: "high", ACs: []string{"Migration rolls back cleanly"}},
+		{Title: "Archive sessions from the CLI"},
+	})
+	if err != nil {
+		t.Fatalf("applySplit failed: %v", err)
+	}
+	if len(children) != 2 || children[0].Priority != session.PriorityHigh || children[1].Priority != session.PriorityMedium {
+		t.Fatalf("Expected a high and an inherited medium child, got %+v", children)
+	}
+
+	saved, err := store.GetBallByID(parent.ID)
+	if err != nil {
+		t.Fatalf("GetBallByID failed: %v", err)
+	}
+	if !saved.Epic || !reflect.DeepEqual(saved.DependsOn, []string{children[0].ID, children[1].ID}) {
+		t.Errorf("parent = epic %v depends on %v, want an epic depending on both children", saved.Epic, saved.DependsOn)
+	}
+	child, err := store.GetBallByID(children[0].ID)
+	if err != nil {
+		t.Fatalf("GetBallByID failed: %v", err)
+	}
+	if child.Parent != parent.ID || !reflect.DeepEqual(child.AcceptanceCriteria, []string{"Migration rolls back cleanly"}) {
+		t.Errorf("child = parent %q ACs %v", child.Parent, child.AcceptanceCriteria)
+	}
+
+	if _, err := applySplit(saved, store, []splitChild{{Title: "Again"}}); err == nil {
+		t.Error("Expected an error splitting an epic again")
+	}
+}
@@ -73,47 +73,21 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	allBalls, err := session.LoadAllBalls(projects)
-	if err != nil {
-		return fmt.Errorf("failed to load balls: %w", err)
+	// Pending balls left untouched gain priority before they are shown
+	for _, projectDir := range projects {
+		printAgedBalls(ageProjectBalls(projectDir))
 	}
 
-	// Filter to non-complete balls
-	activeBalls := make([]*session.Ball, 0)
-	for _, ball := range allBalls {
-		if ball.State != session.StateComplete {
-			activeBalls = append(activeBalls, ball)
-		}
+	// Complete balls are waiting to be archived, so only the rest are read
+	query := session.BallQuery{
+		States: []session.BallState{session.StatePending, session.StateInProgress, session.StateBlocked, session.StateResearched},
 	}
 
-	// Apply tag filter if specified
+	// Apply tag filter if specified (OR logic)
 	if filterTags != "" {
-		tagList := strings.Split(filterTags, ",")
-		// Trim whitespace from each tag
-		for i := range tagList {
-			tagList[i] = strings.TrimSpace(tagList[i])
-		}
-
-		filtered := make([]*session.Ball, 0)
-		for _, ball := range activeBalls {
-			// Check if ball has any of the specified tags (OR logic)
-			hasTag := false
-			for _, filterTag := range tagList {
-				for _, ballTag := range ball.Tags {
-					if ballTag == filterTag {
-						hasTag = true
-						break
-					}
-				}
-				if hasTag {
-					break
-				}
-			}
-			if hasTag {
-				filtered = append(filtered, ball)
-			}
+		for _, tag := range strings.Split(filterTags, ",") {
+			query.Tags = append(query.Tags, strings.TrimSpace(tag))
 		}
-		activeBalls = filtered
 	}
 
 	// Apply priority filter if specified
@@ -121,14 +95,14 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		if !session.ValidatePriority(filterPriority) {
 			return fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", filterPriority)
 		}
-
-		filtered := make([]*session.Ball, 0)
-		for _, ball := range activeBalls {
-			if string(ball.Priority) == filterPriority {
-				filtered = append(filtered, ball)
-			}
+		query.Match = func(ball *session.Ball) bool {
+			return string(ball.Priority) == filterPriority
 		}
-		activeBalls = filtered
+	}
+
+	activeBalls, err := session.LoadAllBallsFiltered(projects, query)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
 	}
 
 	if len(activeBalls) == 0 {
@@ -264,9 +238,12 @@ func renderGroupedSessions(ballsByProject map[string][]*session.Ball, cwd string
 			// Pad first, then style
 			statusCell = statusStyle.Render(padRight(stateStr, 12))
 
-			// Priority
+			// Priority, marked when aging raised it
 			priorityStr := string(ball.Priority)
 			priorityCell := GetPriorityStyle(priorityStr).Render(padRight(priorityStr, 10))
+			if ball.IsAged() {
+				priorityCell = GetPriorityStyle(priorityStr).Render(padRight(priorityStr+" "+agedMarker, 10+len(agedMarker)-1))
+			}
 
 			// Acceptance Criteria
 			criteriaCell := "-"
@@ -3,11 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/ohare93/juggle/internal/agent/supervisor"
+	"github.com/ohare93/juggle/internal/process"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
@@ -38,8 +36,11 @@ The supervisor will:
   1. Periodically scan all projects for sessions with running/pending daemons
   2. Detect stalled daemons (no state update within stall_timeout)
   3. Recover missed signals from OpenCode session exports
-  4. Optionally auto-restart stalled daemons
+  4. Optionally auto-restart stalled daemons, and crashed ones with a backoff
+     (max_crash_restarts, crash_backoff_seconds)
   5. Optionally auto-launch daemons for sessions with pending balls
+  6. Remove stale session storage, as juggle gc does (auto_reap, reap_idle_hours)
+  7. Raise the priority of pending balls left untouched (project "aging" config)
 
 Configuration is read from ~/.juggle/config.json under the "supervisor" key.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -70,12 +71,12 @@ Configuration is read from ~/.juggle/config.json under the "supervisor" key.`,
 		}
 
 		// Wait for interrupt
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		ctx, stop := process.NotifyContext(commandContext(cmd))
+		defer stop()
 
 		fmt.Println("Supervisor running. Press Ctrl+C to stop.")
 
-		<-sigCh
+		<-ctx.Done()
 		fmt.Println("\nStopping supervisor...")
 		sv.Stop()
 		fmt.Println("Supervisor stopped.")
@@ -149,6 +150,8 @@ var supervisorStatusCmd = &cobra.Command{
 				runStatus = fmt.Sprintf("running/%d", st.DaemonPID)
 			} else if st.Stalled {
 				runStatus = "STALLED"
+			} else if st.Crash != nil {
+				runStatus = "CRASHED"
 			}
 
 			fmt.Printf("%-40s %-15s %-8s %s\n",
@@ -184,7 +187,7 @@ var supervisorOnceCmd = &cobra.Command{
 		fmt.Printf("Polled %d sessions:\n", len(statuses))
 		for _, st := range statuses {
 			icon := " "
-			if st.Stalled {
+			if st.Stalled || st.Crash != nil {
 				icon = "!"
 			} else if st.Running {
 				icon = ">"
@@ -219,11 +222,11 @@ var supervisorEnsureCmd = &cobra.Command{
 		}
 
 		// Wait for interrupt
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		ctx, stop := process.NotifyContext(commandContext(cmd))
+		defer stop()
 
 		fmt.Println("Supervisor running. Press Ctrl+C to stop.")
-		<-sigCh
+		<-ctx.Done()
 		fmt.Println("\nStopping supervisor...")
 		sv.Stop()
 
@@ -155,6 +155,9 @@ func syncPRDFile(prdPath, projectDir string) error {
 		return fmt.Errorf("failed to create store: %w", err)
 	}
 
+	// Keep changes in memory to write them with one rewrite of balls.jsonl
+	store = store.Cached()
+
 	// Load existing balls
 	balls, err := store.LoadBalls()
 	if err != nil {
@@ -238,6 +241,10 @@ func syncPRDFile(prdPath, projectDir string) error {
 		}
 	}
 
+	if err := store.Flush(); err != nil {
+		return fmt.Errorf("failed to save balls: %w", err)
+	}
+
 	fmt.Printf("\nSync complete: %d created, %d updated, %d unchanged\n", created, updated, skipped)
 	return nil
 }
@@ -0,0 +1,379 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/vault"
+	"github.com/ohare93/juggle/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+var syncVaultWatch bool
+
+// vaultDebounce groups the burst of events one save or sync causes
+const vaultDebounce = 500 * time.Millisecond
+
+// syncVaultCmd mirrors balls as Markdown notes in a vault folder
+var syncVaultCmd = &cobra.Command{
+	Use:   "vault [folder]",
+	Short: "Mirror balls as Markdown notes in a vault folder",
+	Long: `Mirror the project's balls as Markdown notes with YAML front matter, for
+browsing and editing them in Obsidian or another Markdown knowledge base.
+
+The folder comes from the argument or "vault.path" in .juggle/config.json, and
+should be used by this project only. It gets:
+  balls/<ball-id>.md       one note per ball, linking to its sessions
+  sessions/<session>.md    one note per session, linking to its balls
+
+In a ball note the H1 is the title, text before the first "##" heading is the
+context, and list items under "## Acceptance Criteria" are the criteria. State,
+priority, tags, dependencies and model size are in the front matter.
+
+Sync goes both ways:
+  - Balls that changed since their note was written rewrite the note
+  - Notes edited since they were written update their ball
+  - New notes without an id in balls/ become new balls
+  - Notes of archived or deleted balls are removed
+When a ball and its note both change between syncs, the ball wins.
+
+Examples:
+  # Sync once into the configured vault folder
+  juggle sync vault
+
+  # Sync into a folder inside an Obsidian vault
+  juggle sync vault ~/Obsidian/Work/myapp
+
+  # Keep syncing as balls or notes change
+  juggle sync vault --watch`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSyncVault,
+}
+
+func init() {
+	syncVaultCmd.Flags().BoolVarP(&syncVaultWatch, "watch", "w", false, "Watch balls and notes and sync on every change")
+	syncCmd.AddCommand(syncVaultCmd)
+}
+
+// VaultSyncResult counts what a vault sync changed
+type VaultSyncResult struct {
+	Written int // Notes written from balls and sessions
+	Applied int // Balls updated from edited notes
+	Created int // Balls created from new notes
+	Removed int // Notes removed for balls or sessions that are gone
+}
+
+// Changed reports whether the sync changed anything
+func (r *VaultSyncResult) Changed() bool {
+	return r.Written+r.Applied+r.Created+r.Removed > 0
+}
+
+func (r *VaultSyncResult) String() string {
+	return fmt.Sprintf("%d note(s) written, %d ball(s) updated, %d created, %d note(s) removed",
+		r.Written, r.Applied, r.Created, r.Removed)
+}
+
+func runSyncVault(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	var dir string
+	if len(args) > 0 {
+		dir = (&session.VaultConfig{Path: args[0]}).Dir(cwd)
+	} else {
+		config, err := session.GetProjectVault(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to load project config: %w", err)
+		}
+		dir = config.Dir(cwd)
+	}
+	if dir == "" {
+		return fmt.Errorf("no vault folder: pass one or set vault.path in .juggle/config.json")
+	}
+
+	if syncVaultWatch {
+		return watchVault(commandContext(cmd), cwd, dir)
+	}
+
+	result, err := SyncVault(cwd, dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nVault sync complete: %s\n", result)
+	return nil
+}
+
+// watchVault syncs once, then again whenever the balls, sessions or notes change
+func watchVault(ctx context.Context, projectDir, dir string) error {
+	result, err := SyncVault(projectDir, dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nVault sync complete: %s\n", result)
+
+	w, err := watcher.New()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	if err := w.WatchProject(projectDir); err != nil {
+		return err
+	}
+	if err := w.WatchVault(filepath.Join(dir, vault.BallsDir)); err != nil {
+		return err
+	}
+	w.Start()
+
+	fmt.Printf("\nWatching %s and the project's balls for changes...\n", dir)
+
+	// Syncing writes notes and balls, which fire events again; the follow-up
+	// sync finds nothing to change, so this settles
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event := <-w.Events:
+			switch event.Type {
+			case watcher.BallsChanged, watcher.SessionChanged, watcher.VaultChanged:
+				pending = time.After(vaultDebounce)
+			}
+
+		case <-pending:
+			pending = nil
+			result, err := SyncVault(projectDir, dir)
+			if err != nil {
+				fmt.Printf("Sync error: %v\n", err)
+			} else if result.Changed() {
+				fmt.Printf("[%s] %s\n", time.Now().Format("15:04:05"), result)
+			}
+
+		case err := <-w.Errors:
+			fmt.Printf("Watch error: %v\n", err)
+		}
+	}
+}
+
+// vaultNote is a ball note found in the vault
+type vaultNote struct {
+	path string
+	data []byte
+	note *vault.Note
+}
+
+// SyncVault syncs the project's balls and sessions with the notes in dir in
+// both directions (exported for testing)
+func SyncVault(projectDir, dir string) (*VaultSyncResult, error) {
+	ballsDir := filepath.Join(dir, vault.BallsDir)
+	sessionsDir := filepath.Join(dir, vault.SessionsDir)
+	for _, d := range []string{ballsDir, sessionsDir} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create vault folder: %w", err)
+		}
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	notes, unfiled, err := loadVaultNotes(ballsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	isSession := make(map[string]bool)
+	for _, s := range sessions {
+		isSession[s.ID] = true
+	}
+	ballSessions := func(ball *session.Ball) []string {
+		var ids []string
+		for _, tag := range ball.Tags {
+			if isSession[tag] {
+				ids = append(ids, tag)
+			}
+		}
+		return ids
+	}
+
+	result := &VaultSyncResult{}
+	active := make(map[string]bool)
+	for _, ball := range balls {
+		active[ball.ID] = true
+		existing := notes[ball.ID]
+
+		// Edited since it was written from the ball: the note wins
+		if existing != nil && existing.note.Updated == vault.Stamp(ball) && existing.note.Differs(ball) {
+			if err := existing.note.Validate(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping edits to %s: %v\n", existing.path, err)
+				continue
+			}
+			existing.note.Apply(ball)
+			if err := store.UpdateBall(ball); err != nil {
+				return result, fmt.Errorf("failed to update %s: %w", ball.ID, err)
+			}
+			fmt.Printf("Updated from note: %s - \"%s\"\n", ball.ID, ball.Title)
+			result.Applied++
+		}
+
+		path := filepath.Join(ballsDir, ball.ID+".md")
+		data := vault.RenderBall(ball, ballSessions(ball))
+		if existing != nil && existing.path == path && bytes.Equal(existing.data, data) {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return result, fmt.Errorf("failed to write note: %w", err)
+		}
+		if existing != nil && existing.path != path {
+			_ = os.Remove(existing.path)
+		}
+		result.Written++
+	}
+
+	// Notes of balls that were archived or deleted
+	for id, existing := range notes {
+		if active[id] {
+			continue
+		}
+		if err := os.Remove(existing.path); err != nil {
+			return result, fmt.Errorf("failed to remove note: %w", err)
+		}
+		fmt.Printf("Removed note: %s (ball archived or deleted)\n", id)
+		result.Removed++
+	}
+
+	// Notes written by hand become new balls
+	for _, existing := range unfiled {
+		if err := existing.note.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping new note %s: %v\n", existing.path, err)
+			continue
+		}
+		ball, err := session.NewBall(projectDir, existing.note.Title, session.PriorityMedium)
+		if err != nil {
+			return result, fmt.Errorf("failed to create ball: %w", err)
+		}
+		existing.note.Apply(ball)
+		if err := store.AppendBall(ball); err != nil {
+			return result, fmt.Errorf("failed to create ball: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(ballsDir, ball.ID+".md"), vault.RenderBall(ball, ballSessions(ball)), 0644); err != nil {
+			return result, fmt.Errorf("failed to write note: %w", err)
+		}
+		_ = os.Remove(existing.path)
+		fmt.Printf("Created from note: %s → %s - \"%s\"\n", filepath.Base(existing.path), ball.ID, ball.Title)
+		result.Created++
+		balls = append(balls, ball)
+	}
+
+	written, removed, err := syncVaultSessions(sessionsDir, sessions, balls)
+	result.Written += written
+	result.Removed += removed
+	return result, err
+}
+
+// loadVaultNotes reads the ball notes in dir, keyed by ball ID. Notes without
+// an ID are returned separately; notes that can't be parsed are skipped.
+func loadVaultNotes(dir string) (map[string]*vaultNote, []*vaultNote, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read vault folder: %w", err)
+	}
+
+	notes := make(map[string]*vaultNote)
+	var unfiled []*vaultNote
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read note: %w", err)
+		}
+		note, err := vault.ParseNote(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", path, err)
+			continue
+		}
+		if note.Title == "" && note.ID == "" {
+			note.Title = strings.TrimSuffix(entry.Name(), ".md")
+		}
+
+		found := &vaultNote{path: path, data: data, note: note}
+		if note.ID == "" {
+			unfiled = append(unfiled, found)
+			continue
+		}
+		// A note renamed by hand loses to the one at the canonical path
+		if other := notes[note.ID]; other != nil && filepath.Base(other.path) == note.ID+".md" {
+			continue
+		}
+		notes[note.ID] = found
+	}
+	return notes, unfiled, nil
+}
+
+// syncVaultSessions writes a note per session and removes notes of sessions
+// that no longer exist
+func syncVaultSessions(dir string, sessions []*session.JuggleSession, balls []*session.Ball) (written, removed int, err error) {
+	keep := make(map[string]bool)
+	for _, s := range sessions {
+		var tagged []*session.Ball
+		for _, ball := range balls {
+			for _, tag := range ball.Tags {
+				if tag == s.ID {
+					tagged = append(tagged, ball)
+					break
+				}
+			}
+		}
+
+		name := s.ID + ".md"
+		keep[name] = true
+		path := filepath.Join(dir, name)
+		data := vault.RenderSession(s, tagged)
+		if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return written, removed, fmt.Errorf("failed to write session note: %w", err)
+		}
+		written++
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return written, removed, fmt.Errorf("failed to read vault folder: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" || keep[entry.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return written, removed, fmt.Errorf("failed to remove session note: %w", err)
+		}
+		removed++
+	}
+	return written, removed, nil
+}
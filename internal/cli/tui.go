@@ -130,7 +130,7 @@ func runTUI(cmd *cobra.Command, args []string) error {
 				Interactive:   true,  // Interactive mode for user involvement
 			}
 
-			_, err := RunAgentLoop(agentConfig)
+			_, err := RunAgentLoop(commandContext(cmd), agentConfig)
 			if err != nil {
 				return fmt.Errorf("agent error: %w", err)
 			}
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// tutorialSessionID is the session the tutorial creates in the scratch project
+const tutorialSessionID = "tutorial"
+
+// tutorialPreviewLines is how much of the generated prompt the dry-run step shows
+const tutorialPreviewLines = 40
+
+var (
+	tutorialDir       string
+	tutorialYes       bool
+	tutorialSkipAgent bool
+)
+
+var tutorialCmd = &cobra.Command{
+	Use:   "tutorial",
+	Short: "Walk through juggle step by step in a scratch project",
+	Long: `Walk through the basics of juggle in a scratch directory, without touching
+your own projects:
+
+  1. Check that your agent provider is installed
+  2. Initialize a scratch project
+  3. Create a session
+  4. Create a ball with acceptance criteria
+  5. Dry-run the agent to see its prompt
+  6. Run the agent on that one ball, interactively
+
+Each step prints the command you would run yourself. The agent step asks
+before it starts, since it uses your provider account.
+
+Examples:
+  juggle tutorial                      # Scratch project in a temp directory
+  juggle tutorial --dir ~/juggle-demo  # Keep the scratch project somewhere known
+  juggle tutorial --skip-agent         # Stop after the dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runTutorial,
+}
+
+func init() {
+	tutorialCmd.Flags().StringVar(&tutorialDir, "dir", "", "Scratch directory to use (default: a new temp directory)")
+	tutorialCmd.Flags().BoolVarP(&tutorialYes, "yes", "y", false, "Don't pause between steps")
+	tutorialCmd.Flags().BoolVar(&tutorialSkipAgent, "skip-agent", false, "Stop before running the agent")
+}
+
+// TutorialOptions configures RunTutorial
+type TutorialOptions struct {
+	Dir       string    // Scratch project directory (required)
+	Input     io.Reader // Where answers are read from (default: os.Stdin)
+	Output    io.Writer // Where the walkthrough is written (default: os.Stdout)
+	NoPause   bool      // Don't wait for Enter between steps
+	SkipAgent bool      // Stop before running the agent
+}
+
+func runTutorial(cmd *cobra.Command, args []string) error {
+	dir := tutorialDir
+	if dir == "" {
+		var err error
+		dir, err = os.MkdirTemp("", "juggle-tutorial-")
+		if err != nil {
+			return fmt.Errorf("failed to create scratch directory: %w", err)
+		}
+	}
+
+	return RunTutorial(TutorialOptions{
+		Dir:       dir,
+		NoPause:   tutorialYes,
+		SkipAgent: tutorialSkipAgent,
+	})
+}
+
+// tutorial holds the state of one walkthrough
+type tutorial struct {
+	opts  TutorialOptions
+	in    *bufio.Reader
+	out   io.Writer
+	step  int
+	steps int
+	ball  *session.Ball
+}
+
+// RunTutorial walks through creating a session and ball in a scratch project,
+// dry-running the agent and running it on that ball.
+// This is the core logic extracted for testability.
+func RunTutorial(opts TutorialOptions) error {
+	if opts.Dir == "" {
+		return fmt.Errorf("tutorial directory is required")
+	}
+	if opts.Input == nil {
+		opts.Input = os.Stdin
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+
+	t := &tutorial{
+		opts:  opts,
+		in:    bufio.NewReader(opts.Input),
+		out:   opts.Output,
+		steps: 6,
+	}
+
+	fmt.Fprintln(t.out, "Welcome to juggle!")
+	fmt.Fprintln(t.out)
+	fmt.Fprintln(t.out, "This tutorial sets up a scratch project, gives an agent one small task,")
+	fmt.Fprintln(t.out, "and shows the commands for each step so you can repeat them on real work.")
+	fmt.Fprintf(t.out, "Scratch project: %s\n", opts.Dir)
+
+	providerReady := t.checkProvider()
+	if err := t.initProject(); err != nil {
+		return err
+	}
+	if err := t.createSession(); err != nil {
+		return err
+	}
+	if err := t.createBall(); err != nil {
+		return err
+	}
+	if err := t.dryRun(); err != nil {
+		return err
+	}
+	if err := t.runAgent(providerReady); err != nil {
+		return err
+	}
+
+	t.finish()
+	return nil
+}
+
+// begin prints a step header, pausing first unless disabled
+func (t *tutorial) begin(title string) {
+	t.step++
+	if t.step > 1 && !t.opts.NoPause {
+		fmt.Fprint(t.out, "\nPress Enter to continue...")
+		_, _ = t.in.ReadString('\n')
+	}
+	fmt.Fprintf(t.out, "\n── Step %d/%d: %s ──\n\n", t.step, t.steps, title)
+}
+
+// command prints the CLI command equivalent to a step
+func (t *tutorial) command(args ...string) {
+	fmt.Fprintf(t.out, "  $ juggle %s\n\n", strings.Join(args, " "))
+}
+
+// confirm asks a yes/no question, defaulting to no
+func (t *tutorial) confirm(question string) bool {
+	fmt.Fprintf(t.out, "%s [y/N]: ", question)
+	answer, _ := t.in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// checkProvider reports whether the configured agent provider can run the
+// interactive step. Problems are explained rather than returned, so the rest
+// of the tutorial still works.
+func (t *tutorial) checkProvider() bool {
+	t.begin("Check your agent provider")
+
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(t.out, "⚠️  Could not read the provider from your config: %v\n", err)
+	}
+	registerCustomProviders()
+	providerType := provider.Detect("", "", globalProvider)
+	binary := provider.BinaryName(providerType)
+
+	fmt.Fprintf(t.out, "juggle runs agents through a provider CLI. Yours is %s.\n\n", providerType)
+	fmt.Fprintln(t.out, "To change it:")
+	t.command("config", "provider", "set", "<claude|opencode|goose|amp>")
+
+	if !provider.Get(providerType).SupportsTools() {
+		fmt.Fprintf(t.out, "✗ %s has no tools and only gives advice, so the agent step will be skipped.\n", providerType)
+		return false
+	}
+
+	path, err := exec.LookPath(binary)
+	if binary == "" || err != nil {
+		fmt.Fprintf(t.out, "✗ %q was not found in your PATH. Install it to run the agent step;\n", binary)
+		fmt.Fprintln(t.out, "  the other steps work without it.")
+		return false
+	}
+	fmt.Fprintf(t.out, "✓ Found %s at %s\n", binary, path)
+
+	if !provider.Get(providerType).SupportsInteractive() {
+		fmt.Fprintf(t.out, "✗ %s can't run interactively, so the agent step will be skipped.\n", providerType)
+		return false
+	}
+	return true
+}
+
+// initProject creates the scratch project and its VCS repository
+func (t *tutorial) initProject() error {
+	t.begin("Initialize a project")
+	fmt.Fprintln(t.out, "Every project keeps its balls (tasks) and sessions in a .juggle directory.")
+	t.command("init", t.opts.Dir)
+
+	return InitProject(InitOptions{
+		TargetDir:            t.opts.Dir,
+		JuggleDirName:        ".juggle",
+		InitVCS:              true,
+		CreateClaudeSettings: true,
+		NonInteractive:       true,
+		SkipSetupPrompt:      true,
+		Output:               t.out,
+	})
+}
+
+// createSession creates the tutorial session, reusing it on a re-run
+func (t *tutorial) createSession() error {
+	t.begin("Create a session")
+	fmt.Fprintln(t.out, "A session groups related balls. The agent works through a session's balls,")
+	fmt.Fprintln(t.out, "and session acceptance criteria apply to every ball in it.")
+	t.command("sessions", "create", tutorialSessionID, "-m", `"juggle tutorial"`)
+
+	sessionStore, err := session.NewSessionStore(t.opts.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+	if _, err := sessionStore.LoadSession(tutorialSessionID); err == nil {
+		fmt.Fprintf(t.out, "✓ Session %s already exists, reusing it\n", tutorialSessionID)
+		return nil
+	}
+	if _, err := sessionStore.CreateSession(tutorialSessionID, "juggle tutorial"); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	fmt.Fprintf(t.out, "✓ Created session %s\n", tutorialSessionID)
+	return nil
+}
+
+// createBall adds the task the agent will work on
+func (t *tutorial) createBall() error {
+	t.begin("Create a ball")
+	fmt.Fprintln(t.out, "A ball is one task. Context tells the agent why; acceptance criteria tell it")
+	fmt.Fprintln(t.out, "when it's done.")
+
+	title := "Create hello.txt"
+	context := "This is the juggle tutorial. Create a small text file to show the agent loop working."
+	criteria := []string{
+		"hello.txt exists in the project root",
+		"hello.txt contains a one-line greeting",
+	}
+	t.command("plan", fmt.Sprintf("%q", title), "--session", tutorialSessionID,
+		"--context", fmt.Sprintf("%q", context),
+		"--ac", fmt.Sprintf("%q", criteria[0]),
+		"--ac", fmt.Sprintf("%q", criteria[1]),
+		"--non-interactive")
+
+	store, err := session.NewStore(t.opts.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	ball, err := session.NewBall(t.opts.Dir, title, session.PriorityMedium)
+	if err != nil {
+		return fmt.Errorf("failed to create ball: %w", err)
+	}
+	ball.Context = context
+	ball.SetAcceptanceCriteria(criteria)
+	ball.Tags = []string{tutorialSessionID}
+	if err := store.AppendBall(ball); err != nil {
+		return fmt.Errorf("failed to save ball: %w", err)
+	}
+	t.ball = ball
+
+	fmt.Fprintf(t.out, "✓ Created ball %s: %s\n", ball.ID, ball.Title)
+	fmt.Fprintln(t.out, "\nSee it with:")
+	t.command("show", ball.ID)
+	return nil
+}
+
+// dryRun shows the start of the prompt the agent would receive
+func (t *tutorial) dryRun() error {
+	t.begin("Dry-run the agent")
+	fmt.Fprintln(t.out, "A dry run builds the agent's prompt from the session and ball without")
+	fmt.Fprintln(t.out, "starting the agent, so you can check what it will be told.")
+	t.command("agent", "run", tutorialSessionID, "--ball", t.ball.ID, "--dry-run")
+
+	output, err := exportAgent(t.opts.Dir, tutorialSessionID, []*session.Ball{t.ball}, false, true)
+	if err != nil {
+		return fmt.Errorf("failed to generate prompt: %w", err)
+	}
+	prompt := string(output)
+
+	lines := strings.Split(strings.TrimRight(prompt, "\n"), "\n")
+	preview := lines
+	if len(preview) > tutorialPreviewLines {
+		preview = preview[:tutorialPreviewLines]
+	}
+	for _, line := range preview {
+		fmt.Fprintf(t.out, "  │ %s\n", line)
+	}
+	if len(lines) > len(preview) {
+		fmt.Fprintf(t.out, "  │ ... (%d more lines)\n", len(lines)-len(preview))
+	}
+	fmt.Fprintf(t.out, "\nThe full prompt is %d characters.\n", len(prompt))
+	return nil
+}
+
+// runAgent runs the agent on the tutorial ball in interactive mode, after asking
+func (t *tutorial) runAgent(providerReady bool) error {
+	t.begin("Run the agent on one ball")
+	fmt.Fprintln(t.out, "--ball runs a single iteration on one ball, interactively, so you can watch")
+	fmt.Fprintln(t.out, "and steer. Without --ball the agent loops through the whole session.")
+	t.command("agent", "run", tutorialSessionID, "--ball", t.ball.ID)
+
+	switch {
+	case t.opts.SkipAgent:
+		fmt.Fprintln(t.out, "Skipped (--skip-agent). Run the command above when you're ready.")
+		return nil
+	case !providerReady:
+		fmt.Fprintln(t.out, "Skipped: the agent provider isn't set up (see step 1).")
+		return nil
+	case !t.confirm("Start the agent now? It uses your provider account"):
+		fmt.Fprintln(t.out, "Skipped. Run the command above when you're ready.")
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find juggle executable: %w", err)
+	}
+	args := []string{"agent", "run", tutorialSessionID, "--ball", t.ball.ID}
+	if GlobalOpts.ConfigHome != "" {
+		args = append(args, "--config-home", GlobalOpts.ConfigHome)
+	}
+	agentCmd := exec.Command(exe, args...)
+	agentCmd.Dir = t.opts.Dir
+	agentCmd.Stdin = os.Stdin
+	agentCmd.Stdout = os.Stdout
+	agentCmd.Stderr = os.Stderr
+	if err := agentCmd.Run(); err != nil {
+		fmt.Fprintf(t.out, "⚠️  The agent exited with an error: %v\n", err)
+	}
+	return nil
+}
+
+// finish points at what to try next
+func (t *tutorial) finish() {
+	fmt.Fprintln(t.out)
+	fmt.Fprintln(t.out, "── Done ──")
+	fmt.Fprintln(t.out)
+	fmt.Fprintln(t.out, "Next steps:")
+	next := [][2]string{
+		{fmt.Sprintf("cd %s && juggle", t.opts.Dir), "Browse the scratch project in the TUI"},
+		{"juggle examples", "Workflows for overnight runs, spec import and more"},
+		{"juggle init", "Set up your own project"},
+	}
+	width := 0
+	for _, n := range next {
+		width = max(width, len(n[0]))
+	}
+	for _, n := range next {
+		fmt.Fprintf(t.out, "  %-*s  # %s\n", width, n[0], n[1])
+	}
+	fmt.Fprintln(t.out)
+	fmt.Fprintf(t.out, "Remove the scratch project with: rm -rf %s\n", t.opts.Dir)
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
@@ -103,14 +102,10 @@ func findArchivedBallByID(ballID string) (*session.Ball, *session.Store, error)
 	// Use prefix matching
 	matches := session.ResolveBallByPrefix(archivedBalls, ballID)
 	if len(matches) == 0 {
-		return nil, nil, fmt.Errorf("ball not found in archives: %s", ballID)
+		return nil, nil, fmt.Errorf("%w in archives: %s", session.ErrBallNotFound, ballID)
 	}
 	if len(matches) > 1 {
-		matchingIDs := make([]string, len(matches))
-		for i, m := range matches {
-			matchingIDs[i] = m.ID
-		}
-		return nil, nil, fmt.Errorf("ambiguous ID '%s' matches %d archived balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+		return nil, nil, ambiguousBallError(ballID, matches, archivedBalls)
 	}
 
 	ball := matches[0]
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unblockFilter     string
+	unblockMessage    string
+	unblockRun        bool
+	unblockIterations int
+	unblockTrust      bool
+	unblockDryRun     bool
+)
+
+var unblockCmd = &cobra.Command{
+	Use:   "unblock [ball-id...]",
+	Short: "Move blocked balls back to pending in bulk",
+	Long: `Clear the blocked state of balls after fixing what blocked them.
+
+Pick balls by ID, by --filter, or both. Only blocked balls are changed; they go
+back to pending and their blocked reason is cleared. --message is logged to each
+ball's session progress so the agent knows what changed.
+
+Filters are space-separated conditions that must all match:
+  field=value    field!=value    field~substring    field!~substring
+Fields: blocked_reason, title, context, id, state, priority, kind, tag, session.
+Matching is case-insensitive; quote values with spaces.
+
+With --run, the agent loop then runs on each unblocked ball in turn.
+
+Examples:
+  juggle unblock a1b2 c3d4
+  juggle unblock --filter 'blocked_reason~"missing API key"' --message "Key added to env"
+  juggle unblock --filter 'tag=backend' --dry-run
+  juggle unblock --filter 'blocked_reason~flaky' --run -n 3`,
+	ValidArgsFunction: CompleteBallIDs,
+	RunE:              runUnblock,
+}
+
+func init() {
+	unblockCmd.Flags().StringVar(&unblockFilter, "filter", "", "Select blocked balls matching a filter expression")
+	unblockCmd.Flags().StringVarP(&unblockMessage, "message", "m", "", "Note logged to session progress for the agent")
+	unblockCmd.Flags().BoolVar(&unblockRun, "run", false, "Run the agent loop on each unblocked ball")
+	unblockCmd.Flags().IntVarP(&unblockIterations, "iterations", "n", 3, "Iterations per ball with --run")
+	unblockCmd.Flags().BoolVar(&unblockTrust, "trust", false, "Run the agent with full permissions (with --run)")
+	unblockCmd.Flags().BoolVar(&unblockDryRun, "dry-run", false, "List the balls that would be unblocked without changing them")
+	rootCmd.AddCommand(unblockCmd)
+}
+
+func runUnblock(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && unblockFilter == "" {
+		return fmt.Errorf("give ball IDs or --filter to choose which balls to unblock")
+	}
+	filter, err := session.ParseBallFilter(unblockFilter)
+	if err != nil {
+		return err
+	}
+
+	balls, err := selectBallsToUnblock(args, filter)
+	if err != nil {
+		return err
+	}
+	if len(balls) == 0 {
+		fmt.Println("No blocked balls match.")
+		return nil
+	}
+
+	if unblockDryRun {
+		fmt.Printf("Would unblock %d ball(s):\n", len(balls))
+		for _, ball := range balls {
+			fmt.Printf("  %s %s\n", ball.ShortID(), StyleDim.Render(ball.BlockedReason))
+		}
+		return nil
+	}
+
+	var unblocked []*session.Ball
+	err = unblockBalls(balls, session.StatePending, unblockMessage, func(ball *session.Ball, reason string, err error) error {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to unblock %s: %v\n", ball.ShortID(), err)
+			return nil
+		}
+		fmt.Printf("✓ Unblocked %s %s\n", ball.ShortID(), StyleDim.Render("(was: "+reason+")"))
+		unblocked = append(unblocked, ball)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%d ball(s) moved back to pending\n", len(unblocked))
+
+	if !unblockRun {
+		return nil
+	}
+	ctx := commandContext(cmd)
+	for _, ball := range unblocked {
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Printf("\nStarting agent for ball %s...\n", ball.ShortID())
+		_, err := RunAgentLoop(ctx, AgentLoopConfig{
+			SessionID:     unblockSessionID(ball),
+			ProjectDir:    ball.WorkingDir,
+			MaxIterations: unblockIterations,
+			BallID:        ball.ID,
+			Trust:         unblockTrust,
+		})
+		if err != nil {
+			return fmt.Errorf("agent error on %s: %w", ball.ShortID(), err)
+		}
+	}
+	return nil
+}
+
+// selectBallsToUnblock returns the blocked balls named by ids (all of them when
+// none are given) that also match the filter
+func selectBallsToUnblock(ids []string, filter *session.BallFilter) ([]*session.Ball, error) {
+	var candidates []*session.Ball
+	if len(ids) > 0 {
+		for _, id := range ids {
+			ball, _, err := findBallByID(id)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, ball)
+		}
+	} else {
+		config, err := LoadConfigForCommand()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		cwd, err := GetWorkingDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		store, err := NewStoreForCommand(cwd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create store: %w", err)
+		}
+		projects, err := DiscoverProjectsForCommand(config, store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover projects: %w", err)
+		}
+		candidates, err = session.LoadAllBalls(projects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load balls: %w", err)
+		}
+	}
+
+	var matched []*session.Ball
+	for _, ball := range candidates {
+		if ball.State != session.StateBlocked {
+			if len(ids) > 0 {
+				fmt.Fprintf(os.Stderr, "Skipping %s: not blocked (state: %s)\n", ball.ShortID(), ball.State)
+			}
+			continue
+		}
+		if filter.Match(ball) {
+			matched = append(matched, ball)
+		}
+	}
+	return matched, nil
+}
+
+// unblockBalls unblocks balls as unblockBall does, rewriting each project's
+// balls.jsonl once. each is called with every ball, the blocked reason it
+// had and the error unblocking it; an error from each stops there.
+func unblockBalls(balls []*session.Ball, state session.BallState, message string, each func(ball *session.Ball, reason string, err error) error) error {
+	var projects []string
+	byProject := make(map[string][]*session.Ball)
+	for _, ball := range balls {
+		if _, ok := byProject[ball.WorkingDir]; !ok {
+			projects = append(projects, ball.WorkingDir)
+		}
+		byProject[ball.WorkingDir] = append(byProject[ball.WorkingDir], ball)
+	}
+
+	for _, projectDir := range projects {
+		store, err := NewStoreForCommand(projectDir)
+		if err != nil {
+			return fmt.Errorf("failed to create store: %w", err)
+		}
+		err = store.Batch(func(store *session.Store) error {
+			for _, ball := range byProject[projectDir] {
+				reason := ball.BlockedReason
+				if err := each(ball, reason, unblockBall(store, ball, state, reason, message)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unblockBall moves the ball to state (pending, or in_progress when an agent
+// run picks it straight back up) and logs why to its sessions' progress
+func unblockBall(store *session.Store, ball *session.Ball, state session.BallState, reason, message string) error {
+	if err := ball.SetState(state); err != nil {
+		return err
+	}
+	// A fresh retry budget, so agents don't block the ball again right away
+	ball.AttemptCount = 0
+	ball.FailedAttempts = 0
+	if err := store.UpdateBall(ball); err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf("[UNBLOCK] %s unblocked (was: %s)", ball.ID, reason)
+	if message != "" {
+		entry += ": " + message
+	}
+	sessionStore, err := session.NewSessionStore(ball.WorkingDir)
+	if err != nil {
+		return nil // Progress logging is best-effort
+	}
+	for _, tag := range ball.Tags {
+		if _, err := sessionStore.LoadSession(tag); err != nil {
+			continue
+		}
+		_ = sessionStore.AppendProgress(tag, entry)
+	}
+	return nil
+}
+
+// unblockSessionID picks the session to run the agent in for a ball: its first
+// tag that is a session, or the "all" meta-session
+func unblockSessionID(ball *session.Ball) string {
+	sessionStore, err := session.NewSessionStore(ball.WorkingDir)
+	if err != nil {
+		return "all"
+	}
+	for _, tag := range ball.Tags {
+		if _, err := sessionStore.LoadSession(tag); err == nil {
+			return tag
+		}
+	}
+	return "all"
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestUnblockByFilter(t *testing.T) {
+	projectDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.CreateSession("payments", "Payments"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	reasons := []string{"Missing API key for Stripe", "Flaky test in CI"}
+	var balls []*session.Ball
+	for _, reason := range reasons {
+		ball, err := session.NewBall(projectDir, "Ball: "+reason, session.PriorityMedium)
+		if err != nil {
+			t.Fatalf("failed to create ball: %v", err)
+		}
+		ball.Tags = []string{"payments"}
+		if err := ball.SetBlocked(reason); err != nil {
+			t.Fatalf("failed to block ball: %v", err)
+		}
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("failed to save ball: %v", err)
+		}
+		balls = append(balls, ball)
+	}
+
+	filter, err := session.ParseBallFilter(`blocked_reason~"missing api key"`)
+	if err != nil {
+		t.Fatalf("failed to parse filter: %v", err)
+	}
+	matched, err := selectBallsToUnblock(nil, filter)
+	if err != nil {
+		t.Fatalf("selectBallsToUnblock failed: %v", err)
+	}
+	if len(matched) != 1 || matched[0].ID != balls[0].ID {
+		t.Fatalf("expected only the API key ball to match, got %d balls", len(matched))
+	}
+
+	err = unblockBalls(matched, session.StatePending, "Key added to env", func(ball *session.Ball, reason string, err error) error {
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unblockBalls failed: %v", err)
+	}
+	unblocked, err := store.GetBallByID(balls[0].ID)
+	if err != nil {
+		t.Fatalf("failed to load ball: %v", err)
+	}
+	if unblocked.State != session.StatePending || unblocked.BlockedReason != "" {
+		t.Errorf("expected pending with no reason, got %s %q", unblocked.State, unblocked.BlockedReason)
+	}
+	still, _ := store.GetBallByID(balls[1].ID)
+	if still.State != session.StateBlocked {
+		t.Errorf("expected the other ball to stay blocked, got %s", still.State)
+	}
+
+	progress, _ := sessionStore.LoadProgress("payments")
+	if !strings.Contains(progress, "[UNBLOCK]") || !strings.Contains(progress, "Key added to env") {
+		t.Errorf("expected unblock note in progress, got:\n%s", progress)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
@@ -20,12 +21,17 @@ var (
 	updateBlockReason   string
 	updateOutput        string
 	updateModelSize     string
+	updateKind          string
 	updateAgentProvider string
 	updateModelOverride string
+	updateTimeout       string
 	updateJSONFlag      bool
 	updateAddDep        []string
 	updateRemoveDep     []string
 	updateSetDeps       []string
+	updateEnv           []string
+	updateSetup         []string
+	updateTeardown      []string
 )
 
 var updateCmd = &cobra.Command{
@@ -46,11 +52,16 @@ Examples:
   juggle update my-app-1 --tags bug-fix,security
   juggle update my-app-1 --output "Research findings: ..."
   juggle update my-app-1 --model-size small
+  juggle update my-app-1 --kind docs
   juggle update my-app-1 --agent-provider opencode
   juggle update my-app-1 --model-override sonnet
+  juggle update my-app-1 --timeout 45m
   juggle update my-app-1 --add-dep other-ball-5
   juggle update my-app-1 --remove-dep other-ball-3
-  juggle update my-app-1 --set-deps ball-1,ball-2`,
+  juggle update my-app-1 --set-deps ball-1,ball-2
+  juggle update my-app-1 --env DATABASE_URL=postgres://localhost/test --env DEBUG=
+  juggle update my-app-1 --setup "docker compose up -d db" --teardown "docker compose down"
+  juggle update my-app-1 --setup ""`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: CompleteBallIDs,
 	RunE:              runUpdate,
@@ -65,12 +76,17 @@ func init() {
 	updateCmd.Flags().StringVar(&updateBlockReason, "reason", "", "Blocked reason (required when setting state to blocked)")
 	updateCmd.Flags().StringVar(&updateOutput, "output", "", "Set research output/results")
 	updateCmd.Flags().StringVar(&updateModelSize, "model-size", "", "Set preferred model size (small|medium|large)")
-	updateCmd.Flags().StringVar(&updateAgentProvider, "agent-provider", "", "Set agent provider override (claude|opencode, empty to clear)")
+	updateCmd.Flags().StringVar(&updateKind, "kind", "", "Set kind of work (code|docs|research|ops)")
+	updateCmd.Flags().StringVar(&updateAgentProvider, "agent-provider", "", "Set agent provider override (claude|opencode|goose|amp|<custom>, empty to clear)")
 	updateCmd.Flags().StringVar(&updateModelOverride, "model-override", "", "Set model override (opus|sonnet|haiku, empty to clear)")
+	updateCmd.Flags().StringVar(&updateTimeout, "timeout", "", "Set per-iteration timeout for this ball, overriding --timeout (e.g., 45m, 2h; 0 or empty to clear)")
 	updateCmd.Flags().BoolVar(&updateJSONFlag, "json", false, "Output updated ball as JSON")
 	updateCmd.Flags().StringSliceVar(&updateAddDep, "add-dep", nil, "Add dependency (ball ID, can be specified multiple times)")
 	updateCmd.Flags().StringSliceVar(&updateRemoveDep, "remove-dep", nil, "Remove dependency (ball ID, can be specified multiple times)")
 	updateCmd.Flags().StringSliceVar(&updateSetDeps, "set-deps", nil, "Replace all dependencies (comma-separated ball IDs)")
+	updateCmd.Flags().StringArrayVar(&updateEnv, "env", nil, "Set agent environment variable KEY=VALUE (KEY= removes it, can be specified multiple times)")
+	updateCmd.Flags().StringArrayVar(&updateSetup, "setup", nil, "Replace setup commands run before the agent (can be specified multiple times, \"\" clears)")
+	updateCmd.Flags().StringArrayVar(&updateTeardown, "teardown", nil, "Replace teardown commands run after the agent (can be specified multiple times, \"\" clears)")
 
 	// Add completion for flags
 	updateCmd.RegisterFlagCompletionFunc("priority", CompletePriorities)
@@ -80,8 +96,9 @@ func init() {
 	updateCmd.RegisterFlagCompletionFunc("model-size", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"small", "medium", "large"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	updateCmd.RegisterFlagCompletionFunc("kind", CompleteBallKinds)
 	updateCmd.RegisterFlagCompletionFunc("agent-provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"claude", "opencode"}, cobra.ShellCompDirectiveNoFileComp
+		return append([]string{"claude", "opencode", "goose", "amp"}, registerCustomProviders()...), cobra.ShellCompDirectiveNoFileComp
 	})
 	updateCmd.RegisterFlagCompletionFunc("model-override", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"opus", "sonnet", "haiku"}, cobra.ShellCompDirectiveNoFileComp
@@ -101,7 +118,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// If no flags provided (except --json), enter interactive mode
-	if updateIntent == "" && updatePriority == "" && updateState == "" && updateCriteria == nil && updateTags == "" && updateOutput == "" && updateModelSize == "" && updateAgentProvider == "" && updateModelOverride == "" && updateAddDep == nil && updateRemoveDep == nil && updateSetDeps == nil && !updateJSONFlag {
+	if updateIntent == "" && updatePriority == "" && updateState == "" && updateCriteria == nil && updateTags == "" && updateOutput == "" && updateModelSize == "" && updateKind == "" && updateAgentProvider == "" && updateModelOverride == "" && updateAddDep == nil && updateRemoveDep == nil && updateSetDeps == nil && updateEnv == nil && updateSetup == nil && updateTeardown == nil && !cmd.Flags().Changed("timeout") && !updateJSONFlag {
 		return runInteractiveUpdate(foundBall, foundStore)
 	}
 
@@ -224,9 +241,24 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if updateKind != "" {
+		if !session.ValidateBallKind(updateKind) {
+			err := fmt.Errorf("invalid kind: %s (must be code|docs|research|ops)", updateKind)
+			if updateJSONFlag {
+				return printJSONError(err)
+			}
+			return err
+		}
+		foundBall.SetKind(session.BallKind(updateKind))
+		modified = true
+		if !updateJSONFlag {
+			fmt.Printf("✓ Updated kind: %s\n", updateKind)
+		}
+	}
+
 	if cmd.Flags().Changed("agent-provider") {
-		if updateAgentProvider != "" && !session.ValidateAgentProvider(updateAgentProvider) {
-			err := fmt.Errorf("invalid agent provider: %s (must be claude|opencode)", updateAgentProvider)
+		if updateAgentProvider != "" && !session.ValidateAgentProvider(updateAgentProvider, registerCustomProviders()...) {
+			err := fmt.Errorf("invalid agent provider: %s (must be claude|opencode|goose|amp or a custom provider)", updateAgentProvider)
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
@@ -262,6 +294,60 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmd.Flags().Changed("timeout") {
+		timeout, err := parseBallTimeout(updateTimeout)
+		if err != nil {
+			if updateJSONFlag {
+				return printJSONError(err)
+			}
+			return err
+		}
+		foundBall.SetTimeout(timeout)
+		modified = true
+		if !updateJSONFlag {
+			if timeout == 0 {
+				fmt.Printf("✓ Cleared timeout override\n")
+			} else {
+				fmt.Printf("✓ Updated timeout: %s\n", formatBallTimeout(foundBall.Timeout()))
+			}
+		}
+	}
+
+	for _, assignment := range updateEnv {
+		key, value, err := session.ParseEnvAssignment(assignment)
+		if err != nil {
+			if updateJSONFlag {
+				return printJSONError(err)
+			}
+			return err
+		}
+		foundBall.SetEnvVar(key, value)
+		modified = true
+		if !updateJSONFlag {
+			if value == "" {
+				fmt.Printf("✓ Removed env: %s\n", key)
+			} else {
+				fmt.Printf("✓ Set env: %s\n", key)
+			}
+		}
+	}
+
+	if updateSetup != nil {
+		foundBall.SetSetupCommands(updateSetup)
+		modified = true
+		if !updateJSONFlag {
+			fmt.Printf("✓ Set %d setup command(s)\n", len(foundBall.Setup))
+		}
+	}
+
+	if updateTeardown != nil {
+		foundBall.SetTeardownCommands(updateTeardown)
+		modified = true
+		if !updateJSONFlag {
+			fmt.Printf("✓ Set %d teardown command(s)\n", len(foundBall.Teardown))
+		}
+	}
+
 	// Handle output separately (not tied to researched state)
 	if updateOutput != "" && updateState != "researched" {
 		foundBall.SetOutput(updateOutput)
@@ -331,25 +417,11 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	// Detect circular dependencies after any dependency modification
 	if depsModified {
-		balls, err := foundStore.LoadBalls()
-		if err != nil {
-			if updateJSONFlag {
-				return printJSONError(err)
-			}
-			return fmt.Errorf("failed to load balls for dependency check: %w", err)
-		}
-		// Replace the ball in the list with the modified version
-		for i, b := range balls {
-			if b.ID == foundBall.ID {
-				balls[i] = foundBall
-				break
-			}
-		}
-		if err := session.DetectCircularDependencies(balls); err != nil {
+		if err := checkDependencyCycles(foundStore, foundBall); err != nil {
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
-			return fmt.Errorf("dependency error: %w", err)
+			return err
 		}
 	}
 
@@ -526,14 +598,14 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 	if currentAgentProvider == "" {
 		currentAgentProvider = "unset"
 	}
-	fmt.Printf("Agent Provider [%s] (claude|opencode, 'clear' to remove): ", currentAgentProvider)
+	fmt.Printf("Agent Provider [%s] (claude|opencode|goose|amp, 'clear' to remove): ", currentAgentProvider)
 	input, _ = reader.ReadString('\n')
 	input = strings.TrimSpace(input)
 	if input != "" && input != "-" {
 		if input == "clear" {
 			ball.SetAgentProvider("")
 		} else {
-			if !session.ValidateAgentProvider(input) {
+			if !session.ValidateAgentProvider(input, registerCustomProviders()...) {
 				return fmt.Errorf("invalid agent provider: %s", input)
 			}
 			ball.SetAgentProvider(input)
@@ -591,10 +663,38 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 	if ball.ModelOverride != "" {
 		fmt.Printf("  Model Override: %s\n", ball.ModelOverride)
 	}
+	if ball.TimeoutMinutes > 0 {
+		fmt.Printf("  Timeout: %s\n", formatBallTimeout(ball.Timeout()))
+	}
 
 	return nil
 }
 
+// parseBallTimeout parses a --timeout value for a ball: a duration of at least
+// a minute, or 0 or empty to clear the override
+func parseBallTimeout(value string) (time.Duration, error) {
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout: %s (use a duration like 45m or 2h)", value)
+	}
+	if timeout < time.Minute {
+		return 0, fmt.Errorf("invalid timeout: %s (must be at least 1m, or 0 to clear)", value)
+	}
+	return timeout, nil
+}
+
+// formatBallTimeout shows a timeout without zero units, e.g. "45m" or "1h30m"
+func formatBallTimeout(timeout time.Duration) string {
+	s := strings.TrimSuffix(timeout.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
 // truncateForDisplay truncates a string to the given length with ellipsis
 func truncateForDisplay(s string, maxLen int) string {
 	if s == "" {
@@ -619,7 +719,7 @@ func resolveDependencyIDsForUpdate(store *session.Store, ids []string, excludeID
 		// Use prefix matching
 		matches := session.ResolveBallByPrefix(balls, id)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", session.ErrBallNotFound, id)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
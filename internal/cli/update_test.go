@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestParseBallTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"45m", 45 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"", 0, false},
+		{"0", 0, false},
+		{"30s", 0, true},
+		{"forever", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseBallTimeout(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseBallTimeout(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatBallTimeout(t *testing.T) {
+	for timeout, want := range map[time.Duration]string{
+		45 * time.Minute: "45m",
+		2 * time.Hour:    "2h",
+		90 * time.Minute: "1h30m",
+	} {
+		if got := formatBallTimeout(timeout); got != want {
+			t.Errorf("formatBallTimeout(%v) = %q, want %q", timeout, got, want)
+		}
+	}
+}
+
+func TestIterationTimeout(t *testing.T) {
+	ball := &session.Ball{}
+	if got := iterationTimeout(10*time.Minute, ball); got != 10*time.Minute {
+		t.Errorf("expected the run timeout without an override, got %v", got)
+	}
+	if got := iterationTimeout(10*time.Minute, nil); got != 10*time.Minute {
+		t.Errorf("expected the run timeout when no single ball is targeted, got %v", got)
+	}
+
+	ball.TimeoutMinutes = 45
+	if got := iterationTimeout(10*time.Minute, ball); got != 45*time.Minute {
+		t.Errorf("expected the ball's override, got %v", got)
+	}
+	if got := iterationTimeout(0, ball); got != 45*time.Minute {
+		t.Errorf("expected the override to apply without a run timeout, got %v", got)
+	}
+}
@@ -0,0 +1,315 @@
+// Package cronexpr parses standard five-field cron expressions and finds the
+// times they fire, or writes them as systemd calendar events and launchd
+// calendar intervals.
+//
+// The fields are minute (0-59), hour (0-23), day of month (1-31), month (1-12
+// or jan-dec) and day of week (0-7 or sun-sat, 0 and 7 are Sunday). Each field
+// is "*" or a comma-separated list of values and ranges ("1-5"), optionally
+// stepped ("*/15", "0-30/10", "5/20"). As in cron, when both day fields are
+// restricted a day matches if either does. The macros @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly are accepted too.
+package cronexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far Next looks for a match, so expressions that
+// can never fire, like "0 0 30 2 *", return the zero time
+const searchYears = 5
+
+// macros are the @ shorthands and the expressions they stand for
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes the values one position of an expression accepts
+type field struct {
+	name     string
+	min, max int
+	names    []string // Names for min, min+1, ... (months and weekdays)
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dowField    = field{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// Expr is a parsed cron expression
+type Expr struct {
+	source                       string
+	minute, hour, dom, month     uint64 // Bit n set when value n matches
+	dow                          uint64
+	domRestricted, dowRestricted bool
+}
+
+// Parse parses a five-field cron expression or an @ macro
+func Parse(spec string) (*Expr, error) {
+	source := strings.TrimSpace(spec)
+	expanded := source
+	if strings.HasPrefix(expanded, "@") {
+		m, ok := macros[strings.ToLower(expanded)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron macro %q", source)
+		}
+		expanded = m
+	}
+
+	parts := strings.Fields(expanded)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron expression %q has %d fields, want 5 (minute hour day-of-month month day-of-week)", source, len(parts))
+	}
+
+	e := &Expr{source: source}
+	var err error
+	if e.minute, err = minuteField.parse(parts[0]); err != nil {
+		return nil, err
+	}
+	if e.hour, err = hourField.parse(parts[1]); err != nil {
+		return nil, err
+	}
+	if e.dom, err = domField.parse(parts[2]); err != nil {
+		return nil, err
+	}
+	if e.month, err = monthField.parse(parts[3]); err != nil {
+		return nil, err
+	}
+	if e.dow, err = dowField.parse(parts[4]); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1
+	}
+	e.domRestricted = !strings.HasPrefix(parts[2], "*")
+	e.dowRestricted = !strings.HasPrefix(parts[4], "*")
+	return e, nil
+}
+
+// String returns the expression as it was given
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Next returns the first time after t, to the minute and in t's location,
+// that the expression fires. It returns the zero time if it never does.
+func (e *Expr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Year() + searchYears
+
+	for t.Year() <= limit {
+		if e.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !e.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if e.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if e.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's rule for the two day fields
+func (e *Expr) dayMatches(t time.Time) bool {
+	dom := e.dom&(1<<uint(t.Day())) != 0
+	dow := e.dow&(1<<uint(t.Weekday())) != 0
+	if e.domRestricted && e.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// weekdayNames are systemd's names for the days of the week, Sunday first
+var weekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// OnCalendar returns systemd calendar events (see systemd.time(7)) that
+// together fire when the expression does. When both day fields are
+// restricted there are two, as systemd requires both to match where cron
+// takes either.
+func (e *Expr) OnCalendar() []string {
+	clock := fmt.Sprintf("%s:%s:00", calendarValues(e.hour, 0, 23, nil), calendarValues(e.minute, 0, 59, nil))
+	month := calendarValues(e.month, 1, 12, nil)
+	event := func(dow, dom uint64) string {
+		days := calendarValues(dow, 0, 6, weekdayNames)
+		date := fmt.Sprintf("*-%s-%s %s", month, calendarValues(dom, 1, 31, nil), clock)
+		if days == "*" {
+			return date
+		}
+		return days + " " + date
+	}
+
+	const everyDOM, everyDOW = uint64(1<<32 - 2), uint64(1<<7 - 1)
+	if e.domRestricted && e.dowRestricted {
+		return []string{event(everyDOW, e.dom), event(e.dow, everyDOM)}
+	}
+	return []string{event(e.dow, e.dom)}
+}
+
+// calendarValues writes the values from lo to hi set in bits as a systemd
+// calendar component: "*" for all of them, else a list with runs as ranges
+func calendarValues(bits uint64, lo, hi int, names []string) string {
+	format := func(v int) string {
+		if names != nil {
+			return names[v-lo]
+		}
+		return fmt.Sprintf("%02d", v)
+	}
+
+	var parts []string
+	all := true
+	for v := lo; v <= hi; v++ {
+		if bits&(1<<uint(v)) == 0 {
+			all = false
+			continue
+		}
+		end := v
+		for end < hi && bits&(1<<uint(end+1)) != 0 {
+			end++
+		}
+		switch {
+		case end-v >= 2:
+			parts = append(parts, format(v)+".."+format(end))
+		case end > v:
+			parts = append(parts, format(v), format(end))
+		default:
+			parts = append(parts, format(v))
+		}
+		v = end
+	}
+	if all {
+		return "*"
+	}
+	return strings.Join(parts, ",")
+}
+
+// StartCalendarIntervals returns launchd StartCalendarInterval entries (see
+// launchd.plist(5)) that together fire when the expression does. Each maps
+// some of Minute, Hour, Day, Month and Weekday to a value; a field left out
+// matches any value, and a field with several values gets an entry for each.
+func (e *Expr) StartCalendarIntervals() []map[string]int {
+	entries := []map[string]int{{}}
+	entries = crossField(entries, "Month", e.month, 1, 12)
+	entries = crossField(entries, "Hour", e.hour, 0, 23)
+	entries = crossField(entries, "Minute", e.minute, 0, 59)
+	if e.domRestricted && e.dowRestricted {
+		return append(crossField(entries, "Day", e.dom, 1, 31), crossField(entries, "Weekday", e.dow, 0, 6)...)
+	}
+	return crossField(crossField(entries, "Day", e.dom, 1, 31), "Weekday", e.dow, 0, 6)
+}
+
+// crossField gives each entry a copy for every value from lo to hi set in
+// bits, or leaves them as they are when the field takes every value
+func crossField(entries []map[string]int, key string, bits uint64, lo, hi int) []map[string]int {
+	var values []int
+	for v := lo; v <= hi; v++ {
+		if bits&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+	if len(values) == hi-lo+1 {
+		return entries
+	}
+
+	crossed := make([]map[string]int, 0, len(entries)*len(values))
+	for _, entry := range entries {
+		for _, v := range values {
+			next := make(map[string]int, len(entry)+1)
+			for k, x := range entry {
+				next[k] = x
+			}
+			next[key] = v
+			crossed = append(crossed, next)
+		}
+	}
+	return crossed
+}
+
+// parse turns one field of an expression into a bitset of matching values
+func (f field) parse(spec string) (uint64, error) {
+	var bits uint64
+	for _, term := range strings.Split(spec, ",") {
+		rangePart, stepPart, stepped := strings.Cut(term, "/")
+
+		step := 1
+		if stepped {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field %q", stepPart, f.name, spec)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(loPart, spec); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(hiPart, spec); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("range %q in %s field %q runs backwards", rangePart, f.name, spec)
+			}
+		default:
+			var err error
+			if lo, err = f.value(rangePart, spec); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// "5/20" means from 5 to the end in steps of 20
+			if stepped {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a number or name within the field's bounds
+func (f field) value(s, spec string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field %q", s, f.name, spec)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
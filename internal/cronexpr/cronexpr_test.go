@@ -0,0 +1,133 @@
+package cronexpr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// Friday 2026-01-02 15:04
+	from := time.Date(2026, 1, 2, 15, 4, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 2, 15, 5, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 1, 3, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 2, 15, 15, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2026, 1, 5, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 feb *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"5/20 16 * * *", time.Date(2026, 1, 2, 16, 5, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 13th or any Saturday
+		{"0 8 13 * sat", time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := expr.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestNextKeepsLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	expr, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := expr.Next(time.Date(2026, 1, 2, 1, 0, 0, 0, loc))
+	if want := time.Date(2026, 1, 2, 2, 0, 0, 0, loc); !got.Equal(want) || got.Location() != loc {
+		t.Errorf("Expected 02:00 in the given zone, got %v", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@fortnightly",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", spec)
+		}
+	}
+}
+
+func TestOnCalendar(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"0 2 * * *", []string{"*-*-* 02:00:00"}},
+		{"*/15 * * * *", []string{"*-*-* *:00,15,30,45:00"}},
+		{"30 9 * * mon-fri", []string{"Mon..Fri *-*-* 09:30:00"}},
+		{"0 0 * * 0,6", []string{"Sun,Sat *-*-* 00:00:00"}},
+		{"0 0 * * 7", []string{"Sun *-*-* 00:00:00"}},
+		{"0 12 1,15 feb-apr *", []string{"*-02..04-01,15 12:00:00"}},
+		{"0 8-9 */2 * *", []string{"*-*-01,03,05,07,09,11,13,15,17,19,21,23,25,27,29,31 08,09:00:00"}},
+		// Both day fields restricted: the 13th or any Saturday
+		{"0 8 13 * sat", []string{"*-*-13 08:00:00", "Sat *-*-* 08:00:00"}},
+		{"@weekly", []string{"Sun *-*-* 00:00:00"}},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := expr.OnCalendar(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("OnCalendar(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestStartCalendarIntervals(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []map[string]int
+	}{
+		{"0 2 * * *", []map[string]int{{"Hour": 2, "Minute": 0}}},
+		{"* * * * *", []map[string]int{{}}},
+		{"30 9 * * 1,3", []map[string]int{
+			{"Hour": 9, "Minute": 30, "Weekday": 1},
+			{"Hour": 9, "Minute": 30, "Weekday": 3},
+		}},
+		{"0,30 * * jan *", []map[string]int{{"Month": 1, "Minute": 0}, {"Month": 1, "Minute": 30}}},
+		// Both day fields restricted: the 13th or any Saturday
+		{"0 8 13 * sat", []map[string]int{
+			{"Hour": 8, "Minute": 0, "Day": 13},
+			{"Hour": 8, "Minute": 0, "Weekday": 6},
+		}},
+		{"@weekly", []map[string]int{{"Hour": 0, "Minute": 0, "Weekday": 0}}},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := expr.StartCalendarIntervals(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("StartCalendarIntervals(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
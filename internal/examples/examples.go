@@ -0,0 +1,114 @@
+// Package examples holds runnable, copy-pastable workflow examples (overnight
+// runs, spec import, parallel worktrees) embedded as markdown, shared by the
+// `juggle examples` command and the TUI help view.
+package examples
+
+import (
+	"embed"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed docs/*.md
+var docs embed.FS
+
+// Example is one workflow, parsed from docs/<topic>.md
+type Example struct {
+	Topic   string // File name without extension, e.g. "overnight"
+	Title   string // Text of the first "# " heading
+	Summary string // First paragraph after the title
+	Body    string // Full markdown source
+}
+
+// All returns every embedded example, sorted by topic
+func All() []Example {
+	entries, err := docs.ReadDir("docs")
+	if err != nil {
+		return nil
+	}
+
+	examples := make([]Example, 0, len(entries))
+	for _, entry := range entries {
+		data, err := docs.ReadFile(path.Join("docs", entry.Name()))
+		if err != nil {
+			continue
+		}
+		examples = append(examples, parse(strings.TrimSuffix(entry.Name(), ".md"), string(data)))
+	}
+
+	sort.Slice(examples, func(i, j int) bool {
+		return examples[i].Topic < examples[j].Topic
+	})
+	return examples
+}
+
+// Find returns the example for a topic, ignoring case
+func Find(topic string) (Example, bool) {
+	for _, example := range All() {
+		if strings.EqualFold(example.Topic, topic) {
+			return example, true
+		}
+	}
+	return Example{}, false
+}
+
+// Topics returns the topic names of every example
+func Topics() []string {
+	all := All()
+	topics := make([]string, len(all))
+	for i, example := range all {
+		topics[i] = example.Topic
+	}
+	return topics
+}
+
+// Commands returns the lines of the example's fenced code blocks, with a blank
+// line between blocks. Comment lines are kept since they explain each command.
+func (e Example) Commands() []string {
+	var commands []string
+	inFence := false
+	for _, line := range strings.Split(e.Body, "\n") {
+		if strings.HasPrefix(line, "```") {
+			if inFence && len(commands) > 0 && commands[len(commands)-1] != "" {
+				commands = append(commands, "")
+			}
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			commands = append(commands, line)
+		}
+	}
+
+	// Drop the separator after the last block
+	if len(commands) > 0 && commands[len(commands)-1] == "" {
+		commands = commands[:len(commands)-1]
+	}
+	return commands
+}
+
+// parse extracts the title and summary from an example's markdown
+func parse(topic, body string) Example {
+	example := Example{Topic: topic, Title: topic, Body: body}
+
+	var summary []string
+	seenTitle := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !seenTitle && strings.HasPrefix(trimmed, "# "):
+			example.Title = strings.TrimPrefix(trimmed, "# ")
+			seenTitle = true
+		case !seenTitle:
+			continue
+		case trimmed == "" && len(summary) > 0, strings.HasPrefix(trimmed, "```"):
+			example.Summary = strings.Join(summary, " ")
+			return example
+		case trimmed != "":
+			summary = append(summary, trimmed)
+		}
+	}
+	example.Summary = strings.Join(summary, " ")
+	return example
+}
@@ -0,0 +1,63 @@
+package examples
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAll_ParsesEmbeddedExamples(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("Expected embedded examples")
+	}
+
+	for _, example := range all {
+		if example.Title == example.Topic {
+			t.Errorf("Example %q has no title heading", example.Topic)
+		}
+		if example.Summary == "" {
+			t.Errorf("Example %q has no summary", example.Topic)
+		}
+		if len(example.Commands()) == 0 {
+			t.Errorf("Example %q has no commands", example.Topic)
+		}
+	}
+
+	for _, topic := range []string{"overnight", "spec-import", "parallel"} {
+		if _, ok := Find(topic); !ok {
+			t.Errorf("Expected example for topic %q", topic)
+		}
+	}
+}
+
+func TestFind_IgnoresCase(t *testing.T) {
+	example, ok := Find("Overnight")
+	if !ok {
+		t.Fatal("Expected to find overnight example")
+	}
+	if example.Topic != "overnight" {
+		t.Errorf("Expected topic overnight, got %q", example.Topic)
+	}
+
+	if _, ok := Find("nope"); ok {
+		t.Error("Expected unknown topic not to be found")
+	}
+}
+
+func TestParse(t *testing.T) {
+	body := "# Title here\n\nFirst line of\nthe summary.\n\nMore text.\n\n```bash\n# step one\njuggle a\n```\n\nBetween.\n\n```bash\njuggle b\n```\n"
+	example := parse("topic", body)
+
+	if example.Title != "Title here" {
+		t.Errorf("Title = %q", example.Title)
+	}
+	if example.Summary != "First line of the summary." {
+		t.Errorf("Summary = %q", example.Summary)
+	}
+
+	got := strings.Join(example.Commands(), "|")
+	want := "# step one|juggle a||juggle b"
+	if got != want {
+		t.Errorf("Commands = %q, want %q", got, want)
+	}
+}
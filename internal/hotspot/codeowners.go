@@ -0,0 +1,134 @@
+package hotspot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeOwnersLocations are where GitHub and GitLab look for a CODEOWNERS file,
+// in the order they check
+var codeOwnersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// codeOwnersRule is one pattern line of a CODEOWNERS file
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// CodeOwners holds the rules of a CODEOWNERS file. A nil *CodeOwners owns
+// nothing.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// LoadCodeOwners reads the project's CODEOWNERS file. It returns nil when
+// the project has none.
+func LoadCodeOwners(projectDir string) (*CodeOwners, error) {
+	for _, location := range codeOwnersLocations {
+		f, err := os.Open(filepath.Join(projectDir, filepath.FromSlash(location)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", location, err)
+		}
+		defer f.Close()
+		owners, err := ParseCodeOwners(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", location, err)
+		}
+		return owners, nil
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses CODEOWNERS rules: a gitignore-style pattern
+// followed by owners. Comments, blank lines and GitLab section headers are
+// skipped.
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	owners := &CodeOwners{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		pattern, err := compileOwnersPattern(fields[0])
+		if err != nil {
+			continue // Skip patterns we can't understand rather than failing the whole file
+		}
+		owners.rules = append(owners.rules, codeOwnersRule{pattern: pattern, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// Match returns the owners of a slash-separated project-relative path. As in
+// GitHub, the last matching rule wins, so a later rule with no owners
+// unassigns the path.
+func (c *CodeOwners) Match(p string) []string {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(p) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compileOwnersPattern turns a gitignore-style pattern into a regexp over
+// project-relative paths. Patterns with a leading or inner "/" are anchored
+// to the project root, others match at any depth. A matching directory
+// matches everything under it, except that "dir/*" only matches dir's
+// direct children.
+func compileOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var re strings.Builder
+	if anchored {
+		re.WriteString("^")
+	} else {
+		re.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "/**"):
+			re.WriteString("(?:/.*)?")
+			i += 2
+		case trimmed[i] == '*':
+			re.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(trimmed[i])))
+		}
+	}
+	if strings.HasSuffix(trimmed, "/*") {
+		re.WriteString("$")
+	} else {
+		re.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(re.String())
+}
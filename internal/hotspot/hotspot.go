@@ -0,0 +1,103 @@
+// Package hotspot scores the paths a ball affects by how much they matter:
+// recent commit churn and CODEOWNERS ownership. Maintenance sessions use the
+// score to work on hot paths, where bugs hurt most, first.
+//
+// A ball's paths come from the file and directory names mentioned in its
+// text that exist in the project, plus files agents touched while working on
+// it. A path's churn is the number of recent commits touching it, or anything
+// under it for a directory; paths with a CODEOWNERS owner get a fixed bonus.
+package hotspot
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OwnedWeight is added to the score of each path with a CODEOWNERS owner
+const OwnedWeight = 5
+
+// Path is one of a ball's affected paths with its signals
+type Path struct {
+	Path   string   // Slash-separated, relative to the project
+	Churn  int      // Recent commits touching the path
+	Owners []string // CODEOWNERS owners, if any
+}
+
+// Result is the hotness of a set of paths
+type Result struct {
+	Score int
+	Paths []Path // Hottest first
+}
+
+// Owners returns the distinct owners of the result's paths, in order of
+// first appearance
+func (r Result) Owners() []string {
+	var owners []string
+	seen := make(map[string]bool)
+	for _, p := range r.Paths {
+		for _, owner := range p.Owners {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// Analyze scores paths by churn, the commit counts per file from the VCS,
+// and ownership
+func Analyze(paths []string, churn map[string]int, owners *CodeOwners) Result {
+	var result Result
+	for _, p := range paths {
+		hot := Path{Path: p, Owners: owners.Match(p)}
+		for file, commits := range churn {
+			if file == p || strings.HasPrefix(file, p+"/") {
+				hot.Churn += commits
+			}
+		}
+		result.Score += hot.Churn
+		if len(hot.Owners) > 0 {
+			result.Score += OwnedWeight
+		}
+		result.Paths = append(result.Paths, hot)
+	}
+
+	sort.SliceStable(result.Paths, func(i, j int) bool {
+		if result.Paths[i].Churn != result.Paths[j].Churn {
+			return result.Paths[i].Churn > result.Paths[j].Churn
+		}
+		return result.Paths[i].Path < result.Paths[j].Path
+	})
+	return result
+}
+
+// pathTrim is stripped from both ends of a word before it is checked as a path
+const pathTrim = "`'\"()[]{}<>,;:!?*"
+
+// ExtractPaths returns the words in text that name a file or directory in the
+// project, in order of first appearance. Only words containing a "/" or "."
+// count, so plain words that happen to match a directory name are ignored.
+func ExtractPaths(projectDir, text string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		word = strings.TrimRight(strings.Trim(word, pathTrim), ".")
+		if !strings.ContainsAny(word, "/.") || filepath.IsAbs(word) {
+			continue
+		}
+		p := path.Clean(strings.TrimPrefix(word, "./"))
+		if p == "." || strings.HasPrefix(p, "..") || p == ".juggle" || strings.HasPrefix(p, ".juggle/") || seen[p] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(projectDir, filepath.FromSlash(p))); err != nil {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
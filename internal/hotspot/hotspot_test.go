@@ -0,0 +1,121 @@
+package hotspot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodeOwnersMatch(t *testing.T) {
+	owners, err := ParseCodeOwners(strings.NewReader(`# Default owners
+*                   @everyone
+*.go                @gophers   # Go code
+/internal/vcs/      @vcs-team
+docs/*              @writers
+**/testdata/**      @qa
+/internal/vcs/jj.go
+`))
+	if err != nil {
+		t.Fatalf("ParseCodeOwners failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@everyone"}},
+		{"cmd/main.go", []string{"@gophers"}},
+		{"internal/vcs", []string{"@vcs-team"}},
+		{"internal/vcs/git.go", []string{"@vcs-team"}},
+		{"internal/vcs/jj.go", []string{}},
+		{"docs/commands.md", []string{"@writers"}},
+		{"docs/guides/setup.md", []string{"@everyone"}},
+		{"internal/cli/testdata/golden.txt", []string{"@qa"}},
+	}
+	for _, tt := range tests {
+		if got := owners.Match(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var none *CodeOwners
+	if got := none.Match("README.md"); got != nil {
+		t.Errorf("expected a nil CodeOwners to own nothing, got %v", got)
+	}
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	tmpDir := t.TempDir()
+	owners, err := LoadCodeOwners(tmpDir)
+	if err != nil || owners != nil {
+		t.Fatalf("expected no CODEOWNERS, got %v (err %v)", owners, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".github"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".github", "CODEOWNERS"), []byte("*.go @gophers\n"), 0644); err != nil {
+		t.Fatalf("failed to write CODEOWNERS: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "CODEOWNERS"), []byte("* @ignored\n"), 0644); err != nil {
+		t.Fatalf("failed to write CODEOWNERS: %v", err)
+	}
+
+	owners, err = LoadCodeOwners(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadCodeOwners failed: %v", err)
+	}
+	if got := owners.Match("main.go"); !reflect.DeepEqual(got, []string{"@gophers"}) {
+		t.Errorf("expected .github/CODEOWNERS to take precedence, got %v", got)
+	}
+}
+
+func TestExtractPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"internal/cli/agent.go", "README.md", ".juggle/balls.jsonl"} {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	text := "Fix `internal/cli/agent.go` and update README.md. See ./internal/cli/, internal " +
+		"(not missing/file.go or .juggle/balls.jsonl); agent.go again: internal/cli/agent.go"
+	got := ExtractPaths(tmpDir, text)
+	want := []string{"internal/cli/agent.go", "README.md", "internal/cli"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	churn := map[string]int{
+		"internal/cli/agent.go": 9,
+		"internal/cli/show.go":  2,
+		"README.md":             1,
+	}
+	owners, err := ParseCodeOwners(strings.NewReader("/internal/vcs/ @vcs-team\nREADME.md @writers\n"))
+	if err != nil {
+		t.Fatalf("ParseCodeOwners failed: %v", err)
+	}
+
+	result := Analyze([]string{"README.md", "internal/vcs", "internal/cli"}, churn, owners)
+	if result.Score != 11+1+OwnedWeight*2 {
+		t.Errorf("expected score %d, got %d", 11+1+OwnedWeight*2, result.Score)
+	}
+	var order []string
+	for _, p := range result.Paths {
+		order = append(order, p.Path)
+	}
+	if !reflect.DeepEqual(order, []string{"internal/cli", "README.md", "internal/vcs"}) {
+		t.Errorf("expected paths hottest first, got %v", order)
+	}
+	if !reflect.DeepEqual(result.Owners(), []string{"@writers", "@vcs-team"}) {
+		t.Errorf("expected owners [@writers @vcs-team], got %v", result.Owners())
+	}
+}
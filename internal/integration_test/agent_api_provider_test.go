@@ -0,0 +1,50 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+func TestAgentLoop_RejectsToolLessProvider(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	runner := &fileWritingMockRunner{env: env, ballID: ball.ID}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Provider:      "api",
+	})
+	if err == nil || !strings.Contains(err.Error(), "advice mode") {
+		t.Fatalf("Expected the api provider to be rejected for the agent loop, got %v", err)
+	}
+}
+
+func TestAgentLoop_APIProviderNeedsKey(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Provider:      "api",
+	})
+	if err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY is not set") {
+		t.Fatalf("Expected a missing key error, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+// cancellingRunner cancels the run's context while the agent is running,
+// the way a daemon or API server would stop a run mid-iteration
+type cancellingRunner struct {
+	cancel context.CancelFunc
+	calls  int
+	ctxSet bool
+}
+
+func (m *cancellingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	m.ctxSet = opts.Context != nil
+	m.cancel()
+	return &agent.RunResult{Output: "partial output"}, nil
+}
+
+func TestAgentLoop_ContextCancelledMidIteration(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner := &cancellingRunner{cancel: cancel}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Expected cancelled run to end cleanly, got error: %v", err)
+	}
+	if !runner.ctxSet {
+		t.Error("Expected the run context to be passed to the runner")
+	}
+	if runner.calls != 1 {
+		t.Errorf("Expected run to stop after the cancelled iteration, got %d agent calls", runner.calls)
+	}
+	if !result.Blocked || !strings.Contains(result.BlockedReason, "Cancelled") {
+		t.Errorf("Expected cancelled result, got %+v", result)
+	}
+	if result.Complete {
+		t.Error("Expected cancelled run not to be complete")
+	}
+}
+
+func TestAgentLoop_ContextCancelledBeforeStart(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	runner := &cancellingRunner{cancel: func() {}}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected cancelled run to end cleanly, got error: %v", err)
+	}
+	if runner.calls != 0 {
+		t.Errorf("Expected agent not to run, got %d calls", runner.calls)
+	}
+	if !result.Blocked {
+		t.Errorf("Expected cancelled result, got %+v", result)
+	}
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %s failed: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// slowRunner leaves uncommitted work and keeps running until its context ends
+type slowRunner struct {
+	env   *TestEnv
+	calls int
+}
+
+func (m *slowRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	if err := os.WriteFile(filepath.Join(m.env.ProjectDir, "half-done.go"), []byte("package main\n"), 0644); err != nil {
+		return nil, err
+	}
+	select {
+	case <-opts.Context.Done():
+	case <-time.After(10 * time.Second):
+	}
+	return &agent.RunResult{Output: "partial output"}, nil
+}
+
+func TestAgentLoop_MaxDurationIsolatesWork(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+	head := gitOutput(t, env.ProjectDir, "rev-parse", "HEAD")
+
+	runner := &slowRunner{env: env}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+		MaxDuration:   300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Expected the run to end cleanly, got error: %v", err)
+	}
+	if runner.calls != 1 {
+		t.Errorf("Expected the run to stop during the first iteration, got %d agent calls", runner.calls)
+	}
+	if !result.TimedOut || !strings.Contains(result.TimeoutMessage, "max duration") || result.Blocked {
+		t.Errorf("Expected a max-duration timeout, got %+v", result)
+	}
+
+	if _, err := os.Stat(filepath.Join(env.ProjectDir, "half-done.go")); !os.IsNotExist(err) {
+		t.Error("Expected uncommitted work to be isolated out of the working copy")
+	}
+	if now := gitOutput(t, env.ProjectDir, "rev-parse", "HEAD"); now != head {
+		t.Errorf("Expected the branch to stay at %s, got %s", head, now)
+	}
+	if branches := gitOutput(t, env.ProjectDir, "branch", "--list", "blocked-*"); !strings.Contains(branches, "blocked-") {
+		t.Error("Expected the work to be kept on a blocked-* branch")
+	}
+}
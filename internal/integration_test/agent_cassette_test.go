@@ -0,0 +1,85 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAgentLoop_ReplaysCassette(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+
+	env.CreateSession(t, "test-session", "CSV report")
+	store := env.GetStore(t)
+	for _, title := range []string{"Parse CSV rows", "Write summary report"} {
+		ball := env.CreateInProgressBall(t, title, session.PriorityMedium)
+		ball.Tags = []string{"test-session"}
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	runner, err := agent.NewCassetteRunner(filepath.Join("testdata", "cassettes", "two_ball_session.json"), env.ProjectDir, agent.DefaultRunner)
+	if err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+	runner.Strict = true
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 5,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if runner.Recording() {
+		return
+	}
+
+	if !result.Complete {
+		t.Errorf("Expected run to complete, got %+v", result)
+	}
+	if result.Iterations != 3 {
+		t.Errorf("Expected 3 iterations, got %d", result.Iterations)
+	}
+	if runner.Remaining() != 0 {
+		t.Errorf("Expected every interaction replayed, %d left", runner.Remaining())
+	}
+	if result.InputTokens != 48210+51877+53002 || result.OutputTokens != 3120+2405+1988 {
+		t.Errorf("Expected usage summed over the transcript, got %d in / %d out", result.InputTokens, result.OutputTokens)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	for _, want := range []string{"[ITER 1]", "[ITER 2]", "[ITER 3]"} {
+		if !strings.Contains(progress, want) {
+			t.Errorf("Expected progress to contain %q, got:\n%s", want, progress)
+		}
+	}
+}
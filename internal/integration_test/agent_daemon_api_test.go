@@ -0,0 +1,162 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/pkg/daemonapi"
+)
+
+func TestAgentLoop_DaemonAPIStreamsStateAndTakesControl(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "done", Continue: true})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	// Hold the loop before its first iteration so the test can resume it over the API
+	if err := daemon.SendControlCommand(env.ProjectDir, "test-session", daemon.CmdTakeover, ""); err != nil {
+		t.Fatalf("Failed to send takeover: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	states := make(chan *daemon.State, 100)
+	watchErr := make(chan error, 1)
+	go func() {
+		defer close(states)
+		var client *daemon.APIClient
+		for client == nil {
+			var err error
+			if client, err = daemon.DialAPI(env.ProjectDir, "test-session"); err != nil {
+				if ctx.Err() != nil {
+					watchErr <- err
+					return
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		}
+		defer client.Close()
+
+		stream, err := client.WatchState(ctx, &daemonapi.GetStateRequest{ProjectDir: env.ProjectDir, SessionId: "test-session"})
+		if err != nil {
+			watchErr <- err
+			return
+		}
+		resumed := false
+		for {
+			p, err := stream.Recv()
+			if err != nil {
+				return // The loop ended
+			}
+			state := daemon.StateFromProto(p)
+			states <- state
+			if state.Status == daemon.StatusTakeover && !resumed {
+				resumed = true
+				_, err := client.SendControl(ctx, &daemonapi.SendControlRequest{
+					ProjectDir: env.ProjectDir,
+					SessionId:  "test-session",
+					Command:    daemon.CmdResume,
+				})
+				if err != nil {
+					watchErr <- err
+					return
+				}
+			}
+		}
+	}()
+
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		DaemonMode:    true,
+	})
+	if err != nil {
+		t.Fatalf("RunAgentLoop failed: %v", err)
+	}
+	if result.Blocked {
+		t.Fatalf("Expected the API resume to release the loop, got blocked: %s", result.BlockedReason)
+	}
+
+	var received []*daemon.State
+	for state := range states {
+		received = append(received, state)
+	}
+	select {
+	case err := <-watchErr:
+		t.Fatalf("Watching over the API failed: %v", err)
+	default:
+	}
+	if len(received) == 0 {
+		t.Fatal("Expected states over the API")
+	}
+	sawTakeover := false
+	for _, state := range received {
+		if state.Status == daemon.StatusTakeover {
+			sawTakeover = true
+		}
+	}
+	if !sawTakeover {
+		t.Error("Expected the takeover hold to be streamed")
+	}
+	if last := received[len(received)-1]; last.Running {
+		t.Errorf("Expected the stream to end with the final state, got %+v", last)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected the iteration to run after resuming, got %d agent calls", len(mock.Calls))
+	}
+}
+
+func TestAgentLoop_DaemonCancelSavesHistory(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "done", Continue: true})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	// Cancel before the first iteration, as the monitor TUI does
+	if err := daemon.SendControlCommand(env.ProjectDir, "test-session", daemon.CmdCancel, ""); err != nil {
+		t.Fatalf("Failed to send cancel: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		DaemonMode:    true,
+	})
+	if err != nil {
+		t.Fatalf("RunAgentLoop failed: %v", err)
+	}
+	if !result.Blocked || result.EndedAt.IsZero() {
+		t.Fatalf("Expected the cancel to end the run, got %+v", result)
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(records) != 1 || records[0].BlockedReason != result.BlockedReason {
+		t.Errorf("Expected the cancelled run in the history, got %+v", records)
+	}
+}
@@ -0,0 +1,131 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// largeDiffMockRunner completes a ball with a diff larger than the configured limit
+type largeDiffMockRunner struct {
+	env    *TestEnv
+	ballID string
+}
+
+func (m *largeDiffMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	store, err := session.NewStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	ball, err := store.GetBallByID(m.ballID)
+	if err != nil {
+		return nil, err
+	}
+	ball.MarkComplete("done")
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, err
+	}
+
+	content := strings.Repeat("generated line\n", 50)
+	if err := os.WriteFile(filepath.Join(m.env.ProjectDir, "big.txt"), []byte(content), 0644); err != nil {
+		return nil, err
+	}
+
+	sessionStore, err := session.NewSessionStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("test-session", "Completed ball\n"); err != nil {
+		return nil, err
+	}
+
+	return &agent.RunResult{
+		Output:        "<promise>COMPLETE: feat: add big file</promise>",
+		Complete:      true,
+		CommitMessage: "feat: add big file",
+	}, nil
+}
+
+func TestAgentLoop_DiffLimitBlocksAutoCommit(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.DiffLimit = &session.DiffLimitConfig{MaxLines: 10}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for diff limit")
+	ball := env.CreateInProgressBall(t, "Big change", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.SetRunner(&largeDiffMockRunner{env: env, ballID: ball.ID})
+	defer agent.ResetRunner()
+
+	_, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	// No commit on top of the initial one
+	out, err := exec.Command("git", "-C", env.ProjectDir, "log", "--oneline", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if commits := strings.Count(strings.TrimSpace(string(out)), "\n") + 1; commits != 1 {
+		t.Errorf("Expected only the initial commit, got %d:\n%s", commits, out)
+	}
+
+	// Work is isolated out of the working copy
+	if _, err := os.Stat(filepath.Join(env.ProjectDir, "big.txt")); !os.IsNotExist(err) {
+		t.Error("Expected oversized work to be isolated from the working copy")
+	}
+
+	updated, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if updated.State != session.StateBlocked {
+		t.Errorf("Expected ball to be blocked, got %s", updated.State)
+	}
+	if !strings.Contains(updated.BlockedReason, "needs review") {
+		t.Errorf("Expected needs review blocked reason, got %q", updated.BlockedReason)
+	}
+	hasTag := false
+	for _, tag := range updated.Tags {
+		if tag == "needs-review" {
+			hasTag = true
+		}
+	}
+	if !hasTag {
+		t.Errorf("Expected needs-review tag, got %v", updated.Tags)
+	}
+}
@@ -0,0 +1,57 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/vcs"
+)
+
+func TestAgentLoop_RecordsRunRevisions(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files:  map[string]string{"config.go": "package config\n"},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	record, err := historyStore.FindRecord("test-session")
+	if err != nil {
+		t.Fatalf("Expected the run in history: %v", err)
+	}
+	if record.StartRevision == "" || record.EndRevision == "" || record.StartRevision == record.EndRevision {
+		t.Fatalf("Expected distinct start and end revisions, got %q..%q", record.StartRevision, record.EndRevision)
+	}
+	if record.ReportFile != result.ReportPath {
+		t.Errorf("Expected the record to link report %s, got %s", result.ReportPath, record.ReportFile)
+	}
+
+	diff, err := vcs.NewGitBackend().DiffRevisions(env.ProjectDir, record.StartRevision, record.EndRevision)
+	if err != nil {
+		t.Fatalf("DiffRevisions failed: %v", err)
+	}
+	if files := vcs.SummarizeDiff(diff).Files; len(files) != 1 || files[0] != "config.go" {
+		t.Errorf("Expected the run's diff to change config.go only, got %v:\n%s", files, diff)
+	}
+}
@@ -0,0 +1,92 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// docsEditingRunner rewrites the README during its first iteration, as an
+// agent updating the docs would
+type docsEditingRunner struct {
+	readme  string
+	prompts []string
+}
+
+func (m *docsEditingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.prompts = append(m.prompts, opts.Prompt)
+	if len(m.prompts) == 1 {
+		if err := os.WriteFile(m.readme, []byte("# MyApp\n\nMyApp now syncs calendars too.\n"), 0644); err != nil {
+			return nil, err
+		}
+		// Give the watcher time to see the change before the next iteration
+		time.Sleep(300 * time.Millisecond)
+	}
+	return &agent.RunResult{Output: "working", Continue: true}, nil
+}
+
+func TestAgentLoop_DocsBriefingFollowsContextDocs(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for the docs briefing")
+	readme := filepath.Join(env.ProjectDir, "README.md")
+	if err := os.WriteFile(readme, []byte("# MyApp\n\nMyApp syncs notes between devices.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if err := sessionStore.UpdateSessionContextDocs("test-session", []string{"README.md"}); err != nil {
+		t.Fatalf("Failed to set context docs: %v", err)
+	}
+
+	ball := env.CreateInProgressBall(t, "Add calendar sync", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	runner := &docsEditingRunner{readme: readme}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	_, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 2,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.prompts) != 2 {
+		t.Fatalf("Expected 2 iterations, got %d", len(runner.prompts))
+	}
+
+	// The run builds the briefing before the first prompt
+	if !strings.Contains(runner.prompts[0], "Project docs briefing (from README.md)") ||
+		!strings.Contains(runner.prompts[0], "MyApp syncs notes between devices.") {
+		t.Errorf("Expected the first prompt to include the docs briefing, got:\n%s", runner.prompts[0])
+	}
+	// ...and refreshes it once the README changes mid-run
+	if !strings.Contains(runner.prompts[1], "MyApp now syncs calendars too.") {
+		t.Errorf("Expected the second prompt to include the refreshed briefing, got:\n%s", runner.prompts[1])
+	}
+
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[DOCS]") {
+		t.Errorf("Expected the refresh logged to progress, got:\n%s", progress)
+	}
+}
@@ -0,0 +1,135 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+// envRecordingRunner records the environment passed to each run and appends a
+// line to the shared log so ordering against setup/teardown can be checked
+type envRecordingRunner struct {
+	fileWritingMockRunner
+	logPath string
+	envs    [][]string
+}
+
+func (m *envRecordingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.envs = append(m.envs, opts.Env)
+	f, err := os.OpenFile(m.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	f.WriteString("agent\n")
+	f.Close()
+	return m.fileWritingMockRunner.Run(opts)
+}
+
+func TestAgentLoop_BallEnvironmentAndSetup(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	logPath := filepath.Join(t.TempDir(), "env.log")
+
+	ball := setupGatedProject(t, env, nil)
+	ball.SetEnvVar("DB_NAME", "ball_db")
+	ball.SetEnvVar("SHARED", "from_ball")
+	ball.SetSetupCommands([]string{`echo "ball setup $DB_NAME $SESSION_ONLY" >> ` + logPath})
+	ball.SetTeardownCommands([]string{`echo "ball teardown" >> ` + logPath})
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	sessionStore := env.GetSessionStore(t)
+	for key, value := range map[string]string{"SESSION_ONLY": "yes", "SHARED": "from_session"} {
+		if err := sessionStore.UpdateSessionEnvVar("test-session", key, value); err != nil {
+			t.Fatalf("Failed to set session env: %v", err)
+		}
+	}
+	if err := sessionStore.UpdateSessionSetup("test-session", []string{`echo "session setup" >> ` + logPath}); err != nil {
+		t.Fatalf("Failed to set session setup: %v", err)
+	}
+	if err := sessionStore.UpdateSessionTeardown("test-session", []string{`echo "session teardown" >> ` + logPath}); err != nil {
+		t.Fatalf("Failed to set session teardown: %v", err)
+	}
+
+	runner := &envRecordingRunner{
+		fileWritingMockRunner: fileWritingMockRunner{env: env, ballID: ball.ID},
+		logPath:               logPath,
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if len(runner.envs) != 1 {
+		t.Fatalf("Expected 1 agent run, got %d", len(runner.envs))
+	}
+	for _, want := range []string{"DB_NAME=ball_db", "SESSION_ONLY=yes", "SHARED=from_ball"} {
+		if !slices.Contains(runner.envs[0], want) {
+			t.Errorf("Expected agent env to contain %s, got %v", want, runner.envs[0])
+		}
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Expected setup commands to run: %v", err)
+	}
+	want := "session setup\nball setup ball_db yes\nagent\nball teardown\nsession teardown"
+	if got := strings.TrimSpace(string(log)); got != want {
+		t.Errorf("Unexpected setup/teardown order:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAgentLoop_SetupFailureStopsRun(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	logPath := filepath.Join(t.TempDir(), "env.log")
+
+	ball := setupGatedProject(t, env, nil)
+	ball.SetSetupCommands([]string{"exit 3"})
+	ball.SetTeardownCommands([]string{`echo "ball teardown" >> ` + logPath})
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	runner := &envRecordingRunner{
+		fileWritingMockRunner: fileWritingMockRunner{env: env, ballID: ball.ID},
+		logPath:               logPath,
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "setup failed") {
+		t.Fatalf("Expected setup failure, got %v", err)
+	}
+	if len(runner.envs) != 0 {
+		t.Errorf("Expected agent not to run after failed setup, got %d runs", len(runner.envs))
+	}
+
+	// Teardown still runs to clean up anything setup started
+	log, _ := os.ReadFile(logPath)
+	if strings.TrimSpace(string(log)) != "ball teardown" {
+		t.Errorf("Expected ball teardown after failed setup, got %q", string(log))
+	}
+}
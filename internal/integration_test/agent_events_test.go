@@ -0,0 +1,118 @@
+package integration_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// rateLimitedOnceMockRunner is rate limited on its first call, then completes the ball
+type rateLimitedOnceMockRunner struct {
+	env    *TestEnv
+	ballID string
+	calls  int
+}
+
+func (m *rateLimitedOnceMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	if m.calls == 1 {
+		return &agent.RunResult{Output: "Error: rate limit exceeded", RateLimited: true, RetryAfter: 10 * time.Millisecond}, nil
+	}
+
+	store, err := session.NewStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	ball, err := store.GetBallByID(m.ballID)
+	if err != nil {
+		return nil, err
+	}
+	ball.MarkComplete("done")
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, err
+	}
+	sessionStore, err := session.NewSessionStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("test-session", "Completed ball\n"); err != nil {
+		return nil, err
+	}
+	return &agent.RunResult{Output: "<promise>COMPLETE</promise>", Complete: true}, nil
+}
+
+func TestAgentLoop_StreamsEvents(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for events")
+	ball := env.CreateInProgressBall(t, "Stream events", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.SetRunner(&rateLimitedOnceMockRunner{env: env, ballID: ball.ID})
+	defer agent.ResetRunner()
+
+	var out bytes.Buffer
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+		Events:        &out,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	var events []cli.AgentEvent
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var event cli.AgentEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Line is not a JSON event: %q: %v", scanner.Text(), err)
+		}
+		if event.Session != "test-session" || event.Time.IsZero() {
+			t.Errorf("Expected session and time on every event, got %+v", event)
+		}
+		events = append(events, event)
+	}
+
+	want := []string{
+		cli.AgentEventIterationStart,
+		cli.AgentEventModelSelected,
+		cli.AgentEventRateLimited,
+		cli.AgentEventBallComplete,
+		cli.AgentEventRunSummary,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected events %v, got %+v", want, events)
+	}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, want[i], event.Type)
+		}
+	}
+
+	if events[1].Model == "" {
+		t.Errorf("Expected model_selected to name the model, got %+v", events[1])
+	}
+	if events[2].Attempt != 1 || events[2].WaitSeconds <= 0 {
+		t.Errorf("Expected first rate limit attempt with a wait, got %+v", events[2])
+	}
+	if events[3].BallID != ball.ID || events[3].Title != "Stream events" {
+		t.Errorf("Expected ball_complete for %s, got %+v", ball.ID, events[3])
+	}
+	summary := events[4]
+	if summary.Status != "complete" || summary.Result == nil || summary.Result.BallsComplete != 1 {
+		t.Errorf("Expected a complete run summary, got %+v", summary)
+	}
+}
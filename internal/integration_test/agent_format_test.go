@@ -0,0 +1,118 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// unformattedMockRunner completes a ball leaving behind files a formatter should fix
+type unformattedMockRunner struct {
+	env    *TestEnv
+	ballID string
+}
+
+func (m *unformattedMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	store, err := session.NewStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	ball, err := store.GetBallByID(m.ballID)
+	if err != nil {
+		return nil, err
+	}
+	ball.MarkComplete("done")
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(m.env.ProjectDir, "code.txt"), []byte("messy\n"), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(m.env.ProjectDir, "notes.md"), []byte("messy\n"), 0644); err != nil {
+		return nil, err
+	}
+
+	sessionStore, err := session.NewSessionStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("test-session", "Completed ball\n"); err != nil {
+		return nil, err
+	}
+
+	return &agent.RunResult{
+		Output:        "<promise>COMPLETE: feat: add code</promise>",
+		Complete:      true,
+		CommitMessage: "feat: add code",
+	}, nil
+}
+
+func TestAgentLoop_FormattersFoldedIntoCommit(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Formatters = []session.FormatterConfig{
+		{Command: "sed -i s/messy/tidy/", Patterns: []string{"*.txt"}},
+	}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for formatters")
+	ball := env.CreateInProgressBall(t, "Add code", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.SetRunner(&unformattedMockRunner{env: env, ballID: ball.ID})
+	defer agent.ResetRunner()
+
+	_, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	// The formatter's fix must be part of the agent commit, not left uncommitted
+	committed, err := exec.Command("git", "-C", env.ProjectDir, "show", "HEAD:code.txt").Output()
+	if err != nil {
+		t.Fatalf("Expected code.txt in HEAD commit: %v", err)
+	}
+	if string(committed) != "tidy\n" {
+		t.Errorf("Expected formatted content in commit, got %q", committed)
+	}
+
+	// Files not matching the pattern are left alone
+	notes, err := exec.Command("git", "-C", env.ProjectDir, "show", "HEAD:notes.md").Output()
+	if err != nil {
+		t.Fatalf("Expected notes.md in HEAD commit: %v", err)
+	}
+	if string(notes) != "messy\n" {
+		t.Errorf("Expected notes.md untouched, got %q", notes)
+	}
+}
@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// setGates saves build/lint/typecheck gates to the project
+func setGates(t *testing.T, env *TestEnv, gates []session.GateConfig) {
+	t.Helper()
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Gates = gates
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+}
+
+func TestAgentLoop_GateFailureBlocksAcceptance(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	setGates(t, env, []session.GateConfig{
+		{Name: "build", Command: "true"},
+		{Name: "lint", Command: "echo unused variable x && false"},
+		{Name: "typecheck", Command: "echo should not run"},
+	})
+
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files:  map[string]string{"config.go": "package config\n"},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.Complete {
+		t.Error("Expected run not to complete when a gate fails")
+	}
+
+	if len(result.GateResults) != 2 {
+		t.Fatalf("Expected the build and lint gates to run, got %+v", result.GateResults)
+	}
+	if !result.GateResults[0].Passed || result.GateResults[1].Passed || result.GateResults[1].Name != "lint" {
+		t.Errorf("Expected build to pass and lint to fail, got %+v", result.GateResults)
+	}
+	if result.GateResults[1].Iteration != 1 {
+		t.Errorf("Expected the gate result to record iteration 1, got %d", result.GateResults[1].Iteration)
+	}
+
+	out, _ := exec.Command("git", "-C", env.ProjectDir, "rev-list", "--count", "HEAD").Output()
+	if strings.TrimSpace(string(out)) != "1" {
+		t.Errorf("Expected no agent commit, got %s commits", strings.TrimSpace(string(out)))
+	}
+	env.AssertState(t, ball.ID, session.StateInProgress)
+
+	progress, err := env.GetSessionStore(t).LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[GATE] Iteration 1: build passed") || !strings.Contains(progress, "lint failed") ||
+		!strings.Contains(progress, "unused variable x") {
+		t.Errorf("Expected gate results with lint output in progress, got:\n%s", progress)
+	}
+	if strings.Contains(progress, "should not run") {
+		t.Error("Expected gates after the failing one to be skipped")
+	}
+}
+
+func TestAgentLoop_GateTimeoutAndCompleteTrigger(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	setGates(t, env, []session.GateConfig{
+		{Name: "slow", Command: "sleep 5", TimeoutSeconds: 1, On: session.GateOnComplete},
+	})
+
+	// The mock completes the ball, so the on=complete gate runs and times out
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files:  map[string]string{"config.go": "package config\n"},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(result.GateResults) != 1 || !result.GateResults[0].TimedOut {
+		t.Fatalf("Expected the slow gate to time out, got %+v", result.GateResults)
+	}
+	if result.Complete {
+		t.Error("Expected run not to complete when a gate times out")
+	}
+}
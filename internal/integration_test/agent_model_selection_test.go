@@ -1,9 +1,11 @@
 package integration_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
 	"github.com/ohare93/juggle/internal/cli"
 	"github.com/ohare93/juggle/internal/session"
 )
@@ -29,6 +31,28 @@ func TestSelectModelForIteration_ExplicitModelFlag(t *testing.T) {
 	}
 }
 
+// TestSelectModelForIteration_UnsupportedModel tests fallback when the provider rejects the model
+func TestSelectModelForIteration_UnsupportedModel(t *testing.T) {
+	defer agent.ResetRunner()
+	agent.SetProvider(provider.NewCustomProvider("test", provider.CustomConfig{
+		Binary: "test",
+		Models: map[string]string{"sonnet": "medium-model"},
+	}))
+
+	balls := []*session.Ball{
+		{ID: "ball-1", State: session.StatePending, ModelSize: session.ModelSizeLarge},
+	}
+
+	result := cli.SelectModelForIterationForTest(cli.AgentLoopConfig{}, balls, "")
+
+	if result.Model != "sonnet" {
+		t.Errorf("Expected fallback model=sonnet, got %s", result.Model)
+	}
+	if result.Reason != "opus not supported by test, using sonnet" {
+		t.Errorf("Expected reason about unsupported model, got: %s", result.Reason)
+	}
+}
+
 // TestSelectModelForIteration_SessionDefault tests session default model fallback
 func TestSelectModelForIteration_SessionDefault(t *testing.T) {
 	// All balls have blank model preference
@@ -46,6 +70,28 @@ func TestSelectModelForIteration_SessionDefault(t *testing.T) {
 	}
 }
 
+// TestSelectModelForIteration_KindDefault tests that docs and ops balls default to a medium model
+func TestSelectModelForIteration_KindDefault(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "ball-1", State: session.StatePending, Kind: session.BallKindDocs},
+		{ID: "ball-2", State: session.StatePending, Kind: session.BallKindOps},
+		{ID: "ball-3", State: session.StatePending},
+	}
+
+	config := cli.AgentLoopConfig{} // No explicit model
+
+	result := cli.SelectModelForIterationForTest(config, balls, "")
+	if result.Model != "sonnet" {
+		t.Errorf("Expected model=sonnet (docs and ops default to medium), got %s", result.Model)
+	}
+
+	// A session default applies before the kind default
+	result = cli.SelectModelForIterationForTest(config, balls, session.ModelSizeSmall)
+	if result.Model != "haiku" {
+		t.Errorf("Expected model=haiku (session default), got %s", result.Model)
+	}
+}
+
 // TestSelectModelForIteration_MajorityBallPreference tests selection based on ball count
 func TestSelectModelForIteration_MajorityBallPreference(t *testing.T) {
 	balls := []*session.Ball{
@@ -66,6 +112,51 @@ func TestSelectModelForIteration_MajorityBallPreference(t *testing.T) {
 	}
 }
 
+// TestSelectModelForIteration_Escalated tests that a ball escalated after repeated failures lifts the iteration
+func TestSelectModelForIteration_Escalated(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "ball-1", State: session.StatePending, ModelSize: session.ModelSizeSmall},
+		{ID: "ball-2", State: session.StatePending, ModelSize: session.ModelSizeSmall},
+		{ID: "ball-3", State: session.StateInProgress, ModelSize: session.ModelSizeSmall, EscalatedModel: "sonnet"},
+	}
+
+	result := cli.SelectModelForIterationForTest(cli.AgentLoopConfig{}, balls, "")
+
+	if result.Model != "sonnet" {
+		t.Errorf("Expected model=sonnet (ball-3 escalated), got %s", result.Model)
+	}
+	if result.Reason != "ball 3 escalated after repeated failures" {
+		t.Errorf("Expected escalation reason, got: %s", result.Reason)
+	}
+
+	// An explicit --model still wins
+	result = cli.SelectModelForIterationForTest(cli.AgentLoopConfig{Model: "haiku"}, balls, "")
+	if result.Model != "haiku" {
+		t.Errorf("Expected model=haiku (explicit flag), got %s", result.Model)
+	}
+}
+
+// TestSelectModelForIteration_EstimatedWork tests that estimates weigh balls by their expected iterations
+func TestSelectModelForIteration_EstimatedWork(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "ball-1", State: session.StatePending, Estimate: &session.BallEstimate{ModelSize: session.ModelSizeSmall, Iterations: 1}},
+		{ID: "ball-2", State: session.StatePending, Estimate: &session.BallEstimate{ModelSize: session.ModelSizeSmall, Iterations: 1}},
+		{ID: "ball-3", State: session.StatePending, Estimate: &session.BallEstimate{ModelSize: session.ModelSizeLarge, Iterations: 5}},
+	}
+
+	result := cli.SelectModelForIterationForTest(cli.AgentLoopConfig{}, balls, "")
+
+	if result.Model != "opus" {
+		t.Errorf("Expected model=opus (5 estimated iterations beat 2), got %s", result.Model)
+	}
+	if result.BallsCount != 1 {
+		t.Errorf("Expected BallsCount=1, got %d", result.BallsCount)
+	}
+	if result.Reason != "1 ball(s) prefer opus model (~5 estimated iterations)" {
+		t.Errorf("Expected reason with estimated iterations, got: %s", result.Reason)
+	}
+}
+
 // TestSelectModelForIteration_DefaultToOpus tests default when no preferences
 func TestSelectModelForIteration_DefaultToOpus(t *testing.T) {
 	balls := []*session.Ball{
@@ -179,7 +270,7 @@ func TestPrioritizeBallsByModel_PreservesOrderWithinGroup(t *testing.T) {
 func TestPrioritizeBallsByModel_BlankMatchesAll(t *testing.T) {
 	balls := []*session.Ball{
 		{ID: "ball-1", State: session.StatePending, ModelSize: session.ModelSizeLarge},
-		{ID: "ball-2", State: session.StatePending, ModelSize: ""},  // Blank should match
+		{ID: "ball-2", State: session.StatePending, ModelSize: ""}, // Blank should match
 		{ID: "ball-3", State: session.StatePending, ModelSize: session.ModelSizeSmall},
 	}
 
@@ -196,7 +287,7 @@ func TestPrioritizeBallsByModel_BlankMatchesAll(t *testing.T) {
 func TestPrioritizeBallsByModel_SessionDefaultFallback(t *testing.T) {
 	balls := []*session.Ball{
 		{ID: "ball-1", State: session.StatePending, ModelSize: session.ModelSizeLarge},
-		{ID: "ball-2", State: session.StatePending, ModelSize: ""},  // Uses session default
+		{ID: "ball-2", State: session.StatePending, ModelSize: ""}, // Uses session default
 	}
 
 	// Session default is small (haiku), so ball-2 should match haiku
@@ -296,7 +387,7 @@ func TestModelSelectionInAgentLoop(t *testing.T) {
 		// Note: No Model set, so it should auto-select based on ball preference
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -381,7 +472,7 @@ func TestModelSelectionWithActiveBalls(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -431,7 +522,7 @@ func TestModelSelectionWithExplicitFlag(t *testing.T) {
 		Model:         "haiku", // Explicit flag should take precedence
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
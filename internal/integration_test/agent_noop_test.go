@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// idleMockRunner returns without changing files, balls or progress
+type idleMockRunner struct {
+	prompts []string
+}
+
+func (m *idleMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.prompts = append(m.prompts, opts.Prompt)
+	return &agent.RunResult{Output: "Thinking about it"}, nil
+}
+
+// setupNoOpEnv creates a git repository with an in-progress ball and the given no-op settings
+func setupNoOpEnv(t *testing.T, env *TestEnv, settings *session.NoOpConfig) {
+	t.Helper()
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.NoOp = settings
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for no-op detection")
+	ball := env.CreateInProgressBall(t, "Never touched", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+}
+
+func TestAgentLoop_NoOpIterationsStopRun(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupNoOpEnv(t, env, &session.NoOpConfig{MaxConsecutive: 2, Action: "stop"})
+
+	runner := &idleMockRunner{}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 5,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.prompts) != 2 {
+		t.Errorf("Expected the run to stop after 2 iterations, got %d", len(runner.prompts))
+	}
+	if !result.Blocked || result.BlockedReason != "2 consecutive no-op iterations" {
+		t.Errorf("Expected the run blocked by no-ops, got blocked=%v reason=%q", result.Blocked, result.BlockedReason)
+	}
+	if result.NoOpIterations != 2 {
+		t.Errorf("Expected 2 no-op iterations, got %d", result.NoOpIterations)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[NO_OP] Agent run stopped: 2 consecutive no-op iterations") {
+		t.Errorf("Expected a [NO_OP] progress entry, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_NoOpIterationsNudgeAgent(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupNoOpEnv(t, env, &session.NoOpConfig{MaxConsecutive: 2})
+
+	runner := &idleMockRunner{}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 4,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.prompts) != 4 {
+		t.Fatalf("Expected 4 iterations, got %d", len(runner.prompts))
+	}
+	for i, prompt := range runner.prompts {
+		nudged := strings.Contains(prompt, "changed no files, updated no balls")
+		if want := i == 2; nudged != want {
+			t.Errorf("Iteration %d: nudged=%v, want %v", i+1, nudged, want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAgentLoop_OutputProcessors(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.OutputProcessors = map[string][]string{"*": {"strip_ansi", "extract_summary"}}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for output processors")
+	ball := env.CreateInProgressBall(t, "Fix the parser", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	noisy := "\x1b[2mReading parser.go\x1b[0m\n\x1b[2mRunning go test\x1b[0m\n## Summary\nFixed the off-by-one in the parser.\n"
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: noisy, Continue: true},
+		&agent.RunResult{Output: "## Summary\nNothing left.\n", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	_, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 2,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 2 {
+		t.Fatalf("Expected 2 iterations, got %d", len(mock.Calls))
+	}
+
+	// The next prompt carries the processed output, not the noise
+	prompt := mock.Calls[1].Prompt
+	if !strings.Contains(prompt, "Output of your previous iteration:\n\n## Summary\nFixed the off-by-one in the parser.") {
+		t.Errorf("Expected the processed output in the next prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Reading parser.go") || strings.Contains(prompt, "\x1b[") {
+		t.Errorf("Expected the noise left out of the next prompt")
+	}
+
+	saved, err := os.ReadFile(filepath.Join(env.ProjectDir, ".juggle", "sessions", "test-session", "last_output.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read last output: %v", err)
+	}
+	if string(saved) != "## Summary\nNothing left.\n" {
+		t.Errorf("Expected the processed output saved, got %q", saved)
+	}
+}
+
+func TestAgentLoop_UnknownOutputProcessor(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.OutputProcessors = map[string][]string{"opencode": {"strip_colour"}}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+	env.CreateSession(t, "test-session", "Test session for output processors")
+
+	mock := agent.NewMockRunner()
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	_, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown output processor "strip_colour"`) {
+		t.Errorf("Expected the run to fail on an unknown processor, got %v", err)
+	}
+	if len(mock.Calls) != 0 {
+		t.Errorf("Expected no agent calls, got %d", len(mock.Calls))
+	}
+}
@@ -2,6 +2,7 @@ package integration_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -96,7 +97,7 @@ func TestOutputFormatting_SingleIteration_Complete(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify iteration header appears
@@ -150,7 +151,7 @@ func TestOutputFormatting_SingleIteration_Blocked(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify iteration header appears
@@ -218,7 +219,7 @@ func TestOutputFormatting_MultipleIterations_Continue(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify both iteration headers appear
@@ -275,7 +276,7 @@ func TestOutputFormatting_MultipleIterations_MaxReached(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify all iteration headers appear
@@ -341,7 +342,7 @@ func TestOutputFormatting_PrematureComplete(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify warning message appears with spacing (premature because ball not terminal)
@@ -404,7 +405,7 @@ func TestOutputFormatting_RateLimit(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify rate limit message appears
@@ -456,7 +457,7 @@ func TestOutputFormatting_Timeout(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify iteration header appeared before timeout
@@ -500,7 +501,7 @@ func TestOutputFormatting_Iterations_1(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify header shows 1/1
@@ -553,7 +554,7 @@ func TestOutputFormatting_Iterations_3(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify all 3 iteration headers
@@ -613,7 +614,7 @@ func TestOutputFormatting_Iterations_10(t *testing.T) {
 	}
 
 	output := captureOutput(func() {
-		cli.RunAgentLoop(config)
+		cli.RunAgentLoop(context.Background(), config)
 	})
 
 	// Verify all 10 iteration headers
@@ -0,0 +1,132 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// setupOverlappingSessions creates a ball that mentions internal/auth and a
+// second session that changed a file there an hour ago
+func setupOverlappingSessions(t *testing.T, env *TestEnv, mode string) {
+	t.Helper()
+	authDir := filepath.Join(env.ProjectDir, "internal", "auth")
+	if err := os.MkdirAll(authDir, 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(authDir, "login.go"), []byte("package auth\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Night run A")
+	env.CreateSession(t, "other-session", "Night run B")
+	ball := env.CreateBall(t, "Fix token refresh", session.PriorityMedium)
+	ball.Context = "The refresh logic lives in `internal/auth`."
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	touch := session.NewFileTouch(env.ProjectDir, filepath.Join(authDir, "login.go"), "", time.Now().Add(-time.Hour))
+	if err := sessionStore.RecordFileTouches("other-session", []session.FileTouch{touch}); err != nil {
+		t.Fatalf("Failed to record touches: %v", err)
+	}
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.DuplicateWork = &session.DuplicateWorkConfig{Mode: mode}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+}
+
+func TestAgentLoop_DuplicateWorkBlocks(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupOverlappingSessions(t, env, "block")
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "Working..."})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Blocked || !strings.Contains(result.BlockedReason, "other-session") {
+		t.Errorf("Expected the run to be blocked by other-session's changes, got %+v", result)
+	}
+	if len(mock.Calls) != 0 {
+		t.Errorf("Expected the agent not to start, got %d calls", len(mock.Calls))
+	}
+
+	// --allow-overlap starts anyway
+	result, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		AllowOverlap:  true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected the agent to start with AllowOverlap, got %d calls", len(mock.Calls))
+	}
+}
+
+func TestAgentLoop_DuplicateWorkWarns(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupOverlappingSessions(t, env, "")
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "Working..."})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Fatalf("Expected the agent to start despite the warning, got %d calls", len(mock.Calls))
+	}
+	if !strings.Contains(mock.Calls[0].Prompt, "[OVERLAP]") {
+		t.Error("Expected the overlap to be logged to progress for the agent")
+	}
+	if !containsEnv(mock.Calls[0].Env, "JUGGLE_SESSION_ID=test-session") {
+		t.Errorf("Expected JUGGLE_SESSION_ID in the agent env for hooks, got %v", mock.Calls[0].Env)
+	}
+}
+
+// containsEnv reports whether env has the exact KEY=VALUE entry
+func containsEnv(env []string, entry string) bool {
+	for _, e := range env {
+		if e == entry {
+			return true
+		}
+	}
+	return false
+}
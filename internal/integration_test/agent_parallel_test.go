@@ -0,0 +1,225 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// setupParallelRepo creates a committed git repo with .juggle/ ignored
+func setupParallelRepo(t *testing.T, env *TestEnv) {
+	t.Helper()
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+}
+
+func TestParallelAgents_RunsBallsInSeparateWorktrees(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupParallelRepo(t, env)
+
+	env.CreateSession(t, "par", "Parallel session")
+	store := env.GetStore(t)
+	var balls []*session.Ball
+	for _, title := range []string{"Parse input", "Write output", "Add docs"} {
+		ball := env.CreateBall(t, title, session.PriorityMedium)
+		ball.Tags = []string{"par"}
+		balls = append(balls, ball)
+	}
+	// "Add docs" has to wait for "Parse input"
+	balls[2].DependsOn = []string{balls[0].ID}
+	done := env.CreateBall(t, "Already done", session.PriorityHigh)
+	done.Tags = []string{"par"}
+	done.ForceSetState(session.StateComplete)
+	for _, ball := range append(balls, done) {
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	started := map[string]time.Time{}
+	finished := map[string]time.Time{}
+
+	launch := func(ctx context.Context, job *cli.ParallelJob, out io.Writer) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		started[job.BallID] = time.Now()
+		mu.Unlock()
+
+		// Like a real child agent: hold the ball lock, commit in the workspace, complete the ball
+		lock, err := session.AcquireBallLock(job.WorkspaceDir, job.BallID)
+		if err != nil {
+			return err
+		}
+		defer lock.Release()
+		fmt.Fprintf(out, "working on %s\npartial", job.Title)
+
+		time.Sleep(50 * time.Millisecond)
+		if err := os.WriteFile(filepath.Join(job.WorkspaceDir, job.BallID+".txt"), []byte(job.Title+"\n"), 0644); err != nil {
+			return err
+		}
+		runGit(job.WorkspaceDir, "add", job.BallID+".txt")
+		runGit(job.WorkspaceDir, "commit", "-m", job.Title)
+
+		// The workspace is linked, so its store writes to the main repo's balls
+		wsStore, err := session.NewStore(job.WorkspaceDir)
+		if err != nil {
+			return err
+		}
+		ball, err := wsStore.GetBallByID(job.BallID)
+		if err != nil {
+			return err
+		}
+		ball.MarkComplete("done")
+		if err := wsStore.UpdateBall(ball); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		running--
+		finished[job.BallID] = time.Now()
+		mu.Unlock()
+		return nil
+	}
+
+	var output bytes.Buffer
+	result, err := cli.RunParallelAgents(context.Background(), cli.ParallelAgentOptions{
+		ProjectDir: env.ProjectDir,
+		SessionID:  "par",
+		Parallel:   2,
+		Iterations: 1,
+		Output:     &output,
+		Launch:     launch,
+	})
+	if err != nil {
+		t.Fatalf("RunParallelAgents failed: %v", err)
+	}
+
+	if len(result.Jobs) != 3 || result.Complete != 3 || result.Failed != 0 {
+		t.Fatalf("Expected 3 completed jobs, got %+v\n%s", result, output.String())
+	}
+	if maxRunning != 2 {
+		t.Errorf("Expected 2 agents running at once, got %d", maxRunning)
+	}
+	if started[balls[2].ID].Before(finished[balls[0].ID]) {
+		t.Error("Expected dependent ball to start after its dependency finished")
+	}
+
+	for _, ball := range balls {
+		got, err := store.GetBallByID(ball.ID)
+		if err != nil {
+			t.Fatalf("Failed to load ball: %v", err)
+		}
+		if got.State != session.StateComplete {
+			t.Errorf("Expected %s complete, got %s", ball.ID, got.State)
+		}
+		// Workspaces without uncommitted work are removed; the branch keeps the commit
+		if _, err := os.Stat(filepath.Join(env.TempDir, "project-"+ball.ID)); !os.IsNotExist(err) {
+			t.Errorf("Expected workspace for %s to be removed, got %v", ball.ID, err)
+		}
+		logCmd := exec.Command("git", "log", "--format=%s", "juggle-"+ball.ID)
+		logCmd.Dir = env.ProjectDir
+		if out, _ := logCmd.Output(); !strings.Contains(string(out), ball.Title) {
+			t.Errorf("Expected branch juggle-%s to hold the commit, got %q", ball.ID, out)
+		}
+	}
+	if worktrees, _ := session.ListWorktrees(env.ProjectDir, ""); len(worktrees) != 0 {
+		t.Errorf("Expected workspaces to be unregistered, got %v", worktrees)
+	}
+
+	merged := output.String()
+	for _, want := range []string{
+		"[" + balls[0].ID + "] working on Parse input\n",
+		"[" + balls[0].ID + "] partial\n",
+		"✓ " + balls[1].ID + " complete",
+	} {
+		if !strings.Contains(merged, want) {
+			t.Errorf("Expected merged output to contain %q, got:\n%s", want, merged)
+		}
+	}
+	if strings.Contains(merged, done.ID) {
+		t.Errorf("Expected completed ball to be skipped, got:\n%s", merged)
+	}
+}
+
+func TestParallelAgents_KeepsWorkspaceWithUncommittedChanges(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupParallelRepo(t, env)
+
+	env.CreateSession(t, "par", "Parallel session")
+	store := env.GetStore(t)
+	ball := env.CreateBall(t, "Half finished", session.PriorityMedium)
+	ball.Tags = []string{"par"}
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	launch := func(ctx context.Context, job *cli.ParallelJob, out io.Writer) error {
+		if err := os.WriteFile(filepath.Join(job.WorkspaceDir, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+			return err
+		}
+		return fmt.Errorf("exit status 1")
+	}
+
+	var output bytes.Buffer
+	result, err := cli.RunParallelAgents(context.Background(), cli.ParallelAgentOptions{
+		ProjectDir: env.ProjectDir,
+		SessionID:  "par",
+		Parallel:   4,
+		Iterations: 1,
+		Output:     &output,
+		Launch:     launch,
+	})
+	if err != nil {
+		t.Fatalf("RunParallelAgents failed: %v", err)
+	}
+
+	if result.Failed != 1 || len(result.Jobs) != 1 || !result.Jobs[0].Kept {
+		t.Fatalf("Expected one failed job with its workspace kept, got %+v", result.Jobs)
+	}
+	if _, err := os.Stat(filepath.Join(result.Jobs[0].WorkspaceDir, "wip.txt")); err != nil {
+		t.Errorf("Expected uncommitted work to be kept: %v", err)
+	}
+	if !strings.Contains(output.String(), "failed after") || !strings.Contains(output.String(), "kept at") {
+		t.Errorf("Expected failure and kept workspace reported, got:\n%s", output.String())
+	}
+}
+
+func TestParallelAgents_RequiresVCS(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	env.CreateSession(t, "par", "Parallel session")
+
+	_, err := cli.RunParallelAgents(context.Background(), cli.ParallelAgentOptions{
+		ProjectDir: env.ProjectDir,
+		SessionID:  "par",
+		Parallel:   2,
+		Output:     io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "git or jj") {
+		t.Errorf("Expected error about missing VCS, got %v", err)
+	}
+}
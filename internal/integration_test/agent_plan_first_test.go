@@ -0,0 +1,115 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+func TestAgentLoop_PlanFirst(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: "<plan ball=\"" + ball.ID + "\">\n1. Add config.go with a Load function\n2. Run go test ./...\n</plan>"},
+		&agent.RunResult{Output: "working", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 2,
+		PlanFirst:     true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 2 || result.Iterations != 2 {
+		t.Fatalf("Expected a planning iteration and one execution iteration, got %d calls and %d iterations", len(mock.Calls), result.Iterations)
+	}
+
+	planning := mock.Calls[0]
+	if planning.Permission != agent.PermissionPlan || !strings.Contains(planning.Prompt, "Planning Iteration") {
+		t.Errorf("Expected iteration 1 to plan in plan mode, got permission %v", planning.Permission)
+	}
+	if mock.Calls[1].Permission != agent.PermissionAcceptEdits {
+		t.Errorf("Expected iteration 2 to execute with edits, got permission %v", mock.Calls[1].Permission)
+	}
+	if !strings.Contains(mock.Calls[1].Prompt, "Plan (work through these steps in order") ||
+		!strings.Contains(mock.Calls[1].Prompt, "1. Add config.go with a Load function") {
+		t.Errorf("Expected the execution prompt to include the plan, got:\n%s", mock.Calls[1].Prompt)
+	}
+
+	updated, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if len(updated.Plan) != 2 || updated.Plan[1] != "Run go test ./..." {
+		t.Errorf("Expected the plan stored on the ball, got %q", updated.Plan)
+	}
+
+	progress, err := env.GetSessionStore(t).LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[PLAN] Planned "+ball.ID+" in 2 step(s)") {
+		t.Errorf("Expected the plan in progress, got:\n%s", progress)
+	}
+
+	// A planned ball isn't planned again
+	mock = agent.NewMockRunner(&agent.RunResult{Output: "working", Continue: true})
+	agent.SetRunner(mock)
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		PlanFirst:     true,
+	}); err != nil {
+		t.Fatalf("Second run failed: %v", err)
+	}
+	if len(mock.Calls) != 1 || mock.Calls[0].Permission == agent.PermissionPlan {
+		t.Errorf("Expected the second run to execute straight away, got %d calls", len(mock.Calls))
+	}
+}
+
+func TestAgentLoop_PlanFirstWithoutPlanOutput(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: "I could not come up with a plan"},
+		&agent.RunResult{Output: "working", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 2,
+		PlanFirst:     true,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 2 {
+		t.Errorf("Expected the run to execute after a planning iteration without plans, got %d calls", len(mock.Calls))
+	}
+	updated, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if len(updated.Plan) != 0 {
+		t.Errorf("Expected no plan, got %q", updated.Plan)
+	}
+}
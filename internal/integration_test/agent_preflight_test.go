@@ -0,0 +1,114 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+func TestAgentLoop_PreflightFailsFastOnAuth(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: "Invalid API key · Please run /login", ExitCode: 1},
+		&agent.RunResult{Output: "should not run", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+		Model:         "sonnet",
+		Preflight:     true,
+	})
+	if err == nil {
+		t.Fatal("Expected the run to fail preflight")
+	}
+	if !errors.Is(err, provider.ErrPreflightAuth) || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Errorf("Expected an authentication error quoting the provider, got: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected only the preflight ping, got %d agent calls", len(mock.Calls))
+	}
+
+	progress, err := env.GetSessionStore(t).LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[PREFLIGHT]") {
+		t.Errorf("Expected the preflight failure in progress, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_PreflightPassesThenRuns(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: "OK"},
+		&agent.RunResult{Output: "working", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Model:         "sonnet",
+		Preflight:     true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 2 || result.Iterations != 1 {
+		t.Fatalf("Expected the ping and one iteration, got %d calls and %d iterations", len(mock.Calls), result.Iterations)
+	}
+
+	ping := mock.Calls[0]
+	if ping.Prompt != provider.PreflightPrompt || ping.Permission != agent.PermissionPlan || ping.Model != "sonnet" {
+		t.Errorf("Expected a plan-mode ping with the run's model, got %+v", ping)
+	}
+}
+
+func TestAgentLoop_PreflightRateLimitDoesNotFail(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: "429 too many requests", RateLimited: true, ExitCode: 1},
+		&agent.RunResult{Output: "working", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Model:         "sonnet",
+		Preflight:     true,
+	}); err != nil {
+		t.Fatalf("Expected a rate limited preflight to let the run start, got: %v", err)
+	}
+	if len(mock.Calls) != 2 {
+		t.Errorf("Expected the ping and one iteration, got %d calls", len(mock.Calls))
+	}
+}
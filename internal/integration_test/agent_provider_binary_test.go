@@ -0,0 +1,82 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// setProviderBinaries saves provider binary paths to the project config
+func setProviderBinaries(t *testing.T, env *TestEnv, binaries map[string]string) {
+	t.Helper()
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.ProviderBinaries = binaries
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+}
+
+func TestAgentLoop_UsesPinnedProviderBinary(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	defer provider.ClearBinaryPaths()
+
+	ball := setupGatedProject(t, env, nil)
+
+	// A pinned claude outside PATH is enough for the availability check
+	pinned := filepath.Join(env.TempDir, "nix", "store", "claude")
+	if err := os.MkdirAll(filepath.Dir(pinned), 0755); err != nil {
+		t.Fatalf("Failed to create pinned dir: %v", err)
+	}
+	if err := os.WriteFile(pinned, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("Failed to create pinned binary: %v", err)
+	}
+	setProviderBinaries(t, env, map[string]string{"claude": pinned})
+	t.Setenv("PATH", t.TempDir())
+
+	agent.SetRunner(&fileWritingMockRunner{env: env, ballID: ball.ID})
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if got := provider.BinaryName(provider.TypeClaude); got != pinned {
+		t.Errorf("Expected claude to resolve to %s, got %s", pinned, got)
+	}
+}
+
+func TestAgentLoop_MissingPinnedProviderBinary(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	defer provider.ClearBinaryPaths()
+
+	ball := setupGatedProject(t, env, nil)
+	missing := filepath.Join(env.TempDir, "missing", "claude")
+	setProviderBinaries(t, env, map[string]string{"claude": missing})
+
+	agent.SetRunner(&fileWritingMockRunner{env: env, ballID: ball.ID})
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("Expected error naming the pinned binary, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAgentLoop_WaitsOutQuietHours(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	// Quiet all day, every day: the loop waits until the context gives up
+	quiet, err := session.ParseQuietHours("00:00-24:00")
+	if err != nil {
+		t.Fatalf("ParseQuietHours failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	runner := &cancellingRunner{cancel: func() {}}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+		QuietHours:    quiet,
+	})
+	if err != nil {
+		t.Fatalf("Expected the run to end cleanly, got error: %v", err)
+	}
+	if runner.calls != 0 {
+		t.Errorf("Expected no iterations during quiet hours, got %d agent calls", runner.calls)
+	}
+	if !result.Blocked || !strings.Contains(result.BlockedReason, "Cancelled") {
+		t.Errorf("Expected cancelled result, got %+v", result)
+	}
+}
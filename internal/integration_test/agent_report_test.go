@@ -0,0 +1,84 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+func TestAgentLoop_WritesRunReport(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files:  map[string]string{"config.go": "package config\n"},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Complete {
+		t.Fatal("Expected run to complete")
+	}
+
+	latest, err := env.GetSessionStore(t).LatestReport("test-session")
+	if err != nil {
+		t.Fatalf("Failed to find report: %v", err)
+	}
+	if latest == "" || result.ReportPath != latest {
+		t.Fatalf("Expected the run's report %q to be the latest, got %q", result.ReportPath, latest)
+	}
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	report := string(data)
+	for _, want := range []string{
+		"# Agent Run: test-session",
+		"- **Result:** complete",
+		"- **Iterations:** 1 of 3",
+		"| " + ball.ID + " | ",
+		"in_progress → complete",
+		"- config.go",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
+
+func TestAgentLoop_NoReportWithoutIterations(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Nothing to do")
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.ReportPath != "" {
+		t.Errorf("Expected no report for a run without iterations, got %s", result.ReportPath)
+	}
+	if paths, _ := env.GetSessionStore(t).ListReports("test-session"); len(paths) != 0 {
+		t.Errorf("Expected no reports, got %v", paths)
+	}
+}
@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAgentLoop_ResumeBlocked(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for resuming blocked balls")
+	store := env.GetStore(t)
+	blocked := env.CreateBall(t, "Call the payments API", session.PriorityMedium)
+	blocked.Tags = []string{"test-session"}
+	blocked.AttemptCount = 3
+	if err := blocked.SetBlocked("missing API key"); err != nil {
+		t.Fatalf("Failed to block ball: %v", err)
+	}
+	if err := store.UpdateBall(blocked); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "working", Continue: true})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Message:       "the API key is now in .env",
+		ResumeBlocked: true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Fatalf("Expected the run to continue with the reopened ball, got %d calls", len(mock.Calls))
+	}
+
+	prompt := mock.Calls[0].Prompt
+	if !strings.Contains(prompt, blocked.ID+": Call the payments API (was blocked: missing API key)") {
+		t.Errorf("Expected the prompt to include the prior blocked reason, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "the API key is now in .env") {
+		t.Errorf("Expected the prompt to include the message, got:\n%s", prompt)
+	}
+
+	reopened, err := store.GetBallByID(blocked.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if reopened.State != session.StateInProgress || reopened.BlockedReason != "" || reopened.AttemptCount > 1 {
+		t.Errorf("Expected an in-progress ball with a fresh retry budget, got %s %q (attempts %d)",
+			reopened.State, reopened.BlockedReason, reopened.AttemptCount)
+	}
+}
+
+func TestAgentLoop_WithoutResumeBlockedLeavesBlockedBalls(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for blocked balls")
+	store := env.GetStore(t)
+	blocked := env.CreateBall(t, "Call the payments API", session.PriorityMedium)
+	blocked.Tags = []string{"test-session"}
+	if err := blocked.SetBlocked("missing API key"); err != nil {
+		t.Fatalf("Failed to block ball: %v", err)
+	}
+	if err := store.UpdateBall(blocked); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	mock := agent.NewMockRunner()
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Message:       "the API key is now in .env",
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 0 || !result.Blocked {
+		t.Errorf("Expected the run to wait for a human, got %d calls and blocked=%v", len(mock.Calls), result.Blocked)
+	}
+}
@@ -0,0 +1,96 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// killingRunner finishes one iteration, then stops the run mid-iteration the
+// way a killed daemon would
+type killingRunner struct {
+	cancel context.CancelFunc
+	calls  int
+}
+
+func (m *killingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	if m.calls == 2 {
+		m.cancel()
+		return &agent.RunResult{Output: "partial output"}, nil
+	}
+	return &agent.RunResult{Output: "worked", Continue: true, InputTokens: 1000, OutputTokens: 100}, nil
+}
+
+func TestAgentLoop_ResumeFromCheckpoint(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	agent.SetRunner(&killingRunner{cancel: cancel})
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	}); err != nil {
+		t.Fatalf("First run failed: %v", err)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	checkpoint, err := sessionStore.LoadCheckpoint("test-session", "")
+	if err != nil || checkpoint == nil {
+		t.Fatalf("Expected a checkpoint after the interrupted run, got %+v, %v", checkpoint, err)
+	}
+	if checkpoint.Iteration != 2 || checkpoint.MaxIterations != 3 || checkpoint.InputTokens != 1000 {
+		t.Errorf("Checkpoint = %+v, want iteration 2 of 3 with the first iteration's usage", checkpoint)
+	}
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: "resumed", Continue: true},
+		&agent.RunResult{Output: "resumed again", Continue: true},
+	)
+	agent.SetRunner(mock)
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:  "test-session",
+		ProjectDir: env.ProjectDir,
+		Resume:     true,
+	})
+	if err != nil {
+		t.Fatalf("Resumed run failed: %v", err)
+	}
+	if len(mock.Calls) != 2 {
+		t.Errorf("Expected iterations 2 and 3 to run, got %d agent calls", len(mock.Calls))
+	}
+	if result.Iterations != 3 {
+		t.Errorf("Expected the resumed run to end at iteration 3, got %d", result.Iterations)
+	}
+	if result.InputTokens != 1000 || !result.StartedAt.Equal(checkpoint.StartedAt) {
+		t.Errorf("Expected usage and start time carried over, got %d tokens, started %v", result.InputTokens, result.StartedAt)
+	}
+	if cp, _ := sessionStore.LoadCheckpoint("test-session", ""); cp != nil {
+		t.Errorf("Expected the checkpoint to be cleared after the run finished, got %+v", cp)
+	}
+
+	_, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:  "test-session",
+		ProjectDir: env.ProjectDir,
+		Resume:     true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "no interrupted run") {
+		t.Errorf("Expected an error resuming without a checkpoint, got %v", err)
+	}
+}
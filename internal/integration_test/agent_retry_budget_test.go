@@ -0,0 +1,151 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// thrashingMockRunner logs progress every iteration but never finishes the ball
+type thrashingMockRunner struct {
+	env    *TestEnv
+	calls  int
+	models []string
+}
+
+func (m *thrashingMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	m.models = append(m.models, opts.Model)
+	sessionStore, err := session.NewSessionStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("test-session", "Tried again\n"); err != nil {
+		return nil, err
+	}
+	return &agent.RunResult{Output: "<promise>CONTINUE</promise>", Continue: true}, nil
+}
+
+func TestAgentLoop_RetryBudgetBlocksBall(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.MaxBallAttempts = 3
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for retry budget")
+	ball := env.CreateInProgressBall(t, "Never finishes", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	runner := &thrashingMockRunner{env: env}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 10,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if runner.calls != 3 {
+		t.Errorf("Expected the loop to stop after 3 attempts, got %d", runner.calls)
+	}
+	if !result.Complete {
+		t.Errorf("Expected the run to end with every ball terminal, got %+v", result)
+	}
+
+	updated, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if updated.State != session.StateBlocked || updated.BlockedReason != "exceeded retry budget" {
+		t.Errorf("Expected ball blocked for its retry budget, got %s (%q)", updated.State, updated.BlockedReason)
+	}
+	if updated.AttemptCount != 3 {
+		t.Errorf("Expected 3 attempts, got %d", updated.AttemptCount)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[RETRY_BUDGET] "+ball.ID) {
+		t.Errorf("Expected a [RETRY_BUDGET] progress entry, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_EscalatesModelAfterFailures(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.EscalateAfterFailures = 2
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for model escalation")
+	ball := env.CreateInProgressBall(t, "Too hard for sonnet", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	ball.ModelSize = session.ModelSizeMedium
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	runner := &thrashingMockRunner{env: env}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if got := strings.Join(runner.models, ","); got != "sonnet,sonnet,opus" {
+		t.Errorf("Expected two sonnet iterations then opus, got %s", got)
+	}
+
+	updated, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if updated.EscalatedModel != "opus" {
+		t.Errorf("Expected the ball escalated to opus, got %q", updated.EscalatedModel)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[ESCALATE] "+ball.ID+" failed 2 attempt(s) at sonnet, escalated to opus") {
+		t.Errorf("Expected the escalation in progress, got:\n%s", progress)
+	}
+}
@@ -0,0 +1,163 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// reviewingMockRunner completes the ball on its first call and answers the
+// review iteration after it, recording every call
+type reviewingMockRunner struct {
+	work   *fileWritingMockRunner
+	review string
+	calls  []agent.RunOptions
+}
+
+func (m *reviewingMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls = append(m.calls, opts)
+	if len(m.calls) == 1 {
+		return m.work.Run(opts)
+	}
+	return &agent.RunResult{Output: m.review}, nil
+}
+
+func TestAgentLoop_ReviewAfterCompletion(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	ball.AcceptanceCriteria = []string{"Config loads from config.yaml"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	runner := &reviewingMockRunner{
+		work: &fileWritingMockRunner{
+			env:    env,
+			ballID: ball.ID,
+			files:  map[string]string{"config.go": "package config\n\nconst path = \"config.yaml\"\n"},
+		},
+		review: "## Summary\n\nLooks good.\n\n## Findings\n\n- [minor] config.go: path is hardcoded",
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+		Review:        true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Complete {
+		t.Fatal("Expected run to complete")
+	}
+	if len(runner.calls) != 2 {
+		t.Fatalf("Expected one work iteration and one review, got %d calls", len(runner.calls))
+	}
+
+	review := runner.calls[1]
+	if review.Permission != agent.PermissionPlan {
+		t.Errorf("Expected the review to run in plan mode, got %v", review.Permission)
+	}
+	if !strings.Contains(review.Prompt, "<diff>") || !strings.Contains(review.Prompt, "config.yaml") {
+		t.Errorf("Expected the review prompt to include the run's diff, got:\n%s", review.Prompt)
+	}
+	if !strings.Contains(review.Prompt, "Config loads from config.yaml") {
+		t.Errorf("Expected the review prompt to include the acceptance criteria")
+	}
+
+	sessionStore := env.GetSessionStore(t)
+	path := sessionStore.ReviewFilePath("test-session", "")
+	if result.ReviewPath != path {
+		t.Errorf("Expected review path %s, got %s", path, result.ReviewPath)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected review.md to be written: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# Review: test-session") || !strings.Contains(string(data), "path is hardcoded") {
+		t.Errorf("Unexpected review content:\n%s", data)
+	}
+
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[REVIEW] Review of 1 ball(s)") {
+		t.Errorf("Expected the review in progress, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_ReviewFromProjectConfig(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Review = true
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	runner := &reviewingMockRunner{
+		work:   &fileWritingMockRunner{env: env, ballID: ball.ID, files: map[string]string{"config.go": "package config\n"}},
+		review: "## Summary\n\nFine.",
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.calls) != 2 || result.ReviewPath == "" {
+		t.Errorf("Expected the project config to enable the review, got %d calls and path %q", len(runner.calls), result.ReviewPath)
+	}
+}
+
+func TestAgentLoop_NoReviewByDefault(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	runner := &reviewingMockRunner{
+		work:   &fileWritingMockRunner{env: env, ballID: ball.ID, files: map[string]string{"config.go": "package config\n"}},
+		review: "should not run",
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.calls) != 1 || result.ReviewPath != "" {
+		t.Errorf("Expected no review without --review, got %d calls", len(runner.calls))
+	}
+	if _, err := os.Stat(env.GetSessionStore(t).ReviewFilePath("test-session", "")); !os.IsNotExist(err) {
+		t.Error("Expected no review.md without --review")
+	}
+}
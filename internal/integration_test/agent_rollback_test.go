@@ -0,0 +1,215 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// ballCompletingMockRunner marks a ball complete and commits a file on a chosen iteration
+type ballCompletingMockRunner struct {
+	env          *TestEnv
+	ballID       string
+	completeCall int
+	calls        int
+}
+
+func (m *ballCompletingMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	if m.calls == m.completeCall {
+		store, err := session.NewStore(m.env.ProjectDir)
+		if err != nil {
+			return nil, err
+		}
+		ball, err := store.GetBallByID(m.ballID)
+		if err != nil {
+			return nil, err
+		}
+		ball.ForceSetState(session.StateComplete)
+		if err := store.UpdateBall(ball); err != nil {
+			return nil, err
+		}
+
+		workFile := filepath.Join(m.env.ProjectDir, "agent-work.txt")
+		if err := os.WriteFile(workFile, []byte("agent work\n"), 0644); err != nil {
+			return nil, err
+		}
+		runGit(m.env.ProjectDir, "add", "agent-work.txt")
+		runGit(m.env.ProjectDir, "commit", "-m", "agent work")
+	}
+	return &agent.RunResult{Output: "working"}, nil
+}
+
+func runGit(dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	_ = cmd.Run()
+}
+
+func TestAgentRollback_RestoresRevisionAndBallStates(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+
+	env.CreateSession(t, "test-session", "Test session for rollback")
+
+	first := env.CreateInProgressBall(t, "First ball", session.PriorityMedium)
+	first.Tags = []string{"test-session"}
+	second := env.CreateInProgressBall(t, "Second ball", session.PriorityMedium)
+	second.Tags = []string{"test-session"}
+	store := env.GetStore(t)
+	for _, b := range []*session.Ball{first, second} {
+		if err := store.UpdateBall(b); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	// Iteration 2 completes the first ball and commits a file
+	agent.SetRunner(&ballCompletingMockRunner{env: env, ballID: first.ID, completeCall: 2})
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	snapshots, err := env.GetSessionStore(t).LoadSnapshots("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load snapshots: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots, got %d", len(snapshots))
+	}
+	if snapshots[0].Revision == "" {
+		t.Error("Expected snapshot to record git revision")
+	}
+
+	env.AssertState(t, first.ID, session.StateComplete)
+	workFile := filepath.Join(env.ProjectDir, "agent-work.txt")
+	if _, err := os.Stat(workFile); err != nil {
+		t.Fatalf("Expected agent work file to exist before rollback: %v", err)
+	}
+
+	if err := cli.RollbackAgentRunForTest(env.ProjectDir, "test-session", 2); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	env.AssertState(t, first.ID, session.StateInProgress)
+	env.AssertState(t, second.ID, session.StateInProgress)
+	if _, err := os.Stat(workFile); !os.IsNotExist(err) {
+		t.Error("Expected agent work file to be removed by rollback")
+	}
+
+	snapshots, _ = env.GetSessionStore(t).LoadSnapshots("test-session")
+	if len(snapshots) != 2 {
+		t.Errorf("Expected snapshots after iteration 2 to be dropped, got %d", len(snapshots))
+	}
+
+	if err := cli.RollbackAgentRunForTest(env.ProjectDir, "test-session", 5); err == nil {
+		t.Error("Expected error rolling back to unknown iteration")
+	}
+}
+
+// committingMockRunner commits a file and logs progress on every iteration, then signals CONTINUE
+type committingMockRunner struct {
+	env   *TestEnv
+	calls int
+}
+
+func (m *committingMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	name := filepath.Join(m.env.ProjectDir, "iteration-"+string(rune('0'+m.calls))+".txt")
+	if err := os.WriteFile(name, []byte("agent work\n"), 0644); err != nil {
+		return nil, err
+	}
+	runGit(m.env.ProjectDir, "add", "-A")
+	runGit(m.env.ProjectDir, "commit", "-m", "agent work")
+
+	sessionStore, err := session.NewSessionStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("test-session", "[TEST] iteration done\n"); err != nil {
+		return nil, err
+	}
+	return &agent.RunResult{Output: "working", Continue: true}, nil
+}
+
+func TestAgentRollback_AfterSuccessfulIteration(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+
+	env.CreateSession(t, "test-session", "Test session for rollback")
+	ball := env.CreateInProgressBall(t, "Long running ball", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.SetRunner(&committingMockRunner{env: env})
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	snapshots, err := env.GetSessionStore(t).LoadSnapshots("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load snapshots: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if session.FindCompletedSnapshot(snapshots, i) == nil {
+			t.Errorf("Expected a snapshot after successful iteration %d", i)
+		}
+	}
+
+	if err := cli.RollbackAgentRunAfterForTest(env.ProjectDir, "test-session", 1); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(env.ProjectDir, "iteration-1.txt")); err != nil {
+		t.Errorf("Expected iteration 1's work to be kept: %v", err)
+	}
+	for _, name := range []string{"iteration-2.txt", "iteration-3.txt"} {
+		if _, err := os.Stat(filepath.Join(env.ProjectDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed by rollback", name)
+		}
+	}
+
+	snapshots, _ = env.GetSessionStore(t).LoadSnapshots("test-session")
+	if len(snapshots) != 2 {
+		t.Errorf("Expected only iteration 1's snapshots to remain, got %d", len(snapshots))
+	}
+}
@@ -0,0 +1,127 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// sandboxRecordingRunner records the sandbox each run was given
+type sandboxRecordingRunner struct {
+	fileWritingMockRunner
+	sandboxes []*agent.Sandbox
+}
+
+func (m *sandboxRecordingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.sandboxes = append(m.sandboxes, opts.Sandbox)
+	return m.fileWritingMockRunner.Run(opts)
+}
+
+// setupSandboxProject configures a sandbox and puts a stand-in engine on PATH
+func setupSandboxProject(t *testing.T, env *TestEnv, sandbox *session.SandboxConfig) *session.Ball {
+	t.Helper()
+	ball := setupGatedProject(t, env, nil)
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Sandbox = sandbox
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("Failed to create fake docker: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return ball
+}
+
+func TestAgentLoop_SandboxWrapsTrustRuns(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupSandboxProject(t, env, &session.SandboxConfig{
+		Image: "juggle-agent:latest",
+		Env:   []string{"ANTHROPIC_API_KEY"},
+	})
+
+	runner := &sandboxRecordingRunner{fileWritingMockRunner: fileWritingMockRunner{env: env, ballID: ball.ID}}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Trust:         true,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if len(runner.sandboxes) != 1 || runner.sandboxes[0] == nil {
+		t.Fatalf("Expected the trusted run to be sandboxed, got %v", runner.sandboxes)
+	}
+	sandbox := runner.sandboxes[0]
+	if sandbox.EngineName() != "docker" || sandbox.Image != "juggle-agent:latest" {
+		t.Errorf("Expected docker with the configured image, got %+v", sandbox)
+	}
+	if len(sandbox.Mounts) != 1 || sandbox.Mounts[0] != env.ProjectDir {
+		t.Errorf("Expected only the project directory mounted, got %v", sandbox.Mounts)
+	}
+	if len(sandbox.Env) != 1 || sandbox.Env[0] != "ANTHROPIC_API_KEY" {
+		t.Errorf("Expected env pass-through, got %v", sandbox.Env)
+	}
+}
+
+func TestAgentLoop_SandboxSkipsUntrustedRuns(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupSandboxProject(t, env, &session.SandboxConfig{Image: "juggle-agent:latest"})
+
+	runner := &sandboxRecordingRunner{fileWritingMockRunner: fileWritingMockRunner{env: env, ballID: ball.ID}}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.sandboxes) != 1 || runner.sandboxes[0] != nil {
+		t.Errorf("Expected a run without --trust to stay on the host, got %v", runner.sandboxes)
+	}
+}
+
+func TestAgentLoop_SandboxRejectsUnknownEngine(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupSandboxProject(t, env, &session.SandboxConfig{Image: "img", Engine: "lxc", Always: true})
+
+	agent.SetRunner(&fileWritingMockRunner{env: env, ballID: ball.ID})
+	defer agent.ResetRunner()
+
+	_, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "lxc") {
+		t.Errorf("Expected unsupported engine error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+func TestAgentLoop_AppliesSplit(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+
+	split := "<split ball=\"" + ball.ID + "\">\n" +
+		"<child title=\"Add the config loader\" priority=\"high\">\n- Load reads config.json\n- Missing file returns defaults\n</child>\n" +
+		"<child title=\"Document the config file\">\n- README lists every key\n</child>\n" +
+		"</split>\n<promise>CONTINUE</promise>"
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: split, Continue: true},
+		&agent.RunResult{Output: "working", Continue: true},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 2,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	store := env.GetStore(t)
+	parent, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if !parent.Epic || len(parent.DependsOn) != 2 {
+		t.Fatalf("Expected the ball to become an epic depending on 2 children, got epic %v depends on %v", parent.Epic, parent.DependsOn)
+	}
+
+	for i, id := range parent.DependsOn {
+		child, err := store.GetBallByID(id)
+		if err != nil {
+			t.Fatalf("Failed to load child %s: %v", id, err)
+		}
+		if child.Parent != ball.ID || len(child.Tags) == 0 || child.Tags[0] != "test-session" {
+			t.Errorf("Expected child %s in the session with parent %s, got parent %q tags %v", id, ball.ID, child.Parent, child.Tags)
+		}
+		if i == 0 && (child.Title != "Add the config loader" || len(child.AcceptanceCriteria) != 2) {
+			t.Errorf("Expected the first child with its 2 ACs, got %q %v", child.Title, child.AcceptanceCriteria)
+		}
+	}
+
+	// The next iteration works on the children; the epic waits for them
+	prompt := mock.Calls[1].Prompt
+	if !strings.Contains(prompt, "Add the config loader") || !strings.Contains(prompt, "Parent: "+ball.ID) {
+		t.Errorf("Expected the next prompt to include the children, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "## "+ball.ID) {
+		t.Errorf("Expected the epic to wait for its children, got:\n%s", prompt)
+	}
+
+	progress, err := env.GetSessionStore(t).LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[SPLIT] "+ball.ID+" split into") {
+		t.Errorf("Expected the split in progress, got:\n%s", progress)
+	}
+}
@@ -0,0 +1,127 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// stopRequestingRunner leaves uncommitted work, asks for a stop the way
+// juggle agent stop does, and returns once the loop passes the request on
+type stopRequestingRunner struct {
+	env      *TestEnv
+	calls    int
+	stopSeen bool
+}
+
+func (m *stopRequestingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	if err := os.WriteFile(filepath.Join(m.env.ProjectDir, "half-done.go"), []byte("package main\n"), 0644); err != nil {
+		return nil, err
+	}
+	if err := daemon.RequestStop(m.env.ProjectDir, "test-session"); err != nil {
+		return nil, err
+	}
+	select {
+	case <-opts.Stop:
+		m.stopSeen = true
+	case <-time.After(5 * time.Second):
+	}
+	return &agent.RunResult{Output: "partial output", Continue: true}, nil
+}
+
+func TestAgentLoop_StopRequestEndsRunSafely(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+	head := gitOutput(t, env.ProjectDir, "rev-parse", "HEAD")
+
+	runner := &stopRequestingRunner{env: env}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Expected the run to end cleanly, got error: %v", err)
+	}
+	if !runner.stopSeen {
+		t.Error("Expected the stop request to reach the running agent")
+	}
+	if runner.calls != 1 {
+		t.Errorf("Expected the run to stop after the first iteration, got %d agent calls", runner.calls)
+	}
+	if !result.Blocked || result.BlockedReason != daemon.StatusStopped {
+		t.Errorf("Expected a stopped result, got %+v", result)
+	}
+
+	if _, err := os.Stat(filepath.Join(env.ProjectDir, "half-done.go")); !os.IsNotExist(err) {
+		t.Error("Expected uncommitted work to be isolated out of the working copy")
+	}
+	if now := gitOutput(t, env.ProjectDir, "rev-parse", "HEAD"); now != head {
+		t.Errorf("Expected the branch to stay at %s, got %s", head, now)
+	}
+
+	state, err := daemon.ReadStateFile(env.ProjectDir, "test-session")
+	if err != nil {
+		t.Fatalf("Expected a final state for the foreground run: %v", err)
+	}
+	if state.Running || state.Status != daemon.StatusStopped {
+		t.Errorf("Expected a stopped final state, got %+v", state)
+	}
+	if daemon.StopRequested(env.ProjectDir, "test-session") {
+		t.Error("Expected the stop request to be cleared")
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[STOP]") {
+		t.Errorf("Expected the stop logged to progress, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_StaleStopRequestIsIgnored(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+	if err := daemon.RequestStop(env.ProjectDir, "test-session"); err != nil {
+		t.Fatalf("Failed to request stop: %v", err)
+	}
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "working", Continue: true})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 1 || result.BlockedReason == daemon.StatusStopped {
+		t.Errorf("Expected a request left by an earlier run not to stop this one, got %d calls and %+v", len(mock.Calls), result)
+	}
+}
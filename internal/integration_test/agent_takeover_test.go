@@ -0,0 +1,76 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+func TestAgentLoop_TakeoverHoldsLoopUntilResumed(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "worked after takeover", Continue: true})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	// Requested before the first iteration starts, as if sent during the previous one
+	if err := daemon.SendControlCommand(env.ProjectDir, "test-session", daemon.CmdTakeover, ""); err != nil {
+		t.Fatalf("Failed to send takeover: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	heldAt := make(chan time.Time, 1)
+	go func() {
+		for ctx.Err() == nil {
+			state, err := daemon.ReadStateFile(env.ProjectDir, "test-session")
+			if err == nil && state.Status == daemon.StatusTakeover && state.Paused {
+				heldAt <- time.Now()
+				// The interactive session ends; hand the loop back
+				_ = daemon.SendControlCommand(env.ProjectDir, "test-session", daemon.CmdResume, "")
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}()
+
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		DaemonMode:    true,
+	})
+	if err != nil {
+		t.Fatalf("RunAgentLoop failed: %v", err)
+	}
+	select {
+	case <-heldAt:
+	default:
+		t.Fatal("Expected the loop to report it was held for takeover")
+	}
+	if result.Blocked {
+		t.Errorf("Expected the run to resume after takeover, got blocked: %s", result.BlockedReason)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected the iteration to run after resuming, got %d agent calls", len(mock.Calls))
+	}
+
+	progress, err := os.ReadFile(filepath.Join(env.JuggleDir, "sessions", "test-session", "progress.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read progress: %v", err)
+	}
+	if !strings.Contains(string(progress), "[TAKEOVER] Headless loop paused") || !strings.Contains(string(progress), "[TAKEOVER] Interactive session ended") {
+		t.Errorf("Expected takeover pause and resume in progress, got:\n%s", progress)
+	}
+}
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -89,7 +90,7 @@ func TestAgentLoop_CompleteSignalExitsLoop(t *testing.T) {
 		IterDelay:     0, // No delay for tests
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -146,7 +147,7 @@ func TestAgentLoop_BlockedSignalExitsWithReason(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -202,7 +203,7 @@ func TestAgentLoop_MaxIterationsReached(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -274,7 +275,7 @@ func TestAgentLoop_PromptContainsRequiredSections(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err = cli.RunAgentLoop(config)
+	_, err = cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -355,7 +356,7 @@ func TestAgentLoop_EmptySessionHandling(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -407,7 +408,7 @@ func TestAgentLoop_AllBallsCompleteExitsLoop(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -461,7 +462,7 @@ func TestAgentLoop_TrustFlagPassedToRunner(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -491,7 +492,7 @@ func TestAgentLoop_SessionNotFound(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err == nil {
 		t.Fatal("Expected error for non-existent session")
 	}
@@ -550,7 +551,7 @@ func TestAgentLoop_PrematureCOMPLETE_Continues(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -610,7 +611,7 @@ func TestAgentLoop_AllBlockedOrComplete_ExitsLoop(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -675,7 +676,7 @@ func TestAgentLoop_TerminalStateExitsWithoutCOMPLETESignal(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -748,7 +749,7 @@ func TestAgentLoop_ContinueSignalContinuesLoop(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -810,7 +811,7 @@ func TestAgentLoop_TimeoutExitsLoop(t *testing.T) {
 		Timeout:       5 * time.Minute,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -876,7 +877,7 @@ func TestAgentLoop_TimeoutLogsToProgress(t *testing.T) {
 		Timeout:       5 * time.Minute,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -896,6 +897,49 @@ func TestAgentLoop_TimeoutLogsToProgress(t *testing.T) {
 	}
 }
 
+func TestAgentLoop_BallTimeoutOverridesRunTimeout(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for agent")
+
+	// The ball needs long uninterrupted runs
+	ball := env.CreateBall(t, "Long migration", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	ball.SetTimeout(45 * time.Minute)
+	store := env.GetStore(t)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{
+			Output:   "Working...",
+			TimedOut: true,
+		},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 5,
+		Timeout:       5 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if len(mock.Calls) != 1 || mock.Calls[0].Timeout != 45*time.Minute {
+		t.Fatalf("Expected the ball's 45m timeout passed to runner, got %+v", mock.Calls)
+	}
+	if !strings.Contains(result.TimeoutMessage, "timed out after 45m") {
+		t.Errorf("Expected the timeout message to report the ball's timeout, got %q", result.TimeoutMessage)
+	}
+}
+
 // Rate limit tests
 
 func TestAgentLoop_RateLimitRetries(t *testing.T) {
@@ -938,7 +982,7 @@ func TestAgentLoop_RateLimitRetries(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -996,7 +1040,7 @@ func TestAgentLoop_RateLimitMaxWaitExceeded(t *testing.T) {
 		MaxWait:       1 * time.Minute, // Max wait of 1 minute
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1057,7 +1101,7 @@ func TestAgentLoop_RateLimitLogsToProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1121,7 +1165,7 @@ func TestAgentLoop_RateLimitExponentialBackoff(t *testing.T) {
 		MaxWait:       1 * time.Second, // Very short max-wait to exit quickly
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1184,7 +1228,7 @@ func TestAgentLoop_RateLimitMultipleRetries(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1250,7 +1294,7 @@ func TestAgentLoop_RateLimitResetsRetryCountOnSuccess(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1304,7 +1348,7 @@ func TestAgentLoop_CompleteSignalRejectedWithoutProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1370,7 +1414,7 @@ func TestAgentLoop_ContinueSignalRejectedWithoutProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1426,7 +1470,7 @@ func TestAgentLoop_BlockedSignalRejectedWithoutProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1496,7 +1540,7 @@ func TestAgentLoop_CompleteSignalAcceptedWithProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1567,7 +1611,7 @@ func TestAgentLoop_BlockedSignalAcceptedWithProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1663,7 +1707,7 @@ func TestAgentLoop_BlockedWithVCSChanges_Accepted(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1758,7 +1802,7 @@ func TestAgentLoop_ContinueSignalAcceptedWithProgress(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -1921,7 +1965,7 @@ func TestAgentLoop_ConcurrentAgentBlocked(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err = cli.RunAgentLoop(config)
+	_, err = cli.RunAgentLoop(context.Background(), config)
 	if err == nil {
 		t.Fatal("Expected error when session is locked by another agent")
 	}
@@ -1973,7 +2017,7 @@ func TestAgentLoop_LockReleasedOnCompletion(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2021,7 +2065,7 @@ func TestAgentLoop_LockReleasedOnError(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	// Depending on implementation, might succeed or fail
 	// The important thing is the lock is released
 
@@ -2060,7 +2104,7 @@ func TestAgentLoop_LockErrorMessageIncludesPID(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err = cli.RunAgentLoop(config)
+	_, err = cli.RunAgentLoop(context.Background(), config)
 	if err == nil {
 		t.Fatal("Expected error when session is locked")
 	}
@@ -2118,7 +2162,7 @@ func TestAgentLoop_IterationDelayNotAppliedBeforeFirstRun(t *testing.T) {
 		IterDelay:     iterDelay,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2229,7 +2273,7 @@ func TestAgentLoop_NoDelayAfterLastIteration(t *testing.T) {
 		IterDelay:     iterDelay,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	endTime := time.Now()
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
@@ -2319,7 +2363,7 @@ func TestAgentLoop_OverloadExhaustedRetries(t *testing.T) {
 
 	// Override to use 1 second instead of minutes for faster test
 	// We test the mechanism, not the actual wait time
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2379,7 +2423,7 @@ func TestAgentLoop_OverloadExhaustedMaxWaitExceeded(t *testing.T) {
 		OverloadRetryMinutes: 1,                    // 1 minute wait will exceed max-wait
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2444,7 +2488,7 @@ func TestAgentLoop_OverloadExhaustedLogsToProgress(t *testing.T) {
 		OverloadRetryMinutes: 0, // Instant retry for test
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2520,7 +2564,7 @@ func TestAgentLoop_OverloadExhaustedMultipleRetries(t *testing.T) {
 		OverloadRetryMinutes: 0, // Instant retry for test
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2594,7 +2638,7 @@ func TestAgentLoop_OverloadAndRateLimitCombined(t *testing.T) {
 		OverloadRetryMinutes: 0, // Instant retry for test
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2758,7 +2802,7 @@ func TestAgentLoop_AllBlockedExitsImmediately(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2831,7 +2875,7 @@ func TestAgentLoop_NoActionableBallsExitsImmediately(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -2908,7 +2952,7 @@ func TestAgentLoop_MixedWithWorkableDoesRun(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
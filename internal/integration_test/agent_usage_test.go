@@ -0,0 +1,164 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// usageReportingRunner reports fixed token usage for every run
+type usageReportingRunner struct {
+	fileWritingMockRunner
+}
+
+func (m *usageReportingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	result, err := m.fileWritingMockRunner.Run(opts)
+	if err != nil {
+		return nil, err
+	}
+	result.InputTokens = 1200
+	result.OutputTokens = 300
+	result.CostUSD = 0.025
+	return result, nil
+}
+
+func TestAgentLoop_RecordsUsage(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	agent.SetRunner(&usageReportingRunner{fileWritingMockRunner{env: env, ballID: ball.ID}})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.InputTokens != 1200 || result.OutputTokens != 300 || result.CostUSD != 0.025 {
+		t.Errorf("Expected usage 1200/300/$0.025 in result, got %d/%d/$%v", result.InputTokens, result.OutputTokens, result.CostUSD)
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 history record, got %d (err: %v)", len(records), err)
+	}
+	if records[0].InputTokens != 1200 || records[0].OutputTokens != 300 || records[0].CostUSD != 0.025 {
+		t.Errorf("Expected usage persisted to history, got %+v", records[0])
+	}
+}
+
+// setupBudgetSession creates a session with one pending ball the mock agent never finishes
+func setupBudgetSession(t *testing.T, env *TestEnv) {
+	t.Helper()
+	env.CreateSession(t, "test-session", "Test session for budgets")
+	ball := env.CreateBall(t, "Long task", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+}
+
+func TestAgentLoop_StopsAtTokenBudget(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupBudgetSession(t, env)
+
+	working := func() *agent.RunResult {
+		return &agent.RunResult{Output: "Still working...", InputTokens: 4000, OutputTokens: 1000}
+	}
+	mock := agent.NewMockRunner(working(), working(), working(), working(), working())
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 5,
+		MaxTokens:     12000,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.BudgetExceeded {
+		t.Fatalf("Expected the run to stop on its token budget, got %+v", result)
+	}
+	if len(mock.Calls) != 3 || result.Iterations != 3 {
+		t.Errorf("Expected 3 iterations before the budget was reached, got %d (%d runner calls)", result.Iterations, len(mock.Calls))
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 history record, got %d (err: %v)", len(records), err)
+	}
+	if records[0].Result != "budget_exceeded" {
+		t.Errorf("Expected history result budget_exceeded, got %q", records[0].Result)
+	}
+}
+
+func TestAgentLoop_CostBudgetFromProjectConfig(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupBudgetSession(t, env)
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.MaxCostUSD = 0.05
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	working := func() *agent.RunResult {
+		return &agent.RunResult{Output: "Still working...", CostUSD: 0.03}
+	}
+	mock := agent.NewMockRunner(working(), working(), working())
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.BudgetExceeded || result.Iterations != 2 {
+		t.Errorf("Expected the config budget to stop the run after 2 iterations, got %d (budget exceeded: %v)", result.Iterations, result.BudgetExceeded)
+	}
+
+	// -1 lifts the configured budget for one run
+	mock = agent.NewMockRunner(working(), working(), working())
+	agent.SetRunner(mock)
+	result, err = cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+		MaxCostUSD:    -1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.BudgetExceeded || result.Iterations != 3 {
+		t.Errorf("Expected an unlimited run of 3 iterations, got %d (budget exceeded: %v)", result.Iterations, result.BudgetExceeded)
+	}
+}
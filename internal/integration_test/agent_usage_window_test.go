@@ -0,0 +1,81 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// usageThenRateLimitedRunner spends tokens on its first call and is rate
+// limited, without a retry-after hint, from then on
+type usageThenRateLimitedRunner struct {
+	calls int
+}
+
+func (m *usageThenRateLimitedRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	m.calls++
+	if m.calls == 1 {
+		return &agent.RunResult{Output: "worked on it", InputTokens: 1000, OutputTokens: 500}, nil
+	}
+	return &agent.RunResult{Output: "Error: rate limit exceeded", RateLimited: true}, nil
+}
+
+func TestAgentLoop_RateLimitWaitsForUsageWindowReset(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupGatedProject(t, env, nil)
+
+	// A 1s backoff would retry well within the test's timeout; the window
+	// resets in four hours
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Retry = &session.RetryConfig{RateLimit: &session.RetryPolicyConfig{BaseSeconds: 1, CapSeconds: 1}}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+	opts := cli.GetConfigOptions()
+	resetAt := time.Now().Add(-time.Hour)
+	if err := session.UpdateGlobalUsageWindowWithOptions(opts, &session.UsageWindowConfig{Hours: 5, ResetAt: &resetAt}); err != nil {
+		t.Fatalf("Failed to save usage window: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	runner := &usageThenRateLimitedRunner{}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(ctx, cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Expected the run to end cleanly, got error: %v", err)
+	}
+	if runner.calls != 2 {
+		t.Errorf("Expected the rate limited run to wait for the window reset, got %d agent calls", runner.calls)
+	}
+	if !result.Blocked || !strings.Contains(result.BlockedReason, "Cancelled") {
+		t.Errorf("Expected cancelled result, got %+v", result)
+	}
+
+	window, err := session.LoadUsageWindowWithOptions(opts)
+	if err != nil || window == nil {
+		t.Fatalf("LoadUsageWindowWithOptions = %v, %v", window, err)
+	}
+	if window.State.Tokens != 1500 || window.State.TotalRuns != 1 {
+		t.Errorf("Expected the first run's tokens to be recorded, got %+v", window.State)
+	}
+	if window.Capacity() != 1500 {
+		t.Errorf("Expected the capacity to be learned from the rate limit, got %d", window.Capacity())
+	}
+}
@@ -0,0 +1,164 @@
+package integration_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// setValidation saves a validation config to the project
+func setValidation(t *testing.T, env *TestEnv, validation *session.ValidationConfig) {
+	t.Helper()
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Validation = validation
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+}
+
+func TestAgentLoop_ValidationFailureReopensBall(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	setValidation(t, env, &session.ValidationConfig{Command: "echo tests broke && false"})
+
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files:  map[string]string{"config.go": "package config\n"},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if result.Complete {
+		t.Error("Expected run not to complete when validation fails")
+	}
+
+	out, _ := exec.Command("git", "-C", env.ProjectDir, "rev-list", "--count", "HEAD").Output()
+	if strings.TrimSpace(string(out)) != "1" {
+		t.Errorf("Expected no agent commit, got %s commits", strings.TrimSpace(string(out)))
+	}
+
+	updated, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if updated.State != session.StateInProgress {
+		t.Errorf("Expected ball to be reopened as in_progress, got %s", updated.State)
+	}
+
+	progress, err := env.GetSessionStore(t).LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[VALIDATION]") || !strings.Contains(progress, "tests broke") {
+		t.Errorf("Expected validation failure with output in progress, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_ValidationSkippedForDocsBall(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	ball.SetKind(session.BallKindDocs)
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	setValidation(t, env, &session.ValidationConfig{Command: "echo tests broke && false"})
+
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files:  map[string]string{"README.md": "# Usage\n"},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Complete {
+		t.Error("Expected docs ball to complete without running validation")
+	}
+
+	progress, _ := env.GetSessionStore(t).LoadProgress("test-session")
+	if strings.Contains(progress, "[VALIDATION]") {
+		t.Errorf("Expected validation to be skipped for a docs ball, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_ValidationRunsAffectedTests(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	selectedPath := filepath.Join(t.TempDir(), "selected.txt")
+
+	ball := setupGatedProject(t, env, nil)
+	setValidation(t, env, &session.ValidationConfig{
+		Command:         "false", // Full suite must not run
+		Affected:        session.AffectedJest,
+		AffectedCommand: "printf '%s\\n' >> " + selectedPath,
+	})
+
+	agent.SetRunner(&fileWritingMockRunner{
+		env:    env,
+		ballID: ball.ID,
+		files: map[string]string{
+			"app.ts":   "export const x = 1\n",
+			"notes.md": "# Notes\n",
+		},
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Complete {
+		t.Errorf("Expected run to complete, got %+v", result)
+	}
+
+	selected, err := os.ReadFile(selectedPath)
+	if err != nil {
+		t.Fatalf("Expected affected command to run: %v", err)
+	}
+	if strings.TrimSpace(string(selected)) != "app.ts" {
+		t.Errorf("Expected only app.ts selected, got %q", string(selected))
+	}
+
+	out, _ := exec.Command("git", "-C", env.ProjectDir, "rev-list", "--count", "HEAD").Output()
+	if strings.TrimSpace(string(out)) != "2" {
+		t.Errorf("Expected agent commit, got %s commits", strings.TrimSpace(string(out)))
+	}
+}
@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// verifyingMockRunner sends the agent's run to agentRunner and answers
+// verifier runs with verdict, recording their options
+type verifyingMockRunner struct {
+	agentRunner agent.Runner
+	verdict     *agent.RunResult
+	verifyCalls []agent.RunOptions
+}
+
+func (m *verifyingMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	if strings.HasPrefix(opts.Prompt, "You are reviewing another agent's work") {
+		m.verifyCalls = append(m.verifyCalls, opts)
+		return m.verdict, nil
+	}
+	return m.agentRunner.Run(opts)
+}
+
+// enableVerifyCompletion turns on verify_completion in the project config
+func enableVerifyCompletion(t *testing.T, env *TestEnv) {
+	t.Helper()
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.VerifyCompletion = true
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+}
+
+func TestAgentLoop_VerifierReopensBall(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	ball.AcceptanceCriteria = []string{"Config is documented"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	enableVerifyCompletion(t, env)
+
+	runner := &verifyingMockRunner{
+		agentRunner: &fileWritingMockRunner{env: env, ballID: ball.ID, files: map[string]string{"config.go": "package config\n"}},
+		verdict: &agent.RunResult{
+			Output:        "<promise>BLOCKED: no documentation was added</promise>",
+			Blocked:       true,
+			BlockedReason: "no documentation was added",
+		},
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.Complete {
+		t.Error("Expected the rejected completion not to finish the run")
+	}
+
+	if len(runner.verifyCalls) != 1 {
+		t.Fatalf("Expected one verifier run, got %d", len(runner.verifyCalls))
+	}
+	call := runner.verifyCalls[0]
+	if call.Model != "small" || call.Permission != agent.PermissionPlan {
+		t.Errorf("Expected a small read-only verifier, got model %q permission %q", call.Model, call.Permission)
+	}
+	if !strings.Contains(call.Prompt, "Config is documented") || !strings.Contains(call.Prompt, "config.go") {
+		t.Errorf("Expected the verifier prompt to include criteria and diff, got:\n%s", call.Prompt)
+	}
+
+	env.AssertState(t, ball.ID, session.StateInProgress)
+
+	out, _ := exec.Command("git", "-C", env.ProjectDir, "rev-list", "--count", "HEAD").Output()
+	if strings.TrimSpace(string(out)) != "1" {
+		t.Errorf("Expected no agent commit, got %s commits", strings.TrimSpace(string(out)))
+	}
+
+	progress, err := env.GetSessionStore(t).LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.Contains(progress, "[VERIFY]") || !strings.Contains(progress, "no documentation was added") {
+		t.Errorf("Expected verifier note in progress, got:\n%s", progress)
+	}
+}
+
+func TestAgentLoop_VerifierConfirmsBall(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := setupGatedProject(t, env, nil)
+	enableVerifyCompletion(t, env)
+
+	runner := &verifyingMockRunner{
+		agentRunner: &fileWritingMockRunner{env: env, ballID: ball.ID, files: map[string]string{"config.go": "package config\n"}},
+		verdict:     &agent.RunResult{Output: "<promise>COMPLETE</promise>", Complete: true},
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Complete || len(runner.verifyCalls) != 1 {
+		t.Errorf("Expected a verified, complete run, got %+v with %d verifier runs", result, len(runner.verifyCalls))
+	}
+	env.AssertState(t, ball.ID, session.StateComplete)
+
+	out, _ := exec.Command("git", "-C", env.ProjectDir, "rev-list", "--count", "HEAD").Output()
+	if strings.TrimSpace(string(out)) != "2" {
+		t.Errorf("Expected the agent commit, got %s commits", strings.TrimSpace(string(out)))
+	}
+}
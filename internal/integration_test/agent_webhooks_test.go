@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/notify"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// completeAndBlockMockRunner completes one ball and blocks another in one iteration
+type completeAndBlockMockRunner struct {
+	env                 *TestEnv
+	completeID, blockID string
+}
+
+func (m *completeAndBlockMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	store, err := session.NewStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	done, err := store.GetBallByID(m.completeID)
+	if err != nil {
+		return nil, err
+	}
+	done.MarkComplete("done")
+	if err := store.UpdateBall(done); err != nil {
+		return nil, err
+	}
+	stuck, err := store.GetBallByID(m.blockID)
+	if err != nil {
+		return nil, err
+	}
+	if err := stuck.SetBlocked("needs API credentials"); err != nil {
+		return nil, err
+	}
+	if err := store.UpdateBall(stuck); err != nil {
+		return nil, err
+	}
+
+	sessionStore, err := session.NewSessionStore(m.env.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessionStore.AppendProgress("test-session", "Completed one ball, blocked the other\n"); err != nil {
+		return nil, err
+	}
+	return &agent.RunResult{Output: "<promise>COMPLETE</promise>", Complete: true}, nil
+}
+
+func TestAgentLoop_PostsWebhooks(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	var mu sync.Mutex
+	var received []notify.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Webhook body is not an event: %v", err)
+			return
+		}
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.Webhooks = []session.WebhookConfig{{URL: server.URL}}
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for webhooks")
+	store := env.GetStore(t)
+	done := env.CreateBall(t, "Finish me", session.PriorityMedium)
+	stuck := env.CreateBall(t, "Block me", session.PriorityLow)
+	for _, ball := range []*session.Ball{done, stuck} {
+		ball.Tags = []string{"test-session"}
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	agent.SetRunner(&completeAndBlockMockRunner{env: env, completeID: done.ID, blockID: stuck.ID})
+	defer agent.ResetRunner()
+
+	if _, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	// The loop waits for delivery before returning
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{notify.EventRunStart, notify.EventBallComplete, notify.EventBallBlocked, notify.EventRunEnd}
+	if len(received) != len(want) {
+		t.Fatalf("Expected events %v, got %+v", want, received)
+	}
+	for i, event := range received {
+		if event.Type != want[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, want[i], event.Type)
+		}
+		if event.Session != "test-session" || event.Project != env.ProjectDir || event.Text == "" {
+			t.Errorf("Expected session, project and text on every event, got %+v", event)
+		}
+	}
+	if received[1].BallID != done.ID {
+		t.Errorf("Expected ball_complete for %s, got %+v", done.ID, received[1])
+	}
+	if received[2].BallID != stuck.ID || received[2].Reason != "needs API credentials" {
+		t.Errorf("Expected ball_blocked for %s with its reason, got %+v", stuck.ID, received[2])
+	}
+	if received[3].Status != "complete" || received[3].Result == nil {
+		t.Errorf("Expected a complete run_end with the result, got %+v", received[3])
+	}
+	outputFile := filepath.Join(env.ProjectDir, ".juggle", "sessions", "test-session", "last_output.txt")
+	if received[2].OutputFile != outputFile || received[3].OutputFile != outputFile {
+		t.Errorf("Expected ball_blocked and run_end to point at %s, got %q and %q", outputFile, received[2].OutputFile, received[3].OutputFile)
+	}
+	if !strings.HasPrefix(received[3].Summary, "1/2 balls complete, 1 blocked, 1 iteration in ") {
+		t.Errorf("Unexpected run_end summary: %q", received[3].Summary)
+	}
+}
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -79,7 +80,7 @@ func TestAllMetaSession_AgentRunSkipsSessionVerification(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	result, err := cli.RunAgentLoop(config)
+	result, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run with 'all' session should not require session file: %v", err)
 	}
@@ -177,7 +178,7 @@ func TestAllMetaSession_OutputDirectory(t *testing.T) {
 		IterDelay:     0,
 	}
 
-	_, err := cli.RunAgentLoop(config)
+	_, err := cli.RunAgentLoop(context.Background(), config)
 	if err != nil {
 		t.Fatalf("Agent run failed: %v", err)
 	}
@@ -0,0 +1,71 @@
+package integration_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestAgentSkipsClaimedBalls(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Mixed team")
+	store := env.GetStore(t)
+
+	ball := env.CreateBall(t, "Rework the importer", session.PriorityHigh)
+	ball.Tags = []string{"test-session"}
+	ball.Claim("alice", "halfway through it", time.Hour)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	prompt, err := cli.GenerateAgentPromptForTest(env.ProjectDir, "test-session", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	if strings.Contains(prompt, "Rework the importer") {
+		t.Error("Expected the claimed ball to be left out of the prompt")
+	}
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "worked", Continue: true})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	// Neither the session loop nor a run targeting the ball starts on it
+	for _, ballID := range []string{"", ball.ID} {
+		result, err := cli.RunAgentLoop(context.Background(), cli.AgentLoopConfig{
+			SessionID:     "test-session",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: 1,
+			BallID:        ballID,
+		})
+		if err != nil {
+			t.Fatalf("RunAgentLoop(ball %q) failed: %v", ballID, err)
+		}
+		if !result.Blocked || result.Iterations != 0 {
+			t.Errorf("RunAgentLoop(ball %q) = blocked %v after %d iterations, want blocked before starting", ballID, result.Blocked, result.Iterations)
+		}
+	}
+	if len(mock.Calls) != 0 {
+		t.Errorf("Expected no agent calls while the ball is claimed, got %d", len(mock.Calls))
+	}
+
+	ball.ReleaseClaim()
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	prompt, err = cli.GenerateAgentPromptForTest(env.ProjectDir, "test-session", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Rework the importer") {
+		t.Error("Expected the ball back in the prompt once released")
+	}
+}
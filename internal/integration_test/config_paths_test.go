@@ -143,7 +143,7 @@ func TestConfigPathsPruneNothingToRemove(t *testing.T) {
 	}
 
 	// Should indicate nothing to remove
-	if !strings.Contains(string(output), "No non-existent paths to remove") {
+	if !strings.Contains(string(output), "No non-existent or ignored paths to remove") {
 		t.Errorf("Expected message about nothing to remove, got: %s", output)
 	}
 }
@@ -1,8 +1,10 @@
 package integration_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/ohare93/juggle/internal/cli"
 	"github.com/ohare93/juggle/internal/session"
 )
 
@@ -561,3 +563,45 @@ func TestCLIDependencyMultipleOperations(t *testing.T) {
 		t.Errorf("Expected dependency %s, got %s", ball3.ID, retrieved.DependsOn[0])
 	}
 }
+
+func TestAgentPromptSkipsBallsWaitingOnDependencies(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Dependency ordering")
+	store := env.GetStore(t)
+
+	schema := env.CreateBall(t, "Design the schema", session.PriorityMedium)
+	schema.Tags = []string{"test-session"}
+	migration := env.CreateBall(t, "Write the migration", session.PriorityUrgent)
+	migration.Tags = []string{"test-session"}
+	migration.AddDependency(schema.ID)
+	for _, ball := range []*session.Ball{schema, migration} {
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	prompt, err := cli.GenerateAgentPromptForTest(env.ProjectDir, "test-session", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Design the schema") {
+		t.Error("Expected the dependency to be in the prompt")
+	}
+	if strings.Contains(prompt, "Write the migration") {
+		t.Error("Expected the waiting ball to be left out of the prompt")
+	}
+
+	schema.MarkComplete("done")
+	if err := store.UpdateBall(schema); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	prompt, err = cli.GenerateAgentPromptForTest(env.ProjectDir, "test-session", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Write the migration") {
+		t.Error("Expected the ball to be in the prompt once its dependency is complete")
+	}
+}
@@ -1427,6 +1427,29 @@ func TestExportSortsBallsWithSatisfiedDependencies(t *testing.T) {
 	})
 }
 
+// TestExportSortsBallsByHotspotWithinPriority verifies hotter balls come first
+// within a priority, but never ahead of a higher priority
+func TestExportSortsBallsByHotspotWithinPriority(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "project-1", Priority: session.PriorityMedium, State: session.StatePending},
+		{ID: "project-2", Priority: session.PriorityMedium, State: session.StatePending, Hotspot: &session.BallHotspot{Score: 3}},
+		{ID: "project-3", Priority: session.PriorityMedium, State: session.StatePending, Hotspot: &session.BallHotspot{Score: 12}},
+		{ID: "project-4", Priority: session.PriorityHigh, State: session.StatePending},
+		{ID: "project-5", Priority: session.PriorityLow, State: session.StatePending, Hotspot: &session.BallHotspot{Score: 40}},
+	}
+
+	cli.SortBallsForAgentExport(balls)
+
+	var order []string
+	for _, ball := range balls {
+		order = append(order, ball.ID)
+	}
+	want := []string{"project-4", "project-3", "project-2", "project-1", "project-5"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+}
+
 // TestExportAgentIncludesInProgressBalls verifies that in_progress balls are included in agent export
 func TestExportAgentIncludesInProgressBalls(t *testing.T) {
 	project := t.TempDir()
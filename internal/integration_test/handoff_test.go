@@ -0,0 +1,105 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestHandoff_BundlesSessionState(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runGit(env.ProjectDir, "init")
+	runGit(env.ProjectDir, "config", "user.email", "test@test.com")
+	runGit(env.ProjectDir, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(".juggle/\n"), 0644); err != nil {
+		t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "-A")
+	runGit(env.ProjectDir, "commit", "-m", "initial commit")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, "wip.txt"), []byte("half done\n"), 0644); err != nil {
+		t.Fatalf("Failed to create wip.txt: %v", err)
+	}
+	runGit(env.ProjectDir, "add", "wip.txt")
+
+	env.CreateSession(t, "handoff-session", "Ship the importer")
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if err := sessionStore.UpdateSessionContext("handoff-session", "Importer reads CSV exports."); err != nil {
+		t.Fatalf("Failed to update context: %v", err)
+	}
+	if err := sessionStore.AppendProgress("handoff-session", "[ITER 1] Parsed headers\n"); err != nil {
+		t.Fatalf("Failed to append progress: %v", err)
+	}
+
+	store := env.GetStore(t)
+	active := env.CreateInProgressBall(t, "Parse rows", session.PriorityHigh)
+	active.Tags = []string{"handoff-session"}
+	active.AcceptanceCriteria = []string{"Rows are parsed"}
+	blocked := env.CreateBall(t, "Upload results", session.PriorityMedium)
+	blocked.Tags = []string{"handoff-session"}
+	blocked.SetBlocked("Needs S3 credentials")
+	done := env.CreateBall(t, "Read headers", session.PriorityMedium)
+	done.Tags = []string{"handoff-session"}
+	done.ForceSetState(session.StateComplete)
+	other := env.CreateBall(t, "Unrelated work", session.PriorityLow)
+	for _, b := range []*session.Ball{active, blocked, done, other} {
+		if err := store.UpdateBall(b); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	bundle, err := cli.GenerateHandoffForTest(env.ProjectDir, "handoff-session", time.Now())
+	if err != nil {
+		t.Fatalf("GenerateHandoff failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"# Handoff: handoff-session",
+		"> Ship the importer",
+		"Importer reads CSV exports.",
+		"- Balls: 1 open, 1 blocked, 1 done",
+		"### " + active.ID + ": Parse rows",
+		"1. Rows are parsed",
+		"**Blocked:** Needs S3 credentials",
+		"- VCS: git",
+		"- Uncommitted changes: 1 files",
+		"`wip.txt`",
+		"[ITER 1] Parsed headers",
+		"juggle update " + blocked.ID + " --state in_progress",
+		"juggle agent run handoff-session",
+	} {
+		if !strings.Contains(bundle, want) {
+			t.Errorf("Expected handoff to contain %q, got:\n%s", want, bundle)
+		}
+	}
+
+	if strings.Contains(bundle, "Unrelated work") {
+		t.Errorf("Expected balls from other sessions to be excluded, got:\n%s", bundle)
+	}
+	if strings.Contains(bundle, "Read headers") {
+		t.Errorf("Expected completed balls to be excluded, got:\n%s", bundle)
+	}
+
+	// Blocked balls are listed before open ones so they get attention first
+	if strings.Index(bundle, "Upload results") > strings.Index(bundle, "Parse rows") {
+		t.Errorf("Expected blocked balls before open balls, got:\n%s", bundle)
+	}
+}
+
+func TestHandoff_UnknownSession(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	if _, err := cli.GenerateHandoffForTest(env.ProjectDir, "missing", time.Now()); err == nil {
+		t.Error("Expected error for unknown session")
+	}
+}
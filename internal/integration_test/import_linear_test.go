@@ -0,0 +1,91 @@
+package integration_test
+
+import (
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/linear"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestImportLinearIssues tests mapping Linear issues onto balls and sessions
+func TestImportLinearIssues(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	three := 3.0
+	issues := []linear.Issue{
+		{
+			Identifier:  "ENG-1",
+			Title:       "Fix token refresh",
+			Description: "- Refresh before expiry\n- Retry once on 401",
+			Priority:    1,
+			Estimate:    &three,
+			URL:         "https://linear.app/acme/issue/ENG-1",
+			State:       linear.State{Type: "started"},
+			Project:     &linear.Project{Name: "Auth Revamp"},
+			Labels:      []string{"bug"},
+		},
+		{Identifier: "ENG-2", Title: "Shipped already", State: linear.State{Type: "completed"}},
+		{Identifier: "ENG-3", Title: "Won't do", State: linear.State{Type: "canceled"}},
+	}
+
+	if err := cli.ImportLinearIssues(issues, env.ProjectDir, ""); err != nil {
+		t.Fatalf("ImportLinearIssues failed: %v", err)
+	}
+	// A second import skips everything already imported
+	if err := cli.ImportLinearIssues(issues, env.ProjectDir, ""); err != nil {
+		t.Fatalf("ImportLinearIssues failed: %v", err)
+	}
+
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 2 {
+		t.Fatalf("Expected 2 balls (canceled skipped, no duplicates), got %d", len(balls))
+	}
+
+	var refresh, shipped *session.Ball
+	for _, ball := range balls {
+		switch ball.Title {
+		case "Fix token refresh":
+			refresh = ball
+		case "Shipped already":
+			shipped = ball
+		}
+	}
+	if refresh == nil || shipped == nil {
+		t.Fatalf("Expected both balls, got %+v", balls)
+	}
+
+	if refresh.Priority != session.PriorityUrgent || refresh.State != session.StatePending {
+		t.Errorf("Expected an urgent pending ball, got %s %s", refresh.Priority, refresh.State)
+	}
+	if len(refresh.AcceptanceCriteria) != 2 {
+		t.Errorf("Expected 2 acceptance criteria, got %v", refresh.AcceptanceCriteria)
+	}
+	if refresh.Estimate == nil || refresh.Estimate.ModelSize != session.ModelSizeMedium {
+		t.Errorf("Expected a medium model size hint, got %+v", refresh.Estimate)
+	}
+	tags := make(map[string]bool)
+	for _, tag := range refresh.Tags {
+		tags[tag] = true
+	}
+	for _, tag := range []string{"linear#ENG-1", "bug", "auth-revamp"} {
+		if !tags[tag] {
+			t.Errorf("Expected tag %s, got %v", tag, refresh.Tags)
+		}
+	}
+	if shipped.State != session.StateComplete {
+		t.Errorf("Expected the completed issue to be a complete ball, got %s", shipped.State)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.LoadSession("auth-revamp"); err != nil {
+		t.Errorf("Expected a session for the Linear project: %v", err)
+	}
+}
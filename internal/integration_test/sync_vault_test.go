@@ -0,0 +1,122 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestSyncVault(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "auth", "Login and signup")
+	ball := env.CreateBall(t, "Add login form", session.PriorityMedium)
+	ball.Tags = []string{"auth"}
+	ball.AcceptanceCriteria = []string{"Form validates email"}
+	store := env.GetStore(t)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	gone := env.CreateBall(t, "Will be deleted", session.PriorityLow)
+
+	vaultDir := filepath.Join(env.TempDir, "vault")
+	notePath := filepath.Join(vaultDir, "balls", ball.ID+".md")
+
+	// First sync writes a note per ball and session
+	result, err := cli.SyncVault(env.ProjectDir, vaultDir)
+	if err != nil {
+		t.Fatalf("SyncVault failed: %v", err)
+	}
+	if result.Written != 3 {
+		t.Errorf("Expected 2 ball notes and 1 session note, got %+v", result)
+	}
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("Expected a note for %s: %v", ball.ID, err)
+	}
+	if !strings.Contains(string(data), "- [[auth]]") {
+		t.Errorf("Expected the note to link its session, got:\n%s", data)
+	}
+	sessionNote, err := os.ReadFile(filepath.Join(vaultDir, "sessions", "auth.md"))
+	if err != nil || !strings.Contains(string(sessionNote), "[["+ball.ID+"]]") {
+		t.Errorf("Expected the session note to link the ball, got %q (%v)", sessionNote, err)
+	}
+
+	// Nothing changed, nothing to do
+	if result, err := cli.SyncVault(env.ProjectDir, vaultDir); err != nil || result.Changed() {
+		t.Errorf("Expected a second sync to change nothing, got %+v (%v)", result, err)
+	}
+
+	// Editing the note updates the ball
+	edited := strings.Replace(string(data), "priority: medium", "priority: urgent", 1)
+	edited = strings.Replace(edited, "- [ ] Form validates email\n", "- [ ] Form validates email\n- [ ] Password has a show toggle\n", 1)
+	if err := os.WriteFile(notePath, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to edit note: %v", err)
+	}
+	// A new note becomes a ball; the deleted ball's note goes
+	if err := os.WriteFile(filepath.Join(vaultDir, "balls", "Reset password.md"), []byte("Send a reset link by email.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write new note: %v", err)
+	}
+	if err := store.DeleteBall(gone.ID); err != nil {
+		t.Fatalf("Failed to delete ball: %v", err)
+	}
+
+	result, err = cli.SyncVault(env.ProjectDir, vaultDir)
+	if err != nil {
+		t.Fatalf("SyncVault failed: %v", err)
+	}
+	if result.Applied != 1 || result.Created != 1 || result.Removed != 1 {
+		t.Errorf("Expected one update, creation and removal, got %+v", result)
+	}
+
+	updated, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if updated.Priority != session.PriorityUrgent || len(updated.AcceptanceCriteria) != 2 {
+		t.Errorf("Expected the note's edits on the ball, got %+v", updated)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "balls", gone.ID+".md")); !os.IsNotExist(err) {
+		t.Error("Expected the deleted ball's note to be removed")
+	}
+
+	balls, err := store.LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	var created *session.Ball
+	for _, b := range balls {
+		if b.Title == "Reset password" {
+			created = b
+		}
+	}
+	if created == nil || created.Context != "Send a reset link by email." {
+		t.Fatalf("Expected a ball created from the new note, got %+v", created)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "balls", created.ID+".md")); err != nil {
+		t.Errorf("Expected the new note renamed to the ball ID: %v", err)
+	}
+
+	// Changing the ball rewrites its note
+	updated.Title = "Add login and logout"
+	updated.UpdateActivity()
+	if err := store.UpdateBall(updated); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if _, err := cli.SyncVault(env.ProjectDir, vaultDir); err != nil {
+		t.Fatalf("SyncVault failed: %v", err)
+	}
+	data, _ = os.ReadFile(notePath)
+	if !strings.Contains(string(data), "# Add login and logout\n") {
+		t.Errorf("Expected the note to follow the ball, got:\n%s", data)
+	}
+
+	if result, err := cli.SyncVault(env.ProjectDir, vaultDir); err != nil || result.Changed() {
+		t.Errorf("Expected the sync to settle, got %+v (%v)", result, err)
+	}
+}
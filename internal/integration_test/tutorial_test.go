@@ -0,0 +1,101 @@
+package integration_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestTutorial_WalksThroughScratchProject(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	scratch := filepath.Join(env.TempDir, "scratch")
+	var out bytes.Buffer
+	err := cli.RunTutorial(cli.TutorialOptions{
+		Dir:       scratch,
+		Input:     strings.NewReader(""),
+		Output:    &out,
+		NoPause:   true,
+		SkipAgent: true,
+	})
+	if err != nil {
+		t.Fatalf("Tutorial failed: %v\n%s", err, out.String())
+	}
+
+	sessionStore, err := session.NewSessionStore(scratch)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.LoadSession("tutorial"); err != nil {
+		t.Errorf("Expected tutorial session to be created: %v", err)
+	}
+
+	store, err := session.NewStore(scratch)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil || len(balls) != 1 {
+		t.Fatalf("Expected 1 ball, got %d (err: %v)", len(balls), err)
+	}
+	ball := balls[0]
+	if ball.Title != "Create hello.txt" || len(ball.AcceptanceCriteria) != 2 {
+		t.Errorf("Unexpected tutorial ball: %+v", ball)
+	}
+	if len(ball.Tags) != 1 || ball.Tags[0] != "tutorial" {
+		t.Errorf("Expected ball tagged with the tutorial session, got %v", ball.Tags)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"Step 1/6: Check your agent provider",
+		"$ juggle sessions create tutorial",
+		"$ juggle agent run tutorial --ball " + ball.ID + " --dry-run",
+		"The full prompt is",
+		"Skipped (--skip-agent)",
+		"rm -rf " + scratch,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected tutorial output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestTutorial_DeclinedAgentRunIsSkipped(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	scratch := filepath.Join(env.TempDir, "scratch")
+	var out bytes.Buffer
+	// Five Enters for the pauses between steps, then decline the agent run
+	err := cli.RunTutorial(cli.TutorialOptions{
+		Dir:    scratch,
+		Input:  strings.NewReader("\n\n\n\n\nn\n"),
+		Output: &out,
+	})
+	if err != nil {
+		t.Fatalf("Tutorial failed: %v\n%s", err, out.String())
+	}
+
+	output := out.String()
+	if strings.Count(output, "Press Enter to continue") != 5 {
+		t.Errorf("Expected a pause before each step after the first, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Skipped") {
+		t.Errorf("Expected the agent step to be skipped, got:\n%s", output)
+	}
+
+	// Running again in the same directory reuses the session
+	out.Reset()
+	if err := cli.RunTutorial(cli.TutorialOptions{Dir: scratch, Output: &out, NoPause: true, SkipAgent: true}); err != nil {
+		t.Fatalf("Second tutorial run failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "already exists, reusing it") {
+		t.Errorf("Expected the existing session to be reused, got:\n%s", out.String())
+	}
+}
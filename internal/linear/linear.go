@@ -0,0 +1,254 @@
+// Package linear is a small client for the Linear GraphQL API, covering what
+// juggle import linear needs: listing issues and moving an issue to a
+// completed workflow state.
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultURL is the Linear GraphQL endpoint
+const DefaultURL = "https://api.linear.app/graphql"
+
+// pageSize is how many issues are requested per page
+const pageSize = 50
+
+// Issue is a Linear issue with the fields juggle maps onto balls
+type Issue struct {
+	ID          string   `json:"id"`
+	Identifier  string   `json:"identifier"` // Team key and number, e.g. "ENG-123"
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority"` // 0 none, 1 urgent, 2 high, 3 medium, 4 low
+	Estimate    *float64 `json:"estimate"` // Points in the team's estimate scale, nil when unestimated
+	URL         string   `json:"url"`
+	State       State    `json:"state"`
+	Project     *Project `json:"project"`
+	Labels      []string `json:"-"`
+}
+
+// State is an issue's workflow state
+type State struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // triage, backlog, unstarted, started, completed or canceled
+}
+
+// Done reports whether the state is completed or canceled
+func (s State) Done() bool {
+	return s.Type == "completed" || s.Type == "canceled"
+}
+
+// Project is the Linear project an issue belongs to
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IssueFilter selects the issues to list
+type IssueFilter struct {
+	Team          string // Team key, e.g. "ENG" (empty = all teams)
+	Project       string // Project name (empty = any project)
+	Label         string // Label name (empty = any label)
+	IncludeClosed bool   // Include completed and canceled issues
+	Limit         int    // Maximum number of issues (0 = no limit)
+}
+
+// Client calls the Linear API with a personal API key
+type Client struct {
+	APIKey string
+	URL    string // GraphQL endpoint, DefaultURL unless overridden (tests)
+	HTTP   *http.Client
+}
+
+// NewClient creates a client for the Linear API
+func NewClient(apiKey string) *Client {
+	return &Client{APIKey: apiKey, URL: DefaultURL, HTTP: http.DefaultClient}
+}
+
+const issuesQuery = `query Issues($filter: IssueFilter, $first: Int, $after: String) {
+  issues(filter: $filter, first: $first, after: $after) {
+    nodes {
+      id identifier title description priority estimate url
+      state { id name type }
+      project { id name }
+      labels { nodes { name } }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// Issues lists the issues matching the filter, following pages until the
+// limit is reached
+func (c *Client) Issues(ctx context.Context, filter IssueFilter) ([]Issue, error) {
+	gqlFilter := map[string]any{}
+	if filter.Team != "" {
+		gqlFilter["team"] = map[string]any{"key": map[string]any{"eq": filter.Team}}
+	}
+	if filter.Project != "" {
+		gqlFilter["project"] = map[string]any{"name": map[string]any{"eq": filter.Project}}
+	}
+	if filter.Label != "" {
+		gqlFilter["labels"] = map[string]any{"name": map[string]any{"eq": filter.Label}}
+	}
+	if !filter.IncludeClosed {
+		gqlFilter["state"] = map[string]any{"type": map[string]any{"nin": []string{"completed", "canceled"}}}
+	}
+
+	var issues []Issue
+	var after *string
+	for {
+		first := pageSize
+		if filter.Limit > 0 && filter.Limit-len(issues) < first {
+			first = filter.Limit - len(issues)
+		}
+
+		var data struct {
+			Issues struct {
+				Nodes []struct {
+					Issue
+					Labels struct {
+						Nodes []struct {
+							Name string `json:"name"`
+						} `json:"nodes"`
+					} `json:"labels"`
+				} `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"issues"`
+		}
+		vars := map[string]any{"filter": gqlFilter, "first": first, "after": after}
+		if err := c.do(ctx, issuesQuery, vars, &data); err != nil {
+			return nil, err
+		}
+
+		for _, node := range data.Issues.Nodes {
+			issue := node.Issue
+			for _, label := range node.Labels.Nodes {
+				issue.Labels = append(issue.Labels, label.Name)
+			}
+			issues = append(issues, issue)
+		}
+		if !data.Issues.PageInfo.HasNextPage || (filter.Limit > 0 && len(issues) >= filter.Limit) {
+			return issues, nil
+		}
+		cursor := data.Issues.PageInfo.EndCursor
+		after = &cursor
+	}
+}
+
+const issueStatesQuery = `query IssueStates($id: String!) {
+  issue(id: $id) {
+    id
+    state { id name type }
+    team { states { nodes { id name type position } } }
+  }
+}`
+
+const completeIssueMutation = `mutation CompleteIssue($id: String!, $stateId: String!) {
+  issueUpdate(id: $id, input: { stateId: $stateId }) { success }
+}`
+
+// CompleteIssue moves an issue, by ID or identifier, to its team's first
+// completed workflow state. It returns the state moved to, or an empty state
+// when the issue was already completed or canceled.
+func (c *Client) CompleteIssue(ctx context.Context, id string) (State, error) {
+	var data struct {
+		Issue struct {
+			ID    string `json:"id"`
+			State State  `json:"state"`
+			Team  struct {
+				States struct {
+					Nodes []struct {
+						State
+						Position float64 `json:"position"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	}
+	if err := c.do(ctx, issueStatesQuery, map[string]any{"id": id}, &data); err != nil {
+		return State{}, err
+	}
+	if data.Issue.State.Done() {
+		return State{}, nil
+	}
+
+	var done State
+	position := 0.0
+	for _, state := range data.Issue.Team.States.Nodes {
+		if state.Type == "completed" && (done.ID == "" || state.Position < position) {
+			done, position = state.State, state.Position
+		}
+	}
+	if done.ID == "" {
+		return State{}, fmt.Errorf("%s: team has no completed workflow state", id)
+	}
+
+	var result struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+	vars := map[string]any{"id": data.Issue.ID, "stateId": done.ID}
+	if err := c.do(ctx, completeIssueMutation, vars, &result); err != nil {
+		return State{}, err
+	}
+	if !result.IssueUpdate.Success {
+		return State{}, fmt.Errorf("%s: update was not applied", id)
+	}
+	return done, nil
+}
+
+// do sends a GraphQL request and decodes its data into out
+func (c *Client) do(ctx context.Context, query string, vars map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.APIKey)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("linear request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read linear response: %w", err)
+	}
+
+	var parsed struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return fmt.Errorf("linear returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if len(parsed.Errors) > 0 {
+		var messages []string
+		for _, e := range parsed.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("linear returned %s: %s", resp.Status, strings.Join(messages, "; "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("linear returned %s", resp.Status)
+	}
+	return json.Unmarshal(parsed.Data, out)
+}
@@ -0,0 +1,141 @@
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// graphqlRequest is a request body as the test server sees it
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+func newTestClient(t *testing.T, handle func(req graphqlRequest) string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_api_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"message":"Authentication required"}]}`))
+			return
+		}
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(handle(req)))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("lin_api_test")
+	client.URL = server.URL
+	return client
+}
+
+func TestIssues(t *testing.T) {
+	var filters []any
+	client := newTestClient(t, func(req graphqlRequest) string {
+		filters = append(filters, req.Variables["filter"])
+		if req.Variables["after"] == nil {
+			return `{"data":{"issues":{"nodes":[
+				{"id":"u1","identifier":"ENG-1","title":"First","priority":1,"estimate":3,
+				 "state":{"name":"Todo","type":"unstarted"},"project":{"id":"p1","name":"Auth Revamp"},
+				 "labels":{"nodes":[{"name":"bug"},{"name":"backend"}]}}
+			],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`
+		}
+		return `{"data":{"issues":{"nodes":[
+			{"id":"u2","identifier":"ENG-2","title":"Second","priority":0,"estimate":null,
+			 "state":{"name":"In Progress","type":"started"},"project":null,"labels":{"nodes":[]}}
+		],"pageInfo":{"hasNextPage":false}}}}`
+	})
+
+	issues, err := client.Issues(context.Background(), IssueFilter{Team: "ENG"})
+	if err != nil {
+		t.Fatalf("Issues failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues across pages, got %d", len(issues))
+	}
+	first := issues[0]
+	if first.Identifier != "ENG-1" || first.Priority != 1 || first.Estimate == nil || *first.Estimate != 3 ||
+		first.Project == nil || first.Project.Name != "Auth Revamp" || strings.Join(first.Labels, ",") != "bug,backend" {
+		t.Errorf("unexpected first issue: %+v", first)
+	}
+	if issues[1].Estimate != nil || issues[1].Project != nil {
+		t.Errorf("expected no estimate or project on the second issue, got %+v", issues[1])
+	}
+
+	filter, _ := json.Marshal(filters[0])
+	if !strings.Contains(string(filter), `"team":{"key":{"eq":"ENG"}}`) || !strings.Contains(string(filter), `"nin":["completed","canceled"]`) {
+		t.Errorf("unexpected filter: %s", filter)
+	}
+}
+
+func TestIssues_Limit(t *testing.T) {
+	client := newTestClient(t, func(req graphqlRequest) string {
+		if req.Variables["first"].(float64) != 1 {
+			t.Errorf("expected first=1, got %v", req.Variables["first"])
+		}
+		return `{"data":{"issues":{"nodes":[{"id":"u1","identifier":"ENG-1","title":"Only","labels":{"nodes":[]}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`
+	})
+
+	issues, err := client.Issues(context.Background(), IssueFilter{Limit: 1, IncludeClosed: true})
+	if err != nil {
+		t.Fatalf("Issues failed: %v", err)
+	}
+	if len(issues) != 1 {
+		t.Errorf("expected the limit to stop paging, got %d issues", len(issues))
+	}
+}
+
+func TestIssues_Errors(t *testing.T) {
+	client := newTestClient(t, func(req graphqlRequest) string { return "" })
+	client.APIKey = "wrong"
+	if _, err := client.Issues(context.Background(), IssueFilter{}); err == nil || !strings.Contains(err.Error(), "Authentication required") {
+		t.Errorf("expected the API's error message, got %v", err)
+	}
+}
+
+func TestCompleteIssue(t *testing.T) {
+	var updated map[string]any
+	client := newTestClient(t, func(req graphqlRequest) string {
+		if strings.Contains(req.Query, "issueUpdate") {
+			updated = req.Variables
+			return `{"data":{"issueUpdate":{"success":true}}}`
+		}
+		if req.Variables["id"] == "ENG-9" {
+			return `{"data":{"issue":{"id":"u9","state":{"id":"s4","name":"Done","type":"completed"},"team":{"states":{"nodes":[]}}}}}`
+		}
+		return `{"data":{"issue":{"id":"u1","state":{"id":"s1","name":"Todo","type":"unstarted"},
+			"team":{"states":{"nodes":[
+				{"id":"s1","name":"Todo","type":"unstarted","position":1},
+				{"id":"s5","name":"Released","type":"completed","position":5},
+				{"id":"s4","name":"Done","type":"completed","position":4}
+			]}}}}}`
+	})
+
+	state, err := client.CompleteIssue(context.Background(), "ENG-1")
+	if err != nil {
+		t.Fatalf("CompleteIssue failed: %v", err)
+	}
+	if state.Name != "Done" {
+		t.Errorf("expected the first completed state, got %+v", state)
+	}
+	if updated["id"] != "u1" || updated["stateId"] != "s4" {
+		t.Errorf("unexpected update: %v", updated)
+	}
+
+	updated = nil
+	state, err = client.CompleteIssue(context.Background(), "ENG-9")
+	if err != nil {
+		t.Fatalf("CompleteIssue failed: %v", err)
+	}
+	if state.ID != "" || updated != nil {
+		t.Errorf("expected an already completed issue to be left alone, got %+v", state)
+	}
+}
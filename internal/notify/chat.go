@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// ChatEvents are the events Slack and Discord webhooks get when they list
+// none: run summaries and blocked-ball alerts
+var ChatEvents = []string{EventBallBlocked, EventRunEnd}
+
+// discordMaxContent is the longest message Discord accepts
+const discordMaxContent = 2000
+
+// chatMessage writes an event as a short message for a chat channel. bold
+// wraps text in the channel's bold markup.
+func chatMessage(event Event, bold func(string) string) string {
+	where := fmt.Sprintf("session `%s` (%s)", event.Session, filepath.Base(event.Project))
+	var lines []string
+	switch event.Type {
+	case EventBallBlocked:
+		lines = append(lines,
+			fmt.Sprintf("%s in %s", bold("Ball blocked"), where),
+			fmt.Sprintf("%s (`%s`)", bold(event.Title), event.BallID),
+			"Reason: "+event.Reason)
+	case EventRunEnd:
+		lines = append(lines, fmt.Sprintf("%s in %s", bold("Agent run ended: "+event.Status), where))
+		if event.Summary != "" {
+			lines = append(lines, event.Summary)
+		}
+		if event.Reason != "" {
+			lines = append(lines, "Reason: "+event.Reason)
+		}
+	default:
+		return strings.TrimPrefix(event.Text, "juggle: ")
+	}
+	if event.OutputFile != "" {
+		lines = append(lines, fmt.Sprintf("Last output: `%s`", event.OutputFile))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// chatPayload is the body a Slack or Discord webhook posts for an event
+func chatPayload(format string, event Event) any {
+	if format == session.WebhookFormatDiscord {
+		content := []rune(chatMessage(event, func(s string) string { return "**" + s + "**" }))
+		if len(content) > discordMaxContent {
+			content = append(content[:discordMaxContent-1], '…')
+		}
+		return map[string]string{"username": "juggle", "content": string(content)}
+	}
+	// Slack reads &, < and > as markup, so they must be escaped
+	text := chatMessage(event, func(s string) string { return "*" + s + "*" })
+	text = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+	return map[string]string{"text": text}
+}
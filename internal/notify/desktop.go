@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// DefaultDesktopEvents are the events desktop notifications show when the
+// config lists none: the ones that want someone's attention
+var DefaultDesktopEvents = []string{EventBallBlocked, EventRateLimitWait, EventRunEnd}
+
+// powershellAppID shows toasts as coming from PowerShell, which Windows knows
+// about without juggle registering an app of its own
+const powershellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// runDesktopCommand runs the program that shows a notification. Tests
+// replace it.
+var runDesktopCommand = func(name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil && len(out) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return err
+}
+
+// desktop shows events as OS notifications on this machine
+type desktop struct {
+	events  []string
+	minWait time.Duration
+	goos    string
+}
+
+// Desktop returns a channel for desktop notifications, or nil when they
+// aren't enabled
+func Desktop(cfg *session.DesktopNotificationsConfig) Channel {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	events := cfg.Events
+	if len(events) == 0 {
+		events = DefaultDesktopEvents
+	}
+	return &desktop{events: events, minWait: cfg.GetRateLimitWait(), goos: runtime.GOOS}
+}
+
+func (d *desktop) String() string {
+	return "desktop notification"
+}
+
+// Wants reports whether the event is one to show; rate-limit waits are only
+// shown when they're long enough to hold the run up
+func (d *desktop) Wants(event Event) bool {
+	if !subscribed(d.events, event.Type) {
+		return false
+	}
+	return event.Type != EventRateLimitWait || event.Wait() >= d.minWait
+}
+
+// Deliver shows the event's text
+func (d *desktop) Deliver(event Event) error {
+	title := "juggle"
+	if event.Session != "" {
+		title += ": " + event.Session
+	}
+	name, args := desktopCommand(d.goos, title, strings.TrimPrefix(event.Text, "juggle: "))
+	return runDesktopCommand(name, args...)
+}
+
+// desktopCommand returns the command that shows a notification on an OS
+func desktopCommand(goos, title, body string) (string, []string) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}
+	case "windows":
+		script := strings.Join([]string{
+			"$ErrorActionPreference = 'Stop'",
+			"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null",
+			"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+			"$text = $xml.GetElementsByTagName('text')",
+			"$text.Item(0).AppendChild($xml.CreateTextNode(" + powershellString(title) + ")) | Out-Null",
+			"$text.Item(1).AppendChild($xml.CreateTextNode(" + powershellString(body) + ")) | Out-Null",
+			"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(" + powershellString(powershellAppID) + ").Show([Windows.UI.Notifications.ToastNotification]::new($xml))",
+		}, "; ")
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	default:
+		return "notify-send", []string{"--app-name=juggle", title, body}
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// powershellString quotes s as a PowerShell single-quoted string literal
+func powershellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestDesktopWants(t *testing.T) {
+	d := Desktop(&session.DesktopNotificationsConfig{Enabled: true, RateLimitWaitMinutes: 10})
+
+	tests := []struct {
+		event Event
+		want  bool
+	}{
+		{Event{Type: EventRunEnd}, true},
+		{Event{Type: EventBallBlocked}, true},
+		{Event{Type: EventRunStart}, false},
+		{Event{Type: EventBallComplete}, false},
+		{Event{Type: EventRateLimitWait, WaitSeconds: (5 * time.Minute).Seconds()}, false},
+		{Event{Type: EventRateLimitWait, WaitSeconds: (10 * time.Minute).Seconds()}, true},
+	}
+	for _, tt := range tests {
+		if got := d.Wants(tt.event); got != tt.want {
+			t.Errorf("Wants(%s, wait %v) = %v, want %v", tt.event.Type, tt.event.Wait(), got, tt.want)
+		}
+	}
+
+	d = Desktop(&session.DesktopNotificationsConfig{Enabled: true, Events: []string{EventBallComplete}})
+	if !d.Wants(Event{Type: EventBallComplete}) || d.Wants(Event{Type: EventRunEnd}) {
+		t.Error("Expected configured events to replace the defaults")
+	}
+}
+
+func TestDesktopCommand(t *testing.T) {
+	name, args := desktopCommand("linux", "juggle: auth", "myapp-a1b2 blocked: needs a key")
+	if name != "notify-send" || !slices.Equal(args, []string{"--app-name=juggle", "juggle: auth", "myapp-a1b2 blocked: needs a key"}) {
+		t.Errorf("Unexpected Linux command: %s %q", name, args)
+	}
+
+	name, args = desktopCommand("darwin", "juggle", `say "hi" \ bye`)
+	if name != "osascript" || len(args) != 2 || args[1] != `display notification "say \"hi\" \\ bye" with title "juggle"` {
+		t.Errorf("Unexpected macOS command: %s %q", name, args)
+	}
+
+	name, args = desktopCommand("windows", "juggle", "it's done")
+	if name != "powershell" || !strings.Contains(args[len(args)-1], "CreateTextNode('it''s done')") {
+		t.Errorf("Unexpected Windows command: %s %q", name, args)
+	}
+}
+
+func TestDesktopDelivers(t *testing.T) {
+	var shown [][]string
+	orig := runDesktopCommand
+	runDesktopCommand = func(name string, args ...string) error {
+		shown = append(shown, append([]string{name}, args...))
+		return nil
+	}
+	defer func() { runDesktopCommand = orig }()
+
+	d := Desktop(&session.DesktopNotificationsConfig{Enabled: true}).(*desktop)
+	d.goos = "linux"
+	n := New(d)
+	n.Send(Event{Type: EventRunStart, Project: "/work/myapp", Session: "auth"})
+	n.Send(Event{Type: EventRateLimitWait, Project: "/work/myapp", Session: "auth", WaitSeconds: 3600})
+	n.Send(Event{Type: EventRunEnd, Project: "/work/myapp", Session: "auth", Status: "complete"})
+	n.Close()
+
+	if len(shown) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d: %q", len(shown), shown)
+	}
+	if body := shown[0][len(shown[0])-1]; body != "agent run on auth (myapp) rate limited, waiting 1h0m0s" {
+		t.Errorf("Unexpected rate_limit_wait body: %q", body)
+	}
+	if body := shown[1][len(shown[1])-1]; body != "agent run on auth (myapp) ended: complete" {
+		t.Errorf("Unexpected run_end body: %q", body)
+	}
+}
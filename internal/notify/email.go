@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// EmailEvents are the events emailed when the config lists none: the end of
+// a run and a ball that needs a human
+var EmailEvents = []string{EventBallBlocked, EventRunEnd}
+
+// smtpsPort is the SMTP port that speaks TLS from the start
+const smtpsPort = 465
+
+// email sends each event as a plain text email over SMTP
+type email struct {
+	cfg    *session.EmailConfig
+	events []string
+}
+
+// Email returns a channel that emails events, or nil when no server or
+// recipients are configured
+func Email(cfg *session.EmailConfig) Channel {
+	if cfg == nil || cfg.Host == "" || len(cfg.To) == 0 {
+		return nil
+	}
+	events := cfg.Events
+	if len(events) == 0 {
+		events = EmailEvents
+	}
+	return &email{cfg: cfg, events: events}
+}
+
+func (e *email) String() string {
+	return "email to " + strings.Join(e.cfg.To, ", ")
+}
+
+// Wants reports whether the event is one to email
+func (e *email) Wants(event Event) bool {
+	return subscribed(e.events, event.Type)
+}
+
+// Deliver sends the event as an email, with STARTTLS when the server offers
+// it (or implicit TLS on port 465) and a login when a username is set
+func (e *email) Deliver(event Event) error {
+	host := e.cfg.Host
+	addr := net.JoinHostPort(host, strconv.Itoa(e.cfg.GetPort()))
+	tlsConfig := &tls.Config{ServerName: host}
+
+	dialer := &net.Dialer{Timeout: Timeout}
+	var conn net.Conn
+	var err error
+	if e.cfg.GetPort() == smtpsPort {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(Timeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && e.cfg.GetPort() != smtpsPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.GetPassword(), host)); err != nil {
+			return fmt.Errorf("login: %w", err)
+		}
+	}
+	if err := client.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range e.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("recipient %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(emailMessage(e.cfg.From, e.cfg.To, event)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// emailMessage writes an event as a plain text email, its subject the
+// event's one-line text
+func emailMessage(from string, to []string, event Event) []byte {
+	var msg bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", event.Text))
+	header("Date", event.Time.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	msg.WriteString("\r\n")
+
+	for _, line := range emailBody(event) {
+		msg.WriteString(line + "\r\n")
+	}
+	return msg.Bytes()
+}
+
+// emailBody lists what a reader needs to act on an event, one fact a line
+func emailBody(event Event) []string {
+	lines := []string{strings.TrimPrefix(event.Text, "juggle: "), ""}
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	field("Project", event.Project)
+	field("Session", event.Session)
+	if event.Daemon {
+		field("Run", "daemon")
+	}
+	field("Ball", event.BallID)
+	field("Title", event.Title)
+	field("Status", event.Status)
+	field("Summary", event.Summary)
+	field("Reason", event.Reason)
+	if event.Iteration > 0 {
+		field("Iteration", strconv.Itoa(event.Iteration))
+	}
+	field("Last output", event.OutputFile)
+	return lines
+}
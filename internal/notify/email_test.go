@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// smtpServer accepts one SMTP session and returns the commands and message
+// it received
+func smtpServer(t *testing.T) (port int, received <-chan []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var lines []string
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ready")
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case inData && line == ".":
+				inData = false
+				reply("250 queued")
+			case inData:
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(line, "AUTH"):
+				reply("235 ok")
+			case line == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case line == "QUIT":
+				reply("221 bye")
+				out <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+		out <- lines
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, out
+}
+
+func TestEmailDelivers(t *testing.T) {
+	port, received := smtpServer(t)
+	t.Setenv(session.EnvSMTPPassword, "secret")
+
+	n := New(Email(&session.EmailConfig{
+		Host:     "localhost",
+		Port:     port,
+		Username: "juggle",
+		From:     "juggle@example.com",
+		To:       []string{"me@example.com"},
+	}))
+	n.Send(Event{Type: EventRunStart, Project: "/work/myapp", Session: "auth"})
+	n.Send(Event{Type: EventRunEnd, Project: "/work/myapp", Session: "auth", Daemon: true, Status: "complete", Summary: "2/2 balls complete, 0 blocked, 4 iterations in 1h 5m", OutputFile: "/work/myapp/.juggle/sessions/auth/last_output.txt"})
+	n.Close()
+
+	var lines []string
+	select {
+	case lines = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP server received nothing")
+	}
+	transcript := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"AUTH PLAIN",
+		"MAIL FROM:<juggle@example.com>",
+		"RCPT TO:<me@example.com>",
+		"Subject: juggle: agent run on auth (myapp) ended: complete",
+		"Run: daemon",
+		"Summary: 2/2 balls complete, 0 blocked, 4 iterations in 1h 5m",
+		"Last output: /work/myapp/.juggle/sessions/auth/last_output.txt",
+	} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("Expected %q in the SMTP session:\n%s", want, transcript)
+		}
+	}
+	if strings.Contains(transcript, "run_start") || strings.Contains(transcript, "started") {
+		t.Errorf("Expected run_start not to be emailed:\n%s", transcript)
+	}
+}
+
+func TestEmailNeedsServerAndRecipients(t *testing.T) {
+	for _, cfg := range []*session.EmailConfig{
+		nil,
+		{From: "juggle@example.com", To: []string{"me@example.com"}},
+		{Host: "smtp.example.com", From: "juggle@example.com"},
+	} {
+		if Email(cfg) != nil {
+			t.Errorf("Expected no email channel for %+v", cfg)
+		}
+	}
+	if got := Email(&session.EmailConfig{Host: "smtp.example.com", To: []string{"me@example.com"}}).String(); got != "email to me@example.com" {
+		t.Errorf("Unexpected channel name: %q", got)
+	}
+}
@@ -0,0 +1,194 @@
+// Package notify delivers agent run lifecycle events to the channels
+// configured for a run: a project's webhooks, so chat channels and CI can
+// follow runs, foreground or daemon, without polling .juggle, and desktop
+// notifications on the machine the run is on.
+//
+// Webhooks get each event as one JSON object:
+//
+//	{
+//	  "event": "ball_complete",
+//	  "time": "2026-01-02T15:04:05Z",
+//	  "project": "/home/me/myapp",
+//	  "session": "auth",
+//	  "iteration": 3,
+//	  "ball_id": "myapp-a1b2c3d4",
+//	  "title": "Add login form",
+//	  "text": "juggle: myapp-a1b2c3d4 complete: Add login form"
+//	}
+//
+// "text" is a one-line summary, which is what desktop notifications display.
+// Webhooks with a "slack" or "discord" format get a chat message instead (see
+// chatMessage).
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Event types
+const (
+	EventRunStart      = "run_start"
+	EventBallComplete  = "ball_complete"
+	EventBallBlocked   = "ball_blocked"
+	EventRateLimitWait = "rate_limit_wait"
+	EventRunEnd        = "run_end"
+)
+
+// EventTypes lists every event type a channel can subscribe to
+var EventTypes = []string{EventRunStart, EventBallComplete, EventBallBlocked, EventRateLimitWait, EventRunEnd}
+
+// Timeout caps each delivery so a slow endpoint can't hold up a run
+const Timeout = 10 * time.Second
+
+// queueSize is how many events can wait for delivery before Send blocks
+const queueSize = 64
+
+// Event is the JSON payload POSTed to webhooks. Fields that don't apply to an
+// event type are left out.
+type Event struct {
+	Type          string    `json:"event"`
+	Time          time.Time `json:"time"`
+	Project       string    `json:"project"`
+	Session       string    `json:"session"`
+	Daemon        bool      `json:"daemon,omitempty"` // The run is a background daemon
+	Iteration     int       `json:"iteration,omitempty"`
+	MaxIterations int       `json:"max_iterations,omitempty"`
+	BallID        string    `json:"ball_id,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	Reason        string    `json:"reason,omitempty"`       // Why the ball is blocked
+	WaitSeconds   float64   `json:"wait_seconds,omitempty"` // How long the run waits out a rate limit
+	Status        string    `json:"status,omitempty"`       // How the run ended
+	Summary       string    `json:"summary,omitempty"`      // What the run got done, on run_end
+	OutputFile    string    `json:"output_file,omitempty"`  // The agent's last output (last_output.txt)
+	Result        any       `json:"result,omitempty"`       // The run's full result, on run_end
+	Text          string    `json:"text"`
+}
+
+// Wait is how long the run waits out a rate limit
+func (e Event) Wait() time.Duration {
+	return time.Duration(e.WaitSeconds * float64(time.Second))
+}
+
+// summary is the event's one-line text
+func (e Event) summary() string {
+	switch e.Type {
+	case EventRunStart:
+		return fmt.Sprintf("juggle: agent run started on %s (%s)", e.Session, filepath.Base(e.Project))
+	case EventBallComplete:
+		return fmt.Sprintf("juggle: %s complete: %s", e.BallID, e.Title)
+	case EventBallBlocked:
+		return fmt.Sprintf("juggle: %s blocked: %s", e.BallID, e.Reason)
+	case EventRateLimitWait:
+		return fmt.Sprintf("juggle: agent run on %s (%s) rate limited, waiting %v", e.Session, filepath.Base(e.Project), e.Wait().Round(time.Second))
+	case EventRunEnd:
+		return fmt.Sprintf("juggle: agent run on %s (%s) ended: %s", e.Session, filepath.Base(e.Project), e.Status)
+	default:
+		return "juggle: " + e.Type
+	}
+}
+
+// Channel is somewhere events are delivered: a webhook or the desktop
+type Channel interface {
+	// Wants reports whether the channel takes an event
+	Wants(event Event) bool
+	// Deliver sends one event
+	Deliver(event Event) error
+	// String names the channel in delivery warnings, e.g. "webhook to https://..."
+	String() string
+}
+
+// Notifier delivers events to its channels in the order they were sent,
+// from a background goroutine. A nil Notifier drops events.
+type Notifier struct {
+	channels []Channel
+	queue    chan Event
+	done     chan struct{}
+
+	// Errors receives delivery warnings (default: stderr)
+	Errors io.Writer
+}
+
+// New starts a notifier for the given channels, skipping nil ones, or
+// returns nil when there are none
+func New(channels ...Channel) *Notifier {
+	var active []Channel
+	for _, c := range channels {
+		if c != nil {
+			active = append(active, c)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+	n := &Notifier{
+		channels: active,
+		queue:    make(chan Event, queueSize),
+		done:     make(chan struct{}),
+		Errors:   os.Stderr,
+	}
+	go n.deliver()
+	return n
+}
+
+// Enabled reports whether events go anywhere
+func (n *Notifier) Enabled() bool {
+	return n != nil
+}
+
+// Send queues an event, stamping its time and text if they're unset
+func (n *Notifier) Send(event Event) {
+	if n == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Text == "" {
+		event.Text = event.summary()
+	}
+	n.queue <- event
+}
+
+// Close delivers the queued events and stops the notifier. Send must not be
+// called after Close.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	close(n.queue)
+	<-n.done
+}
+
+// deliver hands queued events to the channels that want them until the
+// queue is closed
+func (n *Notifier) deliver() {
+	defer close(n.done)
+	for event := range n.queue {
+		for _, c := range n.channels {
+			if !c.Wants(event) {
+				continue
+			}
+			if err := c.Deliver(event); err != nil {
+				fmt.Fprintf(n.Errors, "Warning: %s %s failed: %v\n", event.Type, c, err)
+			}
+		}
+	}
+}
+
+// subscribed reports whether an event type is in a channel's list; an
+// empty list means every event
+func subscribed(events []string, eventType string) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// recorder is a webhook endpoint that keeps the payloads it receives
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+	auth   []string
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.auth = append(r.auth, req.Header.Get("Authorization"))
+	r.mu.Unlock()
+}
+
+func TestNotifierDeliversInOrder(t *testing.T) {
+	all, blockedOnly := &recorder{}, &recorder{}
+	allServer := httptest.NewServer(all)
+	defer allServer.Close()
+	blockedServer := httptest.NewServer(blockedOnly)
+	defer blockedServer.Close()
+
+	n := New(Webhooks([]session.WebhookConfig{
+		{URL: allServer.URL, Headers: map[string]string{"Authorization": "Bearer token"}},
+		{URL: blockedServer.URL, Events: []string{EventBallBlocked}},
+	})...)
+	n.Send(Event{Type: EventRunStart, Project: "/work/myapp", Session: "auth"})
+	n.Send(Event{Type: EventBallBlocked, Session: "auth", BallID: "myapp-a1b2", Reason: "needs a key"})
+	n.Send(Event{Type: EventRunEnd, Project: "/work/myapp", Session: "auth", Status: "blocked"})
+	n.Close()
+
+	if len(all.events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(all.events))
+	}
+	for i, want := range []string{EventRunStart, EventBallBlocked, EventRunEnd} {
+		if all.events[i].Type != want {
+			t.Errorf("Event %d: expected %s, got %s", i, want, all.events[i].Type)
+		}
+		if all.auth[i] != "Bearer token" {
+			t.Errorf("Event %d: expected the configured Authorization header, got %q", i, all.auth[i])
+		}
+	}
+	if all.events[0].Time.IsZero() {
+		t.Error("Expected events to be stamped with the time")
+	}
+	if got := all.events[0].Text; got != "juggle: agent run started on auth (myapp)" {
+		t.Errorf("Unexpected run_start text: %q", got)
+	}
+	if got := all.events[2].Text; got != "juggle: agent run on auth (myapp) ended: blocked" {
+		t.Errorf("Unexpected run_end text: %q", got)
+	}
+
+	if len(blockedOnly.events) != 1 || blockedOnly.events[0].Type != EventBallBlocked {
+		t.Fatalf("Expected only the ball_blocked event, got %+v", blockedOnly.events)
+	}
+	if got := blockedOnly.events[0].Text; got != "juggle: myapp-a1b2 blocked: needs a key" {
+		t.Errorf("Unexpected ball_blocked text: %q", got)
+	}
+}
+
+func TestNotifierReportsFailedDeliveries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	n := New(Webhooks([]session.WebhookConfig{{URL: server.URL}})...)
+	n.Errors = &warnings
+	n.Send(Event{Type: EventBallComplete, BallID: "myapp-a1b2", Title: "Add form"})
+	n.Close()
+
+	if !strings.Contains(warnings.String(), "ball_complete webhook to "+server.URL+" failed: status 500") {
+		t.Errorf("Expected a delivery warning, got %q", warnings.String())
+	}
+}
+
+func TestNilNotifier(t *testing.T) {
+	n := New(nil, Desktop(nil), Desktop(&session.DesktopNotificationsConfig{}))
+	if n.Enabled() {
+		t.Error("Expected a notifier without channels to be disabled")
+	}
+	// Sending and closing a nil notifier is a no-op
+	n.Send(Event{Type: EventRunStart})
+	n.Close()
+}
+
+func TestChatWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string][]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		bodies[r.URL.Path] = append(bodies[r.URL.Path], body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	n := New(Webhooks([]session.WebhookConfig{
+		{URL: server.URL + "/slack", Format: session.WebhookFormatSlack},
+		{URL: server.URL + "/discord", Format: session.WebhookFormatDiscord},
+	})...)
+	n.Send(Event{Type: EventRunStart, Project: "/work/myapp", Session: "auth"})
+	n.Send(Event{Type: EventBallBlocked, Project: "/work/myapp", Session: "auth", BallID: "myapp-a1b2", Title: "Add <form>", Reason: "needs a key", OutputFile: "/work/myapp/.juggle/sessions/auth/last_output.txt"})
+	n.Send(Event{Type: EventRunEnd, Project: "/work/myapp", Session: "auth", Status: "blocked", Summary: "0/1 balls complete, 1 blocked, 2 iterations"})
+	n.Close()
+
+	slack, discord := bodies["/slack"], bodies["/discord"]
+	if len(slack) != 2 || len(discord) != 2 {
+		t.Fatalf("Expected ball_blocked and run_end only, got %d Slack and %d Discord messages", len(slack), len(discord))
+	}
+	wantBlocked := "*Ball blocked* in session `auth` (myapp)\n*Add &lt;form&gt;* (`myapp-a1b2`)\nReason: needs a key\nLast output: `/work/myapp/.juggle/sessions/auth/last_output.txt`"
+	if slack[0]["text"] != wantBlocked {
+		t.Errorf("Unexpected Slack blocked message:\n%s", slack[0]["text"])
+	}
+	if want := "**Agent run ended: blocked** in session `auth` (myapp)\n0/1 balls complete, 1 blocked, 2 iterations"; discord[1]["content"] != want {
+		t.Errorf("Unexpected Discord run summary:\n%s", discord[1]["content"])
+	}
+	if discord[0]["username"] != "juggle" || !strings.Contains(discord[0]["content"], "**Add <form>** (`myapp-a1b2`)") {
+		t.Errorf("Unexpected Discord blocked message: %+v", discord[0])
+	}
+}
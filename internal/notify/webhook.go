@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// webhook POSTs events as JSON, or as chat messages, to a URL from project
+// config
+type webhook struct {
+	hook   session.WebhookConfig
+	client *http.Client
+}
+
+// Webhooks returns a channel for each configured webhook
+func Webhooks(hooks []session.WebhookConfig) []Channel {
+	client := &http.Client{Timeout: Timeout}
+	channels := make([]Channel, 0, len(hooks))
+	for _, hook := range hooks {
+		channels = append(channels, &webhook{hook: hook, client: client})
+	}
+	return channels
+}
+
+func (w *webhook) String() string {
+	return "webhook to " + w.hook.URL
+}
+
+// Wants reports whether the webhook subscribes to the event's type; a
+// webhook with no events listed gets them all, or ChatEvents for a chat format
+func (w *webhook) Wants(event Event) bool {
+	if len(w.hook.Events) == 0 && w.hook.IsChat() {
+		return subscribed(ChatEvents, event.Type)
+	}
+	return subscribed(w.hook.Events, event.Type)
+}
+
+// Deliver POSTs the event, or a chat message about it
+func (w *webhook) Deliver(event Event) error {
+	var payload any = event
+	if w.hook.IsChat() {
+		payload = chatPayload(w.hook.Format, event)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, w.hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "juggle")
+	for key, value := range w.hook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
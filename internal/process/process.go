@@ -0,0 +1,49 @@
+// Package process holds the operating system specific parts of running juggle
+// in the background: checking that a daemon's process is alive (and not
+// another program that got its PID), starting one detached from the
+// terminal, and asking one to shut down.
+//
+// On Unix a shutdown request is SIGTERM. Windows has no signals to send to
+// another process, so there the request is a file in the temp directory that
+// the process polls for; NotifyContext watches for either.
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stopPollInterval is how often a process on Windows checks for a shutdown
+// request
+const stopPollInterval = 500 * time.Millisecond
+
+// startSlack allows for a start time read back less precisely than the
+// process recorded it
+const startSlack = 2 * time.Second
+
+// RunningSince reports whether a process with the PID exists and started no
+// later than since, the time the process recorded itself as started. A PID
+// is reused once its process exits, so a process started after that is
+// another program. Without since, or where start times can't be read, it is
+// Running.
+func RunningSince(pid int, since time.Time) bool {
+	if !Running(pid) {
+		return false
+	}
+	if since.IsZero() {
+		return true
+	}
+	started, err := StartTime(pid)
+	if err != nil {
+		return true
+	}
+	return !started.After(since.Add(startSlack))
+}
+
+// stopFile is where a shutdown request for the process with the PID is
+// written on Windows
+func stopFile(pid int) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("juggle-%d.stop", pid))
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestRunning(t *testing.T) {
+	if !Running(os.Getpid()) {
+		t.Error("Expected this process to be running")
+	}
+	if Running(0) || Running(-1) {
+		t.Error("Expected no process for a PID of 0 or less")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The test binary exits at once when no test matches
+	cmd := exec.Command(exe, "-test.run", "^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to run a child: %v", err)
+	}
+	if Running(cmd.Process.Pid) {
+		t.Errorf("Expected the exited child %d not to be running", cmd.Process.Pid)
+	}
+}
+
+func TestRunningSince(t *testing.T) {
+	started, err := StartTime(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("start times aren't known on this system")
+	}
+	if err != nil {
+		t.Fatalf("StartTime failed: %v", err)
+	}
+	if started.After(time.Now()) || time.Since(started) > time.Hour {
+		t.Errorf("Expected this process to have started in the last hour, got %v", started)
+	}
+
+	if !RunningSince(os.Getpid(), time.Now()) || !RunningSince(os.Getpid(), time.Time{}) {
+		t.Error("Expected this process to be running since now")
+	}
+	// A PID file written a day ago names a process that has since exited
+	if RunningSince(os.Getpid(), time.Now().Add(-24*time.Hour)) {
+		t.Error("Expected a process started after the PID file was written not to count")
+	}
+}
+
+func TestTerminateCancelsNotifyContext(t *testing.T) {
+	ctx, stop := NotifyContext(context.Background())
+	defer stop()
+
+	if err := Terminate(os.Getpid()); err != nil {
+		t.Fatalf("Terminate failed: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(4 * stopPollInterval):
+		t.Fatal("Expected a shutdown request to cancel the context")
+	}
+}
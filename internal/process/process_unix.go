@@ -0,0 +1,43 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// Running reports whether a process with the PID exists
+func Running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// On Unix FindProcess always succeeds; signal 0 checks the process exists
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// Detach prepares cmd to keep running after juggle exits. A Unix child
+// already does.
+func Detach(cmd *exec.Cmd) {}
+
+// Terminate asks the process with the PID to shut down, with SIGTERM
+func Terminate(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Signal(syscall.SIGTERM)
+}
+
+// NotifyContext returns a copy of parent that is cancelled on SIGINT or
+// SIGTERM, as signal.NotifyContext does
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, syscall.SIGTERM, syscall.SIGINT)
+}
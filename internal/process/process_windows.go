@@ -0,0 +1,81 @@
+//go:build windows
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a process that
+// hasn't exited (STILL_ACTIVE)
+const stillActive = 259
+
+// Running reports whether a process with the PID exists
+func Running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// A process of another user can't be opened but still exists
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(handle)
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// Detach prepares cmd to keep running after juggle exits: without a console,
+// it doesn't end when the terminal that started it is closed
+func Detach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS
+	cmd.SysProcAttr.HideWindow = true
+}
+
+// Terminate asks the process with the PID to shut down, by writing the
+// request file its NotifyContext polls for
+func Terminate(pid int) error {
+	if !Running(pid) {
+		return os.ErrProcessDone
+	}
+	return os.WriteFile(stopFile(pid), nil, 0644)
+}
+
+// NotifyContext returns a copy of parent that is cancelled on Ctrl+C or when
+// Terminate asks this process to shut down
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt)
+	path := stopFile(os.Getpid())
+	// A request left by an earlier process with the same PID isn't ours
+	os.Remove(path)
+	go func() {
+		ticker := time.NewTicker(stopPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := os.Stat(path); err == nil {
+					os.Remove(path)
+					stop()
+					return
+				}
+			}
+		}
+	}()
+	return ctx, stop
+}
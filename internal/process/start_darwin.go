@@ -0,0 +1,16 @@
+package process
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// StartTime returns when the process with the PID started
+func StartTime(pid int) (time.Time, error) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(info.Proc.P_starttime.Unix()), nil
+}
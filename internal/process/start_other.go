@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package process
+
+import (
+	"errors"
+	"time"
+)
+
+// StartTime returns when the process with the PID started. It isn't known
+// on this system.
+func StartTime(pid int) (time.Time, error) {
+	return time.Time{}, errors.ErrUnsupported
+}
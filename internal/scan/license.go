@@ -0,0 +1,37 @@
+package scan
+
+import (
+	"strings"
+)
+
+// licenseHeaderLines is how far into a new file the license header may appear
+const licenseHeaderLines = 10
+
+// MissingLicenseHeaders returns the paths of new files that don't contain header
+// within their first lines. Only files accepted by include are checked.
+func MissingLicenseHeaders(files []*FileDiff, header string, include func(path string) bool) []string {
+	if header == "" {
+		return nil
+	}
+
+	var missing []string
+	for _, f := range files {
+		if !f.New || (include != nil && !include(f.Path)) {
+			continue
+		}
+		found := false
+		for i, line := range f.Added {
+			if i >= licenseHeaderLines {
+				break
+			}
+			if strings.Contains(line.Text, header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, f.Path)
+		}
+	}
+	return missing
+}
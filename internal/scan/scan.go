@@ -0,0 +1,103 @@
+// Package scan inspects agent diffs before they are committed.
+//
+// It parses unified diffs (as produced by git diff or jj diff --git) into the
+// lines each file adds, then runs gitleaks-style secret detection over the
+// added lines and checks new files for a required license header.
+//
+// Lines containing "gitleaks:allow" are never reported as secrets, matching
+// the gitleaks inline suppression convention.
+//
+// It also finds TODO and FIXME comments across a project's files, so latent
+// work can be turned into balls.
+package scan
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AddedLine is a line added by a diff
+type AddedLine struct {
+	Number int    // Line number in the new file
+	Text   string // Line content without the leading '+'
+}
+
+// FileDiff holds the lines a diff adds to one file
+type FileDiff struct {
+	Path  string
+	New   bool // File did not exist before the diff
+	Added []AddedLine
+}
+
+// ParseUnifiedDiff extracts added lines per file from a unified diff.
+// Deleted files are omitted.
+func ParseUnifiedDiff(diff string) []*FileDiff {
+	var files []*FileDiff
+	var current *FileDiff
+	lineNum := 0
+	inHeader := false // Between "diff --git" and the first hunk
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = &FileDiff{}
+			files = append(files, current)
+			inHeader = true
+			// Fallback path from the header, replaced by the +++ line when present
+			if fields := strings.Fields(line); len(fields) >= 4 {
+				current.Path = strings.TrimPrefix(fields[3], "b/")
+			}
+		case current == nil:
+			continue
+		case inHeader && strings.HasPrefix(line, "new file mode"):
+			current.New = true
+		case inHeader && strings.HasPrefix(line, "--- "):
+			if strings.TrimSpace(strings.TrimPrefix(line, "--- ")) == "/dev/null" {
+				current.New = true
+			}
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			target := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
+			if target == "/dev/null" {
+				current.Path = ""
+			} else {
+				current.Path = strings.TrimPrefix(target, "b/")
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			lineNum = parseHunkStart(line)
+		case inHeader:
+			continue
+		case strings.HasPrefix(line, "+"):
+			current.Added = append(current.Added, AddedLine{Number: lineNum, Text: line[1:]})
+			lineNum++
+		case strings.HasPrefix(line, " "):
+			lineNum++
+		}
+	}
+
+	result := make([]*FileDiff, 0, len(files))
+	for _, f := range files {
+		if f.Path != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// parseHunkStart returns the new-file start line from a hunk header.
+// Format: @@ -a,b +c,d @@
+func parseHunkStart(header string) int {
+	for _, field := range strings.Fields(header) {
+		if !strings.HasPrefix(field, "+") {
+			continue
+		}
+		start := strings.TrimPrefix(field, "+")
+		if i := strings.Index(start, ","); i >= 0 {
+			start = start[:i]
+		}
+		if n, err := strconv.Atoi(start); err == nil {
+			return n
+		}
+	}
+	return 1
+}
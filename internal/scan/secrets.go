@@ -0,0 +1,114 @@
+package scan
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// allowMarker suppresses secret findings on a line
+const allowMarker = "gitleaks:allow"
+
+// SecretFinding is a likely secret found in an added line
+type SecretFinding struct {
+	Path     string
+	Line     int
+	Rule     string
+	Redacted string // The matched secret with all but a short prefix masked
+}
+
+// secretRule detects one kind of secret. If the pattern has a capture group,
+// the first group is the secret; otherwise the whole match is.
+type secretRule struct {
+	id         string
+	pattern    *regexp.Regexp
+	minEntropy float64 // Minimum Shannon entropy of the secret (0 = no check)
+}
+
+var secretRules = []secretRule{
+	{id: "private-key", pattern: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{id: "aws-access-key-id", pattern: regexp.MustCompile(`\b((?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16})\b`)},
+	{id: "github-token", pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,})\b`)},
+	{id: "github-fine-grained-pat", pattern: regexp.MustCompile(`\b(github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{id: "gitlab-token", pattern: regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})\b`)},
+	{id: "slack-token", pattern: regexp.MustCompile(`\b(xox[abposr]-[A-Za-z0-9-]{10,})\b`)},
+	{id: "anthropic-api-key", pattern: regexp.MustCompile(`\b(sk-ant-[A-Za-z0-9_-]{32,})`)},
+	{id: "openai-api-key", pattern: regexp.MustCompile(`\b(sk-(?:proj-)?[A-Za-z0-9_-]{40,})`)},
+	{id: "stripe-secret-key", pattern: regexp.MustCompile(`\b([rs]k_live_[A-Za-z0-9]{20,})\b`)},
+	{id: "google-api-key", pattern: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`)},
+	{
+		id:         "generic-secret",
+		pattern:    regexp.MustCompile(`(?i)(?:api[_-]?key|secret|passw(?:or)?d|token|credential)s?["']?\s*[:=]\s*["']([^"'\s]{16,})["']`),
+		minEntropy: 3.5,
+	},
+}
+
+// ScanSecrets runs the secret rules over the lines added by each file diff.
+// At most one finding is reported per line.
+func ScanSecrets(files []*FileDiff) []SecretFinding {
+	var findings []SecretFinding
+	for _, f := range files {
+		for _, line := range f.Added {
+			if strings.Contains(line.Text, allowMarker) {
+				continue
+			}
+			for _, rule := range secretRules {
+				secret, ok := rule.match(line.Text)
+				if !ok {
+					continue
+				}
+				findings = append(findings, SecretFinding{
+					Path:     f.Path,
+					Line:     line.Number,
+					Rule:     rule.id,
+					Redacted: Redact(secret),
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// match returns the secret matched by the rule in text
+func (r secretRule) match(text string) (string, bool) {
+	m := r.pattern.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	secret := m[0]
+	if len(m) > 1 {
+		secret = m[1]
+	}
+	if r.minEntropy > 0 && shannonEntropy(secret) < r.minEntropy {
+		return "", false
+	}
+	return secret, true
+}
+
+// Redact masks a secret, keeping a short prefix so the finding can be located
+func Redact(secret string) string {
+	keep := 4
+	if len(secret) <= keep*2 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:keep] + strings.Repeat("*", len(secret)-keep)
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	total := float64(len([]rune(s)))
+	entropy := 0.0
+	for _, c := range counts {
+		p := float64(c) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,104 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxTodoFileSize skips files too large to be hand-written source, such as
+// generated code and data dumps
+const maxTodoFileSize = 1 << 20
+
+// todoPattern matches a TODO or FIXME right after a comment marker (//, #,
+// /*, <!--, --, ;) or at the start of a block comment line, with an optional
+// "(owner)" and colon after it. The tag must be upper case, so prose that
+// mentions a todo list doesn't count.
+var todoPattern = regexp.MustCompile(`(?:(?://+|#+|/\*+|<!--|--|;+)|^\s*\*+)\s*(TODO|FIXME)\b(?:\([^)]*\))?:?\s*(.*)$`)
+
+// todoCommentEnd matches the end of a block or HTML comment closed on the same line
+var todoCommentEnd = regexp.MustCompile(`\s*(?:\*/|-->)\s*$`)
+
+// TodoComment is a TODO or FIXME comment in a project file
+type TodoComment struct {
+	Path string // Project-relative, with forward slashes
+	Line int
+	Tag  string // "TODO" or "FIXME"
+	Text string // Comment text after the tag; may be empty
+}
+
+// TodoGroup holds the comments of one file or module
+type TodoGroup struct {
+	Key   string // The file path, or the module directory ("." for the project root)
+	Todos []TodoComment
+}
+
+// FindTodos reads the given project-relative files under root and returns
+// their TODO and FIXME comments, in file and line order. Binary files, files
+// over 1 MiB and files that can't be read are skipped.
+func FindTodos(root string, paths []string) []TodoComment {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	var todos []TodoComment
+	for _, rel := range sorted {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Stat(full)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxTodoFileSize {
+			continue
+		}
+		data, err := os.ReadFile(full)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+		todos = append(todos, todosInText(filepath.ToSlash(rel), data)...)
+	}
+	return todos
+}
+
+// todosInText returns the TODO and FIXME comments in one file's content
+func todosInText(path string, data []byte) []TodoComment {
+	var todos []TodoComment
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTodoFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		match := todoPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		todos = append(todos, TodoComment{
+			Path: path,
+			Line: line,
+			Tag:  match[1],
+			Text: strings.TrimSpace(todoCommentEnd.ReplaceAllString(match[2], "")),
+		})
+	}
+	return todos
+}
+
+// GroupTodos groups comments by file, or by module (the file's directory)
+// when byModule is set. Groups are sorted by key; comments keep their order.
+func GroupTodos(todos []TodoComment, byModule bool) []TodoGroup {
+	index := make(map[string]int)
+	var groups []TodoGroup
+	for _, todo := range todos {
+		key := todo.Path
+		if byModule {
+			key = path.Dir(todo.Path)
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, TodoGroup{Key: key})
+		}
+		groups[i].Todos = append(groups[i].Todos, todo)
+	}
+	sort.SliceStable(groups, func(a, b int) bool { return groups[a].Key < groups[b].Key })
+	return groups
+}
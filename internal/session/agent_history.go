@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,22 +17,78 @@ const (
 
 // AgentRunRecord stores information about a past agent run
 type AgentRunRecord struct {
-	ID             string        `json:"id"`              // Unique run ID (timestamp-based)
-	SessionID      string        `json:"session_id"`      // Session the agent ran on
-	StartedAt      time.Time     `json:"started_at"`      // When the run started
-	EndedAt        time.Time     `json:"ended_at"`        // When the run ended
-	Iterations     int           `json:"iterations"`      // Number of iterations completed
-	MaxIterations  int           `json:"max_iterations"`  // Maximum iterations configured
-	Result         string        `json:"result"`          // "complete", "blocked", "timeout", "max_iterations", "rate_limit", "cancelled", "error"
-	BlockedReason  string        `json:"blocked_reason,omitempty"`
-	TimeoutMessage string        `json:"timeout_message,omitempty"`
-	ErrorMessage   string        `json:"error_message,omitempty"`
-	BallsComplete  int           `json:"balls_complete"`  // Number of balls completed
-	BallsBlocked   int           `json:"balls_blocked"`   // Number of balls blocked
-	BallsTotal     int           `json:"balls_total"`     // Total balls in session
-	TotalWaitTime  time.Duration `json:"total_wait_time"` // Time spent waiting for rate limits
-	OutputFile     string        `json:"output_file"`     // Path to last_output.txt
-	ProjectDir     string        `json:"project_dir"`     // Project directory where agent ran
+	ID               string            `json:"id"`             // Unique run ID (timestamp-based)
+	SessionID        string            `json:"session_id"`     // Session the agent ran on
+	StartedAt        time.Time         `json:"started_at"`     // When the run started
+	EndedAt          time.Time         `json:"ended_at"`       // When the run ended
+	Iterations       int               `json:"iterations"`     // Number of iterations completed
+	MaxIterations    int               `json:"max_iterations"` // Maximum iterations configured
+	Result           string            `json:"result"`         // "complete", "blocked", "timeout", "max_iterations", "rate_limit", "budget_exceeded", "cancelled", "error"
+	BlockedReason    string            `json:"blocked_reason,omitempty"`
+	TimeoutMessage   string            `json:"timeout_message,omitempty"`
+	ErrorMessage     string            `json:"error_message,omitempty"`
+	BallsComplete    int               `json:"balls_complete"`              // Number of balls completed
+	BallsBlocked     int               `json:"balls_blocked"`               // Number of balls blocked
+	BallsTotal       int               `json:"balls_total"`                 // Total balls in session
+	TotalWaitTime    time.Duration     `json:"total_wait_time"`             // Time spent waiting for rate limits
+	InputTokens      int               `json:"input_tokens,omitempty"`      // Tokens sent, summed over all iterations
+	OutputTokens     int               `json:"output_tokens,omitempty"`     // Tokens generated, summed over all iterations
+	CostUSD          float64           `json:"cost_usd,omitempty"`          // Cost reported by the provider
+	IterationTimings []IterationTiming `json:"iteration_timings,omitempty"` // How long each finished iteration took
+	OutputFile       string            `json:"output_file"`                 // Path to last_output.txt
+	ProjectDir       string            `json:"project_dir"`                 // Project directory where agent ran
+	StartRevision    string            `json:"start_revision,omitempty"`    // VCS snapshot revision before the first iteration
+	EndRevision      string            `json:"end_revision,omitempty"`      // VCS snapshot revision once the run ended
+	ReportFile       string            `json:"report_file,omitempty"`       // Report written at the end of the run
+}
+
+// IterationTiming records how long one agent iteration took and with which model
+type IterationTiming struct {
+	Model    string        `json:"model"`
+	Duration time.Duration `json:"duration"`
+}
+
+// AverageIterationDuration returns the mean duration of the timings that used
+// model, or of all timings when model is empty. ok is false when none match.
+func AverageIterationDuration(timings []IterationTiming, model string) (avg time.Duration, ok bool) {
+	var total time.Duration
+	count := 0
+	for _, timing := range timings {
+		if model != "" && timing.Model != model {
+			continue
+		}
+		total += timing.Duration
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / time.Duration(count), true
+}
+
+// FormatUsage renders token counts and cost for display, e.g.
+// "12.3k in / 1.5k out · $0.0421". Returns "" when nothing was reported.
+func FormatUsage(inputTokens, outputTokens int, costUSD float64) string {
+	if inputTokens == 0 && outputTokens == 0 && costUSD == 0 {
+		return ""
+	}
+	usage := fmt.Sprintf("%s in / %s out", FormatTokenCount(inputTokens), FormatTokenCount(outputTokens))
+	if costUSD > 0 {
+		usage += fmt.Sprintf(" · $%.4f", costUSD)
+	}
+	return usage
+}
+
+// FormatTokenCount abbreviates large token counts (1234567 -> "1.2M")
+func FormatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
 }
 
 // NewAgentRunRecord creates a new agent run record with a unique ID
@@ -97,6 +155,17 @@ func (r *AgentRunRecord) SetRateLimitExceeded(iterations int, waitTime time.Dura
 	r.EndedAt = time.Now()
 }
 
+// SetBudgetExceeded marks the run as stopped by its token or cost budget
+func (r *AgentRunRecord) SetBudgetExceeded(iterations int, message string, ballsComplete, ballsBlocked, ballsTotal int) {
+	r.Result = "budget_exceeded"
+	r.Iterations = iterations
+	r.ErrorMessage = message
+	r.BallsComplete = ballsComplete
+	r.BallsBlocked = ballsBlocked
+	r.BallsTotal = ballsTotal
+	r.EndedAt = time.Now()
+}
+
 // SetCancelled marks the run as cancelled
 func (r *AgentRunRecord) SetCancelled(iterations int, ballsComplete, ballsBlocked, ballsTotal int) {
 	r.Result = "cancelled"
@@ -241,6 +310,42 @@ func (s *AgentHistoryStore) LoadHistoryBySession(sessionID string) ([]*AgentRunR
 	return filtered, nil
 }
 
+// FindRecord finds a run by its ID, or by session: "<session>" is the
+// session's latest run and "<session>~N" the Nth run before that
+func (s *AgentHistoryStore) FindRecord(ref string) (*AgentRunRecord, error) {
+	records, err := s.LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.ID == ref {
+			return record, nil
+		}
+	}
+
+	sessionID, back := ref, 0
+	if i := strings.LastIndex(ref, "~"); i >= 0 {
+		n, err := strconv.Atoi(ref[i+1:])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid run %q: expected <session>~N", ref)
+		}
+		sessionID, back = ref[:i], n
+	}
+	var runs []*AgentRunRecord
+	for _, record := range records {
+		if record.SessionID == sessionID {
+			runs = append(runs, record)
+		}
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no agent run found for %q", ref)
+	}
+	if back >= len(runs) {
+		return nil, fmt.Errorf("session %s has only %d run(s) in history", sessionID, len(runs))
+	}
+	return runs[back], nil
+}
+
 // LoadRecentHistory loads the most recent N records
 func (s *AgentHistoryStore) LoadRecentHistory(limit int) ([]*AgentRunRecord, error) {
 	records, err := s.LoadHistory()
@@ -130,6 +130,25 @@ func TestAgentRunRecord_Duration(t *testing.T) {
 	}
 }
 
+func TestFormatUsage(t *testing.T) {
+	tests := []struct {
+		input, output int
+		cost          float64
+		want          string
+	}{
+		{0, 0, 0, ""},
+		{950, 120, 0, "950 in / 120 out"},
+		{12345, 1500, 0.04213, "12.3k in / 1.5k out · $0.0421"},
+		{2_500_000, 80_000, 3.5, "2.5M in / 80.0k out · $3.5000"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatUsage(tt.input, tt.output, tt.cost); got != tt.want {
+			t.Errorf("FormatUsage(%d, %d, %v) = %q, want %q", tt.input, tt.output, tt.cost, got, tt.want)
+		}
+	}
+}
+
 func TestAgentHistoryStore_AppendAndLoad(t *testing.T) {
 	// Create temp directory
 	tmpDir, err := os.MkdirTemp("", "juggle-history-test-*")
@@ -223,6 +242,46 @@ func TestAgentHistoryStore_LoadHistoryBySession(t *testing.T) {
 	}
 }
 
+func TestAgentHistoryStore_FindRecord(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewAgentHistoryStore(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+
+	older := NewAgentRunRecord("feature", tmpDir, time.Now().Add(-3*time.Hour))
+	other := NewAgentRunRecord("other", tmpDir, time.Now().Add(-2*time.Hour))
+	latest := NewAgentRunRecord("feature", tmpDir, time.Now().Add(-1*time.Hour))
+	for _, record := range []*AgentRunRecord{older, other, latest} {
+		if err := store.AppendRecord(record); err != nil {
+			t.Fatalf("Failed to append record: %v", err)
+		}
+	}
+
+	for ref, want := range map[string]*AgentRunRecord{
+		older.ID:    older,
+		"feature":   latest,
+		"feature~0": latest,
+		"feature~1": older,
+		"other":     other,
+	} {
+		got, err := store.FindRecord(ref)
+		if err != nil {
+			t.Errorf("FindRecord(%q) failed: %v", ref, err)
+			continue
+		}
+		if got.ID != want.ID {
+			t.Errorf("FindRecord(%q) = run %s, want %s", ref, got.ID, want.ID)
+		}
+	}
+
+	for _, ref := range []string{"feature~2", "feature~x", "missing"} {
+		if _, err := store.FindRecord(ref); err == nil {
+			t.Errorf("Expected FindRecord(%q) to fail", ref)
+		}
+	}
+}
+
 func TestAgentHistoryStore_LoadRecentHistory(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "juggle-history-test-*")
 	if err != nil {
@@ -295,3 +354,21 @@ func TestAgentHistoryStore_HistoryFilePath(t *testing.T) {
 		t.Errorf("Expected path '%s', got '%s'", expectedPath, actualPath)
 	}
 }
+
+func TestAverageIterationDuration(t *testing.T) {
+	timings := []IterationTiming{
+		{Model: "opus", Duration: 10 * time.Minute},
+		{Model: "opus", Duration: 6 * time.Minute},
+		{Model: "sonnet", Duration: 2 * time.Minute},
+	}
+
+	if avg, ok := AverageIterationDuration(timings, "opus"); !ok || avg != 8*time.Minute {
+		t.Errorf("opus average = %v, %v; want 8m, true", avg, ok)
+	}
+	if avg, ok := AverageIterationDuration(timings, ""); !ok || avg != 6*time.Minute {
+		t.Errorf("overall average = %v, %v; want 6m, true", avg, ok)
+	}
+	if _, ok := AverageIterationDuration(timings, "haiku"); ok {
+		t.Error("expected no average for a model without timings")
+	}
+}
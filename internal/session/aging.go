@@ -0,0 +1,103 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// priorityLadder lists priorities from lowest to highest; aging raises a ball
+// one step at a time
+var priorityLadder = []Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// PriorityBump records one automatic priority raise of an aging ball
+type PriorityBump struct {
+	From Priority  `json:"from"`
+	To   Priority  `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+// String describes the bump, e.g. "low → medium on 2025-06-01"
+func (p PriorityBump) String() string {
+	return fmt.Sprintf("%s → %s on %s", p.From, p.To, p.At.Format("2006-01-02"))
+}
+
+// IsAged reports whether aging ever raised the ball's priority
+func (b *Ball) IsAged() bool {
+	return len(b.Aged) > 0
+}
+
+// Untouched returns when the ball was last touched: its last activity, or
+// its last priority bump if that came later. Bumps don't count as activity,
+// so a ball left alone keeps aging.
+func (b *Ball) Untouched() time.Time {
+	since := b.LastActivity
+	if n := len(b.Aged); n > 0 && b.Aged[n-1].At.After(since) {
+		since = b.Aged[n-1].At
+	}
+	return since
+}
+
+// Age raises a pending ball's priority one step once it has sat untouched for
+// after, up to ceiling, and records the bump. Returns true if it was raised.
+func (b *Ball) Age(after time.Duration, ceiling Priority, now time.Time) bool {
+	if after <= 0 || b.State != StatePending || now.Sub(b.Untouched()) < after {
+		return false
+	}
+	next := nextPriority(b.Priority)
+	if next == "" || priorityRank(next) > priorityRank(ceiling) {
+		return false
+	}
+	b.Aged = append(b.Aged, PriorityBump{From: b.Priority, To: next, At: now})
+	b.Priority = next
+	return true
+}
+
+// nextPriority returns the priority one step up, or "" for urgent and unknown priorities
+func nextPriority(p Priority) Priority {
+	rank := priorityRank(p)
+	if rank < 0 || rank == len(priorityLadder)-1 {
+		return ""
+	}
+	return priorityLadder[rank+1]
+}
+
+// priorityRank returns the priority's position on the ladder, or -1 if unknown
+func priorityRank(p Priority) int {
+	for i, candidate := range priorityLadder {
+		if candidate == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// AgeBalls raises the priority of the project's pending balls that have sat
+// untouched for the configured time and saves them. Returns the balls that
+// were raised; a nil config or after_days of 0 ages nothing.
+func (s *Store) AgeBalls(aging *AgingConfig, now time.Time) ([]*Ball, error) {
+	after := aging.After()
+	if after <= 0 {
+		return nil, nil
+	}
+
+	var aged []*Ball
+	err := s.Batch(func(store *Store) error {
+		balls, err := store.LoadBalls()
+		if err != nil {
+			return err
+		}
+		for _, ball := range balls {
+			if ball.Age(after, aging.Ceiling(), now) {
+				if err := store.UpdateBall(ball); err != nil {
+					return err
+				}
+				aged = append(aged, ball)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aged, nil
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBallAge(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	ball := &Ball{ID: "b", State: StatePending, Priority: PriorityLow, LastActivity: now.Add(-8 * 24 * time.Hour)}
+	if !ball.Age(week, PriorityHigh, now) {
+		t.Fatal("Expected a ball untouched for 8 days to age")
+	}
+	if ball.Priority != PriorityMedium || len(ball.Aged) != 1 || ball.Aged[0].From != PriorityLow {
+		t.Errorf("Expected low → medium recorded, got %s with %v", ball.Priority, ball.Aged)
+	}
+
+	// The bump restarts the clock without counting as activity
+	if ball.Age(week, PriorityHigh, now.Add(24*time.Hour)) {
+		t.Error("Expected no second bump a day after the first")
+	}
+	if !ball.Age(week, PriorityHigh, now.Add(week)) || ball.Priority != PriorityHigh {
+		t.Errorf("Expected a second bump to high a week later, got %s", ball.Priority)
+	}
+	if ball.Age(week, PriorityHigh, now.Add(3*week)) {
+		t.Error("Expected aging to stop at the ceiling")
+	}
+
+	active := &Ball{ID: "a", State: StateInProgress, Priority: PriorityLow, LastActivity: now.Add(-30 * 24 * time.Hour)}
+	if active.Age(week, PriorityHigh, now) {
+		t.Error("Expected only pending balls to age")
+	}
+}
+
+func TestStoreAgeBalls(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	old, err := NewBall(store.ProjectDir(), "Old small task", PriorityLow)
+	if err != nil {
+		t.Fatalf("failed to create ball: %v", err)
+	}
+	old.LastActivity = time.Now().Add(-20 * 24 * time.Hour)
+	fresh, err := NewBall(store.ProjectDir(), "New task", PriorityLow)
+	if err != nil {
+		t.Fatalf("failed to create ball: %v", err)
+	}
+	for _, ball := range []*Ball{old, fresh} {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("failed to save ball: %v", err)
+		}
+	}
+
+	if aged, err := store.AgeBalls(nil, time.Now()); err != nil || len(aged) != 0 {
+		t.Fatalf("Expected no aging without config, got %v (err %v)", aged, err)
+	}
+	aged, err := store.AgeBalls(&AgingConfig{AfterDays: 14}, time.Now())
+	if err != nil {
+		t.Fatalf("AgeBalls failed: %v", err)
+	}
+	if len(aged) != 1 || aged[0].ID != old.ID {
+		t.Fatalf("Expected only the old ball aged, got %v", aged)
+	}
+
+	saved, err := store.GetBallByID(old.ID)
+	if err != nil {
+		t.Fatalf("failed to load ball: %v", err)
+	}
+	if saved.Priority != PriorityMedium || !saved.IsAged() {
+		t.Errorf("Expected the aged ball saved at medium with its bump, got %s (%v)", saved.Priority, saved.Aged)
+	}
+}
@@ -1,7 +1,7 @@
 package session
 
 import (
-	"sort"
+	"slices"
 	"strings"
 	"time"
 )
@@ -35,6 +35,9 @@ type ArchiveQuery struct {
 	// Filter by tags (OR logic)
 	Tags []string
 
+	// Filter by session (ball must carry the session tag)
+	Session string
+
 	// Filter by priority
 	Priority Priority
 
@@ -42,8 +45,9 @@ type ArchiveQuery struct {
 	CompletedAfter  *time.Time
 	CompletedBefore *time.Time
 
-	// Limit number of results (0 = no limit)
-	Limit int
+	// Limit number of results (0 = no limit), after skipping Offset of them
+	Limit  int
+	Offset int
 
 	// Sort order
 	SortBy ArchiveSortBy
@@ -58,114 +62,142 @@ const (
 	SortByPriority      ArchiveSortBy = "priority"       // Highest priority first
 )
 
-// QueryArchive searches archived balls based on the given query
+// QueryArchive searches archived balls based on the given query. Each
+// project's archive is filtered as it is read, and with a Limit only the
+// first Offset+Limit matching balls in the query's order are kept from it.
 func QueryArchive(projectPaths []string, query ArchiveQuery) ([]*Ball, error) {
-	balls, err := LoadArchivedBalls(projectPaths)
-	if err != nil {
-		return nil, err
+	scan := BallQuery{Archived: true, Match: query.matches, Less: archiveLess(query.SortBy)}
+	if query.Session != "" {
+		scan.Tags = []string{query.Session}
+	}
+	if query.Limit > 0 {
+		scan.Limit = query.Offset + query.Limit
 	}
 
-	// Apply filters
 	filtered := make([]*Ball, 0)
+	for _, projectPath := range projectPaths {
+		store, err := NewStore(projectPath)
+		if err != nil {
+			continue // Skip projects we can't access
+		}
+		page, err := store.LoadBallsFiltered(scan)
+		if err != nil {
+			continue // Skip if archive can't be read
+		}
+		filtered = append(filtered, page.Balls...)
+	}
 
-	for _, ball := range balls {
-		// Text search filter (searches both title and context)
-		if query.Query != "" {
-			queryLower := strings.ToLower(query.Query)
-			titleMatch := strings.Contains(strings.ToLower(ball.Title), queryLower)
-			contextMatch := strings.Contains(strings.ToLower(ball.Context), queryLower)
-			if !titleMatch && !contextMatch {
-				continue
-			}
+	// Merge the projects' pages, then apply offset and limit
+	slices.SortStableFunc(filtered, func(a, b *Ball) int {
+		switch {
+		case scan.Less(a, b):
+			return -1
+		case scan.Less(b, a):
+			return 1
 		}
+		return 0
+	})
+	if query.Offset > 0 {
+		filtered = filtered[min(query.Offset, len(filtered)):]
+	}
+	if query.Limit > 0 && len(filtered) > query.Limit {
+		filtered = filtered[:query.Limit]
+	}
 
-		// Tag filter (OR logic)
-		if len(query.Tags) > 0 {
-			hasTag := false
-			for _, filterTag := range query.Tags {
-				for _, ballTag := range ball.Tags {
-					if ballTag == filterTag {
-						hasTag = true
-						break
-					}
-				}
-				if hasTag {
+	return filtered, nil
+}
+
+// matches reports whether an archived ball passes the query's filters
+func (query ArchiveQuery) matches(ball *Ball) bool {
+	// Text search filter (searches both title and context)
+	if query.Query != "" {
+		queryLower := strings.ToLower(query.Query)
+		titleMatch := strings.Contains(strings.ToLower(ball.Title), queryLower)
+		contextMatch := strings.Contains(strings.ToLower(ball.Context), queryLower)
+		if !titleMatch && !contextMatch {
+			return false
+		}
+	}
+
+	// Tag filter (OR logic)
+	if len(query.Tags) > 0 {
+		hasTag := false
+		for _, filterTag := range query.Tags {
+			for _, ballTag := range ball.Tags {
+				if ballTag == filterTag {
+					hasTag = true
 					break
 				}
 			}
-			if !hasTag {
-				continue
+			if hasTag {
+				break
 			}
 		}
-
-		// Priority filter
-		if query.Priority != "" && ball.Priority != query.Priority {
-			continue
+		if !hasTag {
+			return false
 		}
+	}
 
-		// Completed after filter
-		if query.CompletedAfter != nil && ball.CompletedAt != nil {
-			if ball.CompletedAt.Before(*query.CompletedAfter) {
-				continue
+	// Session filter
+	if query.Session != "" {
+		inSession := false
+		for _, tag := range ball.Tags {
+			if tag == query.Session {
+				inSession = true
+				break
 			}
 		}
+		if !inSession {
+			return false
+		}
+	}
 
-		// Completed before filter
-		if query.CompletedBefore != nil && ball.CompletedAt != nil {
-			if ball.CompletedAt.After(*query.CompletedBefore) {
-				continue
-			}
+	// Priority filter
+	if query.Priority != "" && ball.Priority != query.Priority {
+		return false
+	}
+
+	// Completed after filter
+	if query.CompletedAfter != nil && ball.CompletedAt != nil {
+		if ball.CompletedAt.Before(*query.CompletedAfter) {
+			return false
 		}
+	}
 
-		filtered = append(filtered, ball)
+	// Completed before filter
+	if query.CompletedBefore != nil && ball.CompletedAt != nil {
+		if ball.CompletedAt.After(*query.CompletedBefore) {
+			return false
+		}
 	}
 
-	// Apply sorting
-	switch query.SortBy {
-	case SortByCompletedDesc:
-		sort.Slice(filtered, func(i, j int) bool {
-			// Nil-safe comparison
-			if filtered[i].CompletedAt == nil {
-				return false
-			}
-			if filtered[j].CompletedAt == nil {
-				return true
-			}
-			return filtered[i].CompletedAt.After(*filtered[j].CompletedAt)
-		})
+	return true
+}
+
+// archiveLess returns the comparison for sorting archived balls in the given
+// order. Balls never completed sort as the oldest.
+func archiveLess(sortBy ArchiveSortBy) func(a, b *Ball) bool {
+	switch sortBy {
 	case SortByCompletedAsc:
-		sort.Slice(filtered, func(i, j int) bool {
-			if filtered[i].CompletedAt == nil {
-				return true
+		return func(a, b *Ball) bool {
+			if a.CompletedAt == nil || b.CompletedAt == nil {
+				return a.CompletedAt == nil && b.CompletedAt != nil
 			}
-			if filtered[j].CompletedAt == nil {
-				return false
-			}
-			return filtered[i].CompletedAt.Before(*filtered[j].CompletedAt)
-		})
+			return a.CompletedAt.Before(*b.CompletedAt)
+		}
 	case SortByPriority:
-		sort.Slice(filtered, func(i, j int) bool {
-			return filtered[i].PriorityWeight() > filtered[j].PriorityWeight()
-		})
+		return func(a, b *Ball) bool {
+			return a.PriorityWeight() > b.PriorityWeight()
+		}
 	default:
 		// Default: most recently completed first
-		sort.Slice(filtered, func(i, j int) bool {
-			if filtered[i].CompletedAt == nil {
-				return false
-			}
-			if filtered[j].CompletedAt == nil {
-				return true
+		return func(a, b *Ball) bool {
+			if a.CompletedAt == nil || b.CompletedAt == nil {
+				return a.CompletedAt != nil && b.CompletedAt == nil
 			}
-			return filtered[i].CompletedAt.After(*filtered[j].CompletedAt)
-		})
-	}
-
-	// Apply limit
-	if query.Limit > 0 && len(filtered) > query.Limit {
-		filtered = filtered[:query.Limit]
+			return a.CompletedAt.After(*b.CompletedAt)
+		}
 	}
-
-	return filtered, nil
 }
 
 // GetArchiveStats returns statistics about archived balls
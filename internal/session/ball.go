@@ -44,6 +44,55 @@ const (
 	ModelSizeLarge ModelSize = "large"
 )
 
+// BallKind classifies the work a ball asks for. The kind switches the agent's
+// instructions, whether build/test validation runs, and the default model size.
+type BallKind string
+
+const (
+	// BallKindBlank is the implicit kind when omitted from JSON and behaves like code.
+	BallKindBlank BallKind = ""
+
+	// BallKindCode is a change to the codebase, validated by the project's tests.
+	BallKindCode BallKind = "code"
+
+	// BallKindDocs is a documentation change. Validation is skipped.
+	BallKindDocs BallKind = "docs"
+
+	// BallKindResearch is an investigation whose findings go in the ball's output.
+	// It ends in the researched state rather than as a code change.
+	BallKindResearch BallKind = "research"
+
+	// BallKindOps is operational work such as following a runbook. Changes to
+	// the repository are incidental, so validation is skipped.
+	BallKindOps BallKind = "ops"
+)
+
+// ValidateBallKind checks if a ball kind string is valid
+func ValidateBallKind(s string) bool {
+	switch BallKind(s) {
+	case BallKindBlank, BallKindCode, BallKindDocs, BallKindResearch, BallKindOps:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsCode reports whether the kind is a code change (the default)
+func (k BallKind) IsCode() bool {
+	return k == BallKindBlank || k == BallKindCode
+}
+
+// DefaultModelSize is the model size used for balls of this kind that don't
+// set one. Code and research have no preference, so they get the largest model.
+func (k BallKind) DefaultModelSize() ModelSize {
+	switch k {
+	case BallKindDocs, BallKindOps:
+		return ModelSizeMedium
+	default:
+		return ModelSizeBlank
+	}
+}
+
 // BallState represents the lifecycle state of a ball
 type BallState string
 
@@ -55,7 +104,6 @@ const (
 	StateResearched BallState = "researched" // Completed with no code changes, output contains results
 )
 
-
 // Ball represents a task being tracked in the juggle system.
 //
 // A Ball is the fundamental unit of work in juggle. It contains:
@@ -63,7 +111,7 @@ const (
 //   - Description: title, context, and acceptance criteria
 //   - Lifecycle: state, timestamps, and completion info
 //   - Organization: priority, tags, and dependencies
-//   - Agent hints: model size preference and overrides
+//   - Agent hints: kind of work, model size preference and overrides
 //
 // Balls progress through states: pending → in_progress → complete/researched (or blocked).
 // The "researched" state is for investigation tasks that produce findings (stored in Output)
@@ -73,27 +121,42 @@ const (
 //
 //	{"id":"proj-a1b2c3d4","title":"Add feature","priority":"medium","state":"pending",...}
 type Ball struct {
-	ID                 string      `json:"id"`
-	WorkingDir         string      `json:"-"` // Computed from file location, not stored
-	Context            string      `json:"context,omitempty"` // Detailed description/background for the ball
-	Title              string      `json:"title"`             // Short title (50 char soft limit)
-	AcceptanceCriteria []string    `json:"acceptance_criteria,omitempty"`
-	Priority           Priority    `json:"priority"`
-	State              BallState   `json:"state"`
-	BlockedReason      string      `json:"blocked_reason,omitempty"`
-	Output             string      `json:"output,omitempty"` // Research results or investigation output
-	DependsOn          []string    `json:"depends_on,omitempty"` // Ball IDs this ball depends on
-	StartedAt          time.Time   `json:"started_at"`
-	LastActivity       time.Time   `json:"last_activity"`
-	CompletedAt        *time.Time  `json:"completed_at,omitempty"`
-	UpdateCount        int         `json:"update_count"`
-	Tags               []string    `json:"tags,omitempty"`
-	CompletionNote     string      `json:"completion_note,omitempty"`
-	ModelSize          ModelSize   `json:"model_size,omitempty"`
-	AgentProvider      string      `json:"agent_provider,omitempty"`  // Override: which agent provider to use (e.g., "claude", "opencode")
-	ModelOverride      string      `json:"model_override,omitempty"` // Override: specific model to use (e.g., "opus", "sonnet", "haiku")
-	StartingRevision   string      `json:"starting_revision,omitempty"` // VCS revision/change ID when ball was started
-	RevisionID         string      `json:"revision_id,omitempty"`       // VCS revision/change ID when ball was blocked or completed
+	ID                 string            `json:"id"`
+	WorkingDir         string            `json:"-"`                 // Computed from file location, not stored
+	Context            string            `json:"context,omitempty"` // Detailed description/background for the ball
+	Title              string            `json:"title"`             // Short title (50 char soft limit)
+	AcceptanceCriteria []string          `json:"acceptance_criteria,omitempty"`
+	Priority           Priority          `json:"priority"`
+	State              BallState         `json:"state"`
+	BlockedReason      string            `json:"blocked_reason,omitempty"`
+	Output             string            `json:"output,omitempty"`     // Research results or investigation output
+	DependsOn          []string          `json:"depends_on,omitempty"` // Ball IDs this ball depends on
+	StartedAt          time.Time         `json:"started_at"`
+	LastActivity       time.Time         `json:"last_activity"`
+	CompletedAt        *time.Time        `json:"completed_at,omitempty"`
+	UpdateCount        int               `json:"update_count"`
+	AttemptCount       int               `json:"attempt_count,omitempty"`   // Agent iterations that worked on this ball, see max_ball_attempts
+	FailedAttempts     int               `json:"failed_attempts,omitempty"` // Agent iterations at the current model that left the ball unfinished, see escalate_after_failures
+	EscalatedModel     string            `json:"escalated_model,omitempty"` // Model agents moved the ball up to after repeated failures
+	Aged               []PriorityBump    `json:"aged,omitempty"`            // Automatic priority bumps while pending and untouched, see aging
+	Tags               []string          `json:"tags,omitempty"`
+	CompletionNote     string            `json:"completion_note,omitempty"`
+	ModelSize          ModelSize         `json:"model_size,omitempty"`
+	Kind               BallKind          `json:"kind,omitempty"`              // Kind of work: code (default), docs, research, ops
+	AgentProvider      string            `json:"agent_provider,omitempty"`    // Override: which agent provider to use (e.g., "claude", "opencode", "goose", "amp")
+	ModelOverride      string            `json:"model_override,omitempty"`    // Override: specific model to use (e.g., "opus", "sonnet", "haiku")
+	TimeoutMinutes     int               `json:"timeout_minutes,omitempty"`   // Override: per-iteration timeout for iterations on this ball (0 = session/CLI timeout)
+	StartingRevision   string            `json:"starting_revision,omitempty"` // VCS revision/change ID when ball was started
+	RevisionID         string            `json:"revision_id,omitempty"`       // VCS revision/change ID when ball was blocked or completed
+	Env                map[string]string `json:"env,omitempty"`               // Environment variables for the agent while working on this ball
+	Setup              []string          `json:"setup,omitempty"`             // Shell commands run before the agent works on this ball
+	Teardown           []string          `json:"teardown,omitempty"`          // Shell commands run after the agent is done with this ball
+	Claimed            *BallClaim        `json:"claim,omitempty"`             // Person working on the ball by hand; agents skip it while active
+	Plan               []string          `json:"plan,omitempty"`              // Steps from a --plan-first planning iteration, followed by later iterations
+	Epic               bool              `json:"epic,omitempty"`              // Split into child balls; waits on them before its own ACs are checked
+	Parent             string            `json:"parent,omitempty"`            // ID of the epic this ball was split from
+	Estimate           *BallEstimate     `json:"estimate,omitempty"`          // Size, iterations and risks from juggle agent estimate
+	Hotspot            *BallHotspot      `json:"hotspot,omitempty"`           // Churn and ownership of affected paths from juggle agent hotspots
 }
 
 // NewBall creates a new ball with the given parameters in pending state
@@ -131,11 +194,50 @@ func generateID(workingDir string) (string, error) {
 	}
 
 	base := filepath.Base(resolvedDir)
-	id := uuid.New().String()
-	shortID := id[:8] // First 8 characters of UUID (e.g., "a1b2c3d4")
+
+	// Skip short IDs already used by active or archived balls, so a prefix
+	// never names two balls in the same project
+	shortID, err := pickShortID(existingShortIDs(resolvedDir), func() string {
+		return uuid.New().String()[:8] // First 8 characters of UUID (e.g., "a1b2c3d4")
+	})
+	if err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("%s-%s", base, shortID), nil
 }
 
+// maxShortIDAttempts bounds the retries when a generated short ID is taken
+const maxShortIDAttempts = 16
+
+// pickShortID returns the first candidate from next that isn't in taken
+func pickShortID(taken map[string]bool, next func() string) (string, error) {
+	for i := 0; i < maxShortIDAttempts; i++ {
+		candidate := next()
+		if !taken[lowerString(candidate)] {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a unique ball ID after %d attempts", maxShortIDAttempts)
+}
+
+// existingShortIDs returns the lowercased short IDs of the project's active
+// and archived balls. Projects without a juggle directory have none.
+func existingShortIDs(projectDir string) map[string]bool {
+	taken := make(map[string]bool)
+	if _, err := os.Stat(filepath.Join(projectDir, projectStorePath)); err != nil {
+		return taken
+	}
+	store, err := NewStore(projectDir)
+	if err != nil {
+		return taken
+	}
+	ids, _ := store.BallIDs()
+	for _, id := range ids {
+		taken[lowerString((&Ball{ID: id}).ShortID())] = true
+	}
+	return taken
+}
+
 // GetCwd returns the current working directory
 func GetCwd() (string, error) {
 	return os.Getwd()
@@ -159,6 +261,19 @@ func (b *Ball) IncrementUpdateCount() {
 	b.UpdateActivity()
 }
 
+// IncrementAttemptCount records one more agent iteration spent on the ball
+func (b *Ball) IncrementAttemptCount() {
+	b.AttemptCount++
+	b.UpdateActivity()
+}
+
+// Escalate moves the ball's agent iterations up to model, with a fresh count of failures
+func (b *Ball) Escalate(model string) {
+	b.EscalatedModel = model
+	b.FailedAttempts = 0
+	b.UpdateActivity()
+}
+
 // ValidStateTransition checks if a state transition is valid.
 // All state transitions are allowed - balls can move freely between any states.
 func ValidStateTransition(from, to BallState) bool {
@@ -265,6 +380,12 @@ func (b *Ball) RemoveAcceptanceCriterion(index int) error {
 	return nil
 }
 
+// SetPlan replaces the ball's plan with the given steps
+func (b *Ball) SetPlan(steps []string) {
+	b.Plan = steps
+	b.UpdateActivity()
+}
+
 // AddTag adds a tag to the ball
 func (b *Ball) AddTag(tag string) {
 	for _, t := range b.Tags {
@@ -307,7 +428,6 @@ func (b *Ball) FolderName() string {
 	return filepath.Base(b.WorkingDir)
 }
 
-
 // ShortID extracts the unique portion from a ball ID
 // e.g., "myapp-5" -> "5" (legacy numeric), "myapp-a1b2c3d4" -> "a1b2c3d4" (UUID-based)
 func (b *Ball) ShortID() string {
@@ -325,6 +445,40 @@ func (b *Ball) ShortID() string {
 	return b.ID
 }
 
+// ProjectName returns the project part of the ball ID (everything before the
+// short ID), e.g. "myapp-a1b2c3d4" -> "myapp". IDs without a hyphen fall back
+// to the working directory's name.
+func (b *Ball) ProjectName() string {
+	if short := b.ShortID(); short != b.ID {
+		return b.ID[:len(b.ID)-len(short)-1]
+	}
+	return b.FolderName()
+}
+
+// QualifiedID returns the project-qualified short ID, e.g. "myapp:a1b2c3d4",
+// which stays unambiguous across projects
+func (b *Ball) QualifiedID() string {
+	return b.ProjectName() + ":" + b.ShortID()
+}
+
+// SplitQualifiedID splits a project-qualified ID like "api:12" into its
+// project and ID parts. IDs without a colon have an empty project.
+func SplitQualifiedID(id string) (project, rest string) {
+	for i := 0; i < len(id); i++ {
+		if id[i] == ':' {
+			return id[:i], id[i+1:]
+		}
+	}
+	return "", id
+}
+
+// inProject reports whether the ball belongs to the named project, matching
+// the ID's project part or the working directory's name
+func (b *Ball) inProject(project string) bool {
+	project = lowerString(project)
+	return lowerString(b.ProjectName()) == project || lowerString(b.FolderName()) == project
+}
+
 // ComputeMinimalUniqueIDs computes the shortest unique ID prefix for each ball in the slice.
 // Returns a map from full ball ID to the minimal display ID needed to uniquely identify it.
 // For example, if balls have short IDs "01234abc" and "56789def", the map would contain
@@ -392,9 +546,19 @@ func ComputeMinimalUniqueIDs(balls []*Ball) map[string]string {
 
 // ResolveBallByPrefix finds balls that match the given prefix.
 // It tries to match against the short ID (part after last hyphen) first,
-// then falls back to full ID prefix matching.
+// then falls back to full ID prefix matching. A project-qualified prefix
+// like "api:12" only matches balls in the "api" project.
 // Returns all matching balls - callers should handle ambiguity.
 func ResolveBallByPrefix(balls []*Ball, prefix string) []*Ball {
+	if project, rest := SplitQualifiedID(prefix); project != "" {
+		var inProject []*Ball
+		for _, ball := range balls {
+			if ball.inProject(project) {
+				inProject = append(inProject, ball)
+			}
+		}
+		balls, prefix = inProject, rest
+	}
 	if prefix == "" {
 		return nil
 	}
@@ -440,6 +604,29 @@ func ResolveBallByPrefix(balls []*Ball, prefix string) []*Ball {
 	return matches
 }
 
+// ResolveBallByPrefixWithArchive resolves a prefix against active balls and,
+// when includeArchive is set, archived balls too. An exact short or full ID
+// match among active balls wins; otherwise matches from both are returned so
+// callers can report the ambiguity.
+func ResolveBallByPrefixWithArchive(active, archived []*Ball, prefix string, includeArchive bool) []*Ball {
+	matches := ResolveBallByPrefix(active, prefix)
+	if !includeArchive {
+		return matches
+	}
+	if len(matches) == 1 && isExactIDMatch(matches[0], prefix) {
+		return matches
+	}
+	return append(matches, ResolveBallByPrefix(archived, prefix)...)
+}
+
+// isExactIDMatch reports whether id names the ball by its full, short or
+// qualified ID rather than a prefix of one
+func isExactIDMatch(ball *Ball, id string) bool {
+	_, rest := SplitQualifiedID(id)
+	rest = lowerString(rest)
+	return rest == lowerString(ball.ShortID()) || rest == lowerString(ball.ID)
+}
+
 // ExtractTitleFirstSentence extracts the first sentence from a title.
 // If the title contains a period followed by a space or end of string,
 // only the text before the first such period is returned.
@@ -486,7 +673,6 @@ func ValidatePriority(p string) bool {
 	}
 }
 
-
 // ValidateBallState checks if a ball state string is valid
 func ValidateBallState(s string) bool {
 	switch BallState(s) {
@@ -529,13 +715,40 @@ func (b *Ball) SetModelSize(size ModelSize) {
 	b.UpdateActivity()
 }
 
+// SetKind sets the kind of work the ball asks for
+func (b *Ball) SetKind(kind BallKind) {
+	b.Kind = kind
+	b.UpdateActivity()
+}
+
+// PreferredModelSize returns the ball's model size, falling back to its
+// estimate, the session default and then to the default for the ball's kind
+func (b *Ball) PreferredModelSize(sessionDefault ModelSize) ModelSize {
+	if b.ModelSize != ModelSizeBlank {
+		return b.ModelSize
+	}
+	if b.Estimate != nil && b.Estimate.ModelSize != ModelSizeBlank {
+		return b.Estimate.ModelSize
+	}
+	if sessionDefault != ModelSizeBlank {
+		return sessionDefault
+	}
+	return b.Kind.DefaultModelSize()
+}
+
 // ValidateAgentProvider checks if an agent provider string is valid.
-// Valid providers are: "" (blank/unset), "claude", "opencode"
-func ValidateAgentProvider(s string) bool {
+// Valid providers are: "" (blank/unset), "claude", "opencode", "goose", "amp",
+// or one of the given custom provider names.
+func ValidateAgentProvider(s string, customProviders ...string) bool {
 	switch s {
-	case "", "claude", "opencode":
+	case "", "claude", "opencode", "goose", "amp":
 		return true
 	default:
+		for _, name := range customProviders {
+			if s == name {
+				return true
+			}
+		}
 		return false
 	}
 }
@@ -565,6 +778,36 @@ func (b *Ball) SetModelOverride(model string) {
 	b.UpdateActivity()
 }
 
+// Timeout returns the ball's per-iteration timeout override, or 0 if unset
+func (b *Ball) Timeout() time.Duration {
+	return time.Duration(b.TimeoutMinutes) * time.Minute
+}
+
+// SetTimeout sets the per-iteration timeout override, rounded to whole
+// minutes. Use 0 to clear the override.
+func (b *Ball) SetTimeout(timeout time.Duration) {
+	b.TimeoutMinutes = int(timeout.Round(time.Minute) / time.Minute)
+	b.UpdateActivity()
+}
+
+// SetEnvVar sets an environment variable for the agent, or removes it when value is empty
+func (b *Ball) SetEnvVar(key, value string) {
+	b.Env = setEnvVar(b.Env, key, value)
+	b.UpdateActivity()
+}
+
+// SetSetupCommands replaces the commands run before the agent works on this ball
+func (b *Ball) SetSetupCommands(commands []string) {
+	b.Setup = nonEmptyCommands(commands)
+	b.UpdateActivity()
+}
+
+// SetTeardownCommands replaces the commands run after the agent is done with this ball
+func (b *Ball) SetTeardownCommands(commands []string) {
+	b.Teardown = nonEmptyCommands(commands)
+	b.UpdateActivity()
+}
+
 // HasAgentOverrides returns true if the ball has any agent-related overrides
 func (b *Ball) HasAgentOverrides() bool {
 	return b.AgentProvider != "" || b.ModelOverride != ""
@@ -673,6 +916,33 @@ func DetectCircularDependencies(balls []*Ball) error {
 	return nil
 }
 
+// BallStates maps each ball's full and short ID to its state, for dependency checks
+func BallStates(balls []*Ball) map[string]BallState {
+	states := make(map[string]BallState, len(balls)*2)
+	for _, ball := range balls {
+		states[ball.ID] = ball.State
+		states[ball.ShortID()] = ball.State
+	}
+	return states
+}
+
+// UnmetDependencies returns the ball's dependencies that are not yet complete
+// or researched. Dependencies missing from states (archived, or in another
+// project) count as met.
+func UnmetDependencies(ball *Ball, states map[string]BallState) []string {
+	var unmet []string
+	for _, depID := range ball.DependsOn {
+		state, exists := states[depID]
+		if !exists {
+			continue
+		}
+		if state != StateComplete && state != StateResearched {
+			unmet = append(unmet, depID)
+		}
+	}
+	return unmet
+}
+
 // formatCyclePath formats a cycle path for display
 func formatCyclePath(path []string) string {
 	if len(path) == 0 {
@@ -0,0 +1,196 @@
+package session
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// BallQuery selects and pages balls while they are read, so a store with
+// thousands of balls never has to hold them all in memory. Conditions must
+// all hold.
+type BallQuery struct {
+	Archived bool                  // Read the archive rather than the active balls
+	States   []BallState           // Only balls in one of these states; none for any
+	Tags     []string              // Only balls with one of these tags; none for any
+	Match    func(*Ball) bool      // Only balls it accepts, e.g. a BallFilter's Match
+	Less     func(a, b *Ball) bool // Page in this order rather than the order balls were added
+	Offset   int                   // Matching balls to skip before the page
+	Limit    int                   // Most balls in the page; 0 for no limit
+}
+
+// BallPage is a page of balls from LoadBallsFiltered
+type BallPage struct {
+	Balls []*Ball
+	More  bool // More balls match after this page
+}
+
+// FilteredBallStore is a BallStore that applies a BallQuery while reading,
+// as JSONLBallStore does by streaming its file
+type FilteredBallStore interface {
+	BallStore
+	// LoadBallsFiltered returns the page of balls the query selects
+	LoadBallsFiltered(query BallQuery) (*BallPage, error)
+}
+
+// LoadBallsFiltered returns the page of active (or archived) balls the query
+// selects. Backends that can't filter while reading load every ball first.
+func (s *Store) LoadBallsFiltered(query BallQuery) (*BallPage, error) {
+	var page *BallPage
+	if filtered, ok := s.balls.(FilteredBallStore); ok {
+		var err error
+		if page, err = filtered.LoadBallsFiltered(query); err != nil {
+			return nil, err
+		}
+	} else {
+		load := s.balls.LoadBalls
+		if query.Archived {
+			load = s.balls.LoadArchivedBalls
+		}
+		balls, err := load()
+		if err != nil {
+			return nil, err
+		}
+		matching := make([]*Ball, 0, len(balls))
+		for _, ball := range balls {
+			if query.selects(ball.State, ball.Tags) && query.matches(ball) {
+				matching = append(matching, ball)
+			}
+		}
+		page = query.Page(matching)
+	}
+	s.withWorkingDir(page.Balls...)
+	return page, nil
+}
+
+// selects reports whether a ball with the state and tags passes the query's
+// state and tag conditions, which can be tested without decoding the rest
+func (q BallQuery) selects(state BallState, tags []string) bool {
+	if len(q.States) > 0 && !slices.Contains(q.States, state) {
+		return false
+	}
+	if len(q.Tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if slices.Contains(q.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the query's Match accepts the ball
+func (q BallQuery) matches(ball *Ball) bool {
+	return q.Match == nil || q.Match(ball)
+}
+
+// window returns how many of the first matching balls in Less order a query
+// needs to keep, or 0 when it needs them all
+func (q BallQuery) window() int {
+	if q.Limit <= 0 {
+		return 0
+	}
+	return q.Offset + q.Limit + 1
+}
+
+// Page cuts the query's page from balls that match it, given in the order
+// they were added
+func (q BallQuery) Page(balls []*Ball) *BallPage {
+	if q.Less != nil {
+		top := q.top()
+		for _, ball := range balls {
+			top.add(ball)
+		}
+		balls = top.sorted()
+	}
+	return q.cut(balls)
+}
+
+// cut takes the page from matching balls already in the query's order
+func (q BallQuery) cut(balls []*Ball) *BallPage {
+	if q.Offset >= len(balls) {
+		return &BallPage{Balls: []*Ball{}}
+	}
+	balls = balls[max(q.Offset, 0):]
+	page := &BallPage{Balls: balls}
+	if q.Limit > 0 && len(balls) > q.Limit {
+		page.Balls = balls[:q.Limit]
+		page.More = true
+	}
+	return page
+}
+
+// top returns a topBalls for the query's order and window
+func (q BallQuery) top() *topBalls {
+	return &topBalls{less: q.Less, keep: q.window()}
+}
+
+// topBalls collects the balls an ordered query needs as they are read: all of
+// them without a Limit, otherwise only the first window in Less order, held
+// in a heap whose root is the last of those
+type topBalls struct {
+	less  func(a, b *Ball) bool
+	keep  int
+	balls []rankedBall
+	seen  int
+}
+
+// rankedBall is a ball with its position in the file, which breaks ties
+type rankedBall struct {
+	ball *Ball
+	seq  int
+}
+
+// add offers the next ball read, keeping it if it is among the first window
+func (t *topBalls) add(ball *Ball) {
+	ranked := rankedBall{ball: ball, seq: t.seen}
+	t.seen++
+	switch {
+	case t.keep == 0:
+		t.balls = append(t.balls, ranked)
+	case len(t.balls) < t.keep:
+		heap.Push(t, ranked)
+	case t.before(ranked, t.balls[0]):
+		t.balls[0] = ranked
+		heap.Fix(t, 0)
+	}
+}
+
+// sorted returns the kept balls in Less order
+func (t *topBalls) sorted() []*Ball {
+	slices.SortFunc(t.balls, func(a, b rankedBall) int {
+		if t.before(a, b) {
+			return -1
+		}
+		return 1 // seq makes every pair ordered
+	})
+	balls := make([]*Ball, len(t.balls))
+	for i, ranked := range t.balls {
+		balls[i] = ranked.ball
+	}
+	return balls
+}
+
+// before reports whether a sorts before b, in file order when Less ties
+func (t *topBalls) before(a, b rankedBall) bool {
+	if t.less(a.ball, b.ball) {
+		return true
+	}
+	if t.less(b.ball, a.ball) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+// heap.Interface, with the ball sorting last at the root so it is the one dropped
+
+func (t *topBalls) Len() int           { return len(t.balls) }
+func (t *topBalls) Less(i, j int) bool { return t.before(t.balls[j], t.balls[i]) }
+func (t *topBalls) Swap(i, j int)      { t.balls[i], t.balls[j] = t.balls[j], t.balls[i] }
+func (t *topBalls) Push(x any)         { t.balls = append(t.balls, x.(rankedBall)) }
+
+func (t *topBalls) Pop() any {
+	last := t.balls[len(t.balls)-1]
+	t.balls = t.balls[:len(t.balls)-1]
+	return last
+}
@@ -0,0 +1,197 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queryTestStores returns a JSONL store, which filters while reading, and a
+// memory store, which doesn't, holding the same 20 balls: every third one
+// blocked, even ones tagged "api"
+func queryTestStores(t *testing.T) map[string]*Store {
+	t.Helper()
+	jsonl, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	stores := map[string]*Store{
+		"jsonl":  jsonl,
+		"memory": NewStoreWithBackend(t.TempDir(), NewMemoryBallStore()),
+	}
+	for _, store := range stores {
+		for i := 0; i < 20; i++ {
+			ball := &Ball{ID: fmt.Sprintf("proj-%02d", i), Title: fmt.Sprintf("Ball %d", i), State: StatePending, Priority: PriorityMedium}
+			if i%3 == 0 {
+				ball.State = StateBlocked
+			}
+			if i%2 == 0 {
+				ball.Tags = []string{"api"}
+			}
+			if err := store.AppendBall(ball); err != nil {
+				t.Fatalf("AppendBall failed: %v", err)
+			}
+		}
+	}
+	return stores
+}
+
+func pageIDs(page *BallPage) string {
+	ids := make([]string, len(page.Balls))
+	for i, ball := range page.Balls {
+		ids[i] = strings.TrimPrefix(ball.ID, "proj-")
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestLoadBallsFiltered(t *testing.T) {
+	idDesc := func(a, b *Ball) bool { return a.ID > b.ID }
+	tests := []struct {
+		name  string
+		query BallQuery
+		want  string
+		more  bool
+	}{
+		{"all", BallQuery{Limit: 3}, "00,01,02", true},
+		{"state", BallQuery{States: []BallState{StateBlocked}}, "00,03,06,09,12,15,18", false},
+		{"state and tag", BallQuery{States: []BallState{StateBlocked}, Tags: []string{"api"}}, "00,06,12,18", false},
+		{"second page", BallQuery{Tags: []string{"api"}, Offset: 3, Limit: 3}, "06,08,10", true},
+		{"last page", BallQuery{Tags: []string{"api"}, Offset: 9, Limit: 3}, "18", false},
+		{"past the end", BallQuery{Offset: 50, Limit: 3}, "", false},
+		{"ordered", BallQuery{Less: idDesc, Limit: 4}, "19,18,17,16", true},
+		{"ordered second page", BallQuery{States: []BallState{StateBlocked}, Less: idDesc, Offset: 2, Limit: 2}, "12,09", true},
+		{"ordered last page", BallQuery{States: []BallState{StateBlocked}, Less: idDesc, Offset: 6, Limit: 2}, "00", false},
+		{"ordered ties in file order", BallQuery{Less: func(a, b *Ball) bool { return a.State == StateBlocked && b.State != StateBlocked }, Offset: 5, Limit: 4}, "15,18,01,02", true},
+		{"match", BallQuery{Match: func(b *Ball) bool { return strings.HasSuffix(b.Title, "1") }, Offset: 1}, "11", false},
+	}
+
+	for name, store := range queryTestStores(t) {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				page, err := store.LoadBallsFiltered(tt.query)
+				if err != nil {
+					t.Fatalf("LoadBallsFiltered failed: %v", err)
+				}
+				if got := pageIDs(page); got != tt.want || page.More != tt.more {
+					t.Errorf("Expected %q (more %v), got %q (more %v)", tt.want, tt.more, got, page.More)
+				}
+				for _, ball := range page.Balls {
+					if ball.WorkingDir != store.ProjectDir() {
+						t.Errorf("Expected WorkingDir %s, got %s", store.ProjectDir(), ball.WorkingDir)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestLoadAllBallsFiltered(t *testing.T) {
+	var projects []string
+	for p := 0; p < 2; p++ {
+		dir := t.TempDir()
+		store, err := NewStore(dir)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		for i, state := range []BallState{StatePending, StateInProgress, StatePending} {
+			ball := &Ball{ID: fmt.Sprintf("proj%d-%d", p, i), Title: "Ball", State: state, Priority: PriorityMedium, Tags: []string{fmt.Sprintf("s%d", i)}}
+			if err := store.AppendBall(ball); err != nil {
+				t.Fatalf("AppendBall failed: %v", err)
+			}
+		}
+		projects = append(projects, dir)
+	}
+
+	ids := func(balls []*Ball) string {
+		var ids []string
+		for _, ball := range balls {
+			ids = append(ids, ball.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+	pending, err := LoadPendingBalls(projects)
+	if err != nil {
+		t.Fatalf("LoadPendingBalls failed: %v", err)
+	}
+	if got := ids(pending); got != "proj0-0,proj0-2,proj1-0,proj1-2" {
+		t.Errorf("Expected the pending balls of both projects, got %s", got)
+	}
+	inSession, err := LoadBallsBySession(projects, "s1")
+	if err != nil {
+		t.Fatalf("LoadBallsBySession failed: %v", err)
+	}
+	if got := ids(inSession); got != "proj0-1,proj1-1" {
+		t.Errorf("Expected the session's balls from both projects, got %s", got)
+	}
+}
+
+func TestLoadBallsFilteredLegacyLines(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	lines := `{"id":"proj-1","intent":"Legacy title","state":"pending"}` + "\n\n" +
+		`not json` + "\n" +
+		`{"id":"proj-2","title":"Current","state":"blocked"}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, ".juggle", "balls.jsonl"), []byte(lines), 0644); err != nil {
+		t.Fatalf("failed to write balls: %v", err)
+	}
+
+	page, err := store.LoadBallsFiltered(BallQuery{States: []BallState{StatePending}})
+	if err != nil {
+		t.Fatalf("LoadBallsFiltered failed: %v", err)
+	}
+	if len(page.Balls) != 1 || page.Balls[0].Title != "Legacy title" {
+		t.Errorf("Expected the legacy ball with its title migrated, got %+v", page.Balls)
+	}
+}
+
+func TestQueryArchivePages(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		ball := &Ball{ID: fmt.Sprintf("proj-%d", i), Title: fmt.Sprintf("Ball %d", i), State: StatePending, Priority: PriorityMedium}
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("AppendBall failed: %v", err)
+		}
+		// Archived out of completion order
+		completed := start.Add(time.Duration(i*3%7) * time.Minute)
+		ball.State = StateComplete
+		ball.CompletedAt = &completed
+		if err := store.ArchiveBall(ball); err != nil {
+			t.Fatalf("ArchiveBall failed: %v", err)
+		}
+	}
+
+	// Kept while reading, then merged across projects
+	tests := []struct {
+		sortBy ArchiveSortBy
+		want   string
+	}{
+		{"", "proj-6,proj-1,proj-3"},
+		{SortByCompletedAsc, "proj-3,proj-1,proj-6"},
+	}
+	for _, projects := range [][]string{{dir}, {dir, t.TempDir()}} {
+		for _, tt := range tests {
+			balls, err := QueryArchive(projects, ArchiveQuery{Offset: 2, Limit: 3, SortBy: tt.sortBy})
+			if err != nil {
+				t.Fatalf("QueryArchive failed: %v", err)
+			}
+			var ids []string
+			for _, ball := range balls {
+				ids = append(ids, ball.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("Expected the second page by %q for %d project(s) to be %s, got %s", tt.sortBy, len(projects), tt.want, got)
+			}
+		}
+	}
+}
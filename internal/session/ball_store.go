@@ -0,0 +1,175 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// BallStore is where a Store keeps its balls. JSONLBallStore, the default,
+// keeps them in files in the project; other backends (a database, a remote
+// API, MemoryBallStore in tests) plug in through NewStoreWithBackend.
+//
+// Balls returned are the caller's to change: a change is only kept once
+// passed back to UpdateBall. Backends don't store a ball's WorkingDir; the
+// Store sets it.
+type BallStore interface {
+	// LoadBalls returns the active balls, in the order they were added
+	LoadBalls() ([]*Ball, error)
+	// LoadArchivedBalls returns the archived balls, in the order they were archived
+	LoadArchivedBalls() ([]*Ball, error)
+	// AppendBall adds a new active ball
+	AppendBall(ball *Ball) error
+	// UpdateBall replaces the active ball with the same ID, or returns a
+	// BallNotFoundError
+	UpdateBall(ball *Ball) error
+	// DeleteBall removes the active ball with the ID, if there is one
+	DeleteBall(id string) error
+	// ArchiveBall moves an active ball to the archive as given, or returns a
+	// BallNotFoundError
+	ArchiveBall(ball *Ball) error
+	// UnarchiveBall moves an archived ball back to the active balls as
+	// pending and returns it, or returns a BallNotFoundError
+	UnarchiveBall(id string) (*Ball, error)
+}
+
+// BallIDLister is a BallStore that can list the IDs of its active and
+// archived balls without loading them, as JSONLBallStore does
+type BallIDLister interface {
+	BallStore
+	BallIDs() ([]string, error)
+}
+
+// MemoryBallStore is a BallStore that keeps balls in memory, for tests and
+// tools that shouldn't touch a project's files. It is safe for concurrent use.
+type MemoryBallStore struct {
+	mu       sync.Mutex
+	balls    [][]byte
+	archived [][]byte
+}
+
+// NewMemoryBallStore creates an empty in-memory store
+func NewMemoryBallStore() *MemoryBallStore {
+	return &MemoryBallStore{}
+}
+
+// LoadBalls returns copies of the active balls
+func (m *MemoryBallStore) LoadBalls() ([]*Ball, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return decodeBalls(m.balls)
+}
+
+// LoadArchivedBalls returns copies of the archived balls
+func (m *MemoryBallStore) LoadArchivedBalls() ([]*Ball, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return decodeBalls(m.archived)
+}
+
+// AppendBall adds a copy of the ball
+func (m *MemoryBallStore) AppendBall(ball *Ball) error {
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ball: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balls = append(m.balls, data)
+	return nil
+}
+
+// UpdateBall replaces the active ball with the same ID
+func (m *MemoryBallStore) UpdateBall(ball *Ball) error {
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ball: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOfBall(m.balls, ball.ID)
+	if i < 0 {
+		return NewBallNotFoundError(ball.ID)
+	}
+	m.balls[i] = data
+	return nil
+}
+
+// DeleteBall removes the active ball with the ID
+func (m *MemoryBallStore) DeleteBall(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i := indexOfBall(m.balls, id); i >= 0 {
+		m.balls = append(m.balls[:i], m.balls[i+1:]...)
+	}
+	return nil
+}
+
+// ArchiveBall moves an active ball to the archive
+func (m *MemoryBallStore) ArchiveBall(ball *Ball) error {
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ball: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOfBall(m.balls, ball.ID)
+	if i < 0 {
+		return NewBallNotFoundError(ball.ID)
+	}
+	m.balls = append(m.balls[:i], m.balls[i+1:]...)
+	m.archived = append(m.archived, data)
+	return nil
+}
+
+// UnarchiveBall moves an archived ball back to the active balls as pending
+func (m *MemoryBallStore) UnarchiveBall(id string) (*Ball, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := indexOfBall(m.archived, id)
+	if i < 0 {
+		return nil, NewBallNotFoundError(id)
+	}
+	var ball Ball
+	if err := json.Unmarshal(m.archived[i], &ball); err != nil {
+		return nil, fmt.Errorf("failed to parse archived ball: %w", err)
+	}
+	ball.State = StatePending
+	ball.BlockedReason = ""
+	ball.CompletedAt = nil
+	ball.CompletionNote = ""
+
+	data, err := json.Marshal(&ball)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ball: %w", err)
+	}
+	m.archived = append(m.archived[:i], m.archived[i+1:]...)
+	m.balls = append(m.balls, data)
+	return &ball, nil
+}
+
+// decodeBalls decodes stored balls into fresh copies
+func decodeBalls(stored [][]byte) ([]*Ball, error) {
+	balls := make([]*Ball, 0, len(stored))
+	for _, data := range stored {
+		var ball Ball
+		if err := json.Unmarshal(data, &ball); err != nil {
+			return nil, fmt.Errorf("failed to parse ball: %w", err)
+		}
+		balls = append(balls, &ball)
+	}
+	return balls, nil
+}
+
+// indexOfBall returns the position of the stored ball with the ID, or -1
+func indexOfBall(stored [][]byte, id string) int {
+	for i, data := range stored {
+		var ball struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(data, &ball) == nil && ball.ID == id {
+			return i
+		}
+	}
+	return -1
+}
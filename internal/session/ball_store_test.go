@@ -0,0 +1,90 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestBallStoreBackends runs the same operations through a Store on each
+// backend, which should behave alike
+func TestBallStoreBackends(t *testing.T) {
+	backends := map[string]func(t *testing.T) *Store{
+		"jsonl": func(t *testing.T) *Store {
+			store, err := NewStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+			return store
+		},
+		"memory": func(t *testing.T) *Store {
+			return NewStoreWithBackend(t.TempDir(), NewMemoryBallStore())
+		},
+	}
+
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			first, _ := NewBall(store.ProjectDir(), "First", PriorityHigh)
+			second, _ := NewBall(store.ProjectDir(), "Second", PriorityLow)
+			for _, ball := range []*Ball{first, second} {
+				if err := store.AppendBall(ball); err != nil {
+					t.Fatalf("AppendBall failed: %v", err)
+				}
+			}
+
+			balls, err := store.LoadBalls()
+			if err != nil || len(balls) != 2 || balls[0].ID != first.ID {
+				t.Fatalf("Expected both balls in order, got %v (err %v)", balls, err)
+			}
+			if balls[0].WorkingDir != store.ProjectDir() {
+				t.Errorf("Expected WorkingDir %s, got %s", store.ProjectDir(), balls[0].WorkingDir)
+			}
+
+			// A loaded ball only changes once updated
+			balls[0].Title = "First, renamed"
+			if got, _ := store.GetBallByID(first.ID); got.Title != "First" {
+				t.Errorf("Expected the stored ball unchanged before UpdateBall, got %q", got.Title)
+			}
+			if err := store.UpdateBall(balls[0]); err != nil {
+				t.Fatalf("UpdateBall failed: %v", err)
+			}
+			if got, err := store.ResolveBallIDStrict(first.ID); err != nil || got.Title != "First, renamed" {
+				t.Errorf("Expected the renamed ball, got %v (err %v)", got, err)
+			}
+			missing := &Ball{ID: "nope-1"}
+			var notFound *BallNotFoundError
+			if err := store.UpdateBall(missing); !errors.As(err, &notFound) {
+				t.Errorf("Expected BallNotFoundError updating a missing ball, got %v", err)
+			}
+
+			// Archive and restore
+			second.State = StateComplete
+			if err := store.ArchiveBall(second); err != nil {
+				t.Fatalf("ArchiveBall failed: %v", err)
+			}
+			if ids, err := store.BallIDs(); err != nil || len(ids) != 2 || ids[0] != first.ID || ids[1] != second.ID {
+				t.Errorf("Expected the active then the archived ID, got %v (err %v)", ids, err)
+			}
+			if archived, _ := store.LoadArchivedBalls(); len(archived) != 1 || archived[0].State != StateComplete {
+				t.Errorf("Expected the completed ball archived, got %v", archived)
+			}
+			if balls, _ := store.LoadBalls(); len(balls) != 1 {
+				t.Errorf("Expected one active ball after archiving, got %d", len(balls))
+			}
+			restored, err := store.UnarchiveBall(second.ID)
+			if err != nil || restored.State != StatePending || restored.WorkingDir != store.ProjectDir() {
+				t.Fatalf("Expected the ball restored as pending, got %+v (err %v)", restored, err)
+			}
+			if _, err := store.UnarchiveBall(second.ID); !errors.As(err, &notFound) {
+				t.Errorf("Expected BallNotFoundError unarchiving twice, got %v", err)
+			}
+
+			if err := store.DeleteBall(first.ID); err != nil {
+				t.Fatalf("DeleteBall failed: %v", err)
+			}
+			if balls, _ := store.LoadBalls(); len(balls) != 1 || balls[0].ID != second.ID {
+				t.Errorf("Expected only the restored ball left, got %v", balls)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package session
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestExtractTitleFirstSentence(t *testing.T) {
 	tests := []struct {
@@ -102,3 +106,255 @@ func TestNewBallExtractsFirstSentence(t *testing.T) {
 		t.Errorf("NewBall() should extract first sentence, got %q", ball.Title)
 	}
 }
+
+func TestParseEnvAssignment(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{input: "DATABASE_URL=postgres://localhost/db?x=1", wantKey: "DATABASE_URL", wantValue: "postgres://localhost/db?x=1"},
+		{input: "DEBUG=", wantKey: "DEBUG"},
+		{input: "_private=1", wantKey: "_private", wantValue: "1"},
+		{input: "NOVALUE", wantErr: true},
+		{input: "1BAD=x", wantErr: true},
+		{input: "BAD-NAME=x", wantErr: true},
+		{input: "=x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			key, value, err := ParseEnvAssignment(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseEnvAssignment(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEnvAssignment(%q) error = %v", tt.input, err)
+			}
+			if key != tt.wantKey || value != tt.wantValue {
+				t.Errorf("ParseEnvAssignment(%q) = %q, %q, want %q, %q", tt.input, key, value, tt.wantKey, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestBallEnvironment(t *testing.T) {
+	ball := &Ball{}
+
+	ball.SetEnvVar("B", "2")
+	ball.SetEnvVar("A", "1")
+	if got := EnvList(ball.Env); len(got) != 2 || got[0] != "A=1" || got[1] != "B=2" {
+		t.Errorf("EnvList() = %v, want [A=1 B=2]", got)
+	}
+
+	ball.SetEnvVar("A", "")
+	ball.SetEnvVar("B", "")
+	if ball.Env != nil {
+		t.Errorf("Expected env to be nil after removing all vars, got %v", ball.Env)
+	}
+
+	ball.SetSetupCommands([]string{"docker compose up -d db", "make seed"})
+	if len(ball.Setup) != 2 {
+		t.Errorf("Expected 2 setup commands, got %v", ball.Setup)
+	}
+	ball.SetSetupCommands([]string{""})
+	if ball.Setup != nil {
+		t.Errorf("Expected empty command to clear setup, got %v", ball.Setup)
+	}
+}
+
+func TestBallTimeout(t *testing.T) {
+	ball := &Ball{}
+	if ball.Timeout() != 0 {
+		t.Errorf("Expected no timeout override by default, got %v", ball.Timeout())
+	}
+
+	ball.SetTimeout(44*time.Minute + 40*time.Second)
+	if ball.TimeoutMinutes != 45 || ball.Timeout() != 45*time.Minute {
+		t.Errorf("Expected the timeout rounded to 45 minutes, got %d (%v)", ball.TimeoutMinutes, ball.Timeout())
+	}
+
+	ball.SetTimeout(0)
+	if ball.TimeoutMinutes != 0 {
+		t.Errorf("Expected 0 to clear the override, got %d", ball.TimeoutMinutes)
+	}
+}
+
+func TestBallKind(t *testing.T) {
+	for _, kind := range []string{"", "code", "docs", "research", "ops"} {
+		if !ValidateBallKind(kind) {
+			t.Errorf("ValidateBallKind(%q) = false, want true", kind)
+		}
+	}
+	if ValidateBallKind("design") {
+		t.Error("ValidateBallKind(\"design\") = true, want false")
+	}
+
+	if !BallKindBlank.IsCode() || !BallKindCode.IsCode() || BallKindDocs.IsCode() {
+		t.Error("Expected only blank and code kinds to be code")
+	}
+
+	tests := []struct {
+		name           string
+		ball           Ball
+		sessionDefault ModelSize
+		want           ModelSize
+	}{
+		{"code has no default", Ball{Kind: BallKindCode}, ModelSizeBlank, ModelSizeBlank},
+		{"docs defaults to medium", Ball{Kind: BallKindDocs}, ModelSizeBlank, ModelSizeMedium},
+		{"session default beats kind", Ball{Kind: BallKindDocs}, ModelSizeSmall, ModelSizeSmall},
+		{"ball model size beats both", Ball{Kind: BallKindOps, ModelSize: ModelSizeLarge}, ModelSizeSmall, ModelSizeLarge},
+		{"estimate beats session default", Ball{Estimate: &BallEstimate{ModelSize: ModelSizeSmall}}, ModelSizeLarge, ModelSizeSmall},
+		{"ball model size beats estimate", Ball{ModelSize: ModelSizeLarge, Estimate: &BallEstimate{ModelSize: ModelSizeSmall}}, ModelSizeBlank, ModelSizeLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ball.PreferredModelSize(tt.sessionDefault); got != tt.want {
+				t.Errorf("PreferredModelSize(%q) = %q, want %q", tt.sessionDefault, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifiedID(t *testing.T) {
+	ball := &Ball{ID: "my-api-a1b2c3d4", WorkingDir: "/src/my-api"}
+	if got := ball.ProjectName(); got != "my-api" {
+		t.Errorf("ProjectName() = %q, want my-api", got)
+	}
+	if got := ball.QualifiedID(); got != "my-api:a1b2c3d4" {
+		t.Errorf("QualifiedID() = %q, want my-api:a1b2c3d4", got)
+	}
+
+	project, rest := SplitQualifiedID("api:12")
+	if project != "api" || rest != "12" {
+		t.Errorf("SplitQualifiedID(api:12) = %q, %q", project, rest)
+	}
+	if project, rest := SplitQualifiedID("12"); project != "" || rest != "12" {
+		t.Errorf("SplitQualifiedID(12) = %q, %q", project, rest)
+	}
+}
+
+func TestResolveBallByPrefix_Qualified(t *testing.T) {
+	balls := []*Ball{
+		{ID: "api-12ab34cd", WorkingDir: "/src/api"},
+		{ID: "web-12ef56ab", WorkingDir: "/src/web"},
+	}
+
+	if matches := ResolveBallByPrefix(balls, "12"); len(matches) != 2 {
+		t.Fatalf("expected unqualified prefix to match both projects, got %d", len(matches))
+	}
+	matches := ResolveBallByPrefix(balls, "API:12")
+	if len(matches) != 1 || matches[0].ID != "api-12ab34cd" {
+		t.Errorf("expected api:12 to pick the api ball, got %v", matches)
+	}
+	if matches := ResolveBallByPrefix(balls, "docs:12"); len(matches) != 0 {
+		t.Errorf("expected no matches in an unknown project, got %d", len(matches))
+	}
+}
+
+func TestResolveBallByPrefixWithArchive(t *testing.T) {
+	active := []*Ball{{ID: "proj-a1b2c3d4"}}
+	archived := []*Ball{{ID: "proj-a1ffeedd"}, {ID: "proj-b2c3d4e5"}}
+
+	if matches := ResolveBallByPrefixWithArchive(active, archived, "b2", false); len(matches) != 0 {
+		t.Errorf("expected archive to be ignored unless asked, got %d matches", len(matches))
+	}
+	if matches := ResolveBallByPrefixWithArchive(active, archived, "b2", true); len(matches) != 1 || matches[0].ID != "proj-b2c3d4e5" {
+		t.Errorf("expected archived match, got %v", matches)
+	}
+	if matches := ResolveBallByPrefixWithArchive(active, archived, "a1", true); len(matches) != 2 {
+		t.Errorf("expected a prefix shared with the archive to be ambiguous, got %d matches", len(matches))
+	}
+	if matches := ResolveBallByPrefixWithArchive(active, archived, "a1b2c3d4", true); len(matches) != 1 {
+		t.Errorf("expected an exact active match to win, got %d matches", len(matches))
+	}
+}
+
+func TestPickShortID(t *testing.T) {
+	candidates := []string{"aaaa1111", "AAAA2222", "bbbb3333"}
+	next := func() string {
+		c := candidates[0]
+		candidates = candidates[1:]
+		return c
+	}
+
+	got, err := pickShortID(map[string]bool{"aaaa1111": true, "aaaa2222": true}, next)
+	if err != nil || got != "bbbb3333" {
+		t.Errorf("pickShortID() = %q, %v, want bbbb3333", got, err)
+	}
+
+	if _, err := pickShortID(map[string]bool{"same": true}, func() string { return "same" }); err == nil {
+		t.Error("expected an error when every candidate is taken")
+	}
+}
+
+func TestNewBallAvoidsArchivedShortIDs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	ball, err := NewBall(dir, "First", PriorityMedium)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+	if !existingShortIDs(dir)[ball.ShortID()] {
+		t.Errorf("expected %s to be reported as taken", ball.ShortID())
+	}
+
+	ball.MarkComplete("done")
+	if err := store.ArchiveBall(ball); err != nil {
+		t.Fatalf("ArchiveBall failed: %v", err)
+	}
+	if !existingShortIDs(dir)[ball.ShortID()] {
+		t.Errorf("expected archived %s to stay taken", ball.ShortID())
+	}
+}
+
+func TestQueryArchiveSessionFilter(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	for _, tags := range [][]string{{"api"}, {"api-v2"}, {"docs", "api"}} {
+		ball, err := NewBall(dir, "Ball "+strings.Join(tags, ","), PriorityMedium)
+		if err != nil {
+			t.Fatalf("NewBall failed: %v", err)
+		}
+		ball.Tags = tags
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("AppendBall failed: %v", err)
+		}
+		ball.MarkComplete("done")
+		if err := store.ArchiveBall(ball); err != nil {
+			t.Fatalf("ArchiveBall failed: %v", err)
+		}
+	}
+
+	balls, err := QueryArchive([]string{dir}, ArchiveQuery{Session: "api"})
+	if err != nil {
+		t.Fatalf("QueryArchive failed: %v", err)
+	}
+	if len(balls) != 2 {
+		t.Errorf("expected 2 balls in session api, got %d", len(balls))
+	}
+}
+
+func TestUnmetDependencies(t *testing.T) {
+	done := &Ball{ID: "proj-aaaa1111", State: StateComplete}
+	open := &Ball{ID: "proj-bbbb2222", State: StateBlocked}
+	ball := &Ball{ID: "proj-cccc3333", State: StatePending, DependsOn: []string{"aaaa1111", "proj-bbbb2222", "proj-gone0000"}}
+
+	unmet := UnmetDependencies(ball, BallStates([]*Ball{done, open, ball}))
+	if len(unmet) != 1 || unmet[0] != "proj-bbbb2222" {
+		t.Errorf("expected only the blocked dependency to be unmet, got %v", unmet)
+	}
+}
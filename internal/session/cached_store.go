@@ -0,0 +1,261 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// BallChanges are changes to a store's active balls, written together
+type BallChanges struct {
+	Updated []*Ball  // Balls that replace the ones with the same ID
+	Added   []*Ball  // New balls, appended in order
+	Deleted []string // IDs of balls to remove
+}
+
+// Empty reports whether there are no changes
+func (c BallChanges) Empty() bool {
+	return len(c.Updated) == 0 && len(c.Added) == 0 && len(c.Deleted) == 0
+}
+
+// BatchBallStore is a BallStore that can write several changes at once, as
+// JSONLBallStore does with a single rewrite of balls.jsonl
+type BatchBallStore interface {
+	BallStore
+	// ApplyBallChanges writes the changes together. Updates of balls that
+	// are gone are skipped and reported as a BallNotFoundError once the rest
+	// are written.
+	ApplyBallChanges(changes BallChanges) error
+}
+
+// CachedBallStore reads the active balls of a backend once and keeps changes
+// to them in memory until Flush, so a command changing many balls rewrites
+// balls.jsonl once rather than once per ball. Archiving and unarchiving
+// flush first and go straight to the backend.
+//
+// Changes other processes make after the first read aren't seen until the
+// next Flush, so keep a cached store for the length of one operation, not
+// for the life of a long-running process.
+type CachedBallStore struct {
+	backend BallStore
+
+	mu      sync.Mutex
+	balls   []*Ball // Active balls, nil until first read
+	updated map[string]bool
+	added   []string
+	deleted []string
+}
+
+// NewCachedBallStore creates a cached store over a backend
+func NewCachedBallStore(backend BallStore) *CachedBallStore {
+	return &CachedBallStore{backend: backend}
+}
+
+// Dirty reports whether there are changes waiting for Flush
+func (c *CachedBallStore) Dirty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.changes().Empty()
+}
+
+// Flush writes the changes to the backend, in one go when it is a
+// BatchBallStore, and drops the cache so the next read sees other writers'
+// changes too
+func (c *CachedBallStore) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.flushLocked()
+}
+
+func (c *CachedBallStore) flushLocked() error {
+	changes := c.changes()
+	c.balls, c.updated, c.added, c.deleted = nil, nil, nil, nil
+	if changes.Empty() {
+		return nil
+	}
+	if batch, ok := c.backend.(BatchBallStore); ok {
+		return batch.ApplyBallChanges(changes)
+	}
+
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, ball := range changes.Updated {
+		keep(c.backend.UpdateBall(ball))
+	}
+	for _, id := range changes.Deleted {
+		keep(c.backend.DeleteBall(id))
+	}
+	for _, ball := range changes.Added {
+		keep(c.backend.AppendBall(ball))
+	}
+	return firstErr
+}
+
+// changes collects the pending changes. Caller must hold the lock.
+func (c *CachedBallStore) changes() BallChanges {
+	var changes BallChanges
+	isAdded := make(map[string]bool, len(c.added))
+	for _, id := range c.added {
+		isAdded[id] = true
+	}
+	for _, ball := range c.balls {
+		switch {
+		case isAdded[ball.ID]:
+			changes.Added = append(changes.Added, ball)
+		case c.updated[ball.ID]:
+			changes.Updated = append(changes.Updated, ball)
+		}
+	}
+	changes.Deleted = c.deleted
+	return changes
+}
+
+// load reads the backend's active balls if they aren't cached yet. Caller
+// must hold the lock.
+func (c *CachedBallStore) load() error {
+	if c.balls != nil {
+		return nil
+	}
+	balls, err := c.backend.LoadBalls()
+	if err != nil {
+		return err
+	}
+	if balls == nil {
+		balls = []*Ball{}
+	}
+	c.balls = balls
+	c.updated = make(map[string]bool)
+	return nil
+}
+
+// index returns the position of the cached ball with the ID, or -1. Caller
+// must hold the lock.
+func (c *CachedBallStore) index(id string) int {
+	for i, ball := range c.balls {
+		if ball.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// LoadBalls returns copies of the active balls
+func (c *CachedBallStore) LoadBalls() ([]*Ball, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	balls := make([]*Ball, 0, len(c.balls))
+	for _, ball := range c.balls {
+		clone, err := cloneBall(ball)
+		if err != nil {
+			return nil, err
+		}
+		balls = append(balls, clone)
+	}
+	return balls, nil
+}
+
+// LoadArchivedBalls reads the archived balls from the backend
+func (c *CachedBallStore) LoadArchivedBalls() ([]*Ball, error) {
+	return c.backend.LoadArchivedBalls()
+}
+
+// AppendBall adds a copy of the ball, written on Flush
+func (c *CachedBallStore) AppendBall(ball *Ball) error {
+	clone, err := cloneBall(ball)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+	c.balls = append(c.balls, clone)
+	c.added = append(c.added, clone.ID)
+	return nil
+}
+
+// UpdateBall replaces the cached ball with the same ID, written on Flush
+func (c *CachedBallStore) UpdateBall(ball *Ball) error {
+	clone, err := cloneBall(ball)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+	i := c.index(ball.ID)
+	if i < 0 {
+		return NewBallNotFoundError(ball.ID)
+	}
+	c.balls[i] = clone
+	c.updated[ball.ID] = true
+	return nil
+}
+
+// DeleteBall removes the cached ball with the ID, written on Flush
+func (c *CachedBallStore) DeleteBall(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+	i := c.index(id)
+	if i < 0 {
+		return nil
+	}
+	c.balls = append(c.balls[:i], c.balls[i+1:]...)
+	delete(c.updated, id)
+	for j, added := range c.added {
+		if added == id {
+			// Never written, so nothing to delete
+			c.added = append(c.added[:j], c.added[j+1:]...)
+			return nil
+		}
+	}
+	c.deleted = append(c.deleted, id)
+	return nil
+}
+
+// ArchiveBall flushes the changes, then archives the ball in the backend
+func (c *CachedBallStore) ArchiveBall(ball *Ball) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.flushLocked(); err != nil {
+		return err
+	}
+	return c.backend.ArchiveBall(ball)
+}
+
+// UnarchiveBall flushes the changes, then unarchives the ball in the backend
+func (c *CachedBallStore) UnarchiveBall(id string) (*Ball, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.flushLocked(); err != nil {
+		return nil, err
+	}
+	return c.backend.UnarchiveBall(id)
+}
+
+// cloneBall copies a ball through its JSON form, so the copy shares nothing
+// with the original, like a ball read back from a file
+func cloneBall(ball *Ball) (*Ball, error) {
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ball: %w", err)
+	}
+	var clone Ball
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to parse ball: %w", err)
+	}
+	return &clone, nil
+}
@@ -0,0 +1,140 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCachedStoreWritesOnFlush(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	var balls []*Ball
+	for _, title := range []string{"One", "Two", "Three"} {
+		ball, _ := NewBall(dir, title, PriorityMedium)
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("AppendBall failed: %v", err)
+		}
+		balls = append(balls, ball)
+	}
+
+	cached := store.Cached()
+	for _, ball := range balls[:2] {
+		ball.Title += " (edited)"
+		if err := cached.UpdateBall(ball); err != nil {
+			t.Fatalf("UpdateBall failed: %v", err)
+		}
+	}
+	added, _ := NewBall(dir, "Four", PriorityLow)
+	if err := cached.AppendBall(added); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+	if err := cached.DeleteBall(balls[2].ID); err != nil {
+		t.Fatalf("DeleteBall failed: %v", err)
+	}
+
+	// The cached store sees its changes; the file doesn't have them yet
+	if got, err := cached.GetBallByID(balls[0].ID); err != nil || got.Title != "One (edited)" {
+		t.Errorf("Expected the cached edit, got %v (err %v)", got, err)
+	}
+	if got, _ := store.GetBallByID(balls[0].ID); got.Title != "One" {
+		t.Errorf("Expected balls.jsonl unchanged before Flush, got %q", got.Title)
+	}
+
+	// Another writer's change to a ball the batch didn't touch survives
+	other, _ := NewStore(dir)
+	third, _ := other.GetBallByID(balls[2].ID)
+	third.Priority = PriorityUrgent
+	if err := other.UpdateBall(third); err != nil {
+		t.Fatalf("UpdateBall failed: %v", err)
+	}
+	fifth, _ := NewBall(dir, "Five", PriorityLow)
+	if err := other.AppendBall(fifth); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+
+	if err := cached.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	got, err := store.LoadBalls()
+	if err != nil {
+		t.Fatalf("LoadBalls failed: %v", err)
+	}
+	var titles []string
+	for _, ball := range got {
+		titles = append(titles, ball.Title)
+	}
+	want := []string{"One (edited)", "Two (edited)", "Five", "Four"}
+	if len(titles) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, titles)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, titles)
+		}
+	}
+	if cached.Backend().(*CachedBallStore).Dirty() {
+		t.Error("Expected no changes left after Flush")
+	}
+}
+
+func TestCachedStoreMissingBall(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewStore(dir)
+	kept, _ := NewBall(dir, "Kept", PriorityMedium)
+	gone, _ := NewBall(dir, "Gone", PriorityMedium)
+	for _, ball := range []*Ball{kept, gone} {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("AppendBall failed: %v", err)
+		}
+	}
+
+	// A ball archived by someone else meanwhile is reported, the rest written
+	err := store.Batch(func(cached *Store) error {
+		for _, ball := range []*Ball{kept, gone} {
+			ball.Title += "!"
+			if err := cached.UpdateBall(ball); err != nil {
+				return err
+			}
+		}
+		return store.ArchiveBall(gone)
+	})
+	var notFound *BallNotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != gone.ID {
+		t.Fatalf("Expected BallNotFoundError for %s, got %v", gone.ID, err)
+	}
+	if got, _ := store.GetBallByID(kept.ID); got.Title != "Kept!" {
+		t.Errorf("Expected the other update written, got %q", got.Title)
+	}
+}
+
+func TestCachedStoreWithoutBatchBackend(t *testing.T) {
+	store := NewStoreWithBackend(t.TempDir(), NewMemoryBallStore())
+	first, _ := NewBall(store.ProjectDir(), "First", PriorityMedium)
+	if err := store.AppendBall(first); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+
+	// Changes are replayed one by one on a backend that can't batch
+	err := store.Batch(func(cached *Store) error {
+		first.State = StateInProgress
+		if err := cached.UpdateBall(first); err != nil {
+			return err
+		}
+		second, _ := NewBall(store.ProjectDir(), "Second", PriorityMedium)
+		if err := cached.AppendBall(second); err != nil {
+			return err
+		}
+		// Added and removed in the same batch: never written
+		return cached.DeleteBall(second.ID)
+	})
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	balls, _ := store.LoadBalls()
+	if len(balls) != 1 || balls[0].State != StateInProgress {
+		t.Errorf("Expected only the updated first ball, got %v", balls)
+	}
+}
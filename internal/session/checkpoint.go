@@ -0,0 +1,92 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const checkpointFile = "checkpoint.json"
+
+// RunCheckpoint is the agent loop's state at the start of its latest
+// iteration, so a run that crashed or was killed can pick up where it left off.
+//
+// Checkpoints are stored in .juggle/sessions/<id>/checkpoint.json
+// (checkpoint-<ball-id>.json for single-ball runs) and removed when a run
+// finishes on its own.
+type RunCheckpoint struct {
+	Iteration        int               `json:"iteration"` // Iteration in progress, run again on resume
+	MaxIterations    int               `json:"max_iterations"`
+	BallID           string            `json:"ball_id,omitempty"`
+	RateLimitRetries int               `json:"rate_limit_retries,omitempty"`
+	OverloadRetries  int               `json:"overload_retries,omitempty"`
+	CrashRetries     int               `json:"crash_retries,omitempty"`
+	RateLimitWait    time.Duration     `json:"rate_limit_wait,omitempty"`
+	OverloadWait     time.Duration     `json:"overload_wait,omitempty"`
+	InputTokens      int               `json:"input_tokens,omitempty"`
+	OutputTokens     int               `json:"output_tokens,omitempty"`
+	CostUSD          float64           `json:"cost_usd,omitempty"`
+	IterationTimings []IterationTiming `json:"iteration_timings,omitempty"`
+	StartedAt        time.Time         `json:"started_at"` // When the original run started
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// checkpointFilePath returns the path to a session's checkpoint for the given
+// ball, or for the whole session when ballID is empty
+func (s *SessionStore) checkpointFilePath(id, ballID string) string {
+	name := checkpointFile
+	if ballID != "" {
+		name = "checkpoint-" + ballID + ".json"
+	}
+	return filepath.Join(s.sessionPath(id), name)
+}
+
+// SaveCheckpoint writes the run's checkpoint, replacing the previous one
+func (s *SessionStore) SaveCheckpoint(id string, cp *RunCheckpoint) error {
+	if err := os.MkdirAll(s.sessionPath(id), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	cp.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	// Write then rename so a kill mid-write leaves the previous checkpoint intact
+	path := s.checkpointFilePath(id, cp.BallID)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadCheckpoint returns the session's checkpoint for the given ball (or the
+// whole session), or nil if there is none
+func (s *SessionStore) LoadCheckpoint(id, ballID string) (*RunCheckpoint, error) {
+	data, err := os.ReadFile(s.checkpointFilePath(id, ballID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp RunCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// ClearCheckpoint removes the session's checkpoint for the given ball (or the whole session)
+func (s *SessionStore) ClearCheckpoint(id, ballID string) error {
+	err := os.Remove(s.checkpointFilePath(id, ballID))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunCheckpoint_SaveLoadClear(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewSessionStore(dir)
+	if err != nil {
+		t.Fatalf("NewSessionStore failed: %v", err)
+	}
+
+	if cp, err := store.LoadCheckpoint("feature", ""); err != nil || cp != nil {
+		t.Fatalf("expected no checkpoint, got %+v, %v", cp, err)
+	}
+
+	started := time.Now().Add(-time.Hour).Round(time.Second)
+	session := &RunCheckpoint{Iteration: 4, MaxIterations: 10, RateLimitRetries: 2, RateLimitWait: 5 * time.Minute, InputTokens: 1200, StartedAt: started}
+	ball := &RunCheckpoint{Iteration: 2, MaxIterations: 3, BallID: "app-7", StartedAt: started}
+	for _, cp := range []*RunCheckpoint{session, ball} {
+		if err := store.SaveCheckpoint("feature", cp); err != nil {
+			t.Fatalf("SaveCheckpoint failed: %v", err)
+		}
+	}
+
+	got, err := store.LoadCheckpoint("feature", "")
+	if err != nil || got == nil {
+		t.Fatalf("LoadCheckpoint failed: %+v, %v", got, err)
+	}
+	if got.Iteration != 4 || got.RateLimitRetries != 2 || got.RateLimitWait != 5*time.Minute || got.InputTokens != 1200 || !got.StartedAt.Equal(started) {
+		t.Errorf("session checkpoint = %+v, want the saved values", got)
+	}
+	// Single-ball runs keep their own checkpoint
+	got, err = store.LoadCheckpoint("feature", "app-7")
+	if err != nil || got == nil || got.Iteration != 2 {
+		t.Errorf("ball checkpoint = %+v, %v; want iteration 2", got, err)
+	}
+
+	if err := store.ClearCheckpoint("feature", ""); err != nil {
+		t.Fatalf("ClearCheckpoint failed: %v", err)
+	}
+	if cp, _ := store.LoadCheckpoint("feature", ""); cp != nil {
+		t.Error("expected session checkpoint to be cleared")
+	}
+	if cp, _ := store.LoadCheckpoint("feature", "app-7"); cp == nil {
+		t.Error("expected ball checkpoint to remain")
+	}
+	if err := store.ClearCheckpoint("feature", ""); err != nil {
+		t.Errorf("clearing a missing checkpoint should succeed, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"time"
+)
+
+// DefaultClaimTTL is how long a claim lasts when no TTL is given
+const DefaultClaimTTL = 4 * time.Hour
+
+// BallClaim marks a ball as taken by a person. Agent loops and daemons skip
+// claimed balls until the claim is released or expires, so they don't start
+// on work someone is already doing by hand.
+type BallClaim struct {
+	By        string    `json:"by"`
+	Note      string    `json:"note,omitempty"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the claim has lapsed at the given time
+func (c *BallClaim) Expired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// Claim marks the ball as claimed by who for ttl, replacing any earlier claim
+func (b *Ball) Claim(who, note string, ttl time.Duration) {
+	now := time.Now()
+	b.Claimed = &BallClaim{
+		By:        who,
+		Note:      note,
+		ClaimedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	b.UpdateActivity()
+}
+
+// ReleaseClaim removes the ball's claim
+func (b *Ball) ReleaseClaim() {
+	b.Claimed = nil
+	b.UpdateActivity()
+}
+
+// ActiveClaim returns the ball's claim if it hasn't expired, or nil
+func (b *Ball) ActiveClaim() *BallClaim {
+	if b.Claimed == nil || b.Claimed.Expired(time.Now()) {
+		return nil
+	}
+	return b.Claimed
+}
+
+// IsClaimed reports whether a person currently holds a claim on the ball
+func (b *Ball) IsClaimed() bool {
+	return b.ActiveClaim() != nil
+}
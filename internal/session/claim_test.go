@@ -0,0 +1,49 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBallClaim(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	ball, err := NewBall(dir, "Fix the flaky test", PriorityMedium)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	if ball.IsClaimed() {
+		t.Fatal("new ball should not be claimed")
+	}
+
+	ball.Claim("alice", "debugging locally", time.Hour)
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+	loaded, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("GetBallByID failed: %v", err)
+	}
+	claim := loaded.ActiveClaim()
+	if claim == nil || claim.By != "alice" || claim.Note != "debugging locally" {
+		t.Fatalf("ActiveClaim() = %+v, want alice's claim to survive a reload", claim)
+	}
+
+	if claim.Expired(claim.ClaimedAt) || !claim.Expired(claim.ExpiresAt) {
+		t.Error("Expired() should report true from ExpiresAt on")
+	}
+
+	// Lapsed claims no longer hold the ball
+	loaded.Claimed.ExpiresAt = time.Now().Add(-time.Minute)
+	if loaded.IsClaimed() {
+		t.Error("expired claim should not count")
+	}
+
+	ball.ReleaseClaim()
+	if ball.Claimed != nil || ball.IsClaimed() {
+		t.Error("ReleaseClaim should clear the claim")
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 const (
@@ -45,8 +48,13 @@ func DefaultConfigOptions() ConfigOptions {
 //
 // Global configuration includes:
 //   - SearchPaths: directories to scan for juggle projects
+//   - IgnorePaths: patterns excluding directories from discovery and search paths
 //   - IterationDelayMinutes/IterationDelayFuzz: pacing between agent runs
 //   - OverloadRetryMinutes: wait time after rate limit exhaustion
+//   - QuietHours: local-time windows in which agent runs don't start iterations
+//   - UsageWindow: the subscription plan's usage windows, for pacing agent runs
+//   - DesktopNotifications: OS notifications from agent runs on this machine
+//   - Email: summary emails from agent runs over SMTP
 //   - VCS: preferred version control system (git/jj)
 //
 // Unknown fields in the config file are preserved to prevent data loss
@@ -55,33 +63,151 @@ func DefaultConfigOptions() ConfigOptions {
 // Use LoadConfig() to read the config, and config.Save() to write changes.
 type Config struct {
 	SearchPaths []string `json:"search_paths"`
+	IgnorePaths []string `json:"ignore_paths,omitempty"` // Patterns for directories never treated as projects (e.g. "node_modules")
 	// Agent iteration delay settings
 	IterationDelayMinutes int `json:"iteration_delay_minutes,omitempty"` // Base delay between iterations in minutes
 	IterationDelayFuzz    int `json:"iteration_delay_fuzz,omitempty"`    // Random +/- variance in minutes
 	// Overload retry settings (for 529 errors after Claude's built-in retries exhaust)
 	OverloadRetryMinutes int `json:"overload_retry_minutes,omitempty"` // Minutes to wait before retrying after 529 overload exhaustion
+	// Quiet hours, e.g. "09:00-18:00" or "mon-fri 09:00-18:00" (see ParseQuietHours)
+	QuietHours string `json:"quiet_hours,omitempty"`
+	// Subscription plan usage windows (see UsageWindow)
+	UsageWindow *UsageWindowConfig `json:"usage_window,omitempty"`
 	// VCS settings
 	VCS string `json:"vcs,omitempty"` // Version control system: "git" or "jj"
 
 	// Agent provider settings
-	AgentProvider  string            `json:"agent_provider,omitempty"`  // Agent CLI: "claude" or "opencode"
-	ModelOverrides map[string]string `json:"model_overrides,omitempty"` // Custom model mappings (e.g., "opus": "anthropic/claude-opus-5")
+	AgentProvider   string                          `json:"agent_provider,omitempty"`   // Agent CLI: "claude", "opencode", "goose", "amp" or a custom provider name
+	ModelOverrides  map[string]string               `json:"model_overrides,omitempty"`  // Custom model mappings (e.g., "opus": "anthropic/claude-opus-5")
+	CustomProviders map[string]CustomProviderConfig `json:"custom_providers,omitempty"` // User-defined agent CLIs keyed by provider name
 
 	// Supervisor settings
 	Supervisor *SupervisorConfig `json:"supervisor,omitempty"` // Supervisor daemon configuration
+	// Resource limits for agents started by daemons
+	AgentLimits *AgentLimitsConfig `json:"agent_limits,omitempty"`
+	// OS notifications from agent runs on this machine (opt-in)
+	DesktopNotifications *DesktopNotificationsConfig `json:"desktop_notifications,omitempty"`
+	// Summary emails from agent runs over SMTP (opt-in)
+	Email *EmailConfig `json:"email,omitempty"`
+	// Fixed loopback address for the daemon API, e.g. "127.0.0.1:7717", so a
+	// monitor on another machine can reach it over SSH port forwarding
+	DaemonAPIListen string `json:"daemon_api_listen,omitempty"`
+
+	// Linear settings
+	Linear *LinearConfig `json:"linear,omitempty"` // Linear importer configuration
 
 	// UnknownFields stores any fields from the config file that aren't recognized.
 	// These are preserved when saving to avoid data loss.
 	UnknownFields map[string]interface{} `json:"-"`
 }
 
+// CustomProviderConfig describes an agent CLI that juggle drives without built-in support.
+// Argument templates may use {{prompt}}, {{model}} and {{system_prompt}} placeholders.
+type CustomProviderConfig struct {
+	Binary          string              `json:"binary"`                     // Executable name or path
+	Args            []string            `json:"args,omitempty"`             // Headless argument template
+	PromptVia       string              `json:"prompt_via,omitempty"`       // "stdin" (default) or "arg"
+	ModelFlag       string              `json:"model_flag,omitempty"`       // Flag used to pass the model, e.g. "--model"
+	Models          map[string]string   `json:"models,omitempty"`           // Canonical model name -> CLI model name
+	PermissionArgs  map[string][]string `json:"permission_args,omitempty"`  // Permission mode -> extra arguments
+	InteractiveArgs []string            `json:"interactive_args,omitempty"` // Interactive argument template (empty = unsupported)
+}
+
 // SupervisorConfig holds configuration for the juggle supervisor daemon
 type SupervisorConfig struct {
 	PollIntervalMinutes int  `json:"poll_interval_minutes,omitempty"` // How often to check session status (default: 5)
 	StallTimeoutMinutes int  `json:"stall_timeout_minutes,omitempty"` // Consider daemon stalled after this many minutes (default: 30)
 	AutoRestart         bool `json:"auto_restart,omitempty"`          // Automatically restart stalled daemons
 	MaxConcurrent       int  `json:"max_concurrent,omitempty"`        // Max concurrent daemons (default: 3)
+	MaxPerSession       int  `json:"max_per_session,omitempty"`       // Max loops juggle daemon runs at once per session (default: 1)
 	AutoLaunch          bool `json:"auto_launch,omitempty"`           // Auto-launch daemons for sessions with pending balls
+	AutoReap            bool `json:"auto_reap,omitempty"`             // Remove stale session storage on every poll (see juggle gc)
+	ReapIdleHours       int  `json:"reap_idle_hours,omitempty"`       // Storage untouched this long is stale (default: 168)
+	MaxCrashRestarts    int  `json:"max_crash_restarts,omitempty"`    // Restarts of a crashed daemon before giving up (default: 3)
+	CrashBackoffSeconds int  `json:"crash_backoff_seconds,omitempty"` // Wait before the first restart after a crash, doubling each time (default: 30)
+}
+
+// AgentLimitsConfig limits the resources of the agent CLIs that daemons start,
+// so unattended runs leave the machine usable
+type AgentLimitsConfig struct {
+	Nice      int    `json:"nice,omitempty"`       // CPU niceness, 1-19 (0 = unchanged)
+	IOClass   string `json:"io_class,omitempty"`   // Disk I/O class: "idle" or "best-effort" (empty = unchanged)
+	MaxMemory string `json:"max_memory,omitempty"` // Memory limit, e.g. "4G" (empty = unlimited)
+}
+
+// DefaultDesktopRateLimitWait is how long a rate-limit wait must be before a
+// desktop notification is shown for it
+const DefaultDesktopRateLimitWait = 15 * time.Minute
+
+// DesktopNotificationsConfig turns on OS notifications from agent runs on this
+// machine: notify-send on Linux, osascript on macOS and a toast on Windows
+type DesktopNotificationsConfig struct {
+	Enabled              bool     `json:"enabled"`
+	Events               []string `json:"events,omitempty"`                  // Event types to show (default: ball_blocked, rate_limit_wait, run_end)
+	RateLimitWaitMinutes int      `json:"rate_limit_wait_minutes,omitempty"` // Show rate-limit waits at least this long (default: 15)
+}
+
+// GetRateLimitWait returns how long a rate-limit wait must be to be shown,
+// defaulting to DefaultDesktopRateLimitWait
+func (d *DesktopNotificationsConfig) GetRateLimitWait() time.Duration {
+	if d.RateLimitWaitMinutes <= 0 {
+		return DefaultDesktopRateLimitWait
+	}
+	return time.Duration(d.RateLimitWaitMinutes) * time.Minute
+}
+
+// EnvSMTPPassword holds the SMTP password, used when the email config has none
+const EnvSMTPPassword = "JUGGLE_SMTP_PASSWORD"
+
+// DefaultSMTPPort is the SMTP submission port, which upgrades to TLS with STARTTLS
+const DefaultSMTPPort = 587
+
+// EmailConfig sends summary emails over SMTP when agent runs end or need a
+// human, for long unattended runs
+type EmailConfig struct {
+	Host     string   `json:"host"`               // SMTP server, e.g. "smtp.example.com"
+	Port     int      `json:"port,omitempty"`     // SMTP port (default: 587; 465 uses implicit TLS)
+	Username string   `json:"username,omitempty"` // SMTP login (empty = no authentication)
+	Password string   `json:"password,omitempty"` // SMTP password (falls back to JUGGLE_SMTP_PASSWORD)
+	From     string   `json:"from"`               // Sender address
+	To       []string `json:"to"`                 // Recipient addresses
+	Events   []string `json:"events,omitempty"`   // Event types to email (default: ball_blocked, run_end)
+}
+
+// GetPort returns the SMTP port, defaulting to DefaultSMTPPort
+func (e *EmailConfig) GetPort() int {
+	if e.Port <= 0 {
+		return DefaultSMTPPort
+	}
+	return e.Port
+}
+
+// GetPassword returns the configured SMTP password, or JUGGLE_SMTP_PASSWORD
+// when unset
+func (e *EmailConfig) GetPassword() string {
+	if e.Password != "" {
+		return e.Password
+	}
+	return os.Getenv(EnvSMTPPassword)
+}
+
+// EnvLinearAPIKey holds a Linear API key, used when the config has none
+const EnvLinearAPIKey = "LINEAR_API_KEY"
+
+// LinearConfig holds configuration for juggle import linear
+type LinearConfig struct {
+	APIKey    string `json:"api_key,omitempty"`    // Personal API key (falls back to LINEAR_API_KEY)
+	Team      string `json:"team,omitempty"`       // Default team key to import from, e.g. "ENG"
+	WriteBack bool   `json:"write_back,omitempty"` // Move issues of completed balls to a completed state on import
+}
+
+// GetAPIKey returns the configured API key, or LINEAR_API_KEY when unset.
+// A nil config only uses the environment.
+func (l *LinearConfig) GetAPIKey() string {
+	if l != nil && l.APIKey != "" {
+		return l.APIKey
+	}
+	return os.Getenv(EnvLinearAPIKey)
 }
 
 // DefaultSupervisorConfig returns supervisor config with sensible defaults
@@ -92,6 +218,7 @@ func DefaultSupervisorConfig() *SupervisorConfig {
 		AutoRestart:         true,
 		MaxConcurrent:       3,
 		AutoLaunch:          false,
+		AutoReap:            true,
 	}
 }
 
@@ -119,16 +246,69 @@ func (s *SupervisorConfig) GetMaxConcurrent() int {
 	return s.MaxConcurrent
 }
 
+// GetMaxPerSession returns the max loops juggle daemon runs at once per
+// session, defaulting to 1
+func (s *SupervisorConfig) GetMaxPerSession() int {
+	if s.MaxPerSession <= 0 {
+		return 1
+	}
+	return s.MaxPerSession
+}
+
+// maxCrashBackoff caps the wait before restarting a crashed daemon
+const maxCrashBackoff = time.Hour
+
+// GetMaxCrashRestarts returns how many times in a row a crashed daemon is
+// restarted, defaulting to 3
+func (s *SupervisorConfig) GetMaxCrashRestarts() int {
+	if s.MaxCrashRestarts <= 0 {
+		return 3
+	}
+	return s.MaxCrashRestarts
+}
+
+// GetCrashBackoff returns the wait before the nth restart in a row of a
+// crashed daemon: crash_backoff_seconds (default 30), doubled for each
+// restart before it, up to an hour
+func (s *SupervisorConfig) GetCrashBackoff(n int) time.Duration {
+	backoff := 30 * time.Second
+	if s.CrashBackoffSeconds > 0 {
+		backoff = time.Duration(s.CrashBackoffSeconds) * time.Second
+	}
+	for i := 1; i < n && backoff < maxCrashBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxCrashBackoff)
+}
+
+// GetReapIdle returns how long session storage must be idle to be reaped,
+// defaulting to DefaultReapIdle
+func (s *SupervisorConfig) GetReapIdle() time.Duration {
+	if s.ReapIdleHours <= 0 {
+		return DefaultReapIdle
+	}
+	return time.Duration(s.ReapIdleHours) * time.Hour
+}
+
 // knownConfigFields lists the field names we recognize in config JSON
 var knownConfigFields = map[string]bool{
 	"search_paths":            true,
+	"ignore_paths":            true,
 	"iteration_delay_minutes": true,
 	"iteration_delay_fuzz":    true,
 	"overload_retry_minutes":  true,
+	"quiet_hours":             true,
+	"usage_window":            true,
 	"vcs":                     true,
 	"agent_provider":          true,
 	"model_overrides":         true,
+	"custom_providers":        true,
 	"supervisor":              true,
+	"agent_limits":            true,
+	"desktop_notifications":   true,
+	"email":                   true,
+	"daemon_api_listen":       true,
+	"linear":                  true,
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling to capture unknown fields
@@ -148,13 +328,22 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 
 	// Copy known fields
 	c.SearchPaths = alias.SearchPaths
+	c.IgnorePaths = alias.IgnorePaths
 	c.IterationDelayMinutes = alias.IterationDelayMinutes
 	c.IterationDelayFuzz = alias.IterationDelayFuzz
 	c.OverloadRetryMinutes = alias.OverloadRetryMinutes
+	c.QuietHours = alias.QuietHours
+	c.UsageWindow = alias.UsageWindow
 	c.VCS = alias.VCS
 	c.AgentProvider = alias.AgentProvider
 	c.ModelOverrides = alias.ModelOverrides
+	c.CustomProviders = alias.CustomProviders
 	c.Supervisor = alias.Supervisor
+	c.AgentLimits = alias.AgentLimits
+	c.DesktopNotifications = alias.DesktopNotifications
+	c.Email = alias.Email
+	c.DaemonAPIListen = alias.DaemonAPIListen
+	c.Linear = alias.Linear
 
 	// Extract unknown fields
 	c.UnknownFields = make(map[string]interface{})
@@ -177,6 +366,9 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 
 	// Add known fields (they take precedence over unknown fields with same name)
 	result["search_paths"] = c.SearchPaths
+	if len(c.IgnorePaths) > 0 {
+		result["ignore_paths"] = c.IgnorePaths
+	}
 	if c.IterationDelayMinutes != 0 {
 		result["iteration_delay_minutes"] = c.IterationDelayMinutes
 	}
@@ -186,6 +378,12 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 	if c.OverloadRetryMinutes != 0 {
 		result["overload_retry_minutes"] = c.OverloadRetryMinutes
 	}
+	if c.QuietHours != "" {
+		result["quiet_hours"] = c.QuietHours
+	}
+	if c.UsageWindow != nil {
+		result["usage_window"] = c.UsageWindow
+	}
 	if c.VCS != "" {
 		result["vcs"] = c.VCS
 	}
@@ -195,9 +393,27 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 	if len(c.ModelOverrides) > 0 {
 		result["model_overrides"] = c.ModelOverrides
 	}
+	if len(c.CustomProviders) > 0 {
+		result["custom_providers"] = c.CustomProviders
+	}
 	if c.Supervisor != nil {
 		result["supervisor"] = c.Supervisor
 	}
+	if c.AgentLimits != nil {
+		result["agent_limits"] = c.AgentLimits
+	}
+	if c.DesktopNotifications != nil {
+		result["desktop_notifications"] = c.DesktopNotifications
+	}
+	if c.Email != nil {
+		result["email"] = c.Email
+	}
+	if c.DaemonAPIListen != "" {
+		result["daemon_api_listen"] = c.DaemonAPIListen
+	}
+	if c.Linear != nil {
+		result["linear"] = c.Linear
+	}
 
 	return json.Marshal(result)
 }
@@ -259,7 +475,7 @@ func LoadConfigWithOptions(opts ConfigOptions) (*Config, error) {
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+		return nil, NewConfigInvalidError(configPath, err)
 	}
 
 	// Ensure UnknownFields map is initialized
@@ -327,6 +543,28 @@ func (c *Config) RemoveSearchPath(path string) bool {
 	return false
 }
 
+// AddIgnorePath adds an ignore pattern if it doesn't already exist
+func (c *Config) AddIgnorePath(pattern string) bool {
+	for _, existing := range c.IgnorePaths {
+		if existing == pattern {
+			return false
+		}
+	}
+	c.IgnorePaths = append(c.IgnorePaths, pattern)
+	return true
+}
+
+// RemoveIgnorePath removes an ignore pattern
+func (c *Config) RemoveIgnorePath(pattern string) bool {
+	for i, existing := range c.IgnorePaths {
+		if existing == pattern {
+			c.IgnorePaths = append(c.IgnorePaths[:i], c.IgnorePaths[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // SetIterationDelay sets the delay between agent iterations.
 // delayMinutes is the base delay in minutes, fuzz is the +/- variance in minutes.
 func (c *Config) SetIterationDelay(delayMinutes, fuzz int) {
@@ -379,6 +617,11 @@ func EnsureProjectInSearchPaths(projectDir string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Stray .juggle directories in vendored code stay out of discovery
+	if config.IsIgnoredPath(projectDir) {
+		return nil
+	}
+
 	// Add the path if not already present
 	if config.AddSearchPath(projectDir) {
 		if err := config.Save(); err != nil {
@@ -397,16 +640,343 @@ func EnsureProjectInSearchPaths(projectDir string) error {
 //   - VCS: project-specific VCS preference (overrides global)
 //   - AgentProvider: project-specific agent CLI (overrides global)
 //   - ModelOverrides: project-specific model mappings (merged with global)
+//   - ProviderBinaries: absolute paths to agent CLIs, used instead of PATH lookup
 //   - RunAliases: named command aliases for `juggle worktree run`
+//   - DiffLimit: per-iteration diff size guardrail for agent commits
+//   - Formatters: formatter/linter commands run on touched files before agent commits
+//   - CommitGates: secret scanning and license header checks before agent commits
+//   - Gates: build, lint and typecheck commands that must pass before agent commits
+//   - Validation: test command that must pass before agent commits
+//   - VerifyCompletion: second-model check of balls the agent marks complete
+//   - Review: read-only review of a finished run, written to review.md
+//   - Sandbox: container the agent CLI runs in for --trust runs
+//   - MaxBallAttempts: retry budget after which agents block a ball
+//   - EscalateAfterFailures: failed attempts after which a ball moves to a larger model
+//   - NoOp: what agent runs do after consecutive iterations that changed nothing
+//   - Aging: automatic priority bumps for pending balls left untouched
+//   - Vault: folder of Markdown notes kept in sync with the balls
+//   - Webhooks: URLs notified of agent run lifecycle events
+//   - OutputProcessors: per-provider cleanup of agent output before it is saved
 //
 // These settings apply to all balls and sessions within the project.
 type ProjectConfig struct {
-	DefaultAcceptanceCriteria []string          `json:"default_acceptance_criteria,omitempty"` // Repo-level ACs applied to all sessions
-	ACTemplates               []string          `json:"ac_templates,omitempty"`                // Optional AC templates shown during ball creation
-	VCS                       string            `json:"vcs,omitempty"`                         // Version control system: "git" or "jj"
-	AgentProvider             string            `json:"agent_provider,omitempty"`              // Agent CLI: "claude" or "opencode"
-	ModelOverrides            map[string]string `json:"model_overrides,omitempty"`             // Custom model mappings
-	RunAliases                map[string]string `json:"run_aliases,omitempty"`                 // Named command aliases for worktree run
+	DefaultAcceptanceCriteria []string             `json:"default_acceptance_criteria,omitempty"` // Repo-level ACs applied to all sessions
+	ACTemplates               []string             `json:"ac_templates,omitempty"`                // Optional AC templates shown during ball creation
+	VCS                       string               `json:"vcs,omitempty"`                         // Version control system: "git" or "jj"
+	AgentProvider             string               `json:"agent_provider,omitempty"`              // Agent CLI: "claude", "opencode", "goose" or "amp"
+	ModelOverrides            map[string]string    `json:"model_overrides,omitempty"`             // Custom model mappings
+	ProviderBinaries          map[string]string    `json:"provider_binaries,omitempty"`           // Provider name to absolute path of its CLI
+	RunAliases                map[string]string    `json:"run_aliases,omitempty"`                 // Named command aliases for worktree run
+	DiffLimit                 *DiffLimitConfig     `json:"diff_limit,omitempty"`                  // Per-iteration diff size guardrail
+	Formatters                []FormatterConfig    `json:"formatters,omitempty"`                  // Fix-up commands run before agent commits
+	CommitGates               *CommitGatesConfig   `json:"commit_gates,omitempty"`                // Checks that must pass before agent commits
+	Gates                     []GateConfig         `json:"gates,omitempty"`                       // Build/lint/typecheck commands that must pass before agent commits
+	Validation                *ValidationConfig    `json:"validation,omitempty"`                  // Tests that must pass before agent commits
+	VerifyCompletion          bool                 `json:"verify_completion,omitempty"`           // Re-check completed balls with a second, cheaper model
+	VerifyModel               string               `json:"verify_model,omitempty"`                // Model for the completion verifier (default: small)
+	Review                    bool                 `json:"review,omitempty"`                      // Review the run's diff once all balls are terminal
+	Toolchain                 *ToolchainConfig     `json:"toolchain,omitempty"`                   // Build/test/lint briefing in the agent system prompt
+	Retry                     *RetryConfig         `json:"retry,omitempty"`                       // Backoff between retries of failed agent runs
+	Sandbox                   *SandboxConfig       `json:"sandbox,omitempty"`                     // Container the agent CLI runs in
+	MaxTokens                 int                  `json:"max_tokens,omitempty"`                  // Default token budget per agent run (0 = unlimited)
+	MaxCostUSD                float64              `json:"max_cost_usd,omitempty"`                // Default cost budget per agent run in USD (0 = unlimited)
+	DuplicateWork             *DuplicateWorkConfig `json:"duplicate_work,omitempty"`              // Check for other sessions changing the same paths
+	MaxBallAttempts           int                  `json:"max_ball_attempts,omitempty"`           // Agent iterations a ball may take before it is blocked (0 = unlimited)
+	EscalateAfterFailures     int                  `json:"escalate_after_failures,omitempty"`     // Failed attempts at a model before a ball moves to the next larger one (0 = never)
+	NoOp                      *NoOpConfig          `json:"no_op,omitempty"`                       // Strategy change after consecutive iterations that changed nothing
+	Aging                     *AgingConfig         `json:"aging,omitempty"`                       // Raise the priority of pending balls left untouched
+	Vault                     *VaultConfig         `json:"vault,omitempty"`                       // Folder of Markdown notes mirroring the balls
+	Webhooks                  []WebhookConfig      `json:"webhooks,omitempty"`                    // URLs POSTed JSON on agent run lifecycle events
+	OutputProcessors          map[string][]string  `json:"output_processors,omitempty"`           // Provider name ("*" for any) to post-processors applied to its output
+}
+
+// Webhook payload formats
+const (
+	WebhookFormatJSON    = "json"    // The event as JSON (default)
+	WebhookFormatSlack   = "slack"   // A message for a Slack incoming webhook
+	WebhookFormatDiscord = "discord" // A message for a Discord webhook
+)
+
+// WebhookConfig is a URL that agent runs POST a JSON payload to on lifecycle
+// events: run start, ball complete, ball blocked and run end
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Format  string            `json:"format,omitempty"`  // Payload format: "json" (default), "slack" or "discord"
+	Events  []string          `json:"events,omitempty"`  // Event types to send (default: all; ball_blocked and run_end for slack and discord)
+	Headers map[string]string `json:"headers,omitempty"` // Extra request headers, e.g. Authorization
+}
+
+// IsChat reports whether the webhook posts chat messages rather than events
+func (w WebhookConfig) IsChat() bool {
+	return w.Format == WebhookFormatSlack || w.Format == WebhookFormatDiscord
+}
+
+// VaultConfig sets the folder juggle sync vault mirrors the project's balls
+// into, e.g. a folder inside an Obsidian vault
+type VaultConfig struct {
+	Path string `json:"path"` // Absolute, ~/-relative or project-relative folder
+}
+
+// Dir resolves the vault folder for a project, expanding ~/ and relative paths
+func (v *VaultConfig) Dir(projectDir string) string {
+	if v == nil || v.Path == "" {
+		return ""
+	}
+	path := v.Path
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// DuplicateWorkConfig controls the check, before an agent run starts, for other
+// sessions that recently changed the paths its balls are about to work on
+type DuplicateWorkConfig struct {
+	Mode        string `json:"mode,omitempty"`         // "warn" (default), "confirm", "block" or "off"
+	WindowHours int    `json:"window_hours,omitempty"` // How recent a change must be to count (default 24)
+}
+
+// Window returns how far back other sessions' changes are considered
+func (d *DuplicateWorkConfig) Window() time.Duration {
+	if d == nil || d.WindowHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(d.WindowHours) * time.Hour
+}
+
+// CheckMode returns the configured mode, "warn" when unset
+func (d *DuplicateWorkConfig) CheckMode() string {
+	if d == nil || d.Mode == "" {
+		return "warn"
+	}
+	return d.Mode
+}
+
+// NoOpConfig controls what agent runs do after consecutive no-op iterations:
+// iterations that changed no files, no ball states and logged no progress
+// beyond boilerplate
+type NoOpConfig struct {
+	MaxConsecutive int    `json:"max_consecutive,omitempty"` // No-op iterations in a row before acting (default 3)
+	Action         string `json:"action,omitempty"`          // "nudge" (default), "escalate", "stop" or "off"
+}
+
+// Limit returns how many no-op iterations in a row trigger the action
+func (n *NoOpConfig) Limit() int {
+	if n == nil || n.MaxConsecutive <= 0 {
+		return 3
+	}
+	return n.MaxConsecutive
+}
+
+// Strategy returns the configured action, "nudge" when unset
+func (n *NoOpConfig) Strategy() string {
+	if n == nil || n.Action == "" {
+		return "nudge"
+	}
+	return n.Action
+}
+
+// AgingConfig raises the priority of pending balls nobody touched for a while,
+// so old small tasks aren't starved by a stream of new high-priority work
+type AgingConfig struct {
+	AfterDays   int      `json:"after_days"`             // Days a pending ball sits untouched before each bump (0 = off)
+	MaxPriority Priority `json:"max_priority,omitempty"` // Highest priority aging raises a ball to (default "high")
+}
+
+// After returns how long a pending ball must sit untouched before a bump (0 = off)
+func (a *AgingConfig) After() time.Duration {
+	if a == nil || a.AfterDays <= 0 {
+		return 0
+	}
+	return time.Duration(a.AfterDays) * 24 * time.Hour
+}
+
+// Ceiling returns the highest priority aging raises a ball to, "high" when unset
+func (a *AgingConfig) Ceiling() Priority {
+	if a == nil || a.MaxPriority == "" {
+		return PriorityHigh
+	}
+	return a.MaxPriority
+}
+
+// SandboxConfig runs the agent CLI inside a docker or podman container that only
+// mounts the project directory, so --trust runs can't touch the rest of the host.
+type SandboxConfig struct {
+	Image   string   `json:"image"`             // Image with the agent CLI (and juggle) installed
+	Engine  string   `json:"engine,omitempty"`  // "docker" (default) or "podman"
+	Always  bool     `json:"always,omitempty"`  // Sandbox every run, not only --trust runs
+	Env     []string `json:"env,omitempty"`     // Host environment variables passed through, e.g. ANTHROPIC_API_KEY
+	Network string   `json:"network,omitempty"` // Container network, e.g. "none" (empty = engine default)
+	Args    []string `json:"args,omitempty"`    // Extra arguments for the engine's "run" command
+}
+
+// Applies reports whether a run with the given trust setting should be sandboxed
+func (s *SandboxConfig) Applies(trust bool) bool {
+	return s != nil && s.Image != "" && (trust || s.Always)
+}
+
+// RetryConfig overrides the agent loop's retry policies. Unset policies, and
+// unset fields within a policy, keep the built-in defaults.
+type RetryConfig struct {
+	RateLimit *RetryPolicyConfig `json:"rate_limit,omitempty"` // Provider rate limits
+	Overload  *RetryPolicyConfig `json:"overload,omitempty"`   // 529 overload after the agent's own retries
+	Crash     *RetryPolicyConfig `json:"crash,omitempty"`      // Agent exiting with an error
+}
+
+// RetryPolicyConfig is one retry policy: waits start at base and double up to
+// cap, varied by jitter, for at most max_attempts retries
+type RetryPolicyConfig struct {
+	BaseSeconds int     `json:"base_seconds,omitempty"` // Wait before the first retry
+	CapSeconds  int     `json:"cap_seconds,omitempty"`  // Longest wait
+	Jitter      float64 `json:"jitter,omitempty"`       // Random +/- fraction of each wait, e.g. 0.2
+	MaxAttempts int     `json:"max_attempts,omitempty"` // Retries before giving up (-1 = unlimited)
+}
+
+// ToolchainConfig controls the toolchain briefing added to the agent system prompt.
+// By default the project's languages and commands are detected from its manifests;
+// commands set here replace the detected ones.
+type ToolchainConfig struct {
+	Disabled bool   `json:"disabled,omitempty"` // Leave the briefing out of the system prompt
+	Build    string `json:"build,omitempty"`    // Build command, e.g. "make"
+	Test     string `json:"test,omitempty"`     // Test command, e.g. "make test"
+	Lint     string `json:"lint,omitempty"`     // Lint command, e.g. "make lint"
+	Notes    string `json:"notes,omitempty"`    // Extra guidance appended to the briefing
+}
+
+// Test selection modes for ValidationConfig.Affected
+const (
+	AffectedGo   = "go"   // Map changed files to Go packages and their dependents
+	AffectedJest = "jest" // Pass changed files to jest --findRelatedTests
+)
+
+// ValidationConfig is the test command run on each agent iteration before it is committed.
+// A failing run skips the commit and reopens the balls so the next iteration can fix it.
+type ValidationConfig struct {
+	Command         string `json:"command"`                    // Full test command, e.g. "go test ./..."
+	Affected        string `json:"affected,omitempty"`         // Run only tests affected by changed files: "go" or "jest" (empty = always run Command)
+	AffectedCommand string `json:"affected_command,omitempty"` // Command the selected packages or files are appended to (default per mode)
+}
+
+// Enabled reports whether a validation command is configured
+func (v *ValidationConfig) Enabled() bool {
+	return v != nil && v.Command != ""
+}
+
+// SelectionCommand returns the command used for affected test runs
+func (v *ValidationConfig) SelectionCommand() string {
+	if v.AffectedCommand != "" {
+		return v.AffectedCommand
+	}
+	switch v.Affected {
+	case AffectedGo:
+		return "go test"
+	case AffectedJest:
+		return "npx jest --findRelatedTests --passWithNoTests"
+	default:
+		return ""
+	}
+}
+
+// When a gate runs, per GateConfig.On
+const (
+	GateOnCommit   = "commit"   // Every iteration the agent asks to commit (default)
+	GateOnComplete = "complete" // Only iterations that move a ball to complete
+)
+
+// GateConfig is a build, lint or typecheck command an agent iteration must pass
+// before it is committed. Gates run in order before the validation tests; the
+// first failure skips the commit and reopens balls like failing tests do.
+type GateConfig struct {
+	Name           string `json:"name,omitempty"`            // Shown in progress and results, e.g. "lint" (default: the command)
+	Command        string `json:"command"`                   // Shell command, e.g. "go build ./..."
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"` // Fail the gate after this long (0 = no limit)
+	On             string `json:"on,omitempty"`              // "commit" (default) or "complete"
+}
+
+// Label names the gate in output, falling back to its command
+func (g GateConfig) Label() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return g.Command
+}
+
+// Timeout returns the gate's time limit, 0 for none
+func (g GateConfig) Timeout() time.Duration {
+	return time.Duration(g.TimeoutSeconds) * time.Second
+}
+
+// OnComplete reports whether the gate only runs when a ball is completed
+func (g GateConfig) OnComplete() bool {
+	return g.On == GateOnComplete
+}
+
+// CommitGatesConfig enables checks run on an agent iteration's diff before it is committed.
+// A failing gate aborts the commit and blocks the ball for human review.
+type CommitGatesConfig struct {
+	SecretScan      bool     `json:"secret_scan,omitempty"`      // Scan added lines for credentials and keys
+	LicenseHeader   string   `json:"license_header,omitempty"`   // Text new files must contain near the top (empty = no check)
+	LicensePatterns []string `json:"license_patterns,omitempty"` // Glob patterns of files needing the header (empty = all new files)
+}
+
+// Enabled reports whether any gate is configured
+func (g *CommitGatesConfig) Enabled() bool {
+	return g != nil && (g.SecretScan || g.LicenseHeader != "")
+}
+
+// NeedsLicenseHeader reports whether the license check applies to the given path
+func (g *CommitGatesConfig) NeedsLicenseHeader(path string) bool {
+	return matchesPatterns(g.LicensePatterns, path)
+}
+
+// FormatterConfig is a formatter or linter run on files touched by an agent iteration.
+// The matching file paths are appended to Command as arguments.
+type FormatterConfig struct {
+	Command  string   `json:"command"`            // Shell command, e.g. "gofmt -w" or "ruff check --fix"
+	Patterns []string `json:"patterns,omitempty"` // Glob patterns matched against file names (empty = all files)
+}
+
+// Matches reports whether the formatter applies to the given project-relative path.
+// Patterns match either the base name ("*.go") or the full path ("web/*.ts").
+func (f FormatterConfig) Matches(path string) bool {
+	return matchesPatterns(f.Patterns, path)
+}
+
+// matchesPatterns matches a path against globs on its base name or full path.
+// An empty pattern list matches everything.
+func matchesPatterns(patterns []string, path string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffLimitConfig caps how large a single agent iteration's diff may be before
+// juggle refuses to auto-commit it. Zero means no limit for that dimension.
+type DiffLimitConfig struct {
+	MaxFiles int `json:"max_files,omitempty"` // Maximum number of changed files
+	MaxLines int `json:"max_lines,omitempty"` // Maximum number of added plus deleted lines
+}
+
+// Exceeded reports whether the given diff size is over either limit
+func (d *DiffLimitConfig) Exceeded(files, lines int) bool {
+	if d == nil {
+		return false
+	}
+	return (d.MaxFiles > 0 && files > d.MaxFiles) || (d.MaxLines > 0 && lines > d.MaxLines)
 }
 
 // DefaultProjectConfig returns a new project config with initial values
@@ -434,7 +1004,7 @@ func LoadProjectConfig(projectDir string) (*ProjectConfig, error) {
 
 	var config ProjectConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal project config: %w", err)
+		return nil, NewConfigInvalidError(configPath, err)
 	}
 
 	return &config, nil
@@ -587,6 +1157,119 @@ func GetProjectACTemplates(projectDir string) ([]string, error) {
 	return config.ACTemplates, nil
 }
 
+// GetQuietHours parses the configured quiet hours, returning nil when none are set
+func (c *Config) GetQuietHours() (*QuietHours, error) {
+	if strings.TrimSpace(c.QuietHours) == "" {
+		return nil, nil
+	}
+	return ParseQuietHours(c.QuietHours)
+}
+
+// UpdateGlobalQuietHoursWithOptions validates and saves the quiet hours
+// (empty clears them)
+func UpdateGlobalQuietHoursWithOptions(opts ConfigOptions, spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec != "" {
+		if _, err := ParseQuietHours(spec); err != nil {
+			return err
+		}
+	}
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	config.QuietHours = spec
+	return config.SaveWithOptions(opts)
+}
+
+// GetGlobalQuietHoursWithOptions returns the quiet hours from global config,
+// or nil when none are set
+func GetGlobalQuietHoursWithOptions(opts ConfigOptions) (*QuietHours, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return config.GetQuietHours()
+}
+
+// UpdateGlobalUsageWindowWithOptions saves the plan's usage windows (nil clears them)
+func UpdateGlobalUsageWindowWithOptions(opts ConfigOptions, window *UsageWindowConfig) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	config.UsageWindow = window
+	return config.SaveWithOptions(opts)
+}
+
+// GetGlobalAgentLimitsWithOptions returns the resource limits for agents
+// started by daemons, or nil when none are set
+func GetGlobalAgentLimitsWithOptions(opts ConfigOptions) (*AgentLimitsConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return config.AgentLimits, nil
+}
+
+// UpdateGlobalAgentLimitsWithOptions saves the resource limits for agents
+// started by daemons (nil clears them)
+func UpdateGlobalAgentLimitsWithOptions(opts ConfigOptions, limits *AgentLimitsConfig) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	config.AgentLimits = limits
+	return config.SaveWithOptions(opts)
+}
+
+// GetGlobalDesktopNotificationsWithOptions returns the desktop notification
+// settings, or nil when none are set
+func GetGlobalDesktopNotificationsWithOptions(opts ConfigOptions) (*DesktopNotificationsConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return config.DesktopNotifications, nil
+}
+
+// UpdateGlobalDesktopNotificationsWithOptions saves the desktop notification
+// settings (nil clears them)
+func UpdateGlobalDesktopNotificationsWithOptions(opts ConfigOptions, desktop *DesktopNotificationsConfig) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	config.DesktopNotifications = desktop
+	return config.SaveWithOptions(opts)
+}
+
+// GetGlobalEmailWithOptions returns the summary email settings, or nil when
+// none are set
+func GetGlobalEmailWithOptions(opts ConfigOptions) (*EmailConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return config.Email, nil
+}
+
+// UpdateGlobalEmailWithOptions saves the summary email settings (nil clears
+// them)
+func UpdateGlobalEmailWithOptions(opts ConfigOptions, email *EmailConfig) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	config.Email = email
+	return config.SaveWithOptions(opts)
+}
+
 // UpdateGlobalIterationDelay updates the iteration delay in global config
 func UpdateGlobalIterationDelay(delayMinutes, fuzz int) error {
 	return UpdateGlobalIterationDelayWithOptions(DefaultConfigOptions(), delayMinutes, fuzz)
@@ -679,6 +1362,16 @@ func UpdateGlobalOverloadRetryMinutesWithOptions(opts ConfigOptions, minutes int
 	return config.SaveWithOptions(opts)
 }
 
+// GetGlobalLinearConfigWithOptions returns the Linear settings from global
+// config, nil when there are none
+func GetGlobalLinearConfigWithOptions(opts ConfigOptions) (*LinearConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return config.Linear, nil
+}
+
 // GetGlobalVCS returns the VCS setting from global config
 func GetGlobalVCS() (string, error) {
 	return GetGlobalVCSWithOptions(DefaultConfigOptions())
@@ -761,15 +1454,26 @@ func ClearProjectVCS(projectDir string) error {
 }
 
 // SetAgentProvider sets the global agent provider preference.
-// Valid values are "claude", "opencode", or "" (empty for default).
+// Valid values are "claude", "opencode", "goose", "amp", a name from custom_providers,
+// or "" (empty for default).
 func (c *Config) SetAgentProvider(provider string) error {
-	if provider != "" && provider != "claude" && provider != "opencode" {
-		return fmt.Errorf("invalid agent provider: %s (must be 'claude' or 'opencode')", provider)
+	if !ValidateAgentProvider(provider, c.CustomProviderNames()...) {
+		return fmt.Errorf("invalid agent provider: %s (must be 'claude', 'opencode', 'goose', 'amp' or a custom provider)", provider)
 	}
 	c.AgentProvider = provider
 	return nil
 }
 
+// CustomProviderNames returns the names of configured custom providers, sorted.
+func (c *Config) CustomProviderNames() []string {
+	names := make([]string, 0, len(c.CustomProviders))
+	for name := range c.CustomProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GetAgentProvider returns the global agent provider preference.
 func (c *Config) GetAgentProvider() string {
 	return c.AgentProvider
@@ -820,6 +1524,15 @@ func GetGlobalAgentProviderWithOptions(opts ConfigOptions) (string, error) {
 	return config.GetAgentProvider(), nil
 }
 
+// GetGlobalCustomProvidersWithOptions returns the custom provider definitions from global config
+func GetGlobalCustomProvidersWithOptions(opts ConfigOptions) (map[string]CustomProviderConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return config.CustomProviders, nil
+}
+
 // UpdateGlobalAgentProvider updates the agent provider in global config
 func UpdateGlobalAgentProvider(provider string) error {
 	return UpdateGlobalAgentProviderWithOptions(DefaultConfigOptions(), provider)
@@ -869,9 +1582,10 @@ func GetGlobalModelOverridesWithOptions(opts ConfigOptions) (map[string]string,
 }
 
 // SetAgentProvider for ProjectConfig sets the project agent provider preference.
-func (c *ProjectConfig) SetAgentProvider(provider string) error {
-	if provider != "" && provider != "claude" && provider != "opencode" {
-		return fmt.Errorf("invalid agent provider: %s (must be 'claude' or 'opencode')", provider)
+// Custom providers are defined in global config, so their names are passed in.
+func (c *ProjectConfig) SetAgentProvider(provider string, customProviders ...string) error {
+	if !ValidateAgentProvider(provider, customProviders...) {
+		return fmt.Errorf("invalid agent provider: %s (must be 'claude', 'opencode', 'goose', 'amp' or a custom provider)", provider)
 	}
 	c.AgentProvider = provider
 	return nil
@@ -910,13 +1624,13 @@ func GetProjectAgentProvider(projectDir string) (string, error) {
 }
 
 // UpdateProjectAgentProvider updates the agent provider in project config
-func UpdateProjectAgentProvider(projectDir, provider string) error {
+func UpdateProjectAgentProvider(projectDir, provider string, customProviders ...string) error {
 	config, err := LoadProjectConfig(projectDir)
 	if err != nil {
 		return err
 	}
 
-	if err := config.SetAgentProvider(provider); err != nil {
+	if err := config.SetAgentProvider(provider, customProviders...); err != nil {
 		return err
 	}
 	return SaveProjectConfig(projectDir, config)
@@ -941,6 +1655,195 @@ func GetProjectModelOverrides(projectDir string) (map[string]string, error) {
 	return config.GetModelOverrides(), nil
 }
 
+// GetProjectProviderBinaries returns the provider binary paths from project config
+func GetProjectProviderBinaries(projectDir string) (map[string]string, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.ProviderBinaries, nil
+}
+
+// GetProjectDiffLimit returns the diff size guardrail from project config (nil if unset)
+func GetProjectDiffLimit(projectDir string) (*DiffLimitConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.DiffLimit, nil
+}
+
+// GetProjectCommitGates returns the commit gate settings from project config (nil if unset)
+func GetProjectCommitGates(projectDir string) (*CommitGatesConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.CommitGates, nil
+}
+
+// GetProjectGates returns the build/lint/typecheck gates from project config, in run order
+func GetProjectGates(projectDir string) ([]GateConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Gates, nil
+}
+
+// GetProjectValidation returns the validation config from project config, or nil if unset
+func GetProjectValidation(projectDir string) (*ValidationConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Validation, nil
+}
+
+// GetProjectVerifyCompletion returns whether completed balls are verified by a
+// second model, and the model to use ("small" unless verify_model is set)
+func GetProjectVerifyCompletion(projectDir string) (bool, string, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return false, "", err
+	}
+	model := config.VerifyModel
+	if model == "" {
+		model = "small"
+	}
+	return config.VerifyCompletion, model, nil
+}
+
+// GetProjectReview returns whether agent runs end with a review iteration
+func GetProjectReview(projectDir string) (bool, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return false, err
+	}
+	return config.Review, nil
+}
+
+// GetProjectToolchain returns the toolchain briefing settings from project config (nil if unset)
+func GetProjectToolchain(projectDir string) (*ToolchainConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Toolchain, nil
+}
+
+// GetProjectRetry returns the retry policy overrides from project config (nil if unset)
+func GetProjectRetry(projectDir string) (*RetryConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Retry, nil
+}
+
+// GetProjectAgentBudget returns the default token and cost budgets for agent
+// runs from project config (0 = unlimited)
+func GetProjectAgentBudget(projectDir string) (int, float64, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.MaxTokens, config.MaxCostUSD, nil
+}
+
+// GetProjectDuplicateWork returns the duplicate-work check settings from project config (nil if unset)
+func GetProjectDuplicateWork(projectDir string) (*DuplicateWorkConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.DuplicateWork, nil
+}
+
+// GetProjectNoOp returns the no-op iteration settings from project config (nil if unset)
+func GetProjectNoOp(projectDir string) (*NoOpConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.NoOp, nil
+}
+
+// GetProjectOutputProcessors returns the agent output post-processors per
+// provider name from project config (nil if unset)
+func GetProjectOutputProcessors(projectDir string) (map[string][]string, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.OutputProcessors, nil
+}
+
+// GetProjectAging returns the ball aging settings from project config (nil if unset)
+func GetProjectAging(projectDir string) (*AgingConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Aging, nil
+}
+
+// GetProjectMaxBallAttempts returns how many agent iterations a ball may take
+// before it is blocked, from project config (0 = unlimited)
+func GetProjectMaxBallAttempts(projectDir string) (int, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return 0, err
+	}
+	return config.MaxBallAttempts, nil
+}
+
+// GetProjectEscalateAfterFailures returns how many failed agent iterations at
+// a model move a ball to the next larger one (0 = never)
+func GetProjectEscalateAfterFailures(projectDir string) (int, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return 0, err
+	}
+	return config.EscalateAfterFailures, nil
+}
+
+// GetProjectVault returns the Markdown vault settings from project config (nil if unset)
+func GetProjectVault(projectDir string) (*VaultConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Vault, nil
+}
+
+// GetProjectWebhooks returns the run lifecycle webhooks from project config
+func GetProjectWebhooks(projectDir string) ([]WebhookConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Webhooks, nil
+}
+
+// GetProjectSandbox returns the agent sandbox settings from project config (nil if unset)
+func GetProjectSandbox(projectDir string) (*SandboxConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Sandbox, nil
+}
+
+// GetProjectFormatters returns the formatter commands from project config
+func GetProjectFormatters(projectDir string) ([]FormatterConfig, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Formatters, nil
+}
+
 // MergeModelOverrides merges project overrides with global overrides.
 // Project overrides take precedence over global.
 func MergeModelOverrides(global, project map[string]string) map[string]string {
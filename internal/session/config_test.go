@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestProjectConfig_SetDefaultAcceptanceCriteria tests setting repo-level ACs
@@ -272,3 +273,265 @@ func TestProjectConfig_RunAliases_Persistence(t *testing.T) {
 		t.Errorf("expected 'go test -v ./...', got %q", alias)
 	}
 }
+
+func TestDiffLimitConfig_Exceeded(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *DiffLimitConfig
+		files int
+		lines int
+		want  bool
+	}{
+		{"nil limit", nil, 100, 10000, false},
+		{"zero means unlimited", &DiffLimitConfig{}, 100, 10000, false},
+		{"under both", &DiffLimitConfig{MaxFiles: 5, MaxLines: 100}, 5, 100, false},
+		{"files over", &DiffLimitConfig{MaxFiles: 5, MaxLines: 100}, 6, 10, true},
+		{"lines over", &DiffLimitConfig{MaxFiles: 5, MaxLines: 100}, 1, 101, true},
+		{"lines only", &DiffLimitConfig{MaxLines: 100}, 50, 99, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.Exceeded(tt.files, tt.lines); got != tt.want {
+				t.Errorf("Exceeded(%d, %d) = %v, want %v", tt.files, tt.lines, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationConfig_SelectionCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		validation *ValidationConfig
+		want       string
+	}{
+		{"go default", &ValidationConfig{Command: "make test", Affected: AffectedGo}, "go test"},
+		{"jest default", &ValidationConfig{Command: "npm test", Affected: AffectedJest}, "npx jest --findRelatedTests --passWithNoTests"},
+		{"explicit command", &ValidationConfig{Command: "make test", Affected: AffectedGo, AffectedCommand: "go test -race"}, "go test -race"},
+		{"no selection", &ValidationConfig{Command: "make test"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.validation.SelectionCommand(); got != tt.want {
+				t.Errorf("SelectionCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var unset *ValidationConfig
+	if unset.Enabled() {
+		t.Error("expected nil validation config to be disabled")
+	}
+}
+
+func TestGetProjectDiffLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	limit, err := GetProjectDiffLimit(tmpDir)
+	if err != nil {
+		t.Fatalf("GetProjectDiffLimit failed: %v", err)
+	}
+	if limit != nil {
+		t.Errorf("Expected no diff limit by default, got %+v", limit)
+	}
+
+	config := DefaultProjectConfig()
+	config.DiffLimit = &DiffLimitConfig{MaxFiles: 10, MaxLines: 500}
+	if err := SaveProjectConfig(tmpDir, config); err != nil {
+		t.Fatalf("SaveProjectConfig failed: %v", err)
+	}
+
+	limit, err = GetProjectDiffLimit(tmpDir)
+	if err != nil {
+		t.Fatalf("GetProjectDiffLimit failed: %v", err)
+	}
+	if limit == nil || limit.MaxFiles != 10 || limit.MaxLines != 500 {
+		t.Errorf("Expected diff limit {10 500}, got %+v", limit)
+	}
+}
+
+func TestFormatterConfig_Matches(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter FormatterConfig
+		path      string
+		want      bool
+	}{
+		{"no patterns matches all", FormatterConfig{Command: "prettier -w"}, "web/app.ts", true},
+		{"base name match", FormatterConfig{Patterns: []string{"*.go"}}, "internal/cli/agent.go", true},
+		{"base name miss", FormatterConfig{Patterns: []string{"*.go"}}, "README.md", false},
+		{"full path match", FormatterConfig{Patterns: []string{"web/*.ts"}}, "web/app.ts", true},
+		{"any of several", FormatterConfig{Patterns: []string{"*.py", "*.pyi"}}, "pkg/types.pyi", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.Matches(tt.path); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_CustomProviders(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("LoadConfigWithOptions failed: %v", err)
+	}
+	if err := config.SetAgentProvider("aider"); err == nil {
+		t.Error("Expected error for undefined custom provider")
+	}
+
+	config.CustomProviders = map[string]CustomProviderConfig{
+		"aider": {Binary: "aider", Args: []string{"--yes"}, PromptVia: "arg", ModelFlag: "--model"},
+	}
+	if err := config.SetAgentProvider("aider"); err != nil {
+		t.Fatalf("SetAgentProvider failed for custom provider: %v", err)
+	}
+	if err := config.SaveWithOptions(opts); err != nil {
+		t.Fatalf("SaveWithOptions failed: %v", err)
+	}
+
+	custom, err := GetGlobalCustomProvidersWithOptions(opts)
+	if err != nil {
+		t.Fatalf("GetGlobalCustomProvidersWithOptions failed: %v", err)
+	}
+	aider, ok := custom["aider"]
+	if !ok {
+		t.Fatal("Expected custom provider 'aider' to round-trip")
+	}
+	if aider.Binary != "aider" || aider.PromptVia != "arg" || aider.ModelFlag != "--model" || len(aider.Args) != 1 {
+		t.Errorf("Unexpected custom provider config: %+v", aider)
+	}
+
+	project := DefaultProjectConfig()
+	if err := project.SetAgentProvider("aider"); err == nil {
+		t.Error("Expected project config to reject unknown provider without custom names")
+	}
+	if err := project.SetAgentProvider("aider", "aider"); err != nil {
+		t.Errorf("Expected project config to accept custom provider: %v", err)
+	}
+}
+
+func TestSandboxConfig_Applies(t *testing.T) {
+	var unset *SandboxConfig
+	if unset.Applies(true) {
+		t.Error("Expected nil sandbox config not to apply")
+	}
+	if (&SandboxConfig{}).Applies(true) {
+		t.Error("Expected sandbox without an image not to apply")
+	}
+
+	trustOnly := &SandboxConfig{Image: "img"}
+	if !trustOnly.Applies(true) || trustOnly.Applies(false) {
+		t.Error("Expected sandbox to apply to --trust runs only by default")
+	}
+	always := &SandboxConfig{Image: "img", Always: true}
+	if !always.Applies(false) {
+		t.Error("Expected always sandbox to apply to every run")
+	}
+}
+
+func TestConfig_IsIgnoredPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	config := &Config{IgnorePaths: []string{"node_modules", "*.bak", "/srv/mirror/*", "~/backups"}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/work/app", false},
+		{"/work/app/node_modules/left-pad", true},
+		{"/work/app.bak", true},
+		{"/work/app.bak/nested", true},
+		{"/work/backup", false},
+		{"/srv/mirror/repo", true},
+		{"/srv/mirror/repo/sub", true},
+		{"/srv/mirror", false},
+		{filepath.Join(home, "backups", "old-app"), true},
+		{filepath.Join(home, "projects"), false},
+	}
+	for _, tt := range tests {
+		if got := config.IsIgnoredPath(tt.path); got != tt.want {
+			t.Errorf("IsIgnoredPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if (&Config{}).IsIgnoredPath("/work/app/node_modules/x") {
+		t.Error("expected nothing ignored without patterns")
+	}
+}
+
+func TestDiscoverProjects_SkipsIgnoredPaths(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "app")
+	vendored := filepath.Join(app, "node_modules", "dep")
+	for _, dir := range []string{app, vendored} {
+		if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &Config{SearchPaths: []string{app, vendored}, IgnorePaths: []string{"node_modules"}}
+	projects, err := DiscoverProjects(config)
+	if err != nil {
+		t.Fatalf("DiscoverProjects failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0] != app {
+		t.Errorf("expected only %s, got %v", app, projects)
+	}
+}
+
+func TestEnsureProjectInSearchPaths_SkipsIgnoredPaths(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv(EnvConfigHome, configHome)
+
+	config := DefaultConfig()
+	config.IgnorePaths = []string{"vendor"}
+	if err := config.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	root := t.TempDir()
+	if err := EnsureProjectInSearchPaths(filepath.Join(root, "vendor", "lib")); err != nil {
+		t.Fatalf("EnsureProjectInSearchPaths failed: %v", err)
+	}
+	if err := EnsureProjectInSearchPaths(filepath.Join(root, "app")); err != nil {
+		t.Fatalf("EnsureProjectInSearchPaths failed: %v", err)
+	}
+
+	loaded, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(loaded.SearchPaths) != 1 || loaded.SearchPaths[0] != filepath.Join(root, "app") {
+		t.Errorf("expected only the app in search paths, got %v", loaded.SearchPaths)
+	}
+	if len(loaded.IgnorePaths) != 1 || loaded.IgnorePaths[0] != "vendor" {
+		t.Errorf("expected ignore_paths to round-trip, got %v", loaded.IgnorePaths)
+	}
+}
+
+func TestSupervisorConfig_GetCrashBackoff(t *testing.T) {
+	tests := []struct {
+		config SupervisorConfig
+		n      int
+		want   time.Duration
+	}{
+		{SupervisorConfig{}, 1, 30 * time.Second},
+		{SupervisorConfig{}, 3, 2 * time.Minute},
+		{SupervisorConfig{CrashBackoffSeconds: 10}, 2, 20 * time.Second},
+		{SupervisorConfig{CrashBackoffSeconds: 600}, 5, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := tt.config.GetCrashBackoff(tt.n); got != tt.want {
+			t.Errorf("GetCrashBackoff(%d) with %+v = %v, want %v", tt.n, tt.config, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// daemonAPITokenFile holds the token of a daemon API listening on
+// daemon_api_listen, next to the global config
+const daemonAPITokenFile = "daemon-api-token"
+
+// DaemonAPITokenPath returns where the daemon API token is stored
+func DaemonAPITokenPath(opts ConfigOptions) (string, error) {
+	configHome := opts.ConfigHome
+	if configHome == "" {
+		var err error
+		if configHome, err = os.UserHomeDir(); err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+	}
+	return filepath.Join(configHome, opts.JuggleDirName, daemonAPITokenFile), nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // DiscoverProjects finds all directories containing .juggle folders
@@ -12,6 +13,10 @@ func DiscoverProjects(config *Config) ([]string, error) {
 	projects := make([]string, 0)
 
 	for _, path := range config.SearchPaths {
+		if config.IsIgnoredPath(path) {
+			continue
+		}
+
 		// Check if path exists and has .juggle directory
 		jugglePath := filepath.Join(path, ".juggle")
 		if _, err := os.Stat(jugglePath); err == nil {
@@ -22,6 +27,59 @@ func DiscoverProjects(config *Config) ([]string, error) {
 	return projects, nil
 }
 
+// IsIgnoredPath reports whether a directory matches one of the config's ignore
+// patterns. A pattern without a slash, like "node_modules" or "*.bak", matches
+// any component of the path. A pattern with a slash is matched against the
+// whole path and each of its parents, so "~/backups/*" also covers everything
+// below a backup. Patterns use filepath.Match syntax and may start with "~/".
+func (c *Config) IsIgnoredPath(path string) bool {
+	if len(c.IgnorePaths) == 0 {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, pattern := range c.IgnorePaths {
+		if matchIgnorePattern(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIgnorePattern matches one ignore pattern against a clean path
+func matchIgnorePattern(pattern, path string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return false
+	}
+	if strings.HasPrefix(pattern, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return false
+		}
+		pattern = filepath.Join(home, pattern[2:])
+	}
+	pattern = filepath.FromSlash(pattern)
+
+	if !strings.ContainsRune(pattern, filepath.Separator) {
+		for _, part := range strings.Split(path, string(filepath.Separator)) {
+			if ok, _ := filepath.Match(pattern, part); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	pattern = filepath.Clean(pattern)
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if ok, _ := filepath.Match(pattern, dir); ok {
+			return true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return false
+		}
+	}
+}
+
 // LoadAllSessions loads sessions from all discovered projects
 func LoadAllSessions(projectPaths []string) ([]*JuggleSession, error) {
 	allSessions := make([]*JuggleSession, 0)
@@ -68,21 +126,34 @@ func LoadAllBalls(projectPaths []string) ([]*Ball, error) {
 	return allBalls, nil
 }
 
-// LoadInProgressBalls loads all in_progress balls from all projects
-func LoadInProgressBalls(projectPaths []string) ([]*Ball, error) {
-	allBalls, err := LoadAllBalls(projectPaths)
-	if err != nil {
-		return nil, err
-	}
+// LoadAllBallsFiltered loads the balls the query selects from all projects,
+// filtering each project's balls as they are read. Offset and Limit apply
+// to each project.
+func LoadAllBallsFiltered(projectPaths []string, query BallQuery) ([]*Ball, error) {
+	allBalls := make([]*Ball, 0)
 
-	inProgress := make([]*Ball, 0)
-	for _, ball := range allBalls {
-		if ball.State == StateInProgress {
-			inProgress = append(inProgress, ball)
+	for _, projectPath := range projectPaths {
+		store, err := NewStore(projectPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create store for %s: %v\n", projectPath, err)
+			continue
+		}
+
+		page, err := store.LoadBallsFiltered(query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load balls from %s: %v\n", projectPath, err)
+			continue
 		}
+
+		allBalls = append(allBalls, page.Balls...)
 	}
 
-	return inProgress, nil
+	return allBalls, nil
+}
+
+// LoadInProgressBalls loads all in_progress balls from all projects
+func LoadInProgressBalls(projectPaths []string) ([]*Ball, error) {
+	return LoadAllBallsFiltered(projectPaths, BallQuery{States: []BallState{StateInProgress}})
 }
 
 // LoadJugglingBalls loads all balls currently being juggled from all projects
@@ -93,19 +164,7 @@ func LoadJugglingBalls(projectPaths []string) ([]*Ball, error) {
 
 // LoadPendingBalls loads all pending balls from all projects
 func LoadPendingBalls(projectPaths []string) ([]*Ball, error) {
-	allBalls, err := LoadAllBalls(projectPaths)
-	if err != nil {
-		return nil, err
-	}
-
-	pending := make([]*Ball, 0)
-	for _, ball := range allBalls {
-		if ball.State == StatePending {
-			pending = append(pending, ball)
-		}
-	}
-
-	return pending, nil
+	return LoadAllBallsFiltered(projectPaths, BallQuery{States: []BallState{StatePending}})
 }
 
 // LoadReadyBalls loads all ready balls from all projects
@@ -119,22 +178,7 @@ func LoadReadyBalls(projectPaths []string) ([]*Ball, error) {
 // are considered to belong to that session. Balls can belong to multiple
 // sessions via multiple tags.
 func LoadBallsBySession(projectPaths []string, sessionID string) ([]*Ball, error) {
-	allBalls, err := LoadAllBalls(projectPaths)
-	if err != nil {
-		return nil, err
-	}
-
-	sessionBalls := make([]*Ball, 0)
-	for _, ball := range allBalls {
-		for _, tag := range ball.Tags {
-			if tag == sessionID {
-				sessionBalls = append(sessionBalls, ball)
-				break
-			}
-		}
-	}
-
-	return sessionBalls, nil
+	return LoadAllBallsFiltered(projectPaths, BallQuery{Tags: []string{sessionID}})
 }
 
 // ProjectInfo holds information about a project and its balls
@@ -0,0 +1,135 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// docsBriefingMaxPerDoc caps each doc's share of the briefing
+	docsBriefingMaxPerDoc = 2000
+
+	// docsBriefingMaxParagraph caps the first paragraph kept under each heading
+	docsBriefingMaxParagraph = 300
+)
+
+// DocsBriefing is the summary of a session's context docs included in the
+// agent prompt, regenerated when the docs change
+type DocsBriefing struct {
+	Summary   string    `json:"summary"`
+	Digest    string    `json:"digest"` // Hash of the docs' contents the summary was built from
+	Refreshed time.Time `json:"refreshed"`
+}
+
+// SetContextDocs sets the project-relative docs the session's briefing is built from
+func (s *JuggleSession) SetContextDocs(docs []string) {
+	s.ContextDocs = nonEmptyCommands(docs)
+	if len(s.ContextDocs) == 0 {
+		s.DocsBriefing = nil
+	}
+	s.UpdatedAt = time.Now()
+}
+
+// RefreshDocsBriefing rebuilds the session's docs briefing if its context docs
+// changed since the last one, and saves it. Docs are read relative to the
+// project. Returns true if the briefing changed.
+func (s *SessionStore) RefreshDocsBriefing(id string) (bool, error) {
+	sess, err := s.LoadSession(id)
+	if err != nil {
+		return false, err
+	}
+	if len(sess.ContextDocs) == 0 {
+		return false, nil
+	}
+
+	summary, digest := SummarizeDocs(s.projectDir, sess.ContextDocs)
+	if sess.DocsBriefing != nil && sess.DocsBriefing.Digest == digest {
+		return false, nil
+	}
+	sess.DocsBriefing = &DocsBriefing{Summary: summary, Digest: digest, Refreshed: time.Now()}
+	return true, s.saveSession(sess)
+}
+
+// SummarizeDocs builds a briefing from the given project-relative docs: each
+// doc's headings with the first paragraph under them, capped per doc. Also
+// returns a digest of the docs' contents, so callers can tell when they
+// changed. Missing docs are noted in the briefing.
+func SummarizeDocs(projectDir string, docs []string) (summary, digest string) {
+	hash := sha256.New()
+	var buf strings.Builder
+	for _, doc := range docs {
+		data, err := os.ReadFile(filepath.Join(projectDir, doc))
+		fmt.Fprintf(hash, "%s\x00%d\x00", doc, len(data))
+		hash.Write(data)
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("## " + doc + "\n")
+		if err != nil {
+			buf.WriteString("(not found)\n")
+			continue
+		}
+		buf.WriteString(outlineDoc(string(data)))
+	}
+	return buf.String(), hex.EncodeToString(hash.Sum(nil))
+}
+
+// outlineDoc keeps a Markdown doc's headings and the first paragraph of text
+// under each (or at the top), skipping code blocks, images and HTML
+func outlineDoc(text string) string {
+	var buf strings.Builder
+	var paragraph []string
+	wantParagraph := true
+	inFence := false
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			buf.WriteString(truncateRunes(strings.Join(paragraph, " "), docsBriefingMaxParagraph) + "\n")
+			paragraph = nil
+			wantParagraph = false
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			inFence = !inFence
+		case inFence:
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			buf.WriteString(trimmed + "\n")
+			wantParagraph = true
+		case trimmed == "":
+			flush()
+		case !wantParagraph || strings.HasPrefix(trimmed, "<") || strings.HasPrefix(trimmed, "![") || strings.HasPrefix(trimmed, "[!["):
+			continue
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	outline := buf.String()
+	if len([]rune(outline)) > docsBriefingMaxPerDoc {
+		outline = truncateRunes(outline, docsBriefingMaxPerDoc) + "\n"
+	}
+	return outline
+}
+
+// truncateRunes shortens s to at most max runes, ending in "..." when cut
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
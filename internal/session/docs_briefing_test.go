@@ -0,0 +1,90 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeDocs(t *testing.T) {
+	dir := t.TempDir()
+	readme := "# MyApp\n\n[![build](badge.svg)](ci)\n\nMyApp syncs notes\nbetween devices.\n\nSecond paragraph is dropped.\n\n```sh\n# not a heading\nmake install\n```\n\n## Layout\n\nThe server lives in cmd/server.\n"
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0644); err != nil {
+		t.Fatalf("failed to write doc: %v", err)
+	}
+
+	summary, digest := SummarizeDocs(dir, []string{"README.md", "ARCHITECTURE.md"})
+	want := "## README.md\n# MyApp\nMyApp syncs notes between devices.\n## Layout\nThe server lives in cmd/server.\n\n## ARCHITECTURE.md\n(not found)\n"
+	if summary != want {
+		t.Errorf("unexpected summary:\n%s\nwant:\n%s", summary, want)
+	}
+
+	if _, same := SummarizeDocs(dir, []string{"README.md", "ARCHITECTURE.md"}); same != digest {
+		t.Error("Expected the same digest for unchanged docs")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ARCHITECTURE.md"), []byte("# Architecture\n"), 0644); err != nil {
+		t.Fatalf("failed to write doc: %v", err)
+	}
+	if _, changed := SummarizeDocs(dir, []string{"README.md", "ARCHITECTURE.md"}); changed == digest {
+		t.Error("Expected a new digest once a doc changed")
+	}
+}
+
+func TestSummarizeDocs_CapsLongDocs(t *testing.T) {
+	dir := t.TempDir()
+	var doc strings.Builder
+	for i := 0; i < 200; i++ {
+		doc.WriteString("## Section\n\nSome text about this section.\n\n")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(doc.String()), 0644); err != nil {
+		t.Fatalf("failed to write doc: %v", err)
+	}
+
+	summary, _ := SummarizeDocs(dir, []string{"README.md"})
+	if n := len([]rune(summary)); n > docsBriefingMaxPerDoc+len("## README.md\n")+1 {
+		t.Errorf("Expected the briefing capped near %d characters, got %d", docsBriefingMaxPerDoc, n)
+	}
+}
+
+func TestRefreshDocsBriefing(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewSessionStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := store.CreateSession("docs", "Docs session"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if changed, err := store.RefreshDocsBriefing("docs"); err != nil || changed {
+		t.Fatalf("Expected no briefing without context docs, got changed=%v err=%v", changed, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# MyApp\n\nSyncs notes.\n"), 0644); err != nil {
+		t.Fatalf("failed to write doc: %v", err)
+	}
+	if err := store.UpdateSessionContextDocs("docs", []string{"README.md", ""}); err != nil {
+		t.Fatalf("failed to set context docs: %v", err)
+	}
+	if changed, err := store.RefreshDocsBriefing("docs"); err != nil || !changed {
+		t.Fatalf("Expected a new briefing, got changed=%v err=%v", changed, err)
+	}
+	if changed, _ := store.RefreshDocsBriefing("docs"); changed {
+		t.Error("Expected no refresh while the docs are unchanged")
+	}
+
+	sess, err := store.LoadSession("docs")
+	if err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if len(sess.ContextDocs) != 1 || sess.DocsBriefing == nil || !strings.Contains(sess.DocsBriefing.Summary, "Syncs notes.") {
+		t.Errorf("Expected a saved briefing for README.md, got docs %v briefing %+v", sess.ContextDocs, sess.DocsBriefing)
+	}
+
+	if err := store.UpdateSessionContextDocs("docs", []string{""}); err != nil {
+		t.Fatalf("failed to clear context docs: %v", err)
+	}
+	if sess, _ := store.LoadSession("docs"); len(sess.ContextDocs) != 0 || sess.DocsBriefing != nil {
+		t.Errorf("Expected clearing the docs to drop the briefing, got %v %+v", sess.ContextDocs, sess.DocsBriefing)
+	}
+}
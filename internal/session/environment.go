@@ -0,0 +1,67 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envKeyPattern matches portable environment variable names
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseEnvAssignment splits a KEY=VALUE argument.
+// An empty value ("KEY=") is allowed and means unset.
+func ParseEnvAssignment(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid env assignment %q (expected KEY=VALUE)", s)
+	}
+	if !envKeyPattern.MatchString(key) {
+		return "", "", fmt.Errorf("invalid env variable name %q", key)
+	}
+	return key, value, nil
+}
+
+// EnvList renders env vars as KEY=VALUE entries sorted by key
+func EnvList(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, key+"="+env[key])
+	}
+	return list
+}
+
+// setEnvVar sets key in env, or removes it when value is empty.
+// Returns nil instead of an empty map so the field is omitted from JSON.
+func setEnvVar(env map[string]string, key, value string) map[string]string {
+	if value == "" {
+		delete(env, key)
+		if len(env) == 0 {
+			return nil
+		}
+		return env
+	}
+	if env == nil {
+		env = make(map[string]string)
+	}
+	env[key] = value
+	return env
+}
+
+// nonEmptyCommands drops blank entries, so a single "" clears a command list
+func nonEmptyCommands(commands []string) []string {
+	var result []string
+	for _, c := range commands {
+		if strings.TrimSpace(c) != "" {
+			result = append(result, c)
+		}
+	}
+	return result
+}
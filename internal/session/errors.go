@@ -4,7 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"syscall"
+
+	"github.com/ohare93/juggle/internal/process"
 )
 
 // Standard error types for the session package.
@@ -22,8 +23,58 @@ var (
 
 	// ErrBallLocked is returned when a ball is already locked by another process.
 	ErrBallLocked = errors.New("ball locked")
+
+	// ErrSessionNotFound is returned when a session cannot be found by ID.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrAmbiguousID is returned when a ball ID prefix matches more than one ball.
+	ErrAmbiguousID = errors.New("ambiguous ID")
+
+	// ErrConfigInvalid is returned when a config file cannot be parsed.
+	ErrConfigInvalid = errors.New("invalid config")
 )
 
+// SessionNotFoundError provides the ID of a session that could not be found.
+type SessionNotFoundError struct {
+	ID string // The session ID that was not found
+}
+
+func (e *SessionNotFoundError) Error() string {
+	return fmt.Sprintf("session %s not found", e.ID)
+}
+
+func (e *SessionNotFoundError) Is(target error) bool {
+	return target == ErrSessionNotFound
+}
+
+// NewSessionNotFoundError creates a new SessionNotFoundError.
+func NewSessionNotFoundError(id string) *SessionNotFoundError {
+	return &SessionNotFoundError{ID: id}
+}
+
+// ConfigInvalidError provides the path and parse error of an invalid config file.
+type ConfigInvalidError struct {
+	Path string // The config file that could not be parsed
+	Err  error  // The underlying parse error
+}
+
+func (e *ConfigInvalidError) Error() string {
+	return fmt.Sprintf("invalid config %s: %v", e.Path, e.Err)
+}
+
+func (e *ConfigInvalidError) Is(target error) bool {
+	return target == ErrConfigInvalid
+}
+
+func (e *ConfigInvalidError) Unwrap() error {
+	return e.Err
+}
+
+// NewConfigInvalidError creates a new ConfigInvalidError.
+func NewConfigInvalidError(path string, err error) *ConfigInvalidError {
+	return &ConfigInvalidError{Path: path, Err: err}
+}
+
 // BallNotFoundError provides detailed information about a ball lookup failure.
 type BallNotFoundError struct {
 	ID       string // The ball ID that was not found
@@ -140,7 +191,7 @@ func NewSessionLockedError(sessionID string, info *LockInfo) *SessionLockedError
 		// Check if the process is still running (local host only)
 		currentHostname, _ := os.Hostname()
 		if info.Hostname == currentHostname && info.PID > 0 {
-			running := isProcessRunning(info.PID)
+			running := process.RunningSince(info.PID, info.StartedAt)
 			err.ProcessRunning = &running
 		}
 	}
@@ -191,26 +242,13 @@ func NewBallLockedError(ballID string, info *LockInfo) *BallLockedError {
 		// Check if the process is still running (local host only)
 		currentHostname, _ := os.Hostname()
 		if info.Hostname == currentHostname && info.PID > 0 {
-			running := isProcessRunning(info.PID)
+			running := process.RunningSince(info.PID, info.StartedAt)
 			err.ProcessRunning = &running
 		}
 	}
 	return err
 }
 
-// isProcessRunning checks if a process with the given PID is still running.
-// This works by sending signal 0 to the process - if the process exists,
-// the call succeeds; if not, it returns an error.
-func isProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-	// Signal 0 doesn't actually send a signal, but checks if the process exists
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
-}
-
 // AmbiguousIDError is returned when a ball ID prefix matches multiple balls.
 type AmbiguousIDError struct {
 	Prefix     string   // The ambiguous prefix
@@ -225,6 +263,10 @@ func (e *AmbiguousIDError) Error() string {
 	return fmt.Sprintf("ambiguous ID '%s' matches %d balls", e.Prefix, e.MatchCount)
 }
 
+func (e *AmbiguousIDError) Is(target error) bool {
+	return target == ErrAmbiguousID
+}
+
 // NewAmbiguousIDError creates a new AmbiguousIDError.
 func NewAmbiguousIDError(prefix string, matchingIDs []string) *AmbiguousIDError {
 	return &AmbiguousIDError{
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"time"
+)
+
+// BallEstimate is a cheap model's guess at how hard a ball is, written by
+// juggle agent estimate. Agent runs use it to pick a model for balls that
+// don't set one themselves.
+type BallEstimate struct {
+	ModelSize   ModelSize `json:"model_size,omitempty"` // Smallest model size expected to handle the ball
+	Iterations  int       `json:"iterations,omitempty"` // Agent iterations the ball is expected to take
+	Risks       string    `json:"risks,omitempty"`      // What could make the ball harder than it looks
+	Model       string    `json:"model,omitempty"`      // Model that made the estimate
+	EstimatedAt time.Time `json:"estimated_at"`
+}
+
+// SetEstimate records an estimate on the ball, replacing any earlier one
+func (b *Ball) SetEstimate(estimate *BallEstimate) {
+	b.Estimate = estimate
+	b.UpdateActivity()
+}
+
+// EstimatedIterations returns how many iterations the ball is expected to
+// take, 1 when it has no estimate
+func (b *Ball) EstimatedIterations() int {
+	if b.Estimate == nil || b.Estimate.Iterations < 1 {
+		return 1
+	}
+	return b.Estimate.Iterations
+}
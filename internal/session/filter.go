@@ -0,0 +1,157 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// filterFields are the ball fields a BallFilter can test
+var filterFields = map[string]func(*Ball) []string{
+	"id":       func(b *Ball) []string { return []string{b.ID, b.ShortID()} },
+	"title":    func(b *Ball) []string { return []string{b.Title} },
+	"context":  func(b *Ball) []string { return []string{b.Context} },
+	"state":    func(b *Ball) []string { return []string{string(b.State)} },
+	"priority": func(b *Ball) []string { return []string{string(b.Priority)} },
+	"kind": func(b *Ball) []string {
+		if b.Kind == BallKindBlank {
+			return []string{string(BallKindCode)}
+		}
+		return []string{string(b.Kind)}
+	},
+	"blocked_reason": func(b *Ball) []string { return []string{b.BlockedReason} },
+	"tag":            func(b *Ball) []string { return b.Tags },
+	"session":        func(b *Ball) []string { return b.Tags },
+}
+
+// filterCondition is one field test in a BallFilter
+type filterCondition struct {
+	field  string
+	op     string // "=", "!=", "~" or "!~"
+	value  string
+	values func(*Ball) []string
+}
+
+// BallFilter selects balls with space-separated conditions that must all hold,
+// e.g. `blocked_reason~"missing API key" priority=high`. Operators are = and !=
+// for equality and ~ and !~ for substring matches, all case-insensitive. Values
+// with spaces are double-quoted. For tag (alias session), = and ~ match when any
+// tag matches and != and !~ when none does.
+type BallFilter struct {
+	conditions []filterCondition
+}
+
+// ParseBallFilter parses a filter expression. An empty expression matches every ball.
+func ParseBallFilter(expr string) (*BallFilter, error) {
+	filter := &BallFilter{}
+	rest := strings.TrimSpace(expr)
+	for rest != "" {
+		cond, remaining, err := parseFilterCondition(rest)
+		if err != nil {
+			return nil, err
+		}
+		filter.conditions = append(filter.conditions, cond)
+		rest = strings.TrimSpace(remaining)
+	}
+	return filter, nil
+}
+
+// parseFilterCondition parses the condition at the start of s and returns the rest
+func parseFilterCondition(s string) (filterCondition, string, error) {
+	i := 0
+	for i < len(s) && (s[i] == '_' || (s[i] >= 'a' && s[i] <= 'z') || (s[i] >= 'A' && s[i] <= 'Z')) {
+		i++
+	}
+	field := strings.ToLower(s[:i])
+	if field == "" {
+		return filterCondition{}, "", fmt.Errorf("invalid filter near %q: expected a field name", s)
+	}
+	values, ok := filterFields[field]
+	if !ok {
+		return filterCondition{}, "", fmt.Errorf("unknown filter field %q (valid: %s)", field, strings.Join(FilterFieldNames(), ", "))
+	}
+
+	var op string
+	for _, candidate := range []string{"!=", "!~", "=", "~"} {
+		if strings.HasPrefix(s[i:], candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return filterCondition{}, "", fmt.Errorf("invalid filter near %q: expected =, !=, ~ or !~ after %s", s, field)
+	}
+	i += len(op)
+
+	value, rest, err := parseFilterValue(s[i:])
+	if err != nil {
+		return filterCondition{}, "", fmt.Errorf("invalid filter value for %s: %w", field, err)
+	}
+	return filterCondition{field: field, op: op, value: value, values: values}, rest, nil
+}
+
+// parseFilterValue reads a double-quoted (with \" and \\ escapes) or bare value
+func parseFilterValue(s string) (value, rest string, err error) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexAny(s, " \t")
+		if end < 0 {
+			end = len(s)
+		}
+		return s[:end], s[end:], nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quote")
+}
+
+// Match reports whether the ball satisfies every condition
+func (f *BallFilter) Match(ball *Ball) bool {
+	for _, cond := range f.conditions {
+		if !cond.match(ball) {
+			return false
+		}
+	}
+	return true
+}
+
+// match tests one condition; negated operators hold when no value matches
+func (c filterCondition) match(ball *Ball) bool {
+	want := strings.ToLower(c.value)
+	found := false
+	for _, value := range c.values(ball) {
+		value = strings.ToLower(value)
+		if (c.op == "=" || c.op == "!=") && value == want {
+			found = true
+		}
+		if (c.op == "~" || c.op == "!~") && strings.Contains(value, want) {
+			found = true
+		}
+	}
+	if strings.HasPrefix(c.op, "!") {
+		return !found
+	}
+	return found
+}
+
+// FilterFieldNames returns the fields ball filters accept, sorted
+func FilterFieldNames() []string {
+	names := make([]string, 0, len(filterFields))
+	for name := range filterFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
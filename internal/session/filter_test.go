@@ -0,0 +1,45 @@
+package session
+
+import "testing"
+
+func TestBallFilter(t *testing.T) {
+	ball := &Ball{
+		ID:            "proj-a1b2c3d4",
+		Title:         "Call the payments API",
+		State:         StateBlocked,
+		Priority:      PriorityHigh,
+		BlockedReason: "Missing API key for Stripe",
+		Tags:          []string{"backend", "payments"},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{``, true},
+		{`blocked_reason~"missing api key"`, true},
+		{`blocked_reason~"rate limit"`, false},
+		{`blocked_reason!~flaky priority=high`, true},
+		{`priority=high state=pending`, false},
+		{`tag=payments`, true},
+		{`session!=backend`, false},
+		{`id=a1b2c3d4 kind=code`, true},
+		{`title~"say \"hi\""`, false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseBallFilter(tt.expr)
+		if err != nil {
+			t.Errorf("ParseBallFilter(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got := filter.Match(ball); got != tt.want {
+			t.Errorf("ParseBallFilter(%q).Match() = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`owner=me`, `title`, `title>3`, `title~"open`, `=x`} {
+		if _, err := ParseBallFilter(bad); err == nil {
+			t.Errorf("ParseBallFilter(%q) expected error", bad)
+		}
+	}
+}
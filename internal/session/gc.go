@@ -0,0 +1,155 @@
+package session
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultReapIdle is how long session storage must go untouched before the
+// reaper treats it as stale
+const DefaultReapIdle = 7 * 24 * time.Hour
+
+// sessionRuntimeFiles are the files agent runs and daemons leave in a session
+// directory. They are only worth keeping while an agent is using the session.
+var sessionRuntimeFiles = []string{
+	"agent.pid",
+	"agent.state",
+	"agent.ctrl",
+	"agent.ctrl.consumed",
+	"agent.sock",
+	"agent.stop",
+	"agent.log",
+	"events.jsonl",
+	"last_output.txt",
+}
+
+// StaleStorage is a session directory or runtime file the reaper can remove
+type StaleStorage struct {
+	SessionID string
+	Path      string    // Directory or file to remove
+	Reason    string    // Why it is stale, for reporting
+	Size      int64     // Bytes freed by removing it
+	LastUsed  time.Time // Most recent modification inside it
+}
+
+// ReapOptions controls which session storage counts as stale
+type ReapOptions struct {
+	Idle   time.Duration               // Storage untouched for this long is stale (0 = DefaultReapIdle)
+	Now    time.Time                   // Reference time (zero = time.Now())
+	Active func(sessionID string) bool // Reports sessions with a running daemon, which are never reaped
+}
+
+// FindStaleStorage finds session storage with no corresponding session or no
+// recent activity: directories without a session.json, such as the "_all"
+// meta-session or ones left by interrupted daemons, and idle runtime files of
+// real sessions. Sessions that are locked or have a running daemon are skipped.
+func (s *SessionStore) FindStaleStorage(opts ReapOptions) ([]StaleStorage, error) {
+	if opts.Idle <= 0 {
+		opts.Idle = DefaultReapIdle
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	cutoff := opts.Now.Add(-opts.Idle)
+
+	sessionsPath := filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir)
+	entries, err := os.ReadDir(sessionsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	var stale []StaleStorage
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		id := entry.Name()
+		if locked, _ := s.IsLocked(id); locked {
+			continue
+		}
+		if opts.Active != nil && opts.Active(id) {
+			continue
+		}
+
+		dir := s.sessionPath(id)
+		if _, err := os.Stat(s.sessionFilePath(id)); os.IsNotExist(err) {
+			// A scheduled "all" run keeps its storage until the schedule is removed
+			if _, err := os.Stat(s.scheduleFilePath(id)); err == nil {
+				continue
+			}
+			size, lastUsed := storageUsage(dir)
+			if lastUsed.After(cutoff) {
+				continue
+			}
+			reason := "no session"
+			if id == "_all" {
+				reason = `idle "all" meta-session`
+			}
+			stale = append(stale, StaleStorage{SessionID: id, Path: dir, Reason: reason, Size: size, LastUsed: lastUsed})
+			continue
+		}
+
+		for _, name := range sessionRuntimeFiles {
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().After(cutoff) {
+				continue
+			}
+			stale = append(stale, StaleStorage{
+				SessionID: id,
+				Path:      path,
+				Reason:    "idle " + name,
+				Size:      info.Size(),
+				LastUsed:  info.ModTime(),
+			})
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].LastUsed.Before(stale[j].LastUsed)
+	})
+	return stale, nil
+}
+
+// RemoveStaleStorage removes the storage found by FindStaleStorage. It keeps
+// going past failures and returns the first one.
+func RemoveStaleStorage(items []StaleStorage) error {
+	var firstErr error
+	for _, item := range items {
+		if err := os.RemoveAll(item.Path); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to remove %s: %w", item.Path, err)
+		}
+	}
+	return firstErr
+}
+
+// storageUsage returns the total size of the files under dir and the most
+// recent modification time of dir or anything in it
+func storageUsage(dir string) (int64, time.Time) {
+	var size int64
+	var lastUsed time.Time
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			size += info.Size()
+		}
+		if info.ModTime().After(lastUsed) {
+			lastUsed = info.ModTime()
+		}
+		return nil
+	})
+	return size, lastUsed
+}
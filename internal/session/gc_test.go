@@ -0,0 +1,105 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindStaleStorage(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := store.CreateSession("live", "Live session"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if _, err := store.CreateSession("running", "Session with a daemon"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	sessionsPath := filepath.Join(tmpDir, ".juggle", "sessions")
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	write := func(rel string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(sessionsPath, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set times: %v", err)
+		}
+		if err := os.Chtimes(filepath.Dir(path), modTime, modTime); err != nil {
+			t.Fatalf("failed to set times: %v", err)
+		}
+	}
+	write("_all/last_output.txt", old)
+	write("deleted/agent.state", old)
+	write("fresh-orphan/agent.pid", time.Now())
+	write("live/agent.log", old)
+	write("live/agent.state", time.Now())
+	write("running/agent.log", old)
+
+	stale, err := store.FindStaleStorage(ReapOptions{
+		Active: func(id string) bool { return id == "running" },
+	})
+	if err != nil {
+		t.Fatalf("FindStaleStorage failed: %v", err)
+	}
+
+	want := map[string]bool{
+		filepath.Join(sessionsPath, "_all"):              true,
+		filepath.Join(sessionsPath, "deleted"):           true,
+		filepath.Join(sessionsPath, "live", "agent.log"): true,
+	}
+	if len(stale) != len(want) {
+		t.Fatalf("expected %d stale items, got %+v", len(want), stale)
+	}
+	for _, item := range stale {
+		if !want[item.Path] {
+			t.Errorf("unexpected stale item %s (%s)", item.Path, item.Reason)
+		}
+		if item.Size != 4 {
+			t.Errorf("expected %s to be 4 bytes, got %d", item.Path, item.Size)
+		}
+	}
+
+	if err := RemoveStaleStorage(stale); err != nil {
+		t.Fatalf("RemoveStaleStorage failed: %v", err)
+	}
+	for path := range want {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", path)
+		}
+	}
+	if _, err := store.LoadSession("live"); err != nil {
+		t.Errorf("expected the live session to be kept: %v", err)
+	}
+}
+
+func TestFindStaleStorage_SkipsLockedSessions(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	lock, err := store.AcquireSessionLock("_all")
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	defer lock.Release()
+
+	stale, err := store.FindStaleStorage(ReapOptions{Now: time.Now().Add(365 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("FindStaleStorage failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("expected the locked _all session to be kept, got %+v", stale)
+	}
+}
@@ -0,0 +1,29 @@
+package session
+
+import (
+	"time"
+)
+
+// BallHotspot records how hot the code a ball affects is, written by juggle
+// agent hotspots. Within a priority, agents work on hotter balls first.
+type BallHotspot struct {
+	Score      int       `json:"score"`            // Recent churn of the paths plus a bonus per CODEOWNERS-owned path
+	Paths      []string  `json:"paths,omitempty"`  // Affected paths, hottest first
+	Owners     []string  `json:"owners,omitempty"` // CODEOWNERS owners of the paths
+	AnalyzedAt time.Time `json:"analyzed_at"`
+}
+
+// SetHotspot records the ball's hotspot analysis, replacing any earlier one.
+// A nil hotspot clears it.
+func (b *Ball) SetHotspot(hotspot *BallHotspot) {
+	b.Hotspot = hotspot
+	b.UpdateActivity()
+}
+
+// HotspotScore returns the ball's hotspot score, 0 when it hasn't been analyzed
+func (b *Ball) HotspotScore() int {
+	if b.Hotspot == nil {
+		return 0
+	}
+	return b.Hotspot.Score
+}
@@ -0,0 +1,682 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+)
+
+// JSONLBallStore is the default BallStore, keeping balls one JSON object a
+// line in balls.jsonl (active) and archive/balls.jsonl (completed) of a
+// juggle directory.
+//
+// Key features:
+//   - JSONL format for append-friendly version control
+//   - File locking for concurrent access safety
+//   - Atomic writes via temp file + rename pattern
+type JSONLBallStore struct {
+	ballsPath   string
+	archivePath string
+}
+
+// NewJSONLBallStore creates a JSONL store in the given juggle directory,
+// creating it and its archive directory if needed
+func NewJSONLBallStore(juggleDir string) (*JSONLBallStore, error) {
+	if err := os.MkdirAll(juggleDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s directory: %w", filepath.Base(juggleDir), err)
+	}
+
+	archiveDirPath := filepath.Join(juggleDir, archiveDir)
+	if err := os.MkdirAll(archiveDirPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	return &JSONLBallStore{
+		ballsPath:   filepath.Join(juggleDir, ballsFile),
+		archivePath: filepath.Join(archiveDirPath, archiveBallsFile),
+	}, nil
+}
+
+// acquireFileLock acquires an exclusive lock on a file
+// Returns the flock and cleanup function. The cleanup function should be deferred.
+func acquireFileLock(path string) (*flock.Flock, func(), error) {
+	lockPath := path + ".lock"
+	fileLock := flock.New(lockPath)
+
+	// Acquire exclusive lock (blocking)
+	if err := fileLock.Lock(); err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire lock on %s: %w", lockPath, err)
+	}
+
+	cleanup := func() {
+		fileLock.Unlock()
+	}
+
+	return fileLock, cleanup, nil
+}
+
+// AppendBall adds a new ball to the JSONL file
+func (s *JSONLBallStore) AppendBall(ball *Ball) error {
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ball: %w", err)
+	}
+
+	// Acquire file lock
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Open file in append mode
+	f, err := os.OpenFile(s.ballsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open balls file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write ball: %w", err)
+	}
+
+	if _, err := f.WriteString("\n"); err != nil {
+		return fmt.Errorf("failed to write newline: %w", err)
+	}
+
+	return nil
+}
+
+// ballJSON is used for JSON unmarshaling with migration support
+// It includes both old (intent) and new (title) field names
+type ballJSON struct {
+	Ball
+	Intent string `json:"intent,omitempty"` // Legacy field, migrated to Title
+}
+
+// LoadBalls reads all balls from the JSONL file
+func (s *JSONLBallStore) LoadBalls() ([]*Ball, error) {
+	// If file doesn't exist, return empty slice
+	if _, err := os.Stat(s.ballsPath); os.IsNotExist(err) {
+		return []*Ball{}, nil
+	}
+
+	f, err := os.Open(s.ballsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open balls file: %w", err)
+	}
+	defer f.Close()
+
+	balls := make([]*Ball, 0)
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue // Skip empty lines
+		}
+
+		ball, err := decodeBallLine([]byte(line))
+		if err != nil {
+			// Log error but continue
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse ball line: %v\n", err)
+			continue
+		}
+
+		balls = append(balls, ball)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading balls file: %w", err)
+	}
+
+	return balls, nil
+}
+
+// LoadArchivedBalls reads all balls from the archive JSONL file
+func (s *JSONLBallStore) LoadArchivedBalls() ([]*Ball, error) {
+	// If file doesn't exist, return empty slice
+	if _, err := os.Stat(s.archivePath); os.IsNotExist(err) {
+		return []*Ball{}, nil
+	}
+
+	f, err := os.Open(s.archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer f.Close()
+
+	balls := make([]*Ball, 0)
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue // Skip empty lines
+		}
+
+		ball, err := decodeBallLine([]byte(line))
+		if err != nil {
+			// Log error but continue
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse archived ball line: %v\n", err)
+			continue
+		}
+
+		balls = append(balls, ball)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading archive file: %w", err)
+	}
+
+	return balls, nil
+}
+
+// BallIDs returns the IDs of the active and archived balls, parsing only
+// the id of each line
+func (s *JSONLBallStore) BallIDs() ([]string, error) {
+	var ids []string
+	for _, path := range []string{s.ballsPath, s.archivePath} {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open balls file: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 {
+				continue // Skip empty lines
+			}
+			var line struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(data, &line) == nil && line.ID != "" {
+				ids = append(ids, line.ID)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading balls file: %w", err)
+		}
+	}
+	return ids, nil
+}
+
+// decodeBallLine parses one line of a JSONL file into a ball
+func decodeBallLine(line []byte) (*Ball, error) {
+	var ballData ballJSON
+	if err := json.Unmarshal(line, &ballData); err != nil {
+		return nil, err
+	}
+
+	ball := ballData.Ball
+
+	// Migrate legacy "intent" field to "title"
+	if ball.Title == "" && ballData.Intent != "" {
+		ball.Title = ballData.Intent
+	}
+	return &ball, nil
+}
+
+// ballLineHeader holds the fields of a JSONL line a BallQuery can test
+// before the whole ball is parsed
+type ballLineHeader struct {
+	State BallState `json:"state"`
+	Tags  []string  `json:"tags"`
+}
+
+// queryLine is a line that passed a BallQuery's cheap tests, with its ball
+// once parsed
+type queryLine struct {
+	data []byte
+	ball *Ball
+}
+
+// LoadBallsFiltered streams the active (or archived) balls file, parsing only
+// the state and tags of each line until it passes the query's state and tag
+// conditions, and stops once the page is full. An ordered query reads every
+// line, keeping only the first Offset+Limit+1 matching balls in its order.
+func (s *JSONLBallStore) LoadBallsFiltered(query BallQuery) (*BallPage, error) {
+	path := s.ballsPath
+	if query.Archived {
+		path = s.archivePath
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &BallPage{Balls: []*Ball{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open balls file: %w", err)
+	}
+	defer f.Close()
+
+	var lines []queryLine
+	var top *topBalls
+	if query.Less != nil {
+		top = query.top()
+	}
+	skipped := 0
+	more := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue // Skip empty lines
+		}
+
+		var header ballLineHeader
+		if err := json.Unmarshal(data, &header); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse ball line: %v\n", err)
+			continue
+		}
+		if !query.selects(header.State, header.Tags) {
+			continue
+		}
+
+		var line queryLine
+		if query.Match != nil || top != nil {
+			if line.ball, err = decodeBallLine(data); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to parse ball line: %v\n", err)
+				continue
+			}
+			if !query.matches(line.ball) {
+				continue
+			}
+		}
+
+		if top != nil {
+			top.add(line.ball)
+			continue
+		}
+		if skipped < query.Offset {
+			skipped++
+			continue
+		}
+		if query.Limit > 0 && len(lines) == query.Limit {
+			more = true
+			break
+		}
+		if line.ball == nil {
+			line.data = bytes.Clone(data) // The scanner reuses its buffer
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading balls file: %w", err)
+	}
+	if top != nil {
+		return query.cut(top.sorted()), nil
+	}
+
+	balls := make([]*Ball, 0, len(lines))
+	for _, line := range lines {
+		if line.ball == nil {
+			if line.ball, err = decodeBallLine(line.data); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to parse ball line: %v\n", err)
+				continue
+			}
+		}
+		balls = append(balls, line.ball)
+	}
+	return &BallPage{Balls: balls, More: more}, nil
+}
+
+// UpdateBall updates an existing ball by rewriting the JSONL file
+func (s *JSONLBallStore) UpdateBall(updated *Ball) error {
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return err
+	}
+
+	// Find and update the ball
+	found := false
+	for i, ball := range balls {
+		if ball.ID == updated.ID {
+			balls[i] = updated
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return NewBallNotFoundError(updated.ID)
+	}
+
+	// Rewrite entire file
+	return s.writeBalls(balls)
+}
+
+// ApplyBallChanges writes several changes with a single rewrite of the
+// JSONL file, holding its lock throughout so other writers' changes since
+// the balls were read are kept
+func (s *JSONLBallStore) ApplyBallChanges(changes BallChanges) error {
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(balls))
+	for i, ball := range balls {
+		index[ball.ID] = i
+	}
+	var missing string
+	for _, updated := range changes.Updated {
+		i, ok := index[updated.ID]
+		if !ok {
+			if missing == "" {
+				missing = updated.ID
+			}
+			continue
+		}
+		balls[i] = updated
+	}
+
+	deleted := make(map[string]bool, len(changes.Deleted))
+	for _, id := range changes.Deleted {
+		deleted[id] = true
+	}
+	kept := make([]*Ball, 0, len(balls)+len(changes.Added))
+	for _, ball := range balls {
+		if !deleted[ball.ID] {
+			kept = append(kept, ball)
+		}
+	}
+	kept = append(kept, changes.Added...)
+
+	if err := s.writeBallsUnlocked(kept); err != nil {
+		return err
+	}
+	if missing != "" {
+		return NewBallNotFoundError(missing)
+	}
+	return nil
+}
+
+// DeleteBall removes a ball from the JSONL file
+func (s *JSONLBallStore) DeleteBall(id string) error {
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return err
+	}
+
+	// Filter out the ball to delete
+	filtered := make([]*Ball, 0, len(balls))
+	for _, ball := range balls {
+		if ball.ID != id {
+			filtered = append(filtered, ball)
+		}
+	}
+
+	return s.writeBalls(filtered)
+}
+
+// ArchiveBall moves a ball to the archive.
+// This operation is atomic: both files are locked, and changes are applied
+// atomically using temp file + rename pattern.
+func (s *JSONLBallStore) ArchiveBall(ball *Ball) error {
+	// Acquire locks on both files to ensure atomic operation
+	_, unlockBalls, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return fmt.Errorf("failed to lock balls file: %w", err)
+	}
+	defer unlockBalls()
+
+	_, unlockArchive, err := acquireFileLock(s.archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock archive file: %w", err)
+	}
+	defer unlockArchive()
+
+	// Load current balls
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	// Load current archive
+	archived, err := s.LoadArchivedBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	// Find and remove the ball from active list
+	found := false
+	filtered := make([]*Ball, 0, len(balls))
+	for _, b := range balls {
+		if b.ID != ball.ID {
+			filtered = append(filtered, b)
+		} else {
+			found = true
+		}
+	}
+
+	if !found {
+		return NewBallNotFoundError(ball.ID)
+	}
+
+	// Add ball to archive
+	archived = append(archived, ball)
+
+	// Write both files atomically
+	// First, write the new archive (safer to add first)
+	if err := s.writeArchivedBallsUnlocked(archived); err != nil {
+		return fmt.Errorf("failed to update archive: %w", err)
+	}
+
+	// Then, write the active balls (without the archived ball)
+	if err := s.writeBallsUnlocked(filtered); err != nil {
+		// Attempt to restore archive on failure (remove the ball we just added)
+		// This is best-effort; in worst case we have a duplicate in archive
+		s.writeArchivedBallsUnlocked(archived[:len(archived)-1])
+		return fmt.Errorf("failed to remove ball from active: %w", err)
+	}
+
+	return nil
+}
+
+// writeBalls rewrites the entire balls.jsonl file
+func (s *JSONLBallStore) writeBalls(balls []*Ball) error {
+	// Acquire file lock
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.writeBallsUnlocked(balls)
+}
+
+// writeBallsUnlocked rewrites the entire balls.jsonl file without acquiring a lock.
+// Caller must hold the lock.
+func (s *JSONLBallStore) writeBallsUnlocked(balls []*Ball) error {
+	// Write to temp file first
+	tempPath := s.ballsPath + ".tmp"
+	f, err := os.Create(tempPath)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	for _, ball := range balls {
+		data, err := json.Marshal(ball)
+		if err != nil {
+			f.Close()
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to marshal ball: %w", err)
+		}
+
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to write ball: %w", err)
+		}
+
+		if _, err := f.WriteString("\n"); err != nil {
+			f.Close()
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to write newline: %w", err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tempPath, s.ballsPath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// UnarchiveBall restores a completed ball from archive back to ready state.
+// This operation is atomic: both files are locked, and changes are applied
+// atomically using temp file + rename pattern.
+func (s *JSONLBallStore) UnarchiveBall(ballID string) (*Ball, error) {
+	// Acquire locks on both files to ensure atomic operation
+	_, unlockBalls, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock balls file: %w", err)
+	}
+	defer unlockBalls()
+
+	_, unlockArchive, err := acquireFileLock(s.archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock archive file: %w", err)
+	}
+	defer unlockArchive()
+
+	// Load archived balls (within lock)
+	archived, err := s.LoadArchivedBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	// Find ball with matching ID
+	var ball *Ball
+	var ballIndex int
+	for i, b := range archived {
+		if b.ID == ballID {
+			ball = b
+			ballIndex = i
+			break
+		}
+	}
+	if ball == nil {
+		return nil, NewBallNotFoundError(ballID)
+	}
+
+	// Change state to pending using new state model
+	ball.State = StatePending
+	ball.BlockedReason = ""
+	ball.CompletedAt = nil
+	ball.CompletionNote = ""
+
+	// Load current balls
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	// Add the unarchived ball to the list
+	balls = append(balls, ball)
+
+	// Prepare the updated archive (without the ball being restored)
+	updatedArchive := make([]*Ball, 0, len(archived)-1)
+	for i, b := range archived {
+		if i != ballIndex {
+			updatedArchive = append(updatedArchive, b)
+		}
+	}
+
+	// Write both files atomically (temp file + rename pattern)
+	// First, write the new archive
+	if err := s.writeArchivedBallsUnlocked(updatedArchive); err != nil {
+		return nil, fmt.Errorf("failed to update archive: %w", err)
+	}
+
+	// Then, write the active balls
+	if err := s.writeBallsUnlocked(balls); err != nil {
+		// Attempt to restore archive on failure
+		// This is best-effort; in worst case we have inconsistent state
+		s.writeArchivedBallsUnlocked(archived)
+		return nil, fmt.Errorf("failed to add ball to active: %w", err)
+	}
+
+	return ball, nil
+}
+
+// writeArchivedBalls rewrites the entire archive/balls.jsonl file
+func (s *JSONLBallStore) writeArchivedBalls(balls []*Ball) error {
+	// Acquire file lock
+	_, unlock, err := acquireFileLock(s.archivePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.writeArchivedBallsUnlocked(balls)
+}
+
+// writeArchivedBallsUnlocked rewrites the entire archive/balls.jsonl file without acquiring a lock.
+// Caller must hold the lock.
+func (s *JSONLBallStore) writeArchivedBallsUnlocked(balls []*Ball) error {
+	// Write to temp file first
+	tempPath := s.archivePath + ".tmp"
+	f, err := os.Create(tempPath)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	for _, ball := range balls {
+		data, err := json.Marshal(ball)
+		if err != nil {
+			f.Close()
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to marshal ball: %w", err)
+		}
+
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to write ball: %w", err)
+		}
+
+		if _, err := f.WriteString("\n"); err != nil {
+			f.Close()
+			os.Remove(tempPath)
+			return fmt.Errorf("failed to write newline: %w", err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tempPath, s.archivePath); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
@@ -11,10 +11,10 @@ import (
 )
 
 const (
-	sessionsDir       = "sessions"
-	sessionFile       = "session.json"
-	progressFile      = "progress.txt"
-	agentUpdateFile   = "agent-update.txt"
+	sessionsDir     = "sessions"
+	sessionFile     = "session.json"
+	progressFile    = "progress.txt"
+	agentUpdateFile = "agent-update.txt"
 )
 
 // JuggleSession represents a grouping of balls by tag.
@@ -33,13 +33,19 @@ const (
 //	session := session.NewJuggleSession("auth-feature", "OAuth2 implementation")
 //	session.AddAcceptanceCriterion("All tests pass")
 type JuggleSession struct {
-	ID                 string    `json:"id"`                         // Session ID (same as tag)
-	Description        string    `json:"description"`                // Human-readable description
-	Context            string    `json:"context"`                    // Rich context for agent memory
-	DefaultModel       ModelSize `json:"default_model,omitempty"`    // Default model size for balls in this session
-	AcceptanceCriteria []string  `json:"acceptance_criteria,omitempty"` // Session-level ACs applied to all balls
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                 string            `json:"id"`                            // Session ID (same as tag)
+	Description        string            `json:"description"`                   // Human-readable description
+	Context            string            `json:"context"`                       // Rich context for agent memory
+	DefaultModel       ModelSize         `json:"default_model,omitempty"`       // Default model size for balls in this session
+	AcceptanceCriteria []string          `json:"acceptance_criteria,omitempty"` // Session-level ACs applied to all balls
+	Env                map[string]string `json:"env,omitempty"`                 // Environment variables for the agent (ball env takes precedence)
+	Setup              []string          `json:"setup,omitempty"`               // Shell commands run before the first agent iteration
+	Teardown           []string          `json:"teardown,omitempty"`            // Shell commands run after the agent run ends
+	SystemPrompt       string            `json:"system_prompt,omitempty"`       // Template replacing the agent system prompt for this session
+	ContextDocs        []string          `json:"context_docs,omitempty"`        // Project docs (e.g. README.md) the docs briefing is built from
+	DocsBriefing       *DocsBriefing     `json:"docs_briefing,omitempty"`       // Summary of ContextDocs, refreshed when they change
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
 }
 
 // NewJuggleSession creates a new session with the given ID and description
@@ -84,6 +90,30 @@ func (s *JuggleSession) AddAcceptanceCriterion(criterion string) {
 	s.UpdatedAt = time.Now()
 }
 
+// SetEnvVar sets an environment variable for the agent, or removes it when value is empty
+func (s *JuggleSession) SetEnvVar(key, value string) {
+	s.Env = setEnvVar(s.Env, key, value)
+	s.UpdatedAt = time.Now()
+}
+
+// SetSetupCommands replaces the commands run before the first agent iteration
+func (s *JuggleSession) SetSetupCommands(commands []string) {
+	s.Setup = nonEmptyCommands(commands)
+	s.UpdatedAt = time.Now()
+}
+
+// SetTeardownCommands replaces the commands run after the agent run ends
+func (s *JuggleSession) SetTeardownCommands(commands []string) {
+	s.Teardown = nonEmptyCommands(commands)
+	s.UpdatedAt = time.Now()
+}
+
+// SetSystemPrompt sets the session's system prompt template (empty uses the project's)
+func (s *JuggleSession) SetSystemPrompt(prompt string) {
+	s.SystemPrompt = prompt
+	s.UpdatedAt = time.Now()
+}
+
 // HasAcceptanceCriteria returns true if the session has any acceptance criteria
 func (s *JuggleSession) HasAcceptanceCriteria() bool {
 	return len(s.AcceptanceCriteria) > 0
@@ -192,7 +222,7 @@ func (s *SessionStore) LoadSession(id string) (*JuggleSession, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("session %s not found", id)
+			return nil, NewSessionNotFoundError(id)
 		}
 		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
@@ -281,6 +311,61 @@ func (s *SessionStore) UpdateSessionDefaultModel(id string, model ModelSize) err
 	return s.saveSession(session)
 }
 
+// UpdateSessionEnvVar sets or (with an empty value) removes a session environment variable
+func (s *SessionStore) UpdateSessionEnvVar(id, key, value string) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetEnvVar(key, value)
+	return s.saveSession(session)
+}
+
+// UpdateSessionSetup replaces the session's setup commands
+func (s *SessionStore) UpdateSessionSetup(id string, commands []string) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetSetupCommands(commands)
+	return s.saveSession(session)
+}
+
+// UpdateSessionTeardown replaces the session's teardown commands
+func (s *SessionStore) UpdateSessionTeardown(id string, commands []string) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetTeardownCommands(commands)
+	return s.saveSession(session)
+}
+
+// UpdateSessionContextDocs sets or (with no docs) clears the docs the session's briefing is built from
+func (s *SessionStore) UpdateSessionContextDocs(id string, docs []string) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetContextDocs(docs)
+	return s.saveSession(session)
+}
+
+// UpdateSessionSystemPrompt sets or (with an empty prompt) clears the session's system prompt template
+func (s *SessionStore) UpdateSessionSystemPrompt(id, prompt string) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetSystemPrompt(prompt)
+	return s.saveSession(session)
+}
+
 // DeleteSession removes a session and its directory
 func (s *SessionStore) DeleteSession(id string) error {
 	// Verify session exists
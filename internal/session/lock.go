@@ -147,6 +147,26 @@ func (s *SessionStore) IsLocked(sessionID string) (bool, *LockInfo) {
 	return false, nil
 }
 
+// RemoveStaleLock removes lock files a crashed agent run left behind for a
+// session. The OS releases the lock itself when its holder exits, so the
+// files are stale whenever the session isn't locked. Reports whether any
+// files were removed.
+func (s *SessionStore) RemoveStaleLock(sessionID string) (bool, error) {
+	if locked, _ := s.IsLocked(sessionID); locked {
+		return false, nil
+	}
+	removed := false
+	for _, name := range []string{lockFile, lockInfoFile} {
+		err := os.Remove(filepath.Join(s.sessionPath(sessionID), name))
+		if err == nil {
+			removed = true
+		} else if !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove stale lock: %w", err)
+		}
+	}
+	return removed, nil
+}
+
 // readLockInfo reads the lock info from a lock file
 func readLockInfo(lockPath string) (*LockInfo, error) {
 	data, err := os.ReadFile(lockPath)
@@ -171,10 +191,20 @@ type BallLock struct {
 	fileLock     *flock.Flock
 }
 
+// ballLockDir returns the directory holding ball lock files for workDir.
+// Linked worktrees resolve to the main repo so every worktree shares the same ball locks.
+func ballLockDir(workDir string) string {
+	if storageDir, err := ResolveStorageDir(workDir, projectStorePath); err == nil {
+		workDir = storageDir
+	}
+	return filepath.Join(workDir, ".juggle", "balls")
+}
+
 // AcquireBallLock acquires an exclusive lock on a specific ball.
-// The lock file is stored in .juggle/balls/<ballID>.lock within the ball's project directory.
+// The lock file is stored in .juggle/balls/<ballID>.lock within the ball's project directory
+// (the main repo's directory when workDir is a linked worktree).
 func AcquireBallLock(workDir string, ballID string) (*BallLock, error) {
-	lockDir := filepath.Join(workDir, ".juggle", "balls")
+	lockDir := ballLockDir(workDir)
 	if err := os.MkdirAll(lockDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create balls lock directory: %w", err)
 	}
@@ -245,7 +275,7 @@ func (l *BallLock) Release() error {
 
 // IsBallLocked checks if a ball currently has an active lock
 func IsBallLocked(workDir string, ballID string) (bool, *LockInfo) {
-	lockDir := filepath.Join(workDir, ".juggle", "balls")
+	lockDir := ballLockDir(workDir)
 	lockPath := filepath.Join(lockDir, ballID+".lock")
 	lockInfoPath := filepath.Join(lockDir, ballID+".lock.info")
 
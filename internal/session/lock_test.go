@@ -662,3 +662,70 @@ func TestConcurrentBallLockAttempts(t *testing.T) {
 		t.Error("at least one goroutine should have acquired the lock")
 	}
 }
+
+func TestAcquireBallLock_SharedAcrossLinkedWorktree(t *testing.T) {
+	mainDir := t.TempDir()
+	worktreeDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(mainDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+	if err := RegisterWorktree(mainDir, worktreeDir, ""); err != nil {
+		t.Fatalf("failed to register worktree: %v", err)
+	}
+
+	lock, err := AcquireBallLock(mainDir, "test-ball-1")
+	if err != nil {
+		t.Fatalf("failed to acquire lock in main repo: %v", err)
+	}
+	defer lock.Release()
+
+	// The worktree resolves to the main repo's lock, so the ball is already held
+	if locked, _ := IsBallLocked(worktreeDir, "test-ball-1"); !locked {
+		t.Error("expected ball to be locked when checked from the worktree")
+	}
+	if lock2, err := AcquireBallLock(worktreeDir, "test-ball-1"); err == nil {
+		lock2.Release()
+		t.Fatal("expected error when locking the same ball from a linked worktree")
+	}
+}
+
+func TestRemoveStaleLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := store.CreateSession("test-session", "Test session"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	lockPath := filepath.Join(tmpDir, ".juggle", "sessions", "test-session", "agent.lock")
+	lockInfoPath := filepath.Join(tmpDir, ".juggle", "sessions", "test-session", "agent.lock.info")
+
+	// A held lock is left alone
+	lock, err := store.AcquireSessionLock("test-session")
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	if removed, err := store.RemoveStaleLock("test-session"); err != nil || removed {
+		t.Errorf("RemoveStaleLock on a held lock = %v, %v; want false, nil", removed, err)
+	}
+	if _, err := os.Stat(lockInfoPath); err != nil {
+		t.Error("lock info file should remain while locked")
+	}
+	lock.Release()
+
+	// Files left by a crashed run are removed
+	for _, path := range []string{lockPath, lockInfoPath} {
+		if err := os.WriteFile(path, []byte(`{"pid": 1}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if removed, err := store.RemoveStaleLock("test-session"); err != nil || !removed {
+		t.Errorf("RemoveStaleLock = %v, %v; want true, nil", removed, err)
+	}
+	for _, path := range []string{lockPath, lockInfoPath} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", filepath.Base(path))
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -24,6 +25,9 @@ type AgentMetrics struct {
 	LastActivity time.Time      `json:"last_activity"`
 	TotalTools   int            `json:"total_tools"`
 
+	// When each file was last modified (from PostToolUse and agent iteration diffs)
+	FileTouches []FileTouch `json:"file_touches,omitempty"`
+
 	// Turn tracking (from Stop)
 	TurnCount int `json:"turn_count"`
 
@@ -36,6 +40,53 @@ type AgentMetrics struct {
 	SessionEnded bool `json:"session_ended"`
 }
 
+// FileTouch records when an agent last modified a file
+type FileTouch struct {
+	Path   string    `json:"path"`              // Slash-separated, relative to Dir
+	Dir    string    `json:"dir"`               // Directory the agent ran in
+	BallID string    `json:"ball_id,omitempty"` // Ball being worked on, if known
+	At     time.Time `json:"at"`
+}
+
+// AbsPath returns the touched file's absolute path
+func (t FileTouch) AbsPath() string {
+	return filepath.Join(t.Dir, filepath.FromSlash(t.Path))
+}
+
+// fileModifyingTools are the agent tools whose file_path is a file they change
+var fileModifyingTools = map[string]bool{
+	"Edit":         true,
+	"MultiEdit":    true,
+	"Write":        true,
+	"NotebookEdit": true,
+}
+
+// NewFileTouch builds a touch of path by an agent running in dir. Absolute
+// paths are made relative to dir.
+func NewFileTouch(dir, path, ballID string, at time.Time) FileTouch {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		} else {
+			dir, path = filepath.Dir(path), filepath.Base(path)
+		}
+	}
+	return FileTouch{Path: filepath.ToSlash(filepath.Clean(path)), Dir: dir, BallID: ballID, At: at}
+}
+
+// touchFile records a touch, replacing an older one of the same file by the same ball
+func (m *AgentMetrics) touchFile(touch FileTouch) {
+	for i, existing := range m.FileTouches {
+		if existing.Path == touch.Path && existing.Dir == touch.Dir && existing.BallID == touch.BallID {
+			if touch.At.After(existing.At) {
+				m.FileTouches[i].At = touch.At
+			}
+			return
+		}
+	}
+	m.FileTouches = append(m.FileTouches, touch)
+}
+
 // NewAgentMetrics creates an empty metrics struct
 func NewAgentMetrics() *AgentMetrics {
 	return &AgentMetrics{
@@ -108,8 +159,9 @@ func (s *SessionStore) SaveMetrics(id string, metrics *AgentMetrics) error {
 	return nil
 }
 
-// UpdateMetricsFromPostTool updates metrics based on a PostToolUse hook event
-func (s *SessionStore) UpdateMetricsFromPostTool(id, toolName, filePath string) error {
+// UpdateMetricsFromPostTool updates metrics based on a PostToolUse hook event.
+// Files changed by editing tools are recorded as touched by ballID in workDir.
+func (s *SessionStore) UpdateMetricsFromPostTool(id, toolName, filePath, workDir, ballID string) error {
 	metrics, err := s.LoadMetrics(id)
 	if err != nil {
 		return err
@@ -123,11 +175,58 @@ func (s *SessionStore) UpdateMetricsFromPostTool(id, toolName, filePath string)
 	// Track file changes
 	if filePath != "" {
 		metrics.FilesChanged = appendUnique(metrics.FilesChanged, filePath)
+		if fileModifyingTools[toolName] {
+			metrics.touchFile(NewFileTouch(workDir, filePath, ballID, metrics.LastActivity))
+		}
 	}
 
 	return s.SaveMetrics(id, metrics)
 }
 
+// RecordFileTouches adds file touches to a session's metrics
+func (s *SessionStore) RecordFileTouches(id string, touches []FileTouch) error {
+	if len(touches) == 0 {
+		return nil
+	}
+	metrics, err := s.LoadMetrics(id)
+	if err != nil {
+		return err
+	}
+	for _, touch := range touches {
+		metrics.touchFile(touch)
+	}
+	return s.SaveMetrics(id, metrics)
+}
+
+// RecentFileTouches returns the file touches made since the given time, keyed
+// by session storage ID (including "_all")
+func (s *SessionStore) RecentFileTouches(since time.Time) (map[string][]FileTouch, error) {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	touches := make(map[string][]FileTouch)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		metrics, err := s.LoadMetrics(entry.Name())
+		if err != nil {
+			continue // Skip unreadable metrics
+		}
+		for _, touch := range metrics.FileTouches {
+			if !touch.At.Before(since) {
+				touches[entry.Name()] = append(touches[entry.Name()], touch)
+			}
+		}
+	}
+	return touches, nil
+}
+
 // UpdateMetricsFromToolFailure updates metrics based on a PostToolUseFailure hook event
 func (s *SessionStore) UpdateMetricsFromToolFailure(id, toolName string) error {
 	metrics, err := s.LoadMetrics(id)
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUpdateMetricsFromPostTool_RecordsFileTouches(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewSessionStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+
+	file := filepath.Join(dir, "internal", "auth", "login.go")
+	if err := store.UpdateMetricsFromPostTool("night-a", "Read", file, dir, "proj-1"); err != nil {
+		t.Fatalf("UpdateMetricsFromPostTool failed: %v", err)
+	}
+	if err := store.UpdateMetricsFromPostTool("night-a", "Edit", file, dir, "proj-1"); err != nil {
+		t.Fatalf("UpdateMetricsFromPostTool failed: %v", err)
+	}
+	if err := store.UpdateMetricsFromPostTool("night-a", "Edit", file, dir, "proj-1"); err != nil {
+		t.Fatalf("UpdateMetricsFromPostTool failed: %v", err)
+	}
+
+	metrics, err := store.LoadMetrics("night-a")
+	if err != nil {
+		t.Fatalf("LoadMetrics failed: %v", err)
+	}
+	if len(metrics.FileTouches) != 1 {
+		t.Fatalf("Expected one touch for the edited file (reads don't count), got %+v", metrics.FileTouches)
+	}
+	touch := metrics.FileTouches[0]
+	if touch.Path != "internal/auth/login.go" || touch.Dir != dir || touch.BallID != "proj-1" {
+		t.Errorf("Unexpected touch %+v", touch)
+	}
+	if touch.AbsPath() != file {
+		t.Errorf("Expected AbsPath %s, got %s", file, touch.AbsPath())
+	}
+}
+
+func TestRecentFileTouches(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewSessionStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+
+	now := time.Now()
+	if err := store.RecordFileTouches("night-a", []FileTouch{
+		NewFileTouch(dir, "old.go", "", now.Add(-48*time.Hour)),
+		NewFileTouch(dir, "new.go", "", now.Add(-time.Hour)),
+	}); err != nil {
+		t.Fatalf("RecordFileTouches failed: %v", err)
+	}
+	if err := store.RecordFileTouches("_all", []FileTouch{NewFileTouch(dir, "all.go", "", now)}); err != nil {
+		t.Fatalf("RecordFileTouches failed: %v", err)
+	}
+
+	touches, err := store.RecentFileTouches(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("RecentFileTouches failed: %v", err)
+	}
+	if len(touches["night-a"]) != 1 || touches["night-a"][0].Path != "new.go" {
+		t.Errorf("Expected only the recent touch for night-a, got %+v", touches["night-a"])
+	}
+	if len(touches["_all"]) != 1 {
+		t.Errorf("Expected the _all meta-session's touches, got %+v", touches["_all"])
+	}
+}
@@ -0,0 +1,204 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	queueFile       = "queue.json"
+	queueLockFile   = "queue.json.lock"
+	queueWorkerLock = "queue.worker.lock"
+)
+
+// QueueItemKind is what a queue item runs the agent on
+type QueueItemKind string
+
+const (
+	QueueKindBall    QueueItemKind = "ball"
+	QueueKindSession QueueItemKind = "session"
+)
+
+// QueueItemStatus tracks a queue item from enqueue to its result
+type QueueItemStatus string
+
+const (
+	QueueQueued     QueueItemStatus = "queued"
+	QueueRunning    QueueItemStatus = "running"
+	QueueComplete   QueueItemStatus = "complete"   // Every ball worked on finished
+	QueueBlocked    QueueItemStatus = "blocked"    // The agent stopped on a blocker
+	QueueIncomplete QueueItemStatus = "incomplete" // Ran out of iterations, time or budget
+	QueueFailed     QueueItemStatus = "failed"     // The run itself errored
+)
+
+// QueueItem is a ball or session waiting for, or done with, an agent run
+type QueueItem struct {
+	ID         int             `json:"id"`
+	Kind       QueueItemKind   `json:"kind"`
+	Target     string          `json:"target"` // Ball ID or session ID
+	Title      string          `json:"title,omitempty"`
+	ProjectDir string          `json:"project_dir"`
+	SessionID  string          `json:"session_id"` // Session the agent runs in
+	Priority   Priority        `json:"priority"`
+	Iterations int             `json:"iterations"`
+	Trust      bool            `json:"trust,omitempty"`
+	Status     QueueItemStatus `json:"status"`
+	Result     string          `json:"result,omitempty"` // Outcome summary once finished
+	AddedAt    time.Time       `json:"added_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	EndedAt    *time.Time      `json:"ended_at,omitempty"`
+}
+
+// Finished reports whether the item has a result
+func (q *QueueItem) Finished() bool {
+	return q.Status != QueueQueued && q.Status != QueueRunning
+}
+
+// PriorityWeight returns a numeric weight for ordering, like Ball.PriorityWeight
+func (q *QueueItem) PriorityWeight() int {
+	return (&Ball{Priority: q.Priority}).PriorityWeight()
+}
+
+// SortQueue orders items the way a worker drains them: highest priority
+// first, then first added
+func SortQueue(items []*QueueItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].PriorityWeight() != items[j].PriorityWeight() {
+			return items[i].PriorityWeight() > items[j].PriorityWeight()
+		}
+		if !items[i].AddedAt.Equal(items[j].AddedAt) {
+			return items[i].AddedAt.Before(items[j].AddedAt)
+		}
+		return items[i].ID < items[j].ID
+	})
+}
+
+// NextQueued returns the queued item to run next, or nil when none are waiting
+func NextQueued(items []*QueueItem) *QueueItem {
+	var queued []*QueueItem
+	for _, item := range items {
+		if item.Status == QueueQueued {
+			queued = append(queued, item)
+		}
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+	SortQueue(queued)
+	return queued[0]
+}
+
+// QueueStore persists the agent work queue at ~/.juggle/queue.json. The queue
+// spans projects, so it lives with the global config rather than in a project.
+type QueueStore struct {
+	dir string
+}
+
+// NewQueueStore creates a queue store under the config home
+func NewQueueStore(opts ConfigOptions) (*QueueStore, error) {
+	if opts.ConfigHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.ConfigHome = home
+	}
+	return &QueueStore{dir: filepath.Join(opts.ConfigHome, opts.JuggleDirName)}, nil
+}
+
+// Load returns all queue items in the order they were added
+func (s *QueueStore) Load() ([]*QueueItem, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, queueFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*QueueItem{}, nil
+		}
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+	var items []*QueueItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse queue: %w", err)
+	}
+	return items, nil
+}
+
+// Update loads the queue, applies fn and saves the result, holding a file
+// lock throughout so concurrent adds and the worker don't lose each other's changes
+func (s *QueueStore) Update(fn func(items []*QueueItem) ([]*QueueItem, error)) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(s.dir, queueLockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("failed to lock queue: %w", err)
+	}
+	defer lock.Unlock()
+
+	items, err := s.Load()
+	if err != nil {
+		return err
+	}
+	items, err = fn(items)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue: %w", err)
+	}
+	path := filepath.Join(s.dir, queueFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write queue: %w", err)
+	}
+	return nil
+}
+
+// Add appends items to the queue as queued, assigning their IDs
+func (s *QueueStore) Add(newItems ...*QueueItem) error {
+	return s.Update(func(items []*QueueItem) ([]*QueueItem, error) {
+		nextID := 1
+		for _, item := range items {
+			if item.ID >= nextID {
+				nextID = item.ID + 1
+			}
+		}
+		for _, item := range newItems {
+			item.ID = nextID
+			nextID++
+			item.Status = QueueQueued
+			if item.AddedAt.IsZero() {
+				item.AddedAt = time.Now()
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	})
+}
+
+// AcquireWorker takes the lock that allows a single queue worker at a time.
+// The returned function releases it.
+func (s *QueueStore) AcquireWorker() (func(), error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create queue directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(s.dir, queueWorkerLock))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire queue worker lock: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("another queue worker is already running")
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextQueued_PriorityThenAge(t *testing.T) {
+	now := time.Now()
+	items := []*QueueItem{
+		{ID: 1, Priority: PriorityMedium, Status: QueueQueued, AddedAt: now.Add(-3 * time.Hour)},
+		{ID: 2, Priority: PriorityHigh, Status: QueueQueued, AddedAt: now.Add(-1 * time.Hour)},
+		{ID: 3, Priority: PriorityHigh, Status: QueueQueued, AddedAt: now.Add(-2 * time.Hour)},
+		{ID: 4, Priority: PriorityUrgent, Status: QueueComplete, AddedAt: now.Add(-4 * time.Hour)},
+	}
+
+	next := NextQueued(items)
+	if next == nil || next.ID != 3 {
+		t.Fatalf("expected the oldest high priority item (#3), got %+v", next)
+	}
+
+	for _, item := range items {
+		item.Status = QueueComplete
+	}
+	if next := NextQueued(items); next != nil {
+		t.Errorf("expected nothing queued, got #%d", next.ID)
+	}
+}
+
+func TestQueueStore_AddAndUpdate(t *testing.T) {
+	store, err := NewQueueStore(ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"})
+	if err != nil {
+		t.Fatalf("NewQueueStore failed: %v", err)
+	}
+
+	if err := store.Add(&QueueItem{Kind: QueueKindSession, Target: "a"}, &QueueItem{Kind: QueueKindBall, Target: "b"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Add(&QueueItem{Kind: QueueKindSession, Target: "c"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	items, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.ID != i+1 || item.Status != QueueQueued || item.AddedAt.IsZero() {
+			t.Errorf("item %d = %+v, want ID %d, queued, with AddedAt", i, item, i+1)
+		}
+	}
+
+	err = store.Update(func(items []*QueueItem) ([]*QueueItem, error) {
+		items[0].Status = QueueComplete
+		items[0].Result = "done"
+		return items[1:2], nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	items, _ = store.Load()
+	if len(items) != 1 || items[0].Target != "b" {
+		t.Errorf("expected only item b after update, got %+v", items)
+	}
+}
+
+func TestQueueStore_SingleWorker(t *testing.T) {
+	store, err := NewQueueStore(ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"})
+	if err != nil {
+		t.Fatalf("NewQueueStore failed: %v", err)
+	}
+
+	release, err := store.AcquireWorker()
+	if err != nil {
+		t.Fatalf("AcquireWorker failed: %v", err)
+	}
+	if _, err := store.AcquireWorker(); err == nil {
+		t.Error("expected a second worker to be refused")
+	}
+	release()
+
+	release, err = store.AcquireWorker()
+	if err != nil {
+		t.Fatalf("AcquireWorker after release failed: %v", err)
+	}
+	release()
+}
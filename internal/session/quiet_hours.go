@@ -0,0 +1,175 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+// weekdayNames are the day names quiet hours accept, indexed by time.Weekday
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// QuietHours are local-time windows during which the agent loop doesn't
+// start new iterations, e.g. "09:00-18:00" or "mon-fri 09:00-18:00, sat 10:00-14:00".
+type QuietHours struct {
+	source  string
+	windows []quietWindow
+}
+
+// quietWindow is one window of quiet hours. A window whose end is not after
+// its start runs past midnight into the next day.
+type quietWindow struct {
+	days       uint8 // Bit n set when the window starts on time.Weekday(n)
+	start, end int   // Minutes after midnight
+}
+
+// ParseQuietHours parses a comma-separated list of "HH:MM-HH:MM" windows, each
+// optionally prefixed with a day or day range ("mon-fri 09:00-18:00"). Days
+// apply to the day a window starts, so "fri 22:00-06:00" ends Saturday morning.
+func ParseQuietHours(spec string) (*QuietHours, error) {
+	q := &QuietHours{source: strings.TrimSpace(spec)}
+	for _, part := range strings.Split(q.source, ",") {
+		fields := strings.Fields(part)
+		w := quietWindow{days: 0x7f}
+		var span string
+		switch len(fields) {
+		case 1:
+			span = fields[0]
+		case 2:
+			days, err := parseWeekdays(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			w.days = days
+			span = fields[1]
+		default:
+			return nil, fmt.Errorf("invalid quiet hours %q: want \"[days] HH:MM-HH:MM\"", strings.TrimSpace(part))
+		}
+
+		startPart, endPart, ok := strings.Cut(span, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet hours %q: want \"HH:MM-HH:MM\"", span)
+		}
+		var err error
+		if w.start, err = parseClock(startPart); err != nil {
+			return nil, err
+		}
+		if w.start == minutesPerDay {
+			return nil, fmt.Errorf("quiet hours %q can't start at 24:00", span)
+		}
+		if w.end, err = parseClock(endPart); err != nil {
+			return nil, err
+		}
+		if w.start == w.end {
+			return nil, fmt.Errorf("quiet hours %q start and end at the same time", span)
+		}
+		q.windows = append(q.windows, w)
+	}
+	return q, nil
+}
+
+// String returns the quiet hours as they were given
+func (q *QuietHours) String() string {
+	return q.source
+}
+
+// Until returns when the quiet hours covering now end, or the zero time if
+// now is outside them. Back-to-back windows are treated as one.
+func (q *QuietHours) Until(now time.Time) time.Time {
+	var until time.Time
+	t := now
+	// Each window is at most a day long, so a week of chained windows covers
+	// every configuration that ever ends
+	for i := 0; i < 7*len(q.windows)+1; i++ {
+		end, ok := q.endOfWindowAt(t)
+		if !ok {
+			break
+		}
+		until, t = end, end
+	}
+	return until
+}
+
+// endOfWindowAt returns the end of a window containing t
+func (q *QuietHours) endOfWindowAt(t time.Time) (time.Time, bool) {
+	minute := t.Hour()*60 + t.Minute()
+	today := t.Weekday()
+	yesterday := (today + 6) % 7
+	at := func(dayOffset, minutes int) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day()+dayOffset, minutes/60, minutes%60, 0, 0, t.Location())
+	}
+
+	for _, w := range q.windows {
+		startsToday := w.days&(1<<uint(today)) != 0
+		if w.start < w.end {
+			if startsToday && minute >= w.start && minute < w.end {
+				return at(0, w.end), true
+			}
+			continue
+		}
+		// Runs past midnight: either it started today, or yesterday and hasn't ended
+		if startsToday && minute >= w.start {
+			return at(1, w.end), true
+		}
+		if w.days&(1<<uint(yesterday)) != 0 && minute < w.end {
+			return at(0, w.end), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseClock parses "HH:MM" into minutes after midnight. "24:00" is accepted
+// as the end of the day.
+func parseClock(s string) (int, error) {
+	hourPart, minutePart, ok := strings.Cut(s, ":")
+	hour, hourErr := strconv.Atoi(hourPart)
+	minute, minuteErr := strconv.Atoi(minutePart)
+	if !ok || hourErr != nil || minuteErr != nil || len(minutePart) != 2 {
+		return 0, fmt.Errorf("invalid time %q in quiet hours: want HH:MM", s)
+	}
+	if hour == 24 && minute == 0 {
+		return minutesPerDay, nil
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("time %q in quiet hours is out of range", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// parseWeekdays parses a day name or a range of them ("mon-fri", "fri-mon")
+// into a bitset indexed by time.Weekday
+func parseWeekdays(s string) (uint8, error) {
+	firstPart, lastPart, isRange := strings.Cut(s, "-")
+	first, err := parseWeekday(firstPart)
+	if err != nil {
+		return 0, err
+	}
+	last := first
+	if isRange {
+		if last, err = parseWeekday(lastPart); err != nil {
+			return 0, err
+		}
+	}
+
+	var days uint8
+	for d := first; ; d = (d + 1) % 7 {
+		days |= 1 << uint(d)
+		if d == last {
+			break
+		}
+	}
+	return days, nil
+}
+
+// parseWeekday parses a three-letter day name
+func parseWeekday(s string) (int, error) {
+	for i, name := range weekdayNames {
+		if strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q in quiet hours: want one of %s", s, strings.Join(weekdayNames, ", "))
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHours_Until(t *testing.T) {
+	// Friday 2026-01-02
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		now  time.Time
+		want time.Time
+	}{
+		{"09:00-18:00", at(2, 8, 59), time.Time{}},
+		{"09:00-18:00", at(2, 9, 0), at(2, 18, 0)},
+		{"09:00-18:00", at(2, 18, 0), time.Time{}},
+		// Weekdays only: Saturday the 3rd is not quiet
+		{"mon-fri 09:00-18:00", at(2, 12, 0), at(2, 18, 0)},
+		{"mon-fri 09:00-18:00", at(3, 12, 0), time.Time{}},
+		// Past midnight, from the evening side and the morning side
+		{"22:00-06:00", at(2, 23, 0), at(3, 6, 0)},
+		{"22:00-06:00", at(3, 5, 30), at(3, 6, 0)},
+		{"22:00-06:00", at(3, 12, 0), time.Time{}},
+		// The day is the day a window starts: Friday night runs into Saturday
+		{"fri 22:00-06:00", at(3, 5, 0), at(3, 6, 0)},
+		{"fri 22:00-06:00", at(4, 5, 0), time.Time{}},
+		// Back-to-back windows count as one
+		{"09:00-12:00, 12:00-18:00", at(2, 10, 0), at(2, 18, 0)},
+		{"sat-sun 00:00-24:00", at(3, 10, 0), at(5, 0, 0)},
+	}
+	for _, tt := range tests {
+		quiet, err := ParseQuietHours(tt.spec)
+		if err != nil {
+			t.Errorf("ParseQuietHours(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := quiet.Until(tt.now); !got.Equal(tt.want) {
+			t.Errorf("%q at %s: Until = %v, want %v", tt.spec, tt.now.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestParseQuietHours_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"9-18",
+		"09:00",
+		"09:00-25:00",
+		"09:60-18:00",
+		"09:00-09:00",
+		"24:00-06:00",
+		"weekdays 09:00-18:00",
+		"mon fri 09:00-18:00",
+	} {
+		if _, err := ParseQuietHours(spec); err == nil {
+			t.Errorf("Expected ParseQuietHours(%q) to fail", spec)
+		}
+	}
+}
+
+func TestConfig_QuietHoursRoundTrip(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+	if err := UpdateGlobalQuietHoursWithOptions(opts, "25:00-06:00"); err == nil {
+		t.Error("Expected invalid quiet hours to be rejected")
+	}
+	if err := UpdateGlobalQuietHoursWithOptions(opts, "mon-fri 09:00-18:00"); err != nil {
+		t.Fatalf("UpdateGlobalQuietHoursWithOptions failed: %v", err)
+	}
+
+	quiet, err := GetGlobalQuietHoursWithOptions(opts)
+	if err != nil || quiet == nil || quiet.String() != "mon-fri 09:00-18:00" {
+		t.Fatalf("GetGlobalQuietHoursWithOptions = %v, %v; want the saved quiet hours", quiet, err)
+	}
+
+	if err := UpdateGlobalQuietHoursWithOptions(opts, ""); err != nil {
+		t.Fatalf("clearing quiet hours failed: %v", err)
+	}
+	if quiet, err := GetGlobalQuietHoursWithOptions(opts); err != nil || quiet != nil {
+		t.Errorf("Expected no quiet hours after clearing, got %v, %v", quiet, err)
+	}
+}
@@ -0,0 +1,160 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	rateLimitFile = "rate_limit.json"
+
+	// RateLimitProbeWindow is how long one run gets to try the provider alone
+	// once a shared rate limit has passed, before the other runs follow
+	RateLimitProbeWindow = time.Minute
+)
+
+// RateLimitEntry is the rate limit agent runs last hit with one provider
+type RateLimitEntry struct {
+	Until      time.Time `json:"until"`                 // No run starts an iteration before this
+	Attempts   int       `json:"attempts"`              // Rate limits in a row across all runs, for a shared backoff
+	ProbePID   int       `json:"probe_pid,omitempty"`   // Run trying the provider first once Until passed
+	ProbeUntil time.Time `json:"probe_until,omitempty"` // When the other runs stop waiting on the probe
+	LimitedAt  time.Time `json:"limited_at"`            // When a run last hit the limit
+}
+
+// SharedRateLimit is the rate limit state every agent run on the machine
+// consults and updates, since runs in different projects share an account.
+// It is stored next to the global config in rate_limit.json, keyed by
+// provider, and guarded by a file lock.
+type SharedRateLimit struct {
+	provider string
+	path     string
+}
+
+// OpenSharedRateLimitWithOptions returns the shared rate limit state of a provider
+func OpenSharedRateLimitWithOptions(opts ConfigOptions, provider string) (*SharedRateLimit, error) {
+	configHome := opts.ConfigHome
+	if configHome == "" {
+		var err error
+		if configHome, err = os.UserHomeDir(); err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+	}
+	return &SharedRateLimit{
+		provider: provider,
+		path:     filepath.Join(configHome, opts.JuggleDirName, rateLimitFile),
+	}, nil
+}
+
+// Attempts returns how many rate limits in a row runs have hit with the provider
+func (s *SharedRateLimit) Attempts() (int, error) {
+	var attempts int
+	err := s.update(func(entries map[string]*RateLimitEntry) bool {
+		if entry := entries[s.provider]; entry != nil {
+			attempts = entry.Attempts
+		}
+		return false
+	})
+	return attempts, err
+}
+
+// Wait returns how long the run with the given PID should wait before
+// starting an iteration: until the shared rate limit passes, then while
+// another run probes the provider. A run that finds the limit passed and no
+// probe under way becomes the probe itself and gets 0.
+func (s *SharedRateLimit) Wait(now time.Time, pid int) (time.Duration, error) {
+	var wait time.Duration
+	err := s.update(func(entries map[string]*RateLimitEntry) bool {
+		entry := entries[s.provider]
+		switch {
+		case entry == nil || entry.Attempts == 0:
+			return false
+		case now.Before(entry.Until):
+			wait = entry.Until.Sub(now)
+			return false
+		case entry.ProbePID != 0 && entry.ProbePID != pid && now.Before(entry.ProbeUntil):
+			wait = entry.ProbeUntil.Sub(now)
+			return false
+		case entry.ProbePID == pid && now.Before(entry.ProbeUntil):
+			return false
+		}
+		entry.ProbePID = pid
+		entry.ProbeUntil = now.Add(RateLimitProbeWindow)
+		return true
+	})
+	return wait, err
+}
+
+// RecordLimited notes a rate limit that makes runs wait for wait from now.
+// A later limit another run already recorded is kept.
+func (s *SharedRateLimit) RecordLimited(now time.Time, wait time.Duration) error {
+	return s.update(func(entries map[string]*RateLimitEntry) bool {
+		entry := entries[s.provider]
+		if entry == nil {
+			entry = &RateLimitEntry{}
+			entries[s.provider] = entry
+		}
+		entry.Attempts++
+		if until := now.Add(wait); until.After(entry.Until) {
+			entry.Until = until
+		}
+		entry.ProbePID = 0
+		entry.ProbeUntil = time.Time{}
+		entry.LimitedAt = now
+		return true
+	})
+}
+
+// RecordSuccess clears the rate limit after a run that started at startedAt
+// got through, unless another run hit a limit since it started
+func (s *SharedRateLimit) RecordSuccess(startedAt time.Time) error {
+	return s.update(func(entries map[string]*RateLimitEntry) bool {
+		entry := entries[s.provider]
+		if entry == nil || entry.LimitedAt.After(startedAt) {
+			return false
+		}
+		delete(entries, s.provider)
+		return true
+	})
+}
+
+// update runs fn on the saved entries under the file lock, and saves them
+// when fn reports a change
+func (s *SharedRateLimit) update(fn func(entries map[string]*RateLimitEntry) bool) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	fileLock := flock.New(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return fmt.Errorf("failed to lock rate limit state: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	entries := make(map[string]*RateLimitEntry)
+	data, err := os.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("failed to parse rate limit state: %w", err)
+		}
+	}
+
+	if !fn(entries) {
+		return nil
+	}
+	data, err = json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate limit state: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rate limit state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSharedRateLimit(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+	first, err := OpenSharedRateLimitWithOptions(opts, "claude")
+	if err != nil {
+		t.Fatalf("failed to open shared rate limit: %v", err)
+	}
+	second, _ := OpenSharedRateLimitWithOptions(opts, "claude")
+	other, _ := OpenSharedRateLimitWithOptions(opts, "opencode")
+
+	now := time.Now()
+	if wait, err := second.Wait(now, 2); err != nil || wait != 0 {
+		t.Fatalf("Expected no wait without a rate limit, got %v (err %v)", wait, err)
+	}
+
+	if err := first.RecordLimited(now, 10*time.Minute); err != nil {
+		t.Fatalf("RecordLimited failed: %v", err)
+	}
+	if wait, _ := second.Wait(now, 2); wait != 10*time.Minute {
+		t.Errorf("Expected the other run to wait out the limit, got %v", wait)
+	}
+	if wait, _ := other.Wait(now, 3); wait != 0 {
+		t.Errorf("Expected another provider not to wait, got %v", wait)
+	}
+	if attempts, _ := second.Attempts(); attempts != 1 {
+		t.Errorf("Expected 1 shared attempt, got %d", attempts)
+	}
+
+	// Once the limit passes, one run probes while the rest keep waiting
+	later := now.Add(11 * time.Minute)
+	if wait, _ := first.Wait(later, 1); wait != 0 {
+		t.Errorf("Expected the first run to probe, got a wait of %v", wait)
+	}
+	if wait, _ := second.Wait(later, 2); wait != RateLimitProbeWindow {
+		t.Errorf("Expected the second run to wait on the probe, got %v", wait)
+	}
+
+	if err := first.RecordSuccess(later); err != nil {
+		t.Fatalf("RecordSuccess failed: %v", err)
+	}
+	if wait, _ := second.Wait(later, 2); wait != 0 {
+		t.Errorf("Expected no wait after the probe got through, got %v", wait)
+	}
+	if attempts, _ := second.Attempts(); attempts != 0 {
+		t.Errorf("Expected the shared attempts reset, got %d", attempts)
+	}
+}
+
+func TestSharedRateLimit_SuccessKeepsLaterLimit(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+	limit, _ := OpenSharedRateLimitWithOptions(opts, "claude")
+
+	started := time.Now()
+	if err := limit.RecordLimited(started.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Fatalf("RecordLimited failed: %v", err)
+	}
+	// A run that started before another run's limit doesn't clear it
+	if err := limit.RecordSuccess(started); err != nil {
+		t.Fatalf("RecordSuccess failed: %v", err)
+	}
+	if attempts, _ := limit.Attempts(); attempts != 1 {
+		t.Errorf("Expected the later limit kept, got %d attempts", attempts)
+	}
+}
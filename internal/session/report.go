@@ -0,0 +1,68 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const reportsDir = "reports"
+
+// ReportsPath returns the directory holding a session's run reports:
+// .juggle/sessions/<id>/reports/
+func (s *SessionStore) ReportsPath(id string) string {
+	return filepath.Join(s.sessionPath(id), reportsDir)
+}
+
+// SaveReport writes the report of a run that started at startedAt, named by
+// that time (and the ball, for single-ball runs) so reports sort oldest
+// first. Returns the report's path.
+func (s *SessionStore) SaveReport(id, ballID string, startedAt time.Time, content string) (string, error) {
+	dir := s.ReportsPath(id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	name := startedAt.Format("2006-01-02T15-04-05")
+	if ballID != "" {
+		name += "-" + ballID
+	}
+	path := filepath.Join(dir, name+".md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return path, nil
+}
+
+// ListReports returns the paths of a session's run reports, oldest first
+func (s *SessionStore) ListReports(id string) ([]string, error) {
+	entries, err := os.ReadDir(s.ReportsPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read reports directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		paths = append(paths, filepath.Join(s.ReportsPath(id), entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// LatestReport returns the path of a session's most recent run report, or ""
+// if it has none
+func (s *SessionStore) LatestReport(id string) (string, error) {
+	paths, err := s.ListReports(id)
+	if err != nil || len(paths) == 0 {
+		return "", err
+	}
+	return paths[len(paths)-1], nil
+}
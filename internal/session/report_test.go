@@ -0,0 +1,42 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReports(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+
+	if latest, err := store.LatestReport("s1"); err != nil || latest != "" {
+		t.Fatalf("Expected no report yet, got %q (%v)", latest, err)
+	}
+
+	start := time.Date(2026, 3, 4, 9, 30, 0, 0, time.Local)
+	first, err := store.SaveReport("s1", "", start, "# first\n")
+	if err != nil {
+		t.Fatalf("SaveReport failed: %v", err)
+	}
+	if want := filepath.Join(store.ReportsPath("s1"), "2026-03-04T09-30-00.md"); first != want {
+		t.Errorf("Expected report at %s, got %s", want, first)
+	}
+	second, err := store.SaveReport("s1", "ball-1", start.Add(time.Hour), "# second\n")
+	if err != nil {
+		t.Fatalf("SaveReport failed: %v", err)
+	}
+
+	paths, err := store.ListReports("s1")
+	if err != nil {
+		t.Fatalf("ListReports failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != first || paths[1] != second {
+		t.Errorf("Expected reports oldest first, got %v", paths)
+	}
+	if latest, _ := store.LatestReport("s1"); latest != second {
+		t.Errorf("Expected latest report %s, got %s", second, latest)
+	}
+}
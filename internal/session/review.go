@@ -0,0 +1,32 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const reviewFile = "review.md"
+
+// ReviewFilePath returns the path to the review written at the end of a
+// session's agent run: .juggle/sessions/<id>/review.md, or review-<ball-id>.md
+// for single-ball runs so parallel runs don't overwrite each other's
+func (s *SessionStore) ReviewFilePath(id, ballID string) string {
+	name := reviewFile
+	if ballID != "" {
+		name = "review-" + ballID + ".md"
+	}
+	return filepath.Join(s.sessionPath(id), name)
+}
+
+// SaveReview writes the review for the session (or one of its balls),
+// replacing the previous run's
+func (s *SessionStore) SaveReview(id, ballID, content string) error {
+	if err := os.MkdirAll(s.sessionPath(id), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if err := os.WriteFile(s.ReviewFilePath(id, ballID), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write review: %w", err)
+	}
+	return nil
+}
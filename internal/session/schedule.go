@@ -0,0 +1,142 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ohare93/juggle/internal/cronexpr"
+)
+
+const scheduleFile = "schedule.json"
+
+// Schedule runs the agent on a session at the times of a cron expression.
+// The supervisor launches each due run as a daemon.
+//
+// Schedules are stored in .juggle/sessions/<id>/schedule.json.
+type Schedule struct {
+	SessionID  string     `json:"session_id"`      // Session to run ("all" for every ball)
+	Cron       string     `json:"cron"`            // Five-field cron expression in local time
+	Iterations int        `json:"iterations"`      // Maximum iterations per run
+	Trust      bool       `json:"trust,omitempty"` // Run with full permissions
+	Model      string     `json:"model,omitempty"` // Model for the runs (default: per ball)
+	CreatedAt  time.Time  `json:"created_at"`
+	NextRun    time.Time  `json:"next_run"`              // When the next run is due
+	LastRun    *time.Time `json:"last_run,omitempty"`    // When a run was last due
+	LastResult string     `json:"last_result,omitempty"` // What happened then, e.g. "launched"
+}
+
+// NewSchedule validates the cron expression and works out the first run
+func NewSchedule(sessionID, cron string, iterations int, now time.Time) (*Schedule, error) {
+	s := &Schedule{SessionID: sessionID, Cron: cron, Iterations: iterations, CreatedAt: now}
+	if err := s.Advance(now); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Due reports whether the next run's time has come
+func (s *Schedule) Due(now time.Time) bool {
+	return !s.NextRun.IsZero() && !now.Before(s.NextRun)
+}
+
+// Advance sets the next run to the first time after now the expression fires
+func (s *Schedule) Advance(now time.Time) error {
+	expr, err := cronexpr.Parse(s.Cron)
+	if err != nil {
+		return err
+	}
+	next := expr.Next(now.Local())
+	if next.IsZero() {
+		return fmt.Errorf("cron expression %q never fires", s.Cron)
+	}
+	s.NextRun = next
+	return nil
+}
+
+// Fired records what happened to a due run and moves on to the next one
+func (s *Schedule) Fired(now time.Time, result string) error {
+	s.LastRun = &now
+	s.LastResult = result
+	return s.Advance(now)
+}
+
+// scheduleFilePath returns the path to a session's schedule
+func (s *SessionStore) scheduleFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), scheduleFile)
+}
+
+// SaveSchedule writes the session's schedule, replacing any previous one
+func (s *SessionStore) SaveSchedule(id string, sched *Schedule) error {
+	if err := os.MkdirAll(s.sessionPath(id), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(sched, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	// Write then rename so the supervisor never reads a half-written schedule
+	path := s.scheduleFilePath(id)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write schedule: %w", err)
+	}
+	return nil
+}
+
+// LoadSchedule returns the session's schedule, or nil if it has none
+func (s *SessionStore) LoadSchedule(id string) (*Schedule, error) {
+	data, err := os.ReadFile(s.scheduleFilePath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+	var sched Schedule
+	if err := json.Unmarshal(data, &sched); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+	return &sched, nil
+}
+
+// RemoveSchedule deletes the session's schedule; it is not an error if there is none
+func (s *SessionStore) RemoveSchedule(id string) error {
+	err := os.Remove(s.scheduleFilePath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove schedule: %w", err)
+	}
+	return nil
+}
+
+// ListSchedules returns every schedule in the project, keyed by storage ID
+// (the session directory name)
+func (s *SessionStore) ListSchedules() (map[string]*Schedule, error) {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+
+	schedules := make(map[string]*Schedule)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		sched, err := s.LoadSchedule(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if sched != nil {
+			schedules[entry.Name()] = sched
+		}
+	}
+	return schedules, nil
+}
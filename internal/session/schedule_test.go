@@ -0,0 +1,105 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_DueAndFired(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 0, 0, time.Local)
+	sched, err := NewSchedule("feature", "0 2 * * *", 5, now)
+	if err != nil {
+		t.Fatalf("NewSchedule failed: %v", err)
+	}
+	if want := time.Date(2026, 1, 3, 2, 0, 0, 0, time.Local); !sched.NextRun.Equal(want) {
+		t.Fatalf("NextRun = %v, want %v", sched.NextRun, want)
+	}
+	if sched.Due(now) {
+		t.Error("expected the schedule not to be due before 02:00")
+	}
+
+	// A poll a few minutes late still fires, then moves on to the next night
+	late := time.Date(2026, 1, 3, 2, 4, 0, 0, time.Local)
+	if !sched.Due(late) {
+		t.Fatal("expected the schedule to be due after 02:00")
+	}
+	if err := sched.Fired(late, "launched"); err != nil {
+		t.Fatalf("Fired failed: %v", err)
+	}
+	if sched.LastRun == nil || !sched.LastRun.Equal(late) || sched.LastResult != "launched" {
+		t.Errorf("expected the run to be recorded, got %+v", sched)
+	}
+	if want := time.Date(2026, 1, 4, 2, 0, 0, 0, time.Local); !sched.NextRun.Equal(want) {
+		t.Errorf("NextRun = %v, want %v", sched.NextRun, want)
+	}
+
+	if _, err := NewSchedule("feature", "0 25 * * *", 5, now); err == nil {
+		t.Error("expected an invalid cron expression to be rejected")
+	}
+	if _, err := NewSchedule("feature", "0 0 31 2 *", 5, now); err == nil {
+		t.Error("expected an expression that never fires to be rejected")
+	}
+}
+
+func TestSchedule_SaveLoadList(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSessionStore failed: %v", err)
+	}
+
+	if sched, err := store.LoadSchedule("feature"); err != nil || sched != nil {
+		t.Fatalf("expected no schedule, got %+v, %v", sched, err)
+	}
+
+	now := time.Now()
+	feature, _ := NewSchedule("feature", "@daily", 5, now)
+	all, _ := NewSchedule("all", "0 * * * *", 2, now)
+	if err := store.SaveSchedule("feature", feature); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+	if err := store.SaveSchedule("_all", all); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+
+	got, err := store.LoadSchedule("feature")
+	if err != nil || got == nil || got.Cron != "@daily" || got.Iterations != 5 || !got.NextRun.Equal(feature.NextRun) {
+		t.Fatalf("LoadSchedule = %+v, %v; want the saved schedule", got, err)
+	}
+
+	schedules, err := store.ListSchedules()
+	if err != nil {
+		t.Fatalf("ListSchedules failed: %v", err)
+	}
+	if len(schedules) != 2 || schedules["_all"] == nil || schedules["_all"].SessionID != "all" {
+		t.Errorf("expected schedules keyed by storage ID, got %+v", schedules)
+	}
+
+	if err := store.RemoveSchedule("feature"); err != nil {
+		t.Fatalf("RemoveSchedule failed: %v", err)
+	}
+	if sched, _ := store.LoadSchedule("feature"); sched != nil {
+		t.Error("expected the schedule to be removed")
+	}
+	if err := store.RemoveSchedule("feature"); err != nil {
+		t.Errorf("removing a missing schedule should succeed, got %v", err)
+	}
+}
+
+func TestFindStaleStorage_KeepsScheduledStorage(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSessionStore failed: %v", err)
+	}
+	sched, _ := NewSchedule("all", "@monthly", 3, time.Now())
+	if err := store.SaveSchedule("_all", sched); err != nil {
+		t.Fatalf("SaveSchedule failed: %v", err)
+	}
+
+	stale, err := store.FindStaleStorage(ReapOptions{Idle: time.Hour, Now: time.Now().Add(48 * time.Hour)})
+	if err != nil {
+		t.Fatalf("FindStaleStorage failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("expected scheduled storage to be kept, got %+v", stale)
+	}
+}
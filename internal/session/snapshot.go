@@ -0,0 +1,166 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const snapshotsFile = "snapshots.jsonl"
+
+// BallStateSnapshot records a ball's state at the time of a snapshot
+type BallStateSnapshot struct {
+	ID            string    `json:"id"`
+	State         BallState `json:"state"`
+	BlockedReason string    `json:"blocked_reason,omitempty"`
+}
+
+// IterationSnapshot records the VCS revision and ball states taken
+// immediately before an agent iteration ran, or, when Completed is set,
+// right after it succeeded.
+//
+// Snapshots are stored in .juggle/sessions/<id>/snapshots.jsonl and
+// cover the most recent agent run for the session.
+type IterationSnapshot struct {
+	Iteration int                 `json:"iteration"`
+	Revision  string              `json:"revision,omitempty"` // Empty if the VCS revision could not be determined
+	Balls     []BallStateSnapshot `json:"balls"`
+	Completed bool                `json:"completed,omitempty"` // Taken after the iteration succeeded rather than before it started
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// NewIterationSnapshot creates a snapshot of the given balls at the given revision
+func NewIterationSnapshot(iteration int, revision string, balls []*Ball) *IterationSnapshot {
+	snap := &IterationSnapshot{
+		Iteration: iteration,
+		Revision:  revision,
+		Balls:     make([]BallStateSnapshot, 0, len(balls)),
+		CreatedAt: time.Now(),
+	}
+	for _, ball := range balls {
+		snap.Balls = append(snap.Balls, BallStateSnapshot{
+			ID:            ball.ID,
+			State:         ball.State,
+			BlockedReason: ball.BlockedReason,
+		})
+	}
+	return snap
+}
+
+// FindSnapshot returns the snapshot taken before the given iteration, or nil if none exists
+func FindSnapshot(snapshots []*IterationSnapshot, iteration int) *IterationSnapshot {
+	for _, snap := range snapshots {
+		if snap.Iteration == iteration && !snap.Completed {
+			return snap
+		}
+	}
+	return nil
+}
+
+// FindCompletedSnapshot returns the snapshot taken after the given iteration
+// succeeded, or nil if it didn't
+func FindCompletedSnapshot(snapshots []*IterationSnapshot, iteration int) *IterationSnapshot {
+	for _, snap := range snapshots {
+		if snap.Iteration == iteration && snap.Completed {
+			return snap
+		}
+	}
+	return nil
+}
+
+// snapshotsFilePath returns the path to a session's snapshots file
+func (s *SessionStore) snapshotsFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), snapshotsFile)
+}
+
+// AppendSnapshot appends an iteration snapshot to a session's snapshots file
+func (s *SessionStore) AppendSnapshot(id string, snap *IterationSnapshot) error {
+	if err := os.MkdirAll(s.sessionPath(id), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	f, err := os.OpenFile(s.snapshotsFilePath(id), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshots file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshots loads all iteration snapshots for a session, in iteration order
+func (s *SessionStore) LoadSnapshots(id string) ([]*IterationSnapshot, error) {
+	data, err := os.ReadFile(s.snapshotsFilePath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*IterationSnapshot{}, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshots file: %w", err)
+	}
+
+	snapshots := make([]*IterationSnapshot, 0)
+	for _, line := range splitLines(string(data)) {
+		if len(line) == 0 {
+			continue
+		}
+		var snap IterationSnapshot
+		if err := json.Unmarshal([]byte(line), &snap); err != nil {
+			// Skip malformed records
+			continue
+		}
+		snapshots = append(snapshots, &snap)
+	}
+
+	return snapshots, nil
+}
+
+// ClearSnapshots removes all iteration snapshots for a session
+func (s *SessionStore) ClearSnapshots(id string) error {
+	err := os.Remove(s.snapshotsFilePath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snapshots file: %w", err)
+	}
+	return nil
+}
+
+// TruncateSnapshots drops all snapshots taken after keep, keeping keep itself
+// so the run can be rolled back to it again.
+func (s *SessionStore) TruncateSnapshots(id string, keep *IterationSnapshot) error {
+	snapshots, err := s.LoadSnapshots(id)
+	if err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, snap := range snapshots {
+		if snap.Iteration > keep.Iteration {
+			continue
+		}
+		// Before an iteration comes before its completion
+		if snap.Iteration == keep.Iteration && snap.Completed && !keep.Completed {
+			continue
+		}
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+		buf = append(buf, data...)
+		buf = append(buf, '\n')
+	}
+
+	if err := os.WriteFile(s.snapshotsFilePath(id), buf, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshots file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"testing"
+)
+
+func TestIterationSnapshots_AppendLoadTruncate(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+
+	pending := &Ball{ID: "proj-1", State: StatePending}
+	blocked := &Ball{ID: "proj-2", State: StateBlocked, BlockedReason: "needs API key"}
+
+	for i := 1; i <= 3; i++ {
+		snap := NewIterationSnapshot(i, "rev"+string(rune('0'+i)), []*Ball{pending, blocked})
+		if err := store.AppendSnapshot("test-session", snap); err != nil {
+			t.Fatalf("AppendSnapshot failed: %v", err)
+		}
+	}
+
+	snapshots, err := store.LoadSnapshots("test-session")
+	if err != nil {
+		t.Fatalf("LoadSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("Expected 3 snapshots, got %d", len(snapshots))
+	}
+
+	snap := FindSnapshot(snapshots, 2)
+	if snap == nil {
+		t.Fatal("Expected snapshot for iteration 2")
+	}
+	if snap.Revision != "rev2" {
+		t.Errorf("Expected revision 'rev2', got '%s'", snap.Revision)
+	}
+	if len(snap.Balls) != 2 || snap.Balls[1].BlockedReason != "needs API key" {
+		t.Errorf("Expected ball states to be recorded, got %+v", snap.Balls)
+	}
+
+	if err := store.TruncateSnapshots("test-session", snap); err != nil {
+		t.Fatalf("TruncateSnapshots failed: %v", err)
+	}
+	snapshots, _ = store.LoadSnapshots("test-session")
+	if len(snapshots) != 2 {
+		t.Errorf("Expected 2 snapshots after truncate, got %d", len(snapshots))
+	}
+	if FindSnapshot(snapshots, 3) != nil {
+		t.Error("Expected snapshot for iteration 3 to be dropped")
+	}
+
+	if err := store.ClearSnapshots("test-session"); err != nil {
+		t.Fatalf("ClearSnapshots failed: %v", err)
+	}
+	snapshots, err = store.LoadSnapshots("test-session")
+	if err != nil {
+		t.Fatalf("LoadSnapshots after clear failed: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots after clear, got %d", len(snapshots))
+	}
+}
+
+func TestIterationSnapshots_Completed(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+
+	ball := &Ball{ID: "proj-1", State: StateInProgress}
+	for i := 1; i <= 2; i++ {
+		before := NewIterationSnapshot(i, "before"+string(rune('0'+i)), []*Ball{ball})
+		after := NewIterationSnapshot(i, "after"+string(rune('0'+i)), []*Ball{ball})
+		after.Completed = true
+		for _, snap := range []*IterationSnapshot{before, after} {
+			if err := store.AppendSnapshot("test-session", snap); err != nil {
+				t.Fatalf("AppendSnapshot failed: %v", err)
+			}
+		}
+	}
+
+	snapshots, _ := store.LoadSnapshots("test-session")
+	if snap := FindSnapshot(snapshots, 2); snap == nil || snap.Revision != "before2" {
+		t.Errorf("FindSnapshot(2) = %+v, want the snapshot from before iteration 2", snap)
+	}
+	after1 := FindCompletedSnapshot(snapshots, 1)
+	if after1 == nil || after1.Revision != "after1" {
+		t.Fatalf("FindCompletedSnapshot(1) = %+v, want the snapshot from after iteration 1", after1)
+	}
+
+	// Rolling back to after iteration 1 keeps both of its snapshots
+	if err := store.TruncateSnapshots("test-session", after1); err != nil {
+		t.Fatalf("TruncateSnapshots failed: %v", err)
+	}
+	snapshots, _ = store.LoadSnapshots("test-session")
+	if len(snapshots) != 2 || FindSnapshot(snapshots, 2) != nil {
+		t.Errorf("Expected only iteration 1's snapshots to remain, got %d", len(snapshots))
+	}
+
+	// Rolling back to before iteration 1 drops its completion
+	if err := store.TruncateSnapshots("test-session", FindSnapshot(snapshots, 1)); err != nil {
+		t.Fatalf("TruncateSnapshots failed: %v", err)
+	}
+	snapshots, _ = store.LoadSnapshots("test-session")
+	if len(snapshots) != 1 || FindCompletedSnapshot(snapshots, 1) != nil {
+		t.Errorf("Expected only the snapshot from before iteration 1 to remain, got %d", len(snapshots))
+	}
+}
@@ -0,0 +1,37 @@
+package session
+
+import "fmt"
+
+// NewChildBall creates a pending ball split from parent. It inherits the
+// parent's project, sessions, kind and model size, and its priority unless
+// one is given.
+func NewChildBall(parent *Ball, title string, priority Priority) (*Ball, error) {
+	if priority == "" {
+		priority = parent.Priority
+	}
+	child, err := NewBall(parent.WorkingDir, title, priority)
+	if err != nil {
+		return nil, err
+	}
+	child.Tags = append([]string{}, parent.Tags...)
+	child.Kind = parent.Kind
+	child.ModelSize = parent.ModelSize
+	child.Parent = parent.ID
+	child.Context = fmt.Sprintf("Split from %s: %s", parent.ID, parent.Title)
+	if parent.Context != "" {
+		child.Context += "\n\n" + parent.Context
+	}
+	return child, nil
+}
+
+// MarkEpic marks the ball as split into the given children. It depends on
+// them, so agents only come back to it, to check its own acceptance criteria,
+// once every child is done.
+func (b *Ball) MarkEpic(childIDs []string) {
+	b.Epic = true
+	b.Plan = nil
+	for _, id := range childIDs {
+		b.AddDependency(id)
+	}
+	b.UpdateActivity()
+}
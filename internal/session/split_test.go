@@ -0,0 +1,64 @@
+package session
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewChildBall(t *testing.T) {
+	dir := t.TempDir()
+	parent, err := NewBall(dir, "Add session archiving", PriorityHigh)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	parent.Tags = []string{"my-feature"}
+	parent.Kind = BallKindDocs
+	parent.ModelSize = ModelSizeSmall
+	parent.Context = "Users asked for it"
+
+	child, err := NewChildBall(parent, "Write the migration", "")
+	if err != nil {
+		t.Fatalf("NewChildBall failed: %v", err)
+	}
+	if child.Parent != parent.ID || child.Priority != PriorityHigh || child.State != StatePending {
+		t.Errorf("child = parent %q priority %q state %q, want %q high pending", child.Parent, child.Priority, child.State, parent.ID)
+	}
+	if child.Kind != BallKindDocs || child.ModelSize != ModelSizeSmall || child.WorkingDir != dir {
+		t.Errorf("child should inherit kind, model size and project, got %q %q %q", child.Kind, child.ModelSize, child.WorkingDir)
+	}
+	if !reflect.DeepEqual(child.Tags, parent.Tags) {
+		t.Errorf("child tags = %v, want %v", child.Tags, parent.Tags)
+	}
+	child.Tags[0] = "other"
+	if parent.Tags[0] != "my-feature" {
+		t.Error("child tags should not share the parent's slice")
+	}
+	if !strings.Contains(child.Context, parent.ID) || !strings.Contains(child.Context, "Users asked for it") {
+		t.Errorf("child context should name the parent and keep its context, got %q", child.Context)
+	}
+
+	low, err := NewChildBall(parent, "Document it", PriorityLow)
+	if err != nil {
+		t.Fatalf("NewChildBall failed: %v", err)
+	}
+	if low.Priority != PriorityLow {
+		t.Errorf("Priority = %q, want the given low", low.Priority)
+	}
+}
+
+func TestMarkEpic(t *testing.T) {
+	ball, err := NewBall(t.TempDir(), "Add session archiving", PriorityMedium)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	ball.SetPlan([]string{"Do it all at once"})
+
+	ball.MarkEpic([]string{"proj-1", "proj-2"})
+	if !ball.Epic || len(ball.Plan) != 0 {
+		t.Errorf("Epic = %v, Plan = %v; want an epic without the old plan", ball.Epic, ball.Plan)
+	}
+	if !reflect.DeepEqual(ball.DependsOn, []string{"proj-1", "proj-2"}) {
+		t.Errorf("DependsOn = %v, want the children", ball.DependsOn)
+	}
+}
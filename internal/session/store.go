@@ -1,15 +1,10 @@
 package session
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
-
-	"github.com/gofrs/flock"
 )
 
 const (
@@ -33,26 +28,24 @@ func DefaultStoreConfig() StoreConfig {
 
 // Store handles persistence of balls in a project directory.
 //
-// Store manages balls stored in JSONL format at .juggle/balls.jsonl (active)
-// and .juggle/archive/balls.jsonl (completed). It provides thread-safe
-// CRUD operations using file locking.
+// Store keeps its balls in a BallStore backend, by default a JSONLBallStore
+// at .juggle/balls.jsonl (active) and .juggle/archive/balls.jsonl
+// (completed), and adds the lookups callers need on top of it.
 //
 // Key features:
-//   - JSONL format for append-friendly version control
-//   - File locking for concurrent access safety
-//   - Atomic writes via temp file + rename pattern
+//   - Pluggable backends through the BallStore interface
 //   - Ball resolution by full ID, short ID, or prefix
 //   - Worktree-aware: resolves to main repo when in a git worktree
 //
-// Create a Store with NewStore or NewStoreWithConfig:
+// Create a Store with NewStore or NewStoreWithConfig, or NewStoreWithBackend
+// for another backend:
 //
 //	store, err := session.NewStore("/path/to/project")
 //	balls, err := store.LoadBalls()
 type Store struct {
-	projectDir  string
-	ballsPath   string
-	archivePath string
-	config      StoreConfig
+	projectDir string
+	balls      BallStore
+	config     StoreConfig
 }
 
 // ProjectDir returns the project directory for this store
@@ -60,6 +53,11 @@ func (s *Store) ProjectDir() string {
 	return s.projectDir
 }
 
+// Backend returns the BallStore the store keeps its balls in
+func (s *Store) Backend() BallStore {
+	return s.balls
+}
+
 // NewStore creates a new store for the given project directory
 func NewStore(projectDir string) (*Store, error) {
 	return NewStoreWithConfig(projectDir, DefaultStoreConfig())
@@ -83,288 +81,139 @@ func NewStoreWithConfig(projectDir string, config StoreConfig) (*Store, error) {
 		storageDir = projectDir
 	}
 
-	storePath := filepath.Join(storageDir, config.JuggleDirName)
-	ballsPath := filepath.Join(storePath, ballsFile)
-	archivePath := filepath.Join(storePath, archiveDir, archiveBallsFile)
-
-	// Ensure directories exist
-	if err := os.MkdirAll(storePath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create %s directory: %w", config.JuggleDirName, err)
-	}
-
-	archiveDirPath := filepath.Join(storePath, archiveDir)
-	if err := os.MkdirAll(archiveDirPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	balls, err := NewJSONLBallStore(filepath.Join(storageDir, config.JuggleDirName))
+	if err != nil {
+		return nil, err
 	}
 
 	return &Store{
-		projectDir:  projectDir,
-		ballsPath:   ballsPath,
-		archivePath: archivePath,
-		config:      config,
+		projectDir: projectDir,
+		balls:      balls,
+		config:     config,
 	}, nil
 }
 
-// acquireFileLock acquires an exclusive lock on a file
-// Returns the flock and cleanup function. The cleanup function should be deferred.
-func acquireFileLock(path string) (*flock.Flock, func(), error) {
-	lockPath := path + ".lock"
-	fileLock := flock.New(lockPath)
-
-	// Acquire exclusive lock (blocking)
-	if err := fileLock.Lock(); err != nil {
-		return nil, nil, fmt.Errorf("failed to acquire lock on %s: %w", lockPath, err)
+// NewStoreWithBackend creates a store for the given project directory that
+// keeps its balls in the given backend, e.g. a MemoryBallStore in tests
+func NewStoreWithBackend(projectDir string, balls BallStore) *Store {
+	return &Store{
+		projectDir: projectDir,
+		balls:      balls,
+		config:     DefaultStoreConfig(),
 	}
+}
 
-	cleanup := func() {
-		fileLock.Unlock()
+// Cached returns a store over the same balls that reads them once and keeps
+// changes in memory until Flush (see CachedBallStore)
+func (s *Store) Cached() *Store {
+	if _, ok := s.balls.(*CachedBallStore); ok {
+		return s
+	}
+	return &Store{
+		projectDir: s.projectDir,
+		balls:      NewCachedBallStore(s.balls),
+		config:     s.config,
 	}
+}
 
-	return fileLock, cleanup, nil
+// Flush writes the changes a cached store holds; other stores write as they
+// go, so for them it does nothing
+func (s *Store) Flush() error {
+	if cached, ok := s.balls.(*CachedBallStore); ok {
+		return cached.Flush()
+	}
+	return nil
 }
 
-// AppendBall adds a new ball to the JSONL file
-func (s *Store) AppendBall(ball *Ball) error {
-	data, err := json.Marshal(ball)
-	if err != nil {
-		return fmt.Errorf("failed to marshal ball: %w", err)
+// Batch runs fn with a cached copy of the store and writes its changes once
+// fn returns, even when it fails, as the changes would have been written one
+// by one without the cache
+func (s *Store) Batch(fn func(store *Store) error) error {
+	cached := s.Cached()
+	err := fn(cached)
+	if flushErr := cached.Flush(); err == nil {
+		err = flushErr
 	}
+	return err
+}
 
-	// Acquire file lock
-	_, unlock, err := acquireFileLock(s.ballsPath)
+// BallIDs returns the IDs of the active and archived balls, without loading
+// them when the backend can list IDs alone
+func (s *Store) BallIDs() ([]string, error) {
+	if lister, ok := s.balls.(BallIDLister); ok {
+		return lister.BallIDs()
+	}
+	active, err := s.balls.LoadBalls()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer unlock()
-
-	// Open file in append mode
-	f, err := os.OpenFile(s.ballsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	archived, err := s.balls.LoadArchivedBalls()
 	if err != nil {
-		return fmt.Errorf("failed to open balls file: %w", err)
+		return nil, err
 	}
-	defer f.Close()
-
-	if _, err := f.Write(data); err != nil {
-		return fmt.Errorf("failed to write ball: %w", err)
+	ids := make([]string, 0, len(active)+len(archived))
+	for _, ball := range append(active, archived...) {
+		ids = append(ids, ball.ID)
 	}
+	return ids, nil
+}
 
-	if _, err := f.WriteString("\n"); err != nil {
-		return fmt.Errorf("failed to write newline: %w", err)
+// withWorkingDir sets the WorkingDir of balls from a backend, which doesn't
+// store it, to the store's project
+func (s *Store) withWorkingDir(balls ...*Ball) {
+	for _, ball := range balls {
+		ball.WorkingDir = s.projectDir
 	}
-
-	return nil
 }
 
-// ballJSON is used for JSON unmarshaling with migration support
-// It includes both old (intent) and new (title) field names
-type ballJSON struct {
-	Ball
-	Intent string `json:"intent,omitempty"` // Legacy field, migrated to Title
+// AppendBall adds a new ball
+func (s *Store) AppendBall(ball *Ball) error {
+	return s.balls.AppendBall(ball)
 }
 
-// LoadBalls reads all balls from the JSONL file
+// LoadBalls reads all active balls
 func (s *Store) LoadBalls() ([]*Ball, error) {
-	// If file doesn't exist, return empty slice
-	if _, err := os.Stat(s.ballsPath); os.IsNotExist(err) {
-		return []*Ball{}, nil
-	}
-
-	f, err := os.Open(s.ballsPath)
+	balls, err := s.balls.LoadBalls()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open balls file: %w", err)
-	}
-	defer f.Close()
-
-	balls := make([]*Ball, 0)
-	scanner := bufio.NewScanner(f)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		var ballData ballJSON
-		if err := json.Unmarshal([]byte(line), &ballData); err != nil {
-			// Log error but continue
-			fmt.Fprintf(os.Stderr, "Warning: failed to parse ball line: %v\n", err)
-			continue
-		}
-
-		ball := ballData.Ball
-
-		// Migrate legacy "intent" field to "title"
-		if ball.Title == "" && ballData.Intent != "" {
-			ball.Title = ballData.Intent
-		}
-
-		// Set WorkingDir from store location (not stored in JSON)
-		ball.WorkingDir = s.projectDir
-
-		balls = append(balls, &ball)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading balls file: %w", err)
+		return nil, err
 	}
-
+	s.withWorkingDir(balls...)
 	return balls, nil
 }
 
-// LoadArchivedBalls reads all balls from the archive JSONL file
+// LoadArchivedBalls reads all archived balls
 func (s *Store) LoadArchivedBalls() ([]*Ball, error) {
-	// If file doesn't exist, return empty slice
-	if _, err := os.Stat(s.archivePath); os.IsNotExist(err) {
-		return []*Ball{}, nil
-	}
-
-	f, err := os.Open(s.archivePath)
+	balls, err := s.balls.LoadArchivedBalls()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open archive file: %w", err)
-	}
-	defer f.Close()
-
-	balls := make([]*Ball, 0)
-	scanner := bufio.NewScanner(f)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue // Skip empty lines
-		}
-
-		var ballData ballJSON
-		if err := json.Unmarshal([]byte(line), &ballData); err != nil {
-			// Log error but continue
-			fmt.Fprintf(os.Stderr, "Warning: failed to parse archived ball line: %v\n", err)
-			continue
-		}
-
-		ball := ballData.Ball
-
-		// Migrate legacy "intent" field to "title"
-		if ball.Title == "" && ballData.Intent != "" {
-			ball.Title = ballData.Intent
-		}
-
-		// Set WorkingDir from store location (not stored in JSON)
-		ball.WorkingDir = s.projectDir
-
-		balls = append(balls, &ball)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading archive file: %w", err)
+		return nil, err
 	}
-
+	s.withWorkingDir(balls...)
 	return balls, nil
 }
 
-// UpdateBall updates an existing ball by rewriting the JSONL file
+// UpdateBall replaces an existing active ball
 func (s *Store) UpdateBall(updated *Ball) error {
-	balls, err := s.LoadBalls()
-	if err != nil {
-		return err
-	}
-
-	// Find and update the ball
-	found := false
-	for i, ball := range balls {
-		if ball.ID == updated.ID {
-			balls[i] = updated
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return NewBallNotFoundError(updated.ID)
-	}
-
-	// Rewrite entire file
-	return s.writeBalls(balls)
+	return s.balls.UpdateBall(updated)
 }
 
-// DeleteBall removes a ball from the JSONL file
+// DeleteBall removes an active ball
 func (s *Store) DeleteBall(id string) error {
-	balls, err := s.LoadBalls()
-	if err != nil {
-		return err
-	}
-
-	// Filter out the ball to delete
-	filtered := make([]*Ball, 0, len(balls))
-	for _, ball := range balls {
-		if ball.ID != id {
-			filtered = append(filtered, ball)
-		}
-	}
-
-	return s.writeBalls(filtered)
+	return s.balls.DeleteBall(id)
 }
 
-// ArchiveBall moves a ball to the archive.
-// This operation is atomic: both files are locked, and changes are applied
-// atomically using temp file + rename pattern.
+// ArchiveBall moves a ball to the archive
 func (s *Store) ArchiveBall(ball *Ball) error {
-	// Acquire locks on both files to ensure atomic operation
-	_, unlockBalls, err := acquireFileLock(s.ballsPath)
-	if err != nil {
-		return fmt.Errorf("failed to lock balls file: %w", err)
-	}
-	defer unlockBalls()
-
-	_, unlockArchive, err := acquireFileLock(s.archivePath)
-	if err != nil {
-		return fmt.Errorf("failed to lock archive file: %w", err)
-	}
-	defer unlockArchive()
-
-	// Load current balls
-	balls, err := s.LoadBalls()
-	if err != nil {
-		return fmt.Errorf("failed to load balls: %w", err)
-	}
+	return s.balls.ArchiveBall(ball)
+}
 
-	// Load current archive
-	archived, err := s.LoadArchivedBalls()
+// UnarchiveBall restores a completed ball from archive back to pending state
+func (s *Store) UnarchiveBall(ballID string) (*Ball, error) {
+	ball, err := s.balls.UnarchiveBall(ballID)
 	if err != nil {
-		return fmt.Errorf("failed to load archived balls: %w", err)
-	}
-
-	// Find and remove the ball from active list
-	found := false
-	filtered := make([]*Ball, 0, len(balls))
-	for _, b := range balls {
-		if b.ID != ball.ID {
-			filtered = append(filtered, b)
-		} else {
-			found = true
-		}
-	}
-
-	if !found {
-		return NewBallNotFoundError(ball.ID)
-	}
-
-	// Add ball to archive
-	archived = append(archived, ball)
-
-	// Write both files atomically
-	// First, write the new archive (safer to add first)
-	if err := s.writeArchivedBallsUnlocked(archived); err != nil {
-		return fmt.Errorf("failed to update archive: %w", err)
-	}
-
-	// Then, write the active balls (without the archived ball)
-	if err := s.writeBallsUnlocked(filtered); err != nil {
-		// Attempt to restore archive on failure (remove the ball we just added)
-		// This is best-effort; in worst case we have a duplicate in archive
-		s.writeArchivedBallsUnlocked(archived[:len(archived)-1])
-		return fmt.Errorf("failed to remove ball from active: %w", err)
+		return nil, err
 	}
-
-	return nil
+	s.withWorkingDir(ball)
+	return ball, nil
 }
 
 // GetInProgressBalls returns all balls currently in progress in this project
@@ -423,7 +272,6 @@ func (s *Store) GetBallByID(id string) (*Ball, error) {
 	return nil, NewBallNotFoundError(id)
 }
 
-
 // GetBallByShortID finds a ball by its short ID (numeric part)
 // If multiple balls match, returns the most recently active
 func (s *Store) GetBallByShortID(shortID string) (*Ball, error) {
@@ -521,197 +369,6 @@ func (s *Store) ResolveBallIDStrict(id string) (*Ball, error) {
 	return matches[0], nil
 }
 
-// writeBalls rewrites the entire balls.jsonl file
-func (s *Store) writeBalls(balls []*Ball) error {
-	// Acquire file lock
-	_, unlock, err := acquireFileLock(s.ballsPath)
-	if err != nil {
-		return err
-	}
-	defer unlock()
-
-	return s.writeBallsUnlocked(balls)
-}
-
-// writeBallsUnlocked rewrites the entire balls.jsonl file without acquiring a lock.
-// Caller must hold the lock.
-func (s *Store) writeBallsUnlocked(balls []*Ball) error {
-	// Write to temp file first
-	tempPath := s.ballsPath + ".tmp"
-	f, err := os.Create(tempPath)
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-
-	for _, ball := range balls {
-		data, err := json.Marshal(ball)
-		if err != nil {
-			f.Close()
-			os.Remove(tempPath)
-			return fmt.Errorf("failed to marshal ball: %w", err)
-		}
-
-		if _, err := f.Write(data); err != nil {
-			f.Close()
-			os.Remove(tempPath)
-			return fmt.Errorf("failed to write ball: %w", err)
-		}
-
-		if _, err := f.WriteString("\n"); err != nil {
-			f.Close()
-			os.Remove(tempPath)
-			return fmt.Errorf("failed to write newline: %w", err)
-		}
-	}
-
-	if err := f.Close(); err != nil {
-		os.Remove(tempPath)
-		return fmt.Errorf("failed to close temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tempPath, s.ballsPath); err != nil {
-		os.Remove(tempPath)
-		return fmt.Errorf("failed to rename temp file: %w", err)
-	}
-
-	return nil
-}
-
-// UnarchiveBall restores a completed ball from archive back to ready state.
-// This operation is atomic: both files are locked, and changes are applied
-// atomically using temp file + rename pattern.
-func (s *Store) UnarchiveBall(ballID string) (*Ball, error) {
-	// Acquire locks on both files to ensure atomic operation
-	_, unlockBalls, err := acquireFileLock(s.ballsPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to lock balls file: %w", err)
-	}
-	defer unlockBalls()
-
-	_, unlockArchive, err := acquireFileLock(s.archivePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to lock archive file: %w", err)
-	}
-	defer unlockArchive()
-
-	// Load archived balls (within lock)
-	archived, err := s.LoadArchivedBalls()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load archived balls: %w", err)
-	}
-
-	// Find ball with matching ID
-	var ball *Ball
-	var ballIndex int
-	for i, b := range archived {
-		if b.ID == ballID {
-			ball = b
-			ballIndex = i
-			break
-		}
-	}
-	if ball == nil {
-		return nil, NewBallNotFoundError(ballID)
-	}
-
-	// Change state to pending using new state model
-	ball.State = StatePending
-	ball.BlockedReason = ""
-	ball.CompletedAt = nil
-	ball.CompletionNote = ""
-
-	// Load current balls
-	balls, err := s.LoadBalls()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load balls: %w", err)
-	}
-
-	// Add the unarchived ball to the list
-	balls = append(balls, ball)
-
-	// Prepare the updated archive (without the ball being restored)
-	updatedArchive := make([]*Ball, 0, len(archived)-1)
-	for i, b := range archived {
-		if i != ballIndex {
-			updatedArchive = append(updatedArchive, b)
-		}
-	}
-
-	// Write both files atomically (temp file + rename pattern)
-	// First, write the new archive
-	if err := s.writeArchivedBallsUnlocked(updatedArchive); err != nil {
-		return nil, fmt.Errorf("failed to update archive: %w", err)
-	}
-
-	// Then, write the active balls
-	if err := s.writeBallsUnlocked(balls); err != nil {
-		// Attempt to restore archive on failure
-		// This is best-effort; in worst case we have inconsistent state
-		s.writeArchivedBallsUnlocked(archived)
-		return nil, fmt.Errorf("failed to add ball to active: %w", err)
-	}
-
-	return ball, nil
-}
-
-// writeArchivedBalls rewrites the entire archive/balls.jsonl file
-func (s *Store) writeArchivedBalls(balls []*Ball) error {
-	// Acquire file lock
-	_, unlock, err := acquireFileLock(s.archivePath)
-	if err != nil {
-		return err
-	}
-	defer unlock()
-
-	return s.writeArchivedBallsUnlocked(balls)
-}
-
-// writeArchivedBallsUnlocked rewrites the entire archive/balls.jsonl file without acquiring a lock.
-// Caller must hold the lock.
-func (s *Store) writeArchivedBallsUnlocked(balls []*Ball) error {
-	// Write to temp file first
-	tempPath := s.archivePath + ".tmp"
-	f, err := os.Create(tempPath)
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-
-	for _, ball := range balls {
-		data, err := json.Marshal(ball)
-		if err != nil {
-			f.Close()
-			os.Remove(tempPath)
-			return fmt.Errorf("failed to marshal ball: %w", err)
-		}
-
-		if _, err := f.Write(data); err != nil {
-			f.Close()
-			os.Remove(tempPath)
-			return fmt.Errorf("failed to write ball: %w", err)
-		}
-
-		if _, err := f.WriteString("\n"); err != nil {
-			f.Close()
-			os.Remove(tempPath)
-			return fmt.Errorf("failed to write newline: %w", err)
-		}
-	}
-
-	if err := f.Close(); err != nil {
-		os.Remove(tempPath)
-		return fmt.Errorf("failed to close temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tempPath, s.archivePath); err != nil {
-		os.Remove(tempPath)
-		return fmt.Errorf("failed to rename temp file: %w", err)
-	}
-
-	return nil
-}
-
 // Save is an alias for UpdateBall for backwards compatibility.
 //
 // Deprecated: Use UpdateBall for existing balls or AppendBall for new balls instead.
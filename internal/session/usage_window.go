@@ -0,0 +1,212 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	usageWindowFile = "usage_window.json"
+
+	// DefaultUsageWindowHours matches the 5-hour windows of Claude subscription plans
+	DefaultUsageWindowHours = 5
+)
+
+// UsageWindowConfig describes the usage windows of a subscription plan, whose
+// limits reset at the end of each window rather than per request
+type UsageWindowConfig struct {
+	Hours      int        `json:"hours,omitempty"`       // Window length (default: 5)
+	ResetAt    *time.Time `json:"reset_at,omitempty"`    // A time a window reset; windows repeat every Hours from it (nil = rolling: a window starts with the first run after the last one ended)
+	TokenLimit int        `json:"token_limit,omitempty"` // Tokens available per window (0 = learn from rate limits)
+}
+
+// Length returns the window length, defaulting to DefaultUsageWindowHours
+func (c *UsageWindowConfig) Length() time.Duration {
+	if c.Hours > 0 {
+		return time.Duration(c.Hours) * time.Hour
+	}
+	return DefaultUsageWindowHours * time.Hour
+}
+
+// UsageWindowState is the usage agent runs have observed, shared by every
+// project on the machine since the plan's limits are per account.
+// It is stored next to the global config in usage_window.json.
+type UsageWindowState struct {
+	WindowStart   time.Time `json:"window_start"`             // Start of the window Tokens counts
+	Tokens        int       `json:"tokens"`                   // Tokens used in that window
+	ObservedLimit int       `json:"observed_limit,omitempty"` // Tokens used in a window when it was last rate limited
+	TotalTokens   int       `json:"total_tokens"`             // Tokens used by all recorded agent runs
+	TotalRuns     int       `json:"total_runs"`               // Agent runs recorded, for the average per run
+}
+
+// UsageWindow tracks agent usage against the plan's usage windows
+type UsageWindow struct {
+	Config UsageWindowConfig
+	State  UsageWindowState
+	path   string
+}
+
+// LoadUsageWindowWithOptions returns the configured usage windows and the
+// usage observed so far, or nil when global config has no usage_window
+func LoadUsageWindowWithOptions(opts ConfigOptions) (*UsageWindow, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if config.UsageWindow == nil {
+		return nil, nil
+	}
+
+	configHome := opts.ConfigHome
+	if configHome == "" {
+		if configHome, err = os.UserHomeDir(); err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+	}
+	w := &UsageWindow{
+		Config: *config.UsageWindow,
+		path:   filepath.Join(configHome, opts.JuggleDirName, usageWindowFile),
+	}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Start returns the start of the window covering now, or the zero time for
+// rolling windows when none is open
+func (w *UsageWindow) Start(now time.Time) time.Time {
+	length := w.Config.Length()
+	if w.Config.ResetAt != nil {
+		elapsed := now.Sub(*w.Config.ResetAt)
+		n := elapsed / length
+		if elapsed < 0 && elapsed%length != 0 {
+			n-- // Round down before the reference reset too
+		}
+		return w.Config.ResetAt.Add(n * length)
+	}
+	if !w.State.WindowStart.IsZero() && now.Before(w.State.WindowStart.Add(length)) {
+		return w.State.WindowStart
+	}
+	return time.Time{}
+}
+
+// ResetsAt returns when the window covering now resets, or the zero time for
+// rolling windows when none is open
+func (w *UsageWindow) ResetsAt(now time.Time) time.Time {
+	start := w.Start(now)
+	if start.IsZero() {
+		return start
+	}
+	return start.Add(w.Config.Length())
+}
+
+// Used returns the tokens recorded in the window covering now
+func (w *UsageWindow) Used(now time.Time) int {
+	start := w.Start(now)
+	if start.IsZero() || !start.Equal(w.State.WindowStart) {
+		return 0
+	}
+	return w.State.Tokens
+}
+
+// Capacity returns the tokens available per window: the configured limit,
+// else the usage last seen when a window was rate limited (0 = unknown)
+func (w *UsageWindow) Capacity() int {
+	if w.Config.TokenLimit > 0 {
+		return w.Config.TokenLimit
+	}
+	return w.State.ObservedLimit
+}
+
+// Remaining returns the tokens estimated to be left in the window covering
+// now. ok is false while the capacity is unknown.
+func (w *UsageWindow) Remaining(now time.Time) (remaining int, ok bool) {
+	capacity := w.Capacity()
+	if capacity == 0 {
+		return 0, false
+	}
+	return max(capacity-w.Used(now), 0), true
+}
+
+// PerRun returns the average tokens an agent run has used (0 = no runs yet)
+func (w *UsageWindow) PerRun() int {
+	if w.State.TotalRuns == 0 {
+		return 0
+	}
+	return w.State.TotalTokens / w.State.TotalRuns
+}
+
+// Record adds the tokens of an agent run that started at the given time and saves
+func (w *UsageWindow) Record(startedAt time.Time, tokens int) error {
+	// Other runs may have recorded usage since this one loaded
+	if err := w.load(); err != nil {
+		return err
+	}
+	start := w.Start(startedAt)
+	if start.IsZero() {
+		start = startedAt // The run opened a new rolling window
+	}
+	if !start.Equal(w.State.WindowStart) {
+		w.State.WindowStart = start
+		w.State.Tokens = 0
+	}
+	w.State.Tokens += tokens
+	w.State.TotalTokens += tokens
+	w.State.TotalRuns++
+	return w.save()
+}
+
+// RecordLimit notes that the window covering now was rate limited, taking the
+// tokens used in it as the plan's capacity, and saves
+func (w *UsageWindow) RecordLimit(now time.Time) error {
+	if err := w.load(); err != nil {
+		return err
+	}
+	used := w.Used(now)
+	if used == 0 {
+		return nil // Nothing juggle recorded, so nothing learned
+	}
+	w.State.ObservedLimit = used
+	return w.save()
+}
+
+// load reads the saved usage; a missing file is no usage yet
+func (w *UsageWindow) load() error {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			w.State = UsageWindowState{}
+			return nil
+		}
+		return fmt.Errorf("failed to read usage window state: %w", err)
+	}
+	var state UsageWindowState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse usage window state: %w", err)
+	}
+	w.State = state
+	return nil
+}
+
+// save writes the usage, replacing the file atomically since concurrent runs share it
+func (w *UsageWindow) save() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(w.State, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage window state: %w", err)
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", w.path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write usage window state: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return fmt.Errorf("failed to write usage window state: %w", err)
+	}
+	return nil
+}
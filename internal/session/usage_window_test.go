@@ -0,0 +1,97 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestUsageWindow(t *testing.T, config *UsageWindowConfig) *UsageWindow {
+	t.Helper()
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+	if err := UpdateGlobalUsageWindowWithOptions(opts, config); err != nil {
+		t.Fatalf("UpdateGlobalUsageWindowWithOptions failed: %v", err)
+	}
+	window, err := LoadUsageWindowWithOptions(opts)
+	if err != nil || window == nil {
+		t.Fatalf("LoadUsageWindowWithOptions = %v, %v", window, err)
+	}
+	return window
+}
+
+func TestUsageWindow_FixedResets(t *testing.T) {
+	resetAt := time.Date(2026, 1, 2, 14, 0, 0, 0, time.UTC)
+	window := newTestUsageWindow(t, &UsageWindowConfig{ResetAt: &resetAt})
+
+	tests := []struct {
+		now, start time.Time
+	}{
+		{resetAt, resetAt},
+		{resetAt.Add(4*time.Hour + 59*time.Minute), resetAt},
+		{resetAt.Add(5 * time.Hour), resetAt.Add(5 * time.Hour)},
+		{resetAt.Add(-time.Minute), resetAt.Add(-5 * time.Hour)},
+		{resetAt.Add(-5 * time.Hour), resetAt.Add(-5 * time.Hour)},
+	}
+	for _, tt := range tests {
+		if got := window.Start(tt.now); !got.Equal(tt.start) {
+			t.Errorf("Start(%v) = %v, want %v", tt.now, got, tt.start)
+		}
+	}
+	if got, want := window.ResetsAt(resetAt.Add(time.Hour)), resetAt.Add(5*time.Hour); !got.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", got, want)
+	}
+}
+
+func TestUsageWindow_RecordAndLearnCapacity(t *testing.T) {
+	window := newTestUsageWindow(t, &UsageWindowConfig{Hours: 5})
+	start := time.Now().Add(-time.Hour)
+
+	if !window.Start(start).IsZero() {
+		t.Fatal("Expected no rolling window before any run")
+	}
+	if _, ok := window.Remaining(start); ok {
+		t.Error("Expected the capacity to be unknown before any rate limit")
+	}
+
+	// The first run opens the window; the next adds to it
+	if err := window.Record(start, 1000); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := window.Record(start.Add(30*time.Minute), 3000); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	now := start.Add(time.Hour)
+	if got := window.Used(now); got != 4000 {
+		t.Errorf("Used = %d, want 4000", got)
+	}
+	if got := window.PerRun(); got != 2000 {
+		t.Errorf("PerRun = %d, want 2000", got)
+	}
+	if got := window.ResetsAt(now); !got.Equal(start.Add(5 * time.Hour)) {
+		t.Errorf("ResetsAt = %v, want five hours after the first run", got)
+	}
+
+	if err := window.RecordLimit(now); err != nil {
+		t.Fatalf("RecordLimit failed: %v", err)
+	}
+	if remaining, ok := window.Remaining(now); !ok || remaining != 0 || window.Capacity() != 4000 {
+		t.Errorf("Expected a learned capacity of 4000 with none left, got capacity %d, remaining %d, %v", window.Capacity(), remaining, ok)
+	}
+
+	// Once the window ends, the next run opens a fresh one with the full capacity
+	later := start.Add(6 * time.Hour)
+	if remaining, _ := window.Remaining(later); remaining != 4000 {
+		t.Errorf("Expected the full capacity after the reset, got %d", remaining)
+	}
+	if err := window.Record(later, 500); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if got := window.Used(later); got != 500 || !window.Start(later).Equal(later) {
+		t.Errorf("Expected a new window from %v using 500, got %d from %v", later, got, window.Start(later))
+	}
+
+	// A configured limit wins over the learned one
+	window.Config.TokenLimit = 10000
+	if remaining, _ := window.Remaining(later); remaining != 9500 {
+		t.Errorf("Remaining = %d, want 9500", remaining)
+	}
+}
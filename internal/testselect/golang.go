@@ -0,0 +1,142 @@
+package testselect
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// goManifests are files whose change can affect every package
+var goManifests = map[string]bool{
+	"go.mod":  true,
+	"go.sum":  true,
+	"go.work": true,
+}
+
+// goPackage is one entry from go list
+type goPackage struct {
+	importPath string
+	dir        string
+	imports    []string // Imports plus test imports
+}
+
+// Go maps project-relative changed files to the import paths of the packages
+// that must be retested: the packages containing the files, plus every package
+// that imports one of them, transitively.
+// Files outside any package (docs, CI config) select nothing.
+func Go(projectDir string, changed []string) (Selection, error) {
+	for _, path := range changed {
+		if goManifests[filepath.Base(path)] {
+			return full, nil
+		}
+	}
+	if len(changed) == 0 {
+		return Selection{}, nil
+	}
+
+	// go list reports resolved directories, so the root must be resolved too
+	root, err := filepath.Abs(projectDir)
+	if err != nil {
+		return Selection{}, err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	packages, err := listGoPackages(root)
+	if err != nil {
+		return Selection{}, err
+	}
+
+	byDir := make(map[string]*goPackage, len(packages))
+	dependents := make(map[string][]string)
+	for _, pkg := range packages {
+		byDir[pkg.dir] = pkg
+		for _, imp := range pkg.imports {
+			dependents[imp] = append(dependents[imp], pkg.importPath)
+		}
+	}
+
+	affected := make(map[string]bool)
+	var queue []string
+	for _, path := range changed {
+		pkg := owningPackage(byDir, root, filepath.Join(root, path))
+		if pkg == nil || affected[pkg.importPath] {
+			continue
+		}
+		affected[pkg.importPath] = true
+		queue = append(queue, pkg.importPath)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[current] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	var args []string
+	for importPath := range affected {
+		args = append(args, importPath)
+	}
+	sort.Strings(args)
+	return Selection{Args: args}, nil
+}
+
+// owningPackage returns the package whose directory is closest above path.
+// Walking up covers testdata and embedded files as well as deleted sources.
+func owningPackage(byDir map[string]*goPackage, root, path string) *goPackage {
+	dir := filepath.Dir(path)
+	for {
+		if pkg, ok := byDir[dir]; ok {
+			return pkg
+		}
+		if dir == root || !strings.HasPrefix(dir, root) {
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// listGoPackages lists the module's packages with their imports and test imports
+func listGoPackages(projectDir string) ([]*goPackage, error) {
+	const format = `{{.ImportPath}}{{"\t"}}{{.Dir}}{{"\t"}}{{join .Imports " "}} {{join .TestImports " "}} {{join .XTestImports " "}}`
+	cmd := exec.Command("go", "list", "-e", "-f", format, "./...")
+	cmd.Dir = projectDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	var packages []*goPackage
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) < 2 || fields[1] == "" {
+			continue
+		}
+		pkg := &goPackage{importPath: fields[0], dir: fields[1]}
+		if len(fields) == 3 {
+			pkg.imports = strings.Fields(fields[2])
+		}
+		packages = append(packages, pkg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go list output: %w", err)
+	}
+	return packages, nil
+}
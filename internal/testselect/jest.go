@@ -0,0 +1,52 @@
+package testselect
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// jestManifests are files whose change can affect every test
+var jestManifests = map[string]bool{
+	"package.json":      true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"bun.lockb":         true,
+	"tsconfig.json":     true,
+	"babel.config.js":   true,
+}
+
+// jestSources are the extensions jest can resolve related tests for
+var jestSources = map[string]bool{
+	".js":  true,
+	".jsx": true,
+	".mjs": true,
+	".cjs": true,
+	".ts":  true,
+	".tsx": true,
+	".mts": true,
+	".cts": true,
+}
+
+// Jest selects the changed source files to pass to jest --findRelatedTests.
+// A deleted source file can't be resolved by jest, so it selects the full suite.
+func Jest(projectDir string, changed []string) Selection {
+	var args []string
+	for _, path := range changed {
+		base := filepath.Base(path)
+		if jestManifests[base] || strings.HasPrefix(base, "jest.config.") {
+			return full
+		}
+		if !jestSources[filepath.Ext(path)] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err != nil {
+			return full
+		}
+		args = append(args, path)
+	}
+	sort.Strings(args)
+	return Selection{Args: args}
+}
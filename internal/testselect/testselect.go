@@ -0,0 +1,25 @@
+// Package testselect picks the tests affected by a set of changed files, so
+// validation can run after every agent iteration without the full suite.
+//
+// Go changes are mapped to their packages and every package that imports them,
+// directly or through test imports; go test's result cache then skips anything
+// whose inputs are unchanged. JavaScript changes are passed to
+// jest --findRelatedTests, which resolves the dependent test files itself.
+//
+// Changes to dependency manifests (go.mod, package.json, lockfiles) always
+// select the full suite.
+package testselect
+
+// Selection is the result of mapping changed files to tests
+type Selection struct {
+	Full bool     // Run the full suite; Args is empty
+	Args []string // Packages or files to append to the affected test command
+}
+
+// Empty reports whether no tests are affected
+func (s Selection) Empty() bool {
+	return !s.Full && len(s.Args) == 0
+}
+
+// full is the selection that runs every test
+var full = Selection{Full: true}
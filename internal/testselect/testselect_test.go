@@ -0,0 +1,113 @@
+package testselect
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates files under dir from a path -> content map
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// setupGoModule creates a module where b imports a, c's tests import b, and d is standalone
+func setupGoModule(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found in PATH")
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":            "module example.com/m\n\ngo 1.21\n",
+		"a/a.go":            "package a\n",
+		"a/testdata/in.txt": "fixture\n",
+		"b/b.go":            "package b\n\nimport _ \"example.com/m/a\"\n",
+		"c/c.go":            "package c\n",
+		"c/c_test.go":       "package c\n\nimport _ \"example.com/m/b\"\n",
+		"d/d.go":            "package d\n",
+	})
+	return dir
+}
+
+func TestGo(t *testing.T) {
+	dir := setupGoModule(t)
+
+	tests := []struct {
+		name    string
+		changed []string
+		want    Selection
+	}{
+		{"no changes", nil, Selection{}},
+		{"leaf package", []string{"d/d.go"}, Selection{Args: []string{"example.com/m/d"}}},
+		{"dependents included", []string{"a/a.go"}, Selection{Args: []string{"example.com/m/a", "example.com/m/b", "example.com/m/c"}}},
+		{"testdata maps to package", []string{"a/testdata/in.txt"}, Selection{Args: []string{"example.com/m/a", "example.com/m/b", "example.com/m/c"}}},
+		{"deleted file maps to package", []string{"d/gone.go"}, Selection{Args: []string{"example.com/m/d"}}},
+		{"non-package file", []string{"README.md"}, Selection{}},
+		{"manifest runs everything", []string{"d/d.go", "go.sum"}, Selection{Full: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Go(dir, tt.changed)
+			if err != nil {
+				t.Fatalf("Go failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Go(%v) = %+v, want %+v", tt.changed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJest(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"src/app.ts":   "export {}\n",
+		"src/util.jsx": "export {}\n",
+	})
+
+	tests := []struct {
+		name    string
+		changed []string
+		want    Selection
+	}{
+		{"sources passed through", []string{"src/util.jsx", "README.md", "src/app.ts"}, Selection{Args: []string{"src/app.ts", "src/util.jsx"}}},
+		{"no sources", []string{"docs/guide.md"}, Selection{}},
+		{"deleted source runs everything", []string{"src/gone.ts"}, Selection{Full: true}},
+		{"manifest runs everything", []string{"package.json"}, Selection{Full: true}},
+		{"jest config runs everything", []string{"jest.config.ts"}, Selection{Full: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Jest(dir, tt.changed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Jest(%v) = %+v, want %+v", tt.changed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelection_Empty(t *testing.T) {
+	if !(Selection{}).Empty() {
+		t.Error("expected zero selection to be empty")
+	}
+	if (Selection{Full: true}).Empty() {
+		t.Error("expected full selection to be non-empty")
+	}
+	if (Selection{Args: []string{"./x"}}).Empty() {
+		t.Error("expected selection with args to be non-empty")
+	}
+}
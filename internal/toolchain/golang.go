@@ -0,0 +1,64 @@
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goFrameworks maps module path prefixes to the names shown in the briefing
+var goFrameworks = []struct{ module, name string }{
+	{"github.com/gin-gonic/gin", "gin"},
+	{"github.com/labstack/echo", "echo"},
+	{"github.com/gofiber/fiber", "fiber"},
+	{"github.com/go-chi/chi", "chi"},
+	{"github.com/spf13/cobra", "cobra"},
+	{"github.com/charmbracelet/bubbletea", "bubbletea"},
+	{"google.golang.org/grpc", "grpc"},
+	{"gorm.io/gorm", "gorm"},
+	{"github.com/stretchr/testify", "testify"},
+}
+
+// detectGo reads go.mod for the module, Go version and notable dependencies
+func detectGo(projectDir string) *Toolchain {
+	data, err := os.ReadFile(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+
+	chain := &Toolchain{
+		Name:     "Go",
+		Manifest: "go.mod",
+		Build:    "go build ./...",
+		Test:     "go test ./...",
+		Lint:     "go vet ./...",
+	}
+
+	content := string(data)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "go":
+			chain.Name = "Go " + fields[1]
+		case "module":
+			chain.Details = append(chain.Details, "module "+fields[1])
+		}
+	}
+
+	for _, fw := range goFrameworks {
+		if strings.Contains(content, fw.module) {
+			chain.Frameworks = append(chain.Frameworks, fw.name)
+		}
+	}
+
+	for _, name := range []string{".golangci.yml", ".golangci.yaml", ".golangci.toml", ".golangci.json"} {
+		if exists(projectDir, name) {
+			chain.Lint = "golangci-lint run"
+			break
+		}
+	}
+	return chain
+}
@@ -0,0 +1,96 @@
+package toolchain
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// npmDefaultTest is the placeholder test script written by `npm init`
+const npmDefaultTest = `echo "Error: no test specified" && exit 1`
+
+// nodeFrameworks maps dependency names to the names shown in the briefing
+var nodeFrameworks = []struct{ dependency, name string }{
+	{"next", "next"},
+	{"react", "react"},
+	{"vue", "vue"},
+	{"svelte", "svelte"},
+	{"@angular/core", "angular"},
+	{"express", "express"},
+	{"@nestjs/core", "nestjs"},
+	{"typescript", "typescript"},
+	{"jest", "jest"},
+	{"vitest", "vitest"},
+	{"eslint", "eslint"},
+}
+
+// packageJSON is the subset of package.json used for detection
+type packageJSON struct {
+	PackageManager  string            `json:"packageManager"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// detectNode reads package.json for scripts, dependencies and the package manager
+func detectNode(projectDir string) *Toolchain {
+	data, err := os.ReadFile(filepath.Join(projectDir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+
+	pm := nodePackageManager(projectDir, pkg.PackageManager)
+	chain := &Toolchain{
+		Name:     "Node.js",
+		Manifest: "package.json",
+		Details:  []string{pm},
+	}
+
+	for _, fw := range nodeFrameworks {
+		if _, ok := pkg.Dependencies[fw.dependency]; ok {
+			chain.Frameworks = append(chain.Frameworks, fw.name)
+		} else if _, ok := pkg.DevDependencies[fw.dependency]; ok {
+			chain.Frameworks = append(chain.Frameworks, fw.name)
+		}
+	}
+
+	if _, ok := pkg.Scripts["build"]; ok {
+		chain.Build = pm + " run build"
+	}
+	if script, ok := pkg.Scripts["test"]; ok && script != npmDefaultTest {
+		if pm == "bun" {
+			// "bun test" runs bun's own test runner instead of the script
+			chain.Test = "bun run test"
+		} else {
+			chain.Test = pm + " test"
+		}
+	}
+	if _, ok := pkg.Scripts["lint"]; ok {
+		chain.Lint = pm + " run lint"
+	}
+	return chain
+}
+
+// nodePackageManager picks the package manager from the packageManager field or lockfiles
+func nodePackageManager(projectDir, declared string) string {
+	if declared != "" {
+		name, _, _ := strings.Cut(declared, "@")
+		return name
+	}
+	for _, lock := range []struct{ file, pm string }{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"bun.lockb", "bun"},
+		{"bun.lock", "bun"},
+	} {
+		if exists(projectDir, lock.file) {
+			return lock.pm
+		}
+	}
+	return "npm"
+}
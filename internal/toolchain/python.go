@@ -0,0 +1,77 @@
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pythonManifests are checked in order; the first found names the toolchain
+var pythonManifests = []string{"pyproject.toml", "setup.py", "requirements.txt"}
+
+// pythonFrameworks are dependency names shown in the briefing
+var pythonFrameworks = []string{"django", "fastapi", "flask", "pytest", "ruff", "mypy"}
+
+// detectPython scans the Python manifests for dependencies and the environment manager.
+// Manifests are matched as text so no TOML parser is needed.
+func detectPython(projectDir string) *Toolchain {
+	var manifest string
+	var content strings.Builder
+	for _, name := range pythonManifests {
+		data, err := os.ReadFile(filepath.Join(projectDir, name))
+		if err != nil {
+			continue
+		}
+		if manifest == "" {
+			manifest = name
+		}
+		content.WriteString(strings.ToLower(string(data)) + "\n")
+	}
+	if manifest == "" {
+		return nil
+	}
+
+	text := content.String()
+	chain := &Toolchain{Name: "Python", Manifest: manifest}
+
+	// uv and poetry run tools inside the project's environment
+	var run string
+	switch {
+	case exists(projectDir, "uv.lock"):
+		run = "uv run "
+		chain.Details = append(chain.Details, "uv")
+	case exists(projectDir, "poetry.lock") || strings.Contains(text, "[tool.poetry]"):
+		run = "poetry run "
+		chain.Details = append(chain.Details, "poetry")
+	}
+
+	for _, fw := range pythonFrameworks {
+		if mentionsPackage(text, fw) {
+			chain.Frameworks = append(chain.Frameworks, fw)
+		}
+	}
+
+	switch {
+	case mentionsPackage(text, "pytest") || exists(projectDir, "conftest.py"):
+		chain.Test = run + "pytest"
+	case mentionsPackage(text, "django") && exists(projectDir, "manage.py"):
+		chain.Test = run + "python manage.py test"
+	default:
+		chain.Test = run + "python -m unittest discover"
+	}
+
+	switch {
+	case mentionsPackage(text, "ruff"):
+		chain.Lint = run + "ruff check ."
+	case mentionsPackage(text, "flake8"):
+		chain.Lint = run + "flake8"
+	}
+	return chain
+}
+
+// mentionsPackage reports whether a lowercased manifest references a package by name.
+// Plugins such as "pytest-cov" count as a mention; longer words such as "gruff" don't.
+func mentionsPackage(text, name string) bool {
+	return regexp.MustCompile(`(^|[^a-z0-9_-])` + regexp.QuoteMeta(name) + `([^a-z0-9_]|$)`).MatchString(text)
+}
@@ -0,0 +1,110 @@
+// Package toolchain detects a project's languages and frameworks from its
+// manifests (go.mod, package.json, pyproject.toml) and renders a short briefing
+// on how to build, test and lint it, so agents don't have to rediscover the
+// project's commands on every iteration.
+package toolchain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Toolchain is one language ecosystem detected in a project
+type Toolchain struct {
+	Name       string   // Language or runtime, e.g. "Go" or "Node.js"
+	Manifest   string   // File the toolchain was detected from
+	Details    []string // Version, module name or package manager
+	Frameworks []string // Notable frameworks and tools from the dependencies
+	Build      string   // Build command ("" if none)
+	Test       string   // Test command ("" if none)
+	Lint       string   // Lint command ("" if none)
+}
+
+// Commands overrides the detected commands. Empty fields keep the detected ones.
+type Commands struct {
+	Build string
+	Test  string
+	Lint  string
+}
+
+// Empty reports whether no command is overridden
+func (c Commands) Empty() bool {
+	return c.Build == "" && c.Test == "" && c.Lint == ""
+}
+
+// Detect returns the toolchains found in the project root, in a stable order.
+// Unreadable or malformed manifests are skipped.
+func Detect(projectDir string) []Toolchain {
+	var chains []Toolchain
+	for _, detect := range []func(string) *Toolchain{detectGo, detectNode, detectPython} {
+		if chain := detect(projectDir); chain != nil {
+			chains = append(chains, *chain)
+		}
+	}
+	return chains
+}
+
+// Briefing renders detected toolchains as system prompt text.
+// Overridden commands replace the detected ones and are listed once for the
+// whole project. Notes are appended verbatim. Returns "" if there is nothing
+// to say.
+func Briefing(chains []Toolchain, overrides Commands, notes string) string {
+	if len(chains) == 0 && overrides.Empty() && notes == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("PROJECT TOOLCHAIN:")
+	for _, chain := range chains {
+		b.WriteString("\n- " + chain.describe())
+		if overrides.Empty() {
+			writeCommands(&b, "  ", Commands{Build: chain.Build, Test: chain.Test, Lint: chain.Lint})
+		}
+	}
+	if !overrides.Empty() {
+		b.WriteString("\n- Project commands:")
+		writeCommands(&b, "  ", overrides)
+	}
+	if notes != "" {
+		b.WriteString("\n" + strings.TrimSpace(notes))
+	}
+	if len(chains) > 0 || !overrides.Empty() {
+		b.WriteString("\nUse these commands to check your work before signalling COMPLETE or CONTINUE.")
+	}
+	return b.String()
+}
+
+// describe renders the toolchain heading, e.g. "Go 1.25 (module x; cobra)"
+func (t Toolchain) describe() string {
+	s := t.Name
+	var extra []string
+	extra = append(extra, t.Details...)
+	if len(t.Frameworks) > 0 {
+		extra = append(extra, strings.Join(t.Frameworks, ", "))
+	}
+	if len(extra) > 0 {
+		s += " (" + strings.Join(extra, "; ") + ")"
+	}
+	return fmt.Sprintf("%s from %s", s, t.Manifest)
+}
+
+// writeCommands writes the non-empty commands, one per line
+func writeCommands(b *strings.Builder, indent string, c Commands) {
+	for _, cmd := range []struct{ label, command string }{
+		{"build", c.Build},
+		{"test", c.Test},
+		{"lint", c.Lint},
+	} {
+		if cmd.command != "" {
+			fmt.Fprintf(b, "\n%s%s: %s", indent, cmd.label, cmd.command)
+		}
+	}
+}
+
+// exists reports whether name exists in dir
+func exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
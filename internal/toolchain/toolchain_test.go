@@ -0,0 +1,181 @@
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestDetect_Go(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.25.0\n\nrequire github.com/spf13/cobra v1.10.1\n",
+		".golangci.yml": "linters: {}\n",
+	})
+
+	chains := Detect(dir)
+	if len(chains) != 1 {
+		t.Fatalf("Detect() = %+v, want 1 toolchain", chains)
+	}
+	want := Toolchain{
+		Name:       "Go 1.25.0",
+		Manifest:   "go.mod",
+		Details:    []string{"module example.com/app"},
+		Frameworks: []string{"cobra"},
+		Build:      "go build ./...",
+		Test:       "go test ./...",
+		Lint:       "golangci-lint run",
+	}
+	if !reflect.DeepEqual(chains[0], want) {
+		t.Errorf("Detect() = %+v, want %+v", chains[0], want)
+	}
+}
+
+func TestDetect_Node(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  Toolchain
+	}{
+		{
+			name: "pnpm with scripts",
+			files: map[string]string{
+				"package.json":   `{"scripts":{"build":"next build","test":"vitest","lint":"eslint ."},"dependencies":{"next":"15","react":"19"},"devDependencies":{"typescript":"5","vitest":"2"}}`,
+				"pnpm-lock.yaml": "",
+			},
+			want: Toolchain{
+				Name:       "Node.js",
+				Manifest:   "package.json",
+				Details:    []string{"pnpm"},
+				Frameworks: []string{"next", "react", "typescript", "vitest"},
+				Build:      "pnpm run build",
+				Test:       "pnpm test",
+				Lint:       "pnpm run lint",
+			},
+		},
+		{
+			name: "npm init placeholder test",
+			files: map[string]string{
+				"package.json": `{"scripts":{"test":"echo \"Error: no test specified\" && exit 1"}}`,
+			},
+			want: Toolchain{Name: "Node.js", Manifest: "package.json", Details: []string{"npm"}},
+		},
+		{
+			name: "bun from packageManager",
+			files: map[string]string{
+				"package.json": `{"packageManager":"bun@1.1.0","scripts":{"test":"bun test"}}`,
+			},
+			want: Toolchain{Name: "Node.js", Manifest: "package.json", Details: []string{"bun"}, Test: "bun run test"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			chains := Detect(dir)
+			if len(chains) != 1 {
+				t.Fatalf("Detect() = %+v, want 1 toolchain", chains)
+			}
+			if !reflect.DeepEqual(chains[0], tt.want) {
+				t.Errorf("Detect() = %+v, want %+v", chains[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_Python(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pyproject.toml": "[project]\ndependencies = [\"FastAPI>=0.110\"]\n\n[dependency-groups]\ndev = [\"pytest-cov\", \"ruff\"]\n",
+		"uv.lock":        "",
+	})
+
+	chains := Detect(dir)
+	if len(chains) != 1 {
+		t.Fatalf("Detect() = %+v, want 1 toolchain", chains)
+	}
+	want := Toolchain{
+		Name:       "Python",
+		Manifest:   "pyproject.toml",
+		Details:    []string{"uv"},
+		Frameworks: []string{"fastapi", "pytest", "ruff"},
+		Test:       "uv run pytest",
+		Lint:       "uv run ruff check .",
+	}
+	if !reflect.DeepEqual(chains[0], want) {
+		t.Errorf("Detect() = %+v, want %+v", chains[0], want)
+	}
+}
+
+func TestDetect_MultipleAndNone(t *testing.T) {
+	dir := t.TempDir()
+	if chains := Detect(dir); chains != nil {
+		t.Errorf("Detect() on empty dir = %+v, want nil", chains)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod":           "module example.com/app\n",
+		"package.json":     `{}`,
+		"requirements.txt": "flask\n",
+	})
+	var names []string
+	for _, chain := range Detect(dir) {
+		names = append(names, chain.Name)
+	}
+	if want := []string{"Go", "Node.js", "Python"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Detect() names = %v, want %v", names, want)
+	}
+}
+
+func TestBriefing(t *testing.T) {
+	chains := []Toolchain{{
+		Name:     "Go 1.25",
+		Manifest: "go.mod",
+		Details:  []string{"module example.com/app"},
+		Build:    "go build ./...",
+		Test:     "go test ./...",
+	}}
+
+	t.Run("detected commands", func(t *testing.T) {
+		got := Briefing(chains, Commands{}, "")
+		for _, want := range []string{"Go 1.25 (module example.com/app) from go.mod", "build: go build ./...", "test: go test ./..."} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Briefing() missing %q:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "lint:") {
+			t.Errorf("Briefing() should omit empty commands:\n%s", got)
+		}
+	})
+
+	t.Run("overrides replace detected commands", func(t *testing.T) {
+		got := Briefing(chains, Commands{Test: "make test"}, "Integration tests need docker.")
+		if strings.Contains(got, "go test") {
+			t.Errorf("Briefing() should not list detected commands when overridden:\n%s", got)
+		}
+		for _, want := range []string{"Go 1.25", "test: make test", "Integration tests need docker."} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Briefing() missing %q:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("nothing to say", func(t *testing.T) {
+		if got := Briefing(nil, Commands{}, ""); got != "" {
+			t.Errorf("Briefing() = %q, want empty", got)
+		}
+	})
+}
@@ -19,6 +19,20 @@ func (m Model) handleCancelAgent() (tea.Model, tea.Cmd) {
 
 // handleAgentCancelConfirm handles the agent cancel confirmation
 func (m Model) handleAgentCancelConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// A remote monitor cancels the daemon's loop and stays on it
+	if m.agentRemote != nil {
+		switch msg.String() {
+		case "y", "Y":
+			m.mode = agentMonitorView
+			m.message = "Cancelling agent..."
+			return m, sendDaemonControlCmd(m.agentRemote, m.agentRemote.ProjectDir, m.agentStatus.SessionID, "cancel", "")
+		case "n", "N", "esc", "q":
+			m.mode = agentMonitorView
+			m.message = "Agent still running"
+		}
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "y", "Y":
 		// Confirm cancellation
@@ -1,11 +1,28 @@
 package tui
 
 import (
+	"slices"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/agent/daemon"
+	"github.com/ohare93/juggle/internal/agent/provider"
 )
 
 // handleAgentMonitorKey handles keyboard input in agent monitor view
 func (m Model) handleAgentMonitorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.agentProviderChoices != nil {
+		return m.handleMonitorProviderSwitchKey(msg)
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		// A remote monitor has no split view to return to
+		if m.agentRemote != nil {
+			m.closeAgentStateStream()
+			return m, tea.Quit
+		}
+	}
+
 	switch msg.String() {
 	case "esc":
 		// Return to split view (agent keeps running)
@@ -15,6 +32,7 @@ func (m Model) handleAgentMonitorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.agentLogTailer.Close()
 			m.agentLogTailer = nil
 		}
+		m.closeAgentStateStream()
 		return m, nil
 
 	case "q":
@@ -26,6 +44,7 @@ func (m Model) handleAgentMonitorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.agentLogTailer.Close()
 			m.agentLogTailer = nil
 		}
+		m.closeAgentStateStream()
 		return m, nil
 
 	case "X":
@@ -56,6 +75,25 @@ func (m Model) handleAgentMonitorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "t":
+		// Take over interactively after the current iteration
+		if m.agentRemote != nil {
+			m.message = "Take over a remote daemon with juggle agent attach on its machine"
+			return m, nil
+		}
+		if m.agentStatus.Running {
+			m.message = "Taking over after the current iteration..."
+			return m, takeoverCmd(m.store.ProjectDir(), m.agentStatus.SessionID)
+		}
+		return m, nil
+
+	case "P":
+		// Switch the agent provider from the next iteration
+		if m.agentStatus.Running {
+			return m.handleMonitorOpenProviderSwitch()
+		}
+		return m, nil
+
 	case "n":
 		// Skip to next ball
 		if m.agentStatus.Running {
@@ -90,20 +128,68 @@ func (m Model) handleAgentMonitorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleMonitorPause() (tea.Model, tea.Cmd) {
 	m.agentMonitorPaused = true
 	m.message = "Pausing after current iteration..."
-	return m, sendDaemonControlCmd(m.store.ProjectDir(), m.agentStatus.SessionID, "pause", "")
+	return m, sendDaemonControlCmd(m.agentRemote, m.monitorProjectDir(), m.agentStatus.SessionID, "pause", "")
 }
 
 // handleMonitorResume sends a resume command to the daemon
 func (m Model) handleMonitorResume() (tea.Model, tea.Cmd) {
 	m.agentMonitorPaused = false
 	m.message = "Resuming..."
-	return m, sendDaemonControlCmd(m.store.ProjectDir(), m.agentStatus.SessionID, "resume", "")
+	return m, sendDaemonControlCmd(m.agentRemote, m.monitorProjectDir(), m.agentStatus.SessionID, "resume", "")
 }
 
-// handleMonitorSkipBall sends a skip_ball command to the daemon
+// handleMonitorSkipBall asks the daemon to block the ball it shows and move
+// on to the next workable ball after the current iteration
 func (m Model) handleMonitorSkipBall() (tea.Model, tea.Cmd) {
-	m.message = "Skipping current ball..."
-	return m, sendDaemonControlCmd(m.store.ProjectDir(), m.agentStatus.SessionID, "skip_ball", "")
+	ballID := m.agentStatus.CurrentBallID
+	if ballID == "" {
+		m.message = "No ball to skip yet"
+		return m, nil
+	}
+	m.message = "Skipping " + ballID + " after the current iteration (it will be blocked)..."
+	return m, skipBallCmd(m.agentRemote, m.monitorProjectDir(), m.agentStatus.SessionID, ballID)
+}
+
+// handleMonitorOpenProviderSwitch opens the provider switch on the daemon's
+// current provider
+func (m Model) handleMonitorOpenProviderSwitch() (tea.Model, tea.Cmd) {
+	m.agentProviderChoices = monitorProviderChoices()
+	m.agentProviderCursor = max(slices.Index(m.agentProviderChoices, m.agentStatus.Provider), 0)
+	return m, nil
+}
+
+// handleMonitorProviderSwitchKey handles keys while the provider switch is open
+func (m Model) handleMonitorProviderSwitchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "P":
+		m.agentProviderChoices = nil
+	case "h", "left", "shift+tab":
+		m.agentProviderCursor = (m.agentProviderCursor + len(m.agentProviderChoices) - 1) % len(m.agentProviderChoices)
+	case "l", "right", "tab":
+		m.agentProviderCursor = (m.agentProviderCursor + 1) % len(m.agentProviderChoices)
+	case "enter":
+		choice := m.agentProviderChoices[m.agentProviderCursor]
+		m.agentProviderChoices = nil
+		if choice == m.agentStatus.Provider {
+			m.message = "Already using " + choice
+			return m, nil
+		}
+		m.message = "Switching to " + choice + " after the current iteration..."
+		return m, sendDaemonControlCmd(m.agentRemote, m.monitorProjectDir(), m.agentStatus.SessionID, daemon.CmdChangeProvider, choice)
+	}
+	return m, nil
+}
+
+// monitorProviderChoices lists the providers that can run the agent loop.
+// The daemon checks the one picked is installed before switching.
+func monitorProviderChoices() []string {
+	var choices []string
+	for _, name := range append(provider.ValidProviders(), provider.CustomProviders()...) {
+		if provider.Get(provider.Type(name)).SupportsTools() {
+			choices = append(choices, name)
+		}
+	}
+	return choices
 }
 
 // handleMonitorCancelAgent sends a cancel command to the daemon
@@ -112,3 +198,11 @@ func (m Model) handleMonitorCancelAgent() (tea.Model, tea.Cmd) {
 	m.mode = confirmAgentCancel
 	return m, nil
 }
+
+// closeAgentStateStream stops streaming the daemon state when leaving the monitor view
+func (m *Model) closeAgentStateStream() {
+	if m.agentStateStream != nil {
+		m.agentStateStream.Close()
+		m.agentStateStream = nil
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ohare93/juggle/internal/agent/daemon"
 )
 
 // Monitor view styles
@@ -110,7 +112,9 @@ func (m Model) renderMonitorTitleBar() string {
 		m.agentStatus.Iteration,
 		m.agentStatus.MaxIterations)
 
-	if m.agentMonitorReconnected {
+	if m.agentRemote != nil {
+		title += lipgloss.NewStyle().Faint(true).Render(" (remote, " + m.agentRemote.Addr + ")")
+	} else if m.agentMonitorReconnected {
 		title += lipgloss.NewStyle().Faint(true).Render(" (reconnected)")
 	}
 
@@ -183,9 +187,23 @@ func (m Model) renderMonitorOutputSection(height int) string {
 		height -= 3 // Account for error banner
 	}
 
+	// Warn when the daemon's state schema differs from this build's, as some
+	// of what it reports may be missing or not understood
+	if warning := m.daemonVersionWarning(); warning != "" {
+		if len(warning) > m.width-6 {
+			warning = warning[:m.width-9] + "..."
+		}
+		warningStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("3")) // Yellow
+		b.WriteString("  " + warningStyle.Render("⚠ "+warning) + "\n")
+		height-- // Account for warning line
+	}
+
 	if len(m.agentOutput) == 0 {
 		emptyMsg := "  No agent output"
-		if !m.agentStatus.Running {
+		if m.agentRemote != nil {
+			emptyMsg = "  Output isn't sent by a remote daemon; follow it with juggle agent logs -f on its machine"
+		} else if !m.agentStatus.Running {
 			emptyMsg += " - Press Esc to return"
 		}
 		b.WriteString(helpStyle.Render(emptyMsg) + "\n")
@@ -346,14 +364,37 @@ func (m Model) renderMonitorMetricsPanel() string {
 		monitorMetricLabelStyle.Render("Phase:"),
 		monitorMetricValueStyle.Render(phase)))
 
-	// Row 5: Phase message (if present)
+	// Row 5: Estimated time remaining while the daemon runs. A daemon too old
+	// to estimate it is told apart from one that hasn't yet.
+	if m.agentStatus.Running && !m.agentStatus.reports("estimated_end") {
+		b.WriteString(fmt.Sprintf("  %s %s\n",
+			monitorMetricLabelStyle.Render("ETA:"),
+			monitorMetricValueStyle.Render("unknown (daemon too old to estimate)")))
+	} else if m.agentStatus.Running && !m.agentStatus.EstimatedEnd.IsZero() {
+		eta := "finishing"
+		if remaining := time.Until(m.agentStatus.EstimatedEnd); remaining > 0 {
+			eta = "~" + formatDuration(remaining)
+		}
+		b.WriteString(fmt.Sprintf("  %s %s\n",
+			monitorMetricLabelStyle.Render("ETA:"),
+			monitorMetricValueStyle.Render(fmt.Sprintf("%s (around %s)", eta, m.agentStatus.EstimatedEnd.Format("15:04")))))
+	}
+
+	// Row 6: Scheduled runs of the session (if any)
+	if m.agentStatus.Schedule != "" {
+		b.WriteString(fmt.Sprintf("  %s %s\n",
+			monitorMetricLabelStyle.Render("Schedule:"),
+			monitorMetricValueStyle.Render(fmt.Sprintf("%s (next run %s)", m.agentStatus.Schedule, m.agentStatus.NextScheduledRun.Format("Mon 15:04")))))
+	}
+
+	// Row 7: Phase message (if present)
 	if phaseMessage != "" {
 		b.WriteString(fmt.Sprintf("  %s %s\n",
 			monitorMetricLabelStyle.Render("Status:"),
 			monitorMetricValueStyle.Render(phaseMessage)))
 	}
 
-	// Row 6: Hook metrics (if available)
+	// Row 8: Hook metrics (if available)
 	if m.agentMetrics != nil && m.agentMetrics.TotalTools > 0 {
 		// Format tools info
 		toolsInfo := fmt.Sprintf("%d", m.agentMetrics.TotalTools)
@@ -386,6 +427,23 @@ func (m Model) renderMonitorMetricsPanel() string {
 	return b.String()
 }
 
+// daemonVersionWarning describes a running daemon whose state schema version
+// differs from this build's and what to do about it, or returns ""
+func (m Model) daemonVersionWarning() string {
+	if !m.agentStatus.Running {
+		return ""
+	}
+	mismatch := daemon.VersionMismatch(m.agentStatus.StateVersion)
+	switch {
+	case mismatch == "":
+		return ""
+	case m.agentStatus.StateVersion < daemon.StateVersion:
+		return mismatch + "; restart the run to see everything: juggle agent stop " + m.agentStatus.SessionID + ", then start it again"
+	default:
+		return mismatch + "; update juggle to see everything"
+	}
+}
+
 // formatTokenCount formats token counts with K/M suffixes
 func formatTokenCount(tokens int) string {
 	if tokens >= 1000000 {
@@ -399,6 +457,10 @@ func formatTokenCount(tokens int) string {
 
 // renderMonitorControlsPanel renders the controls help line
 func (m Model) renderMonitorControlsPanel() string {
+	if m.agentProviderChoices != nil {
+		return "\n  " + m.renderMonitorProviderSwitch()
+	}
+
 	var controls []string
 
 	if m.agentStatus.Running {
@@ -409,7 +471,9 @@ func (m Model) renderMonitorControlsPanel() string {
 		}
 		controls = append(controls,
 			"m:Model",
+			"P:Provider",
 			"n:Skip ball",
+			"t:Takeover",
 			"X:Cancel",
 		)
 	}
@@ -421,3 +485,16 @@ func (m Model) renderMonitorControlsPanel() string {
 
 	return "\n  " + monitorControlsStyle.Render(strings.Join(controls, " | "))
 }
+
+// renderMonitorProviderSwitch renders the provider switch in place of the
+// controls line
+func (m Model) renderMonitorProviderSwitch() string {
+	parts := make([]string, len(m.agentProviderChoices))
+	for i, name := range m.agentProviderChoices {
+		if i == m.agentProviderCursor {
+			name = "[" + name + "]"
+		}
+		parts[i] = name
+	}
+	return monitorControlsStyle.Render("Provider: " + strings.Join(parts, " ") + " | ←/→:Choose | Enter:Switch | Esc:Cancel")
+}
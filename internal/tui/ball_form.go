@@ -31,7 +31,7 @@ func (m Model) finalizeBallCreation() (tea.Model, tea.Cmd) {
 	modelSize := modelSizes[m.pendingBallModelSize]
 
 	// Map agent provider index to string
-	agentProviders := []string{"", "claude", "opencode"}
+	agentProviders := []string{"", "claude", "opencode", "goose", "amp"}
 	agentProvider := agentProviders[m.pendingBallAgentProvider]
 
 	// Map model override index to string
@@ -308,7 +308,7 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Number of options for selection fields
 	numModelSizeOptions := 4       // (default), small, medium, large
-	numAgentProviderOptions := 3   // (default), claude, opencode
+	numAgentProviderOptions := 5   // (default), claude, opencode, goose, amp
 	numModelOverrideOptions := 4   // (default), opus, sonnet, haiku
 	numPriorityOptions := 4        // low, medium, high, urgent
 	numBlockingReasonOptions := 5  // (blank), Human needed, Waiting for dependency, Needs research, (custom)
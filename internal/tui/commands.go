@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -16,6 +17,9 @@ import (
 	"github.com/ohare93/juggle/internal/agent/daemon"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/watcher"
+	"github.com/ohare93/juggle/pkg/daemonapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ballsLoadedMsg struct {
@@ -65,6 +69,49 @@ func updateBall(store *session.Store, ball *session.Ball) tea.Cmd {
 	}
 }
 
+type ballsUpdatedMsg struct {
+	balls []*session.Ball
+	err   error
+}
+
+// updateBallsByProject saves balls with one command per project, each
+// rewriting the project's balls.jsonl once rather than once per ball
+func updateBallsByProject(balls []*session.Ball) (tea.Cmd, error) {
+	byProject := make(map[string][]*session.Ball)
+	var projects []string
+	for _, ball := range balls {
+		if _, ok := byProject[ball.WorkingDir]; !ok {
+			projects = append(projects, ball.WorkingDir)
+		}
+		byProject[ball.WorkingDir] = append(byProject[ball.WorkingDir], ball)
+	}
+
+	var cmds []tea.Cmd
+	for _, projectDir := range projects {
+		store, err := session.NewStore(projectDir)
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, updateBalls(store, byProject[projectDir]))
+	}
+	return tea.Batch(cmds...), nil
+}
+
+// updateBalls saves several balls of a store's project in one batch
+func updateBalls(store *session.Store, balls []*session.Ball) tea.Cmd {
+	return func() tea.Msg {
+		err := store.Batch(func(store *session.Store) error {
+			for _, ball := range balls {
+				if err := store.UpdateBall(ball); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return ballsUpdatedMsg{balls: balls, err: err}
+	}
+}
+
 type ballArchivedMsg struct {
 	ball *session.Ball
 	err  error
@@ -205,9 +252,19 @@ type AgentStatus struct {
 	ACsTotal         int
 	Model            string
 	Provider         string
-	Status           string // Status message when stopped (e.g., "No workable balls", "Complete")
-	Phase            string // Current agent phase (starting, working, blocked, testing, complete)
-	PhaseMessage     string // Message describing current phase activity
+	Status           string    // Status message when stopped (e.g., "No workable balls", "Complete")
+	Phase            string    // Current agent phase (starting, working, blocked, testing, complete)
+	PhaseMessage     string    // Message describing current phase activity
+	EstimatedEnd     time.Time // Rough finish time reported by the daemon, zero when unknown
+	Schedule         string    // Cron expression of the session's scheduled runs, if any
+	NextScheduledRun time.Time // When the next scheduled run is due
+	StateVersion     int       // Schema version of the daemon's state, see daemon.StateVersion
+}
+
+// reports returns whether the daemon writes a state field (by JSON name), so
+// an unset one is empty rather than unknown to an older daemon
+func (s AgentStatus) reports(field string) bool {
+	return (&daemon.State{Version: s.StateVersion}).HasField(field)
 }
 
 // DaemonInfo stores information about a running daemon for a session
@@ -701,6 +758,26 @@ type daemonControlSentMsg struct {
 	command string
 }
 
+// agentTakeoverDoneMsg is sent when an interactive takeover session returns
+type agentTakeoverDoneMsg struct {
+	err error
+}
+
+// takeoverCmd suspends the TUI and runs juggle agent attach, which pauses the
+// daemon after its current iteration for an interactive session and resumes it
+// when the session ends
+func takeoverCmd(projectDir, sessionID string) tea.Cmd {
+	exe, err := os.Executable()
+	if err != nil {
+		return func() tea.Msg { return agentTakeoverDoneMsg{err: err} }
+	}
+	attachCmd := exec.Command(exe, "agent", "attach", sessionID)
+	attachCmd.Dir = projectDir
+	return tea.ExecProcess(attachCmd, func(err error) tea.Msg {
+		return agentTakeoverDoneMsg{err: err}
+	})
+}
+
 // daemonControlErrorMsg is sent when sending a control command fails
 type daemonControlErrorMsg struct {
 	err error
@@ -718,15 +795,37 @@ type daemonStateLoadedMsg struct {
 	acsTotal         int
 	model            string
 	provider         string
-	status           string    // Status message when stopped (e.g., "No workable balls")
-	startedAt        time.Time // When the daemon actually started
+	status           string             // Status message when stopped (e.g., "No workable balls")
+	startedAt        time.Time          // When the daemon actually started
+	estimatedEnd     time.Time          // Rough time the run should finish
+	schedule         string             // Cron expression of the session's scheduled runs
+	nextRun          time.Time          // When the next scheduled run is due
+	version          int                // Schema version of the daemon's state
+	stream           *DaemonStateStream // Set when the state came over the daemon API
 	err              error
 }
 
+// RemoteDaemon is a daemon on another machine the monitor reaches through
+// its API, typically over an SSH tunnel
+type RemoteDaemon struct {
+	Addr       string // Local end of the tunnel, e.g. "localhost:7717"
+	Token      string // The remote daemon's API token
+	ProjectDir string // Project of the monitored loop on the remote machine
+}
+
+// dialDaemonAPI connects to the daemon API serving a session's loop: the
+// remote daemon's, or the one named in the session's PID file
+func dialDaemonAPI(remote *RemoteDaemon, projectDir, sessionID string) (*daemon.APIClient, error) {
+	if remote != nil {
+		return daemon.DialRemoteAPI(remote.Addr, remote.Token)
+	}
+	return daemon.DialAPI(projectDir, sessionID)
+}
+
 // sendDaemonControlCmd creates a command that sends a control command to the daemon
-func sendDaemonControlCmd(projectDir, sessionID, command, args string) tea.Cmd {
+func sendDaemonControlCmd(remote *RemoteDaemon, projectDir, sessionID, command, args string) tea.Cmd {
 	return func() tea.Msg {
-		err := sendDaemonControl(projectDir, sessionID, command, args)
+		err := sendDaemonControl(remote, projectDir, sessionID, command, args)
 		if err != nil {
 			return daemonControlErrorMsg{err: err}
 		}
@@ -734,33 +833,191 @@ func sendDaemonControlCmd(projectDir, sessionID, command, args string) tea.Cmd {
 	}
 }
 
-// sendDaemonControl writes a control command to the daemon control file
-func sendDaemonControl(projectDir, sessionID, command, args string) error {
+// skipBallCmd asks the daemon to block a ball and move on to the next
+// workable one, noting the local user as the one who asked
+func skipBallCmd(remote *RemoteDaemon, projectDir, sessionID, ballID string) tea.Cmd {
+	return sendDaemonControlCmd(remote, projectDir, sessionID, daemon.CmdSkipBall, daemon.SkipBallArgs(ballID, monitorUser()))
+}
+
+// monitorUser names the person using the monitor, for the daemon's notes
+func monitorUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// sendDaemonControl sends a control command to the daemon over the daemon
+// API, or its control socket or file if the API can't be reached. A remote
+// daemon only has the API.
+func sendDaemonControl(remote *RemoteDaemon, projectDir, sessionID, command, args string) error {
+	if client, err := dialDaemonAPI(remote, projectDir, sessionID); err == nil {
+		defer client.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := client.SendControl(ctx, &daemonapi.SendControlRequest{
+			ProjectDir: projectDir,
+			SessionId:  sessionID,
+			Command:    command,
+			Args:       args,
+		})
+		if remote != nil || status.Code(err) != codes.Unavailable {
+			return err
+		}
+	} else if remote != nil {
+		return err
+	}
 	return daemon.SendControlCommand(projectDir, sessionID, command, args)
 }
 
 // loadDaemonStateCmd creates a command that loads the daemon state from the state file
 func loadDaemonStateCmd(projectDir, sessionID string) tea.Cmd {
 	return func() tea.Msg {
-		state, err := daemon.ReadStateFile(projectDir, sessionID)
-		if err != nil {
+		return loadDaemonState(projectDir, sessionID)
+	}
+}
+
+// loadDaemonState loads the daemon state from the state file
+func loadDaemonState(projectDir, sessionID string) daemonStateLoadedMsg {
+	state, err := daemon.ReadStateFile(projectDir, sessionID)
+	if err != nil {
+		return daemonStateLoadedMsg{err: err}
+	}
+	return newDaemonStateLoadedMsg(projectDir, sessionID, state)
+}
+
+// DaemonStateStream receives a daemon's state over the daemon API as it changes
+type DaemonStateStream struct {
+	projectDir string
+	sessionID  string
+	client     *daemon.APIClient
+	remote     bool // The daemon runs on another machine
+	states     daemonapi.Daemon_WatchStateClient
+	cancel     context.CancelFunc
+	closed     bool
+	mu         sync.Mutex
+}
+
+// Close ends the stream
+func (s *DaemonStateStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.cancel()
+	s.client.Close()
+}
+
+// IsClosed returns whether the stream is closed
+func (s *DaemonStateStream) IsClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// daemonStateStreamEndedMsg is sent when the daemon ends a state stream or it
+// is closed
+type daemonStateStreamEndedMsg struct {
+	stream *DaemonStateStream
+}
+
+// watchDaemonStateCmd creates a command that streams the daemon state over the
+// daemon API, or loads it once from the state file if the daemon doesn't
+// serve the API (the file watcher then reports changes). A remote daemon's
+// state only comes over the API.
+func watchDaemonStateCmd(remote *RemoteDaemon, projectDir, sessionID string) tea.Cmd {
+	fallback := func(err error) tea.Msg {
+		if remote != nil {
 			return daemonStateLoadedMsg{err: err}
 		}
+		return loadDaemonState(projectDir, sessionID)
+	}
+	return func() tea.Msg {
+		client, err := dialDaemonAPI(remote, projectDir, sessionID)
+		if err != nil {
+			return fallback(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		states, err := client.WatchState(ctx, &daemonapi.GetStateRequest{ProjectDir: projectDir, SessionId: sessionID})
+		if err != nil {
+			cancel()
+			client.Close()
+			return fallback(err)
+		}
+		stream := &DaemonStateStream{
+			projectDir: projectDir,
+			sessionID:  sessionID,
+			client:     client,
+			states:     states,
+			cancel:     cancel,
+		}
+		// The first state tells whether the daemon is reachable at all
+		state, err := states.Recv()
+		if err != nil {
+			stream.Close()
+			return fallback(err)
+		}
+		stream.remote = remote != nil
+		msg := newDaemonStateLoadedMsg(stream.scheduleDir(), sessionID, daemon.StateFromProto(state))
+		msg.stream = stream
+		return msg
+	}
+}
 
-		return daemonStateLoadedMsg{
-			running:          state.Running,
-			paused:           state.Paused,
-			currentBallID:    state.CurrentBallID,
-			currentBallTitle: state.CurrentBallTitle,
-			iteration:        state.Iteration,
-			maxIterations:    state.MaxIterations,
-			acsComplete:      state.ACsComplete,
-			acsTotal:         state.ACsTotal,
-			model:            state.Model,
-			provider:         state.Provider,
-			status:           state.Status,
-			startedAt:        state.StartedAt,
+// nextDaemonStateCmd creates a command that waits for the next state on a stream
+func nextDaemonStateCmd(stream *DaemonStateStream) tea.Cmd {
+	return func() tea.Msg {
+		state, err := stream.states.Recv()
+		if err != nil {
+			return daemonStateStreamEndedMsg{stream: stream}
 		}
+		msg := newDaemonStateLoadedMsg(stream.scheduleDir(), stream.sessionID, daemon.StateFromProto(state))
+		msg.stream = stream
+		return msg
+	}
+}
+
+// scheduleDir returns the project to read the session's schedule from, or ""
+// for a remote daemon, whose schedule isn't on this machine
+func (s *DaemonStateStream) scheduleDir() string {
+	if s.remote {
+		return ""
+	}
+	return s.projectDir
+}
+
+// newDaemonStateLoadedMsg builds the message for a daemon state, adding the
+// session's schedule when projectDir is set
+func newDaemonStateLoadedMsg(projectDir, sessionID string, state *daemon.State) daemonStateLoadedMsg {
+	var schedule string
+	var nextRun time.Time
+	if projectDir != "" {
+		if sessionStore, err := session.NewSessionStore(projectDir); err == nil {
+			if sched, _ := sessionStore.LoadSchedule(sessionID); sched != nil {
+				schedule, nextRun = sched.Cron, sched.NextRun
+			}
+		}
+	}
+
+	return daemonStateLoadedMsg{
+		running:          state.Running,
+		paused:           state.Paused,
+		currentBallID:    state.CurrentBallID,
+		currentBallTitle: state.CurrentBallTitle,
+		iteration:        state.Iteration,
+		maxIterations:    state.MaxIterations,
+		acsComplete:      state.ACsComplete,
+		acsTotal:         state.ACsTotal,
+		model:            state.Model,
+		provider:         state.Provider,
+		status:           state.Status,
+		startedAt:        state.StartedAt,
+		estimatedEnd:     state.EstimatedEnd,
+		schedule:         schedule,
+		nextRun:          nextRun,
+		version:          state.Version,
 	}
 }
 
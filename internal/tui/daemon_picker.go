@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// daemonPickerRefreshInterval is how often the daemon picker rereads the
+// running daemons
+const daemonPickerRefreshInterval = 2 * time.Second
+
+// RunningDaemon is a daemon listed by the daemon picker
+type RunningDaemon struct {
+	ProjectDir    string
+	SessionID     string // As the user names it ("all" rather than "_all")
+	StorageID     string // Session directory name
+	PID           int    // Zero for a remote daemon
+	Paused        bool
+	BallID        string
+	BallTitle     string
+	Iteration     int
+	MaxIterations int
+	Model         string
+	StartedAt     time.Time
+}
+
+// DaemonPickerModel is a TUI that lists running daemons across projects and
+// exits with the one to attach the monitor to. It exits on enter, or without
+// a selection on q/esc.
+type DaemonPickerModel struct {
+	load    func() []RunningDaemon // Reads the running daemons
+	daemons []RunningDaemon
+	loaded  bool
+	cursor  int
+	width   int
+
+	selected *RunningDaemon
+}
+
+// NewDaemonPickerModel creates a daemon picker that lists the daemons load
+// returns, rereading them while it is open
+func NewDaemonPickerModel(load func() []RunningDaemon) DaemonPickerModel {
+	return DaemonPickerModel{load: load}
+}
+
+// daemonsLoadedMsg carries the running daemons read by the picker
+type daemonsLoadedMsg struct {
+	daemons []RunningDaemon
+}
+
+// daemonPickerTickMsg asks the picker to reread the running daemons
+type daemonPickerTickMsg struct{}
+
+func (m DaemonPickerModel) loadDaemons() tea.Cmd {
+	return func() tea.Msg {
+		return daemonsLoadedMsg{daemons: m.load()}
+	}
+}
+
+func (m DaemonPickerModel) Init() tea.Cmd {
+	return m.loadDaemons()
+}
+
+func (m DaemonPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case daemonsLoadedMsg:
+		m.daemons = msg.daemons
+		m.loaded = true
+		if m.cursor >= len(m.daemons) {
+			m.cursor = max(len(m.daemons)-1, 0)
+		}
+		return m, tea.Tick(daemonPickerRefreshInterval, func(time.Time) tea.Msg {
+			return daemonPickerTickMsg{}
+		})
+
+	case daemonPickerTickMsg:
+		return m, m.loadDaemons()
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "j", "down":
+			if m.cursor < len(m.daemons)-1 {
+				m.cursor++
+			}
+		case "k", "up":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "enter":
+			if m.cursor < len(m.daemons) {
+				d := m.daemons[m.cursor]
+				m.selected = &d
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m DaemonPickerModel) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Running Agent Daemons"))
+	b.WriteString("\n")
+
+	switch {
+	case !m.loaded:
+		b.WriteString(helpStyle.Render("Looking for daemons...") + "\n")
+	case len(m.daemons) == 0:
+		b.WriteString(helpStyle.Render("No agent daemons are running. Start one with: juggle agent run --daemon <session>") + "\n")
+	}
+
+	showProject := false
+	for _, d := range m.daemons {
+		if d.ProjectDir != m.daemons[0].ProjectDir {
+			showProject = true
+			break
+		}
+	}
+	for i, d := range m.daemons {
+		style := ballStyle
+		if i == m.cursor {
+			style = selectedBallStyle
+		}
+		b.WriteString(style.Render(m.renderDaemonLine(d)))
+		b.WriteString("\n")
+		if showProject {
+			b.WriteString(helpStyle.Render("    " + d.ProjectDir))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("enter: attach | j/k: move | q: quit"))
+	return b.String()
+}
+
+// renderDaemonLine renders a daemon as one picker row
+func (m DaemonPickerModel) renderDaemonLine(d RunningDaemon) string {
+	state := lipgloss.NewStyle().Foreground(readyColor).Render("running")
+	if d.Paused {
+		state = lipgloss.NewStyle().Foreground(jugglingColor).Render("paused")
+	}
+	iteration := "-"
+	if d.MaxIterations > 0 {
+		iteration = fmt.Sprintf("%d/%d", d.Iteration, d.MaxIterations)
+	}
+	ball := d.BallTitle
+	if ball == "" {
+		ball = d.BallID
+	}
+	pid := "-"
+	if d.PID > 0 {
+		pid = fmt.Sprint(d.PID)
+	}
+	line := fmt.Sprintf("%-24s %s  pid %-7s iter %-7s %s", truncate(d.SessionID, 24), state, pid, iteration, ball)
+	if d.Model != "" {
+		line += helpStyle.Render(" [" + d.Model + "]")
+	}
+	if !d.StartedAt.IsZero() {
+		line += helpStyle.Render(" up " + formatDuration(time.Since(d.StartedAt)))
+	}
+	if m.width > 0 {
+		line = lipgloss.NewStyle().MaxWidth(m.width - 2).Render(line)
+	}
+	return line
+}
+
+// Selected returns the daemon picked with enter, or nil when the picker was
+// quit
+func (m DaemonPickerModel) Selected() *RunningDaemon {
+	return m.selected
+}
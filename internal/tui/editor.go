@@ -23,6 +23,7 @@ type BallYAML struct {
 	Tags               []string `yaml:"tags"`
 	AcceptanceCriteria []string `yaml:"acceptance_criteria"`
 	ModelSize          string   `yaml:"model_size"`
+	Kind               string   `yaml:"kind"`
 }
 
 // ballToYAML converts a ball to YAML format for editing
@@ -48,6 +49,7 @@ func ballToYAML(ball *session.Ball) (string, error) {
 		Tags:               tags,
 		AcceptanceCriteria: ac,
 		ModelSize:          string(ball.ModelSize),
+		Kind:               string(ball.Kind),
 	}
 
 	data, err := yaml.Marshal(&yamlBall)
@@ -70,7 +72,7 @@ func ballToYAML(ball *session.Ball) (string, error) {
 // yamlToBall parses edited YAML and applies changes to a ball
 // Empty values are handled gracefully:
 // - Required fields (intent, priority, state): keep existing value if empty/whitespace
-// - Optional fields (blocked_reason, tags, acceptance_criteria, model_size): can be cleared to empty
+// - Optional fields (blocked_reason, tags, acceptance_criteria, model_size, kind): can be cleared to empty
 func yamlToBall(yamlContent string, ball *session.Ball) error {
 	var yamlBall BallYAML
 	if err := yaml.Unmarshal([]byte(yamlContent), &yamlBall); err != nil {
@@ -148,6 +150,13 @@ func yamlToBall(yamlContent string, ball *session.Ball) error {
 		ball.ModelSize = session.ModelSizeBlank
 	}
 
+	// Update kind (can be cleared to blank, which means code)
+	kind := strings.TrimSpace(yamlBall.Kind)
+	if !session.ValidateBallKind(kind) {
+		return fmt.Errorf("invalid kind: %s (must be code, docs, research, ops, or empty)", kind)
+	}
+	ball.Kind = session.BallKind(kind)
+
 	ball.UpdateActivity()
 	return nil
 }
@@ -129,6 +129,7 @@ type Model struct {
 	lastKey            string // Last key pressed (for gg detection)
 	pendingKeySequence string // Pending key for two-key sequences (s, t, etc.)
 	helpScrollOffset   int    // Scroll offset for help view
+	helpShowExamples   bool   // Help view shows workflow examples instead of keybindings
 	ballsScrollOffset  int    // Scroll offset for balls panel viewport
 	detailScrollOffset int    // Scroll offset for ball detail panel
 
@@ -180,7 +181,7 @@ type Model struct {
 	pendingBallTags            string   // Comma-separated tags
 	pendingBallSession         int      // Index in session options (0=none, 1+ = session index)
 	pendingBallModelSize       int      // Index in model size options (0=default, 1=small, 2=medium, 3=large)
-	pendingBallAgentProvider   int      // Index in agent provider options (0=default, 1=claude, 2=opencode)
+	pendingBallAgentProvider   int      // Index in agent provider options (0=default, 1=claude, 2=opencode, 3=goose, 4=amp)
 	pendingBallModelOverride   int      // Index in model override options (0=default, 1=opus, 2=sonnet, 3=haiku)
 	pendingBallDependsOn       []string // Selected dependency ball IDs
 	pendingBallBlockingReason  int      // Index in blocking reason options (0=blank, 1=Human needed, 2=Waiting for dependency, 3=Needs research, 4=custom)
@@ -240,8 +241,12 @@ type Model struct {
 	agentMonitorStartTime   time.Time       // When the current agent run started
 	agentSpinner            spinner.Model   // Spinner for agent running animation
 	agentLogTailer          *LogTailer      // Log file tailer for streaming agent output
+	agentStateStream        *DaemonStateStream // Daemon state over the daemon API, nil when read from the state file
+	agentRemote             *RemoteDaemon      // Set when monitoring a daemon on another machine
 	agentDaemonError        string          // Error message from daemon (displayed prominently)
 	agentMetrics            *AgentMetricsState // Hook-provided metrics (files changed, tool counts, tokens)
+	agentProviderChoices    []string           // Providers offered by the provider switch, nil when it isn't open
+	agentProviderCursor     int                // Highlighted provider in the provider switch
 
 	// Time provider for testability
 	nowFunc func() time.Time // Can be overridden in tests
@@ -349,7 +354,44 @@ func InitialMonitorModel(store *session.Store, sessionStore *session.SessionStor
 	}
 }
 
+// InitialRemoteMonitorModel creates a model that monitors a loop of a daemon
+// on another machine through its API. There is no local project: the
+// monitor shows the loop's state and sends it control commands, but can't
+// show its output, and quitting it ends the program.
+func InitialRemoteMonitorModel(remote RemoteDaemon, sessionID string) Model {
+	return Model{
+		mode:           agentMonitorView,
+		activePanel:    BallsPanel,
+		selectedBalls:  make(map[string]bool),
+		activityLog:    make([]ActivityEntry, 0),
+		textInput:      textinput.New(),
+		contextInput:   newContextTextarea(),
+		nowFunc:        time.Now,
+		agentSpinner:   newAgentSpinner(),
+		runningDaemons: make(map[string]*DaemonInfo),
+		agentRemote:    &remote,
+		agentStatus: AgentStatus{
+			Running:   true,
+			SessionID: sessionID,
+		},
+	}
+}
+
+// monitorProjectDir returns the project of the loop the monitor shows
+func (m Model) monitorProjectDir() string {
+	if m.agentRemote != nil {
+		return m.agentRemote.ProjectDir
+	}
+	return m.store.ProjectDir()
+}
+
 func (m Model) Init() tea.Cmd {
+	if m.agentRemote != nil {
+		return tea.Batch(
+			watchDaemonStateCmd(m.agentRemote, m.agentRemote.ProjectDir, m.agentStatus.SessionID),
+			m.agentSpinner.Tick,
+		)
+	}
 	cmds := []tea.Cmd{
 		loadBalls(m.store, m.config, m.localOnly),
 		loadSessions(m.sessionStore, m.config, m.localOnly),
@@ -361,7 +403,7 @@ func (m Model) Init() tea.Cmd {
 	// If starting in monitor mode with a session, load daemon state, start spinner, and start log tail
 	// true = starting in monitor mode means reconnecting to existing session, read existing content
 	if m.mode == agentMonitorView && m.agentStatus.SessionID != "" && m.store != nil {
-		cmds = append(cmds, loadDaemonStateCmd(m.store.ProjectDir(), m.agentStatus.SessionID))
+		cmds = append(cmds, watchDaemonStateCmd(nil, m.store.ProjectDir(), m.agentStatus.SessionID))
 		cmds = append(cmds, m.agentSpinner.Tick)
 		cmds = append(cmds, startLogTailCmd(m.store.ProjectDir(), m.agentStatus.SessionID, true))
 		// Also load agent update for phase info and metrics
@@ -213,19 +213,16 @@ func (m Model) handleSplitSetPending() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	var cmds []tea.Cmd
 	for _, ball := range ballsToSet {
 		if err := ball.SetState(session.StatePending); err != nil {
 			m.message = "Error: " + err.Error()
 			return m, nil
 		}
-
-		store, err := session.NewStore(ball.WorkingDir)
-		if err != nil {
-			m.message = "Error: " + err.Error()
-			return m, nil
-		}
-		cmds = append(cmds, updateBall(store, ball))
+	}
+	cmd, err := updateBallsByProject(ballsToSet)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
 	}
 
 	if len(ballsToSet) == 1 {
@@ -237,7 +234,7 @@ func (m Model) handleSplitSetPending() (tea.Model, tea.Cmd) {
 	// Clear multi-select after operation
 	m.selectedBalls = make(map[string]bool)
 
-	return m, tea.Batch(cmds...)
+	return m, cmd
 }
 
 // handleSplitArchiveBall archives completed ball(s)
@@ -769,19 +766,16 @@ func (m Model) handleSplitStartBall() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	var cmds []tea.Cmd
 	for _, ball := range ballsToStart {
 		if err := ball.SetState(session.StateInProgress); err != nil {
 			m.message = "Error: " + err.Error()
 			return m, nil
 		}
-
-		store, err := session.NewStore(ball.WorkingDir)
-		if err != nil {
-			m.message = "Error: " + err.Error()
-			return m, nil
-		}
-		cmds = append(cmds, updateBall(store, ball))
+	}
+	cmd, err := updateBallsByProject(ballsToStart)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
 	}
 
 	if len(ballsToStart) == 1 {
@@ -793,7 +787,7 @@ func (m Model) handleSplitStartBall() (tea.Model, tea.Cmd) {
 	// Clear multi-select after operation
 	m.selectedBalls = make(map[string]bool)
 
-	return m, tea.Batch(cmds...)
+	return m, cmd
 }
 
 // handleSplitCompleteBall completes the selected ball(s) in split view and archives them
@@ -1310,12 +1304,16 @@ func (m Model) handleSplitEditItem() (tea.Model, tea.Cmd) {
 			m.pendingBallModelSize = 0 // Default
 		}
 
-		// Convert agent provider to index (blank=0, claude=1, opencode=2)
+		// Convert agent provider to index (blank=0, claude=1, opencode=2, goose=3, amp=4)
 		switch ball.AgentProvider {
 		case "claude":
 			m.pendingBallAgentProvider = 1
 		case "opencode":
 			m.pendingBallAgentProvider = 2
+		case "goose":
+			m.pendingBallAgentProvider = 3
+		case "amp":
+			m.pendingBallAgentProvider = 4
 		default:
 			m.pendingBallAgentProvider = 0 // Default
 		}
@@ -400,6 +400,9 @@ func (m Model) renderBallsPanel(width, height int) string {
 		prioritySuffix := ""
 		if m.showPriorityColumn {
 			prioritySuffix = fmt.Sprintf(" [%s]", string(ball.Priority)[0:1]) // First letter: l/m/h/u
+			if ball.IsAged() {
+				prioritySuffix = fmt.Sprintf(" [%s↑]", string(ball.Priority)[0:1]) // Raised by aging
+			}
 		}
 
 		tagsSuffix := ""
@@ -651,6 +654,9 @@ func (m Model) buildBallDetailLines(ball *session.Ball, width int) []string {
 	// Row 2: Priority and Title
 	priorityLabel := labelStyle.Render("Priority:")
 	priorityValue := string(ball.Priority)
+	if ball.IsAged() {
+		priorityValue += " ↑ aged"
+	}
 	titleLabel := labelStyle.Render("Title:")
 	titleValue := truncate(ball.Title, width-50)
 	lines = append(lines, fmt.Sprintf("  %s %s    %s %s", priorityLabel, valueStyle.Render(priorityValue), titleLabel, valueStyle.Render(titleValue)))
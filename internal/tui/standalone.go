@@ -673,18 +673,15 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 }
 
 func (m StandaloneBallModel) openDependencySelector() (tea.Model, tea.Cmd) {
-	balls, err := m.store.LoadBalls()
+	page, err := m.store.LoadBallsFiltered(session.BallQuery{
+		States: []session.BallState{session.StatePending, session.StateInProgress, session.StateBlocked},
+	})
 	if err != nil {
 		m.message = "Error loading balls: " + err.Error()
 		return m, nil
 	}
 
-	var selectableBalls []*session.Ball
-	for _, ball := range balls {
-		if ball.State != session.StateComplete && ball.State != session.StateResearched {
-			selectableBalls = append(selectableBalls, ball)
-		}
-	}
+	selectableBalls := page.Balls
 
 	if len(selectableBalls) == 0 {
 		m.message = "No non-complete balls to select as dependencies"
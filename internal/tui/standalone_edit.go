@@ -593,16 +593,18 @@ func (m StandaloneEditModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 }
 
 func (m StandaloneEditModel) openDependencySelector() (tea.Model, tea.Cmd) {
-	balls, err := m.store.LoadBalls()
+	page, err := m.store.LoadBallsFiltered(session.BallQuery{
+		States: []session.BallState{session.StatePending, session.StateInProgress, session.StateBlocked},
+	})
 	if err != nil {
 		m.message = "Error loading balls: " + err.Error()
 		return m, nil
 	}
 
 	var selectableBalls []*session.Ball
-	for _, ball := range balls {
-		// Exclude the ball being edited and complete balls
-		if ball.ID != m.ball.ID && ball.State != session.StateComplete && ball.State != session.StateResearched {
+	for _, ball := range page.Balls {
+		// Exclude the ball being edited
+		if ball.ID != m.ball.ID {
 			selectableBalls = append(selectableBalls, ball)
 		}
 	}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/agent/daemon"
 	"github.com/ohare93/juggle/internal/session"
 )
 
@@ -10106,3 +10108,224 @@ func TestEditBallClearBlockingReasonAlwaysSetsInProgress(t *testing.T) {
 		t.Errorf("expected BlockedReason to be empty, got '%s'", updatedBall.BlockedReason)
 	}
 }
+
+// Test Tab switches the help view to workflow examples and back
+func TestHelpViewTabShowsExamples(t *testing.T) {
+	model := Model{
+		mode:             splitHelpView,
+		width:            120,
+		height:           200,
+		helpScrollOffset: 3,
+	}
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m := newModel.(Model)
+	if !m.helpShowExamples {
+		t.Fatal("Expected Tab to show workflow examples")
+	}
+	if m.helpScrollOffset != 0 {
+		t.Errorf("Expected scroll offset reset, got %d", m.helpScrollOffset)
+	}
+
+	view := m.renderSplitHelpView()
+	for _, want := range []string{"Workflow Examples", "Overnight run", "juggle import spec", "juggle worktree add", "Tab = keybindings"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected examples help view to contain %q", want)
+		}
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if m.helpShowExamples {
+		t.Error("Expected second Tab to return to keybindings")
+	}
+}
+
+func TestDaemonPickerAttachesOnEnter(t *testing.T) {
+	daemons := []RunningDaemon{
+		{ProjectDir: "/a", SessionID: "first", StorageID: "first", PID: 10, Iteration: 1, MaxIterations: 5},
+		{ProjectDir: "/b", SessionID: "all", StorageID: "_all", PID: 20, Paused: true},
+	}
+	var m tea.Model = NewDaemonPickerModel(func() []RunningDaemon { return daemons })
+	m, _ = m.Update(m.Init()())
+
+	view := m.View()
+	for _, want := range []string{"first", "paused", "/b"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected picker view to contain %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to quit the picker")
+	}
+	selected := m.(DaemonPickerModel).Selected()
+	if selected == nil || selected.StorageID != "_all" || selected.ProjectDir != "/b" {
+		t.Errorf("Expected the second daemon to be selected, got %+v", selected)
+	}
+}
+
+func TestDaemonPickerQuitWithoutSelection(t *testing.T) {
+	var m tea.Model = NewDaemonPickerModel(func() []RunningDaemon { return nil })
+	m, _ = m.Update(m.Init()())
+	if !strings.Contains(m.View(), "No agent daemons are running") {
+		t.Errorf("Expected an empty picker to say so:\n%s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if m.(DaemonPickerModel).Selected() != nil {
+		t.Error("Expected no daemon to be selected")
+	}
+}
+
+func TestRemoteMonitorQuitsInsteadOfReturning(t *testing.T) {
+	m := InitialRemoteMonitorModel(RemoteDaemon{Addr: "localhost:7717", Token: "t", ProjectDir: "/remote/project"}, "feature")
+	if m.monitorProjectDir() != "/remote/project" {
+		t.Errorf("Expected the remote project, got %q", m.monitorProjectDir())
+	}
+
+	// No takeover: juggle agent attach runs on the daemon's machine
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if cmd != nil || !strings.Contains(updated.(Model).message, "on its machine") {
+		t.Errorf("Expected takeover to be refused, got message %q", updated.(Model).message)
+	}
+
+	// Cancelling asks first and stays on the monitor
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if updated.(Model).mode != agentMonitorView {
+		t.Errorf("Expected to stay on the monitor, got mode %v", updated.(Model).mode)
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatal("Expected q to quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected q to quit a remote monitor")
+	}
+}
+
+func TestMonitorSkipBallNamesShownBall(t *testing.T) {
+	dir := t.TempDir()
+	store, err := session.NewStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := InitialMonitorModel(store, nil, nil, true, nil, "feature", true)
+	m.agentStatus.Running = true
+	m.agentStatus.SessionID = "feature"
+
+	// Nothing to skip before the daemon reports a ball
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd != nil || updated.(Model).message != "No ball to skip yet" {
+		t.Errorf("Expected no skip without a ball, got message %q", updated.(Model).message)
+	}
+
+	m.agentStatus.CurrentBallID = "proj-7"
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd == nil {
+		t.Fatal("Expected n to send skip_ball")
+	}
+	cmd()
+	ctrl, err := daemon.ReadControlCommand(dir, "feature")
+	if err != nil || ctrl == nil {
+		t.Fatalf("Expected a control command, got %v", err)
+	}
+	ballID, _ := daemon.ParseSkipBallArgs(ctrl.Args)
+	if ctrl.Command != daemon.CmdSkipBall || ballID != "proj-7" {
+		t.Errorf("Expected skip_ball for proj-7, got %+v", ctrl)
+	}
+}
+
+func TestMonitorProviderSwitch(t *testing.T) {
+	dir := t.TempDir()
+	store, err := session.NewStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := InitialMonitorModel(store, nil, nil, true, nil, "feature", true)
+	m.agentStatus.Running = true
+	m.agentStatus.SessionID = "feature"
+	m.agentStatus.Provider = "claude"
+
+	key := func(m Model, k string) (Model, tea.Cmd) {
+		var msg tea.KeyMsg
+		switch k {
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, cmd := m.Update(msg)
+		return updated.(Model), cmd
+	}
+
+	m, _ = key(m, "P")
+	if len(m.agentProviderChoices) == 0 || m.agentProviderChoices[m.agentProviderCursor] != "claude" {
+		t.Fatalf("Expected the switch to open on claude, got %v at %d", m.agentProviderChoices, m.agentProviderCursor)
+	}
+	if slices.Contains(m.agentProviderChoices, "api") {
+		t.Error("Expected the tool-less api provider not to be offered")
+	}
+
+	// Esc closes the switch without leaving the monitor
+	closed, _ := key(m, "esc")
+	if closed.agentProviderChoices != nil || closed.mode != agentMonitorView {
+		t.Errorf("Expected esc to close only the switch, got choices %v, mode %v", closed.agentProviderChoices, closed.mode)
+	}
+
+	m, _ = key(m, "right")
+	want := m.agentProviderChoices[m.agentProviderCursor]
+	m, cmd := key(m, "enter")
+	if cmd == nil || m.agentProviderChoices != nil {
+		t.Fatal("Expected enter to send change_provider and close the switch")
+	}
+	cmd()
+	ctrl, err := daemon.ReadControlCommand(dir, "feature")
+	if err != nil || ctrl == nil {
+		t.Fatalf("Expected a control command, got %v", err)
+	}
+	if ctrl.Command != daemon.CmdChangeProvider || ctrl.Args != want {
+		t.Errorf("Expected change_provider %s, got %+v", want, ctrl)
+	}
+}
+
+func TestMonitorDaemonVersionMismatch(t *testing.T) {
+	store, err := session.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	m := InitialMonitorModel(store, nil, nil, true, nil, "feature", true)
+	m.width, m.height = 160, 40
+
+	// A state from before versioning: warn and say why there is no ETA
+	updated, _ := m.Update(daemonStateLoadedMsg{running: true, iteration: 2, maxIterations: 10})
+	view := updated.(Model).renderAgentMonitorView()
+	if !strings.Contains(view, "daemon was started by an older juggle") || !strings.Contains(view, "juggle agent stop feature") {
+		t.Errorf("Expected an older daemon warning with a restart hint, got:\n%s", view)
+	}
+	if !strings.Contains(view, "unknown (daemon too old to estimate)") {
+		t.Errorf("Expected the ETA to be marked unknown, got:\n%s", view)
+	}
+
+	// A newer daemon asks for an update
+	updated, _ = m.Update(daemonStateLoadedMsg{running: true, version: daemon.StateVersion + 1})
+	view = updated.(Model).renderAgentMonitorView()
+	if !strings.Contains(view, "daemon was started by a newer juggle") || !strings.Contains(view, "update juggle") {
+		t.Errorf("Expected a newer daemon warning, got:\n%s", view)
+	}
+
+	// A current daemon that hasn't estimated yet shows neither
+	updated, _ = m.Update(daemonStateLoadedMsg{running: true, version: daemon.StateVersion})
+	view = updated.(Model).renderAgentMonitorView()
+	if strings.Contains(view, "juggle (state version") || strings.Contains(view, "ETA:") {
+		t.Errorf("Expected no version warning or ETA, got:\n%s", view)
+	}
+}
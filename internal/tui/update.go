@@ -189,6 +189,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Reload balls
 		return m, loadBalls(m.store, m.config, m.localOnly)
 
+	case ballsUpdatedMsg:
+		if msg.err != nil {
+			m.message = "Error: " + msg.err.Error()
+			m.addActivity("Error: " + msg.err.Error())
+		} else if len(msg.balls) == 1 {
+			m.message = "Ball updated successfully"
+			m.addActivity("Ball updated: " + msg.balls[0].ID)
+		} else {
+			m.message = fmt.Sprintf("%d balls updated successfully", len(msg.balls))
+			m.addActivity(fmt.Sprintf("Balls updated: %d", len(msg.balls)))
+		}
+		// Reload balls
+		return m, loadBalls(m.store, m.config, m.localOnly)
+
 	case ballArchivedMsg:
 		if msg.err != nil {
 			m.message = "Error: " + msg.err.Error()
@@ -366,6 +380,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.addActivity("Sent daemon command: " + msg.command)
 		return m, nil
 
+	case agentTakeoverDoneMsg:
+		if msg.err != nil {
+			m.message = "Takeover failed: " + msg.err.Error()
+			m.addActivity("Takeover failed: " + msg.err.Error())
+			return m, nil
+		}
+		m.message = "Takeover ended, headless loop resumed"
+		m.addActivity("Interactive takeover ended")
+		return m, nil
+
 	case daemonControlErrorMsg:
 		// Control command failed
 		m.message = "Daemon control error: " + msg.err.Error()
@@ -375,8 +399,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case daemonStateLoadedMsg:
 		if msg.err != nil {
 			m.message = "Failed to load daemon state"
+			if m.agentRemote != nil {
+				m.agentDaemonError = "Can't reach the remote daemon: " + msg.err.Error()
+			}
 			return m, nil
 		}
+		if msg.stream != nil && msg.stream != m.agentStateStream {
+			if msg.stream.IsClosed() {
+				return m, nil // Left the monitor meanwhile
+			}
+			// First state of a new stream
+			if m.agentStateStream != nil {
+				m.agentStateStream.Close()
+			}
+			m.agentStateStream = msg.stream
+		}
 		// Update agent status from daemon state
 		m.agentStatus.Running = msg.running
 		m.agentStatus.Iteration = msg.iteration
@@ -388,11 +425,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.agentStatus.Model = msg.model
 		m.agentStatus.Provider = msg.provider
 		m.agentStatus.Status = msg.status
+		m.agentStatus.EstimatedEnd = msg.estimatedEnd
+		m.agentStatus.Schedule = msg.schedule
+		m.agentStatus.NextScheduledRun = msg.nextRun
+		m.agentStatus.StateVersion = msg.version
 		m.agentMonitorPaused = msg.paused
 		// Use daemon's actual start time for elapsed calculation (not TUI connection time)
 		if !msg.startedAt.IsZero() {
 			m.agentMonitorStartTime = msg.startedAt
 		}
+		if msg.stream != nil {
+			return m, nextDaemonStateCmd(msg.stream)
+		}
+		return m, nil
+
+	case daemonStateStreamEndedMsg:
+		// The run ended or the monitor was left; the state file watcher takes over
+		msg.stream.Close()
+		if m.agentStateStream == msg.stream {
+			m.agentStateStream = nil
+			// Nothing takes over for a remote daemon
+			if msg.stream.remote && m.agentStatus.Running {
+				m.agentStatus.Running = false
+				m.agentDaemonError = "Lost the connection to the remote daemon"
+			}
+		}
 		return m, nil
 
 	case agentUpdateLoadedMsg:
@@ -768,6 +825,7 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "?":
 		// Show comprehensive help view
 		m.helpScrollOffset = 0 // Reset scroll position
+		m.helpShowExamples = false
 		m.mode = splitHelpView
 		return m, nil
 
@@ -914,7 +972,7 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// true = reconnecting, read existing log content
 				cmds := []tea.Cmd{m.agentSpinner.Tick}
 				if m.store != nil {
-					cmds = append(cmds, loadDaemonStateCmd(m.store.ProjectDir(), targetSessionID))
+					cmds = append(cmds, watchDaemonStateCmd(nil, m.store.ProjectDir(), targetSessionID))
 					cmds = append(cmds, startLogTailCmd(m.store.ProjectDir(), targetSessionID, true))
 				}
 				// Also load agent update for phase info
@@ -1333,8 +1391,10 @@ func (m Model) handleWatcherEvent(event watcher.Event) (tea.Model, tea.Cmd) {
 		// but log it for awareness
 
 	case watcher.AgentStateChanged:
-		// Daemon state file changed - update monitor view if active
-		if event.SessionID != "" && event.SessionID == m.agentStatus.SessionID {
+		// Daemon state file changed - update monitor view if active, unless the
+		// state already streams over the daemon API
+		streaming := m.agentStateStream != nil && m.agentStateStream.sessionID == event.SessionID
+		if event.SessionID != "" && event.SessionID == m.agentStatus.SessionID && !streaming {
 			// Load the updated daemon state
 			cmds = append(cmds, loadDaemonStateCmd(m.store.ProjectDir(), event.SessionID))
 		}
@@ -1370,6 +1430,12 @@ func (m Model) handleSplitHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = splitView
 		return m, nil
 
+	case "tab":
+		// Switch between keybindings and workflow examples
+		m.helpShowExamples = !m.helpShowExamples
+		m.helpScrollOffset = 0
+		return m, nil
+
 	case "j", "down":
 		// Scroll down
 		m.helpScrollOffset++
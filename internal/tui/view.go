@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/examples"
 	"github.com/ohare93/juggle/internal/session"
 )
 
@@ -569,6 +570,9 @@ func (m Model) renderSplitHelpView() string {
 	var b strings.Builder
 
 	title := titleStyle.Render("Juggle TUI - Complete Keybindings Reference")
+	if m.helpShowExamples {
+		title = titleStyle.Render("Juggle - Workflow Examples")
+	}
 	b.WriteString(title + "\n\n")
 
 	// Build all help sections - organized by category
@@ -674,6 +678,7 @@ func (m Model) renderSplitHelpView() string {
 				{"P", "Toggle project scope (local ↔ all projects)"},
 				{"R", "Refresh / Reload data"},
 				{"?", "Toggle this help"},
+				{"Tab", "Switch help between keybindings and workflow examples"},
 			},
 		},
 		{
@@ -716,14 +721,18 @@ func (m Model) renderSplitHelpView() string {
 
 	// Build content lines
 	var lines []string
-	for _, section := range sections {
-		lines = append(lines, titleStyle.Render(section.title))
-		for _, item := range section.items {
-			keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")).Width(15)
-			line := fmt.Sprintf("  %s  %s", keyStyle.Render(item.key), item.desc)
-			lines = append(lines, line)
+	if m.helpShowExamples {
+		lines = m.helpExampleLines()
+	} else {
+		for _, section := range sections {
+			lines = append(lines, titleStyle.Render(section.title))
+			for _, item := range section.items {
+				keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")).Width(15)
+				line := fmt.Sprintf("  %s  %s", keyStyle.Render(item.key), item.desc)
+				lines = append(lines, line)
+			}
+			lines = append(lines, "") // Empty line between sections
 		}
-		lines = append(lines, "") // Empty line between sections
 	}
 
 	// Calculate visible area
@@ -771,11 +780,49 @@ func (m Model) renderSplitHelpView() string {
 	// Footer
 	b.WriteString("\n")
 	footerStyle := lipgloss.NewStyle().Faint(true)
-	b.WriteString(footerStyle.Render("j/k = scroll | ? or Esc = close help"))
+	if m.helpShowExamples {
+		b.WriteString(footerStyle.Render("j/k = scroll | Tab = keybindings | ? or Esc = close help"))
+	} else {
+		b.WriteString(footerStyle.Render("j/k = scroll | Tab = workflow examples | ? or Esc = close help"))
+	}
 
 	return b.String()
 }
 
+// helpExampleLines renders the embedded workflow examples for the help view,
+// with each example's commands ready to copy
+func (m Model) helpExampleLines() []string {
+	commandStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	commentStyle := lipgloss.NewStyle().Faint(true)
+	summaryStyle := lipgloss.NewStyle()
+	if m.width > 4 {
+		summaryStyle = summaryStyle.Width(m.width - 4)
+	}
+
+	var lines []string
+	for _, example := range examples.All() {
+		lines = append(lines, titleStyle.Render(example.Title))
+		for _, line := range strings.Split(summaryStyle.Render(example.Summary), "\n") {
+			lines = append(lines, "  "+line)
+		}
+		lines = append(lines, "")
+		for _, command := range example.Commands() {
+			switch {
+			case command == "":
+				lines = append(lines, "")
+			case strings.HasPrefix(command, "#"):
+				lines = append(lines, "    "+commentStyle.Render(command))
+			default:
+				lines = append(lines, "    "+commandStyle.Render(command))
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, "  "+commentStyle.Render("More: juggle examples "+example.Topic))
+		lines = append(lines, "") // Empty line between examples
+	}
+	return lines
+}
+
 // renderHistoryView renders the agent run history view
 func (m Model) renderHistoryView() string {
 	var b strings.Builder
@@ -879,6 +926,9 @@ func (m Model) renderHistoryView() string {
 		if record.TotalWaitTime > 0 {
 			b.WriteString(detailStyle.Render(fmt.Sprintf("Rate Limit Wait: %s\n", formatDuration(record.TotalWaitTime))))
 		}
+		if usage := session.FormatUsage(record.InputTokens, record.OutputTokens, record.CostUSD); usage != "" {
+			b.WriteString(detailStyle.Render(fmt.Sprintf("Usage: %s\n", usage)))
+		}
 		if record.OutputFile != "" {
 			b.WriteString(detailStyle.Render(fmt.Sprintf("Output: %s\n", record.OutputFile)))
 		}
@@ -906,6 +956,8 @@ func formatHistoryResult(result string) string {
 		return lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("⟳ MaxIter")
 	case "rate_limit":
 		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ RateLimit")
+	case "budget_exceeded":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render("$ Budget")
 	case "cancelled":
 		return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("✗ Cancelled")
 	case "error":
@@ -1329,7 +1381,7 @@ func (m Model) renderUnifiedBallFormView() string {
 	b.WriteString("\n")
 
 	// --- Agent Provider field ---
-	agentProviders := []string{"(default)", "claude", "opencode"}
+	agentProviders := []string{"(default)", "claude", "opencode", "goose", "amp"}
 	labelStyle = normalStyle
 	if m.pendingBallFormField == fieldAgentProvider {
 		labelStyle = activeFieldStyle
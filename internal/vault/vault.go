@@ -0,0 +1,291 @@
+// Package vault renders balls and sessions as Markdown notes with YAML front
+// matter, for browsing and editing them in Obsidian or any other Markdown
+// knowledge base, and parses edited ball notes back.
+//
+// A ball note looks like this:
+//
+//	---
+//	id: myapp-a1b2c3d4
+//	state: in_progress
+//	priority: high
+//	tags: [auth]
+//	updated: 2026-01-02T15:04:05.123Z
+//	---
+//
+//	# Add login form
+//
+//	Users sign in with email and password.
+//
+//	## Acceptance Criteria
+//
+//	- [ ] Form validates email
+//
+//	## Sessions
+//
+//	- [[auth]]
+//
+// The H1 is the title, text before the first H2 is the context, and list
+// items under "Acceptance Criteria" are the criteria. The Sessions and
+// Depends On sections are wikilinks generated from the front matter and are
+// ignored when parsing. "updated" is the ball's last activity when the note
+// was written, which tells a stale note from an edited one.
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"gopkg.in/yaml.v3"
+)
+
+// Folders inside the vault directory
+const (
+	BallsDir    = "balls"
+	SessionsDir = "sessions"
+)
+
+// acHeading is the section holding a ball's acceptance criteria
+const acHeading = "Acceptance Criteria"
+
+// frontMatter is the YAML block at the top of a ball note
+type frontMatter struct {
+	ID            string   `yaml:"id,omitempty"`
+	State         string   `yaml:"state,omitempty"`
+	Priority      string   `yaml:"priority,omitempty"`
+	BlockedReason string   `yaml:"blocked_reason,omitempty"`
+	ModelSize     string   `yaml:"model_size,omitempty"`
+	Tags          []string `yaml:"tags,omitempty"`
+	DependsOn     []string `yaml:"depends_on,omitempty"`
+	Updated       string   `yaml:"updated,omitempty"`
+}
+
+// Note is a ball note parsed back into ball fields
+type Note struct {
+	ID                 string // Empty for a note written by hand that has no ball yet
+	Title              string
+	Context            string
+	AcceptanceCriteria []string
+	State              session.BallState
+	Priority           session.Priority
+	BlockedReason      string
+	ModelSize          session.ModelSize
+	Tags               []string
+	DependsOn          []string
+	Updated            string
+}
+
+// Stamp formats a ball's last activity the way notes record it
+func Stamp(ball *session.Ball) string {
+	return ball.LastActivity.UTC().Format(time.RFC3339Nano)
+}
+
+// RenderBall renders a ball as a note. sessions are the ball's tags that are
+// sessions, linked from the note.
+func RenderBall(ball *session.Ball, sessions []string) []byte {
+	fm := frontMatter{
+		ID:            ball.ID,
+		State:         string(ball.State),
+		Priority:      string(ball.Priority),
+		BlockedReason: ball.BlockedReason,
+		ModelSize:     string(ball.ModelSize),
+		Tags:          ball.Tags,
+		DependsOn:     ball.DependsOn,
+		Updated:       Stamp(ball),
+	}
+
+	var b bytes.Buffer
+	writeFrontMatter(&b, fm)
+	fmt.Fprintf(&b, "# %s\n", ball.Title)
+	if context := strings.TrimSpace(ball.Context); context != "" {
+		fmt.Fprintf(&b, "\n%s\n", context)
+	}
+
+	if len(ball.AcceptanceCriteria) > 0 {
+		check := " "
+		if ball.State == session.StateComplete || ball.State == session.StateResearched {
+			check = "x"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", acHeading)
+		for _, ac := range ball.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- [%s] %s\n", check, ac)
+		}
+	}
+	writeLinks(&b, "Sessions", sessions)
+	writeLinks(&b, "Depends On", ball.DependsOn)
+	return b.Bytes()
+}
+
+// RenderSession renders a session as a note linking to its balls
+func RenderSession(s *session.JuggleSession, balls []*session.Ball) []byte {
+	var b bytes.Buffer
+	writeFrontMatter(&b, map[string]string{"id": s.ID, "description": s.Description})
+	fmt.Fprintf(&b, "# %s\n", s.ID)
+	if s.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", s.Description)
+	}
+	if context := strings.TrimSpace(s.Context); context != "" {
+		fmt.Fprintf(&b, "\n%s\n", context)
+	}
+	if len(balls) > 0 {
+		b.WriteString("\n## Balls\n\n")
+		for _, ball := range balls {
+			fmt.Fprintf(&b, "- [[%s]] %s (%s)\n", ball.ID, ball.Title, ball.State)
+		}
+	}
+	return b.Bytes()
+}
+
+// writeFrontMatter writes v as a YAML front matter block
+func writeFrontMatter(b *bytes.Buffer, v any) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		data = nil
+	}
+	b.WriteString("---\n")
+	b.Write(data)
+	b.WriteString("---\n\n")
+}
+
+// writeLinks writes a section of wikilinks, if there are any
+func writeLinks(b *bytes.Buffer, heading string, targets []string) {
+	if len(targets) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", heading)
+	for _, target := range targets {
+		fmt.Fprintf(b, "- [[%s]]\n", target)
+	}
+}
+
+// listItemPattern matches a bullet, numbered or checkbox list item
+var listItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.+)$`)
+
+// ParseNote parses a ball note. A note without front matter is read as a new
+// ball with only a title and body.
+func ParseNote(data []byte) (*Note, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var fm frontMatter
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		end := strings.Index(rest, "\n---\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n---") {
+				return nil, fmt.Errorf("front matter is not closed with ---")
+			}
+			end = len(rest) - len("\n---")
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+			return nil, fmt.Errorf("invalid front matter: %w", err)
+		}
+		text = strings.TrimPrefix(rest[end:], "\n---")
+	}
+
+	note := &Note{
+		ID:            strings.TrimSpace(fm.ID),
+		State:         session.BallState(strings.TrimSpace(fm.State)),
+		Priority:      session.Priority(strings.TrimSpace(fm.Priority)),
+		BlockedReason: strings.TrimSpace(fm.BlockedReason),
+		ModelSize:     session.ModelSize(strings.TrimSpace(fm.ModelSize)),
+		Tags:          fm.Tags,
+		DependsOn:     fm.DependsOn,
+		Updated:       fm.Updated,
+	}
+
+	var context []string
+	section := ""
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# ") && note.Title == "" && section == "":
+			note.Title = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "## "):
+			section = strings.TrimSpace(line[3:])
+		case section == "":
+			context = append(context, line)
+		case strings.EqualFold(section, acHeading):
+			if m := listItemPattern.FindStringSubmatch(line); m != nil {
+				note.AcceptanceCriteria = append(note.AcceptanceCriteria, strings.TrimSpace(m[1]))
+			}
+		}
+	}
+	note.Context = strings.TrimSpace(strings.Join(context, "\n"))
+	return note, nil
+}
+
+// Validate checks the note's fields can be applied to a ball
+func (n *Note) Validate() error {
+	if n.Title == "" {
+		return fmt.Errorf("note has no title (a line starting with \"# \")")
+	}
+	if n.State != "" && !session.ValidateBallState(string(n.State)) {
+		return fmt.Errorf("invalid state %q", n.State)
+	}
+	if n.Priority != "" && !session.ValidatePriority(string(n.Priority)) {
+		return fmt.Errorf("invalid priority %q", n.Priority)
+	}
+	if !session.ValidateModelSize(string(n.ModelSize)) {
+		return fmt.Errorf("invalid model_size %q", n.ModelSize)
+	}
+	return nil
+}
+
+// Differs reports whether the note has edits the ball doesn't have
+func (n *Note) Differs(ball *session.Ball) bool {
+	return n.Title != ball.Title ||
+		n.Context != strings.TrimSpace(ball.Context) ||
+		!equalStrings(n.AcceptanceCriteria, ball.AcceptanceCriteria) ||
+		(n.State != "" && n.State != ball.State) ||
+		(n.Priority != "" && n.Priority != ball.Priority) ||
+		n.BlockedReason != ball.BlockedReason ||
+		n.ModelSize != ball.ModelSize ||
+		!equalStrings(n.Tags, ball.Tags) ||
+		!equalStrings(n.DependsOn, ball.DependsOn)
+}
+
+// Apply copies the note's fields onto the ball. Call Validate first.
+func (n *Note) Apply(ball *session.Ball) {
+	ball.Title = n.Title
+	ball.Context = n.Context
+	ball.AcceptanceCriteria = n.AcceptanceCriteria
+	if n.Priority != "" {
+		ball.Priority = n.Priority
+	}
+	ball.ModelSize = n.ModelSize
+	ball.Tags = n.Tags
+	if ball.Tags == nil {
+		ball.Tags = []string{}
+	}
+	ball.DependsOn = n.DependsOn
+
+	switch {
+	case n.State == "" || n.State == ball.State:
+		if ball.State == session.StateBlocked {
+			ball.BlockedReason = n.BlockedReason
+		}
+	case n.State == session.StateComplete:
+		ball.MarkComplete(ball.CompletionNote)
+	case n.State == session.StateResearched:
+		ball.MarkResearched(ball.Output)
+	case n.State == session.StateBlocked:
+		_ = ball.SetBlocked(n.BlockedReason)
+	default:
+		_ = ball.SetState(n.State)
+	}
+	ball.UpdateActivity()
+}
+
+// equalStrings compares two lists, treating nil and empty as equal
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package vault
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func testBall() *session.Ball {
+	return &session.Ball{
+		ID:                 "myapp-a1b2",
+		Title:              "Add login form",
+		Context:            "Users sign in with email.\n\nSee the design doc.",
+		AcceptanceCriteria: []string{"Form validates email", "Errors are shown inline"},
+		Priority:           session.PriorityHigh,
+		State:              session.StateInProgress,
+		Tags:               []string{"auth", "frontend"},
+		DependsOn:          []string{"myapp-c3d4"},
+		LastActivity:       time.Date(2026, 1, 2, 15, 4, 5, 123000000, time.UTC),
+	}
+}
+
+func TestRenderAndParseBall(t *testing.T) {
+	ball := testBall()
+	data := string(RenderBall(ball, []string{"auth"}))
+
+	for _, want := range []string{
+		"id: myapp-a1b2\n",
+		"updated: \"2026-01-02T15:04:05.123Z\"\n",
+		"# Add login form\n",
+		"- [ ] Form validates email\n",
+		"## Sessions\n\n- [[auth]]\n",
+		"## Depends On\n\n- [[myapp-c3d4]]\n",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Expected note to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	note, err := ParseNote([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseNote failed: %v", err)
+	}
+	if note.ID != ball.ID || note.Updated != Stamp(ball) {
+		t.Errorf("Unexpected front matter: %+v", note)
+	}
+	if note.Differs(ball) {
+		t.Errorf("Expected a rendered note to match its ball, got %+v", note)
+	}
+}
+
+func TestParseNote_Edits(t *testing.T) {
+	ball := testBall()
+	data := strings.Replace(string(RenderBall(ball, nil)), "priority: high", "priority: urgent", 1)
+	data = strings.Replace(data, "- [ ] Errors are shown inline\n", "- [ ] Errors are shown inline\n* [x] Submit is disabled while loading\n", 1)
+
+	note, err := ParseNote([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseNote failed: %v", err)
+	}
+	if !note.Differs(ball) {
+		t.Fatal("Expected the edited note to differ from the ball")
+	}
+	if err := note.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	note.Apply(ball)
+	if ball.Priority != session.PriorityUrgent || len(ball.AcceptanceCriteria) != 3 || ball.AcceptanceCriteria[2] != "Submit is disabled while loading" {
+		t.Errorf("Expected edits applied, got %+v", ball)
+	}
+}
+
+func TestParseNote_NewNote(t *testing.T) {
+	note, err := ParseNote([]byte("# Write release notes\n\nFor 2.0.\n\n## Acceptance Criteria\n\n1. Mention the new vault sync\n"))
+	if err != nil {
+		t.Fatalf("ParseNote failed: %v", err)
+	}
+	if note.ID != "" || note.Title != "Write release notes" || note.Context != "For 2.0." ||
+		len(note.AcceptanceCriteria) != 1 || note.AcceptanceCriteria[0] != "Mention the new vault sync" {
+		t.Errorf("Unexpected note: %+v", note)
+	}
+}
+
+func TestParseNote_Invalid(t *testing.T) {
+	if _, err := ParseNote([]byte("---\nid: x\n# no closing line\n")); err == nil {
+		t.Error("Expected an error for unclosed front matter")
+	}
+
+	note, err := ParseNote([]byte("---\nstate: finished\n---\n\n# Title\n"))
+	if err != nil {
+		t.Fatalf("ParseNote failed: %v", err)
+	}
+	if err := note.Validate(); err == nil {
+		t.Error("Expected an invalid state to fail validation")
+	}
+	if err := (&Note{}).Validate(); err == nil {
+		t.Error("Expected a note without a title to fail validation")
+	}
+}
+
+func TestApply_StateChanges(t *testing.T) {
+	ball := testBall()
+	(&Note{Title: ball.Title, State: session.StateBlocked, BlockedReason: "waiting on design"}).Apply(ball)
+	if ball.State != session.StateBlocked || ball.BlockedReason != "waiting on design" {
+		t.Errorf("Expected blocked with reason, got %s (%q)", ball.State, ball.BlockedReason)
+	}
+
+	(&Note{Title: ball.Title, State: session.StateComplete}).Apply(ball)
+	if ball.State != session.StateComplete || ball.CompletedAt == nil || ball.BlockedReason != "" {
+		t.Errorf("Expected complete, got %+v", ball)
+	}
+}
+
+func TestRenderSession(t *testing.T) {
+	s := &session.JuggleSession{ID: "auth", Description: "Login and signup"}
+	data := string(RenderSession(s, []*session.Ball{testBall()}))
+	if !strings.Contains(data, "# auth\n") || !strings.Contains(data, "- [[myapp-a1b2]] Add login form (in_progress)\n") {
+		t.Errorf("Unexpected session note:\n%s", data)
+	}
+}
@@ -2,7 +2,10 @@ package vcs
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -249,3 +252,206 @@ func (g *GitBackend) GetCurrentRevision(projectDir string) (string, error) {
 
 	return result, nil
 }
+
+// GetSnapshotRevision returns the full commit hash of HEAD.
+func (g *GitBackend) GetSnapshotRevision(projectDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResetToRevision hard-resets the current branch and working tree to the given revision.
+func (g *GitBackend) ResetToRevision(projectDir, revision string) error {
+	cmd := exec.Command("git", "reset", "--hard", revision)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git reset failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// DiffStat counts files and lines changed since the given revision, including untracked files.
+func (g *GitBackend) DiffStat(projectDir, fromRevision string) (files, lines int, err error) {
+	cmd := exec.Command("git", "diff", "--numstat", fromRevision)
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	// Format: <added>\t<deleted>\t<path>, binary files show "-" for counts
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || strings.HasPrefix(fields[2], juggleDirPrefix) {
+			continue
+		}
+		files++
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		lines += added + deleted
+	}
+
+	// Untracked files don't show in git diff, count them as fully added
+	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	cmd.Dir = projectDir
+	output, err = cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("git ls-files failed: %w", err)
+	}
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		files++
+		if data, err := os.ReadFile(filepath.Join(projectDir, path)); err == nil {
+			lines += strings.Count(string(data), "\n")
+		}
+	}
+
+	return files, lines, nil
+}
+
+// ChangedFiles lists files changed since the given revision, including untracked files.
+func (g *GitBackend) ChangedFiles(projectDir, fromRevision string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", fromRevision)
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	untrackedCmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	untrackedCmd.Dir = projectDir
+	untracked, err := untrackedCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(string(output)+"\n"+string(untracked), "\n") {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// Diff returns the unified diff since the given revision, with untracked files shown as new.
+func (g *GitBackend) Diff(projectDir, fromRevision string) (string, error) {
+	cmd := exec.Command("git", "diff", fromRevision, "--", ".", ":(exclude)"+juggleDirPrefix)
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+
+	var diff strings.Builder
+	diff.Write(output)
+
+	untrackedCmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
+	untrackedCmd.Dir = projectDir
+	untracked, err := untrackedCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-files failed: %w", err)
+	}
+	for _, path := range strings.Split(strings.TrimSpace(string(untracked)), "\n") {
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		// --no-index exits 1 when the files differ, which is always the case here
+		fileCmd := exec.Command("git", "diff", "--no-index", "--", "/dev/null", path)
+		fileCmd.Dir = projectDir
+		fileDiff, _ := fileCmd.Output()
+		diff.Write(fileDiff)
+	}
+
+	return diff.String(), nil
+}
+
+// DiffRevisions returns the diff between two commits.
+func (g *GitBackend) DiffRevisions(projectDir, fromRevision, toRevision string) (string, error) {
+	cmd := exec.Command("git", "diff", fromRevision, toRevision, "--", ".", ":(exclude)"+juggleDirPrefix)
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// AddWorkspace creates a git worktree at workspaceDir on the given branch.
+func (g *GitBackend) AddWorkspace(projectDir, workspaceDir, name string) error {
+	args := []string{"worktree", "add", "-b", name, workspaceDir}
+	verify := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	verify.Dir = projectDir
+	if verify.Run() == nil {
+		// Pick up where a previous run on this branch left off
+		args = []string{"worktree", "add", workspaceDir, name}
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git worktree add failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// RemoveWorkspace removes the git worktree at workspaceDir, keeping its branch.
+func (g *GitBackend) RemoveWorkspace(projectDir, workspaceDir, name string) error {
+	cmd := exec.Command("git", "worktree", "remove", "--force", workspaceDir)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git worktree remove failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// Churn counts the files changed by each commit on the current branch since the given time.
+func (g *GitBackend) Churn(projectDir string, since time.Time) (map[string]int, error) {
+	cmd := exec.Command("git", "log", "--since="+since.Format(time.RFC3339), "--format=", "--name-only")
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	churn := make(map[string]int)
+	for _, path := range strings.Split(string(output), "\n") {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		churn[path]++
+	}
+	return churn, nil
+}
+
+// ListFiles lists tracked and untracked files, leaving out those .gitignore excludes.
+func (g *GitBackend) ListFiles(projectDir string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = projectDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(string(output), "\n") {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
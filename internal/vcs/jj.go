@@ -2,8 +2,10 @@ package vcs
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // JJBackend implements VCS for Jujutsu (jj).
@@ -147,3 +149,173 @@ func (j *JJBackend) GetCurrentRevision(projectDir string) (string, error) {
 	}
 	return strings.TrimSpace(string(output)), nil
 }
+
+// GetSnapshotRevision returns the commit_id of the working copy's parent.
+func (j *JJBackend) GetSnapshotRevision(projectDir string) (string, error) {
+	cmd := exec.Command("jj", "log", "-r", "@-", "--no-graph", "-T", "commit_id")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("jj log failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResetToRevision starts a new working copy on top of the given revision.
+func (j *JJBackend) ResetToRevision(projectDir, revision string) error {
+	cmd := exec.Command("jj", "new", revision)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("jj new %s failed: %s: %w", revision, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// DiffStat counts files and lines changed between the given revision and the working copy.
+func (j *JJBackend) DiffStat(projectDir, fromRevision string) (files, lines int, err error) {
+	diff, err := j.Diff(projectDir, fromRevision)
+	if err != nil {
+		return 0, 0, err
+	}
+	summary := SummarizeDiff(diff)
+	return len(summary.Files), summary.Lines, nil
+}
+
+// ChangedFiles lists files changed between the given revision and the working copy.
+func (j *JJBackend) ChangedFiles(projectDir, fromRevision string) ([]string, error) {
+	cmd := exec.Command("jj", "diff", "--from", fromRevision, "--to", "@", "--name-only")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("jj diff failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(string(output), "\n") {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// Diff returns the git-format diff between the given revision and the working copy.
+func (j *JJBackend) Diff(projectDir, fromRevision string) (string, error) {
+	return j.DiffRevisions(projectDir, fromRevision, "@")
+}
+
+// DiffRevisions returns the git-format diff between two revisions.
+func (j *JJBackend) DiffRevisions(projectDir, fromRevision, toRevision string) (string, error) {
+	cmd := exec.Command("jj", "diff", "--from", fromRevision, "--to", toRevision, "--git")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("jj diff failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	// Drop per-file sections for juggle's own state
+	var diff strings.Builder
+	skip := false
+	for _, line := range strings.SplitAfter(string(output), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			fields := strings.Fields(line)
+			skip = len(fields) >= 3 && strings.HasPrefix(strings.TrimPrefix(fields[2], "a/"), juggleDirPrefix)
+		}
+		if !skip {
+			diff.WriteString(line)
+		}
+	}
+	return diff.String(), nil
+}
+
+// AddWorkspace creates a jj workspace at workspaceDir.
+func (j *JJBackend) AddWorkspace(projectDir, workspaceDir, name string) error {
+	cmd := exec.Command("jj", "workspace", "add", "--name", name, workspaceDir)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("jj workspace add failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// RemoveWorkspace forgets the jj workspace and deletes its directory.
+func (j *JJBackend) RemoveWorkspace(projectDir, workspaceDir, name string) error {
+	cmd := exec.Command("jj", "workspace", "forget", name)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("jj workspace forget failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	if err := os.RemoveAll(workspaceDir); err != nil {
+		return fmt.Errorf("failed to remove workspace directory: %w", err)
+	}
+	return nil
+}
+
+// Churn counts the files changed by each ancestor of the working copy committed since the given time.
+func (j *JJBackend) Churn(projectDir string, since time.Time) (map[string]int, error) {
+	revset := fmt.Sprintf(`::@ & committer_date(after:"%s")`, since.Format(time.RFC3339))
+	cmd := exec.Command("jj", "log", "--no-graph", "-r", revset, "-T", "", "--summary")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("jj log failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	churn := make(map[string]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		path := jjSummaryPath(line)
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		churn[path]++
+	}
+	return churn, nil
+}
+
+// jjSummaryPath returns the path a "jj log --summary" line changed. Lines are
+// "<status> <path>"; renames and copies are "R dir/{old => new}.go", which
+// resolve to the new path.
+func jjSummaryPath(line string) string {
+	status, path, ok := strings.Cut(strings.TrimSpace(line), " ")
+	if !ok || len(status) != 1 {
+		return ""
+	}
+	prefix, rest, ok := strings.Cut(path, "{")
+	if !ok {
+		return path
+	}
+	renamed, suffix, ok := strings.Cut(rest, "}")
+	if !ok {
+		return path
+	}
+	if _, to, ok := strings.Cut(renamed, " => "); ok {
+		renamed = to
+	}
+	return strings.TrimPrefix(strings.ReplaceAll(prefix+renamed+suffix, "//", "/"), "/")
+}
+
+// ListFiles lists the files in the working-copy commit, which jj snapshots
+// without the ones .gitignore excludes.
+func (j *JJBackend) ListFiles(projectDir string) ([]string, error) {
+	cmd := exec.Command("jj", "file", "list")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("jj file list failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(string(output), "\n") {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.HasPrefix(path, juggleDirPrefix) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // VCSType represents the version control system type.
@@ -69,6 +70,85 @@ type VCS interface {
 	// For jj: returns the change_id of the working copy
 	// For git: returns the current commit hash or branch name
 	GetCurrentRevision(projectDir string) (string, error)
+
+	// GetSnapshotRevision returns an immutable identifier for the last committed state,
+	// suitable for restoring later with ResetToRevision.
+	// For git: returns the full commit hash of HEAD
+	// For jj: returns the commit_id of the working copy's parent (@-)
+	GetSnapshotRevision(projectDir string) (string, error)
+
+	// ResetToRevision discards all work made after the given revision.
+	// For git: runs "git reset --hard <revision>" on the current branch
+	// For jj: runs "jj new <revision>", leaving later changes reachable but abandoned as the working copy
+	ResetToRevision(projectDir, revision string) error
+
+	// DiffStat returns the number of changed files and changed lines (added + deleted)
+	// between the given revision and the working copy, including uncommitted changes.
+	// Juggle's own .juggle/ directory is excluded from the count.
+	DiffStat(projectDir, fromRevision string) (files, lines int, err error)
+
+	// ChangedFiles returns the project-relative paths changed between the given revision
+	// and the working copy, including untracked files. Excludes .juggle/.
+	ChangedFiles(projectDir, fromRevision string) ([]string, error)
+
+	// Diff returns a git-style unified diff between the given revision and the
+	// working copy, including untracked files as additions. Excludes .juggle/.
+	Diff(projectDir, fromRevision string) (string, error)
+
+	// DiffRevisions returns a git-style unified diff between two revisions, such
+	// as the snapshot revisions taken before and after an agent run. Excludes .juggle/.
+	DiffRevisions(projectDir, fromRevision, toRevision string) (string, error)
+
+	// AddWorkspace creates a separate working copy at workspaceDir, starting from the
+	// current revision, so another agent can work without touching projectDir.
+	// For git: runs "git worktree add" on a branch called name (reused if it exists)
+	// For jj: runs "jj workspace add --name <name>"
+	AddWorkspace(projectDir, workspaceDir, name string) error
+
+	// RemoveWorkspace deletes a working copy created by AddWorkspace.
+	// Committed work stays reachable: on the branch for git, in the repo for jj.
+	RemoveWorkspace(projectDir, workspaceDir, name string) error
+
+	// Churn returns how many commits since the given time touched each
+	// project-relative file path. Excludes .juggle/.
+	// For git: counts "git log --since --name-only" entries
+	// For jj: counts "jj log --summary" entries of the working copy's ancestors
+	Churn(projectDir string, since time.Time) (map[string]int, error)
+
+	// ListFiles returns the project-relative paths of the working copy's files,
+	// tracked or not, leaving out ignored files. Excludes .juggle/.
+	// For git: runs "git ls-files --cached --others --exclude-standard"
+	// For jj: runs "jj file list" (jj tracks every file that isn't ignored)
+	ListFiles(projectDir string) ([]string, error)
+}
+
+// juggleDirPrefix is excluded from diff statistics so ball and progress updates don't count
+const juggleDirPrefix = ".juggle/"
+
+// DiffSummary is what a unified diff changes: its files and the number of
+// lines added or deleted
+type DiffSummary struct {
+	Files []string
+	Lines int
+}
+
+// SummarizeDiff reads the changed files and lines from a git-style unified diff
+func SummarizeDiff(diff string) DiffSummary {
+	var summary DiffSummary
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			// Format: diff --git a/<path> b/<path>
+			if fields := strings.Fields(line); len(fields) >= 3 {
+				summary.Files = append(summary.Files, strings.TrimPrefix(fields[2], "a/"))
+			}
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			continue
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+			summary.Lines++
+		}
+	}
+	return summary
 }
 
 // GetBackend returns the appropriate VCS backend for the given type.
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestVCSType_IsValid(t *testing.T) {
@@ -583,6 +584,346 @@ func TestGitBackend_Commit(t *testing.T) {
 	}
 }
 
+func TestGitBackend_ResetToRevision(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	backend := NewGitBackend()
+
+	snapshot, err := backend.GetSnapshotRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetSnapshotRevision failed: %v", err)
+	}
+	if len(snapshot) != 40 {
+		t.Errorf("expected full commit hash, got %q", snapshot)
+	}
+
+	// Commit a new file, then leave an uncommitted change on top
+	newFile := filepath.Join(tmpDir, "newfile.txt")
+	if err := os.WriteFile(newFile, []byte("new content\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if _, err := backend.Commit(tmpDir, "Add new file"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+
+	if err := backend.ResetToRevision(tmpDir, snapshot); err != nil {
+		t.Fatalf("ResetToRevision failed: %v", err)
+	}
+
+	if _, err := os.Stat(newFile); !os.IsNotExist(err) {
+		t.Error("expected committed file to be removed by reset")
+	}
+	hasChanges, err := backend.HasChanges(tmpDir)
+	if err != nil {
+		t.Fatalf("HasChanges failed: %v", err)
+	}
+	if hasChanges {
+		t.Error("expected clean working tree after reset")
+	}
+	current, _ := backend.GetSnapshotRevision(tmpDir)
+	if current != snapshot {
+		t.Errorf("expected HEAD %s after reset, got %s", snapshot, current)
+	}
+}
+
+func TestGitBackend_DiffStat(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	backend := NewGitBackend()
+
+	base, err := backend.GetSnapshotRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetSnapshotRevision failed: %v", err)
+	}
+
+	// Committed change: replace one README line and add one
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Changed\nsecond line\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if _, err := backend.Commit(tmpDir, "Change README"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	// Untracked file with three lines, plus juggle state that must be ignored
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".juggle", "balls.jsonl"), []byte("{}\n{}\n"), 0644); err != nil {
+		t.Fatalf("failed to create balls file: %v", err)
+	}
+
+	files, lines, err := backend.DiffStat(tmpDir, base)
+	if err != nil {
+		t.Fatalf("DiffStat failed: %v", err)
+	}
+	if files != 2 {
+		t.Errorf("expected 2 changed files, got %d", files)
+	}
+	// README: 1 deleted + 2 added; new.txt: 3 added
+	if lines != 6 {
+		t.Errorf("expected 6 changed lines, got %d", lines)
+	}
+}
+
+func TestGitBackend_ChangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	backend := NewGitBackend()
+
+	base, err := backend.GetSnapshotRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetSnapshotRevision failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".juggle", "balls.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("failed to create balls file: %v", err)
+	}
+
+	files, err := backend.ChangedFiles(tmpDir, base)
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	if len(files) != 2 || files[0] != "README.md" || files[1] != "new.go" {
+		t.Errorf("expected [README.md new.go], got %v", files)
+	}
+}
+
+func TestGitBackend_ListFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	for name, content := range map[string]string{
+		".gitignore":          "build/\n",
+		"new.go":              "package main\n",
+		"build/out.bin":       "binary",
+		".juggle/balls.jsonl": "{}\n",
+	} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	files, err := NewGitBackend().ListFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+	// README.md is committed by setupGitRepo; new.go and .gitignore are untracked
+	want := map[string]bool{"README.md": true, "new.go": true, ".gitignore": true}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, got %v", len(want), files)
+	}
+	for _, file := range files {
+		if !want[file] {
+			t.Errorf("unexpected file %q in %v", file, files)
+		}
+	}
+}
+
+func TestGitBackend_Churn(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	commit := func(files ...string) {
+		t.Helper()
+		for _, name := range files {
+			path := filepath.Join(tmpDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("failed to create dir: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(time.Now().String()), 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+		cmd := exec.Command("git", "add", "-A")
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git add failed: %s: %v", output, err)
+		}
+		cmd = exec.Command("git", "commit", "-m", "change")
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git commit failed: %s: %v", output, err)
+		}
+	}
+	commit("internal/hot.go", ".juggle/balls.jsonl")
+	commit("internal/hot.go", "README.md")
+
+	churn, err := NewGitBackend().Churn(tmpDir, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Churn failed: %v", err)
+	}
+	if churn["internal/hot.go"] != 2 {
+		t.Errorf("expected internal/hot.go to have 2 commits, got %d", churn["internal/hot.go"])
+	}
+	// README.md was also committed by setupGitRepo
+	if churn["README.md"] != 2 {
+		t.Errorf("expected README.md to have 2 commits, got %d", churn["README.md"])
+	}
+	if _, ok := churn[".juggle/balls.jsonl"]; ok {
+		t.Errorf("expected .juggle/ to be excluded, got %v", churn)
+	}
+
+	churn, err = NewGitBackend().Churn(tmpDir, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Churn failed: %v", err)
+	}
+	if len(churn) != 0 {
+		t.Errorf("expected no churn in the future, got %v", churn)
+	}
+}
+
+func TestJJSummaryPath(t *testing.T) {
+	tests := map[string]string{
+		"M internal/cli/agent.go":         "internal/cli/agent.go",
+		"A README.md":                     "README.md",
+		"R internal/{old => new}/file.go": "internal/new/file.go",
+		"R internal/cli/{a.go => b.go}":   "internal/cli/b.go",
+		"R {internal/x => }/file.go":      "file.go",
+		"":                                "",
+		"Working copy changes:":           "",
+	}
+	for line, want := range tests {
+		if got := jjSummaryPath(line); got != want {
+			t.Errorf("jjSummaryPath(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestGitBackend_Diff(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	backend := NewGitBackend()
+
+	base, err := backend.GetSnapshotRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetSnapshotRevision failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte("untracked content\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".juggle", "balls.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("failed to create balls file: %v", err)
+	}
+
+	diff, err := backend.Diff(tmpDir, base)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, "+# Changed") {
+		t.Errorf("expected tracked change in diff, got:\n%s", diff)
+	}
+	if !strings.Contains(diff, "+++ b/new.txt") || !strings.Contains(diff, "+untracked content") {
+		t.Errorf("expected untracked file as addition in diff, got:\n%s", diff)
+	}
+	if strings.Contains(diff, ".juggle") {
+		t.Errorf("expected .juggle to be excluded from diff, got:\n%s", diff)
+	}
+}
+
+func TestGitBackend_DiffRevisions(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	backend := NewGitBackend()
+
+	base, err := backend.GetSnapshotRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetSnapshotRevision failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("failed to modify README: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".juggle", "balls.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("failed to create balls file: %v", err)
+	}
+	if _, err := backend.Commit(tmpDir, "Change README"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	head, err := backend.GetSnapshotRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetSnapshotRevision failed: %v", err)
+	}
+
+	// Uncommitted work after the second revision is not part of the diff
+	if err := os.WriteFile(filepath.Join(tmpDir, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	diff, err := backend.DiffRevisions(tmpDir, base, head)
+	if err != nil {
+		t.Fatalf("DiffRevisions failed: %v", err)
+	}
+	summary := SummarizeDiff(diff)
+	if len(summary.Files) != 1 || summary.Files[0] != "README.md" {
+		t.Errorf("expected only README.md in the diff, got %v:\n%s", summary.Files, diff)
+	}
+	if summary.Lines != 2 {
+		t.Errorf("expected 2 changed lines, got %d:\n%s", summary.Lines, diff)
+	}
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,3 @@
+ package main
+-func old() {}
++func new() {}
++func extra() {}
+diff --git a/added.txt b/added.txt
+new file mode 100644
+--- /dev/null
++++ b/added.txt
+@@ -0,0 +1 @@
++hello
+`
+	summary := SummarizeDiff(diff)
+	if len(summary.Files) != 2 || summary.Files[0] != "main.go" || summary.Files[1] != "added.txt" {
+		t.Errorf("unexpected files: %v", summary.Files)
+	}
+	if summary.Lines != 4 {
+		t.Errorf("expected 4 changed lines, got %d", summary.Lines)
+	}
+	if empty := SummarizeDiff(""); len(empty.Files) != 0 || empty.Lines != 0 {
+		t.Errorf("expected an empty summary, got %+v", empty)
+	}
+}
+
 func TestGitBackend_Commit_NoChanges(t *testing.T) {
 	tmpDir := t.TempDir()
 	setupGitRepo(t, tmpDir)
@@ -623,6 +964,42 @@ func TestGitBackend_Commit_EmptyMessage(t *testing.T) {
 	}
 }
 
+func TestGitBackend_AddAndRemoveWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	workspaceDir := filepath.Join(t.TempDir(), "ws")
+
+	backend := NewGitBackend()
+	if err := backend.AddWorkspace(tmpDir, workspaceDir, "juggle-ball-1"); err != nil {
+		t.Fatalf("AddWorkspace failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workspaceDir, "README.md")); err != nil {
+		t.Errorf("expected workspace to contain the checkout: %v", err)
+	}
+
+	// Commit in the workspace, then remove it: the branch keeps the work
+	if err := os.WriteFile(filepath.Join(workspaceDir, "new.txt"), []byte("work\n"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if result, err := backend.Commit(workspaceDir, "add new.txt"); err != nil || !result.Success {
+		t.Fatalf("Commit in workspace failed: %v %+v", err, result)
+	}
+	if err := backend.RemoveWorkspace(tmpDir, workspaceDir, "juggle-ball-1"); err != nil {
+		t.Fatalf("RemoveWorkspace failed: %v", err)
+	}
+	if _, err := os.Stat(workspaceDir); !os.IsNotExist(err) {
+		t.Errorf("expected workspace directory to be removed, got %v", err)
+	}
+
+	// Re-adding reuses the existing branch
+	if err := backend.AddWorkspace(tmpDir, workspaceDir, "juggle-ball-1"); err != nil {
+		t.Fatalf("AddWorkspace on existing branch failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workspaceDir, "new.txt")); err != nil {
+		t.Errorf("expected workspace to resume the existing branch: %v", err)
+	}
+}
+
 // =============================================================================
 // JJ Backend Tests
 // =============================================================================
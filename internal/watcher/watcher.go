@@ -20,6 +20,8 @@ const (
 	AgentStateChanged   // Daemon state file (agent.state) changed
 	AgentUpdateChanged  // Agent loop update file (agent-update.txt) changed
 	AgentMetricsChanged // Hook metrics file (agent-metrics.json) changed
+	VaultChanged        // A Markdown note in a watched vault folder changed
+	DocsChanged         // A watched project doc (e.g. README.md) changed
 )
 
 // Event represents a file change event
@@ -37,6 +39,8 @@ type Watcher struct {
 	done    chan struct{}
 	mu      sync.Mutex
 	running bool
+	vaults  map[string]bool // Watched vault folders
+	files   map[string]bool // Watched individual files
 }
 
 // New creates a new file watcher
@@ -51,6 +55,8 @@ func New() (*Watcher, error) {
 		Events:  make(chan Event, 100),
 		Errors:  make(chan error, 10),
 		done:    make(chan struct{}),
+		vaults:  make(map[string]bool),
+		files:   make(map[string]bool),
 	}, nil
 }
 
@@ -94,6 +100,35 @@ func (w *Watcher) WatchProject(projectDir string) error {
 	return nil
 }
 
+// WatchVault adds a watcher for the Markdown notes in a vault folder
+func (w *Watcher) WatchVault(dir string) error {
+	dir = filepath.Clean(dir)
+	if err := w.watcher.Add(dir); err != nil {
+		return fmt.Errorf("failed to watch vault directory: %w", err)
+	}
+
+	w.mu.Lock()
+	w.vaults[dir] = true
+	w.mu.Unlock()
+	return nil
+}
+
+// WatchFiles adds watchers for individual files. Their directories are
+// watched instead, so files replaced by editors (or not created yet) are seen.
+func (w *Watcher) WatchFiles(paths []string) error {
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		if err := w.watcher.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+
+		w.mu.Lock()
+		w.files[path] = true
+		w.mu.Unlock()
+	}
+	return nil
+}
+
 // Start begins watching for file changes
 func (w *Watcher) Start() {
 	w.mu.Lock()
@@ -152,6 +187,30 @@ func (w *Watcher) eventLoop() {
 func (w *Watcher) classifyEvent(path string) *Event {
 	base := filepath.Base(path)
 
+	// Check for individually watched files
+	w.mu.Lock()
+	watchedFile := w.files[filepath.Clean(path)]
+	w.mu.Unlock()
+	if watchedFile {
+		return &Event{
+			Type: DocsChanged,
+			Path: path,
+		}
+	}
+
+	// Check for notes in a vault folder
+	if filepath.Ext(base) == ".md" {
+		w.mu.Lock()
+		inVault := w.vaults[filepath.Dir(path)]
+		w.mu.Unlock()
+		if inVault {
+			return &Event{
+				Type: VaultChanged,
+				Path: path,
+			}
+		}
+	}
+
 	// Check for balls.jsonl
 	if base == "balls.jsonl" {
 		return &Event{
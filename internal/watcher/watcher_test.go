@@ -117,6 +117,53 @@ func TestClassifyEvent_Unknown(t *testing.T) {
 	}
 }
 
+func TestClassifyEvent_VaultChanged(t *testing.T) {
+	w, _ := New()
+	defer w.Close()
+
+	vaultDir := t.TempDir()
+	if err := w.WatchVault(vaultDir); err != nil {
+		t.Fatalf("Failed to watch vault: %v", err)
+	}
+
+	event := w.classifyEvent(filepath.Join(vaultDir, "myapp-a1b2.md"))
+	if event == nil {
+		t.Fatal("Expected event, got nil")
+	}
+	if event.Type != VaultChanged {
+		t.Errorf("Expected VaultChanged, got %v", event.Type)
+	}
+
+	// Notes outside watched vault folders are ignored
+	if event := w.classifyEvent("/path/to/notes/other.md"); event != nil {
+		t.Errorf("Expected nil for a note outside the vault, got %v", event.Type)
+	}
+}
+
+func TestClassifyEvent_DocsChanged(t *testing.T) {
+	w, _ := New()
+	defer w.Close()
+
+	projectDir := t.TempDir()
+	readme := filepath.Join(projectDir, "README.md")
+	if err := w.WatchFiles([]string{readme}); err != nil {
+		t.Fatalf("Failed to watch files: %v", err)
+	}
+
+	event := w.classifyEvent(readme)
+	if event == nil {
+		t.Fatal("Expected event, got nil")
+	}
+	if event.Type != DocsChanged {
+		t.Errorf("Expected DocsChanged, got %v", event.Type)
+	}
+
+	// Other files in the same directory are ignored
+	if event := w.classifyEvent(filepath.Join(projectDir, "main.go")); event != nil {
+		t.Errorf("Expected nil for an unwatched file, got %v", event.Type)
+	}
+}
+
 func TestWatcherBallsFileChange(t *testing.T) {
 	// Create temp directory structure
 	tmpDir := t.TempDir()
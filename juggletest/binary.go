@@ -0,0 +1,78 @@
+package juggletest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// EnvBinary names an existing juggle binary to run instead of building one
+const EnvBinary = "JUGGLE_TEST_BINARY"
+
+var (
+	binaryOnce sync.Once
+	binaryPath string
+	binaryErr  error
+)
+
+// Binary returns the path to a juggle binary for running CLI commands. It uses
+// $JUGGLE_TEST_BINARY when set; otherwise juggle is built from the module once
+// per test process into a temporary directory.
+func Binary(t testing.TB) string {
+	t.Helper()
+
+	if path := os.Getenv(EnvBinary); path != "" {
+		return path
+	}
+
+	binaryOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "juggletest-bin-*")
+		if err != nil {
+			binaryErr = err
+			return
+		}
+		name := "juggle"
+		if runtime.GOOS == "windows" {
+			name = "juggle.exe"
+		}
+		binaryPath = filepath.Join(dir, name)
+
+		build := exec.Command("go", "build", "-o", binaryPath, "github.com/ohare93/juggle/cmd/juggle")
+		if output, err := build.CombinedOutput(); err != nil {
+			binaryErr = fmt.Errorf("%w\n%s", err, output)
+		}
+	})
+	if binaryErr != nil {
+		t.Fatalf("Failed to build juggle: %v", binaryErr)
+	}
+	return binaryPath
+}
+
+// Juggle runs the juggle binary in the project against the Env's config home
+// and returns its combined output
+func (env *Env) Juggle(args ...string) (string, error) {
+	env.t.Helper()
+
+	allArgs := append([]string{"--config-home", env.ConfigHome}, args...)
+	cmd := exec.Command(Binary(env.t), allArgs...)
+	cmd.Dir = env.ProjectDir
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// MustJuggle runs the juggle binary like Juggle and fails the test if the
+// command exits non-zero
+func (env *Env) MustJuggle(args ...string) string {
+	env.t.Helper()
+
+	output, err := env.Juggle(args...)
+	if err != nil {
+		env.t.Fatalf("juggle %s failed: %v\nOutput: %s", strings.Join(args, " "), err, output)
+	}
+	return output
+}
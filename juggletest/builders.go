@@ -0,0 +1,177 @@
+package juggletest
+
+import (
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// BallBuilder creates a ball in an Env. Start one with Env.Ball, chain the
+// setters and finish with Create.
+type BallBuilder struct {
+	env  *Env
+	ball *Ball
+	err  error
+}
+
+// Ball starts building a pending, medium priority ball with the given title
+func (env *Env) Ball(title string) *BallBuilder {
+	env.t.Helper()
+
+	ball, err := session.NewBall(env.ProjectDir, title, PriorityMedium)
+	return &BallBuilder{env: env, ball: ball, err: err}
+}
+
+// Priority sets the ball's priority
+func (b *BallBuilder) Priority(priority Priority) *BallBuilder {
+	b.ball.Priority = priority
+	return b
+}
+
+// State sets the ball's state. Complete and blocked balls get a completion
+// note or blocked reason so they look like the agent set them.
+func (b *BallBuilder) State(state BallState) *BallBuilder {
+	switch state {
+	case StateInProgress:
+		b.ball.Start()
+	case StateComplete:
+		b.ball.MarkComplete("done")
+	case StateBlocked:
+		b.ball.ForceSetState(StateBlocked)
+		b.ball.BlockedReason = "blocked"
+	default:
+		b.ball.ForceSetState(state)
+	}
+	return b
+}
+
+// Session tags the ball with one or more sessions
+func (b *BallBuilder) Session(ids ...string) *BallBuilder {
+	for _, id := range ids {
+		b.ball.AddTag(id)
+	}
+	return b
+}
+
+// Tags adds tags to the ball
+func (b *BallBuilder) Tags(tags ...string) *BallBuilder {
+	return b.Session(tags...)
+}
+
+// Context sets the ball's background context
+func (b *BallBuilder) Context(context string) *BallBuilder {
+	b.ball.Context = context
+	return b
+}
+
+// Criteria sets the ball's acceptance criteria
+func (b *BallBuilder) Criteria(criteria ...string) *BallBuilder {
+	b.ball.SetAcceptanceCriteria(criteria)
+	return b
+}
+
+// DependsOn sets the IDs of the balls this ball waits for
+func (b *BallBuilder) DependsOn(ids ...string) *BallBuilder {
+	b.ball.DependsOn = ids
+	return b
+}
+
+// ModelSize sets the ball's preferred model size ("small", "medium" or "large")
+func (b *BallBuilder) ModelSize(size string) *BallBuilder {
+	b.ball.ModelSize = session.ModelSize(size)
+	return b
+}
+
+// Env sets an environment variable for agents working on the ball
+func (b *BallBuilder) Env(key, value string) *BallBuilder {
+	if b.ball.Env == nil {
+		b.ball.Env = make(map[string]string)
+	}
+	b.ball.Env[key] = value
+	return b
+}
+
+// Create saves the ball to the project's store and returns it
+func (b *BallBuilder) Create() *Ball {
+	b.env.t.Helper()
+
+	if b.err != nil {
+		b.env.t.Fatalf("Failed to create ball: %v", b.err)
+	}
+	if err := b.env.Store().AppendBall(b.ball); err != nil {
+		b.env.t.Fatalf("Failed to save ball: %v", err)
+	}
+	return b.ball
+}
+
+// SessionBuilder creates a session in an Env. Start one with Env.Session,
+// chain the setters and finish with Create.
+type SessionBuilder struct {
+	env      *Env
+	id       string
+	desc     string
+	context  string
+	criteria []string
+	vars     map[string]string
+}
+
+// Session starts building a session with the given ID
+func (env *Env) Session(id string) *SessionBuilder {
+	return &SessionBuilder{env: env, id: id, desc: id}
+}
+
+// Description sets the session's description (defaults to the ID)
+func (s *SessionBuilder) Description(description string) *SessionBuilder {
+	s.desc = description
+	return s
+}
+
+// Context sets the session's context, which agents see in their prompt
+func (s *SessionBuilder) Context(context string) *SessionBuilder {
+	s.context = context
+	return s
+}
+
+// Criteria sets acceptance criteria applied to every ball in the session
+func (s *SessionBuilder) Criteria(criteria ...string) *SessionBuilder {
+	s.criteria = criteria
+	return s
+}
+
+// Env sets an environment variable for agents working on the session
+func (s *SessionBuilder) Env(key, value string) *SessionBuilder {
+	if s.vars == nil {
+		s.vars = make(map[string]string)
+	}
+	s.vars[key] = value
+	return s
+}
+
+// Create saves the session and returns it as stored
+func (s *SessionBuilder) Create() *JuggleSession {
+	s.env.t.Helper()
+
+	store := s.env.SessionStore()
+	if _, err := store.CreateSession(s.id, s.desc); err != nil {
+		s.env.t.Fatalf("Failed to create session: %v", err)
+	}
+	if s.context != "" {
+		if err := store.UpdateSessionContext(s.id, s.context); err != nil {
+			s.env.t.Fatalf("Failed to set session context: %v", err)
+		}
+	}
+	if len(s.criteria) > 0 {
+		if err := store.UpdateSessionAcceptanceCriteria(s.id, s.criteria); err != nil {
+			s.env.t.Fatalf("Failed to set session acceptance criteria: %v", err)
+		}
+	}
+	for key, value := range s.vars {
+		if err := store.UpdateSessionEnvVar(s.id, key, value); err != nil {
+			s.env.t.Fatalf("Failed to set session env: %v", err)
+		}
+	}
+
+	sess, err := store.LoadSession(s.id)
+	if err != nil {
+		s.env.t.Fatalf("Failed to load session: %v", err)
+	}
+	return sess
+}
@@ -0,0 +1,181 @@
+// Package juggletest provides test helpers for code that automates juggle:
+// an isolated project and config directory, ball and session builders, mock
+// agent runners, and a runner for the juggle binary.
+//
+// Env changes process-wide state (juggle's global options and the
+// JUGGLER_CONFIG_HOME environment variable), so tests using it must not call
+// t.Parallel.
+package juggletest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// Re-exported juggle types, so tests outside this module can name the values
+// returned by the helpers.
+type (
+	Ball          = session.Ball
+	BallState     = session.BallState
+	Priority      = session.Priority
+	JuggleSession = session.JuggleSession
+	Store         = session.Store
+	SessionStore  = session.SessionStore
+	ProjectConfig = session.ProjectConfig
+)
+
+// Ball states
+const (
+	StatePending    = session.StatePending
+	StateInProgress = session.StateInProgress
+	StateComplete   = session.StateComplete
+	StateBlocked    = session.StateBlocked
+	StateResearched = session.StateResearched
+)
+
+// Ball priorities
+const (
+	PriorityLow    = session.PriorityLow
+	PriorityMedium = session.PriorityMedium
+	PriorityHigh   = session.PriorityHigh
+	PriorityUrgent = session.PriorityUrgent
+)
+
+// JuggleDirName is the name of the juggle directory inside Env projects
+const JuggleDirName = ".juggle"
+
+// Env is an isolated juggle project with its own config home. Everything lives
+// under a t.TempDir and is removed when the test ends.
+type Env struct {
+	t          testing.TB
+	TempDir    string
+	ProjectDir string // The project balls and sessions are stored in
+	ConfigHome string // Used instead of ~/.juggle
+	JuggleDir  string // ProjectDir/.juggle
+}
+
+// New creates an isolated environment and points juggle's global options at
+// it. The previous options and environment are restored by t.Cleanup.
+func New(t testing.TB) *Env {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	env := &Env{
+		t:          t,
+		TempDir:    tempDir,
+		ProjectDir: filepath.Join(tempDir, "project"),
+		ConfigHome: filepath.Join(tempDir, "config"),
+	}
+	env.JuggleDir = filepath.Join(env.ProjectDir, JuggleDirName)
+
+	for _, dir := range []string{env.ProjectDir, env.ConfigHome} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	// Keep test data out of the user's real config
+	t.Setenv(session.EnvConfigHome, env.ConfigHome)
+
+	original := cli.GlobalOpts
+	cli.GlobalOpts = cli.GlobalOptions{
+		ConfigHome: env.ConfigHome,
+		ProjectDir: env.ProjectDir,
+		JuggleDir:  JuggleDirName,
+	}
+	t.Cleanup(func() { cli.GlobalOpts = original })
+
+	return env
+}
+
+// Store returns the ball store for the project
+func (env *Env) Store() *Store {
+	env.t.Helper()
+
+	store, err := cli.NewStoreForCommand(env.ProjectDir)
+	if err != nil {
+		env.t.Fatalf("Failed to create store: %v", err)
+	}
+	return store
+}
+
+// SessionStore returns the session store for the project
+func (env *Env) SessionStore() *SessionStore {
+	env.t.Helper()
+
+	sessionStore, err := session.NewSessionStoreWithConfig(env.ProjectDir, session.StoreConfig{
+		JuggleDirName: JuggleDirName,
+	})
+	if err != nil {
+		env.t.Fatalf("Failed to create session store: %v", err)
+	}
+	return sessionStore
+}
+
+// GetBall loads a ball by ID, failing the test if it doesn't exist
+func (env *Env) GetBall(id string) *Ball {
+	env.t.Helper()
+
+	ball, err := env.Store().GetBallByID(id)
+	if err != nil {
+		env.t.Fatalf("Expected ball %s to exist: %v", id, err)
+	}
+	return ball
+}
+
+// AssertState fails the test unless the ball is in the expected state
+func (env *Env) AssertState(id string, expected BallState) {
+	env.t.Helper()
+
+	if ball := env.GetBall(id); ball.State != expected {
+		env.t.Errorf("Expected ball %s to be %s, got %s", id, expected, ball.State)
+	}
+}
+
+// UpdateProjectConfig loads the project config, applies fn and saves it
+func (env *Env) UpdateProjectConfig(fn func(*ProjectConfig)) {
+	env.t.Helper()
+
+	config, err := session.LoadProjectConfig(env.ProjectDir)
+	if err != nil {
+		env.t.Fatalf("Failed to load project config: %v", err)
+	}
+	fn(config)
+	if err := session.SaveProjectConfig(env.ProjectDir, config); err != nil {
+		env.t.Fatalf("Failed to save project config: %v", err)
+	}
+}
+
+// InitGit makes the project a git repository with one commit. The juggle
+// directory is ignored so ball updates don't dirty the working copy.
+func (env *Env) InitGit() {
+	env.t.Helper()
+
+	env.Git("init")
+	env.Git("config", "user.email", "test@test.com")
+	env.Git("config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, ".gitignore"), []byte(JuggleDirName+"/\n"), 0644); err != nil {
+		env.t.Fatalf("Failed to create .gitignore: %v", err)
+	}
+	env.Git("add", "-A")
+	env.Git("commit", "-m", "initial commit")
+}
+
+// Git runs git in the project and returns its trimmed output
+func (env *Env) Git(args ...string) string {
+	env.t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = env.ProjectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		env.t.Fatalf("git %s failed: %v\nOutput: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
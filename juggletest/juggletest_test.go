@@ -0,0 +1,101 @@
+package juggletest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/juggletest"
+)
+
+func TestBuilders(t *testing.T) {
+	env := juggletest.New(t)
+
+	sess := env.Session("api").
+		Description("API work").
+		Context("Keep handlers small").
+		Criteria("Tests pass").
+		Env("API_URL", "http://localhost").
+		Create()
+	if sess.Description != "API work" || sess.Context != "Keep handlers small" || sess.Env["API_URL"] != "http://localhost" {
+		t.Errorf("Expected session fields to be saved, got %+v", sess)
+	}
+
+	first := env.Ball("Add endpoint").Session("api").Priority(juggletest.PriorityHigh).Create()
+	second := env.Ball("Document endpoint").
+		Session("api").
+		State(juggletest.StateInProgress).
+		Criteria("README updated").
+		DependsOn(first.ID).
+		Create()
+
+	got := env.GetBall(second.ID)
+	if got.State != juggletest.StateInProgress || len(got.DependsOn) != 1 || got.DependsOn[0] != first.ID {
+		t.Errorf("Expected in-progress ball depending on %s, got %+v", first.ID, got)
+	}
+	env.AssertState(first.ID, juggletest.StatePending)
+	if env.GetBall(first.ID).Priority != juggletest.PriorityHigh {
+		t.Error("Expected priority to be saved")
+	}
+}
+
+func TestRunAgentWithCompletingRunner(t *testing.T) {
+	juggletest.SkipIfNoAgentCLI(t)
+	env := juggletest.New(t)
+	env.Session("s").Create()
+	ball := env.Ball("Write config").Session("s").State(juggletest.StateInProgress).Create()
+
+	juggletest.UseRunner(t, env.CompleteBall(ball.ID, map[string]string{"config/app.yaml": "port: 80\n"}))
+
+	result, err := env.RunAgent(juggletest.AgentLoopConfig{SessionID: "s"})
+	if err != nil {
+		t.Fatalf("RunAgent failed: %v", err)
+	}
+	if !result.Complete {
+		t.Errorf("Expected run to complete, got %+v", result)
+	}
+	env.AssertState(ball.ID, juggletest.StateComplete)
+	if _, err := os.Stat(filepath.Join(env.ProjectDir, "config", "app.yaml")); err != nil {
+		t.Errorf("Expected runner to write files: %v", err)
+	}
+}
+
+func TestUseMockRunnerRecordsCalls(t *testing.T) {
+	juggletest.SkipIfNoAgentCLI(t)
+	env := juggletest.New(t)
+	env.Session("s").Create()
+	env.Ball("Parse the input").Session("s").State(juggletest.StateInProgress).Create()
+
+	runner := juggletest.UseMockRunner(t, &juggletest.RunResult{Output: "still working"})
+
+	if _, err := env.RunAgent(juggletest.AgentLoopConfig{SessionID: "s"}); err != nil {
+		t.Fatalf("RunAgent failed: %v", err)
+	}
+	if len(runner.Calls) != 1 {
+		t.Fatalf("Expected one agent call, got %d", len(runner.Calls))
+	}
+	if !strings.Contains(runner.Calls[0].Prompt, "Parse the input") {
+		t.Error("Expected the prompt to include the ball")
+	}
+}
+
+func TestJuggleBinary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the juggle binary")
+	}
+	env := juggletest.New(t)
+	env.InitGit()
+	ball := env.Ball("Visible from the CLI").Create()
+
+	output := env.MustJuggle("show", ball.ID)
+	if !strings.Contains(output, "Visible from the CLI") {
+		t.Errorf("Expected show output to include the ball title, got:\n%s", output)
+	}
+	if _, err := env.Juggle("show", "no-such-ball"); err == nil {
+		t.Error("Expected an error for an unknown ball")
+	}
+	if env.Git("rev-list", "--count", "HEAD") != "1" {
+		t.Error("Expected InitGit to make one commit")
+	}
+}
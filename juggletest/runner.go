@@ -0,0 +1,124 @@
+package juggletest
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// Re-exported agent types for wiring runners into the agent loop
+type (
+	Runner          = agent.Runner
+	RunOptions      = agent.RunOptions
+	RunResult       = agent.RunResult
+	MockRunner      = agent.MockRunner
+	AgentLoopConfig = cli.AgentLoopConfig
+	AgentResult     = cli.AgentResult
+)
+
+// RunnerFunc adapts a function to the Runner interface
+type RunnerFunc func(opts RunOptions) (*RunResult, error)
+
+// Run calls f(opts)
+func (f RunnerFunc) Run(opts RunOptions) (*RunResult, error) {
+	return f(opts)
+}
+
+// UseRunner makes the agent loop use r instead of a real agent CLI until the
+// test ends
+func UseRunner(t testing.TB, r Runner) {
+	t.Helper()
+
+	agent.SetRunner(r)
+	t.Cleanup(agent.ResetRunner)
+}
+
+// UseMockRunner installs a MockRunner that returns responses in order and
+// returns it so the test can inspect its calls
+func UseMockRunner(t testing.TB, responses ...*RunResult) *MockRunner {
+	t.Helper()
+
+	runner := agent.NewMockRunner(responses...)
+	UseRunner(t, runner)
+	return runner
+}
+
+// CompleteBall returns a Runner that acts like an agent finishing the ball:
+// it writes files (relative to the project), marks the ball complete, logs
+// progress to the ball's sessions and reports COMPLETE.
+func (env *Env) CompleteBall(ballID string, files map[string]string) Runner {
+	return RunnerFunc(func(opts RunOptions) (*RunResult, error) {
+		for name, content := range files {
+			path := filepath.Join(env.ProjectDir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return nil, err
+			}
+		}
+
+		store, err := cli.NewStoreForCommand(env.ProjectDir)
+		if err != nil {
+			return nil, err
+		}
+		ball, err := store.GetBallByID(ballID)
+		if err != nil {
+			return nil, err
+		}
+		ball.MarkComplete("done")
+		if err := store.UpdateBall(ball); err != nil {
+			return nil, err
+		}
+
+		// The agent loop expects each iteration to add progress
+		sessionStore, err := session.NewSessionStore(env.ProjectDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range ball.Tags {
+			if _, err := sessionStore.LoadSession(tag); err != nil {
+				continue
+			}
+			if err := sessionStore.AppendProgress(tag, "Completed "+ball.ID+"\n"); err != nil {
+				return nil, err
+			}
+		}
+
+		return &RunResult{
+			Output:   "<promise>COMPLETE</promise>",
+			Complete: true,
+		}, nil
+	})
+}
+
+// RunAgent runs the agent loop for the project with the given config. The
+// project directory defaults to the Env's and iterations default to 1.
+func (env *Env) RunAgent(config AgentLoopConfig) (*AgentResult, error) {
+	env.t.Helper()
+
+	if config.ProjectDir == "" {
+		config.ProjectDir = env.ProjectDir
+	}
+	if config.MaxIterations == 0 {
+		config.MaxIterations = 1
+	}
+	return cli.RunAgentLoop(context.Background(), config)
+}
+
+// SkipIfNoAgentCLI skips the test when the claude CLI isn't installed. The
+// agent loop checks the provider is available even when a mock runner is
+// installed, so RunAgent needs it on PATH.
+func SkipIfNoAgentCLI(t testing.TB) {
+	t.Helper()
+
+	if _, err := exec.LookPath("claude"); err != nil {
+		t.Skip("claude CLI not available, skipping test")
+	}
+}
@@ -0,0 +1,616 @@
+// The gRPC API of a process running agent loops in daemon mode, either
+// `juggle agent run --daemon` or `juggle daemon`. It listens on localhost; the
+// address and token are in the agent.pid file of each session it runs.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: daemon.proto
+
+package daemonapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListLoopsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoopsRequest) Reset() {
+	*x = ListLoopsRequest{}
+	mi := &file_daemon_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoopsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoopsRequest) ProtoMessage() {}
+
+func (x *ListLoopsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoopsRequest.ProtoReflect.Descriptor instead.
+func (*ListLoopsRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{0}
+}
+
+type ListLoopsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loops         []*Loop                `protobuf:"bytes,1,rep,name=loops,proto3" json:"loops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoopsResponse) Reset() {
+	*x = ListLoopsResponse{}
+	mi := &file_daemon_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoopsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoopsResponse) ProtoMessage() {}
+
+func (x *ListLoopsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoopsResponse.ProtoReflect.Descriptor instead.
+func (*ListLoopsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListLoopsResponse) GetLoops() []*Loop {
+	if x != nil {
+		return x.Loops
+	}
+	return nil
+}
+
+// Loop is an agent loop running in the process
+type Loop struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ProjectDir string                 `protobuf:"bytes,1,opt,name=project_dir,json=projectDir,proto3" json:"project_dir,omitempty"`
+	// The session's storage ID ("_all" for the "all" meta-session)
+	SessionId     string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	State         *State `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Loop) Reset() {
+	*x = Loop{}
+	mi := &file_daemon_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Loop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Loop) ProtoMessage() {}
+
+func (x *Loop) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Loop.ProtoReflect.Descriptor instead.
+func (*Loop) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Loop) GetProjectDir() string {
+	if x != nil {
+		return x.ProjectDir
+	}
+	return ""
+}
+
+func (x *Loop) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Loop) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectDir    string                 `protobuf:"bytes,1,opt,name=project_dir,json=projectDir,proto3" json:"project_dir,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_daemon_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStateRequest) GetProjectDir() string {
+	if x != nil {
+		return x.ProjectDir
+	}
+	return ""
+}
+
+func (x *GetStateRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// State mirrors the session's agent.state file
+type State struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Running          bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	Paused           bool                   `protobuf:"varint,2,opt,name=paused,proto3" json:"paused,omitempty"`
+	CurrentBallId    string                 `protobuf:"bytes,3,opt,name=current_ball_id,json=currentBallId,proto3" json:"current_ball_id,omitempty"`
+	CurrentBallTitle string                 `protobuf:"bytes,4,opt,name=current_ball_title,json=currentBallTitle,proto3" json:"current_ball_title,omitempty"`
+	Iteration        int32                  `protobuf:"varint,5,opt,name=iteration,proto3" json:"iteration,omitempty"`
+	MaxIterations    int32                  `protobuf:"varint,6,opt,name=max_iterations,json=maxIterations,proto3" json:"max_iterations,omitempty"`
+	FilesChanged     int32                  `protobuf:"varint,7,opt,name=files_changed,json=filesChanged,proto3" json:"files_changed,omitempty"`
+	AcsComplete      int32                  `protobuf:"varint,8,opt,name=acs_complete,json=acsComplete,proto3" json:"acs_complete,omitempty"`
+	AcsTotal         int32                  `protobuf:"varint,9,opt,name=acs_total,json=acsTotal,proto3" json:"acs_total,omitempty"`
+	Model            string                 `protobuf:"bytes,10,opt,name=model,proto3" json:"model,omitempty"`
+	Provider         string                 `protobuf:"bytes,11,opt,name=provider,proto3" json:"provider,omitempty"`
+	LastUpdated      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	StartedAt        *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// e.g. "No workable balls", "Complete", "Blocked"
+	Status string `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`
+	// Unset when unknown
+	EstimatedEnd *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=estimated_end,json=estimatedEnd,proto3" json:"estimated_end,omitempty"`
+	// Schema version of the daemon's state; unset before versioning
+	Version       int32 `protobuf:"varint,16,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_daemon_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *State) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *State) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *State) GetCurrentBallId() string {
+	if x != nil {
+		return x.CurrentBallId
+	}
+	return ""
+}
+
+func (x *State) GetCurrentBallTitle() string {
+	if x != nil {
+		return x.CurrentBallTitle
+	}
+	return ""
+}
+
+func (x *State) GetIteration() int32 {
+	if x != nil {
+		return x.Iteration
+	}
+	return 0
+}
+
+func (x *State) GetMaxIterations() int32 {
+	if x != nil {
+		return x.MaxIterations
+	}
+	return 0
+}
+
+func (x *State) GetFilesChanged() int32 {
+	if x != nil {
+		return x.FilesChanged
+	}
+	return 0
+}
+
+func (x *State) GetAcsComplete() int32 {
+	if x != nil {
+		return x.AcsComplete
+	}
+	return 0
+}
+
+func (x *State) GetAcsTotal() int32 {
+	if x != nil {
+		return x.AcsTotal
+	}
+	return 0
+}
+
+func (x *State) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *State) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *State) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+func (x *State) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *State) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *State) GetEstimatedEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedEnd
+	}
+	return nil
+}
+
+func (x *State) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SendControlRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ProjectDir string                 `protobuf:"bytes,1,opt,name=project_dir,json=projectDir,proto3" json:"project_dir,omitempty"`
+	SessionId  string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// pause, resume, cancel, skip_ball, change_model, change_provider or takeover
+	Command string `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	// e.g. the model name for change_model or the provider for change_provider
+	Args          string `protobuf:"bytes,4,opt,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendControlRequest) Reset() {
+	*x = SendControlRequest{}
+	mi := &file_daemon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendControlRequest) ProtoMessage() {}
+
+func (x *SendControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendControlRequest.ProtoReflect.Descriptor instead.
+func (*SendControlRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendControlRequest) GetProjectDir() string {
+	if x != nil {
+		return x.ProjectDir
+	}
+	return ""
+}
+
+func (x *SendControlRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SendControlRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *SendControlRequest) GetArgs() string {
+	if x != nil {
+		return x.Args
+	}
+	return ""
+}
+
+type SendControlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendControlResponse) Reset() {
+	*x = SendControlResponse{}
+	mi := &file_daemon_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendControlResponse) ProtoMessage() {}
+
+func (x *SendControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendControlResponse.ProtoReflect.Descriptor instead.
+func (*SendControlResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_rawDescGZIP(), []int{6}
+}
+
+var File_daemon_proto protoreflect.FileDescriptor
+
+const file_daemon_proto_rawDesc = "" +
+	"\n" +
+	"\fdaemon.proto\x12\x10juggle.daemon.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x12\n" +
+	"\x10ListLoopsRequest\"A\n" +
+	"\x11ListLoopsResponse\x12,\n" +
+	"\x05loops\x18\x01 \x03(\v2\x16.juggle.daemon.v1.LoopR\x05loops\"u\n" +
+	"\x04Loop\x12\x1f\n" +
+	"\vproject_dir\x18\x01 \x01(\tR\n" +
+	"projectDir\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12-\n" +
+	"\x05state\x18\x03 \x01(\v2\x17.juggle.daemon.v1.StateR\x05state\"Q\n" +
+	"\x0fGetStateRequest\x12\x1f\n" +
+	"\vproject_dir\x18\x01 \x01(\tR\n" +
+	"projectDir\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\"\xd8\x04\n" +
+	"\x05State\x12\x18\n" +
+	"\arunning\x18\x01 \x01(\bR\arunning\x12\x16\n" +
+	"\x06paused\x18\x02 \x01(\bR\x06paused\x12&\n" +
+	"\x0fcurrent_ball_id\x18\x03 \x01(\tR\rcurrentBallId\x12,\n" +
+	"\x12current_ball_title\x18\x04 \x01(\tR\x10currentBallTitle\x12\x1c\n" +
+	"\titeration\x18\x05 \x01(\x05R\titeration\x12%\n" +
+	"\x0emax_iterations\x18\x06 \x01(\x05R\rmaxIterations\x12#\n" +
+	"\rfiles_changed\x18\a \x01(\x05R\ffilesChanged\x12!\n" +
+	"\facs_complete\x18\b \x01(\x05R\vacsComplete\x12\x1b\n" +
+	"\tacs_total\x18\t \x01(\x05R\bacsTotal\x12\x14\n" +
+	"\x05model\x18\n" +
+	" \x01(\tR\x05model\x12\x1a\n" +
+	"\bprovider\x18\v \x01(\tR\bprovider\x12=\n" +
+	"\flast_updated\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x129\n" +
+	"\n" +
+	"started_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12\x16\n" +
+	"\x06status\x18\x0e \x01(\tR\x06status\x12?\n" +
+	"\restimated_end\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\festimatedEnd\x12\x18\n" +
+	"\aversion\x18\x10 \x01(\x05R\aversion\"\x82\x01\n" +
+	"\x12SendControlRequest\x12\x1f\n" +
+	"\vproject_dir\x18\x01 \x01(\tR\n" +
+	"projectDir\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x04 \x01(\tR\x04args\"\x15\n" +
+	"\x13SendControlResponse2\xce\x02\n" +
+	"\x06Daemon\x12T\n" +
+	"\tListLoops\x12\".juggle.daemon.v1.ListLoopsRequest\x1a#.juggle.daemon.v1.ListLoopsResponse\x12F\n" +
+	"\bGetState\x12!.juggle.daemon.v1.GetStateRequest\x1a\x17.juggle.daemon.v1.State\x12J\n" +
+	"\n" +
+	"WatchState\x12!.juggle.daemon.v1.GetStateRequest\x1a\x17.juggle.daemon.v1.State0\x01\x12Z\n" +
+	"\vSendControl\x12$.juggle.daemon.v1.SendControlRequest\x1a%.juggle.daemon.v1.SendControlResponseB)Z'github.com/ohare93/juggle/pkg/daemonapib\x06proto3"
+
+var (
+	file_daemon_proto_rawDescOnce sync.Once
+	file_daemon_proto_rawDescData []byte
+)
+
+func file_daemon_proto_rawDescGZIP() []byte {
+	file_daemon_proto_rawDescOnce.Do(func() {
+		file_daemon_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_daemon_proto_rawDesc), len(file_daemon_proto_rawDesc)))
+	})
+	return file_daemon_proto_rawDescData
+}
+
+var file_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_daemon_proto_goTypes = []any{
+	(*ListLoopsRequest)(nil),      // 0: juggle.daemon.v1.ListLoopsRequest
+	(*ListLoopsResponse)(nil),     // 1: juggle.daemon.v1.ListLoopsResponse
+	(*Loop)(nil),                  // 2: juggle.daemon.v1.Loop
+	(*GetStateRequest)(nil),       // 3: juggle.daemon.v1.GetStateRequest
+	(*State)(nil),                 // 4: juggle.daemon.v1.State
+	(*SendControlRequest)(nil),    // 5: juggle.daemon.v1.SendControlRequest
+	(*SendControlResponse)(nil),   // 6: juggle.daemon.v1.SendControlResponse
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_daemon_proto_depIdxs = []int32{
+	2, // 0: juggle.daemon.v1.ListLoopsResponse.loops:type_name -> juggle.daemon.v1.Loop
+	4, // 1: juggle.daemon.v1.Loop.state:type_name -> juggle.daemon.v1.State
+	7, // 2: juggle.daemon.v1.State.last_updated:type_name -> google.protobuf.Timestamp
+	7, // 3: juggle.daemon.v1.State.started_at:type_name -> google.protobuf.Timestamp
+	7, // 4: juggle.daemon.v1.State.estimated_end:type_name -> google.protobuf.Timestamp
+	0, // 5: juggle.daemon.v1.Daemon.ListLoops:input_type -> juggle.daemon.v1.ListLoopsRequest
+	3, // 6: juggle.daemon.v1.Daemon.GetState:input_type -> juggle.daemon.v1.GetStateRequest
+	3, // 7: juggle.daemon.v1.Daemon.WatchState:input_type -> juggle.daemon.v1.GetStateRequest
+	5, // 8: juggle.daemon.v1.Daemon.SendControl:input_type -> juggle.daemon.v1.SendControlRequest
+	1, // 9: juggle.daemon.v1.Daemon.ListLoops:output_type -> juggle.daemon.v1.ListLoopsResponse
+	4, // 10: juggle.daemon.v1.Daemon.GetState:output_type -> juggle.daemon.v1.State
+	4, // 11: juggle.daemon.v1.Daemon.WatchState:output_type -> juggle.daemon.v1.State
+	6, // 12: juggle.daemon.v1.Daemon.SendControl:output_type -> juggle.daemon.v1.SendControlResponse
+	9, // [9:13] is the sub-list for method output_type
+	5, // [5:9] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_daemon_proto_init() }
+func file_daemon_proto_init() {
+	if File_daemon_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_daemon_proto_rawDesc), len(file_daemon_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_daemon_proto_goTypes,
+		DependencyIndexes: file_daemon_proto_depIdxs,
+		MessageInfos:      file_daemon_proto_msgTypes,
+	}.Build()
+	File_daemon_proto = out.File
+	file_daemon_proto_goTypes = nil
+	file_daemon_proto_depIdxs = nil
+}
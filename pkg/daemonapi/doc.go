@@ -0,0 +1,13 @@
+// Package daemonapi is the generated gRPC client and server for the API of a
+// process running juggle agent loops in daemon mode (`juggle agent run
+// --daemon` or `juggle daemon`), defined in daemon.proto.
+//
+// The process listens on localhost. Each session it runs has the address and
+// a token in its .juggle/sessions/<id>/agent.pid file, as api_addr and
+// api_token; send the token as the juggle-token metadata of every call.
+//
+// Like package juggle, this package follows the module's semantic version:
+// fields and RPCs are only added in minor versions.
+package daemonapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative daemon.proto
@@ -0,0 +1,105 @@
+package juggle
+
+import (
+	"context"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/cli"
+)
+
+// AgentLoopConfig configures RunAgentLoop. The fields match the flags of
+// `juggle agent run`.
+type AgentLoopConfig = cli.AgentLoopConfig
+
+// AgentResult summarizes an agent loop run
+type AgentResult = cli.AgentResult
+
+// RunAgentLoop runs agents on a session's balls until they are done, blocked
+// or MaxIterations is reached, the same way `juggle agent run` does.
+// Cancelling ctx stops the current agent and ends the loop.
+//
+// The runner, provider and project directory are process-wide, so only one
+// loop may run at a time in a process.
+func RunAgentLoop(ctx context.Context, config AgentLoopConfig) (*AgentResult, error) {
+	// Parts of the loop resolve the project like the CLI does, from its flags
+	if config.ProjectDir != "" {
+		original := cli.GlobalOpts
+		cli.GlobalOpts.ProjectDir = config.ProjectDir
+		defer func() { cli.GlobalOpts = original }()
+	}
+	return cli.RunAgentLoop(ctx, config)
+}
+
+// Provider is an agent CLI backend such as Claude Code or OpenCode
+type Provider = provider.Provider
+
+// ProviderType names a provider
+type ProviderType = provider.Type
+
+// Built-in providers
+const (
+	ProviderClaude   = provider.TypeClaude
+	ProviderOpenCode = provider.TypeOpenCode
+	ProviderGoose    = provider.TypeGoose
+	ProviderAmp      = provider.TypeAmp
+	ProviderAPI      = provider.TypeAPI
+)
+
+// CustomProviderConfig describes an agent CLI juggle doesn't know about. It has
+// the same fields as a custom_providers entry in the global config.
+type CustomProviderConfig = provider.CustomConfig
+
+// RegisterCustomProvider makes a custom provider available by name
+func RegisterCustomProvider(name string, config CustomProviderConfig) error {
+	return provider.RegisterCustom(name, config)
+}
+
+// GetProvider returns the provider for a type. Unknown types get Claude.
+func GetProvider(providerType ProviderType) Provider {
+	return provider.Get(providerType)
+}
+
+// ProviderAvailable reports whether the provider's CLI can be found
+func ProviderAvailable(providerType ProviderType) bool {
+	return provider.IsAvailable(providerType)
+}
+
+// RunOptions configures a single agent invocation
+type RunOptions = agent.RunOptions
+
+// RunResult is the outcome of a single agent invocation
+type RunResult = agent.RunResult
+
+// RunMode is headless or interactive
+type RunMode = agent.RunMode
+
+// Run modes
+const (
+	ModeHeadless    = provider.ModeHeadless
+	ModeInteractive = provider.ModeInteractive
+)
+
+// PermissionMode is the agent's permission level
+type PermissionMode = agent.PermissionMode
+
+// Permission modes
+const (
+	PermissionAcceptEdits = provider.PermissionAcceptEdits
+	PermissionPlan        = provider.PermissionPlan
+	PermissionBypass      = provider.PermissionBypass
+)
+
+// Runner executes one agent invocation. Replace it with SetRunner to route
+// agent runs through your own code, e.g. a remote executor or a test double.
+type Runner = agent.Runner
+
+// SetRunner replaces the runner RunAgentLoop uses
+func SetRunner(r Runner) {
+	agent.SetRunner(r)
+}
+
+// ResetRunner restores the default runner, which runs the provider's CLI
+func ResetRunner() {
+	agent.ResetRunner()
+}
@@ -0,0 +1,48 @@
+package juggle
+
+import (
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// Ball is a unit of work tracked by juggle
+type Ball = session.Ball
+
+// BallState is the lifecycle state of a ball
+type BallState = session.BallState
+
+// Ball states
+const (
+	StatePending    = session.StatePending
+	StateInProgress = session.StateInProgress
+	StateComplete   = session.StateComplete
+	StateBlocked    = session.StateBlocked
+	StateResearched = session.StateResearched
+)
+
+// Priority is a ball's priority
+type Priority = session.Priority
+
+// Ball priorities
+const (
+	PriorityLow    = session.PriorityLow
+	PriorityMedium = session.PriorityMedium
+	PriorityHigh   = session.PriorityHigh
+	PriorityUrgent = session.PriorityUrgent
+)
+
+// ModelSize is a ball's preferred model size
+type ModelSize = session.ModelSize
+
+// Model sizes (blank lets the session or agent loop decide)
+const (
+	ModelSizeBlank  = session.ModelSizeBlank
+	ModelSizeSmall  = session.ModelSizeSmall
+	ModelSizeMedium = session.ModelSizeMedium
+	ModelSizeLarge  = session.ModelSizeLarge
+)
+
+// NewBall creates a pending ball with an ID unique within the project. The
+// ball isn't saved until it is passed to Store.AppendBall.
+func NewBall(projectDir, title string, priority Priority) (*Ball, error) {
+	return session.NewBall(projectDir, title, priority)
+}
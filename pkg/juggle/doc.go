@@ -0,0 +1,36 @@
+// Package juggle is the stable Go API for embedding juggle: reading and
+// writing balls and sessions, and running the agent loop with the built-in or
+// a custom agent provider.
+//
+// # Compatibility
+//
+// This package follows the module's semantic version. Exported identifiers,
+// function signatures and the fields of exported types are only removed or
+// changed in a new major version; minor versions may add fields, functions
+// and constants. The on-disk formats (balls.jsonl, session.json and
+// config.json) are covered by the same promise. Everything under internal/
+// may change in any release, so import this package instead.
+//
+// # Example
+//
+//	store, err := juggle.OpenStore(projectDir)
+//	if err != nil {
+//		return err
+//	}
+//	ball, err := juggle.NewBall(projectDir, "Add rate limiting", juggle.PriorityHigh)
+//	if err != nil {
+//		return err
+//	}
+//	ball.AddTag("api")
+//	if err := store.AppendBall(ball); err != nil {
+//		return err
+//	}
+//
+//	result, err := juggle.RunAgentLoop(ctx, juggle.AgentLoopConfig{
+//		SessionID:     "api",
+//		ProjectDir:    projectDir,
+//		MaxIterations: 10,
+//	})
+//
+// RunAgentLoop writes progress to stdout like `juggle agent run`.
+package juggle
@@ -0,0 +1,143 @@
+package juggle_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ohare93/juggle/pkg/juggle"
+)
+
+// The signatures below are part of the compatibility promise. If one of these
+// assignments stops compiling, the change needs a new major version.
+var (
+	_ func(string, string, juggle.Priority) (*juggle.Ball, error)                = juggle.NewBall
+	_ func(string) (*juggle.Store, error)                                        = juggle.OpenStore
+	_ func(string, juggle.BallStore) *juggle.Store                               = juggle.OpenStoreWithBackend
+	_ func() *juggle.MemoryBallStore                                             = juggle.NewMemoryBallStore
+	_ func(string) (*juggle.SessionStore, error)                                 = juggle.OpenSessionStore
+	_ func(string) (*juggle.ProjectConfig, error)                                = juggle.LoadProjectConfig
+	_ func(string, *juggle.ProjectConfig) error                                  = juggle.SaveProjectConfig
+	_ func(context.Context, juggle.AgentLoopConfig) (*juggle.AgentResult, error) = juggle.RunAgentLoop
+	_ func(string, juggle.CustomProviderConfig) error                            = juggle.RegisterCustomProvider
+	_ func(juggle.ProviderType) juggle.Provider                                  = juggle.GetProvider
+	_ func(juggle.ProviderType) bool                                             = juggle.ProviderAvailable
+	_ func(juggle.Runner)                                                        = juggle.SetRunner
+	_ func()                                                                     = juggle.ResetRunner
+	_ func(*juggle.Store, *juggle.Ball) error                                    = (*juggle.Store).AppendBall
+	_ func(*juggle.Store, *juggle.Ball) error                                    = (*juggle.Store).UpdateBall
+	_ func(*juggle.Store) ([]*juggle.Ball, error)                                = (*juggle.Store).LoadBalls
+	_ func(*juggle.Store, string) (*juggle.Ball, error)                          = (*juggle.Store).GetBallByID
+	_ func(*juggle.SessionStore, string, string) (*juggle.Session, error)        = (*juggle.SessionStore).CreateSession
+	_ func(*juggle.SessionStore, string) (*juggle.Session, error)                = (*juggle.SessionStore).LoadSession
+	_ func(*juggle.SessionStore, string, string) error                           = (*juggle.SessionStore).AppendProgress
+	_ func(juggle.Runner, juggle.RunOptions) (*juggle.RunResult, error)          = juggle.Runner.Run
+)
+
+// completeRunner finishes the ball it is given, like an agent would
+type completeRunner struct {
+	projectDir string
+	ballID     string
+	calls      int
+}
+
+func (r *completeRunner) Run(opts juggle.RunOptions) (*juggle.RunResult, error) {
+	r.calls++
+	store, err := juggle.OpenStore(r.projectDir)
+	if err != nil {
+		return nil, err
+	}
+	ball, err := store.GetBallByID(r.ballID)
+	if err != nil {
+		return nil, err
+	}
+	ball.MarkComplete("done")
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, err
+	}
+	sessions, err := juggle.OpenSessionStore(r.projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := sessions.AppendProgress("api", "Completed "+r.ballID+"\n"); err != nil {
+		return nil, err
+	}
+	return &juggle.RunResult{Output: "<promise>COMPLETE</promise>", Complete: true}, nil
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	projectDir := t.TempDir()
+	store, err := juggle.OpenStore(projectDir)
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+
+	ball, err := juggle.NewBall(projectDir, "Add rate limiting", juggle.PriorityHigh)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	ball.AddTag("api")
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+
+	got, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("GetBallByID failed: %v", err)
+	}
+	if got.Title != "Add rate limiting" || got.State != juggle.StatePending || got.Priority != juggle.PriorityHigh {
+		t.Errorf("Expected the saved ball back, got %+v", got)
+	}
+}
+
+func TestRunAgentLoop(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Setenv("JUGGLER_CONFIG_HOME", t.TempDir())
+
+	sessions, err := juggle.OpenSessionStore(projectDir)
+	if err != nil {
+		t.Fatalf("OpenSessionStore failed: %v", err)
+	}
+	if _, err := sessions.CreateSession("api", "API work"); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	store, err := juggle.OpenStore(projectDir)
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	ball, err := juggle.NewBall(projectDir, "Add rate limiting", juggle.PriorityMedium)
+	if err != nil {
+		t.Fatalf("NewBall failed: %v", err)
+	}
+	ball.AddTag("api")
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("AppendBall failed: %v", err)
+	}
+
+	// An embedding program's own provider, available on any unix PATH
+	if err := juggle.RegisterCustomProvider("embedded", juggle.CustomProviderConfig{Binary: "true"}); err != nil {
+		t.Fatalf("RegisterCustomProvider failed: %v", err)
+	}
+	runner := &completeRunner{projectDir: projectDir, ballID: ball.ID}
+	juggle.SetRunner(runner)
+	defer juggle.ResetRunner()
+
+	result, err := juggle.RunAgentLoop(context.Background(), juggle.AgentLoopConfig{
+		SessionID:     "api",
+		ProjectDir:    projectDir,
+		MaxIterations: 1,
+		Provider:      "embedded",
+	})
+	if err != nil {
+		t.Fatalf("RunAgentLoop failed: %v", err)
+	}
+	if !result.Complete || runner.calls != 1 {
+		t.Errorf("Expected one run completing the session, got %+v after %d calls", result, runner.calls)
+	}
+	// Loading providers from global config keeps the embedded one
+	if got := juggle.GetProvider("embedded").Type(); got != "embedded" {
+		t.Errorf("Expected the embedded provider to stay registered, got %s", got)
+	}
+	if got, _ := store.GetBallByID(ball.ID); got == nil || got.State != juggle.StateComplete {
+		t.Errorf("Expected the ball to be complete, got %+v", got)
+	}
+}
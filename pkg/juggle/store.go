@@ -0,0 +1,57 @@
+package juggle
+
+import (
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// Store reads and writes a project's balls (.juggle/balls.jsonl and its
+// archive, unless opened on another backend)
+type Store = session.Store
+
+// BallStore is a backend a Store keeps its balls in; implement it to keep
+// balls somewhere other than the project's files
+type BallStore = session.BallStore
+
+// MemoryBallStore is a BallStore that keeps balls in memory, for tests
+type MemoryBallStore = session.MemoryBallStore
+
+// Session groups balls by tag and holds context shared with the agent
+type Session = session.JuggleSession
+
+// SessionStore reads and writes a project's sessions and their progress logs
+type SessionStore = session.SessionStore
+
+// ProjectConfig is a project's .juggle/config.json
+type ProjectConfig = session.ProjectConfig
+
+// OpenStore opens the ball store for a project, creating .juggle if needed.
+// In a linked worktree the main repository's store is used.
+func OpenStore(projectDir string) (*Store, error) {
+	return session.NewStore(projectDir)
+}
+
+// OpenStoreWithBackend opens a store for a project that keeps its balls in
+// the given backend
+func OpenStoreWithBackend(projectDir string, balls BallStore) *Store {
+	return session.NewStoreWithBackend(projectDir, balls)
+}
+
+// NewMemoryBallStore creates an empty in-memory backend
+func NewMemoryBallStore() *MemoryBallStore {
+	return session.NewMemoryBallStore()
+}
+
+// OpenSessionStore opens the session store for a project
+func OpenSessionStore(projectDir string) (*SessionStore, error) {
+	return session.NewSessionStore(projectDir)
+}
+
+// LoadProjectConfig reads a project's config, returning defaults when it has none
+func LoadProjectConfig(projectDir string) (*ProjectConfig, error) {
+	return session.LoadProjectConfig(projectDir)
+}
+
+// SaveProjectConfig writes a project's config
+func SaveProjectConfig(projectDir string, config *ProjectConfig) error {
+	return session.SaveProjectConfig(projectDir, config)
+}